@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	stdlog "log"
@@ -39,6 +40,7 @@ import (
 	discoveryv1 "k8s.io/api/discovery/v1"
 	eventsv1 "k8s.io/api/events/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -54,13 +56,17 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/httpsd"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
 	"github.com/prometheus-operator/prometheus-operator/pkg/kubelet"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	prometheusagentcontroller "github.com/prometheus-operator/prometheus-operator/pkg/prometheus/agent"
 	prometheuscontroller "github.com/prometheus-operator/prometheus-operator/pkg/prometheus/server"
+	"github.com/prometheus-operator/prometheus-operator/pkg/render"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
 	"github.com/prometheus-operator/prometheus-operator/pkg/server"
 	thanoscontroller "github.com/prometheus-operator/prometheus-operator/pkg/thanos"
+	"github.com/prometheus-operator/prometheus-operator/pkg/upgradecheck"
 	"github.com/prometheus-operator/prometheus-operator/pkg/versionutil"
 )
 
@@ -120,8 +126,18 @@ var (
 
 	serverConfig = server.DefaultConfig(":8080", false)
 
+	// Parameters for the HTTP service discovery server.
+	httpSDServerConfig = server.DefaultConfig("", false)
+	httpSDURL          string
+	httpSDTokenKeyFile string
+
 	disableUnmanagedPrometheusConfiguration bool
 
+	enableSelectionReport bool
+
+	// Name of the PrometheusOperatorDefaults object.
+	workloadDefaults string
+
 	// Parameters for the kubelet endpoints controller.
 	kubeletObject        string
 	kubeletSelector      operator.LabelSelector
@@ -138,6 +154,11 @@ func parseFlags(fs *flag.FlagSet) {
 	// Web server settings.
 	server.RegisterFlags(fs, &serverConfig)
 
+	// HTTP service discovery server settings.
+	server.RegisterFlagsWithPrefix(fs, &httpSDServerConfig, "http-sd", "HTTP service discovery server", "Address on which to expose the HTTP service discovery endpoint used by Prometheus and PrometheusAgent resources with 'operatorServiceDiscovery' defined. The endpoint is disabled when empty (default).")
+	fs.StringVar(&httpSDURL, "http-sd.url", "", "URL from which Prometheus and PrometheusAgent pods reach the HTTP service discovery endpoint of the operator (e.g. 'https://prometheus-operator.monitoring.svc:8443'). Required when --http-sd.listen-address is set.")
+	fs.StringVar(&httpSDTokenKeyFile, "http-sd.token-key-file", "", "File containing the key (at least 32 bytes) signing the bearer tokens which authenticate the requests to the HTTP service discovery endpoint. Each token only grants access to the targets of one Prometheus or PrometheusAgent resource. When empty, a random key is generated at startup and the tokens change when the operator restarts.")
+
 	// Kubernetes client-go settings.
 	fs.StringVar(&impersonateUser, "as", "", "Username to impersonate. User could be a regular user or a service account in a namespace.")
 	fs.StringVar(&apiServer, "apiserver", "", "API Server addr, e.g. ' - NOT RECOMMENDED FOR PRODUCTION - http://127.0.0.1:8080'. Omit parameter to run in on-cluster mode and utilize the service account token.")
@@ -170,6 +191,7 @@ func parseFlags(fs *flag.FlagSet) {
 	fs.StringVar(&cfg.ThanosDefaultBaseImage, "thanos-default-base-image", operator.DefaultThanosBaseImage, "Thanos default base image (path without tag/version)")
 	fs.StringVar(&cfg.ControllerID, "controller-id", "", "Value used by the operator to filter Alertmanager, Prometheus, PrometheusAgent and ThanosRuler objects that it should reconcile. If the value isn't empty, the operator only reconciles objects with an `operator.prometheus.io/controller-id` annotation of the same value. Otherwise the operator reconciles all objects without the annotation or with an empty annotation value.")
 	fs.Var(&cfg.RepairPolicy, "repair-policy-for-statefulsets", "Policy to use when a StatefulSet rollout is stuck. Possible values: 'none' (default), 'evict' or 'delete'.")
+	fs.Var(&cfg.DriftPolicy, "drift-policy", "Policy to use when fields of the managed objects are modified by other field managers. Possible values: 'revert' (default) to take back the ownership of the fields or 'report' to keep the changes (the managed objects aren't updated until the changes are removed).")
 
 	fs.Var(cfg.Namespaces.AllowList, "namespaces", "Namespaces to scope the interaction of the Prometheus Operator and the apiserver (allow list). This is mutually exclusive with --deny-namespaces.")
 	fs.Var(cfg.Namespaces.DenyList, "deny-namespaces", "Namespaces not to scope the interaction of the Prometheus Operator (deny list). This is mutually exclusive with --namespaces.")
@@ -179,6 +201,8 @@ func parseFlags(fs *flag.FlagSet) {
 	fs.Var(cfg.Namespaces.ThanosRulerAllowList, "thanos-ruler-instance-namespaces", "Namespaces where ThanosRuler custom resources and corresponding StatefulSets are watched/created. If set this takes precedence over --namespaces or --deny-namespaces for ThanosRuler custom resources.")
 	fs.BoolVar(&cfg.WatchObjectRefsInAllNamespaces, "watch-referenced-objects-in-all-namespaces", false, "When true the operator watches for configmaps and secrets in both workload and configuration resource namespaces.\nWhen false (default), the operator will only watch for secrets and configmaps in:\n* Workload namespaces for Prometheus and PrometheusAgent resources.\n* Configuration namespaces for Alertmanager resources.")
 
+	fs.StringVar(&workloadDefaults, "workload-defaults", "", "Name of the cluster-scoped PrometheusOperatorDefaults object providing the default values of the Alertmanager, Prometheus, PrometheusAgent and ThanosRuler objects. The default values apply to the fields which aren't defined by the objects. Disabled when empty (default).")
+
 	fs.Var(&cfg.Annotations, "annotations", "Annotations to be add to all resources created by the operator")
 	fs.Var(&cfg.Labels, "labels", "Labels to be add to all resources created by the operator")
 
@@ -195,6 +219,7 @@ func parseFlags(fs *flag.FlagSet) {
 
 	fs.Float64Var(&memlimitRatio, "auto-gomemlimit-ratio", defaultMemlimitRatio, "The ratio of reserved GOMEMLIMIT memory to the detected maximum container or system memory. The value should be greater than 0.0 and less than 1.0. Default: 0.0 (disabled).")
 	fs.BoolVar(&disableUnmanagedPrometheusConfiguration, "disable-unmanaged-prometheus-configuration", false, "Disable support for unmanaged Prometheus configuration when all resource selectors are nil. As stated in the API documentation, unmanaged Prometheus configuration is a deprecated feature which can be avoided with '.spec.additionalScrapeConfigs' or the ScrapeConfig CRD. Default: false.")
+	fs.BoolVar(&enableSelectionReport, "enable-selection-report", false, "Serve the selection report of each Prometheus and PrometheusAgent object at '/selection/<kind>/<namespace>/<name>' on the web server. The report isn't protected by authentication and exposes the selected resources of all watched namespaces: restrict the access to the web port when enabled. Default: false.")
 	cfg.RegisterFeatureGatesFlags(fs, featureGates)
 
 	logging.RegisterFlags(fs, &logConfig)
@@ -267,9 +292,17 @@ func run(fs *flag.FlagSet) int {
 		return crds()
 	case "full-crds":
 		return fullCrds()
+	case "render":
+		if fs.NArg() != 3 {
+			fmt.Fprintln(os.Stderr, "Usage: render <manifests directory> <output directory>")
+			return 1
+		}
+		return renderManifests(fs.Arg(1), fs.Arg(2))
+	case "check-upgrade":
+		return checkUpgrade(fs.Args()[1:])
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", cmd)
-		fmt.Fprintln(os.Stderr, "Available commands: crds, full-crds, start")
+		fmt.Fprintln(os.Stderr, "Available commands: check-upgrade, crds, full-crds, render, start")
 		return 1
 	}
 }
@@ -354,6 +387,38 @@ func start() int {
 		logger.Info("Disabling support for unmanaged Prometheus configurations")
 		promControllerOptions = append(promControllerOptions, prometheuscontroller.WithoutUnmanagedConfiguration())
 	}
+
+	var httpSDRegistry *httpsd.Registry
+	if httpSDServerConfig.ListenAddress != "" {
+		if httpSDURL == "" {
+			logger.Error("--http-sd.url must be set when --http-sd.listen-address is set")
+			cancel()
+			return 1
+		}
+
+		if !httpSDServerConfig.TLSConfig.Enabled {
+			logger.Warn("the HTTP service discovery endpoint is served without TLS, the bearer tokens are sent in clear text. Consider setting --http-sd.enable-tls.")
+		}
+
+		key, err := httpsd.LoadKey(httpSDTokenKeyFile)
+		if err != nil {
+			logger.Error("failed to load the HTTP service discovery token key", "err", err)
+			cancel()
+			return 1
+		}
+
+		httpSDRegistry = httpsd.NewRegistry(logger.With("component", "httpsd"), key)
+		promControllerOptions = append(promControllerOptions, prometheuscontroller.WithHTTPServiceDiscovery(httpSDRegistry, httpSDURL))
+		promAgentControllerOptions = append(promAgentControllerOptions, prometheusagentcontroller.WithHTTPServiceDiscovery(httpSDRegistry, httpSDURL))
+	}
+
+	var selectionReports *selectionreport.Registry
+	if enableSelectionReport {
+		selectionReports = selectionreport.NewRegistry(logger.With("component", "selectionreport"))
+		promControllerOptions = append(promControllerOptions, prometheuscontroller.WithSelectionReports(selectionReports))
+		promAgentControllerOptions = append(promAgentControllerOptions, prometheusagentcontroller.WithSelectionReports(selectionReports))
+	}
+
 	// Check if we can read the storage classs
 	canReadStorageClass, err := checkPrerequisites(
 		ctx,
@@ -425,6 +490,63 @@ func start() int {
 		promAgentControllerOptions = append(promAgentControllerOptions, prometheusagentcontroller.WithScrapeConfig())
 	}
 
+	monitoringQuotaSupported, err := checkPrerequisites(
+		ctx,
+		logger,
+		kclient,
+		cfg.Namespaces.AllowList.Slice(),
+		monitoringv1alpha1.SchemeGroupVersion,
+		monitoringv1alpha1.MonitoringQuotaName,
+		k8s.ResourceAttribute{
+			Group:    monitoring.GroupName,
+			Version:  monitoringv1alpha1.Version,
+			Resource: monitoringv1alpha1.MonitoringQuotaName,
+			Verbs:    []string{"get", "list", "watch"},
+		},
+	)
+	if err != nil {
+		logger.Error("failed to check MonitoringQuota support", "err", err)
+		cancel()
+		return 1
+	}
+	if monitoringQuotaSupported {
+		promControllerOptions = append(promControllerOptions, prometheuscontroller.WithMonitoringQuota())
+		promAgentControllerOptions = append(promAgentControllerOptions, prometheusagentcontroller.WithMonitoringQuota())
+		thanosControllerOptions = append(thanosControllerOptions, thanoscontroller.WithMonitoringQuota())
+	}
+
+	if workloadDefaults != "" {
+		workloadDefaultsSupported, err := checkPrerequisites(
+			ctx,
+			logger,
+			kclient,
+			nil,
+			monitoringv1alpha1.SchemeGroupVersion,
+			monitoringv1alpha1.PrometheusOperatorDefaultsName,
+			k8s.ResourceAttribute{
+				Group:    monitoring.GroupName,
+				Version:  monitoringv1alpha1.Version,
+				Resource: monitoringv1alpha1.PrometheusOperatorDefaultsName,
+				Verbs:    []string{"get", "list", "watch"},
+			},
+		)
+		if err != nil {
+			logger.Error("failed to check PrometheusOperatorDefaults support", "err", err)
+			cancel()
+			return 1
+		}
+		if !workloadDefaultsSupported {
+			logger.Error("--workload-defaults is set but the PrometheusOperatorDefaults resource isn't available")
+			cancel()
+			return 1
+		}
+
+		alertmanagerControllerOptions = append(alertmanagerControllerOptions, alertmanagercontroller.WithWorkloadDefaults(workloadDefaults))
+		promAgentControllerOptions = append(promAgentControllerOptions, prometheusagentcontroller.WithWorkloadDefaults(workloadDefaults))
+		promControllerOptions = append(promControllerOptions, prometheuscontroller.WithWorkloadDefaults(workloadDefaults))
+		thanosControllerOptions = append(thanosControllerOptions, thanoscontroller.WithWorkloadDefaults(workloadDefaults))
+	}
+
 	// EndpointSlice v1 became available with Kubernetes v1.21.0.
 	endpointSliceSupported := cfg.KubernetesVersion.GTE(semver.MustParse("1.21.0"))
 	logger.Info("Kubernetes API capabilities", "endpointslices", endpointSliceSupported)
@@ -743,6 +865,9 @@ func start() int {
 	mux := http.NewServeMux()
 	admit := admission.New(logger.With("component", "admissionwebhook"), model.LegacyValidation, parser.Options{})
 	admit.Register(mux)
+	if selectionReports != nil {
+		selectionReports.Register(mux)
+	}
 
 	r.MustRegister(cfg.Gates)
 
@@ -766,6 +891,22 @@ func start() int {
 	// Start the web server.
 	wg.Go(func() error { return srv.Serve(ctx) })
 
+	// Setup and start the HTTP service discovery server.
+	var httpSDSrv *server.Server
+	if httpSDRegistry != nil {
+		httpSDMux := http.NewServeMux()
+		httpSDRegistry.Register(httpSDMux)
+
+		httpSDSrv, err = server.NewServer(logger.With("component", "httpsd"), &httpSDServerConfig, httpSDMux)
+		if err != nil {
+			logger.Error("failed to create HTTP service discovery server", "err", err)
+			cancel()
+			return 1
+		}
+
+		wg.Go(func() error { return httpSDSrv.Serve(ctx) })
+	}
+
 	// Start the controllers.
 	if po != nil {
 		wg.Go(func() error { return po.Run(ctx) })
@@ -796,6 +937,12 @@ func start() int {
 		logger.Warn("server shutdown error", "err", err)
 	}
 
+	if httpSDSrv != nil {
+		if err := httpSDSrv.Shutdown(ctx); err != nil {
+			logger.Warn("HTTP service discovery server shutdown error", "err", err)
+		}
+	}
+
 	cancel()
 	if err := wg.Wait(); err != nil {
 		logger.Warn("unhandled error received. Exiting...", "err", err)
@@ -811,9 +958,11 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s [arguments] [<command>]\n\n", os.Args[0])
 		fmt.Fprintln(os.Stderr, "Commands:")
-		fmt.Fprintln(os.Stderr, "  start      Run the operator (default)")
-		fmt.Fprintln(os.Stderr, "  crds       Print the CRDs in YAML format to standard output")
-		fmt.Fprintln(os.Stderr, "  full-crds  Print the full CRDs (with all fields) in YAML format to standard output")
+		fmt.Fprintln(os.Stderr, "  start          Run the operator (default)")
+		fmt.Fprintln(os.Stderr, "  crds           Print the CRDs in YAML format to standard output")
+		fmt.Fprintln(os.Stderr, "  full-crds      Print the full CRDs (with all fields) in YAML format to standard output")
+		fmt.Fprintln(os.Stderr, "  render         Render the resources generated from the manifests of a directory (render <input> <output>)")
+		fmt.Fprintln(os.Stderr, "  check-upgrade  Report the impact of upgrading Prometheus and Alertmanager (check-upgrade -help for details)")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Arguments:")
 		fs.PrintDefaults()
@@ -821,6 +970,165 @@ func main() {
 	os.Exit(run(fs))
 }
 
+// renderManifests generates the resources managed by the operator from the
+// manifests in the input directory and writes them to the output directory
+// without connecting to a Kubernetes cluster.
+func renderManifests(input, output string) int {
+	logger, err := logging.NewLoggerSlog(logConfig)
+	if err != nil {
+		stdlog.Fatal(err)
+	}
+	klog.SetSlogLogger(logger)
+
+	if err := cfg.Gates.UpdateFeatureGates(*featureGates.Map); err != nil {
+		logger.Error("failed to update feature gates", "error", err)
+		return 1
+	}
+
+	if err := cfg.Namespaces.Finalize(); err != nil {
+		logger.Error("failed to parse namespaces configuration", "configuration", cfg.Namespaces.String(), "error", err)
+		return 1
+	}
+
+	objects, err := render.LoadManifests(logger, input)
+	if err != nil {
+		logger.Error("failed to load the manifests", "err", err)
+		return 1
+	}
+
+	var opts []render.Option
+	if workloadDefaults != "" {
+		opts = append(opts, render.WithWorkloadDefaults(workloadDefaults))
+	}
+
+	rendered, err := render.Render(context.Background(), logger, cfg, objects, opts...)
+	if err != nil {
+		logger.Error("failed to render the manifests", "err", err)
+		return 1
+	}
+
+	if err := render.WriteObjects(output, rendered); err != nil {
+		logger.Error("failed to write the rendered resources", "err", err)
+		return 1
+	}
+
+	logger.Info("resources rendered", "count", len(rendered), "output", output)
+	return 0
+}
+
+// checkUpgrade reports the impact of upgrading the Prometheus and
+// Alertmanager versions on the resources from a directory of manifests or
+// from the cluster.
+func checkUpgrade(args []string) int {
+	var (
+		fs                  = flag.NewFlagSet("check-upgrade", flag.ExitOnError)
+		prometheusVersion   string
+		alertmanagerVersion string
+		output              string
+		defaults            string
+	)
+	fs.StringVar(&prometheusVersion, "prometheus-version", "", "Target version for Prometheus and PrometheusAgent.")
+	fs.StringVar(&alertmanagerVersion, "alertmanager-version", "", "Target version for Alertmanager.")
+	fs.StringVar(&output, "output", "text", "Output format (text or json).")
+	fs.StringVar(&defaults, "workload-defaults", workloadDefaults, "Name of the cluster-scoped PrometheusOperatorDefaults object merged into the workloads (defaults to the value of the operator's --workload-defaults argument).")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [arguments] check-upgrade [check-upgrade arguments] [<manifests directory>]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Without manifests directory, the resources are read from the cluster.")
+		fmt.Fprintln(os.Stderr, "The command exits with code 2 when issues are found.")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Arguments:")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() > 1 || (output != "text" && output != "json") {
+		fs.Usage()
+		return 1
+	}
+
+	logger, err := logging.NewLoggerSlog(logConfig)
+	if err != nil {
+		stdlog.Fatal(err)
+	}
+	klog.SetSlogLogger(logger)
+
+	if err := cfg.Gates.UpdateFeatureGates(*featureGates.Map); err != nil {
+		logger.Error("failed to update feature gates", "error", err)
+		return 1
+	}
+
+	if err := cfg.Namespaces.Finalize(); err != nil {
+		logger.Error("failed to parse namespaces configuration", "configuration", cfg.Namespaces.String(), "error", err)
+		return 1
+	}
+
+	ctx := context.Background()
+
+	var objects []runtime.Object
+	if fs.NArg() == 1 {
+		objects, err = render.LoadManifests(logger, fs.Arg(0))
+		if err != nil {
+			logger.Error("failed to load the manifests", "err", err)
+			return 1
+		}
+	} else {
+		restConfig, err := k8s.NewClusterConfig(k8s.ClusterConfig{
+			Host:      apiServer,
+			TLSConfig: tlsClientConfig,
+			AsUser:    impersonateUser,
+		})
+		if err != nil {
+			logger.Error("failed to create Kubernetes client configuration", "err", err)
+			return 1
+		}
+
+		clients, err := operator.NewClients(restConfig)
+		if err != nil {
+			logger.Error("failed to create the clients", "err", err)
+			return 1
+		}
+
+		objects, err = render.LoadFromCluster(ctx, clients)
+		if err != nil {
+			logger.Error("failed to load the resources from the cluster", "err", err)
+			return 1
+		}
+	}
+
+	findings, err := upgradecheck.Check(ctx, cfg, objects, upgradecheck.Options{
+		PrometheusVersion:   prometheusVersion,
+		AlertmanagerVersion: alertmanagerVersion,
+		WorkloadDefaults:    defaults,
+	})
+	if err != nil {
+		logger.Error("failed to check the upgrade", "err", err)
+		return 1
+	}
+
+	if output == "json" {
+		if findings == nil {
+			findings = []upgradecheck.Finding{}
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			logger.Error("failed to encode the findings", "err", err)
+			return 1
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	}
+
+	if len(findings) > 0 {
+		return 2
+	}
+
+	return 0
+}
+
 // crds prints all embedded CRDs to stdout.
 func crds() int {
 	if err := crd.PrintAll(os.Stdout); err != nil {
@@ -11085,6 +11085,15 @@
                       ],
                       type: 'string',
                     },
+                    shards: {
+                      description: "shards defines the shards of the workload evaluating the rules of the\nconfiguration resource.\nIt is only set for PrometheusRule resources when the Prometheus\nworkload doesn't evaluate all the rules on every shard (see\n`spec.ruleEvaluation`).",
+                      items: {
+                        format: 'int32',
+                        type: 'integer',
+                      },
+                      type: 'array',
+                      'x-kubernetes-list-type': 'set',
+                    },
                   },
                   required: [
                     'group',
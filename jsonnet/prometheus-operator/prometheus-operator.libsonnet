@@ -11,6 +11,7 @@ local defaults = {
   },
   enableReloaderProbes: false,
   repairPolicy: '',  // can be 'none' (default), 'delete' or 'evict'
+  workloadDefaults: '',  // name of the PrometheusOperatorDefaults object
   goGC: '30',
   port: 8080,
   resources: {
@@ -51,6 +52,8 @@ function(params) {
   '0prometheusruleCustomResourceDefinition': import 'prometheusrules-crd.json',
   '0thanosrulerCustomResourceDefinition': import 'thanosrulers-crd.json',
   '0scrapeconfigCustomResourceDefinition': import 'scrapeconfigs-crd.json',
+  '0monitoringquotaCustomResourceDefinition': import 'monitoringquotas-crd.json',
+  '0prometheusoperatordefaultsCustomResourceDefinition': import 'prometheusoperatordefaults-crd.json',
 
   clusterRoleBinding: {
     apiVersion: 'rbac.authorization.k8s.io/v1',
@@ -87,6 +90,7 @@ function(params) {
                  'prometheusagents',
                  'prometheusagents/finalizers',
                  'prometheuses',
+                 'prometheusoperatordefaults',
                  'prometheuses/finalizers',
                  'thanosrulers',
                  'thanosrulers/finalizers',
@@ -113,10 +117,12 @@ function(params) {
                resources: [
                  'alertmanagers',
                  'alertmanagerconfigs',
+                 'monitoringquotas',
                  'podmonitors',
                  'probes',
                  'prometheusagents',
                  'prometheuses',
+                 'prometheusoperatordefaults',
                  'prometheusrules',
                  'servicemonitors',
                  'scrapeconfigs',
@@ -132,12 +138,42 @@ function(params) {
              {
                apiGroups: [''],
                resources: ['configmaps', 'secrets'],
-               verbs: ['get', 'list', 'watch', 'create', 'update', 'delete'],
+               verbs: ['get', 'list', 'watch', 'create', 'update', 'patch', 'delete'],
              },
              {
                apiGroups: [''],
                resources: ['pods'],
-               verbs: ['list', 'delete'],
+               verbs: ['get', 'list', 'patch', 'delete'],
+             },
+             {
+               apiGroups: [''],
+               resources: ['persistentvolumeclaims'],
+               verbs: ['get', 'list', 'create', 'patch', 'delete'],
+             },
+             {
+               apiGroups: [''],
+               resources: ['persistentvolumes'],
+               verbs: ['list', 'get', 'patch'],
+             },
+             {
+               apiGroups: ['batch'],
+               resources: ['jobs'],
+               verbs: ['get', 'create', 'delete'],
+             },
+             {
+               apiGroups: ['policy'],
+               resources: ['poddisruptionbudgets'],
+               verbs: ['get', 'list', 'create', 'update', 'patch', 'delete'],
+             },
+             {
+               apiGroups: ['networking.k8s.io'],
+               resources: ['networkpolicies'],
+               verbs: ['get', 'create', 'update', 'patch', 'delete'],
+             },
+             {
+               apiGroups: ['gateway.networking.k8s.io'],
+               resources: ['httproutes', 'backendtlspolicies'],
+               verbs: ['get', 'list', 'create', 'update', 'patch', 'delete'],
              },
              {
                apiGroups: [''],
@@ -145,7 +181,7 @@ function(params) {
                  'services',
                  'services/finalizers',
                ],
-               verbs: ['get', 'create', 'update', 'delete'],
+               verbs: ['get', 'list', 'create', 'update', 'patch', 'delete'],
              },
              {
                apiGroups: [''],
@@ -165,7 +201,7 @@ function(params) {
              {
                apiGroups: ['networking.k8s.io'],
                resources: ['ingresses'],
-               verbs: ['get', 'list', 'watch'],
+               verbs: ['get', 'list', 'watch', 'create', 'update', 'patch', 'delete'],
              },
              {
                apiGroups: ['storage.k8s.io'],
@@ -180,7 +216,7 @@ function(params) {
                    resources: [
                      'endpoints',
                    ],
-                   verbs: ['get', 'create', 'update', 'delete'],
+                   verbs: ['get', 'create', 'update', 'patch', 'delete'],
                  },
                ]
              else
@@ -194,7 +230,7 @@ function(params) {
                    resources: [
                      'endpointslices',
                    ],
-                   verbs: ['get', 'create', 'list', 'update', 'delete'],
+                   verbs: ['get', 'create', 'list', 'update', 'patch', 'delete'],
                  },
                ]
              else
@@ -238,7 +274,8 @@ function(params) {
             optionalArg('--config-reloader-cpu-request', po.config.configReloaderResources.requests.cpu) +
             optionalArg('--config-reloader-memory-request', po.config.configReloaderResources.requests.memory) +
             enableReloaderProbesArg(po.config.enableReloaderProbes) +
-            optionalArg('--repair-policy-for-statefulsets', po.config.repairPolicy),
+            optionalArg('--repair-policy-for-statefulsets', po.config.repairPolicy) +
+            optionalArg('--workload-defaults', po.config.workloadDefaults),
       ports: [{
         containerPort: po.config.port,
         name: 'http',
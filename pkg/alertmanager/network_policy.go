@@ -0,0 +1,48 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"context"
+	"strings"
+
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// addNetworkPolicyEgress allows the Alertmanager pods to reach the in-cluster
+// webhook receivers of the selected AlertmanagerConfig resources.
+func addNetworkPolicyEgress(ctx context.Context, np *operator.NetworkPolicyBuilder, amConfigs map[string]*monitoringv1alpha1.AlertmanagerConfig, store *assets.StoreBuilder) {
+	for _, amc := range amConfigs {
+		for _, receiver := range amc.Spec.Receivers {
+			for _, wc := range receiver.WebhookConfigs {
+				if wc.URLSecret != nil {
+					// The secret has already been validated when selecting
+					// the AlertmanagerConfig resources.
+					url, err := store.GetSecretKey(ctx, amc.Namespace, *wc.URLSecret)
+					if err == nil {
+						np.AddAddresses(strings.TrimSpace(url))
+					}
+					continue
+				}
+
+				if wc.URL != nil {
+					np.AddAddresses(*wc.URL)
+				}
+			}
+		}
+	}
+}
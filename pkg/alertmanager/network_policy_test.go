@@ -0,0 +1,91 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+func TestAddNetworkPolicyEgress(t *testing.T) {
+	kclient := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "webhook",
+			Namespace: "team-b",
+		},
+		Data: map[string][]byte{
+			"url": []byte("http://receiver.team-c.svc:8080/alerts\n"),
+		},
+	})
+	store := assets.NewStoreBuilder(kclient.CoreV1(), kclient.CoreV1())
+
+	amConfigs := map[string]*monitoringv1alpha1.AlertmanagerConfig{
+		"team-a/config": {
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "team-a"},
+			Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+				Receivers: []monitoringv1alpha1.Receiver{
+					{
+						Name: "in-cluster",
+						WebhookConfigs: []monitoringv1alpha1.WebhookConfig{
+							{URL: ptr.To("http://receiver.team-a.svc:8080/alerts")},
+							{URL: ptr.To("https://receiver.example.com/alerts")},
+						},
+					},
+				},
+			},
+		},
+		"team-b/config": {
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "team-b"},
+			Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+				Receivers: []monitoringv1alpha1.Receiver{
+					{
+						Name: "secret",
+						WebhookConfigs: []monitoringv1alpha1.WebhookConfig{
+							{
+								URLSecret: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"},
+									Key:                  "url",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b := operator.NewNetworkPolicyBuilder("monitoring", map[string]string{"alertmanager": "test"})
+	addNetworkPolicyEgress(context.Background(), b, amConfigs, store)
+
+	np := b.Build(context.Background(), slog.New(slog.DiscardHandler), kclient, monitoringv1.NetworkPolicySpec{})
+
+	// The last rule allows the namespaces of the in-cluster receivers.
+	rule := np.Spec.Egress[len(np.Spec.Egress)-1]
+	require.Len(t, rule.To, 1)
+	require.NotNil(t, rule.To[0].NamespaceSelector)
+	require.Equal(t, []string{"team-a", "team-c"}, rule.To[0].NamespaceSelector.MatchExpressions[0].Values)
+}
@@ -16,6 +16,7 @@ package alertmanager
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -31,9 +32,11 @@ import (
 	"github.com/prometheus/common/model"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	typedauthv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/client-go/metadata"
@@ -81,11 +84,13 @@ type Config struct {
 // configurations.
 type Operator struct {
 	kclient    kubernetes.Interface
+	dclient    dynamic.Interface
 	mdClient   metadata.Interface
 	mclient    monitoringclient.Interface
 	ssarClient typedauthv1.SelfSubjectAccessReviewInterface
 
 	controllerID string
+	driftPolicy  operator.DriftPolicy
 	repairPolicy operator.RepairPolicy
 
 	logger   *slog.Logger
@@ -100,6 +105,11 @@ type Operator struct {
 	secrInfs    *informers.ForResource
 	ssetInfs    *informers.ForResource
 
+	// defaultsInfs is nil when no PrometheusOperatorDefaults object is
+	// configured.
+	defaultsInfs     *informers.ForResource
+	workloadDefaults string
+
 	rr *operator.ResourceReconciler
 
 	metrics         *operator.Metrics
@@ -132,30 +142,43 @@ func WithConfigResourceStatus() ControllerOption {
 	}
 }
 
+// WithWorkloadDefaults tells that the controller merges the default values
+// from the PrometheusOperatorDefaults object with the given name into the
+// Alertmanager objects.
+func WithWorkloadDefaults(name string) ControllerOption {
+	return func(o *Operator) {
+		o.workloadDefaults = name
+	}
+}
+
 // New creates a new controller.
 func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
-	logger = logger.With("component", controllerName)
-
-	client, err := kubernetes.NewForConfig(restConfig)
+	clients, err := operator.NewClients(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("instantiating kubernetes client failed: %w", err)
+		return nil, err
 	}
 
-	mdClient, err := metadata.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating kubernetes client failed: %w", err)
-	}
+	return NewForClients(ctx, clients, c, logger, r, options...)
+}
 
-	mclient, err := monitoringclient.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating monitoring client failed: %w", err)
-	}
+// NewForClients creates a new controller which interacts with the
+// Kubernetes API using the given clients.
+func NewForClients(ctx context.Context, clients *operator.Clients, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
+	logger = logger.With("component", controllerName)
+
+	var (
+		client   = clients.Kubernetes
+		dclient  = clients.Dynamic
+		mdClient = clients.Metadata
+		mclient  = clients.Monitoring
+	)
 
 	// All the metrics exposed by the controller get the controller="alertmanager" label.
 	r = prometheus.WrapRegistererWith(prometheus.Labels{"controller": "alertmanager"}, r)
 
 	o := &Operator{
 		kclient:    client,
+		dclient:    dclient,
 		mdClient:   mdClient,
 		mclient:    mclient,
 		ssarClient: client.AuthorizationV1().SelfSubjectAccessReviews(),
@@ -168,6 +191,7 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		newEventRecorder: c.EventRecorderFactory(client, controllerName),
 
 		controllerID: c.ControllerID,
+		driftPolicy:  c.DriftPolicy,
 		repairPolicy: c.RepairPolicy,
 
 		config: Config{
@@ -301,6 +325,13 @@ func (c *Operator) bootstrap(ctx context.Context, config operator.Config) error
 		return fmt.Errorf("error creating statefulset informers: %w", err)
 	}
 
+	if c.workloadDefaults != "" {
+		c.defaultsInfs, err = operator.NewWorkloadDefaultsInformers(c.mclient, c.workloadDefaults, resyncPeriod)
+		if err != nil {
+			return fmt.Errorf("error creating prometheusoperatordefaults informers: %w", err)
+		}
+	}
+
 	newNamespaceInformer := func(o *Operator, allowList map[string]struct{}) (cache.SharedIndexInformer, error) {
 		lw, privileged, err := listwatch.NewNamespaceListWatchFromClient(
 			ctx,
@@ -351,7 +382,14 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 		{"Secret", c.secrInfs},
 		{"ConfigMap", c.cmapInfs},
 		{"StatefulSet", c.ssetInfs},
+		{"PrometheusOperatorDefaults", c.defaultsInfs},
 	} {
+		// The PrometheusOperatorDefaults informer is nil when no object is
+		// configured.
+		if infs.informersForResource == nil {
+			continue
+		}
+
 		for _, inf := range infs.informersForResource.GetInformers() {
 			if !operator.WaitForNamedCacheSync(ctx, "alertmanager", c.logger.With("informer", infs.name), inf.Informer()) {
 				return fmt.Errorf("failed to sync cache for %s informer", infs.name)
@@ -426,6 +464,17 @@ func (c *Operator) addHandlers() {
 		operator.WithFilter(hasRefFunc),
 	))
 
+	if c.defaultsInfs != nil {
+		c.defaultsInfs.AddEventHandler(operator.NewEventHandler(
+			c.logger,
+			c.accessor,
+			c.metrics,
+			monitoringv1alpha1.PrometheusOperatorDefaultsKind,
+			c.enqueueAll,
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
 	// The controller needs to watch the namespaces in which the
 	// alertmanagerconfigs live because a label change on a namespace may
 	// trigger a configuration change.
@@ -491,22 +540,31 @@ func (c *Operator) enqueueForNamespace(gbk operator.GetByKeyer, nsName string) {
 	}
 }
 
-// Run the controller.
-func (c *Operator) Run(ctx context.Context) error {
-	go c.rr.Run(ctx)
-	defer c.rr.Stop()
-
+// StartInformers starts the informers of the controller and waits for
+// their caches to be synced.
+func (c *Operator) StartInformers(ctx context.Context) error {
 	go c.alrtInfs.Start(ctx.Done())
 	go c.alrtCfgInfs.Start(ctx.Done())
 	go c.secrInfs.Start(ctx.Done())
 	go c.cmapInfs.Start(ctx.Done())
 	go c.ssetInfs.Start(ctx.Done())
+	if c.defaultsInfs != nil {
+		go c.defaultsInfs.Start(ctx.Done())
+	}
 	go c.nsAlrtCfgInf.Run(ctx.Done())
 	if c.nsAlrtInf != c.nsAlrtCfgInf {
 		go c.nsAlrtInf.Run(ctx.Done())
 	}
 
-	if err := c.waitForCacheSync(ctx); err != nil {
+	return c.waitForCacheSync(ctx)
+}
+
+// Run the controller.
+func (c *Operator) Run(ctx context.Context) error {
+	go c.rr.Run(ctx)
+	defer c.rr.Stop()
+
+	if err := c.StartInformers(ctx); err != nil {
 		return err
 	}
 
@@ -586,6 +644,19 @@ func (c *Operator) handleNamespaceUpdate(oldo, curo any) {
 	}
 }
 
+// enqueueAll enqueues all the Alertmanager objects.
+func (c *Operator) enqueueAll(string) {
+	err := c.alrtInfs.ListAll(labels.Everything(), func(obj any) {
+		c.rr.EnqueueForReconciliation(obj.(*monitoringv1.Alertmanager))
+	})
+	if err != nil {
+		c.logger.Error(
+			"listing all Alertmanager instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
 // Sync implements the operator.Syncer interface.
 func (c *Operator) Sync(ctx context.Context, key string) error {
 	c.reconciliations.ResetStatus(key)
@@ -621,6 +692,25 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return nil
 	}
 
+	ctx = operator.WithDriftReporting(ctx, logger, c.metrics, c.newEventRecorder(am), c.driftPolicy)
+
+	if err := k8s.ValidateOverlays(am.Spec.Overlays); err != nil {
+		return err
+	}
+
+	defaults, err := operator.GetWorkloadDefaults(c.defaultsInfs, c.workloadDefaults)
+	if err != nil {
+		return err
+	}
+	defaulted := operator.ApplyAlertmanagerDefaults(am, defaults)
+	if len(defaulted) > 0 {
+		logger.Debug("default values applied", "fields", defaulted)
+	}
+	defaultedValues, err := operator.DefaultedWorkloadValues(am.Spec.Resources, am.Spec.SecurityContext, am.Spec.Tolerations, am.Spec.PriorityClassName, defaulted)
+	if err != nil {
+		return err
+	}
+
 	c.recordDeprecatedFields(key, logger, am)
 
 	if err := operator.CheckStorageClass(ctx, c.canReadStorageClass, c.kclient, am.Spec.Storage); err != nil {
@@ -628,13 +718,14 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	}
 
 	assetStore := assets.NewStoreBuilder(c.kclient.CoreV1(), c.kclient.CoreV1())
+	np := operator.NewNetworkPolicyBuilder(am.Namespace, makeSelectorLabels(am.Name))
 
-	if err := c.provisionAlertmanagerConfiguration(ctx, am, assetStore); err != nil {
+	if err := c.provisionAlertmanagerConfiguration(ctx, am, assetStore, np); err != nil {
 		return fmt.Errorf("provision alertmanager configuration: %w", err)
 	}
 	c.reconciliations.UpdateReferenceTracker(key, assetStore.RefTracker())
 
-	tlsShardedSecret, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), c.kclient, c.newTLSAssetSecret(am))
+	tlsShardedSecret, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), c.kclient, c.newTLSAssetSecret(am), am.Spec.Overlays)
 	if err != nil {
 		return fmt.Errorf("failed to reconcile the TLS secrets: %w", err)
 	}
@@ -657,9 +748,8 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 			return err
 		}
 	} else {
-		// Create governing service if it doesn't exist.
-		if _, err = k8s.CreateOrUpdateService(ctx, svcClient, makeStatefulSetService(am, c.config)); err != nil {
-			return fmt.Errorf("synchronizing governing service failed: %w", err)
+		if err := c.createOrUpdateGoverningService(ctx, am); err != nil {
+			return err
 		}
 	}
 
@@ -678,7 +768,7 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return nil
 	}
 
-	newSSetInputHash, err := createSSetInputHash(*am, c.config, tlsShardedSecret, existingStatefulSet.Spec)
+	newSSetInputHash, err := createSSetInputHash(*am, defaultedValues, c.config, tlsShardedSecret, existingStatefulSet.Spec)
 	if err != nil {
 		return err
 	}
@@ -689,6 +779,33 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	}
 	operator.SanitizeSTS(sset)
 
+	if err := k8s.ApplyOverlays(am.Spec.Overlays, sset); err != nil {
+		return err
+	}
+
+	var pdbs []*policyv1.PodDisruptionBudget
+	if am.Spec.PodDisruptionBudget != nil {
+		pdbs = append(pdbs, operator.MakePodDisruptionBudget(*am.Spec.PodDisruptionBudget, sset))
+	}
+
+	if err := operator.ReconcilePodDisruptionBudgets(ctx, c.kclient, am, labels.SelectorFromSet(sset.Spec.Selector.MatchLabels), pdbs); err != nil {
+		return err
+	}
+
+	if err := c.reconcileNetworkPolicy(ctx, logger, am, sset, np); err != nil {
+		return err
+	}
+
+	if err := c.reconcileExposure(ctx, logger, am, sset); err != nil {
+		return fmt.Errorf("failed to reconcile the exposure: %w", err)
+	}
+
+	if operator.UpdateStorageResizedCondition(ctx, c.kclient, c.reconciliations, key, []*appsv1.StatefulSet{sset}, am.Generation) {
+		logger.Debug("persistent volume claims resize in progress")
+		c.rr.EnqueueForReconciliationAfter(am, operator.StorageResizeRequeueInterval)
+		c.rr.EnqueueForStatus(am)
+	}
+
 	if newSSetInputHash == existingStatefulSet.Annotations[operator.InputHashAnnotationKey] {
 		logger.Debug("new statefulset generation inputs match current, skipping any actions")
 		return nil
@@ -704,6 +821,20 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return nil
 	}
 
+	expand, err := operator.ExpandPersistentVolumeClaims(ctx, c.kclient, c.canReadStorageClass, existingStatefulSet, sset)
+	if err != nil {
+		return err
+	}
+
+	if expand {
+		c.metrics.StsDeleteCreateCounter().Inc()
+		logger.Info("recreating StatefulSet because the volume claim templates have been expanded")
+		if err := k8s.OrphanDeleteStatefulSet(ctx, ssetClient, sset.Name); err != nil {
+			return fmt.Errorf("failed to delete statefulset: %w", err)
+		}
+		return nil
+	}
+
 	if err = k8s.ForceUpdateStatefulSet(ctx, ssetClient, sset, func(reason string) {
 		c.metrics.StsDeleteCreateCounter().Inc()
 		logger.Info("recreating StatefulSet because the update operation wasn't possible", "reason", reason)
@@ -772,7 +903,11 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 	a.Status.Selector = selector.String()
 	availableCondition := stsReporter.Update(a)
 	reconciledCondition := c.reconciliations.GetCondition(key, a.Generation)
-	a.Status.Conditions = operator.UpdateConditions(a.Status.Conditions, availableCondition, reconciledCondition)
+	conditions := []monitoringv1.Condition{availableCondition, reconciledCondition}
+	if cond := c.reconciliations.GetStorageResizedCondition(key); cond != nil {
+		conditions = append(conditions, *cond)
+	}
+	a.Status.Conditions = operator.UpdateConditions(a.Status.Conditions, conditions...)
 	a.Status.Paused = a.Spec.Paused
 
 	if availableCondition.Status != monitoringv1.ConditionTrue {
@@ -792,6 +927,70 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 	return nil
 }
 
+// reconcileNetworkPolicy creates or updates the NetworkPolicy of the
+// Alertmanager pods when it is defined and deletes it otherwise.
+func (c *Operator) reconcileNetworkPolicy(ctx context.Context, logger *slog.Logger, am *monitoringv1.Alertmanager, sset *appsv1.StatefulSet, np *operator.NetworkPolicyBuilder) error {
+	name := prefixedName(am.Name)
+	if am.Spec.NetworkPolicy == nil {
+		return operator.ReconcileNetworkPolicy(ctx, c.kclient, am.Namespace, name, nil)
+	}
+
+	np.AddPodSpec(&sset.Spec.Template.Spec)
+	// The Alertmanager peers gossip over TCP and UDP.
+	np.AddPeerPort(corev1.ProtocolTCP, alertmanagerMeshPort)
+	np.AddPeerPort(corev1.ProtocolUDP, alertmanagerMeshPort)
+	np.AddAddresses(am.Spec.AdditionalPeers...)
+
+	return operator.ReconcileNetworkPolicy(
+		ctx,
+		c.kclient,
+		am.Namespace,
+		name,
+		np.Build(
+			ctx,
+			logger,
+			c.kclient,
+			*am.Spec.NetworkPolicy,
+			operator.WithName(name),
+			operator.WithNamespace(am.Namespace),
+			operator.WithLabels(c.config.Labels),
+			operator.WithAnnotations(c.config.Annotations),
+			operator.WithManagingOwner(am),
+		),
+	)
+}
+
+// reconcileExposure creates or updates the objects exposing the Alertmanager
+// web server when the exposure is defined and deletes them otherwise.
+func (c *Operator) reconcileExposure(ctx context.Context, logger *slog.Logger, am *monitoringv1.Alertmanager, sset *appsv1.StatefulSet) error {
+	var exposure *operator.Exposure
+	if am.Spec.Exposure != nil {
+		exposure = operator.NewExposure(*am.Spec.Exposure, operator.ExposureConfig{
+			Name:        prefixedName(am.Name),
+			PodSelector: makeSelectorLabels(am.Name),
+			PortName:    cmp.Or(am.Spec.PortName, defaultPortName),
+			Port:        alertmanagerWebPort,
+			RoutePrefix: am.Spec.RoutePrefix,
+			WebTLS:      am.Spec.Web != nil && am.Spec.Web.TLSConfig != nil,
+			Overlays:    am.Spec.Overlays,
+		})
+		exposure.AddStatefulSet(sset)
+	}
+
+	return operator.ReconcileExposure(
+		ctx,
+		logger,
+		c.kclient,
+		c.dclient,
+		am,
+		prefixedName(am.Name),
+		exposure,
+		operator.WithLabels(c.config.Labels),
+		operator.WithAnnotations(c.config.Annotations),
+		operator.WithManagingOwner(am),
+	)
+}
+
 // makeSelectorLabels returns the default selector for the pods of the Alertmanager statefulset.
 func makeSelectorLabels(name string) map[string]string {
 	return map[string]string{
@@ -812,7 +1011,7 @@ func labelSelectorForStatefulSets() string {
 	)
 }
 
-func createSSetInputHash(a monitoringv1.Alertmanager, c Config, tlsAssets *operator.ShardedSecret, s appsv1.StatefulSetSpec) (string, error) {
+func createSSetInputHash(a monitoringv1.Alertmanager, defaultedValues string, c Config, tlsAssets *operator.ShardedSecret, s appsv1.StatefulSetSpec) (string, error) {
 	var http2 *bool
 	if a.Spec.Web != nil && a.Spec.Web.HTTPConfig != nil {
 		http2 = a.Spec.Web.HTTPConfig.HTTP2
@@ -824,21 +1023,25 @@ func createSSetInputHash(a monitoringv1.Alertmanager, c Config, tlsAssets *opera
 	s.RevisionHistoryLimit = nil
 
 	hash, err := hashstructure.Hash(struct {
-		AlertmanagerLabels      map[string]string
-		AlertmanagerAnnotations map[string]string
-		AlertmanagerGeneration  int64
-		AlertmanagerWebHTTP2    *bool
-		Config                  Config
-		StatefulSetSpec         appsv1.StatefulSetSpec
-		ShardedSecret           *operator.ShardedSecret
+		AlertmanagerLabels          map[string]string
+		AlertmanagerAnnotations     map[string]string
+		AlertmanagerGeneration      int64
+		AlertmanagerWebHTTP2        *bool
+		AlertmanagerOverlays        []monitoringv1.Overlay
+		AlertmanagerDefaultedValues string
+		Config                      Config
+		StatefulSetSpec             appsv1.StatefulSetSpec
+		ShardedSecret               *operator.ShardedSecret
 	}{
-		AlertmanagerLabels:      a.Labels,
-		AlertmanagerAnnotations: a.Annotations,
-		AlertmanagerGeneration:  a.Generation,
-		AlertmanagerWebHTTP2:    http2,
-		Config:                  c,
-		StatefulSetSpec:         s,
-		ShardedSecret:           tlsAssets,
+		AlertmanagerLabels:          a.Labels,
+		AlertmanagerAnnotations:     a.Annotations,
+		AlertmanagerGeneration:      a.Generation,
+		AlertmanagerWebHTTP2:        http2,
+		AlertmanagerOverlays:        a.Spec.Overlays,
+		AlertmanagerDefaultedValues: defaultedValues,
+		Config:                      c,
+		StatefulSetSpec:             s,
+		ShardedSecret:               tlsAssets,
 	},
 		nil,
 	)
@@ -891,7 +1094,7 @@ func (c *Operator) loadConfigurationFromSecret(ctx context.Context, am *monitori
 	return rawAlertmanagerConfig, secret.Data, nil
 }
 
-func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.StoreBuilder) error {
+func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.StoreBuilder, np *operator.NetworkPolicyBuilder) error {
 	amVersion := operator.StringValOrDefault(am.Spec.Version, operator.DefaultAlertmanagerVersion)
 	version, err := semver.ParseTolerant(amVersion)
 	if err != nil {
@@ -926,6 +1129,7 @@ func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *m
 	if err != nil {
 		return fmt.Errorf("failed to select AlertmanagerConfig objects: %w", err)
 	}
+	addNetworkPolicyEgress(ctx, np, amConfigs, store)
 
 	var (
 		additionalData map[string][]byte
@@ -944,6 +1148,7 @@ func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *m
 		if err != nil {
 			return fmt.Errorf("failed to initialize from global AlertmanagerConfig: %w", err)
 		}
+		addNetworkPolicyEgress(ctx, np, map[string]*monitoringv1alpha1.AlertmanagerConfig{am.Namespace + "/" + globalAmConfig.Name: globalAmConfig}, store)
 
 		for _, v := range am.Spec.AlertmanagerConfiguration.Templates {
 			if v.ConfigMap != nil {
@@ -1009,6 +1214,10 @@ func (c *Operator) createOrUpdateGeneratedConfigSecret(ctx context.Context, am *
 	}
 	generatedConfigSecret.Data[alertmanagerConfigFileCompressed] = buf.Bytes()
 
+	if err := k8s.ApplyOverlays(am.Spec.Overlays, generatedConfigSecret); err != nil {
+		return err
+	}
+
 	sClient := c.kclient.CoreV1().Secrets(am.Namespace)
 	err := k8s.CreateOrUpdateSecret(ctx, sClient, generatedConfigSecret)
 	if err != nil {
@@ -1895,13 +2104,25 @@ func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, a *monitor
 		operator.WithManagingOwner(a),
 	)
 
-	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, c.kclient.CoreV1().Secrets(a.Namespace), s); err != nil {
+	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, c.kclient.CoreV1().Secrets(a.Namespace), s, a.Spec.Overlays); err != nil {
 		return fmt.Errorf("failed to reconcile web config secret: %w", err)
 	}
 
 	return nil
 }
 
+// createOrUpdateGoverningService reconciles the default governing service.
+// The service is shared by all the Alertmanager resources of the namespace
+// hence the overlays don't apply to it.
+func (c *Operator) createOrUpdateGoverningService(ctx context.Context, am *monitoringv1.Alertmanager) error {
+	svcClient := c.kclient.CoreV1().Services(am.Namespace)
+	if _, err := k8s.CreateOrUpdateService(ctx, svcClient, makeStatefulSetService(am, c.config)); err != nil {
+		return fmt.Errorf("synchronizing governing service failed: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Operator) createOrUpdateClusterTLSConfigSecret(ctx context.Context, a *monitoringv1.Alertmanager) error {
 	clusterTLSConfig, err := clustertlsconfig.New(clusterTLSConfigDir, a)
 	if err != nil {
@@ -1928,6 +2149,10 @@ func (c *Operator) createOrUpdateClusterTLSConfigSecret(ctx context.Context, a *
 		operator.WithManagingOwner(a),
 	)
 
+	if err := k8s.ApplyOverlays(a.Spec.Overlays, s); err != nil {
+		return err
+	}
+
 	if err = k8s.CreateOrUpdateSecret(ctx, c.kclient.CoreV1().Secrets(a.Namespace), s); err != nil {
 		return fmt.Errorf("failed to reconcile secret: %w", err)
 	}
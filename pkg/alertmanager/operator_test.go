@@ -168,10 +168,10 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			a1Hash, err := createSSetInputHash(tc.a, Config{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{})
+			a1Hash, err := createSSetInputHash(tc.a, "", Config{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{})
 			require.NoError(t, err)
 
-			a2Hash, err := createSSetInputHash(tc.b, Config{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{})
+			a2Hash, err := createSSetInputHash(tc.b, "", Config{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{})
 			require.NoError(t, err)
 
 			if !tc.equal {
@@ -181,7 +181,7 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 
 			require.Equal(t, a1Hash, a2Hash, "expected two Alertmanager CRDs to produce the same hash but got different hash")
 
-			a2Hash, err = createSSetInputHash(tc.a, Config{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{Replicas: new(int32(2))})
+			a2Hash, err = createSSetInputHash(tc.a, "", Config{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{Replicas: new(int32(2))})
 			require.NoError(t, err)
 
 			require.NotEqual(t, a1Hash, a2Hash, "expected same Alertmanager CRDs with different statefulset specs to produce different hashes but got equal hash")
@@ -2243,7 +2243,7 @@ func TestProvisionAlertmanagerConfiguration(t *testing.T) {
 			require.NoError(t, err)
 
 			store := assets.NewStoreBuilder(c.CoreV1(), c.CoreV1())
-			err = o.provisionAlertmanagerConfiguration(context.Background(), tc.am, store)
+			err = o.provisionAlertmanagerConfiguration(context.Background(), tc.am, store, operator.NewNetworkPolicyBuilder(tc.am.Namespace, nil))
 
 			if !tc.ok {
 				require.Error(t, err)
@@ -2276,3 +2276,49 @@ func (*alwaysAllowed) Create(_ context.Context, _ *authv1.SelfSubjectAccessRevie
 		},
 	}, nil
 }
+
+func TestOverlays(t *testing.T) {
+	// Two Alertmanager resources in the same namespace share the governing
+	// service, the overlays of one resource mustn't be applied to it.
+	alertmanagers := []*monitoringv1.Alertmanager{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "test"},
+			Spec: monitoringv1.AlertmanagerSpec{
+				Overlays: []monitoringv1.Overlay{
+					{
+						Target: monitoringv1.OverlayTarget{Kind: monitoringv1.ServiceOverlayTargetKind},
+						Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+						Patch:  `{"metadata": {"annotations": {"foo": "bar"}}}`,
+					},
+					{
+						Target: monitoringv1.OverlayTarget{Kind: monitoringv1.SecretOverlayTargetKind},
+						Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+						Patch:  `{"metadata": {"annotations": {"foo": "bar"}}}`,
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "test"},
+		},
+	}
+
+	o := &Operator{kclient: fake.NewClientset()}
+	for _, am := range alertmanagers {
+		require.NoError(t, o.createOrUpdateGoverningService(context.Background(), am))
+
+		svc, err := o.kclient.CoreV1().Services("test").Get(context.Background(), defaultOperatedServiceName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, svc.Annotations, "foo")
+
+		require.NoError(t, o.createOrUpdateGeneratedConfigSecret(context.Background(), am, []byte{}, nil))
+
+		s, err := o.kclient.CoreV1().Secrets("test").Get(context.Background(), generatedConfigSecretName(am.Name), metav1.GetOptions{})
+		require.NoError(t, err)
+		if len(am.Spec.Overlays) > 0 {
+			require.Equal(t, "bar", s.Annotations["foo"])
+		} else {
+			require.NotContains(t, s.Annotations, "foo")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// EndpointsForReference returns the Alertmanager endpoints derived from the
+// spec of the referenced Alertmanager object.
+//
+// The endpoints select the pods of the Alertmanager behind its governing
+// service. The scheme, path prefix and API version are derived from the spec
+// unless they are already defined in ep. When web TLS is enabled, the serving
+// certificate is trusted if the Alertmanager lives in namespace (the
+// namespace of the referencing object) and ep has no TLS configuration.
+func EndpointsForReference(ep monitoringv1.AlertmanagerEndpoints, am *monitoringv1.Alertmanager, namespace string) (monitoringv1.AlertmanagerEndpoints, error) {
+	if am.Spec.ListenLocal {
+		return monitoringv1.AlertmanagerEndpoints{}, fmt.Errorf("alertmanager %s/%s listens on localhost only", am.Namespace, am.Name)
+	}
+
+	ret := *ep.DeepCopy()
+	ret.AlertmanagerRef = nil
+	ret.Name = getServiceName(am)
+	ret.Namespace = ptr.To(am.Namespace)
+	// The port number is used instead of the port name because a custom
+	// governing service may name the ports differently.
+	ret.Port = intstr.FromInt32(alertmanagerWebPort)
+
+	webTLS := webTLSConfig(am)
+	if ret.Scheme == nil {
+		ret.Scheme = ptr.To(monitoringv1.SchemeHTTP)
+		if webTLS != nil {
+			ret.Scheme = ptr.To(monitoringv1.SchemeHTTPS)
+		}
+	}
+
+	if ret.PathPrefix == nil {
+		ret.PathPrefix = ptr.To(routePrefix(am))
+	}
+
+	if ret.APIVersion == nil {
+		ret.APIVersion = ptr.To(monitoringv1.AlertmanagerAPIVersion2)
+	}
+
+	if ret.TLSConfig == nil && webTLS != nil && am.Namespace == namespace && (webTLS.Cert.Secret != nil || webTLS.Cert.ConfigMap != nil) {
+		ret.TLSConfig = &monitoringv1.TLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				CA:         *webTLS.Cert.DeepCopy(),
+				ServerName: ptr.To(serverName(am)),
+			},
+		}
+	}
+
+	// The governing service may be shared by several Alertmanagers.
+	ret.RelabelConfigs = append(
+		[]monitoringv1.RelabelConfig{{
+			Action:       "keep",
+			SourceLabels: []monitoringv1.LabelName{"__meta_kubernetes_pod_label_alertmanager"},
+			Regex:        am.Name,
+		}},
+		ret.RelabelConfigs...,
+	)
+
+	return ret, nil
+}
+
+// PodAddresses returns the addresses (<host>:<port>) of the Alertmanager
+// pods.
+func PodAddresses(am *monitoringv1.Alertmanager) []string {
+	replicas := max(ptr.Deref(am.Spec.Replicas, minReplicas), 0)
+
+	addrs := make([]string, 0, replicas)
+	for i := range replicas {
+		addrs = append(addrs, fmt.Sprintf("%s-%d.%s.%s.svc:%d", prefixedName(am.Name), i, getServiceName(am), am.Namespace, alertmanagerWebPort))
+	}
+
+	return addrs
+}
+
+// routePrefix returns the HTTP route prefix of the Alertmanager API.
+func routePrefix(am *monitoringv1.Alertmanager) string {
+	if am.Spec.RoutePrefix != "" {
+		return am.Spec.RoutePrefix
+	}
+
+	return "/"
+}
+
+// serverName returns the name which the serving certificate of the
+// Alertmanager is expected to be valid for.
+func serverName(am *monitoringv1.Alertmanager) string {
+	return fmt.Sprintf("%s.%s.svc", getServiceName(am), am.Namespace)
+}
+
+func webTLSConfig(am *monitoringv1.Alertmanager) *monitoringv1.WebTLSConfig {
+	if am.Spec.Web == nil {
+		return nil
+	}
+
+	return am.Spec.Web.TLSConfig
+}
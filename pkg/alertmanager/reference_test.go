@@ -0,0 +1,195 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestEndpointsForReference(t *testing.T) {
+	webTLS := &monitoringv1.AlertmanagerWebSpec{
+		WebConfigFileFields: monitoringv1.WebConfigFileFields{
+			TLSConfig: &monitoringv1.WebTLSConfig{
+				Cert: monitoringv1.SecretOrConfigMap{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "tls"},
+						Key:                  "tls.crt",
+					},
+				},
+				KeySecret: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "tls"},
+					Key:                  "tls.key",
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		ep        monitoringv1.AlertmanagerEndpoints
+		am        monitoringv1.AlertmanagerSpec
+		namespace string
+
+		exp monitoringv1.AlertmanagerEndpoints
+		err bool
+	}{
+		{
+			name:      "defaults",
+			namespace: "monitoring",
+			exp: monitoringv1.AlertmanagerEndpoints{
+				Name:       "alertmanager-operated",
+				Namespace:  ptr.To("monitoring"),
+				Port:       intstr.FromInt32(9093),
+				Scheme:     ptr.To(monitoringv1.SchemeHTTP),
+				PathPrefix: ptr.To("/"),
+				APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+			},
+		},
+		{
+			name:      "custom service and route prefix",
+			namespace: "default",
+			am: monitoringv1.AlertmanagerSpec{
+				ServiceName: ptr.To("alertmanager"),
+				RoutePrefix: "/alertmanager",
+			},
+			exp: monitoringv1.AlertmanagerEndpoints{
+				Name:       "alertmanager",
+				Namespace:  ptr.To("monitoring"),
+				Port:       intstr.FromInt32(9093),
+				Scheme:     ptr.To(monitoringv1.SchemeHTTP),
+				PathPrefix: ptr.To("/alertmanager"),
+				APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+			},
+		},
+		{
+			name:      "web TLS",
+			namespace: "monitoring",
+			am:        monitoringv1.AlertmanagerSpec{Web: webTLS},
+			exp: monitoringv1.AlertmanagerEndpoints{
+				Name:       "alertmanager-operated",
+				Namespace:  ptr.To("monitoring"),
+				Port:       intstr.FromInt32(9093),
+				Scheme:     ptr.To(monitoringv1.SchemeHTTPS),
+				PathPrefix: ptr.To("/"),
+				APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+				TLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						CA:         webTLS.TLSConfig.Cert,
+						ServerName: ptr.To("alertmanager-operated.monitoring.svc"),
+					},
+				},
+			},
+		},
+		{
+			name:      "web TLS in another namespace",
+			namespace: "default",
+			am:        monitoringv1.AlertmanagerSpec{Web: webTLS},
+			exp: monitoringv1.AlertmanagerEndpoints{
+				Name:       "alertmanager-operated",
+				Namespace:  ptr.To("monitoring"),
+				Port:       intstr.FromInt32(9093),
+				Scheme:     ptr.To(monitoringv1.SchemeHTTPS),
+				PathPrefix: ptr.To("/"),
+				APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+			},
+		},
+		{
+			name:      "explicit fields",
+			namespace: "monitoring",
+			ep: monitoringv1.AlertmanagerEndpoints{
+				AlertmanagerRef: &monitoringv1.AlertmanagerReference{Name: "main"},
+				Scheme:          ptr.To(monitoringv1.SchemeHTTP),
+				PathPrefix:      ptr.To("/api"),
+				Timeout:         ptr.To(monitoringv1.Duration("5s")),
+				RelabelConfigs: []monitoringv1.RelabelConfig{{
+					Action:      "replace",
+					TargetLabel: "foo",
+					Replacement: ptr.To("bar"),
+				}},
+			},
+			am: monitoringv1.AlertmanagerSpec{Web: webTLS},
+			exp: monitoringv1.AlertmanagerEndpoints{
+				Name:       "alertmanager-operated",
+				Namespace:  ptr.To("monitoring"),
+				Port:       intstr.FromInt32(9093),
+				Scheme:     ptr.To(monitoringv1.SchemeHTTP),
+				PathPrefix: ptr.To("/api"),
+				APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+				Timeout:    ptr.To(monitoringv1.Duration("5s")),
+				TLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						CA:         webTLS.TLSConfig.Cert,
+						ServerName: ptr.To("alertmanager-operated.monitoring.svc"),
+					},
+				},
+				RelabelConfigs: []monitoringv1.RelabelConfig{{
+					Action:      "replace",
+					TargetLabel: "foo",
+					Replacement: ptr.To("bar"),
+				}},
+			},
+		},
+		{
+			name:      "listen local",
+			namespace: "monitoring",
+			am:        monitoringv1.AlertmanagerSpec{ListenLocal: true},
+			err:       true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			am := &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+				Spec:       tc.am,
+			}
+
+			got, err := EndpointsForReference(tc.ep, am, tc.namespace)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			tc.exp.RelabelConfigs = append(
+				[]monitoringv1.RelabelConfig{{
+					Action:       "keep",
+					SourceLabels: []monitoringv1.LabelName{"__meta_kubernetes_pod_label_alertmanager"},
+					Regex:        "main",
+				}},
+				tc.exp.RelabelConfigs...,
+			)
+			require.Equal(t, tc.exp, got)
+		})
+	}
+}
+
+func TestPodAddresses(t *testing.T) {
+	am := &monitoringv1.Alertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec:       monitoringv1.AlertmanagerSpec{Replicas: ptr.To(int32(2))},
+	}
+
+	require.Equal(t, []string{
+		"alertmanager-main-0.alertmanager-operated.monitoring.svc:9093",
+		"alertmanager-main-1.alertmanager-operated.monitoring.svc:9093",
+	}, PodAddresses(am))
+}
@@ -263,7 +263,13 @@ func makeStatefulSetSpec(logger *slog.Logger, a *monitoringv1.Alertmanager, conf
 		amArgs = append(amArgs, monitoringv1.Argument{Name: "web.listen-address", Value: ":9093"})
 	}
 
-	if a.Spec.ExternalURL != "" {
+	switch {
+	case a.Spec.Exposure != nil:
+		amArgs = append(amArgs, monitoringv1.Argument{
+			Name:  "web.external-url",
+			Value: operator.ExposureExternalURL(*a.Spec.Exposure, prefixedName(a.Name), false, a.Spec.RoutePrefix),
+		})
+	case a.Spec.ExternalURL != "":
 		amArgs = append(amArgs, monitoringv1.Argument{Name: "web.external-url", Value: a.Spec.ExternalURL})
 	}
 
@@ -733,7 +739,8 @@ func makeStatefulSetSpec(logger *slog.Logger, a *monitoringv1.Alertmanager, conf
 		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 	}
 
-	if version.GTE(semver.MustParse("0.30.0")) {
+	// The external URL of the PerReplica exposure references the pod name.
+	if version.GTE(semver.MustParse("0.30.0")) || operator.ExposurePerReplica(a.Spec.Exposure) {
 		alertmanagerContainer.Env = append(alertmanagerContainer.Env, corev1.EnvVar{
 			Name: operator.PodNameEnvVar,
 			ValueFrom: &corev1.EnvVarSource{
@@ -48,32 +48,42 @@ const (
 
 	ThanosRulersKind = "ThanosRuler"
 	ThanosRulerName  = "thanosrulers"
+
+	MonitoringQuotasKind = "MonitoringQuota"
+	MonitoringQuotaName  = "monitoringquotas"
+
+	PrometheusOperatorDefaultsKind = "PrometheusOperatorDefaults"
+	PrometheusOperatorDefaultsName = "prometheusoperatordefaults"
 )
 
 var resourceToKindMap = map[string]string{
-	PrometheusName:         PrometheusesKind,
-	PrometheusAgentName:    PrometheusAgentsKind,
-	AlertmanagerName:       AlertmanagersKind,
-	AlertmanagerConfigName: AlertmanagerConfigsKind,
-	ServiceMonitorName:     ServiceMonitorsKind,
-	PodMonitorName:         PodMonitorsKind,
-	PrometheusRuleName:     PrometheusRuleKind,
-	ProbeName:              ProbesKind,
-	ScrapeConfigName:       ScrapeConfigsKind,
-	ThanosRulerName:        ThanosRulersKind,
+	PrometheusName:                 PrometheusesKind,
+	PrometheusAgentName:            PrometheusAgentsKind,
+	AlertmanagerName:               AlertmanagersKind,
+	AlertmanagerConfigName:         AlertmanagerConfigsKind,
+	ServiceMonitorName:             ServiceMonitorsKind,
+	PodMonitorName:                 PodMonitorsKind,
+	PrometheusRuleName:             PrometheusRuleKind,
+	ProbeName:                      ProbesKind,
+	ScrapeConfigName:               ScrapeConfigsKind,
+	ThanosRulerName:                ThanosRulersKind,
+	MonitoringQuotaName:            MonitoringQuotasKind,
+	PrometheusOperatorDefaultsName: PrometheusOperatorDefaultsKind,
 }
 
 var kindToResource = map[string]string{
-	PrometheusesKind:        PrometheusName,
-	PrometheusAgentsKind:    PrometheusAgentName,
-	AlertmanagersKind:       AlertmanagerName,
-	AlertmanagerConfigsKind: AlertmanagerConfigName,
-	ServiceMonitorsKind:     ServiceMonitorName,
-	PodMonitorsKind:         PodMonitorName,
-	PrometheusRuleKind:      PrometheusRuleName,
-	ProbesKind:              ProbeName,
-	ScrapeConfigsKind:       ScrapeConfigName,
-	ThanosRulersKind:        ThanosRulerName,
+	PrometheusesKind:               PrometheusName,
+	PrometheusAgentsKind:           PrometheusAgentName,
+	AlertmanagersKind:              AlertmanagerName,
+	AlertmanagerConfigsKind:        AlertmanagerConfigName,
+	ServiceMonitorsKind:            ServiceMonitorName,
+	PodMonitorsKind:                PodMonitorName,
+	PrometheusRuleKind:             PrometheusRuleName,
+	ProbesKind:                     ProbeName,
+	ScrapeConfigsKind:              ScrapeConfigName,
+	ThanosRulersKind:               ThanosRulerName,
+	MonitoringQuotasKind:           MonitoringQuotaName,
+	PrometheusOperatorDefaultsKind: PrometheusOperatorDefaultsName,
 }
 
 // KindToResource returns the resource name corresponding to the given kind.
@@ -195,6 +195,16 @@ type AlertmanagerSpec struct {
 	// under a different route prefix. For example for use with `kubectl proxy`.
 	// +optional
 	RoutePrefix string `json:"routePrefix,omitempty"`
+
+	// exposure defines how the web UI and API are exposed outside of the
+	// cluster. The operator generates the Service(s) and the Ingress or the
+	// Gateway API HTTPRoute(s) matching the route prefix and the web TLS
+	// configuration. When defined, the operator also derives the external
+	// URL from the host name and it takes precedence over `externalUrl`.
+	//
+	// +optional
+	Exposure *ExposureSpec `json:"exposure,omitempty"`
+
 	// paused if set to true all actions on the underlying managed objects are not
 	// going to be performed, except for delete actions.
 	// +optional
@@ -274,6 +284,23 @@ type AlertmanagerSpec struct {
 	// +optional
 	UpdateStrategy *StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// podDisruptionBudget defines the PodDisruptionBudget protecting the
+	// pods. When defined, the operator creates one PodDisruptionBudget per
+	// StatefulSet (e.g. per shard) and deletes it when the StatefulSet is
+	// removed or when the field is unset.
+	//
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// networkPolicy defines the NetworkPolicy generated by the operator for
+	// the pods. When defined, the operator allows the ingress traffic to the
+	// exposed ports and the egress traffic to the destinations it knows
+	// about (DNS, selected namespaces, in-cluster endpoints, ...). The
+	// NetworkPolicy is deleted when the field is unset.
+	//
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
 	// containers allows injecting additional containers or modifying operator
 	// generated containers. This can be used to allow adding an authentication
 	// proxy to the Pods or to change the behavior of an operator generated
@@ -311,6 +338,23 @@ type AlertmanagerSpec struct {
 	// +optional
 	InitContainers []v1.Container `json:"initContainers,omitempty"`
 
+	// overlays defines patches applied to the StatefulSet(s), Service(s) and
+	// Secret(s) generated by the operator. The patches are applied in order
+	// after the operator has built the objects. It can be used to modify
+	// fields which can't be configured otherwise (e.g. the metadata of the
+	// volume claim templates). The overlays don't apply to the default
+	// governing Service which is shared by all the resources of the namespace.
+	//
+	// A failing patch is reported in the `Reconciled` condition with the
+	// `OverlayFailed` reason.
+	//
+	// Overlays which modify fields managed by the operator require careful
+	// testing, especially when upgrading to a new version of the operator.
+	//
+	// +listType=atomic
+	// +optional
+	Overlays []Overlay `json:"overlays,omitempty"`
+
 	// priorityClassName assigned to the Pods
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
@@ -304,6 +304,20 @@ type CommonPrometheusFields struct {
 	// +optional
 	ShardingStrategy *ShardingStrategy `json:"shardingStrategy,omitempty"`
 
+	// shardRolloutStrategy defines how the operator rolls out the changes of
+	// the StatefulSets and of the Prometheus configuration across the shards.
+
+	//
+	// The configuration files of a shard are updated only when the shard is
+	// rolled out and the operator waits for the config-reloader sidecars to
+	// report a successful reload before moving on to the next shards. When set,
+	// each shard reads its own configuration file.
+	//
+	// When not defined, the operator updates all shards at the same time.
+	//
+	// +optional
+	ShardRolloutStrategy *ShardRolloutStrategy `json:"shardRolloutStrategy,omitempty"`
+
 	// replicaExternalLabelName defines the name of Prometheus external label used to denote the replica name.
 	// The external label will _not_ be added when the field is set to the
 	// empty string (`""`).
@@ -548,6 +562,23 @@ type CommonPrometheusFields struct {
 	// +optional
 	UpdateStrategy *StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// podDisruptionBudget defines the PodDisruptionBudget protecting the
+	// pods. When defined, the operator creates one PodDisruptionBudget per
+	// StatefulSet (e.g. per shard) and deletes it when the StatefulSet is
+	// removed or when the field is unset.
+	//
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// networkPolicy defines the NetworkPolicy generated by the operator for
+	// the pods. When defined, the operator allows the ingress traffic to the
+	// exposed ports and the egress traffic to the destinations it knows
+	// about (DNS, selected namespaces, in-cluster endpoints, ...). The
+	// NetworkPolicy is deleted when the field is unset.
+	//
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
 	// enableServiceLinks defines whether information about services should be injected into pod's environment variables
 	// +optional
 	EnableServiceLinks *bool `json:"enableServiceLinks,omitempty"` // nolint:kubeapilinter
@@ -589,6 +620,23 @@ type CommonPrometheusFields struct {
 	// +optional
 	InitContainers []v1.Container `json:"initContainers,omitempty"`
 
+	// overlays defines patches applied to the StatefulSet(s), Service(s) and
+	// Secret(s) generated by the operator. The patches are applied in order
+	// after the operator has built the objects. It can be used to modify
+	// fields which can't be configured otherwise (e.g. the metadata of the
+	// volume claim templates). The overlays don't apply to the default
+	// governing Service which is shared by all the resources of the namespace.
+	//
+	// A failing patch is reported in the `Reconciled` condition with the
+	// `OverlayFailed` reason.
+	//
+	// Overlays which modify fields managed by the operator require careful
+	// testing, especially when upgrading to a new version of the operator.
+	//
+	// +listType=atomic
+	// +optional
+	Overlays []Overlay `json:"overlays,omitempty"`
+
 	// additionalScrapeConfigs allows specifying a key of a Secret containing
 	// additional Prometheus scrape configurations. Scrape configurations
 	// specified are appended to the configurations generated by the Prometheus
@@ -998,6 +1046,34 @@ type CommonPrometheusFields struct {
 	// +optional
 	ServiceDiscoveryRole *ServiceDiscoveryRole `json:"serviceDiscoveryRole,omitempty"`
 
+	// operatorServiceDiscovery configures Prometheus to discover the static
+	// targets of ScrapeConfig (`spec.staticConfigs`) and Probe
+	// (`spec.targets.staticConfig`) resources from the HTTP service discovery
+	// endpoint served by the operator instead of writing them in the
+	// configuration. Changes to the targets are then picked up by Prometheus
+	// without configuration reload.
+	//
+	// It requires the operator to run with the `--http-sd.listen-address`
+	// and `--http-sd.url` arguments, otherwise the targets are written in the
+	// configuration.
+	//
+	// It requires Prometheus >= v2.21.0.
+	//
+	// +optional
+	OperatorServiceDiscovery *OperatorServiceDiscovery `json:"operatorServiceDiscovery,omitempty"`
+
+	// annotationDiscovery configures scrape jobs discovering the pods and the
+	// service endpoints annotated with `prometheus.io/scrape: "true"`. The
+	// `prometheus.io/port`, `prometheus.io/path` and `prometheus.io/scheme`
+	// annotations override the address, metrics path and scheme of the
+	// targets.
+	//
+	// The jobs honor the enforced namespace label, the sharding configuration
+	// and the enforced limits.
+	//
+	// +optional
+	AnnotationDiscovery *AnnotationDiscovery `json:"annotationDiscovery,omitempty"`
+
 	// tsdb defines the runtime reloadable configuration of the timeseries database(TSDB).
 	// It requires Prometheus >= v2.39.0 or PrometheusAgent >= v2.54.0.
 	//
@@ -1117,6 +1193,96 @@ const (
 	ProcessSignalReloadStrategyType ReloadStrategyType = "ProcessSignal"
 )
 
+// +kubebuilder:validation:Enum=Inline;PerResource
+type ScrapeConfigRenderingMode string
+
+const (
+	// InlineScrapeConfigRendering writes all scrape configurations into the
+	// main configuration file.
+	InlineScrapeConfigRendering ScrapeConfigRenderingMode = "Inline"
+
+	// PerResourceScrapeConfigRendering writes the scrape configurations of
+	// each resource into a separate file referenced by `scrape_config_files`.
+	PerResourceScrapeConfigRendering ScrapeConfigRenderingMode = "PerResource"
+)
+
+// OperatorServiceDiscovery defines how Prometheus connects to the HTTP
+// service discovery endpoint served by the operator.
+// +k8s:openapi-gen=true
+type OperatorServiceDiscovery struct {
+	// refreshInterval defines the interval at which Prometheus refreshes the
+	// targets from the operator.
+	//
+	// If not defined, Prometheus uses its default value (1m).
+	//
+	// +optional
+	RefreshInterval *Duration `json:"refreshInterval,omitempty"`
+
+	// tlsConfig defines the TLS configuration used by Prometheus to connect to
+	// the operator. When the operator verifies client certificates, it should
+	// reference the client certificate and key of Prometheus.
+	//
+	// +optional
+	TLSConfig *SafeTLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// AnnotationDiscovery defines the scrape jobs discovering targets from the
+// `prometheus.io/*` annotations.
+// +k8s:openapi-gen=true
+type AnnotationDiscovery struct {
+	// roles defines the kinds of objects which are discovered from their
+	// annotations.
+	//
+	// * `Pod`: the pods are annotated.
+	// * `Service`: the services are annotated and the targets are the service
+	// endpoints.
+	//
+	// If not defined, both roles are enabled.
+	// The `Service` role isn't supported in DaemonSet mode.
+	//
+	// +listType=set
+	// +optional
+	Roles []AnnotationDiscoveryRole `json:"roles,omitempty"`
+
+	// namespaceSelector defines the namespaces from which the targets are
+	// discovered.
+	//
+	// If empty, only the namespace of the Prometheus object is used.
+	//
+	// +optional
+	NamespaceSelector NamespaceSelector `json:"namespaceSelector,omitempty"`
+
+	// scrapeClass defines the scrape class to apply.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	ScrapeClassName *string `json:"scrapeClass,omitempty"`
+
+	// sampleLimit defines a per-scrape limit on the number of scraped samples
+	// that will be accepted.
+	// The value is capped by `enforcedSampleLimit` when defined.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	SampleLimit *int64 `json:"sampleLimit,omitempty"`
+
+	// targetLimit defines a per-job limit on the number of scraped targets
+	// that will be accepted.
+	// The value is capped by `enforcedTargetLimit` when defined.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	TargetLimit *int64 `json:"targetLimit,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Pod;Service
+type AnnotationDiscoveryRole string
+
+const (
+	PodAnnotationDiscoveryRole     AnnotationDiscoveryRole = "Pod"
+	ServiceAnnotationDiscoveryRole AnnotationDiscoveryRole = "Service"
+)
+
 // +kubebuilder:validation:Enum=Endpoints;EndpointSlice
 type ServiceDiscoveryRole string
 
@@ -1206,6 +1372,7 @@ func (l *PrometheusList) DeepCopyObject() runtime.Object {
 // PrometheusSpec is a specification of the desired behavior of the Prometheus cluster. More info:
 // https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
 // +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="!has(self.retentionSize) || !has(self.retentionSizePercentage)",message="retentionSize and retentionSizePercentage are mutually exclusive"
 type PrometheusSpec struct {
 	// +optional
 	CommonPrometheusFields `json:",inline"`
@@ -1220,14 +1387,43 @@ type PrometheusSpec struct {
 	// +optional
 	SHA string `json:"sha,omitempty"`
 
+	// exposure defines how the web UI and API are exposed outside of the
+	// cluster. The operator generates the Service(s) and the Ingress or the
+	// Gateway API HTTPRoute(s) matching the route prefix and the web TLS
+	// configuration. When defined, the operator also derives the external
+	// URL from the host name and it takes precedence over `externalUrl`.
+	//
+	// +optional
+	Exposure *ExposureSpec `json:"exposure,omitempty"`
+
 	// retention defines how long to retain the Prometheus data.
 	//
 	// Default: "24h" if `spec.retention` and `spec.retentionSize` are empty.
 	// +optional
 	Retention Duration `json:"retention,omitempty"`
 	// retentionSize defines the maximum number of bytes used by the Prometheus data.
+	//
+	// It cannot be set at the same time as `retentionSizePercentage`.
 	// +optional
 	RetentionSize ByteSize `json:"retentionSize,omitempty"`
+	// retentionSizePercentage defines the maximum size used by the Prometheus
+	// data as a percentage of the storage size. The storage size is the
+	// storage request of `spec.storage.volumeClaimTemplate` (or
+	// `spec.storage.ephemeral.volumeClaimTemplate`) or the size limit of
+	// `spec.storage.emptyDir`.
+	//
+	// The size-based retention includes the write-ahead log (WAL) which can
+	// grow beyond the limit until the next head compaction: the percentage
+	// should leave enough headroom to avoid filling the volume (e.g. 80).
+	//
+	// The value is recomputed whenever the storage size changes. It is ignored
+	// if the storage size isn't defined.
+	//
+	// It cannot be set at the same time as `retentionSize`.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RetentionSizePercentage *int32 `json:"retentionSizePercentage,omitempty"`
 
 	// shardRetentionPolicy defines the retention policy for the Prometheus shards.
 	//
@@ -1236,6 +1432,12 @@ type PrometheusSpec struct {
 	// +optional
 	ShardRetentionPolicy *ShardRetentionPolicy `json:"shardRetentionPolicy,omitempty"`
 
+	// storageMigration defines how the operator handles a change of the
+	// storage class in `spec.storage.volumeClaimTemplate`.
+	//
+	// +optional
+	StorageMigration *StorageMigration `json:"storageMigration,omitempty"`
+
 	// disableCompaction when true, the Prometheus compaction is disabled.
 	//
 	// When `spec.thanos.objectStorageConfig` or `spec.thanos.objectStorageConfigFile` are defined, the operator's
@@ -1268,6 +1470,38 @@ type PrometheusSpec struct {
 	// namespace only.
 	// +optional
 	RuleNamespaceSelector *metav1.LabelSelector `json:"ruleNamespaceSelector,omitempty"`
+	// ruleEvaluation defines which shards evaluate the rules selected by
+	// `ruleSelector` when the Prometheus resource has more than one shard.
+	//
+	// By default, every shard evaluates all the rules which means that each
+	// alert fires once per shard and that recording rules produce one
+	// partial series per shard.
+	//
+	// +optional
+	RuleEvaluation *RuleEvaluation `json:"ruleEvaluation,omitempty"`
+
+	// scrapeConfigRendering defines how the operator renders the scrape
+	// configurations generated from the ServiceMonitor, PodMonitor, Probe and
+	// ScrapeConfig resources.
+	//
+	// * `Inline` (default): all scrape configurations are written in the
+	// main Prometheus configuration file.
+	// * `PerResource`: the scrape configurations of each resource are
+	// written to a separate file which is referenced by the
+	// `scrape_config_files` field of the main configuration. The files are
+	// rendered for each shard and stored in a set of Secrets named
+	// `prometheus-<name>[-shard-<s>]-scrape-configs-<n>`.
+	// A change to one resource doesn't modify the files of the other
+	// resources.
+	//
+	// The additional scrape configurations (`spec.additionalScrapeConfigs`)
+	// are always written in the main configuration file.
+	//
+	// `PerResource` requires Prometheus >= v2.43.0, the operator falls back to
+	// `Inline` for older versions.
+	//
+	// +optional
+	ScrapeConfigRendering *ScrapeConfigRenderingMode `json:"scrapeConfigRendering,omitempty"`
 
 	// query defines the configuration of the Prometheus query service.
 	// +optional
@@ -1404,8 +1638,55 @@ type ShardRetentionPolicy struct {
 	Retain *RetainConfig `json:"retain,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=None;Copy
+type StorageMigrationPolicy string
+
+const (
+	NoneStorageMigrationPolicy StorageMigrationPolicy = "None"
+	CopyStorageMigrationPolicy StorageMigrationPolicy = "Copy"
+)
+
+type StorageMigration struct {
+	// policy defines the migration policy when the storage class of the
+	// volume claim template changes.
+	// * `None`, the existing persistent volume claims keep the previous
+	// storage class and only the new claims use the new storage class.
+	// * `Copy`, the operator migrates the data of each replica to a new
+	// persistent volume of the new storage class: it stops the pod, copies
+	// the data with a Job and binds the new volume to the persistent volume
+	// claim of the pod. The replicas are migrated one at a time and the
+	// migration of a replica starts only when the other replicas of the shard
+	// are ready. The previous volumes are retained and need to be deleted
+	// manually.
+	//
+	// The `Copy` policy requires the storage class to be set explicitly in
+	// the volume claim template.
+	//
+	// If not defined, the operator assumes the `None` value.
+	//
+	// +optional
+	Policy StorageMigrationPolicy `json:"policy,omitempty"`
+	// image defines the container image of the Job copying the data. The
+	// image should provide the `cp` command.
+	//
+	// If not defined, the operator uses the Prometheus image.
+	//
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// timeout defines the maximum duration of the Job copying the data of a
+	// replica. When the Job doesn't complete within the timeout (for instance
+	// because its pod can't be scheduled), the operator stops the migration and
+	// reports the failure in the `StorageMigrated` condition. Deleting the Job
+	// restarts the copy.
+	//
+	// If not defined, the operator assumes a timeout of 6 hours.
+	//
+	// +optional
+	Timeout *Duration `json:"timeout,omitempty"`
+}
+
 // ShardingStrategyMode defines the sharding mode for Prometheus.
-// +kubebuilder:validation:Enum=Address;Topology
+// +kubebuilder:validation:Enum=Address;Topology;Namespace;Label
 type ShardingStrategyMode string
 
 const (
@@ -1418,6 +1699,16 @@ const (
 	//
 	// (Beta) Using this mode requires the `PrometheusTopologySharding` feature gate (enabled by default).
 	TopologyShardingStrategyMode ShardingStrategyMode = "Topology"
+
+	// NamespaceShardingStrategyMode assigns whole namespaces to shards.
+	// Each shard only has the scrape configurations of the resources from
+	// its namespaces.
+	NamespaceShardingStrategyMode ShardingStrategyMode = "Namespace"
+
+	// LabelShardingStrategyMode assigns the resources to shards based on the
+	// value of a label. Resources without the label are assigned based on
+	// their namespace.
+	LabelShardingStrategyMode ShardingStrategyMode = "Label"
 )
 
 // TopologyShardingStrategy defines the configuration for topology-aware sharding.
@@ -1439,10 +1730,139 @@ type TopologyShardingStrategy struct {
 	Values []string `json:"values,omitempty"`
 }
 
+// NamespaceShardingStrategy defines the configuration for namespace-based sharding.
+type NamespaceShardingStrategy struct {
+	// assignments pins namespaces to specific shards.
+	//
+	// The namespaces which aren't listed are assigned to a shard based on a
+	// hash of their name.
+	//
+	// +listType=map
+	// +listMapKey=value
+	// +optional
+	Assignments []ShardAssignment `json:"assignments,omitempty"`
+}
+
+// LabelShardingStrategy defines the configuration for label-based sharding.
+type LabelShardingStrategy struct {
+	// labelName defines the name of the label on the ServiceMonitor,
+	// PodMonitor, Probe and ScrapeConfig resources which identifies the
+	// shard key.
+	//
+	// The resources sharing the same label value are assigned to the same
+	// shard. The resources without the label are assigned to a shard based
+	// on a hash of their namespace.
+	//
+	// If not defined, it defaults to "operator.prometheus.io/shard".
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	LabelName *string `json:"labelName,omitempty"`
+
+	// assignments pins label values to specific shards.
+	//
+	// The label values which aren't listed are assigned to a shard based on
+	// a hash of the value.
+	//
+	// +listType=map
+	// +listMapKey=value
+	// +optional
+	Assignments []ShardAssignment `json:"assignments,omitempty"`
+}
+
+// RuleEvaluationMode defines how the rule groups are assigned to the shards.
+// +kubebuilder:validation:Enum=AllShards;SingleShard;HashGroups;GroupLabel
+type RuleEvaluationMode string
+
+const (
+	// AllShardsRuleEvaluationMode evaluates all the rule groups on every
+	// shard.
+	AllShardsRuleEvaluationMode RuleEvaluationMode = "AllShards"
+
+	// SingleShardRuleEvaluationMode evaluates all the rule groups on a
+	// single shard.
+	SingleShardRuleEvaluationMode RuleEvaluationMode = "SingleShard"
+
+	// HashGroupsRuleEvaluationMode distributes the rule groups across the
+	// shards based on a hash of the rule group's identity.
+	HashGroupsRuleEvaluationMode RuleEvaluationMode = "HashGroups"
+
+	// GroupLabelRuleEvaluationMode assigns the rule groups to the shards
+	// based on the value of a rule group label.
+	GroupLabelRuleEvaluationMode RuleEvaluationMode = "GroupLabel"
+)
+
+// RuleEvaluation defines how the rules are evaluated by a sharded Prometheus.
+// +kubebuilder:validation:XValidation:rule="!has(self.groupLabelName) || (has(self.mode) && self.mode == 'GroupLabel')",message="groupLabelName can only be defined when mode is set to 'GroupLabel'"
+type RuleEvaluation struct {
+	// mode defines how the rule groups are assigned to the shards.
+	//
+	// * `AllShards` (default): every shard evaluates all the rule groups.
+	// * `SingleShard`: only the shard defined by the `shard` field evaluates
+	// the rule groups.
+	// * `HashGroups`: each rule group is evaluated by one shard, computed
+	// from a hash of the PrometheusRule's namespace, name and of the group's
+	// name.
+	// * `GroupLabel`: each rule group is evaluated by the shard defined by
+	// the value of the `groupLabelName` label of the group. The groups
+	// without the label (or with an invalid value) are evaluated by the shard
+	// defined by the `shard` field.
+	//
+	// +optional
+	Mode *RuleEvaluationMode `json:"mode,omitempty"`
+
+	// shard defines the index of the shard (starting from 0) evaluating the
+	// rules when the mode is 'SingleShard', and the rule groups without
+	// shard label when the mode is 'GroupLabel'.
+	//
+	// If the index is greater than or equal to the number of shards, the
+	// operator uses the first shard.
+	//
+	// If not defined, it defaults to 0.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Shard *int32 `json:"shard,omitempty"`
+
+	// groupLabelName defines the name of the rule group label whose value is
+	// the index of the shard evaluating the group when the mode is
+	// 'GroupLabel'.
+	//
+	// The label is removed from the generated rule group. Rule group labels
+	// require Prometheus v3.0.0 and above.
+	//
+	// If not defined, it defaults to "prometheus_operator_shard".
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	GroupLabelName *string `json:"groupLabelName,omitempty"`
+}
+
+// ShardAssignment pins a sharding key to a shard.
+type ShardAssignment struct {
+	// value defines the sharding key: the namespace name when the mode is
+	// 'Namespace' or the label value when the mode is 'Label'.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Value string `json:"value"`
+
+	// shard defines the index of the shard (starting from 0).
+	//
+	// If the index is greater than or equal to the number of shards, the
+	// assignment is ignored.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	Shard int32 `json:"shard"`
+}
+
 // ShardingStrategy defines the sharding strategy for Prometheus.
 // +kubebuilder:validation:XValidation:rule="!has(self.topology) || (has(self.mode) && self.mode == 'Topology')",message="topology can only be defined when mode is set to 'Topology'"
+// +kubebuilder:validation:XValidation:rule="!has(self.__namespace__) || (has(self.mode) && self.mode == 'Namespace')",message="namespace can only be defined when mode is set to 'Namespace'"
+// +kubebuilder:validation:XValidation:rule="!has(self.label) || (has(self.mode) && self.mode == 'Label')",message="label can only be defined when mode is set to 'Label'"
 type ShardingStrategy struct {
-	// mode defines the sharding mode. Can be 'Address' or 'Topology'.
+	// mode defines the sharding mode. Can be 'Address', 'Topology', 'Namespace' or 'Label'.
 	//
 	// 'Address' is the default mode and distributes targets across shards
 	// based on a hash of the target address.
@@ -1452,6 +1872,18 @@ type ShardingStrategy struct {
 	// (Alpha) Using the 'Topology' mode requires the `PrometheusTopologySharding`
 	// feature gate to be enabled.
 	//
+	// 'Namespace' assigns whole namespaces to shards. The configuration of
+	// each shard only contains the scrape jobs of the ServiceMonitor,
+	// PodMonitor, Probe and ScrapeConfig resources from its namespaces.
+	//
+	// 'Label' assigns the ServiceMonitor, PodMonitor, Probe and ScrapeConfig
+	// resources to shards based on the value of a label. The configuration
+	// of each shard only contains the scrape jobs of its resources.
+	//
+	// With the 'Namespace' and 'Label' modes, the additional scrape
+	// configurations are still distributed based on the target address and
+	// the annotation-based discovery is distributed by namespace.
+	//
 	// +optional
 	Mode *ShardingStrategyMode `json:"mode,omitempty"`
 
@@ -1459,6 +1891,47 @@ type ShardingStrategy struct {
 	// This field is only valid when mode is set to 'Topology'.
 	// +optional
 	Topology *TopologyShardingStrategy `json:"topology,omitempty"`
+
+	// namespace defines the configuration for namespace-based sharding.
+	// This field is only valid when mode is set to 'Namespace'.
+	// +optional
+	Namespace *NamespaceShardingStrategy `json:"namespace,omitempty"`
+
+	// label defines the configuration for label-based sharding.
+	// This field is only valid when mode is set to 'Label'.
+	// +optional
+	Label *LabelShardingStrategy `json:"label,omitempty"`
+}
+
+// ShardRolloutStrategy defines the progressive rollout of the changes across
+// the shards.
+type ShardRolloutStrategy struct {
+	// batchSize defines the maximum number of shards being updated at the
+	// same time. The operator updates the StatefulSet of the next shard(s)
+	// only when the previously updated shards are available: all their pods
+	// run the latest revision and are ready. Because the pods are restarted
+	// during the update, a pod becomes ready only when its configuration has
+	// been generated and loaded successfully.
+	//
+	// The shards are updated in ascending order. The shards which aren't
+	// available count towards the batch size but they can always be updated.
+	//
+	// If not defined, the operator updates one shard at a time.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize *int32 `json:"batchSize,omitempty"`
+
+	// progressDeadline defines the maximum duration for an updated shard to
+	// become available. When a shard doesn't become available within the
+	// deadline, the operator pauses the rollout and reports it in the
+	// `RolledOut` condition. The rollout resumes when the shard becomes
+	// available or when the resource is updated.
+	//
+	// If not defined, the operator assumes a deadline of 10 minutes.
+	//
+	// +optional
+	ProgressDeadline *Duration `json:"progressDeadline,omitempty"`
 }
 
 // PrometheusStatus is the most recent observed status of the Prometheus cluster.
@@ -1739,12 +2212,37 @@ type ThanosSpec struct {
 // RemoteWriteSpec defines the configuration to write samples from Prometheus
 // to a remote endpoint.
 // +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="has(self.url) != has(self.prometheusRef)",message="exactly one of url and prometheusRef must be set"
 type RemoteWriteSpec struct {
 	// url defines the URL of the endpoint to send samples to.
 	//
 	// It must use the HTTP or HTTPS scheme.
-	// +required
-	URL URL `json:"url"`
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
+	//
+	// +optional
+	URL URL `json:"url,omitempty"`
+
+	// prometheusRef references a Prometheus object to send samples to.
+	//
+	// The operator configures one remote-write queue per replica of the
+	// referenced Prometheus, targeting the remote-write receiver endpoint of
+	// the pod. The referenced Prometheus must have
+	// `enableRemoteWriteReceiver` set to true and it can't be sharded.
+	//
+	// The scheme and the route prefix are derived from the web configuration
+	// of the referenced Prometheus. When web TLS is enabled and the referenced
+	// Prometheus lives in the same namespace, the serving certificate is
+	// trusted unless `tlsConfig` is defined. Authentication settings (e.g.
+	// `basicAuth`) aren't derived and must be defined explicitly.
+	//
+	// When `name` is defined and the referenced Prometheus has more than one
+	// replica, the replica number is appended to the queue's name.
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
+	//
+	// +optional
+	PrometheusRef *PrometheusReference `json:"prometheusRef,omitempty"`
 
 	// name of the remote write queue, it must be unique if specified. The
 	// name is used in metrics and logging in order to differentiate queues.
@@ -1808,13 +2306,13 @@ type RemoteWriteSpec struct {
 	//
 	// It requires Prometheus >= v2.27.0 or Thanos >= v0.24.0.
 	//
-	// Cannot be set at the same time as `sigv4`, `authorization`, `basicAuth`, or `azureAd`.
+	// Cannot be set at the same time as `sigv4`, `authorization`, `basicAuth`, `azureAd` or `googleIAM`.
 	// +optional
 	OAuth2 *OAuth2 `json:"oauth2,omitempty"`
 
 	// basicAuth configuration for the URL.
 	//
-	// Cannot be set at the same time as `sigv4`, `authorization`, `oauth2`, or `azureAd`.
+	// Cannot be set at the same time as `sigv4`, `authorization`, `oauth2`, `azureAd` or `googleIAM`.
 	//
 	// +optional
 	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
@@ -1829,7 +2327,7 @@ type RemoteWriteSpec struct {
 	//
 	// It requires Prometheus >= v2.26.0 or Thanos >= v0.24.0.
 	//
-	// Cannot be set at the same time as `sigv4`, `basicAuth`, `oauth2`, or `azureAd`.
+	// Cannot be set at the same time as `sigv4`, `basicAuth`, `oauth2`, `azureAd` or `googleIAM`.
 	//
 	// +optional
 	Authorization *Authorization `json:"authorization,omitempty"`
@@ -1838,7 +2336,7 @@ type RemoteWriteSpec struct {
 	//
 	// It requires Prometheus >= v2.26.0 or Thanos >= v0.24.0.
 	//
-	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, or `azureAd`.
+	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, `azureAd` or `googleIAM`.
 	//
 	// +optional
 	Sigv4 *Sigv4 `json:"sigv4,omitempty"`
@@ -1847,11 +2345,20 @@ type RemoteWriteSpec struct {
 	//
 	// It requires Prometheus >= v2.45.0 or Thanos >= v0.31.0.
 	//
-	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, or `sigv4`.
+	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, `sigv4` or `googleIAM`.
 	//
 	// +optional
 	AzureAD *AzureAD `json:"azureAd,omitempty"`
 
+	// googleIAM defines the Google Cloud IAM authentication for the URL.
+	//
+	// It requires Prometheus >= v2.55.0 or Thanos >= v0.37.0.
+	//
+	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, `sigv4` or `azureAd`.
+	//
+	// +optional
+	GoogleIAM *GoogleIAM `json:"googleIAM,omitempty"`
+
 	// bearerToken is deprecated: this will be removed in a future release.
 	// *Warning: this field shouldn't be used because the token value appears
 	// in clear-text. Prefer using `authorization`.*
@@ -1996,6 +2503,19 @@ type Sigv4 struct {
 	UseFIPSSTSEndpoint *bool `json:"useFIPSSTSEndpoint,omitempty"` // nolint:kubeapilinter
 }
 
+// GoogleIAM defines the configuration for remote write's google_iam parameters.
+// +k8s:openapi-gen=true
+type GoogleIAM struct {
+	// credentials defines the Secret's key containing the Google Cloud
+	// credentials file (JSON format).
+	//
+	// If not defined, the Google Application Default Credentials are used
+	// (e.g. GKE Workload Identity).
+	//
+	// +optional
+	Credentials *v1.SecretKeySelector `json:"credentials,omitempty"`
+}
+
 // AzureAD defines the configuration for remote write's azuread parameters.
 // +k8s:openapi-gen=true
 type AzureAD struct {
@@ -2087,12 +2607,36 @@ type AzureWorkloadIdentity struct {
 // RemoteReadSpec defines the configuration for Prometheus to read back samples
 // from a remote endpoint.
 // +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="has(self.url) != has(self.prometheusRef)",message="exactly one of url and prometheusRef must be set"
 type RemoteReadSpec struct {
 	// url defines the URL of the endpoint to query from.
 	//
 	// It must use the HTTP or HTTPS scheme.
-	// +required
-	URL URL `json:"url"`
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
+	//
+	// +optional
+	URL URL `json:"url,omitempty"`
+
+	// prometheusRef references a Prometheus object to query from.
+	//
+	// The operator configures one remote-read endpoint per shard of the
+	// referenced Prometheus, targeting the first replica of the shard (the
+	// replicas of a shard hold the same data).
+	//
+	// The scheme and the route prefix are derived from the web configuration
+	// of the referenced Prometheus. When web TLS is enabled and the referenced
+	// Prometheus lives in the same namespace, the serving certificate is
+	// trusted unless `tlsConfig` is defined. Authentication settings (e.g.
+	// `basicAuth`) aren't derived and must be defined explicitly.
+	//
+	// When `name` is defined and the referenced Prometheus has more than one
+	// shard, the shard number is appended to the endpoint's name.
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
+	//
+	// +optional
+	PrometheusRef *PrometheusReference `json:"prometheusRef,omitempty"`
 
 	// name of the remote read queue, it must be unique if specified. The
 	// name is used in metrics and logging in order to differentiate read
@@ -2302,9 +2846,17 @@ const (
 	AlertmanagerAPIVersion2 = AlertmanagerAPIVersion("V2")
 )
 
-// AlertmanagerEndpoints defines a selection of a single Endpoints object
-// containing Alertmanager IPs to fire alerts against.
+// AlertmanagerEndpoints defines the Alertmanager IPs to fire alerts against.
+//
+// The Alertmanagers are either discovered from a single Endpoints object
+// (`name`, `namespace` and `port`), from static and DNS-based service
+// discovery (`staticConfigs` and `dnsSDConfigs`) or from an Alertmanager
+// object managed by the operator (`alertmanagerRef`).
+//
 // +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="[has(self.name), has(self.staticConfigs) || has(self.dnsSDConfigs), has(self.alertmanagerRef)].filter(x, x).size() == 1",message="exactly one of name, staticConfigs/dnsSDConfigs or alertmanagerRef must be defined"
+// +kubebuilder:validation:XValidation:rule="!has(self.name) || has(self.port)",message="port is required when name is defined"
+// +kubebuilder:validation:XValidation:rule="has(self.name) || !has(self.__namespace__)",message="namespace can only be defined when name is defined"
 type AlertmanagerEndpoints struct {
 	// namespace of the Endpoints object.
 	//
@@ -2317,13 +2869,52 @@ type AlertmanagerEndpoints struct {
 
 	// name of the Endpoints object in the namespace.
 	//
+	// Cannot be set at the same time as `staticConfigs`, `dnsSDConfigs` or `alertmanagerRef`.
+	//
 	// +kubebuilder:validation:MinLength:=1
-	// +required
-	Name string `json:"name"`
+	// +optional
+	Name string `json:"name,omitempty"`
 
 	// port on which the Alertmanager API is exposed.
-	// +required
-	Port intstr.IntOrString `json:"port"`
+	//
+	// It is required when `name` is defined.
+	//
+	// +optional
+	Port intstr.IntOrString `json:"port,omitempty,omitzero"`
+
+	// staticConfigs defines a static list of Alertmanager addresses.
+	//
+	// Cannot be set at the same time as `name` or `alertmanagerRef`.
+	//
+	// +listType:=atomic
+	// +optional
+	StaticConfigs []AlertmanagerStaticConfig `json:"staticConfigs,omitempty"`
+
+	// dnsSDConfigs defines DNS-based service discovery configurations for
+	// Alertmanager.
+	//
+	// Cannot be set at the same time as `name` or `alertmanagerRef`.
+	//
+	// +listType:=atomic
+	// +optional
+	DNSSDConfigs []AlertmanagerDNSSDConfig `json:"dnsSDConfigs,omitempty"`
+
+	// alertmanagerRef references an Alertmanager object managed by the
+	// operator.
+	//
+	// The operator discovers the pods of the Alertmanager and derives the
+	// scheme, the path prefix and the API version from its spec. When web TLS
+	// is enabled and the Alertmanager lives in the same namespace, the
+	// serving certificate is trusted and the server name is set to
+	// `<service>.<namespace>.svc`.
+	//
+	// The fields explicitly defined in the endpoints take precedence over the
+	// derived values.
+	//
+	// Cannot be set at the same time as `name`, `staticConfigs` or `dnsSDConfigs`.
+	//
+	// +optional
+	AlertmanagerRef *AlertmanagerReference `json:"alertmanagerRef,omitempty"`
 
 	// scheme defines the HTTP scheme to use when sending alerts.
 	//
@@ -2405,6 +2996,110 @@ type AlertmanagerEndpoints struct {
 	AlertRelabelConfigs []RelabelConfig `json:"alertRelabelings,omitempty"`
 }
 
+// PrometheusReference references a Prometheus object.
+//
+// +k8s:openapi-gen=true
+type PrometheusReference struct {
+	// name of the Prometheus object.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// namespace of the Prometheus object.
+	//
+	// If not set, the Prometheus object is looked up in the namespace of the
+	// referencing object.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// AlertmanagerReference references an Alertmanager object.
+//
+// +k8s:openapi-gen=true
+type AlertmanagerReference struct {
+	// name of the Alertmanager object.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// namespace of the Alertmanager object.
+	//
+	// If not set, the Alertmanager object is looked up in the namespace of
+	// the referencing object.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// AlertmanagerStaticConfig defines a static list of Alertmanager addresses.
+//
+// +k8s:openapi-gen=true
+type AlertmanagerStaticConfig struct {
+	// targets defines the list of Alertmanager addresses (`<host>:<port>`).
+	//
+	// If the port is omitted, Prometheus uses the default port of the scheme.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +required
+	Targets []string `json:"targets"`
+}
+
+// +kubebuilder:validation:Enum=SRV;A;AAAA
+type AlertmanagerDNSRecordType string
+
+const (
+	AlertmanagerDNSRecordTypeSRV  AlertmanagerDNSRecordType = "SRV"
+	AlertmanagerDNSRecordTypeA    AlertmanagerDNSRecordType = "A"
+	AlertmanagerDNSRecordTypeAAAA AlertmanagerDNSRecordType = "AAAA"
+)
+
+// AlertmanagerDNSSDConfig defines a set of DNS domain names which are
+// periodically queried to discover the Alertmanager addresses.
+//
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#dns_sd_config
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="!has(self.type) || self.type == 'SRV' || has(self.port)",message="port is required for A and AAAA records"
+type AlertmanagerDNSSDConfig struct {
+	// names defines the list of DNS domain names to be queried.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +required
+	Names []string `json:"names"`
+
+	// type defines the type of DNS query to perform.
+	//
+	// If not set, Prometheus uses its default value (SRV).
+	//
+	// +optional
+	Type *AlertmanagerDNSRecordType `json:"type,omitempty"`
+
+	// port defines the port of the Alertmanager API.
+	//
+	// It is required for A and AAAA records and ignored for SRV records.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// refreshInterval defines the time after which the names are refreshed.
+	//
+	// If not set, Prometheus uses its default value.
+	//
+	// +optional
+	RefreshInterval *Duration `json:"refreshInterval,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 type Rules struct {
 	// alert defines the parameters of the Prometheus rules' engine.
@@ -2477,6 +3172,11 @@ type ShardStatus struct {
 	// unavailableReplicas defines the Total number of unavailable pods targeted by this shard.
 	// +required
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// namespaces defines the namespaces assigned to this shard when the
+	// sharding strategy mode is 'Namespace' or 'Label'.
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 type TSDBSpec struct {
@@ -2695,6 +3395,87 @@ type ScrapeClass struct {
 	//
 	// +optional
 	AttachMetadata *AttachMetadata `json:"attachMetadata,omitempty"`
+
+	// scrapeInterval defines the default interval between consecutive scrapes.
+	// It will only apply if the scrape resource doesn't specify any interval.
+	//
+	// +optional
+	ScrapeInterval *Duration `json:"scrapeInterval,omitempty"`
+
+	// scrapeTimeout defines the default timeout after which a scrape is
+	// considered failed.
+	// It will only apply if the scrape resource doesn't specify any timeout.
+	//
+	// Like for the global scrape timeout, the value is ignored when it is
+	// greater than the scrape interval of the job.
+	//
+	// +optional
+	ScrapeTimeout *Duration `json:"scrapeTimeout,omitempty"`
+
+	// sampleLimit defines the default per-scrape limit on the number of scraped
+	// samples that will be accepted.
+	// It will only apply if the scrape resource doesn't specify any sampleLimit.
+	//
+	// The `spec.enforcedSampleLimit` value, if defined, takes precedence when
+	// it is lower.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	SampleLimit *int64 `json:"sampleLimit,omitempty"`
+
+	// targetLimit defines the default limit on the number of scraped targets
+	// that will be accepted.
+	// It will only apply if the scrape resource doesn't specify any targetLimit.
+	//
+	// The `spec.enforcedTargetLimit` value, if defined, takes precedence when
+	// it is lower.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	TargetLimit *int64 `json:"targetLimit,omitempty"`
+
+	// labelLimit defines the default per-scrape limit on the number of labels
+	// that will be accepted for a sample.
+	// It will only apply if the scrape resource doesn't specify any labelLimit.
+	//
+	// The `spec.enforcedLabelLimit` value, if defined, takes precedence when
+	// it is lower.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LabelLimit *int64 `json:"labelLimit,omitempty"`
+
+	// labelNameLengthLimit defines the default per-scrape limit on the length
+	// of label names that will be accepted for a sample.
+	// It will only apply if the scrape resource doesn't specify any labelNameLengthLimit.
+	//
+	// The `spec.enforcedLabelNameLengthLimit` value, if defined, takes
+	// precedence when it is lower.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LabelNameLengthLimit *int64 `json:"labelNameLengthLimit,omitempty"`
+
+	// labelValueLengthLimit defines the default per-scrape limit on the length
+	// of label values that will be accepted for a sample.
+	// It will only apply if the scrape resource doesn't specify any labelValueLengthLimit.
+	//
+	// The `spec.enforcedLabelValueLengthLimit` value, if defined, takes
+	// precedence when it is lower.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LabelValueLengthLimit *int64 `json:"labelValueLengthLimit,omitempty"`
+
+	// bodySizeLimit defines the default job level limit on the size of
+	// uncompressed response body that will be accepted by Prometheus.
+	// It will only apply if the scrape resource doesn't specify any bodySizeLimit.
+	//
+	// The `spec.enforcedBodySizeLimit` value, if defined, takes precedence
+	// when it is lower.
+	//
+	// +optional
+	BodySizeLimit *ByteSize `json:"bodySizeLimit,omitempty"`
 }
 
 // TranslationStrategyOption represents a translation strategy option for the OTLP endpoint.
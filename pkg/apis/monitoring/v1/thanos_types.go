@@ -240,15 +240,52 @@ type ThanosRulerSpec struct {
 	// +optional
 	UpdateStrategy *StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// podDisruptionBudget defines the PodDisruptionBudget protecting the
+	// pods. When defined, the operator creates one PodDisruptionBudget per
+	// StatefulSet (e.g. per shard) and deletes it when the StatefulSet is
+	// removed or when the field is unset.
+	//
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// networkPolicy defines the NetworkPolicy generated by the operator for
+	// the pods. When defined, the operator allows the ingress traffic to the
+	// exposed ports and the egress traffic to the destinations it knows
+	// about (DNS, selected namespaces, in-cluster endpoints, ...). The
+	// NetworkPolicy is deleted when the field is unset.
+	//
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
 	// queryEndpoints defines the list of Thanos Query endpoints from which to query metrics.
 	//
 	// For Thanos >= v0.11.0, it is recommended to use `queryConfig` instead.
 	//
-	// `queryConfig` takes precedence over this field.
+	// `queryConfig` and `queryEndpointRefs` take precedence over this field.
 	//
 	// +optional
 	QueryEndpoints []string `json:"queryEndpoints,omitempty"`
 
+	// queryEndpointRefs defines the list of Prometheus objects from which to
+	// query metrics.
+	//
+	// The operator generates the query configuration targeting the pods of
+	// the referenced Prometheus objects. The scheme and the route prefix are
+	// derived from the web configuration of each Prometheus. When web TLS is
+	// enabled and the Prometheus lives in the same namespace as the
+	// ThanosRuler, the serving certificate is trusted.
+	//
+	// The referenced Prometheus objects can't be sharded.
+	//
+	// It requires Thanos >= v0.11.0.
+	//
+	// `queryConfig` takes precedence over this field.
+	// This field takes precedence over `queryEndpoints`.
+	//
+	// +listType=atomic
+	// +optional
+	QueryEndpointRefs []PrometheusReference `json:"queryEndpointRefs,omitempty"`
+
 	// queryConfig defines the list of Thanos Query endpoints from which to query metrics.
 	//
 	// The configuration format is defined at https://thanos.io/tip/components/rule.md/#query-api
@@ -257,7 +294,7 @@ type ThanosRulerSpec struct {
 	//
 	// The operator performs no validation of the configuration.
 	//
-	// This field takes precedence over `queryEndpoints`.
+	// This field takes precedence over `queryEndpointRefs` and `queryEndpoints`.
 	//
 	// +optional
 	QueryConfig *v1.SecretKeySelector `json:"queryConfig,omitempty"`
@@ -266,7 +303,9 @@ type ThanosRulerSpec struct {
 	//
 	// For Thanos >= v0.10.0, it is recommended to use `alertmanagersConfig` instead.
 	//
-	// `alertmanagersConfig` takes precedence over this field.
+	// Each URL must have an `http` or `https` scheme (optionally prefixed with `dns+`, `dnssrv+` or `dnssrvnoa+`) and a host.
+	//
+	// `alertmanagersConfig`, `alertmanagerRefs` and `alertmanagerEndpoints` take precedence over this field.
 	//
 	// +optional
 	AlertManagersURL []string `json:"alertmanagersUrl,omitempty"`
@@ -276,12 +315,46 @@ type ThanosRulerSpec struct {
 	//
 	// It requires Thanos >= v0.10.0.
 	//
-	// The operator performs no validation of the configuration.
+	// The operator checks the addresses, scheme, API version and timeout of the Alertmanager endpoints. An invalid configuration is reported in the operator logs but it doesn't fail the reconciliation.
 	//
-	// This field takes precedence over `alertmanagersUrl`.
+	// This field takes precedence over `alertmanagerRefs`, `alertmanagerEndpoints` and `alertmanagersUrl`.
 	//
 	// +optional
 	AlertManagersConfig *v1.SecretKeySelector `json:"alertmanagersConfig,omitempty"`
+	// alertmanagerRefs defines the Alertmanager objects managed by the operator
+	// to send alerts to.
+	//
+	// The operator generates the Alertmanager configuration from the spec of
+	// the Alertmanager objects (addresses of the pods, scheme, path prefix and
+	// API version). When web TLS is enabled and the Alertmanager lives in the
+	// same namespace, the serving certificate is trusted and the server name
+	// is set to `<service>.<namespace>.svc`.
+	//
+	// It requires Thanos >= v0.10.0.
+	//
+	// It can be combined with `alertmanagerEndpoints`.
+	//
+	// `alertmanagersConfig` takes precedence over this field.
+	// This field takes precedence over `alertmanagersUrl`.
+	//
+	// +listType=atomic
+	// +optional
+	AlertmanagerRefs []AlertmanagerReference `json:"alertmanagerRefs,omitempty"`
+	// alertmanagerEndpoints defines the Alertmanager endpoints which aren't
+	// managed by the operator (e.g. running outside of the cluster) to send
+	// alerts to.
+	//
+	// The operator generates the Alertmanager configuration from the
+	// endpoints. It can be combined with `alertmanagerRefs`.
+	//
+	// It requires Thanos >= v0.10.0.
+	//
+	// `alertmanagersConfig` takes precedence over this field.
+	// This field takes precedence over `alertmanagersUrl`.
+	//
+	// +listType=atomic
+	// +optional
+	AlertmanagerEndpoints []ThanosRulerAlertmanagerEndpoints `json:"alertmanagerEndpoints,omitempty"`
 
 	// ruleSelector defines the PrometheusRule objects to be selected for rule evaluation. An empty
 	// label selector matches all objects. A null label selector matches no
@@ -397,6 +470,23 @@ type ThanosRulerSpec struct {
 	// +optional
 	InitContainers []v1.Container `json:"initContainers,omitempty"`
 
+	// overlays defines patches applied to the StatefulSet(s), Service(s) and
+	// Secret(s) generated by the operator. The patches are applied in order
+	// after the operator has built the objects. It can be used to modify
+	// fields which can't be configured otherwise (e.g. the metadata of the
+	// volume claim templates). The overlays don't apply to the default
+	// governing Service which is shared by all the resources of the namespace.
+	//
+	// A failing patch is reported in the `Reconciled` condition with the
+	// `OverlayFailed` reason.
+	//
+	// Overlays which modify fields managed by the operator require careful
+	// testing, especially when upgrading to a new version of the operator.
+	//
+	// +listType=atomic
+	// +optional
+	Overlays []Overlay `json:"overlays,omitempty"`
+
 	// tracingConfig defines the tracing configuration.
 	//
 	// The configuration format is defined at https://thanos.io/tip/thanos/tracing.md/#configuration
@@ -450,6 +540,14 @@ type ThanosRulerSpec struct {
 	// +optional
 	RoutePrefix string `json:"routePrefix,omitempty"`
 
+	// exposure defines how the web UI and API are exposed outside of the
+	// cluster. The operator generates the Service(s) and the Ingress or the
+	// Gateway API HTTPRoute(s) matching the route prefix and the web TLS
+	// configuration.
+	//
+	// +optional
+	Exposure *ExposureSpec `json:"exposure,omitempty"`
+
 	// grpcServerTlsConfig defines the gRPC server from which Thanos Querier reads
 	// recorded rule data.
 	//
@@ -564,6 +662,71 @@ type ThanosRulerSpec struct {
 }
 
 // ThanosRulerWebSpec defines the configuration of the ThanosRuler web server.
+// ThanosRulerAlertmanagerEndpoints defines Alertmanager endpoints discovered
+// from static addresses or DNS lookups.
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="has(self.staticConfigs) || has(self.dnsSDConfigs)",message="one of staticConfigs or dnsSDConfigs must be defined"
+type ThanosRulerAlertmanagerEndpoints struct {
+	// staticConfigs defines a static list of Alertmanager addresses.
+	//
+	// +listType:=atomic
+	// +optional
+	StaticConfigs []AlertmanagerStaticConfig `json:"staticConfigs,omitempty"`
+
+	// dnsSDConfigs defines DNS-based service discovery configurations for
+	// Alertmanager.
+	//
+	// The SRV records are resolved with the `dnssrv+` prefix and the A and
+	// AAAA records with the `dns+` prefix (which resolves both record types).
+	// The `refreshInterval` field isn't supported: Thanos Ruler refreshes the
+	// names at the interval defined by the `--alertmanagers.sd-dns-interval`
+	// argument.
+	//
+	// +kubebuilder:validation:XValidation:rule="self.all(c, !has(c.refreshInterval))",message="refreshInterval isn't supported by Thanos Ruler"
+	// +listType:=atomic
+	// +optional
+	DNSSDConfigs []AlertmanagerDNSSDConfig `json:"dnsSDConfigs,omitempty"`
+
+	// scheme defines the HTTP scheme to use when sending alerts.
+	//
+	// +optional
+	Scheme *Scheme `json:"scheme,omitempty"`
+
+	// pathPrefix defines the prefix for the HTTP path alerts are pushed to.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	PathPrefix *string `json:"pathPrefix,omitempty"`
+
+	// tlsConfig to use for Alertmanager.
+	//
+	// The `minVersion` and `maxVersion` fields aren't supported by Thanos
+	// Ruler.
+	//
+	// +kubebuilder:validation:XValidation:rule="!has(self.minVersion) && !has(self.maxVersion)",message="minVersion and maxVersion aren't supported by Thanos Ruler"
+	// +optional
+	TLSConfig *SafeTLSConfig `json:"tlsConfig,omitempty"`
+
+	// basicAuth configuration for Alertmanager.
+	//
+	// +optional
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
+
+	// apiVersion defines the version of the Alertmanager API that Thanos
+	// Ruler uses to send alerts.
+	//
+	// If not set, Thanos Ruler uses its default value.
+	//
+	// +optional
+	APIVersion *AlertmanagerAPIVersion `json:"apiVersion,omitempty"`
+
+	// timeout defines a per-target Alertmanager timeout when pushing alerts.
+	//
+	// +optional
+	Timeout *Duration `json:"timeout,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 type ThanosRulerWebSpec struct {
 	// +optional
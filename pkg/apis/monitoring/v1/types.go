@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -282,6 +284,31 @@ const (
 	// - False: the controller rejected the configuration due to an error.
 	// - Unknown: the operator couldn't determine the condition status.
 	Accepted ConditionType = "Accepted"
+	// StorageResized indicates whether the persistent volume claims have the
+	// storage capacity requested by the volume claim template. It is only
+	// reported when persistent storage is configured.
+	// The possible status values for this condition type are:
+	// - True: all bound persistent volume claims have the requested capacity.
+	// - False: some persistent volume claims are being resized.
+	// - Unknown: the operator couldn't determine the condition status.
+	StorageResized ConditionType = "StorageResized"
+	// StorageMigrated indicates whether the persistent volume claims use the
+	// storage class of the volume claim template. It is only reported when
+	// the storage migration policy is `Copy`.
+	// The possible status values for this condition type are:
+	// - True: all persistent volume claims use the requested storage class.
+	// - False: some persistent volume claims are being migrated.
+	// - Unknown: the operator couldn't determine the condition status.
+	StorageMigrated ConditionType = "StorageMigrated"
+	// RolledOut indicates whether the changes have been rolled out to all
+	// the shards. It is only reported when a shard rollout strategy is
+	// defined.
+	// The possible status values for this condition type are:
+	// - True: all shards are available and run the latest revision.
+	// - False: the rollout is in progress or it has been paused because a
+	// shard didn't become available within the progress deadline.
+	// - Unknown: the operator couldn't determine the condition status.
+	RolledOut ConditionType = "RolledOut"
 )
 
 // +kubebuilder:validation:MinLength=1
@@ -917,6 +944,14 @@ type WorkloadBinding struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []ConfigResourceCondition `json:"conditions,omitempty"`
+	// shards defines the shards of the workload evaluating the rules of the
+	// configuration resource.
+	// It is only set for PrometheusRule resources when the Prometheus
+	// workload doesn't evaluate all the rules on every shard (see
+	// `spec.ruleEvaluation`).
+	// +listType=set
+	// +optional
+	Shards []int32 `json:"shards,omitempty"`
 }
 
 // ConfigResourceCondition describes the status of configuration resources linked to Prometheus, PrometheusAgent, Alertmanager or ThanosRuler.
@@ -1018,6 +1053,282 @@ type RollingUpdateStatefulSetStrategy struct {
 	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty" protobuf:"varint,2,opt,name=maxUnavailable"`
 }
 
+// PodDisruptionBudgetSpec defines the PodDisruptionBudget created by the
+// operator for the pods of a StatefulSet.
+//
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetSpec struct {
+	// minAvailable defines the number of pods of the StatefulSet which must
+	// still be available after an eviction. The value can be an absolute
+	// number (ex: 1) or a percentage of the replicas (ex: 50%).
+	//
+	// It is mutually exclusive with `maxUnavailable`.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// maxUnavailable defines the number of pods of the StatefulSet which can
+	// be unavailable after an eviction. The value can be an absolute number
+	// (ex: 1) or a percentage of the replicas (ex: 50%).
+	//
+	// It is mutually exclusive with `minAvailable`. If neither `minAvailable`
+	// nor `maxUnavailable` is defined, the operator assumes a value of 1.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// unhealthyPodEvictionPolicy defines the criteria for when unhealthy
+	// pods should be considered for eviction.
+	//
+	// If not defined, the Kubernetes default (`IfHealthyBudget`) applies.
+	//
+	// +kubebuilder:validation:Enum=IfHealthyBudget;AlwaysAllow
+	// +optional
+	UnhealthyPodEvictionPolicy *policyv1.UnhealthyPodEvictionPolicyType `json:"unhealthyPodEvictionPolicy,omitempty"`
+}
+
+// ExposureHostMode defines how the pods are mapped to host names.
+//
+// +kubebuilder:validation:Enum=Single;PerShard;PerReplica
+type ExposureHostMode string
+
+const (
+	// SingleExposureHostMode exposes all the pods under the same host name.
+	SingleExposureHostMode ExposureHostMode = "Single"
+	// PerShardExposureHostMode exposes the pods of each shard under a
+	// dedicated host name.
+	PerShardExposureHostMode ExposureHostMode = "PerShard"
+	// PerReplicaExposureHostMode exposes each pod under a dedicated host
+	// name.
+	PerReplicaExposureHostMode ExposureHostMode = "PerReplica"
+)
+
+// ExposureSpec defines how the web UI and API are exposed outside of the
+// cluster.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.ingress) != has(self.httpRoute)",message="exactly one of ingress and httpRoute must be defined"
+type ExposureSpec struct {
+	// host defines the DNS name under which the web UI and API are
+	// reachable.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern:=^[0-9a-zA-Z-.]+$
+	// +required
+	Host string `json:"host"`
+
+	// hostMode defines how the pods are mapped to host names.
+	//
+	// * `Single`: all the pods are reachable at `<host>`.
+	// * `PerShard`: the pods of each shard are reachable at
+	//   `<statefulset name>.<host>`. It is equivalent to `Single` for
+	//   workloads which don't support sharding.
+	// * `PerReplica`: each pod is reachable at `<pod name>.<host>`.
+	//
+	// If not defined, the operator assumes `Single`.
+	//
+	// +optional
+	HostMode *ExposureHostMode `json:"hostMode,omitempty"`
+
+	// ingress defines the Ingress exposing the pods.
+	//
+	// It is mutually exclusive with `httpRoute`.
+	//
+	// +optional
+	Ingress *IngressExposure `json:"ingress,omitempty"`
+
+	// httpRoute defines the Gateway API HTTPRoute exposing the pods. It
+	// requires the Gateway API CRDs to be installed.
+	//
+	// It is mutually exclusive with `ingress`.
+	//
+	// +optional
+	HTTPRoute *HTTPRouteExposure `json:"httpRoute,omitempty"`
+}
+
+// IngressExposure defines the Ingress generated by the operator.
+type IngressExposure struct {
+	// ingressClassName defines the name of the IngressClass.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// annotations defines the annotations added to the Ingress (e.g. for
+	// controller-specific settings).
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// tlsSecretName defines the name of the Secret holding the certificate
+	// for the host names. When defined, the Ingress terminates TLS and the
+	// external URL uses the `https` scheme.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	TLSSecretName *string `json:"tlsSecretName,omitempty"`
+}
+
+// HTTPRouteExposure defines the Gateway API HTTPRoute generated by the
+// operator.
+type HTTPRouteExposure struct {
+	// parentRefs defines the Gateways to which the HTTPRoute is attached.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	// +required
+	ParentRefs []GatewayParentReference `json:"parentRefs"`
+
+	// scheme defines the scheme of the external URL. It should be `HTTPS`
+	// when the Gateway listener terminates TLS.
+	//
+	// If not defined, the operator assumes `HTTPS`.
+	//
+	// +optional
+	Scheme *Scheme `json:"scheme,omitempty"`
+
+	// backendTLS defines how the Gateway validates the serving certificate
+	// of the pods. It is only used when the web server of the pods has TLS
+	// enabled and it requires the BackendTLSPolicy CRD to be installed.
+	//
+	// +optional
+	BackendTLS *BackendTLSValidation `json:"backendTLS,omitempty"`
+}
+
+// GatewayParentReference identifies a Gateway (and optionally one of its
+// listeners).
+type GatewayParentReference struct {
+	// name of the Gateway.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// namespace of the Gateway. If not defined, the namespace of the
+	// resource is used.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// sectionName defines the name of the Gateway listener.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// BackendTLSValidation defines how the Gateway validates the certificate
+// presented by the pods.
+type BackendTLSValidation struct {
+	// caCertificateRefs defines the ConfigMaps holding the CA certificates
+	// (in the `ca.crt` key). If empty, the system CA certificates are used.
+	//
+	// +listType=atomic
+	// +optional
+	CACertificateRefs []v1.LocalObjectReference `json:"caCertificateRefs,omitempty"`
+
+	// hostname defines the name which the Gateway uses for SNI and to
+	// validate the certificate. If not defined, the DNS name of the
+	// generated Service (`<service>.<namespace>.svc`) is used.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Hostname *string `json:"hostname,omitempty"`
+}
+
+// NetworkPolicySpec defines the NetworkPolicy created by the operator for the
+// pods of a workload.
+type NetworkPolicySpec struct {
+	// ingressFrom defines the sources which are allowed to reach the ports
+	// exposed by the pods (e.g. the web and gRPC ports).
+	//
+	// If empty, all sources are allowed.
+	//
+	// +listType=atomic
+	// +optional
+	IngressFrom []networkingv1.NetworkPolicyPeer `json:"ingressFrom,omitempty"`
+
+	// additionalEgress defines egress rules appended to the rules generated
+	// by the operator. It should be used for destinations which the operator
+	// can't infer such as external receivers, object storage or targets
+	// discovered with additional scrape configurations.
+	//
+	// The pods running in the host network (e.g. kubelet or node-exporter)
+	// aren't matched by the generated rules: they need an `ipBlock` rule
+	// covering the node IP addresses.
+	//
+	// +listType=atomic
+	// +optional
+	AdditionalEgress []networkingv1.NetworkPolicyEgressRule `json:"additionalEgress,omitempty"`
+}
+
+// OverlayTargetKind defines the kind of the generated objects which can be
+// patched by an overlay.
+//
+// +kubebuilder:validation:Enum=StatefulSet;Service;Secret
+type OverlayTargetKind string
+
+const (
+	StatefulSetOverlayTargetKind OverlayTargetKind = "StatefulSet"
+	ServiceOverlayTargetKind     OverlayTargetKind = "Service"
+	SecretOverlayTargetKind      OverlayTargetKind = "Secret"
+)
+
+// OverlayPatchType defines the format of an overlay patch.
+//
+// +kubebuilder:validation:Enum=JSONPatch;StrategicMerge
+type OverlayPatchType string
+
+const (
+	// JSONPatchOverlayPatchType is a list of RFC 6902 JSON patch operations.
+	JSONPatchOverlayPatchType OverlayPatchType = "JSONPatch"
+	// StrategicMergeOverlayPatchType is a partial object merged into the
+	// generated object with the Kubernetes strategic merge patch semantics.
+	StrategicMergeOverlayPatchType OverlayPatchType = "StrategicMerge"
+)
+
+// Overlay defines a patch applied to the objects generated by the operator.
+type Overlay struct {
+	// target selects the generated objects to which the patch applies.
+	//
+	// +required
+	Target OverlayTarget `json:"target"`
+
+	// type defines the format of the patch.
+	//
+	// * `JSONPatch`: the patch is a list of RFC 6902 JSON patch operations.
+	// * `StrategicMerge`: the patch is a partial object merged with the
+	//   Kubernetes strategic merge patch semantics.
+	//
+	// If not defined, the operator assumes `JSONPatch`.
+	//
+	// +optional
+	Type *OverlayPatchType `json:"type,omitempty"`
+
+	// patch defines the content of the patch in YAML or JSON format.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Patch string `json:"patch"`
+}
+
+// OverlayTarget selects the generated objects patched by an overlay.
+type OverlayTarget struct {
+	// kind defines the kind of the objects.
+	//
+	// +required
+	Kind OverlayTargetKind `json:"kind"`
+
+	// name defines the name of the object. If not defined, the patch applies
+	// to all the generated objects of the given kind.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Name *string `json:"name,omitempty"`
+}
+
 // StatefulSetUpdateStrategyType is a string enumeration type that enumerates
 // all possible update strategies for the StatefulSet pods.
 //
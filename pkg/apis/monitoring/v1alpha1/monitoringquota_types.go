@@ -0,0 +1,163 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	MonitoringQuotasKind   = "MonitoringQuota"
+	MonitoringQuotaName    = "monitoringquotas"
+	MonitoringQuotaKindKey = "monitoringquota"
+)
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="prometheus-operator",shortName="mquota"
+// +kubebuilder:storageversion
+
+// MonitoringQuota defines the limits applying to the monitoring resources
+// (ServiceMonitor, PodMonitor, Probe, ScrapeConfig and PrometheusRule) of
+// its namespace.
+//
+// The limits are enforced for each workload (Prometheus, PrometheusAgent and
+// ThanosRuler) selecting the resources: the objects exceeding the quota
+// aren't selected and the `Accepted` condition of their status binding is
+// set to false with the `QuotaExceeded` reason.
+//
+// When a namespace contains several MonitoringQuota objects, the lowest
+// value of each limit applies.
+type MonitoringQuota struct {
+	// TypeMeta defines the versioned schema of this representation of an object.
+	metav1.TypeMeta `json:",inline"`
+	// metadata defines ObjectMeta as the metadata that all persisted resources.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// spec defines the specification of MonitoringQuotaSpec.
+	// +required
+	Spec MonitoringQuotaSpec `json:"spec"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *MonitoringQuota) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// MonitoringQuotaList is a list of MonitoringQuotas.
+// +k8s:openapi-gen=true
+type MonitoringQuotaList struct {
+	// TypeMeta defines the versioned schema of this representation of an object.
+	metav1.TypeMeta `json:",inline"`
+	// metadata defines ListMeta as metadata for collection responses.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of MonitoringQuotas
+	// +required
+	Items []MonitoringQuota `json:"items"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *MonitoringQuotaList) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// MonitoringQuotaSpec is a specification of the limits applying to the
+// monitoring resources of a namespace.
+// +k8s:openapi-gen=true
+type MonitoringQuotaSpec struct {
+	// serviceMonitors defines the maximum number of ServiceMonitor objects
+	// from the namespace which can be selected by a workload.
+	//
+	// The objects are admitted by order of creation.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ServiceMonitors *int32 `json:"serviceMonitors,omitempty"`
+
+	// podMonitors defines the maximum number of PodMonitor objects from the
+	// namespace which can be selected by a workload.
+	//
+	// The objects are admitted by order of creation.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PodMonitors *int32 `json:"podMonitors,omitempty"`
+
+	// probes defines the maximum number of Probe objects from the namespace
+	// which can be selected by a workload.
+	//
+	// The objects are admitted by order of creation.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Probes *int32 `json:"probes,omitempty"`
+
+	// scrapeConfigs defines the maximum number of ScrapeConfig objects from
+	// the namespace which can be selected by a workload.
+	//
+	// The objects are admitted by order of creation.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ScrapeConfigs *int32 `json:"scrapeConfigs,omitempty"`
+
+	// prometheusRules defines the maximum number of PrometheusRule objects
+	// from the namespace which can be selected by a workload.
+	//
+	// The objects are admitted by order of creation.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PrometheusRules *int32 `json:"prometheusRules,omitempty"`
+
+	// rules defines the maximum number of alerting and recording rules
+	// across the PrometheusRule objects from the namespace which are selected
+	// by a workload.
+	//
+	// A PrometheusRule object which would exceed the limit is rejected as a
+	// whole.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Rules *int32 `json:"rules,omitempty"`
+
+	// sampleLimit defines the maximum per-scrape sample limit of the
+	// ServiceMonitor, PodMonitor, Probe and ScrapeConfig objects from the
+	// namespace.
+	//
+	// When defined, the effective sample limit of the objects must be lower
+	// than or equal to this value, otherwise they are rejected. The effective
+	// limit takes into account the scrape class and the `sampleLimit` and
+	// `enforcedSampleLimit` fields of the Prometheus resource.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SampleLimit *int64 `json:"sampleLimit,omitempty"`
+
+	// targetLimit defines the maximum per-scrape target limit of the
+	// ServiceMonitor, PodMonitor, Probe and ScrapeConfig objects from the
+	// namespace.
+	//
+	// When defined, the effective target limit of the objects must be lower
+	// than or equal to this value, otherwise they are rejected. The effective
+	// limit takes into account the scrape class and the `targetLimit` and
+	// `enforcedTargetLimit` fields of the Prometheus resource.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TargetLimit *int64 `json:"targetLimit,omitempty"`
+}
@@ -0,0 +1,174 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const (
+	PrometheusOperatorDefaultsKind    = "PrometheusOperatorDefaults"
+	PrometheusOperatorDefaultsName    = "prometheusoperatordefaults"
+	PrometheusOperatorDefaultsKindKey = "prometheusoperatordefaults"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="prometheus-operator",scope=Cluster,shortName="podefaults"
+// +kubebuilder:storageversion
+
+// PrometheusOperatorDefaults defines cluster-wide default values for the
+// workloads (Alertmanager, Prometheus, PrometheusAgent and ThanosRuler)
+// managed by the operator.
+//
+// The operator only uses the object whose name is given by the
+// `--workload-defaults` command-line argument. At each reconciliation, the
+// default values are merged beneath the fields of the workload's spec: a
+// default value applies only when the field isn't defined by the workload.
+type PrometheusOperatorDefaults struct {
+	// TypeMeta defines the versioned schema of this representation of an object.
+	metav1.TypeMeta `json:",inline"`
+	// metadata defines ObjectMeta as the metadata that all persisted resources.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// spec defines the specification of PrometheusOperatorDefaultsSpec.
+	// +required
+	Spec PrometheusOperatorDefaultsSpec `json:"spec"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *PrometheusOperatorDefaults) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// PrometheusOperatorDefaultsList is a list of PrometheusOperatorDefaults.
+// +k8s:openapi-gen=true
+type PrometheusOperatorDefaultsList struct {
+	// TypeMeta defines the versioned schema of this representation of an object.
+	metav1.TypeMeta `json:",inline"`
+	// metadata defines ListMeta as metadata for collection responses.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of PrometheusOperatorDefaults
+	// +required
+	Items []PrometheusOperatorDefaults `json:"items"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *PrometheusOperatorDefaultsList) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// PrometheusOperatorDefaultsSpec is a specification of the default values
+// for each kind of workload.
+// +k8s:openapi-gen=true
+type PrometheusOperatorDefaultsSpec struct {
+	// prometheus defines the default values of the Prometheus objects.
+	// +optional
+	Prometheus *PrometheusDefaults `json:"prometheus,omitempty"`
+
+	// prometheusAgent defines the default values of the PrometheusAgent
+	// objects.
+	// +optional
+	PrometheusAgent *PrometheusDefaults `json:"prometheusAgent,omitempty"`
+
+	// alertmanager defines the default values of the Alertmanager objects.
+	// +optional
+	Alertmanager *WorkloadDefaults `json:"alertmanager,omitempty"`
+
+	// thanosRuler defines the default values of the ThanosRuler objects.
+	// +optional
+	ThanosRuler *WorkloadDefaults `json:"thanosRuler,omitempty"`
+}
+
+// WorkloadDefaults defines the default values which are common to all kinds
+// of workloads.
+// +k8s:openapi-gen=true
+type WorkloadDefaults struct {
+	// resources defines the default resource requirements of the main
+	// container. It applies when the workload defines neither requests nor
+	// limits.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// securityContext defines the default pod-level security attributes. It
+	// applies when the workload doesn't define `securityContext`.
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// tolerations defines the default pod's tolerations. They apply when the
+	// workload doesn't define any toleration.
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// priorityClassName defines the default priority class assigned to the
+	// pods. It applies when the workload doesn't define `priorityClassName`.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+}
+
+// PrometheusDefaults defines the default values of the Prometheus and
+// PrometheusAgent objects.
+// +k8s:openapi-gen=true
+type PrometheusDefaults struct {
+	WorkloadDefaults `json:",inline"`
+
+	// enforcedSampleLimit defines the default value of
+	// `enforcedSampleLimit`.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	EnforcedSampleLimit *int64 `json:"enforcedSampleLimit,omitempty"`
+
+	// enforcedTargetLimit defines the default value of
+	// `enforcedTargetLimit`.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	EnforcedTargetLimit *int64 `json:"enforcedTargetLimit,omitempty"`
+
+	// enforcedLabelLimit defines the default value of `enforcedLabelLimit`.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	EnforcedLabelLimit *int64 `json:"enforcedLabelLimit,omitempty"`
+
+	// enforcedLabelNameLengthLimit defines the default value of
+	// `enforcedLabelNameLengthLimit`.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	EnforcedLabelNameLengthLimit *int64 `json:"enforcedLabelNameLengthLimit,omitempty"`
+
+	// enforcedLabelValueLengthLimit defines the default value of
+	// `enforcedLabelValueLengthLimit`.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	EnforcedLabelValueLengthLimit *int64 `json:"enforcedLabelValueLengthLimit,omitempty"`
+
+	// enforcedKeepDroppedTargets defines the default value of
+	// `enforcedKeepDroppedTargets`.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	EnforcedKeepDroppedTargets *int64 `json:"enforcedKeepDroppedTargets,omitempty"`
+
+	// enforcedBodySizeLimit defines the default value of
+	// `enforcedBodySizeLimit`.
+	// +optional
+	EnforcedBodySizeLimit *monitoringv1.ByteSize `json:"enforcedBodySizeLimit,omitempty"`
+}
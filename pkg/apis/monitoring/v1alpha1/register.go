@@ -49,8 +49,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&AlertmanagerConfig{},
 		&AlertmanagerConfigList{},
+		&MonitoringQuota{},
+		&MonitoringQuotaList{},
 		&PrometheusAgent{},
 		&PrometheusAgentList{},
+		&PrometheusOperatorDefaults{},
+		&PrometheusOperatorDefaultsList{},
 		&ScrapeConfig{},
 		&ScrapeConfigList{},
 	)
@@ -44,6 +44,10 @@ type StoreBuilder struct {
 	refTracker RefTracker
 
 	tlsAssetKeys map[tlsAssetKey]struct{}
+
+	// versions records the resource versions of the objects read via
+	// ForNamespace() when not nil.
+	versions AssetVersions
 }
 
 // NewTestStoreBuilder returns a *StoreBuilder already initialized with the
@@ -262,6 +266,23 @@ func (s *StoreBuilder) AddAzureOAuth(ctx context.Context, ns string, azureAD *mo
 	return nil
 }
 
+// AddGoogleIAM processes the GoogleIAM SecretKeySelector and adds the
+// credentials file to the TLS assets because Prometheus reads the
+// credentials from a file.
+func (s *StoreBuilder) AddGoogleIAM(ctx context.Context, ns string, googleIAM *monitoringv1.GoogleIAM) error {
+	if googleIAM == nil || googleIAM.Credentials == nil {
+		return nil
+	}
+
+	if _, err := s.GetSecretKey(ctx, ns, *googleIAM.Credentials); err != nil {
+		return fmt.Errorf("failed to read GoogleIAM credentials: %w", err)
+	}
+
+	s.tlsAssetKeys[tlsAssetKeyFromSecretSelector(ns, googleIAM.Credentials)] = struct{}{}
+
+	return nil
+}
+
 // GetKey processes the given SecretOrConfigMap selector and returns the referenced data.
 func (s *StoreBuilder) GetKey(ctx context.Context, namespace string, sel monitoringv1.SecretOrConfigMap) (string, error) {
 	switch {
@@ -356,29 +377,33 @@ func (s *StoreBuilder) ForNamespace(namespace string) StoreGetter {
 		panic("namespace can't be empty")
 	}
 	return &cacheOnlyStore{
-		ns: namespace,
-		c:  s.objStore,
+		ns:       namespace,
+		c:        s.objStore,
+		versions: s.versions,
 	}
 }
 
 type cacheOnlyStore struct {
-	ns string
-	c  cache.Store
+	ns       string
+	c        cache.Store
+	versions AssetVersions
 }
 
 var _ = StoreGetter(&cacheOnlyStore{})
 
 func (cos *cacheOnlyStore) GetConfigMapKey(sel corev1.ConfigMapKeySelector) (string, error) {
-	obj, exists, err := cos.c.Get(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: sel.Name, Namespace: cos.ns}})
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: sel.Name, Namespace: cos.ns}}
+	obj, exists, err := cos.c.Get(cm)
 	if err != nil {
 		return "", fmt.Errorf("failed to get configmap %s/%s: %w", cos.ns, sel.Name, err)
 	}
+	cos.versions.record(configMapKey(cm), obj, exists)
 
 	if !exists {
 		return "", fmt.Errorf("configmap %s/%s not found", cos.ns, sel.Name)
 	}
 
-	cm := obj.(*corev1.ConfigMap)
+	cm = obj.(*corev1.ConfigMap)
 	if _, found := cm.Data[sel.Key]; !found {
 		return "", fmt.Errorf("key %q in configmap %s/%s not found", sel.Key, cos.ns, sel.Name)
 	}
@@ -387,16 +412,18 @@ func (cos *cacheOnlyStore) GetConfigMapKey(sel corev1.ConfigMapKeySelector) (str
 }
 
 func (cos *cacheOnlyStore) GetSecretKey(sel corev1.SecretKeySelector) ([]byte, error) {
-	obj, exists, err := cos.c.Get(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: sel.Name, Namespace: cos.ns}})
+	s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: sel.Name, Namespace: cos.ns}}
+	obj, exists, err := cos.c.Get(s)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get secret %s/%s: %w", cos.ns, sel.Name, err)
 	}
+	cos.versions.record(secretKey(s), obj, exists)
 
 	if !exists {
 		return nil, fmt.Errorf("secret %s/%s not found", cos.ns, sel.Name)
 	}
 
-	s := obj.(*corev1.Secret)
+	s = obj.(*corev1.Secret)
 	if _, found := s.Data[sel.Key]; !found {
 		return nil, fmt.Errorf("key %q in secret %s/%s not found", sel.Key, cos.ns, sel.Name)
 	}
@@ -1171,6 +1171,87 @@ func TestAddAzureOAuth(t *testing.T) {
 	}
 }
 
+func TestAddGoogleIAM(t *testing.T) {
+	c := fake.NewClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"credentials.json": []byte("{}"),
+			},
+		},
+	)
+
+	for _, tc := range []struct {
+		title        string
+		ns           string
+		selectedName string
+		key          string
+
+		err bool
+	}{
+		{
+			title:        "valid credentials key",
+			ns:           "ns1",
+			selectedName: "secret",
+			key:          "credentials.json",
+		},
+		{
+			title:        "default credentials",
+			ns:           "ns1",
+			selectedName: "",
+		},
+		{
+			title:        "wrong namespace",
+			ns:           "ns2",
+			selectedName: "secret",
+			key:          "credentials.json",
+
+			err: true,
+		},
+		{
+			title:        "wrong key selector",
+			ns:           "ns1",
+			selectedName: "secret",
+			key:          "wrong-key",
+
+			err: true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			store := NewStoreBuilder(c.CoreV1(), c.CoreV1())
+
+			googleIAM := monitoringv1.GoogleIAM{}
+			if tc.selectedName != "" {
+				googleIAM.Credentials = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: tc.selectedName,
+					},
+					Key: tc.key,
+				}
+			}
+
+			err := store.AddGoogleIAM(context.Background(), tc.ns, &googleIAM)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if googleIAM.Credentials == nil {
+				require.Empty(t, store.TLSAssets())
+				return
+			}
+
+			// The credentials file is added to the TLS assets.
+			require.Equal(t, []byte("{}"), store.TLSAssets()[store.ForNamespace(tc.ns).TLSAsset(googleIAM.Credentials)])
+		})
+	}
+}
+
 func TestUpdateObject(t *testing.T) {
 	c := fake.NewClientset(
 		&corev1.Secret{
@@ -0,0 +1,79 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// absentVersion is the version recorded for objects which aren't present in
+// the store.
+const absentVersion = "<absent>"
+
+// AssetVersions records the resource versions of the secrets and configmaps
+// read from a store.
+type AssetVersions map[string]string
+
+// record saves the resource version of the object. It is a no-op if the map
+// is nil.
+func (v AssetVersions) record(key string, obj any, exists bool) {
+	if v == nil {
+		return
+	}
+
+	v[key] = objectVersion(obj, exists)
+}
+
+func objectVersion(obj any, exists bool) string {
+	if !exists {
+		return absentVersion
+	}
+
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return absentVersion
+	}
+
+	return objMeta.GetResourceVersion()
+}
+
+// WithAssetVersions returns a copy of the store which records in v the
+// resource versions of all the secrets and configmaps read by the getters
+// returned by ForNamespace().
+//
+// The returned store shares the underlying data with the original store.
+func (s *StoreBuilder) WithAssetVersions(v AssetVersions) *StoreBuilder {
+	sb := *s
+	sb.versions = v
+
+	return &sb
+}
+
+// MatchesAssetVersions returns true if the secrets and configmaps in the
+// store have the same resource versions as the recorded ones.
+func (s *StoreBuilder) MatchesAssetVersions(v AssetVersions) bool {
+	for key, version := range v {
+		obj, exists, err := s.objStore.GetByKey(key)
+		if err != nil {
+			return false
+		}
+
+		if objectVersion(obj, exists) != version {
+			return false
+		}
+	}
+
+	return true
+}
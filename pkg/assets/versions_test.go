@@ -0,0 +1,79 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAssetVersions(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "secret",
+			Namespace:       "ns1",
+			ResourceVersion: "1",
+		},
+		Data: map[string][]byte{
+			"key1": []byte("val1"),
+		},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cm",
+			Namespace:       "ns1",
+			ResourceVersion: "2",
+		},
+		Data: map[string]string{
+			"key1": "val1",
+		},
+	}
+	store := NewTestStoreBuilder(secret, cm)
+
+	// Reads from the original store aren't recorded.
+	_, err := store.ForNamespace("ns1").GetSecretKey(corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "secret"}, Key: "key1"})
+	require.NoError(t, err)
+
+	versions := AssetVersions{}
+	rs := store.WithAssetVersions(versions)
+
+	_, err = rs.ForNamespace("ns1").GetSecretKey(corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "secret"}, Key: "key1"})
+	require.NoError(t, err)
+	_, err = rs.ForNamespace("ns1").GetConfigMapKey(corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}, Key: "key1"})
+	require.NoError(t, err)
+	_, err = rs.ForNamespace("ns1").GetSecretKey(corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "key1"})
+	require.Error(t, err)
+
+	require.Len(t, versions, 3)
+	require.True(t, store.MatchesAssetVersions(versions))
+
+	// Updating a recorded secret invalidates the versions.
+	secret = secret.DeepCopy()
+	secret.ResourceVersion = "3"
+	require.NoError(t, store.UpdateObject(secret))
+	require.False(t, store.MatchesAssetVersions(versions))
+
+	// Creating a previously missing object invalidates the versions.
+	versions = AssetVersions{}
+	_, err = store.WithAssetVersions(versions).ForNamespace("ns1").GetSecretKey(corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "key1"})
+	require.Error(t, err)
+	require.True(t, store.MatchesAssetVersions(versions))
+
+	require.NoError(t, store.AddObject(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "ns1"}}))
+	require.False(t, store.MatchesAssetVersions(versions))
+}
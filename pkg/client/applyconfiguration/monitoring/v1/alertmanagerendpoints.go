@@ -25,8 +25,12 @@ import (
 // AlertmanagerEndpointsApplyConfiguration represents a declarative configuration of the AlertmanagerEndpoints type for use
 // with apply.
 //
-// AlertmanagerEndpoints defines a selection of a single Endpoints object
-// containing Alertmanager IPs to fire alerts against.
+// AlertmanagerEndpoints defines the Alertmanager IPs to fire alerts against.
+//
+// The Alertmanagers are either discovered from a single Endpoints object
+// (`name`, `namespace` and `port`), from static and DNS-based service
+// discovery (`staticConfigs` and `dnsSDConfigs`) or from an Alertmanager
+// object managed by the operator (`alertmanagerRef`).
 type AlertmanagerEndpointsApplyConfiguration struct {
 	// namespace of the Endpoints object.
 	//
@@ -34,9 +38,36 @@ type AlertmanagerEndpointsApplyConfiguration struct {
 	// Prometheus object.
 	Namespace *string `json:"namespace,omitempty"`
 	// name of the Endpoints object in the namespace.
+	//
+	// Cannot be set at the same time as `staticConfigs`, `dnsSDConfigs` or `alertmanagerRef`.
 	Name *string `json:"name,omitempty"`
 	// port on which the Alertmanager API is exposed.
+	//
+	// It is required when `name` is defined.
 	Port *intstr.IntOrString `json:"port,omitempty"`
+	// staticConfigs defines a static list of Alertmanager addresses.
+	//
+	// Cannot be set at the same time as `name` or `alertmanagerRef`.
+	StaticConfigs []AlertmanagerStaticConfigApplyConfiguration `json:"staticConfigs,omitempty"`
+	// dnsSDConfigs defines DNS-based service discovery configurations for
+	// Alertmanager.
+	//
+	// Cannot be set at the same time as `name` or `alertmanagerRef`.
+	DNSSDConfigs []AlertmanagerDNSSDConfigApplyConfiguration `json:"dnsSDConfigs,omitempty"`
+	// alertmanagerRef references an Alertmanager object managed by the
+	// operator.
+	//
+	// The operator discovers the pods of the Alertmanager and derives the
+	// scheme, the path prefix and the API version from its spec. When web TLS
+	// is enabled and the Alertmanager lives in the same namespace, the
+	// serving certificate is trusted and the server name is set to
+	// `<service>.<namespace>.svc`.
+	//
+	// The fields explicitly defined in the endpoints take precedence over the
+	// derived values.
+	//
+	// Cannot be set at the same time as `name`, `staticConfigs` or `dnsSDConfigs`.
+	AlertmanagerRef *AlertmanagerReferenceApplyConfiguration `json:"alertmanagerRef,omitempty"`
 	// scheme defines the HTTP scheme to use when sending alerts.
 	Scheme *monitoringv1.Scheme `json:"scheme,omitempty"`
 	// pathPrefix defines the prefix for the HTTP path alerts are pushed to.
@@ -110,6 +141,40 @@ func (b *AlertmanagerEndpointsApplyConfiguration) WithPort(value intstr.IntOrStr
 	return b
 }
 
+// WithStaticConfigs adds the given value to the StaticConfigs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the StaticConfigs field.
+func (b *AlertmanagerEndpointsApplyConfiguration) WithStaticConfigs(values ...*AlertmanagerStaticConfigApplyConfiguration) *AlertmanagerEndpointsApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithStaticConfigs")
+		}
+		b.StaticConfigs = append(b.StaticConfigs, *values[i])
+	}
+	return b
+}
+
+// WithDNSSDConfigs adds the given value to the DNSSDConfigs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the DNSSDConfigs field.
+func (b *AlertmanagerEndpointsApplyConfiguration) WithDNSSDConfigs(values ...*AlertmanagerDNSSDConfigApplyConfiguration) *AlertmanagerEndpointsApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithDNSSDConfigs")
+		}
+		b.DNSSDConfigs = append(b.DNSSDConfigs, *values[i])
+	}
+	return b
+}
+
+// WithAlertmanagerRef sets the AlertmanagerRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AlertmanagerRef field is set to the value of the last call.
+func (b *AlertmanagerEndpointsApplyConfiguration) WithAlertmanagerRef(value *AlertmanagerReferenceApplyConfiguration) *AlertmanagerEndpointsApplyConfiguration {
+	b.AlertmanagerRef = value
+	return b
+}
+
 // WithScheme sets the Scheme field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Scheme field is set to the value of the last call.
@@ -124,6 +124,12 @@ type AlertmanagerSpecApplyConfiguration struct {
 	// and the actual ExternalURL is still true, but the server serves requests
 	// under a different route prefix. For example for use with `kubectl proxy`.
 	RoutePrefix *string `json:"routePrefix,omitempty"`
+	// exposure defines how the web UI and API are exposed outside of the
+	// cluster. The operator generates the Service(s) and the Ingress or the
+	// Gateway API HTTPRoute(s) matching the route prefix and the web TLS
+	// configuration. When defined, the operator also derives the external
+	// URL from the host name and it takes precedence over `externalUrl`.
+	Exposure *ExposureSpecApplyConfiguration `json:"exposure,omitempty"`
 	// paused if set to true all actions on the underlying managed objects are not
 	// going to be performed, except for delete actions.
 	Paused *bool `json:"paused,omitempty"`
@@ -177,6 +183,17 @@ type AlertmanagerSpecApplyConfiguration struct {
 	//
 	// The default strategy is RollingUpdate.
 	UpdateStrategy *StatefulSetUpdateStrategyApplyConfiguration `json:"updateStrategy,omitempty"`
+	// podDisruptionBudget defines the PodDisruptionBudget protecting the
+	// pods. When defined, the operator creates one PodDisruptionBudget per
+	// StatefulSet (e.g. per shard) and deletes it when the StatefulSet is
+	// removed or when the field is unset.
+	PodDisruptionBudget *PodDisruptionBudgetSpecApplyConfiguration `json:"podDisruptionBudget,omitempty"`
+	// networkPolicy defines the NetworkPolicy generated by the operator for
+	// the pods. When defined, the operator allows the ingress traffic to the
+	// exposed ports and the egress traffic to the destinations it knows
+	// about (DNS, selected namespaces, in-cluster endpoints, ...). The
+	// NetworkPolicy is deleted when the field is unset.
+	NetworkPolicy *NetworkPolicySpecApplyConfiguration `json:"networkPolicy,omitempty"`
 	// containers allows injecting additional containers or modifying operator
 	// generated containers. This can be used to allow adding an authentication
 	// proxy to the Pods or to change the behavior of an operator generated
@@ -208,6 +225,19 @@ type AlertmanagerSpecApplyConfiguration struct {
 	// careful testing, especially when upgrading to a new version of the
 	// operator.
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// overlays defines patches applied to the StatefulSet(s), Service(s) and
+	// Secret(s) generated by the operator. The patches are applied in order
+	// after the operator has built the objects. It can be used to modify
+	// fields which can't be configured otherwise (e.g. the metadata of the
+	// volume claim templates). The overlays don't apply to the default
+	// governing Service which is shared by all the resources of the namespace.
+	//
+	// A failing patch is reported in the `Reconciled` condition with the
+	// `OverlayFailed` reason.
+	//
+	// Overlays which modify fields managed by the operator require careful
+	// testing, especially when upgrading to a new version of the operator.
+	Overlays []OverlayApplyConfiguration `json:"overlays,omitempty"`
 	// priorityClassName assigned to the Pods
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 	// additionalPeers allows injecting a set of additional Alertmanagers to peer with to form a highly available cluster.
@@ -493,6 +523,14 @@ func (b *AlertmanagerSpecApplyConfiguration) WithRoutePrefix(value string) *Aler
 	return b
 }
 
+// WithExposure sets the Exposure field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Exposure field is set to the value of the last call.
+func (b *AlertmanagerSpecApplyConfiguration) WithExposure(value *ExposureSpecApplyConfiguration) *AlertmanagerSpecApplyConfiguration {
+	b.Exposure = value
+	return b
+}
+
 // WithPaused sets the Paused field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Paused field is set to the value of the last call.
@@ -631,6 +669,22 @@ func (b *AlertmanagerSpecApplyConfiguration) WithUpdateStrategy(value *StatefulS
 	return b
 }
 
+// WithPodDisruptionBudget sets the PodDisruptionBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodDisruptionBudget field is set to the value of the last call.
+func (b *AlertmanagerSpecApplyConfiguration) WithPodDisruptionBudget(value *PodDisruptionBudgetSpecApplyConfiguration) *AlertmanagerSpecApplyConfiguration {
+	b.PodDisruptionBudget = value
+	return b
+}
+
+// WithNetworkPolicy sets the NetworkPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicy field is set to the value of the last call.
+func (b *AlertmanagerSpecApplyConfiguration) WithNetworkPolicy(value *NetworkPolicySpecApplyConfiguration) *AlertmanagerSpecApplyConfiguration {
+	b.NetworkPolicy = value
+	return b
+}
+
 // WithContainers adds the given value to the Containers field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Containers field.
@@ -651,6 +705,19 @@ func (b *AlertmanagerSpecApplyConfiguration) WithInitContainers(values ...corev1
 	return b
 }
 
+// WithOverlays adds the given value to the Overlays field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Overlays field.
+func (b *AlertmanagerSpecApplyConfiguration) WithOverlays(values ...*OverlayApplyConfiguration) *AlertmanagerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOverlays")
+		}
+		b.Overlays = append(b.Overlays, *values[i])
+	}
+	return b
+}
+
 // WithPriorityClassName sets the PriorityClassName field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the PriorityClassName field is set to the value of the last call.
@@ -177,6 +177,17 @@ type CommonPrometheusFieldsApplyConfiguration struct {
 	// When not defined, the operator defaults to the 'Address' mode which distributes
 	// targets based on a hash of the target address.
 	ShardingStrategy *ShardingStrategyApplyConfiguration `json:"shardingStrategy,omitempty"`
+	// shardRolloutStrategy defines how the operator rolls out the changes of
+	// the StatefulSets and of the Prometheus configuration across the shards.
+
+	//
+	// The configuration files of a shard are updated only when the shard is
+	// rolled out and the operator waits for the config-reloader sidecars to
+	// report a successful reload before moving on to the next shards. When set,
+	// each shard reads its own configuration file.
+	//
+	// When not defined, the operator updates all shards at the same time.
+	ShardRolloutStrategy *ShardRolloutStrategyApplyConfiguration `json:"shardRolloutStrategy,omitempty"`
 	// replicaExternalLabelName defines the name of Prometheus external label used to denote the replica name.
 	// The external label will _not_ be added when the field is set to the
 	// empty string (`""`).
@@ -335,6 +346,17 @@ type CommonPrometheusFieldsApplyConfiguration struct {
 	//
 	// The default strategy is RollingUpdate.
 	UpdateStrategy *StatefulSetUpdateStrategyApplyConfiguration `json:"updateStrategy,omitempty"`
+	// podDisruptionBudget defines the PodDisruptionBudget protecting the
+	// pods. When defined, the operator creates one PodDisruptionBudget per
+	// StatefulSet (e.g. per shard) and deletes it when the StatefulSet is
+	// removed or when the field is unset.
+	PodDisruptionBudget *PodDisruptionBudgetSpecApplyConfiguration `json:"podDisruptionBudget,omitempty"`
+	// networkPolicy defines the NetworkPolicy generated by the operator for
+	// the pods. When defined, the operator allows the ingress traffic to the
+	// exposed ports and the egress traffic to the destinations it knows
+	// about (DNS, selected namespaces, in-cluster endpoints, ...). The
+	// NetworkPolicy is deleted when the field is unset.
+	NetworkPolicy *NetworkPolicySpecApplyConfiguration `json:"networkPolicy,omitempty"`
 	// enableServiceLinks defines whether information about services should be injected into pod's environment variables
 	EnableServiceLinks *bool `json:"enableServiceLinks,omitempty"`
 	// containers allows injecting additional containers or modifying operator
@@ -368,6 +390,19 @@ type CommonPrometheusFieldsApplyConfiguration struct {
 	// careful testing, especially when upgrading to a new version of the
 	// operator.
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// overlays defines patches applied to the StatefulSet(s), Service(s) and
+	// Secret(s) generated by the operator. The patches are applied in order
+	// after the operator has built the objects. It can be used to modify
+	// fields which can't be configured otherwise (e.g. the metadata of the
+	// volume claim templates). The overlays don't apply to the default
+	// governing Service which is shared by all the resources of the namespace.
+	//
+	// A failing patch is reported in the `Reconciled` condition with the
+	// `OverlayFailed` reason.
+	//
+	// Overlays which modify fields managed by the operator require careful
+	// testing, especially when upgrading to a new version of the operator.
+	Overlays []OverlayApplyConfiguration `json:"overlays,omitempty"`
 	// additionalScrapeConfigs allows specifying a key of a Secret containing
 	// additional Prometheus scrape configurations. Scrape configurations
 	// specified are appended to the configurations generated by the Prometheus
@@ -658,6 +693,28 @@ type CommonPrometheusFieldsApplyConfiguration struct {
 	// If set, the value should be either "Endpoints" or "EndpointSlice".
 	// If unset, the operator assumes the "Endpoints" role.
 	ServiceDiscoveryRole *monitoringv1.ServiceDiscoveryRole `json:"serviceDiscoveryRole,omitempty"`
+	// operatorServiceDiscovery configures Prometheus to discover the static
+	// targets of ScrapeConfig (`spec.staticConfigs`) and Probe
+	// (`spec.targets.staticConfig`) resources from the HTTP service discovery
+	// endpoint served by the operator instead of writing them in the
+	// configuration. Changes to the targets are then picked up by Prometheus
+	// without configuration reload.
+	//
+	// It requires the operator to run with the `--http-sd.listen-address`
+	// and `--http-sd.url` arguments, otherwise the targets are written in the
+	// configuration.
+	//
+	// It requires Prometheus >= v2.21.0.
+	OperatorServiceDiscovery *OperatorServiceDiscoveryApplyConfiguration `json:"operatorServiceDiscovery,omitempty"`
+	// annotationDiscovery configures scrape jobs discovering the pods and the
+	// service endpoints annotated with `prometheus.io/scrape: "true"`. The
+	// `prometheus.io/port`, `prometheus.io/path` and `prometheus.io/scheme`
+	// annotations override the address, metrics path and scheme of the
+	// targets.
+	//
+	// The jobs honor the enforced namespace label, the sharding configuration
+	// and the enforced limits.
+	AnnotationDiscovery *AnnotationDiscoveryApplyConfiguration `json:"annotationDiscovery,omitempty"`
 	// tsdb defines the runtime reloadable configuration of the timeseries database(TSDB).
 	// It requires Prometheus >= v2.39.0 or PrometheusAgent >= v2.54.0.
 	TSDB *TSDBSpecApplyConfiguration `json:"tsdb,omitempty"`
@@ -838,6 +895,14 @@ func (b *CommonPrometheusFieldsApplyConfiguration) WithShardingStrategy(value *S
 	return b
 }
 
+// WithShardRolloutStrategy sets the ShardRolloutStrategy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ShardRolloutStrategy field is set to the value of the last call.
+func (b *CommonPrometheusFieldsApplyConfiguration) WithShardRolloutStrategy(value *ShardRolloutStrategyApplyConfiguration) *CommonPrometheusFieldsApplyConfiguration {
+	b.ShardRolloutStrategy = value
+	return b
+}
+
 // WithReplicaExternalLabelName sets the ReplicaExternalLabelName field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ReplicaExternalLabelName field is set to the value of the last call.
@@ -1172,6 +1237,22 @@ func (b *CommonPrometheusFieldsApplyConfiguration) WithUpdateStrategy(value *Sta
 	return b
 }
 
+// WithPodDisruptionBudget sets the PodDisruptionBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodDisruptionBudget field is set to the value of the last call.
+func (b *CommonPrometheusFieldsApplyConfiguration) WithPodDisruptionBudget(value *PodDisruptionBudgetSpecApplyConfiguration) *CommonPrometheusFieldsApplyConfiguration {
+	b.PodDisruptionBudget = value
+	return b
+}
+
+// WithNetworkPolicy sets the NetworkPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicy field is set to the value of the last call.
+func (b *CommonPrometheusFieldsApplyConfiguration) WithNetworkPolicy(value *NetworkPolicySpecApplyConfiguration) *CommonPrometheusFieldsApplyConfiguration {
+	b.NetworkPolicy = value
+	return b
+}
+
 // WithEnableServiceLinks sets the EnableServiceLinks field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the EnableServiceLinks field is set to the value of the last call.
@@ -1200,6 +1281,19 @@ func (b *CommonPrometheusFieldsApplyConfiguration) WithInitContainers(values ...
 	return b
 }
 
+// WithOverlays adds the given value to the Overlays field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Overlays field.
+func (b *CommonPrometheusFieldsApplyConfiguration) WithOverlays(values ...*OverlayApplyConfiguration) *CommonPrometheusFieldsApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOverlays")
+		}
+		b.Overlays = append(b.Overlays, *values[i])
+	}
+	return b
+}
+
 // WithAdditionalScrapeConfigs sets the AdditionalScrapeConfigs field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the AdditionalScrapeConfigs field is set to the value of the last call.
@@ -1542,6 +1636,22 @@ func (b *CommonPrometheusFieldsApplyConfiguration) WithServiceDiscoveryRole(valu
 	return b
 }
 
+// WithOperatorServiceDiscovery sets the OperatorServiceDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperatorServiceDiscovery field is set to the value of the last call.
+func (b *CommonPrometheusFieldsApplyConfiguration) WithOperatorServiceDiscovery(value *OperatorServiceDiscoveryApplyConfiguration) *CommonPrometheusFieldsApplyConfiguration {
+	b.OperatorServiceDiscovery = value
+	return b
+}
+
+// WithAnnotationDiscovery sets the AnnotationDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AnnotationDiscovery field is set to the value of the last call.
+func (b *CommonPrometheusFieldsApplyConfiguration) WithAnnotationDiscovery(value *AnnotationDiscoveryApplyConfiguration) *CommonPrometheusFieldsApplyConfiguration {
+	b.AnnotationDiscovery = value
+	return b
+}
+
 // WithTSDB sets the TSDB field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the TSDB field is set to the value of the last call.
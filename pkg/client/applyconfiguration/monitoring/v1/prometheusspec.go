@@ -36,16 +36,42 @@ type PrometheusSpecApplyConfiguration struct {
 	Tag *string `json:"tag,omitempty"`
 	// sha is deprecated: use 'spec.image' instead. The image's digest can be specified as part of the image name.
 	SHA *string `json:"sha,omitempty"`
+	// exposure defines how the web UI and API are exposed outside of the
+	// cluster. The operator generates the Service(s) and the Ingress or the
+	// Gateway API HTTPRoute(s) matching the route prefix and the web TLS
+	// configuration. When defined, the operator also derives the external
+	// URL from the host name and it takes precedence over `externalUrl`.
+	Exposure *ExposureSpecApplyConfiguration `json:"exposure,omitempty"`
 	// retention defines how long to retain the Prometheus data.
 	//
 	// Default: "24h" if `spec.retention` and `spec.retentionSize` are empty.
 	Retention *monitoringv1.Duration `json:"retention,omitempty"`
 	// retentionSize defines the maximum number of bytes used by the Prometheus data.
+	//
+	// It cannot be set at the same time as `retentionSizePercentage`.
 	RetentionSize *monitoringv1.ByteSize `json:"retentionSize,omitempty"`
+	// retentionSizePercentage defines the maximum size used by the Prometheus
+	// data as a percentage of the storage size. The storage size is the
+	// storage request of `spec.storage.volumeClaimTemplate` (or
+	// `spec.storage.ephemeral.volumeClaimTemplate`) or the size limit of
+	// `spec.storage.emptyDir`.
+	//
+	// The size-based retention includes the write-ahead log (WAL) which can
+	// grow beyond the limit until the next head compaction: the percentage
+	// should leave enough headroom to avoid filling the volume (e.g. 80).
+	//
+	// The value is recomputed whenever the storage size changes. It is ignored
+	// if the storage size isn't defined.
+	//
+	// It cannot be set at the same time as `retentionSize`.
+	RetentionSizePercentage *int32 `json:"retentionSizePercentage,omitempty"`
 	// shardRetentionPolicy defines the retention policy for the Prometheus shards.
 	//
 	// (Beta) Using this mode requires the `PrometheusShardRetentionPolicy` feature gate (enabled by default).
 	ShardRetentionPolicy *ShardRetentionPolicyApplyConfiguration `json:"shardRetentionPolicy,omitempty"`
+	// storageMigration defines how the operator handles a change of the
+	// storage class in `spec.storage.volumeClaimTemplate`.
+	StorageMigration *StorageMigrationApplyConfiguration `json:"storageMigration,omitempty"`
 	// disableCompaction when true, the Prometheus compaction is disabled.
 	//
 	// When `spec.thanos.objectStorageConfig` or `spec.thanos.objectStorageConfigFile` are defined, the operator's
@@ -72,6 +98,33 @@ type PrometheusSpecApplyConfiguration struct {
 	// matches all namespaces. A null label selector matches the current
 	// namespace only.
 	RuleNamespaceSelector *metav1.LabelSelectorApplyConfiguration `json:"ruleNamespaceSelector,omitempty"`
+	// ruleEvaluation defines which shards evaluate the rules selected by
+	// `ruleSelector` when the Prometheus resource has more than one shard.
+	//
+	// By default, every shard evaluates all the rules which means that each
+	// alert fires once per shard and that recording rules produce one
+	// partial series per shard.
+	RuleEvaluation *RuleEvaluationApplyConfiguration `json:"ruleEvaluation,omitempty"`
+	// scrapeConfigRendering defines how the operator renders the scrape
+	// configurations generated from the ServiceMonitor, PodMonitor, Probe and
+	// ScrapeConfig resources.
+	//
+	// * `Inline` (default): all scrape configurations are written in the
+	// main Prometheus configuration file.
+	// * `PerResource`: the scrape configurations of each resource are
+	// written to a separate file which is referenced by the
+	// `scrape_config_files` field of the main configuration. The files are
+	// rendered for each shard and stored in a set of Secrets named
+	// `prometheus-<name>[-shard-<s>]-scrape-configs-<n>`.
+	// A change to one resource doesn't modify the files of the other
+	// resources.
+	//
+	// The additional scrape configurations (`spec.additionalScrapeConfigs`)
+	// are always written in the main configuration file.
+	//
+	// `PerResource` requires Prometheus >= v2.43.0, the operator falls back to
+	// `Inline` for older versions.
+	ScrapeConfigRendering *monitoringv1.ScrapeConfigRenderingMode `json:"scrapeConfigRendering,omitempty"`
 	// query defines the configuration of the Prometheus query service.
 	Query *QuerySpecApplyConfiguration `json:"query,omitempty"`
 	// alerting defines the settings related to Alertmanager.
@@ -292,6 +345,14 @@ func (b *PrometheusSpecApplyConfiguration) WithShardingStrategy(value *ShardingS
 	return b
 }
 
+// WithShardRolloutStrategy sets the ShardRolloutStrategy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ShardRolloutStrategy field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithShardRolloutStrategy(value *ShardRolloutStrategyApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.ShardRolloutStrategy = value
+	return b
+}
+
 // WithReplicaExternalLabelName sets the ReplicaExternalLabelName field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ReplicaExternalLabelName field is set to the value of the last call.
@@ -626,6 +687,22 @@ func (b *PrometheusSpecApplyConfiguration) WithUpdateStrategy(value *StatefulSet
 	return b
 }
 
+// WithPodDisruptionBudget sets the PodDisruptionBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodDisruptionBudget field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithPodDisruptionBudget(value *PodDisruptionBudgetSpecApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.PodDisruptionBudget = value
+	return b
+}
+
+// WithNetworkPolicy sets the NetworkPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicy field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithNetworkPolicy(value *NetworkPolicySpecApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.NetworkPolicy = value
+	return b
+}
+
 // WithEnableServiceLinks sets the EnableServiceLinks field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the EnableServiceLinks field is set to the value of the last call.
@@ -654,6 +731,19 @@ func (b *PrometheusSpecApplyConfiguration) WithInitContainers(values ...corev1.C
 	return b
 }
 
+// WithOverlays adds the given value to the Overlays field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Overlays field.
+func (b *PrometheusSpecApplyConfiguration) WithOverlays(values ...*OverlayApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOverlays")
+		}
+		b.CommonPrometheusFieldsApplyConfiguration.Overlays = append(b.CommonPrometheusFieldsApplyConfiguration.Overlays, *values[i])
+	}
+	return b
+}
+
 // WithAdditionalScrapeConfigs sets the AdditionalScrapeConfigs field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the AdditionalScrapeConfigs field is set to the value of the last call.
@@ -996,6 +1086,22 @@ func (b *PrometheusSpecApplyConfiguration) WithServiceDiscoveryRole(value monito
 	return b
 }
 
+// WithOperatorServiceDiscovery sets the OperatorServiceDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperatorServiceDiscovery field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithOperatorServiceDiscovery(value *OperatorServiceDiscoveryApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.OperatorServiceDiscovery = value
+	return b
+}
+
+// WithAnnotationDiscovery sets the AnnotationDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AnnotationDiscovery field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithAnnotationDiscovery(value *AnnotationDiscoveryApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.AnnotationDiscovery = value
+	return b
+}
+
 // WithTSDB sets the TSDB field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the TSDB field is set to the value of the last call.
@@ -1068,6 +1174,14 @@ func (b *PrometheusSpecApplyConfiguration) WithSHA(value string) *PrometheusSpec
 	return b
 }
 
+// WithExposure sets the Exposure field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Exposure field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithExposure(value *ExposureSpecApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.Exposure = value
+	return b
+}
+
 // WithRetention sets the Retention field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Retention field is set to the value of the last call.
@@ -1084,6 +1198,14 @@ func (b *PrometheusSpecApplyConfiguration) WithRetentionSize(value monitoringv1.
 	return b
 }
 
+// WithRetentionSizePercentage sets the RetentionSizePercentage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RetentionSizePercentage field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithRetentionSizePercentage(value int32) *PrometheusSpecApplyConfiguration {
+	b.RetentionSizePercentage = &value
+	return b
+}
+
 // WithShardRetentionPolicy sets the ShardRetentionPolicy field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ShardRetentionPolicy field is set to the value of the last call.
@@ -1092,6 +1214,14 @@ func (b *PrometheusSpecApplyConfiguration) WithShardRetentionPolicy(value *Shard
 	return b
 }
 
+// WithStorageMigration sets the StorageMigration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StorageMigration field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithStorageMigration(value *StorageMigrationApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.StorageMigration = value
+	return b
+}
+
 // WithDisableCompaction sets the DisableCompaction field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the DisableCompaction field is set to the value of the last call.
@@ -1137,6 +1267,22 @@ func (b *PrometheusSpecApplyConfiguration) WithRuleNamespaceSelector(value *meta
 	return b
 }
 
+// WithRuleEvaluation sets the RuleEvaluation field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RuleEvaluation field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithRuleEvaluation(value *RuleEvaluationApplyConfiguration) *PrometheusSpecApplyConfiguration {
+	b.RuleEvaluation = value
+	return b
+}
+
+// WithScrapeConfigRendering sets the ScrapeConfigRendering field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ScrapeConfigRendering field is set to the value of the last call.
+func (b *PrometheusSpecApplyConfiguration) WithScrapeConfigRendering(value monitoringv1.ScrapeConfigRenderingMode) *PrometheusSpecApplyConfiguration {
+	b.ScrapeConfigRendering = &value
+	return b
+}
+
 // WithQuery sets the Query field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Query field is set to the value of the last call.
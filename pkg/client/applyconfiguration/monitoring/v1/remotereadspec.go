@@ -30,7 +30,26 @@ type RemoteReadSpecApplyConfiguration struct {
 	// url defines the URL of the endpoint to query from.
 	//
 	// It must use the HTTP or HTTPS scheme.
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
 	URL *monitoringv1.URL `json:"url,omitempty"`
+	// prometheusRef references a Prometheus object to query from.
+	//
+	// The operator configures one remote-read endpoint per shard of the
+	// referenced Prometheus, targeting the first replica of the shard (the
+	// replicas of a shard hold the same data).
+	//
+	// The scheme and the route prefix are derived from the web configuration
+	// of the referenced Prometheus. When web TLS is enabled and the referenced
+	// Prometheus lives in the same namespace, the serving certificate is
+	// trusted unless `tlsConfig` is defined. Authentication settings (e.g.
+	// `basicAuth`) aren't derived and must be defined explicitly.
+	//
+	// When `name` is defined and the referenced Prometheus has more than one
+	// shard, the shard number is appended to the endpoint's name.
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
+	PrometheusRef *PrometheusReferenceApplyConfiguration `json:"prometheusRef,omitempty"`
 	// name of the remote read queue, it must be unique if specified. The
 	// name is used in metrics and logging in order to differentiate read
 	// configurations.
@@ -101,6 +120,14 @@ func (b *RemoteReadSpecApplyConfiguration) WithURL(value monitoringv1.URL) *Remo
 	return b
 }
 
+// WithPrometheusRef sets the PrometheusRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PrometheusRef field is set to the value of the last call.
+func (b *RemoteReadSpecApplyConfiguration) WithPrometheusRef(value *PrometheusReferenceApplyConfiguration) *RemoteReadSpecApplyConfiguration {
+	b.PrometheusRef = value
+	return b
+}
+
 // WithName sets the Name field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Name field is set to the value of the last call.
@@ -30,7 +30,27 @@ type RemoteWriteSpecApplyConfiguration struct {
 	// url defines the URL of the endpoint to send samples to.
 	//
 	// It must use the HTTP or HTTPS scheme.
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
 	URL *monitoringv1.URL `json:"url,omitempty"`
+	// prometheusRef references a Prometheus object to send samples to.
+	//
+	// The operator configures one remote-write queue per replica of the
+	// referenced Prometheus, targeting the remote-write receiver endpoint of
+	// the pod. The referenced Prometheus must have
+	// `enableRemoteWriteReceiver` set to true and it can't be sharded.
+	//
+	// The scheme and the route prefix are derived from the web configuration
+	// of the referenced Prometheus. When web TLS is enabled and the referenced
+	// Prometheus lives in the same namespace, the serving certificate is
+	// trusted unless `tlsConfig` is defined. Authentication settings (e.g.
+	// `basicAuth`) aren't derived and must be defined explicitly.
+	//
+	// When `name` is defined and the referenced Prometheus has more than one
+	// replica, the replica number is appended to the queue's name.
+	//
+	// Exactly one of `url` and `prometheusRef` must be set.
+	PrometheusRef *PrometheusReferenceApplyConfiguration `json:"prometheusRef,omitempty"`
 	// name of the remote write queue, it must be unique if specified. The
 	// name is used in metrics and logging in order to differentiate queues.
 	//
@@ -73,11 +93,11 @@ type RemoteWriteSpecApplyConfiguration struct {
 	//
 	// It requires Prometheus >= v2.27.0 or Thanos >= v0.24.0.
 	//
-	// Cannot be set at the same time as `sigv4`, `authorization`, `basicAuth`, or `azureAd`.
+	// Cannot be set at the same time as `sigv4`, `authorization`, `basicAuth`, `azureAd` or `googleIAM`.
 	OAuth2 *OAuth2ApplyConfiguration `json:"oauth2,omitempty"`
 	// basicAuth configuration for the URL.
 	//
-	// Cannot be set at the same time as `sigv4`, `authorization`, `oauth2`, or `azureAd`.
+	// Cannot be set at the same time as `sigv4`, `authorization`, `oauth2`, `azureAd` or `googleIAM`.
 	BasicAuth *BasicAuthApplyConfiguration `json:"basicAuth,omitempty"`
 	// bearerTokenFile defines the file from which to read bearer token for the URL.
 	//
@@ -87,20 +107,26 @@ type RemoteWriteSpecApplyConfiguration struct {
 	//
 	// It requires Prometheus >= v2.26.0 or Thanos >= v0.24.0.
 	//
-	// Cannot be set at the same time as `sigv4`, `basicAuth`, `oauth2`, or `azureAd`.
+	// Cannot be set at the same time as `sigv4`, `basicAuth`, `oauth2`, `azureAd` or `googleIAM`.
 	Authorization *AuthorizationApplyConfiguration `json:"authorization,omitempty"`
 	// sigv4 defines the AWS's Signature Verification 4 for the URL.
 	//
 	// It requires Prometheus >= v2.26.0 or Thanos >= v0.24.0.
 	//
-	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, or `azureAd`.
+	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, `azureAd` or `googleIAM`.
 	Sigv4 *Sigv4ApplyConfiguration `json:"sigv4,omitempty"`
 	// azureAd for the URL.
 	//
 	// It requires Prometheus >= v2.45.0 or Thanos >= v0.31.0.
 	//
-	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, or `sigv4`.
+	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, `sigv4` or `googleIAM`.
 	AzureAD *AzureADApplyConfiguration `json:"azureAd,omitempty"`
+	// googleIAM defines the Google Cloud IAM authentication for the URL.
+	//
+	// It requires Prometheus >= v2.55.0 or Thanos >= v0.37.0.
+	//
+	// Cannot be set at the same time as `authorization`, `basicAuth`, `oauth2`, `sigv4` or `azureAd`.
+	GoogleIAM *GoogleIAMApplyConfiguration `json:"googleIAM,omitempty"`
 	// bearerToken is deprecated: this will be removed in a future release.
 	// *Warning: this field shouldn't be used because the token value appears
 	// in clear-text. Prefer using `authorization`.*
@@ -153,6 +179,14 @@ func (b *RemoteWriteSpecApplyConfiguration) WithURL(value monitoringv1.URL) *Rem
 	return b
 }
 
+// WithPrometheusRef sets the PrometheusRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PrometheusRef field is set to the value of the last call.
+func (b *RemoteWriteSpecApplyConfiguration) WithPrometheusRef(value *PrometheusReferenceApplyConfiguration) *RemoteWriteSpecApplyConfiguration {
+	b.PrometheusRef = value
+	return b
+}
+
 // WithName sets the Name field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Name field is set to the value of the last call.
@@ -268,6 +302,14 @@ func (b *RemoteWriteSpecApplyConfiguration) WithAzureAD(value *AzureADApplyConfi
 	return b
 }
 
+// WithGoogleIAM sets the GoogleIAM field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the GoogleIAM field is set to the value of the last call.
+func (b *RemoteWriteSpecApplyConfiguration) WithGoogleIAM(value *GoogleIAMApplyConfiguration) *RemoteWriteSpecApplyConfiguration {
+	b.GoogleIAM = value
+	return b
+}
+
 // WithBearerToken sets the BearerToken field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the BearerToken field is set to the value of the last call.
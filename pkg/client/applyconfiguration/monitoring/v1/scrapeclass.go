@@ -65,6 +65,58 @@ type ScrapeClassApplyConfiguration struct {
 	// When the scrape object defines its own configuration, it takes
 	// precedence over the scrape class configuration.
 	AttachMetadata *AttachMetadataApplyConfiguration `json:"attachMetadata,omitempty"`
+	// scrapeInterval defines the default interval between consecutive scrapes.
+	// It will only apply if the scrape resource doesn't specify any interval.
+	ScrapeInterval *monitoringv1.Duration `json:"scrapeInterval,omitempty"`
+	// scrapeTimeout defines the default timeout after which a scrape is
+	// considered failed.
+	// It will only apply if the scrape resource doesn't specify any timeout.
+	//
+	// Like for the global scrape timeout, the value is ignored when it is
+	// greater than the scrape interval of the job.
+	ScrapeTimeout *monitoringv1.Duration `json:"scrapeTimeout,omitempty"`
+	// sampleLimit defines the default per-scrape limit on the number of scraped
+	// samples that will be accepted.
+	// It will only apply if the scrape resource doesn't specify any sampleLimit.
+	//
+	// The `spec.enforcedSampleLimit` value, if defined, takes precedence when
+	// it is lower.
+	SampleLimit *int64 `json:"sampleLimit,omitempty"`
+	// targetLimit defines the default limit on the number of scraped targets
+	// that will be accepted.
+	// It will only apply if the scrape resource doesn't specify any targetLimit.
+	//
+	// The `spec.enforcedTargetLimit` value, if defined, takes precedence when
+	// it is lower.
+	TargetLimit *int64 `json:"targetLimit,omitempty"`
+	// labelLimit defines the default per-scrape limit on the number of labels
+	// that will be accepted for a sample.
+	// It will only apply if the scrape resource doesn't specify any labelLimit.
+	//
+	// The `spec.enforcedLabelLimit` value, if defined, takes precedence when
+	// it is lower.
+	LabelLimit *int64 `json:"labelLimit,omitempty"`
+	// labelNameLengthLimit defines the default per-scrape limit on the length
+	// of label names that will be accepted for a sample.
+	// It will only apply if the scrape resource doesn't specify any labelNameLengthLimit.
+	//
+	// The `spec.enforcedLabelNameLengthLimit` value, if defined, takes
+	// precedence when it is lower.
+	LabelNameLengthLimit *int64 `json:"labelNameLengthLimit,omitempty"`
+	// labelValueLengthLimit defines the default per-scrape limit on the length
+	// of label values that will be accepted for a sample.
+	// It will only apply if the scrape resource doesn't specify any labelValueLengthLimit.
+	//
+	// The `spec.enforcedLabelValueLengthLimit` value, if defined, takes
+	// precedence when it is lower.
+	LabelValueLengthLimit *int64 `json:"labelValueLengthLimit,omitempty"`
+	// bodySizeLimit defines the default job level limit on the size of
+	// uncompressed response body that will be accepted by Prometheus.
+	// It will only apply if the scrape resource doesn't specify any bodySizeLimit.
+	//
+	// The `spec.enforcedBodySizeLimit` value, if defined, takes precedence
+	// when it is lower.
+	BodySizeLimit *monitoringv1.ByteSize `json:"bodySizeLimit,omitempty"`
 }
 
 // ScrapeClassApplyConfiguration constructs a declarative configuration of the ScrapeClass type for use with
@@ -146,3 +198,67 @@ func (b *ScrapeClassApplyConfiguration) WithAttachMetadata(value *AttachMetadata
 	b.AttachMetadata = value
 	return b
 }
+
+// WithScrapeInterval sets the ScrapeInterval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ScrapeInterval field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithScrapeInterval(value monitoringv1.Duration) *ScrapeClassApplyConfiguration {
+	b.ScrapeInterval = &value
+	return b
+}
+
+// WithScrapeTimeout sets the ScrapeTimeout field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ScrapeTimeout field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithScrapeTimeout(value monitoringv1.Duration) *ScrapeClassApplyConfiguration {
+	b.ScrapeTimeout = &value
+	return b
+}
+
+// WithSampleLimit sets the SampleLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SampleLimit field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithSampleLimit(value int64) *ScrapeClassApplyConfiguration {
+	b.SampleLimit = &value
+	return b
+}
+
+// WithTargetLimit sets the TargetLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TargetLimit field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithTargetLimit(value int64) *ScrapeClassApplyConfiguration {
+	b.TargetLimit = &value
+	return b
+}
+
+// WithLabelLimit sets the LabelLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LabelLimit field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithLabelLimit(value int64) *ScrapeClassApplyConfiguration {
+	b.LabelLimit = &value
+	return b
+}
+
+// WithLabelNameLengthLimit sets the LabelNameLengthLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LabelNameLengthLimit field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithLabelNameLengthLimit(value int64) *ScrapeClassApplyConfiguration {
+	b.LabelNameLengthLimit = &value
+	return b
+}
+
+// WithLabelValueLengthLimit sets the LabelValueLengthLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LabelValueLengthLimit field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithLabelValueLengthLimit(value int64) *ScrapeClassApplyConfiguration {
+	b.LabelValueLengthLimit = &value
+	return b
+}
+
+// WithBodySizeLimit sets the BodySizeLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BodySizeLimit field is set to the value of the last call.
+func (b *ScrapeClassApplyConfiguration) WithBodySizeLimit(value monitoringv1.ByteSize) *ScrapeClassApplyConfiguration {
+	b.BodySizeLimit = &value
+	return b
+}
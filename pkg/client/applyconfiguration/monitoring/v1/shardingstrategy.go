@@ -25,7 +25,7 @@ import (
 //
 // ShardingStrategy defines the sharding strategy for Prometheus.
 type ShardingStrategyApplyConfiguration struct {
-	// mode defines the sharding mode. Can be 'Address' or 'Topology'.
+	// mode defines the sharding mode. Can be 'Address', 'Topology', 'Namespace' or 'Label'.
 	//
 	// 'Address' is the default mode and distributes targets across shards
 	// based on a hash of the target address.
@@ -34,10 +34,28 @@ type ShardingStrategyApplyConfiguration struct {
 	// specific topology zone and only scrapes targets in that zone.
 	// (Alpha) Using the 'Topology' mode requires the `PrometheusTopologySharding`
 	// feature gate to be enabled.
+	//
+	// 'Namespace' assigns whole namespaces to shards. The configuration of
+	// each shard only contains the scrape jobs of the ServiceMonitor,
+	// PodMonitor, Probe and ScrapeConfig resources from its namespaces.
+	//
+	// 'Label' assigns the ServiceMonitor, PodMonitor, Probe and ScrapeConfig
+	// resources to shards based on the value of a label. The configuration
+	// of each shard only contains the scrape jobs of its resources.
+	//
+	// With the 'Namespace' and 'Label' modes, the additional scrape
+	// configurations are still distributed based on the target address and
+	// the annotation-based discovery is distributed by namespace.
 	Mode *monitoringv1.ShardingStrategyMode `json:"mode,omitempty"`
 	// topology defines the configuration for topology-aware sharding.
 	// This field is only valid when mode is set to 'Topology'.
 	Topology *TopologyShardingStrategyApplyConfiguration `json:"topology,omitempty"`
+	// namespace defines the configuration for namespace-based sharding.
+	// This field is only valid when mode is set to 'Namespace'.
+	Namespace *NamespaceShardingStrategyApplyConfiguration `json:"namespace,omitempty"`
+	// label defines the configuration for label-based sharding.
+	// This field is only valid when mode is set to 'Label'.
+	Label *LabelShardingStrategyApplyConfiguration `json:"label,omitempty"`
 }
 
 // ShardingStrategyApplyConfiguration constructs a declarative configuration of the ShardingStrategy type for use with
@@ -61,3 +79,19 @@ func (b *ShardingStrategyApplyConfiguration) WithTopology(value *TopologyShardin
 	b.Topology = value
 	return b
 }
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *ShardingStrategyApplyConfiguration) WithNamespace(value *NamespaceShardingStrategyApplyConfiguration) *ShardingStrategyApplyConfiguration {
+	b.Namespace = value
+	return b
+}
+
+// WithLabel sets the Label field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Label field is set to the value of the last call.
+func (b *ShardingStrategyApplyConfiguration) WithLabel(value *LabelShardingStrategyApplyConfiguration) *ShardingStrategyApplyConfiguration {
+	b.Label = value
+	return b
+}
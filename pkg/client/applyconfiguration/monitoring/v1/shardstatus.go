@@ -31,6 +31,9 @@ type ShardStatusApplyConfiguration struct {
 	AvailableReplicas *int32 `json:"availableReplicas,omitempty"`
 	// unavailableReplicas defines the Total number of unavailable pods targeted by this shard.
 	UnavailableReplicas *int32 `json:"unavailableReplicas,omitempty"`
+	// namespaces defines the namespaces assigned to this shard when the
+	// sharding strategy mode is 'Namespace' or 'Label'.
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 // ShardStatusApplyConfiguration constructs a declarative configuration of the ShardStatus type for use with
@@ -78,3 +81,13 @@ func (b *ShardStatusApplyConfiguration) WithUnavailableReplicas(value int32) *Sh
 	b.UnavailableReplicas = &value
 	return b
 }
+
+// WithNamespaces adds the given value to the Namespaces field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Namespaces field.
+func (b *ShardStatusApplyConfiguration) WithNamespaces(values ...string) *ShardStatusApplyConfiguration {
+	for i := range values {
+		b.Namespaces = append(b.Namespaces, values[i])
+	}
+	return b
+}
@@ -128,12 +128,39 @@ type ThanosRulerSpecApplyConfiguration struct {
 	//
 	// The default strategy is RollingUpdate.
 	UpdateStrategy *StatefulSetUpdateStrategyApplyConfiguration `json:"updateStrategy,omitempty"`
+	// podDisruptionBudget defines the PodDisruptionBudget protecting the
+	// pods. When defined, the operator creates one PodDisruptionBudget per
+	// StatefulSet (e.g. per shard) and deletes it when the StatefulSet is
+	// removed or when the field is unset.
+	PodDisruptionBudget *PodDisruptionBudgetSpecApplyConfiguration `json:"podDisruptionBudget,omitempty"`
+	// networkPolicy defines the NetworkPolicy generated by the operator for
+	// the pods. When defined, the operator allows the ingress traffic to the
+	// exposed ports and the egress traffic to the destinations it knows
+	// about (DNS, selected namespaces, in-cluster endpoints, ...). The
+	// NetworkPolicy is deleted when the field is unset.
+	NetworkPolicy *NetworkPolicySpecApplyConfiguration `json:"networkPolicy,omitempty"`
 	// queryEndpoints defines the list of Thanos Query endpoints from which to query metrics.
 	//
 	// For Thanos >= v0.11.0, it is recommended to use `queryConfig` instead.
 	//
-	// `queryConfig` takes precedence over this field.
+	// `queryConfig` and `queryEndpointRefs` take precedence over this field.
 	QueryEndpoints []string `json:"queryEndpoints,omitempty"`
+	// queryEndpointRefs defines the list of Prometheus objects from which to
+	// query metrics.
+	//
+	// The operator generates the query configuration targeting the pods of
+	// the referenced Prometheus objects. The scheme and the route prefix are
+	// derived from the web configuration of each Prometheus. When web TLS is
+	// enabled and the Prometheus lives in the same namespace as the
+	// ThanosRuler, the serving certificate is trusted.
+	//
+	// The referenced Prometheus objects can't be sharded.
+	//
+	// It requires Thanos >= v0.11.0.
+	//
+	// `queryConfig` takes precedence over this field.
+	// This field takes precedence over `queryEndpoints`.
+	QueryEndpointRefs []PrometheusReferenceApplyConfiguration `json:"queryEndpointRefs,omitempty"`
 	// queryConfig defines the list of Thanos Query endpoints from which to query metrics.
 	//
 	// The configuration format is defined at https://thanos.io/tip/components/rule.md/#query-api
@@ -142,13 +169,15 @@ type ThanosRulerSpecApplyConfiguration struct {
 	//
 	// The operator performs no validation of the configuration.
 	//
-	// This field takes precedence over `queryEndpoints`.
+	// This field takes precedence over `queryEndpointRefs` and `queryEndpoints`.
 	QueryConfig *corev1.SecretKeySelector `json:"queryConfig,omitempty"`
 	// alertmanagersUrl defines the list of Alertmanager endpoints to send alerts to.
 	//
 	// For Thanos >= v0.10.0, it is recommended to use `alertmanagersConfig` instead.
 	//
-	// `alertmanagersConfig` takes precedence over this field.
+	// Each URL must have an `http` or `https` scheme (optionally prefixed with `dns+`, `dnssrv+` or `dnssrvnoa+`) and a host.
+	//
+	// `alertmanagersConfig`, `alertmanagerRefs` and `alertmanagerEndpoints` take precedence over this field.
 	AlertManagersURL []string `json:"alertmanagersUrl,omitempty"`
 	// alertmanagersConfig defines the list of Alertmanager endpoints to send alerts to.
 	//
@@ -156,10 +185,38 @@ type ThanosRulerSpecApplyConfiguration struct {
 	//
 	// It requires Thanos >= v0.10.0.
 	//
-	// The operator performs no validation of the configuration.
+	// The operator checks the addresses, scheme, API version and timeout of the Alertmanager endpoints. An invalid configuration is reported in the operator logs but it doesn't fail the reconciliation.
 	//
-	// This field takes precedence over `alertmanagersUrl`.
+	// This field takes precedence over `alertmanagerRefs`, `alertmanagerEndpoints` and `alertmanagersUrl`.
 	AlertManagersConfig *corev1.SecretKeySelector `json:"alertmanagersConfig,omitempty"`
+	// alertmanagerRefs defines the Alertmanager objects managed by the operator
+	// to send alerts to.
+	//
+	// The operator generates the Alertmanager configuration from the spec of
+	// the Alertmanager objects (addresses of the pods, scheme, path prefix and
+	// API version). When web TLS is enabled and the Alertmanager lives in the
+	// same namespace, the serving certificate is trusted and the server name
+	// is set to `<service>.<namespace>.svc`.
+	//
+	// It requires Thanos >= v0.10.0.
+	//
+	// It can be combined with `alertmanagerEndpoints`.
+	//
+	// `alertmanagersConfig` takes precedence over this field.
+	// This field takes precedence over `alertmanagersUrl`.
+	AlertmanagerRefs []AlertmanagerReferenceApplyConfiguration `json:"alertmanagerRefs,omitempty"`
+	// alertmanagerEndpoints defines the Alertmanager endpoints which aren't
+	// managed by the operator (e.g. running outside of the cluster) to send
+	// alerts to.
+	//
+	// The operator generates the Alertmanager configuration from the
+	// endpoints. It can be combined with `alertmanagerRefs`.
+	//
+	// It requires Thanos >= v0.10.0.
+	//
+	// `alertmanagersConfig` takes precedence over this field.
+	// This field takes precedence over `alertmanagersUrl`.
+	AlertmanagerEndpoints []ThanosRulerAlertmanagerEndpointsApplyConfiguration `json:"alertmanagerEndpoints,omitempty"`
 	// ruleSelector defines the PrometheusRule objects to be selected for rule evaluation. An empty
 	// label selector matches all objects. A null label selector matches no
 	// objects.
@@ -231,6 +288,19 @@ type ThanosRulerSpecApplyConfiguration struct {
 	// an initContainer will lead to a restart of the Pod. More info:
 	// https://kubernetes.io/docs/concepts/workloads/pods/init-containers/
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// overlays defines patches applied to the StatefulSet(s), Service(s) and
+	// Secret(s) generated by the operator. The patches are applied in order
+	// after the operator has built the objects. It can be used to modify
+	// fields which can't be configured otherwise (e.g. the metadata of the
+	// volume claim templates). The overlays don't apply to the default
+	// governing Service which is shared by all the resources of the namespace.
+	//
+	// A failing patch is reported in the `Reconciled` condition with the
+	// `OverlayFailed` reason.
+	//
+	// Overlays which modify fields managed by the operator require careful
+	// testing, especially when upgrading to a new version of the operator.
+	Overlays []OverlayApplyConfiguration `json:"overlays,omitempty"`
 	// tracingConfig defines the tracing configuration.
 	//
 	// The configuration format is defined at https://thanos.io/tip/thanos/tracing.md/#configuration
@@ -269,6 +339,11 @@ type ThanosRulerSpecApplyConfiguration struct {
 	ExternalPrefix *string `json:"externalPrefix,omitempty"`
 	// routePrefix defines the route prefix ThanosRuler registers HTTP handlers for. This allows thanos UI to be served on a sub-path.
 	RoutePrefix *string `json:"routePrefix,omitempty"`
+	// exposure defines how the web UI and API are exposed outside of the
+	// cluster. The operator generates the Service(s) and the Ingress or the
+	// Gateway API HTTPRoute(s) matching the route prefix and the web TLS
+	// configuration.
+	Exposure *ExposureSpecApplyConfiguration `json:"exposure,omitempty"`
 	// grpcServerTlsConfig defines the gRPC server from which Thanos Querier reads
 	// recorded rule data.
 	//
@@ -593,6 +668,22 @@ func (b *ThanosRulerSpecApplyConfiguration) WithUpdateStrategy(value *StatefulSe
 	return b
 }
 
+// WithPodDisruptionBudget sets the PodDisruptionBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodDisruptionBudget field is set to the value of the last call.
+func (b *ThanosRulerSpecApplyConfiguration) WithPodDisruptionBudget(value *PodDisruptionBudgetSpecApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	b.PodDisruptionBudget = value
+	return b
+}
+
+// WithNetworkPolicy sets the NetworkPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicy field is set to the value of the last call.
+func (b *ThanosRulerSpecApplyConfiguration) WithNetworkPolicy(value *NetworkPolicySpecApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	b.NetworkPolicy = value
+	return b
+}
+
 // WithQueryEndpoints adds the given value to the QueryEndpoints field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the QueryEndpoints field.
@@ -603,6 +694,19 @@ func (b *ThanosRulerSpecApplyConfiguration) WithQueryEndpoints(values ...string)
 	return b
 }
 
+// WithQueryEndpointRefs adds the given value to the QueryEndpointRefs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the QueryEndpointRefs field.
+func (b *ThanosRulerSpecApplyConfiguration) WithQueryEndpointRefs(values ...*PrometheusReferenceApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQueryEndpointRefs")
+		}
+		b.QueryEndpointRefs = append(b.QueryEndpointRefs, *values[i])
+	}
+	return b
+}
+
 // WithQueryConfig sets the QueryConfig field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the QueryConfig field is set to the value of the last call.
@@ -629,6 +733,32 @@ func (b *ThanosRulerSpecApplyConfiguration) WithAlertManagersConfig(value corev1
 	return b
 }
 
+// WithAlertmanagerRefs adds the given value to the AlertmanagerRefs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AlertmanagerRefs field.
+func (b *ThanosRulerSpecApplyConfiguration) WithAlertmanagerRefs(values ...*AlertmanagerReferenceApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithAlertmanagerRefs")
+		}
+		b.AlertmanagerRefs = append(b.AlertmanagerRefs, *values[i])
+	}
+	return b
+}
+
+// WithAlertmanagerEndpoints adds the given value to the AlertmanagerEndpoints field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AlertmanagerEndpoints field.
+func (b *ThanosRulerSpecApplyConfiguration) WithAlertmanagerEndpoints(values ...*ThanosRulerAlertmanagerEndpointsApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithAlertmanagerEndpoints")
+		}
+		b.AlertmanagerEndpoints = append(b.AlertmanagerEndpoints, *values[i])
+	}
+	return b
+}
+
 // WithRuleSelector sets the RuleSelector field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the RuleSelector field is set to the value of the last call.
@@ -779,6 +909,19 @@ func (b *ThanosRulerSpecApplyConfiguration) WithInitContainers(values ...corev1.
 	return b
 }
 
+// WithOverlays adds the given value to the Overlays field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Overlays field.
+func (b *ThanosRulerSpecApplyConfiguration) WithOverlays(values ...*OverlayApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOverlays")
+		}
+		b.Overlays = append(b.Overlays, *values[i])
+	}
+	return b
+}
+
 // WithTracingConfig sets the TracingConfig field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the TracingConfig field is set to the value of the last call.
@@ -835,6 +978,14 @@ func (b *ThanosRulerSpecApplyConfiguration) WithRoutePrefix(value string) *Thano
 	return b
 }
 
+// WithExposure sets the Exposure field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Exposure field is set to the value of the last call.
+func (b *ThanosRulerSpecApplyConfiguration) WithExposure(value *ExposureSpecApplyConfiguration) *ThanosRulerSpecApplyConfiguration {
+	b.Exposure = value
+	return b
+}
+
 // WithGRPCServerTLSConfig sets the GRPCServerTLSConfig field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the GRPCServerTLSConfig field is set to the value of the last call.
@@ -31,6 +31,12 @@ type WorkloadBindingApplyConfiguration struct {
 	Namespace *string `json:"namespace,omitempty"`
 	// conditions defines the current state of the configuration resource when bound to the referenced Workload object.
 	Conditions []ConfigResourceConditionApplyConfiguration `json:"conditions,omitempty"`
+	// shards defines the shards of the workload evaluating the rules of the
+	// configuration resource.
+	// It is only set for PrometheusRule resources when the Prometheus
+	// workload doesn't evaluate all the rules on every shard (see
+	// `spec.ruleEvaluation`).
+	Shards []int32 `json:"shards,omitempty"`
 }
 
 // WorkloadBindingApplyConfiguration constructs a declarative configuration of the WorkloadBinding type for use with
@@ -83,3 +89,13 @@ func (b *WorkloadBindingApplyConfiguration) WithConditions(values ...*ConfigReso
 	}
 	return b
 }
+
+// WithShards adds the given value to the Shards field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Shards field.
+func (b *WorkloadBindingApplyConfiguration) WithShards(values ...int32) *WorkloadBindingApplyConfiguration {
+	for i := range values {
+		b.Shards = append(b.Shards, values[i])
+	}
+	return b
+}
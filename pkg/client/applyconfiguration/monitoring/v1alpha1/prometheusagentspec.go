@@ -190,6 +190,14 @@ func (b *PrometheusAgentSpecApplyConfiguration) WithShardingStrategy(value *v1.S
 	return b
 }
 
+// WithShardRolloutStrategy sets the ShardRolloutStrategy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ShardRolloutStrategy field is set to the value of the last call.
+func (b *PrometheusAgentSpecApplyConfiguration) WithShardRolloutStrategy(value *v1.ShardRolloutStrategyApplyConfiguration) *PrometheusAgentSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.ShardRolloutStrategy = value
+	return b
+}
+
 // WithReplicaExternalLabelName sets the ReplicaExternalLabelName field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ReplicaExternalLabelName field is set to the value of the last call.
@@ -524,6 +532,22 @@ func (b *PrometheusAgentSpecApplyConfiguration) WithUpdateStrategy(value *v1.Sta
 	return b
 }
 
+// WithPodDisruptionBudget sets the PodDisruptionBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PodDisruptionBudget field is set to the value of the last call.
+func (b *PrometheusAgentSpecApplyConfiguration) WithPodDisruptionBudget(value *v1.PodDisruptionBudgetSpecApplyConfiguration) *PrometheusAgentSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.PodDisruptionBudget = value
+	return b
+}
+
+// WithNetworkPolicy sets the NetworkPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicy field is set to the value of the last call.
+func (b *PrometheusAgentSpecApplyConfiguration) WithNetworkPolicy(value *v1.NetworkPolicySpecApplyConfiguration) *PrometheusAgentSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.NetworkPolicy = value
+	return b
+}
+
 // WithEnableServiceLinks sets the EnableServiceLinks field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the EnableServiceLinks field is set to the value of the last call.
@@ -552,6 +576,19 @@ func (b *PrometheusAgentSpecApplyConfiguration) WithInitContainers(values ...cor
 	return b
 }
 
+// WithOverlays adds the given value to the Overlays field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Overlays field.
+func (b *PrometheusAgentSpecApplyConfiguration) WithOverlays(values ...*v1.OverlayApplyConfiguration) *PrometheusAgentSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOverlays")
+		}
+		b.CommonPrometheusFieldsApplyConfiguration.Overlays = append(b.CommonPrometheusFieldsApplyConfiguration.Overlays, *values[i])
+	}
+	return b
+}
+
 // WithAdditionalScrapeConfigs sets the AdditionalScrapeConfigs field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the AdditionalScrapeConfigs field is set to the value of the last call.
@@ -894,6 +931,22 @@ func (b *PrometheusAgentSpecApplyConfiguration) WithServiceDiscoveryRole(value m
 	return b
 }
 
+// WithOperatorServiceDiscovery sets the OperatorServiceDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the OperatorServiceDiscovery field is set to the value of the last call.
+func (b *PrometheusAgentSpecApplyConfiguration) WithOperatorServiceDiscovery(value *v1.OperatorServiceDiscoveryApplyConfiguration) *PrometheusAgentSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.OperatorServiceDiscovery = value
+	return b
+}
+
+// WithAnnotationDiscovery sets the AnnotationDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AnnotationDiscovery field is set to the value of the last call.
+func (b *PrometheusAgentSpecApplyConfiguration) WithAnnotationDiscovery(value *v1.AnnotationDiscoveryApplyConfiguration) *PrometheusAgentSpecApplyConfiguration {
+	b.CommonPrometheusFieldsApplyConfiguration.AnnotationDiscovery = value
+	return b
+}
+
 // WithTSDB sets the TSDB field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the TSDB field is set to the value of the last call.
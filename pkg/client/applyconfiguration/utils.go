@@ -42,18 +42,26 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.AlertmanagerConfigMatcherStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerConfiguration"):
 		return &monitoringv1.AlertmanagerConfigurationApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("AlertmanagerDNSSDConfig"):
+		return &monitoringv1.AlertmanagerDNSSDConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerEndpoints"):
 		return &monitoringv1.AlertmanagerEndpointsApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerGlobalConfig"):
 		return &monitoringv1.AlertmanagerGlobalConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerLimitsSpec"):
 		return &monitoringv1.AlertmanagerLimitsSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("AlertmanagerReference"):
+		return &monitoringv1.AlertmanagerReferenceApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerSpec"):
 		return &monitoringv1.AlertmanagerSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("AlertmanagerStaticConfig"):
+		return &monitoringv1.AlertmanagerStaticConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerStatus"):
 		return &monitoringv1.AlertmanagerStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AlertmanagerWebSpec"):
 		return &monitoringv1.AlertmanagerWebSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("AnnotationDiscovery"):
+		return &monitoringv1.AnnotationDiscoveryApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("APIServerConfig"):
 		return &monitoringv1.APIServerConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ArbitraryFSAccessThroughSMsConfig"):
@@ -72,6 +80,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.AzureSDKApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("AzureWorkloadIdentity"):
 		return &monitoringv1.AzureWorkloadIdentityApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("BackendTLSValidation"):
+		return &monitoringv1.BackendTLSValidationApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("BasicAuth"):
 		return &monitoringv1.BasicAuthApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ChunkEncodingSpec"):
@@ -96,6 +106,10 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.EndpointApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Exemplars"):
 		return &monitoringv1.ExemplarsApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ExposureSpec"):
+		return &monitoringv1.ExposureSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GatewayParentReference"):
+		return &monitoringv1.GatewayParentReferenceApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("GlobalJiraConfig"):
 		return &monitoringv1.GlobalJiraConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("GlobalMattermostConfig"):
@@ -112,6 +126,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.GlobalWebexConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("GlobalWeChatConfig"):
 		return &monitoringv1.GlobalWeChatConfigApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GoogleIAM"):
+		return &monitoringv1.GoogleIAMApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("GRPCServerTLSConfig"):
 		return &monitoringv1.GRPCServerTLSConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("HostAlias"):
@@ -128,20 +144,38 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.HTTPConfigWithProxyAndTLSFilesApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("HTTPConfigWithTLSFiles"):
 		return &monitoringv1.HTTPConfigWithTLSFilesApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("HTTPRouteExposure"):
+		return &monitoringv1.HTTPRouteExposureApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("IngressExposure"):
+		return &monitoringv1.IngressExposureApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("LabelShardingStrategy"):
+		return &monitoringv1.LabelShardingStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ManagedIdentity"):
 		return &monitoringv1.ManagedIdentityApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("MetadataConfig"):
 		return &monitoringv1.MetadataConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("NamespaceSelector"):
 		return &monitoringv1.NamespaceSelectorApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("NamespaceShardingStrategy"):
+		return &monitoringv1.NamespaceShardingStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("NativeHistogramConfig"):
 		return &monitoringv1.NativeHistogramConfigApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("NetworkPolicySpec"):
+		return &monitoringv1.NetworkPolicySpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("OAuth2"):
 		return &monitoringv1.OAuth2ApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ObjectReference"):
 		return &monitoringv1.ObjectReferenceApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("OperatorServiceDiscovery"):
+		return &monitoringv1.OperatorServiceDiscoveryApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("OTLPConfig"):
 		return &monitoringv1.OTLPConfigApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("Overlay"):
+		return &monitoringv1.OverlayApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("OverlayTarget"):
+		return &monitoringv1.OverlayTargetApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("PodDisruptionBudgetSpec"):
+		return &monitoringv1.PodDisruptionBudgetSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PodDNSConfig"):
 		return &monitoringv1.PodDNSConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PodDNSConfigOption"):
@@ -168,6 +202,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.ProbeTargetStaticConfigApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Prometheus"):
 		return &monitoringv1.PrometheusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("PrometheusReference"):
+		return &monitoringv1.PrometheusReferenceApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PrometheusRule"):
 		return &monitoringv1.PrometheusRuleApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PrometheusRuleExcludeConfig"):
@@ -198,6 +234,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.RollingUpdateStatefulSetStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Rule"):
 		return &monitoringv1.RuleApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RuleEvaluation"):
+		return &monitoringv1.RuleEvaluationApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RuleGroup"):
 		return &monitoringv1.RuleGroupApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Rules"):
@@ -218,20 +256,28 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1.ServiceMonitorApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ServiceMonitorSpec"):
 		return &monitoringv1.ServiceMonitorSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ShardAssignment"):
+		return &monitoringv1.ShardAssignmentApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ShardingStrategy"):
 		return &monitoringv1.ShardingStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ShardRetentionPolicy"):
 		return &monitoringv1.ShardRetentionPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ShardRolloutStrategy"):
+		return &monitoringv1.ShardRolloutStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ShardStatus"):
 		return &monitoringv1.ShardStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Sigv4"):
 		return &monitoringv1.Sigv4ApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("StatefulSetUpdateStrategy"):
 		return &monitoringv1.StatefulSetUpdateStrategyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("StorageMigration"):
+		return &monitoringv1.StorageMigrationApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("StorageSpec"):
 		return &monitoringv1.StorageSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ThanosRuler"):
 		return &monitoringv1.ThanosRulerApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ThanosRulerAlertmanagerEndpoints"):
+		return &monitoringv1.ThanosRulerAlertmanagerEndpointsApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ThanosRulerSpec"):
 		return &monitoringv1.ThanosRulerSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ThanosRulerStatus"):
@@ -324,6 +370,10 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1alpha1.LinodeSDConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Matcher"):
 		return &monitoringv1alpha1.MatcherApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("MonitoringQuota"):
+		return &monitoringv1alpha1.MonitoringQuotaApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("MonitoringQuotaSpec"):
+		return &monitoringv1alpha1.MonitoringQuotaSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MSTeamsConfig"):
 		return &monitoringv1alpha1.MSTeamsConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MSTeamsV2Config"):
@@ -352,6 +402,12 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1alpha1.PrometheusAgentApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PrometheusAgentSpec"):
 		return &monitoringv1alpha1.PrometheusAgentSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PrometheusDefaults"):
+		return &monitoringv1alpha1.PrometheusDefaultsApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PrometheusOperatorDefaults"):
+		return &monitoringv1alpha1.PrometheusOperatorDefaultsApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PrometheusOperatorDefaultsSpec"):
+		return &monitoringv1alpha1.PrometheusOperatorDefaultsSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PuppetDBSDConfig"):
 		return &monitoringv1alpha1.PuppetDBSDConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PushoverConfig"):
@@ -398,6 +454,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &monitoringv1alpha1.WebhookConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("WeChatConfig"):
 		return &monitoringv1alpha1.WeChatConfigApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("WorkloadDefaults"):
+		return &monitoringv1alpha1.WorkloadDefaultsApplyConfiguration{}
 
 		// Group=monitoring.coreos.com, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithKind("AlertmanagerConfig"):
@@ -24,8 +24,12 @@ import (
 type Interface interface {
 	// AlertmanagerConfigs returns a AlertmanagerConfigInformer.
 	AlertmanagerConfigs() AlertmanagerConfigInformer
+	// MonitoringQuotas returns a MonitoringQuotaInformer.
+	MonitoringQuotas() MonitoringQuotaInformer
 	// PrometheusAgents returns a PrometheusAgentInformer.
 	PrometheusAgents() PrometheusAgentInformer
+	// PrometheusOperatorDefaults returns a PrometheusOperatorDefaultsInformer.
+	PrometheusOperatorDefaults() PrometheusOperatorDefaultsInformer
 	// ScrapeConfigs returns a ScrapeConfigInformer.
 	ScrapeConfigs() ScrapeConfigInformer
 }
@@ -46,11 +50,21 @@ func (v *version) AlertmanagerConfigs() AlertmanagerConfigInformer {
 	return &alertmanagerConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// MonitoringQuotas returns a MonitoringQuotaInformer.
+func (v *version) MonitoringQuotas() MonitoringQuotaInformer {
+	return &monitoringQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // PrometheusAgents returns a PrometheusAgentInformer.
 func (v *version) PrometheusAgents() PrometheusAgentInformer {
 	return &prometheusAgentInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// PrometheusOperatorDefaults returns a PrometheusOperatorDefaultsInformer.
+func (v *version) PrometheusOperatorDefaults() PrometheusOperatorDefaultsInformer {
+	return &prometheusOperatorDefaultsInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ScrapeConfigs returns a ScrapeConfigInformer.
 func (v *version) ScrapeConfigs() ScrapeConfigInformer {
 	return &scrapeConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
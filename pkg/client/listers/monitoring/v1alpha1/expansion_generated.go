@@ -24,6 +24,14 @@ type AlertmanagerConfigListerExpansion interface{}
 // AlertmanagerConfigNamespaceLister.
 type AlertmanagerConfigNamespaceListerExpansion interface{}
 
+// MonitoringQuotaListerExpansion allows custom methods to be added to
+// MonitoringQuotaLister.
+type MonitoringQuotaListerExpansion interface{}
+
+// MonitoringQuotaNamespaceListerExpansion allows custom methods to be added to
+// MonitoringQuotaNamespaceLister.
+type MonitoringQuotaNamespaceListerExpansion interface{}
+
 // PrometheusAgentListerExpansion allows custom methods to be added to
 // PrometheusAgentLister.
 type PrometheusAgentListerExpansion interface{}
@@ -32,6 +40,10 @@ type PrometheusAgentListerExpansion interface{}
 // PrometheusAgentNamespaceLister.
 type PrometheusAgentNamespaceListerExpansion interface{}
 
+// PrometheusOperatorDefaultsListerExpansion allows custom methods to be added to
+// PrometheusOperatorDefaultsLister.
+type PrometheusOperatorDefaultsListerExpansion interface{}
+
 // ScrapeConfigListerExpansion allows custom methods to be added to
 // ScrapeConfigLister.
 type ScrapeConfigListerExpansion interface{}
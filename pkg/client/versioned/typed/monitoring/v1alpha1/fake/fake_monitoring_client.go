@@ -30,10 +30,18 @@ func (c *FakeMonitoringV1alpha1) AlertmanagerConfigs(namespace string) v1alpha1.
 	return newFakeAlertmanagerConfigs(c, namespace)
 }
 
+func (c *FakeMonitoringV1alpha1) MonitoringQuotas(namespace string) v1alpha1.MonitoringQuotaInterface {
+	return newFakeMonitoringQuotas(c, namespace)
+}
+
 func (c *FakeMonitoringV1alpha1) PrometheusAgents(namespace string) v1alpha1.PrometheusAgentInterface {
 	return newFakePrometheusAgents(c, namespace)
 }
 
+func (c *FakeMonitoringV1alpha1) PrometheusOperatorDefaults() v1alpha1.PrometheusOperatorDefaultsInterface {
+	return newFakePrometheusOperatorDefaults(c)
+}
+
 func (c *FakeMonitoringV1alpha1) ScrapeConfigs(namespace string) v1alpha1.ScrapeConfigInterface {
 	return newFakeScrapeConfigs(c, namespace)
 }
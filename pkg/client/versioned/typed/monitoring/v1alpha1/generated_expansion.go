@@ -18,6 +18,10 @@ package v1alpha1
 
 type AlertmanagerConfigExpansion interface{}
 
+type MonitoringQuotaExpansion interface{}
+
 type PrometheusAgentExpansion interface{}
 
+type PrometheusOperatorDefaultsExpansion interface{}
+
 type ScrapeConfigExpansion interface{}
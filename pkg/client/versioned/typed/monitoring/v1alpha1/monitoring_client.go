@@ -27,7 +27,9 @@ import (
 type MonitoringV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AlertmanagerConfigsGetter
+	MonitoringQuotasGetter
 	PrometheusAgentsGetter
+	PrometheusOperatorDefaultsGetter
 	ScrapeConfigsGetter
 }
 
@@ -40,10 +42,18 @@ func (c *MonitoringV1alpha1Client) AlertmanagerConfigs(namespace string) Alertma
 	return newAlertmanagerConfigs(c, namespace)
 }
 
+func (c *MonitoringV1alpha1Client) MonitoringQuotas(namespace string) MonitoringQuotaInterface {
+	return newMonitoringQuotas(c, namespace)
+}
+
 func (c *MonitoringV1alpha1Client) PrometheusAgents(namespace string) PrometheusAgentInterface {
 	return newPrometheusAgents(c, namespace)
 }
 
+func (c *MonitoringV1alpha1Client) PrometheusOperatorDefaults() PrometheusOperatorDefaultsInterface {
+	return newPrometheusOperatorDefaults(c)
+}
+
 func (c *MonitoringV1alpha1Client) ScrapeConfigs(namespace string) ScrapeConfigInterface {
 	return newScrapeConfigs(c, namespace)
 }
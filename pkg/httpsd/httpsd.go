@@ -0,0 +1,243 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpsd implements the HTTP service discovery endpoint served by the
+// operator to Prometheus.
+//
+// See https://prometheus.io/docs/prometheus/latest/http_sd/ for the
+// specification.
+//
+// Every request must carry a bearer token derived from the owner (Prometheus
+// or PrometheusAgent object) of the requested path so that a caller can only
+// read the targets of its own owner.
+package httpsd
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	pathPrefix = "/sd"
+
+	// keySize is the minimum size in bytes of the key used to sign the
+	// tokens.
+	keySize = 32
+)
+
+// TargetGroup is a set of targets sharing the same labels.
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// OwnerKey returns the key identifying the Prometheus or PrometheusAgent
+// object in the registry.
+func OwnerKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), namespace, name)
+}
+
+// ResourceKey returns the key identifying the configuration resource
+// (ScrapeConfig, Probe, ...) in the registry.
+func ResourceKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), namespace, name)
+}
+
+// Path returns the URL path serving the target groups of the resource for the
+// given shard of the Prometheus or PrometheusAgent object.
+//
+// The shard argument is a string because the value is usually expanded by the
+// config-reloader ("$(SHARD)").
+func Path(ownerKind, ownerNamespace, ownerName, shard, resourceKind, resourceNamespace, resourceName string) string {
+	return fmt.Sprintf("%s/%s/shards/%s/%s",
+		pathPrefix,
+		OwnerKey(ownerKind, ownerNamespace, ownerName),
+		shard,
+		ResourceKey(resourceKind, resourceNamespace, resourceName),
+	)
+}
+
+type ownerTargets struct {
+	shards int32
+	groups map[string][]TargetGroup
+}
+
+// Registry holds the target groups served to the Prometheus shards.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	logger *slog.Logger
+	key    []byte
+
+	mtx    sync.RWMutex
+	owners map[string]*ownerTargets
+}
+
+// NewRegistry returns an empty registry. The key signs the bearer tokens
+// returned by Token.
+func NewRegistry(logger *slog.Logger, key []byte) *Registry {
+	return &Registry{
+		logger: logger,
+		key:    key,
+		owners: map[string]*ownerTargets{},
+	}
+}
+
+// LoadKey returns the key read from the given file. If the file is empty, it
+// returns a random key which means that the tokens change when the operator
+// restarts.
+func LoadKey(file string) ([]byte, error) {
+	if file == "" {
+		key := make([]byte, keySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate the token key: %w", err)
+		}
+
+		return key, nil
+	}
+
+	key, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the token key: %w", err)
+	}
+
+	key = []byte(strings.TrimSpace(string(key)))
+	if len(key) < keySize {
+		return nil, fmt.Errorf("the token key from %q must be at least %d bytes long", file, keySize)
+	}
+
+	return key, nil
+}
+
+// Token returns the bearer token granting access to the target groups of the
+// Prometheus or PrometheusAgent object identified by ownerKey.
+func (r *Registry) Token(ownerKey string) string {
+	if r == nil {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(ownerKey))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// authorize returns the HTTP status code matching the bearer token of the
+// request for the given owner (http.StatusOK if the request is authorized).
+func (r *Registry) authorize(req *http.Request, ownerKey string) int {
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return http.StatusUnauthorized
+	}
+
+	if !hmac.Equal([]byte(token), []byte(r.Token(ownerKey))) {
+		return http.StatusForbidden
+	}
+
+	return http.StatusOK
+}
+
+// Set replaces the target groups of the Prometheus or PrometheusAgent object
+// identified by ownerKey. The groups are indexed by resource key.
+func (r *Registry) Set(ownerKey string, shards int32, groups map[string][]TargetGroup) {
+	if r == nil {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.owners[ownerKey] = &ownerTargets{
+		shards: shards,
+		groups: groups,
+	}
+}
+
+// Delete removes the target groups of the Prometheus or PrometheusAgent
+// object identified by ownerKey.
+func (r *Registry) Delete(ownerKey string) {
+	if r == nil {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	delete(r.owners, ownerKey)
+}
+
+// Register adds the HTTP service discovery handler to the mux.
+func (r *Registry) Register(mux *http.ServeMux) {
+	mux.HandleFunc(
+		"GET "+pathPrefix+"/{ownerKind}/{ownerNamespace}/{ownerName}/shards/{shard}/{resourceKind}/{resourceNamespace}/{resourceName}",
+		r.serveTargets,
+	)
+}
+
+func (r *Registry) serveTargets(w http.ResponseWriter, req *http.Request) {
+	var (
+		ownerKey    = OwnerKey(req.PathValue("ownerKind"), req.PathValue("ownerNamespace"), req.PathValue("ownerName"))
+		resourceKey = ResourceKey(req.PathValue("resourceKind"), req.PathValue("resourceNamespace"), req.PathValue("resourceName"))
+	)
+
+	switch code := r.authorize(req, ownerKey); code {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, http.StatusText(code), code)
+		return
+	default:
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	shard, err := strconv.ParseInt(req.PathValue("shard"), 10, 32)
+	if err != nil || shard < 0 {
+		http.Error(w, fmt.Sprintf("invalid shard %q", req.PathValue("shard")), http.StatusBadRequest)
+		return
+	}
+
+	r.mtx.RLock()
+	owner, found := r.owners[ownerKey]
+	var groups []TargetGroup
+	if found {
+		groups, found = owner.groups[resourceKey]
+		found = found && int32(shard) < owner.shards
+	}
+	r.mtx.RUnlock()
+
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	if groups == nil {
+		groups = []TargetGroup{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(groups); err != nil {
+		r.logger.Warn("failed to write the target groups", "owner", ownerKey, "resource", resourceKey, "err", err)
+	}
+}
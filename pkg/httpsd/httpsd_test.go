@@ -0,0 +1,171 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpsd
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	require.Equal(t,
+		"/sd/prometheus/monitoring/main/shards/$(SHARD)/scrapeconfig/default/static",
+		Path("Prometheus", "monitoring", "main", "$(SHARD)", "ScrapeConfig", "default", "static"),
+	)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(slog.New(slog.DiscardHandler), []byte("0123456789abcdef0123456789abcdef"))
+	mux := http.NewServeMux()
+	r.Register(mux)
+
+	var (
+		token      = r.Token(OwnerKey("Prometheus", "monitoring", "main"))
+		agentToken = r.Token(OwnerKey("PrometheusAgent", "monitoring", "main"))
+	)
+	require.NotEqual(t, token, agentToken)
+
+	r.Set(OwnerKey("Prometheus", "monitoring", "main"), 2, map[string][]TargetGroup{
+		ResourceKey("ScrapeConfig", "default", "static"): {
+			{
+				Targets: []string{"foo:9090", "bar:9090"},
+				Labels:  map[string]string{"env": "prod"},
+			},
+		},
+		ResourceKey("Probe", "default", "empty"): nil,
+	})
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{
+			name:   "target groups",
+			path:   Path("Prometheus", "monitoring", "main", "1", "ScrapeConfig", "default", "static"),
+			token:  token,
+			status: http.StatusOK,
+			body:   `[{"targets":["foo:9090","bar:9090"],"labels":{"env":"prod"}}]`,
+		},
+		{
+			name:   "no target",
+			path:   Path("Prometheus", "monitoring", "main", "0", "Probe", "default", "empty"),
+			token:  token,
+			status: http.StatusOK,
+			body:   `[]`,
+		},
+		{
+			name:   "missing token",
+			path:   Path("Prometheus", "monitoring", "main", "1", "ScrapeConfig", "default", "static"),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid token",
+			path:   Path("Prometheus", "monitoring", "main", "1", "ScrapeConfig", "default", "static"),
+			token:  "invalid",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "token of another owner",
+			path:   Path("Prometheus", "monitoring", "main", "1", "ScrapeConfig", "default", "static"),
+			token:  agentToken,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "unknown resource",
+			path:   Path("Prometheus", "monitoring", "main", "0", "ScrapeConfig", "default", "unknown"),
+			token:  token,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown owner",
+			path:   Path("PrometheusAgent", "monitoring", "main", "0", "ScrapeConfig", "default", "static"),
+			token:  agentToken,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "shard out of range",
+			path:   Path("Prometheus", "monitoring", "main", "2", "ScrapeConfig", "default", "static"),
+			token:  token,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid shard",
+			path:   Path("Prometheus", "monitoring", "main", "$(SHARD)", "ScrapeConfig", "default", "static"),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			require.Equal(t, tc.status, w.Code)
+			if tc.status == http.StatusUnauthorized {
+				require.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.body, w.Body.String())
+		})
+	}
+
+	r.Delete(OwnerKey("Prometheus", "monitoring", "main"))
+
+	req := httptest.NewRequest(http.MethodGet, Path("Prometheus", "monitoring", "main", "0", "ScrapeConfig", "default", "static"), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLoadKey(t *testing.T) {
+	key, err := LoadKey("")
+	require.NoError(t, err)
+	require.Len(t, key, keySize)
+
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(file, []byte("0123456789abcdef0123456789abcdef\n"), 0o600))
+	key, err = LoadKey(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("0123456789abcdef0123456789abcdef"), key)
+
+	file = filepath.Join(dir, "short")
+	require.NoError(t, os.WriteFile(file, []byte("short"), 0o600))
+	_, err = LoadKey(file)
+	require.Error(t, err)
+
+	_, err = LoadKey(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
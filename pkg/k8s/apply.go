@@ -0,0 +1,463 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/csaupgrade"
+)
+
+// ApplyFieldManager is the field manager name used by the operator to write
+// the objects it manages with server-side apply.
+const ApplyFieldManager = "prometheus-operator"
+
+// reservedPrefix is the prefix of the labels and annotations reserved to the
+// operator.
+const reservedPrefix = "operator.prometheus.io/"
+
+// FieldConflict is a field declared by the operator which has been modified
+// by another field manager.
+type FieldConflict struct {
+	// Manager is the name of the field manager which modified the field.
+	Manager string
+	// Field is the path of the field.
+	Field string
+}
+
+// Drift describes the fields of a managed object which have been modified by
+// other field managers.
+type Drift struct {
+	// Kind is the kind of the object (e.g. "StatefulSet").
+	Kind string
+	// Object is the object returned by the API server. When Reverted is
+	// true, it is the object after the operator took back the ownership of
+	// the fields.
+	Object runtime.Object
+	// Conflicts is the list of modified fields.
+	Conflicts []FieldConflict
+	// Reverted is true when the operator took back the ownership of the
+	// fields. Otherwise the object isn't updated until the other field
+	// managers release the fields.
+	Reverted bool
+}
+
+// DriftHandler is called whenever a drift is detected on a managed object.
+type DriftHandler func(Drift)
+
+type driftHandlerKey struct{}
+
+type driftHandler struct {
+	handle DriftHandler
+	revert bool
+}
+
+// WithDriftHandler returns a copy of ctx which carries the handler used to
+// report the drifts detected while applying objects. When revert is false,
+// the operator doesn't take back the ownership of the fields modified by
+// other field managers and the apply fails instead.
+//
+// Without handler, the drifts are reverted.
+func WithDriftHandler(ctx context.Context, h DriftHandler, revert bool) context.Context {
+	return context.WithValue(ctx, driftHandlerKey{}, driftHandler{handle: h, revert: revert})
+}
+
+// revertDrift returns whether the fields modified by other field managers
+// should be forced.
+func revertDrift(ctx context.Context) bool {
+	h, ok := ctx.Value(driftHandlerKey{}).(driftHandler)
+	return !ok || h.revert
+}
+
+func reportDrift(ctx context.Context, d Drift) {
+	h, ok := ctx.Value(driftHandlerKey{}).(driftHandler)
+	if !ok || h.handle == nil {
+		return
+	}
+
+	h.handle(d)
+}
+
+// appliedObjects records the last state applied for each object.
+var appliedObjects = &applyCache{objects: map[string]appliedObject{}}
+
+// appliedObject identifies the state of an object after an apply.
+type appliedObject struct {
+	// digest is the digest of the desired state.
+	digest          string
+	uid             types.UID
+	resourceVersion string
+}
+
+// applyCache tells whether an object has been modified since the last apply.
+type applyCache struct {
+	mtx     sync.Mutex
+	objects map[string]appliedObject
+}
+
+func applyCacheKey(u *unstructured.Unstructured) string {
+	return strings.Join([]string{u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName()}, "/")
+}
+
+// desiredDigest returns the digest of the desired state.
+func desiredDigest(u *unstructured.Unstructured) (string, error) {
+	b, err := json.Marshal(u.Object)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// has returns true if the last apply of the object used the same desired
+// state.
+func (c *applyCache) has(key, digest string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.objects[key].digest == digest
+}
+
+// unchanged returns true if the last apply of the object used the same
+// desired state and if the object hasn't been modified since then. It is
+// always false for the objects without UID or resource version.
+func (c *applyCache) unchanged(key, digest string, obj runtime.Object) bool {
+	m, err := meta.Accessor(obj)
+	if err != nil || m.GetUID() == "" || m.GetResourceVersion() == "" {
+		return false
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.objects[key] == appliedObject{digest: digest, uid: m.GetUID(), resourceVersion: m.GetResourceVersion()}
+}
+
+func (c *applyCache) set(key, digest string, obj runtime.Object) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.objects[key] = appliedObject{digest: digest, uid: m.GetUID(), resourceVersion: m.GetResourceVersion()}
+}
+
+func (c *applyCache) delete(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.objects, key)
+}
+
+// applyTyped writes obj with server-side apply using the Get, Apply and Patch
+// methods of a typed client.
+func applyTyped[T runtime.Object, A any](
+	ctx context.Context,
+	obj runtime.Object,
+	getFn func(context.Context, string, metav1.GetOptions) (T, error),
+	applyFn func(context.Context, A, metav1.ApplyOptions) (T, error),
+	patchFn func(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) (T, error),
+) (T, error) {
+	var ret T
+
+	u, err := toApplyUnstructured(obj)
+	if err != nil {
+		return ret, err
+	}
+
+	b, err := json.Marshal(u.Object)
+	if err != nil {
+		return ret, err
+	}
+
+	var cfg A
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return ret, fmt.Errorf("failed to convert %s %q to apply configuration: %w", u.GetKind(), u.GetName(), err)
+	}
+
+	return serverSideApply(
+		ctx,
+		u,
+		func() (T, error) {
+			return getFn(ctx, u.GetName(), metav1.GetOptions{})
+		},
+		func(opts metav1.ApplyOptions) (T, error) {
+			return applyFn(ctx, cfg, opts)
+		},
+		func(patch []byte) (T, error) {
+			return patchFn(ctx, u.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+		},
+	)
+}
+
+// serverSideApply applies an object without forcing the conflicts first. If
+// other field managers have modified fields declared by the operator, the
+// conflicts are reported as drift and, unless the drift handler carried by
+// ctx says otherwise, the object is applied again with force to take back the
+// ownership of these fields.
+//
+// The fields owned by the update requests of previous operator versions are
+// handed over to the apply field manager afterwards: otherwise the fields
+// which aren't declared anymore would never be removed.
+//
+// Finally the labels and annotations with the reserved
+// "operator.prometheus.io/" prefix which aren't declared in desired are
+// removed, whoever added them.
+//
+// No apply request is sent when the desired state is the same as the last
+// apply and the object hasn't been modified since then.
+func serverSideApply[T runtime.Object](
+	ctx context.Context,
+	desired *unstructured.Unstructured,
+	getFn func() (T, error),
+	applyFn func(metav1.ApplyOptions) (T, error),
+	patchFn func([]byte) (T, error),
+) (T, error) {
+	var zero T
+	kind := desired.GetKind()
+
+	key := applyCacheKey(desired)
+	digest, err := desiredDigest(desired)
+	if err != nil {
+		return zero, err
+	}
+
+	if appliedObjects.has(key, digest) {
+		current, err := getFn()
+		switch {
+		case err == nil && appliedObjects.unchanged(key, digest, current):
+			return current, nil
+		case apierrors.IsNotFound(err):
+			appliedObjects.delete(key)
+		}
+	}
+
+	ret, err := applyFn(metav1.ApplyOptions{FieldManager: ApplyFieldManager})
+	if err != nil {
+		conflicts, ok := fieldManagerConflicts(err)
+		if !ok {
+			return zero, err
+		}
+
+		if !revertDrift(ctx) {
+			if len(conflicts) > 0 {
+				if current, gerr := getFn(); gerr == nil {
+					reportDrift(ctx, Drift{Kind: kind, Object: current, Conflicts: conflicts})
+				}
+			}
+
+			return zero, fmt.Errorf("%s %q has been modified by other field managers: %w", kind, desired.GetName(), err)
+		}
+
+		ret, err = applyFn(metav1.ApplyOptions{FieldManager: ApplyFieldManager, Force: true})
+		if err != nil {
+			return zero, err
+		}
+
+		if len(conflicts) > 0 {
+			reportDrift(ctx, Drift{Kind: kind, Object: ret, Conflicts: conflicts, Reverted: true})
+		}
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(ret, sets.New(PrometheusOperatorFieldManager), ApplyFieldManager)
+	if err != nil {
+		return zero, fmt.Errorf("failed to upgrade the managed fields: %w", err)
+	}
+
+	// The object is recorded as applied only when the managed fields have
+	// been upgraded.
+	upgraded := true
+	if patch != nil {
+		if _, err := patchFn(patch); err != nil {
+			// A conflict means that the object has been modified in the
+			// meantime, the upgrade will be retried on the next apply.
+			if !apierrors.IsConflict(err) {
+				return zero, fmt.Errorf("failed to upgrade the managed fields: %w", err)
+			}
+			upgraded = false
+		} else {
+			// Apply again to remove the fields which were owned by the
+			// previous operator version but aren't declared anymore.
+			ret, err = applyFn(metav1.ApplyOptions{FieldManager: ApplyFieldManager, Force: true})
+			if err != nil {
+				return zero, err
+			}
+		}
+	}
+
+	patch, err = removeReservedMetadataPatch(ret, desired)
+	if err != nil {
+		return zero, err
+	}
+
+	if patch == nil {
+		if upgraded {
+			appliedObjects.set(key, digest, ret)
+		}
+		return ret, nil
+	}
+
+	patched, err := patchFn(patch)
+	if err != nil {
+		// The object has been modified in the meantime, the removal will be
+		// retried on the next apply.
+		if apierrors.IsConflict(err) {
+			return ret, nil
+		}
+
+		return zero, fmt.Errorf("failed to remove the reserved labels and annotations: %w", err)
+	}
+
+	if upgraded {
+		appliedObjects.set(key, digest, patched)
+	}
+	return patched, nil
+}
+
+// removeReservedMetadataPatch returns the JSON patch removing the labels and
+// annotations of obj with the reserved prefix which aren't declared in
+// desired. It returns nil if there's nothing to remove.
+func removeReservedMetadataPatch(obj runtime.Object, desired *unstructured.Unstructured) ([]byte, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var ops []map[string]string
+	for _, f := range []struct {
+		field    string
+		current  map[string]string
+		declared map[string]string
+	}{
+		{field: "labels", current: m.GetLabels(), declared: desired.GetLabels()},
+		{field: "annotations", current: m.GetAnnotations(), declared: desired.GetAnnotations()},
+	} {
+		for _, k := range slices.Sorted(maps.Keys(f.current)) {
+			if !strings.HasPrefix(k, reservedPrefix) {
+				continue
+			}
+
+			if _, found := f.declared[k]; found {
+				continue
+			}
+
+			ops = append(ops, map[string]string{
+				"op":   "remove",
+				"path": "/metadata/" + f.field + "/" + escape.Replace(k),
+			})
+		}
+	}
+
+	if len(ops) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(ops)
+}
+
+// fieldManagerConflicts returns the field conflicts of an apply error. The
+// boolean is false if err isn't an apply conflict. Conflicts with fields
+// owned by the update requests of previous operator versions aren't
+// returned since they aren't considered as drift.
+func fieldManagerConflicts(err error) ([]FieldConflict, bool) {
+	var status apierrors.APIStatus
+	if !apierrors.IsConflict(err) || !errors.As(err, &status) {
+		return nil, false
+	}
+
+	details := status.Status().Details
+	if details == nil {
+		return nil, false
+	}
+
+	var (
+		found     bool
+		conflicts []FieldConflict
+	)
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		found = true
+
+		// The message looks like `conflict with "kubectl-edit" using apps/v1`.
+		var manager string
+		if _, err := fmt.Sscanf(cause.Message, "conflict with %q", &manager); err != nil {
+			manager = cause.Message
+		}
+
+		if manager == PrometheusOperatorFieldManager {
+			continue
+		}
+
+		conflicts = append(conflicts, FieldConflict{Manager: manager, Field: cause.Field})
+	}
+
+	return conflicts, found
+}
+
+// toApplyUnstructured converts obj into its unstructured representation
+// suitable for server-side apply: the type information is set and the fields
+// populated by the API server are removed.
+func toApplyUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	var u *unstructured.Unstructured
+	if uo, ok := obj.(*unstructured.Unstructured); ok {
+		u = uo.DeepCopy()
+	} else {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u = &unstructured.Unstructured{Object: content}
+	}
+
+	if u.GetKind() == "" {
+		gvks, _, err := clientgoscheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("missing apiVersion or kind and cannot assign it; %w", err)
+		}
+		u.SetGroupVersionKind(gvks[0])
+	}
+
+	delete(u.Object, "status")
+	for _, f := range []string{"creationTimestamp", "generation", "managedFields", "resourceVersion", "selfLink", "uid"} {
+		unstructured.RemoveNestedField(u.Object, "metadata", f)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,267 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServerSideApplyDrift(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		manager           string
+		data              map[string]string
+		expectedConflicts []FieldConflict
+	}{
+		{
+			name:    "field modified by another manager",
+			manager: "kubectl-edit",
+			data:    map[string]string{"key": "modified"},
+			expectedConflicts: []FieldConflict{
+				{Manager: "kubectl-edit", Field: ".data.key"},
+			},
+		},
+		{
+			name:    "field added by another manager",
+			manager: "kubectl-edit",
+			data:    map[string]string{"key": "value", "other": "value"},
+		},
+		{
+			name:    "field modified by a previous operator version",
+			manager: PrometheusOperatorFieldManager,
+			data:    map[string]string{"key": "modified"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var drifts []Drift
+			ctx := WithDriftHandler(context.Background(), func(d Drift) {
+				drifts = append(drifts, d)
+			}, true)
+
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "prometheus-rules",
+					Namespace: "default",
+				},
+				Data: map[string]string{"key": "value"},
+			}
+
+			cmClient := fake.NewClientset().CoreV1().ConfigMaps("default")
+			require.NoError(t, CreateOrUpdateConfigMap(ctx, cmClient, cm))
+
+			modified := cm.DeepCopy()
+			modified.Data = tc.data
+			_, err := cmClient.Update(ctx, modified, metav1.UpdateOptions{FieldManager: tc.manager})
+			require.NoError(t, err)
+
+			require.NoError(t, CreateOrUpdateConfigMap(ctx, cmClient, cm))
+
+			got, err := cmClient.Get(ctx, "prometheus-rules", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, "value", got.Data["key"])
+
+			if len(tc.expectedConflicts) == 0 {
+				require.Empty(t, drifts)
+				return
+			}
+
+			require.Len(t, drifts, 1)
+			require.Equal(t, "ConfigMap", drifts[0].Kind)
+			require.Equal(t, tc.expectedConflicts, drifts[0].Conflicts)
+			require.True(t, drifts[0].Reverted)
+
+			// The operator owns the field again.
+			drifts = nil
+			require.NoError(t, CreateOrUpdateConfigMap(ctx, cmClient, cm))
+			require.Empty(t, drifts)
+		})
+	}
+}
+
+func TestServerSideApplyUpgradeManagedFields(t *testing.T) {
+	ctx := context.Background()
+
+	// The secret has been created by a previous version of the operator.
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-web-config",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "prometheus",
+				"obsolete":               "true",
+			},
+		},
+	}
+
+	sClient := fake.NewClientset().CoreV1().Secrets("default")
+	_, err := sClient.Create(ctx, secret, metav1.CreateOptions{FieldManager: PrometheusOperatorFieldManager})
+	require.NoError(t, err)
+
+	delete(secret.Labels, "obsolete")
+	require.NoError(t, CreateOrUpdateSecret(ctx, sClient, secret))
+
+	got, err := sClient.Get(ctx, "prometheus-web-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"app.kubernetes.io/name": "prometheus"}, got.Labels)
+
+	require.Len(t, got.ManagedFields, 1)
+	require.Equal(t, ApplyFieldManager, got.ManagedFields[0].Manager)
+	require.Equal(t, metav1.ManagedFieldsOperationApply, got.ManagedFields[0].Operation)
+}
+
+func TestServerSideApplyDriftWithoutRevert(t *testing.T) {
+	var drifts []Drift
+	ctx := WithDriftHandler(context.Background(), func(d Drift) {
+		drifts = append(drifts, d)
+	}, false)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-rules",
+			Namespace: "default",
+		},
+		Data: map[string]string{"key": "value"},
+	}
+
+	cmClient := fake.NewClientset().CoreV1().ConfigMaps("default")
+	require.NoError(t, CreateOrUpdateConfigMap(ctx, cmClient, cm))
+
+	modified := cm.DeepCopy()
+	modified.Data = map[string]string{"key": "modified"}
+	_, err := cmClient.Update(ctx, modified, metav1.UpdateOptions{FieldManager: "kubectl-edit"})
+	require.NoError(t, err)
+
+	err = CreateOrUpdateConfigMap(ctx, cmClient, cm)
+	require.True(t, apierrors.IsConflict(err))
+
+	// The changes are kept.
+	got, err := cmClient.Get(ctx, "prometheus-rules", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "modified", got.Data["key"])
+
+	require.Len(t, drifts, 1)
+	require.Equal(t, []FieldConflict{{Manager: "kubectl-edit", Field: ".data.key"}}, drifts[0].Conflicts)
+	require.False(t, drifts[0].Reverted)
+}
+
+func TestServerSideApplySkipUnchanged(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		applies int
+		current = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "skip-unchanged",
+				Namespace:       "default",
+				UID:             "1",
+				ResourceVersion: "1",
+			},
+		}
+		getFn   = func() (*corev1.ConfigMap, error) { return current.DeepCopy(), nil }
+		patchFn = func([]byte) (*corev1.ConfigMap, error) { return nil, errors.New("unexpected patch") }
+		applyFn = func(metav1.ApplyOptions) (*corev1.ConfigMap, error) {
+			applies++
+			current.ResourceVersion = strconv.Itoa(applies + 1)
+			return current.DeepCopy(), nil
+		}
+	)
+
+	desired := func(value string) *unstructured.Unstructured {
+		u, err := toApplyUnstructured(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "skip-unchanged", Namespace: "default"},
+			Data:       map[string]string{"key": value},
+		})
+		require.NoError(t, err)
+		return u
+	}
+
+	for _, step := range []struct {
+		name            string
+		value           string
+		modify          bool
+		expectedApplies int
+	}{
+		{name: "first apply", value: "a", expectedApplies: 1},
+		{name: "unchanged", value: "a", expectedApplies: 1},
+		{name: "desired state changed", value: "b", expectedApplies: 2},
+		{name: "object modified", value: "b", modify: true, expectedApplies: 3},
+		{name: "unchanged again", value: "b", expectedApplies: 3},
+	} {
+		if step.modify {
+			current.ResourceVersion = "modified"
+		}
+
+		_, err := serverSideApply(ctx, desired(step.value), getFn, applyFn, patchFn)
+		require.NoError(t, err, step.name)
+		require.Equal(t, step.expectedApplies, applies, step.name)
+	}
+}
+
+func TestServerSideApplyRemoveReservedMetadataError(t *testing.T) {
+	var (
+		ctx = context.Background()
+		cm  = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "reserved-metadata",
+				Namespace: "default",
+				Labels:    map[string]string{"operator.prometheus.io/shard": "0"},
+			},
+		}
+		getFn   = func() (*corev1.ConfigMap, error) { return cm.DeepCopy(), nil }
+		applyFn = func(metav1.ApplyOptions) (*corev1.ConfigMap, error) { return cm.DeepCopy(), nil }
+	)
+
+	u, err := toApplyUnstructured(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "reserved-metadata", Namespace: "default"},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		patchErr error
+		err      bool
+	}{
+		{
+			name:     "conflict",
+			patchErr: apierrors.NewConflict(corev1.Resource("configmaps"), "reserved-metadata", errors.New("conflict")),
+		},
+		{
+			name:     "invalid",
+			patchErr: apierrors.NewInvalid(corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind(), "reserved-metadata", nil),
+			err:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			patchFn := func([]byte) (*corev1.ConfigMap, error) { return nil, tc.patchErr }
+
+			_, err := serverSideApply(ctx, u, getFn, applyFn, patchFn)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
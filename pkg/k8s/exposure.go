@@ -0,0 +1,56 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	typednetworkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
+)
+
+// CreateOrUpdateIngress creates or updates an Ingress resource with
+// server-side apply.
+func CreateOrUpdateIngress(ctx context.Context, ingClient typednetworkingv1.IngressInterface, ing *networkingv1.Ingress) (*networkingv1.Ingress, error) {
+	return applyTyped(ctx, ing, ingClient.Get, ingClient.Apply, ingClient.Patch)
+}
+
+// CreateOrUpdateUnstructured creates or updates with server-side apply a
+// resource for which no typed client is available (e.g. the Gateway API
+// resources).
+func CreateOrUpdateUnstructured(ctx context.Context, client dynamic.ResourceInterface, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	u, err := toApplyUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return serverSideApply(
+		ctx,
+		u,
+		func() (*unstructured.Unstructured, error) {
+			return client.Get(ctx, u.GetName(), metav1.GetOptions{})
+		},
+		func(opts metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+			return client.Apply(ctx, u.GetName(), u, opts)
+		},
+		func(patch []byte) (*unstructured.Unstructured, error) {
+			return client.Patch(ctx, u.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+		},
+	)
+}
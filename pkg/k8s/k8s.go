@@ -17,17 +17,13 @@ package k8s
 import (
 	"context"
 	"fmt"
-	"iter"
-	"maps"
 	"net/url"
 	"os"
-	"strings"
 
 	promversion "github.com/prometheus/common/version"
 	appsv1 "k8s.io/api/apps/v1"
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
-	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,7 +35,6 @@ import (
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/retry"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
@@ -199,70 +194,24 @@ func IsAllowed(
 	return len(missingPermissions) == 0, missingPermissions, nil
 }
 
-// UpdateDaemonSet merges metadata of existing DaemonSet with new one and updates it.
+// UpdateDaemonSet updates a DaemonSet resource with server-side apply.
 func UpdateDaemonSet(ctx context.Context, dmsClient clientappsv1.DaemonSetInterface, dset *appsv1.DaemonSet) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		existingDset, err := dmsClient.Get(ctx, dset.Name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-
-		mergeMetadata(&dset.ObjectMeta, existingDset.ObjectMeta)
-		// Propagate annotations set by kubectl on spec.template.annotations. e.g performing a rolling restart.
-		copyKubectlAnnotations(&dset.Spec.Template.ObjectMeta, existingDset.Spec.Template.Annotations)
-
-		_, err = dmsClient.Update(ctx, dset, metav1.UpdateOptions{})
-		return err
-	})
+	_, err := applyTyped(ctx, dset, dmsClient.Get, dmsClient.Apply, dmsClient.Patch)
+	return err
 }
 
-// CreateOrUpdateSecret merges metadata of existing Secret with new one and updates it.
+// CreateOrUpdateSecret creates or updates a Secret resource with server-side
+// apply.
 func CreateOrUpdateSecret(ctx context.Context, secretClient typedcorev1.SecretInterface, desired *corev1.Secret) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		existingSecret, err := secretClient.Get(ctx, desired.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-
-			_, err = secretClient.Create(ctx, desired, metav1.CreateOptions{})
-			return err
-		}
-
-		mutated := existingSecret.DeepCopyObject().(*corev1.Secret)
-		mergeMetadata(&desired.ObjectMeta, mutated.ObjectMeta)
-		if apiequality.Semantic.DeepEqual(existingSecret, desired) {
-			return nil
-		}
-		_, err = secretClient.Update(ctx, desired, metav1.UpdateOptions{})
-		return err
-	})
+	_, err := applyTyped(ctx, desired, secretClient.Get, secretClient.Apply, secretClient.Patch)
+	return err
 }
 
-// CreateOrUpdateConfigMap merges metadata of existing ConfigMap with new one and updates it.
+// CreateOrUpdateConfigMap creates or updates a ConfigMap resource with
+// server-side apply.
 func CreateOrUpdateConfigMap(ctx context.Context, cmClient typedcorev1.ConfigMapInterface, desired *corev1.ConfigMap) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		existingCM, err := cmClient.Get(ctx, desired.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-
-			_, err = cmClient.Create(ctx, desired, metav1.CreateOptions{})
-			return err
-		}
-
-		mutated := existingCM.DeepCopyObject().(*corev1.ConfigMap)
-		mergeMetadata(&desired.ObjectMeta, mutated.ObjectMeta)
-		if apiequality.Semantic.DeepEqual(existingCM, desired) {
-			return nil
-		}
-		_, err = cmClient.Update(ctx, desired, metav1.UpdateOptions{})
-		return err
-	})
+	_, err := applyTyped(ctx, desired, cmClient.Get, cmClient.Apply, cmClient.Patch)
+	return err
 }
 
 // IsAPIGroupVersionResourceSupported checks if given groupVersion and resource is supported by the cluster.
@@ -306,63 +255,3 @@ func AddTypeInformationToObject(obj runtime.Object) error {
 
 	return nil
 }
-
-// mergeMetadata takes labels and annotations from the old resource and merges
-// them into the new resource. If a key is present in both resources, the new
-// resource wins. All keys starting with the "operator.prometheus.io/" prefix
-// in the old resource are dropped before merging.
-// It also copies the ResourceVersion from the old resource to the new resource
-// to prevent update conflicts.
-func mergeMetadata(newObj *metav1.ObjectMeta, oldObj metav1.ObjectMeta) {
-	newObj.ResourceVersion = oldObj.ResourceVersion
-
-	newObj.SetLabels(mergeMap(maps.Collect(excludeOperatorPrefixSeq(oldObj.Labels)), maps.All(newObj.Labels)))
-	newObj.SetAnnotations(mergeMap(maps.Collect(excludeOperatorPrefixSeq(oldObj.Annotations)), maps.All(newObj.Annotations)))
-}
-
-// copyKubectlAnnotations copies the kubectl's annotations into the object
-// metadata.
-func copyKubectlAnnotations(objMeta *metav1.ObjectMeta, annotations map[string]string) {
-	objMeta.SetAnnotations(mergeMap(objMeta.Annotations, filterByPrefixSeq(annotations, "kubectl.kubernetes.io/")))
-}
-
-// excludeOperatorPrefixSeq returns a iterator over m excluding all keys
-// which start by the reserved operator's prefix.
-func excludeOperatorPrefixSeq(m map[string]string) iter.Seq2[string, string] {
-	return func(yield func(k, v string) bool) {
-		for k, v := range m {
-			if strings.HasPrefix(k, "operator.prometheus.io/") {
-				continue
-			}
-			if !yield(k, v) {
-				return
-			}
-		}
-	}
-}
-
-// filterByPrefixSeq returns a iterator over m for all keys matching the
-// prefix.
-func filterByPrefixSeq(m map[string]string, prefix string) iter.Seq2[string, string] {
-	return func(yield func(k, v string) bool) {
-		for k, v := range m {
-			if strings.HasPrefix(k, prefix) {
-				if !yield(k, v) {
-					return
-				}
-			}
-		}
-	}
-}
-
-// mergeMap returns a clone of m for which key-value pairs from seq have been added.
-func mergeMap(m map[string]string, seq iter.Seq2[string, string]) map[string]string {
-	// Don't mutate the input maps.
-	m = maps.Clone(m)
-	if m == nil {
-		m = make(map[string]string)
-	}
-
-	maps.Insert(m, seq)
-	return m
-}
@@ -26,7 +26,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 )
 
-func TestMergeMetadata_CreateOrUpdateSecret(t *testing.T) {
+func TestApplyMetadata_CreateOrUpdateSecret(t *testing.T) {
 	testCases := []struct {
 		name                string
 		expectedLabels      map[string]string
@@ -105,12 +105,14 @@ func TestMergeMetadata_CreateOrUpdateSecret(t *testing.T) {
 				},
 			}
 
-			sClient := fake.NewClientset(secret).CoreV1().Secrets(namespace)
+			sClient := fake.NewClientset().CoreV1().Secrets(namespace)
+			err := CreateOrUpdateSecret(context.Background(), sClient, secret)
+			require.NoError(t, err)
 
 			modifiedSecret := secret.DeepCopy()
 			maps.Copy(modifiedSecret.Labels, tc.modifiedLabels)
 			maps.Copy(modifiedSecret.Annotations, tc.modifiedAnnotations)
-			_, err := sClient.Update(context.Background(), modifiedSecret, metav1.UpdateOptions{})
+			_, err = sClient.Update(context.Background(), modifiedSecret, metav1.UpdateOptions{FieldManager: "kubectl-edit"})
 			require.NoError(t, err)
 
 			err = CreateOrUpdateSecret(context.Background(), sClient, secret)
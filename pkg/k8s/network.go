@@ -20,106 +20,38 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	clientdiscoveryv1 "k8s.io/client-go/kubernetes/typed/discovery/v1"
-	"k8s.io/client-go/util/retry"
 )
 
-// CreateOrUpdateService creates or updates a Service resource.
+// CreateOrUpdateService creates or updates a Service resource with
+// server-side apply.
 func CreateOrUpdateService(ctx context.Context, sclient typedcorev1.ServiceInterface, svc *corev1.Service) (*corev1.Service, error) {
-	var ret *corev1.Service
-
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		service, err := sclient.Get(ctx, svc.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-
-			ret, err = sclient.Create(ctx, svc, metav1.CreateOptions{})
-			return err
-		}
-
-		// Apply immutable fields from the existing service.
-		svc.Spec.IPFamilies = service.Spec.IPFamilies
-		svc.Spec.IPFamilyPolicy = service.Spec.IPFamilyPolicy
-		svc.Spec.ClusterIP = service.Spec.ClusterIP
-		svc.Spec.ClusterIPs = service.Spec.ClusterIPs
-
-		svc.SetOwnerReferences(mergeOwnerReferences(service.GetOwnerReferences(), svc.GetOwnerReferences()))
-		mergeMetadata(&svc.ObjectMeta, service.ObjectMeta)
-
-		ret, err = sclient.Update(ctx, svc, metav1.UpdateOptions{})
-		return err
-	})
-
-	return ret, err
-}
-
-func mergeOwnerReferences(oldObj []metav1.OwnerReference, newObj []metav1.OwnerReference) []metav1.OwnerReference {
-	existing := make(map[metav1.OwnerReference]bool)
-	for _, ownerRef := range oldObj {
-		existing[ownerRef] = true
-	}
-	for _, ownerRef := range newObj {
-		if _, ok := existing[ownerRef]; !ok {
-			oldObj = append(oldObj, ownerRef)
-		}
-	}
-	return oldObj
+	return applyTyped(ctx, svc, sclient.Get, sclient.Apply, sclient.Patch)
 }
 
-// CreateOrUpdateEndpoints creates or updates an Endpoints resource.
+// CreateOrUpdateEndpoints creates or updates an Endpoints resource with
+// server-side apply.
 //
 //nolint:staticcheck // Ignore SA1019 Endpoints is marked as deprecated.
 func CreateOrUpdateEndpoints(ctx context.Context, eclient typedcorev1.EndpointsInterface, eps *corev1.Endpoints) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		endpoints, err := eclient.Get(ctx, eps.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-
-			_, err = eclient.Create(ctx, eps, metav1.CreateOptions{})
-			return err
-		}
-
-		mergeMetadata(&eps.ObjectMeta, endpoints.ObjectMeta)
-
-		_, err = eclient.Update(ctx, eps, metav1.UpdateOptions{})
-		return err
-	})
+	_, err := applyTyped(ctx, eps, eclient.Get, eclient.Apply, eclient.Patch)
+	return err
 }
 
-// CreateOrUpdateEndpointSlice creates or updates an EndpointSlice resource.
+// CreateOrUpdateEndpointSlice creates or updates an EndpointSlice resource
+// with server-side apply. The resource is created with a generated name if
+// the name is empty.
 func CreateOrUpdateEndpointSlice(ctx context.Context, c clientdiscoveryv1.EndpointSliceInterface, eps *discoveryv1.EndpointSlice) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		if eps.Name == "" {
-			_, err := c.Create(ctx, eps, metav1.CreateOptions{})
-			return err
-		}
-
-		endpoints, err := c.Get(ctx, eps.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-
-			_, err = c.Create(ctx, eps, metav1.CreateOptions{})
-			return err
-		}
-
-		mergeMetadata(&eps.ObjectMeta, endpoints.ObjectMeta)
-
-		_, err = c.Update(ctx, eps, metav1.UpdateOptions{})
+	if eps.Name == "" {
+		_, err := c.Create(ctx, eps, metav1.CreateOptions{FieldManager: ApplyFieldManager})
 		return err
-	})
+	}
+
+	_, err := applyTyped(ctx, eps, c.Get, c.Apply, c.Patch)
+	return err
 }
 
 // EnsureCustomGoverningService is responsible for the following:
@@ -28,7 +28,7 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
-func TestMergeMetadata_CreateOrUpdateService(t *testing.T) {
+func TestApplyMetadata_CreateOrUpdateService(t *testing.T) {
 	testCases := []struct {
 		name                string
 		expectedLabels      map[string]string
@@ -118,7 +118,7 @@ func TestMergeMetadata_CreateOrUpdateService(t *testing.T) {
 	}
 }
 
-func TestMergeMetadata_CreateOrUpdateEndpoints(t *testing.T) {
+func TestApplyMetadata_CreateOrUpdateEndpoints(t *testing.T) {
 	testCases := []struct {
 		name                string
 		expectedLabels      map[string]string
@@ -261,6 +261,9 @@ func TestCreateOrUpdateImmutableFields(t *testing.T) {
 		_, err := CreateOrUpdateService(context.TODO(), svcClient, modifiedSvc)
 		require.NoError(t, err)
 
+		modifiedSvc, err = svcClient.Get(context.TODO(), "prometheus-operated-test", metav1.GetOptions{})
+		require.NoError(t, err)
+
 		require.Equal(t, service.Spec.IPFamilies, modifiedSvc.Spec.IPFamilies, "services Spec.IPFamilies are not equal, expected %q, got %q",
 			service.Spec.IPFamilies, modifiedSvc.Spec.IPFamilies)
 
@@ -0,0 +1,28 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	typednetworkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
+)
+
+// CreateOrUpdateNetworkPolicy creates or updates a NetworkPolicy resource
+// with server-side apply.
+func CreateOrUpdateNetworkPolicy(ctx context.Context, npClient typednetworkingv1.NetworkPolicyInterface, np *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
+	return applyTyped(ctx, np, npClient.Get, npClient.Apply, npClient.Patch)
+}
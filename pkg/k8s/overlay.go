@@ -0,0 +1,189 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// OverlayError is returned when an overlay is invalid or when it can't be
+// applied to a generated object.
+type OverlayError struct {
+	// Index is the position of the overlay in the list.
+	Index int
+	Err   error
+}
+
+func (e *OverlayError) Error() string {
+	return fmt.Sprintf("overlays[%d]: %s", e.Index, e.Err)
+}
+
+func (e *OverlayError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateOverlays checks that the overlays target a supported kind and that
+// their patches can be decoded.
+func ValidateOverlays(overlays []monitoringv1.Overlay) error {
+	for i, o := range overlays {
+		switch o.Target.Kind {
+		case monitoringv1.StatefulSetOverlayTargetKind,
+			monitoringv1.ServiceOverlayTargetKind,
+			monitoringv1.SecretOverlayTargetKind:
+		default:
+			return &OverlayError{Index: i, Err: fmt.Errorf("unsupported target kind %q", o.Target.Kind)}
+		}
+
+		if _, err := decodeOverlayPatch(o); err != nil {
+			return &OverlayError{Index: i, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// ApplyOverlays patches obj in place with the overlays which target the kind
+// and the name of obj. The overlays are applied in order.
+//
+// The overlays must only be applied to the objects owned by a single
+// resource, not to the objects shared by several resources (e.g. the default
+// governing Services).
+func ApplyOverlays(overlays []monitoringv1.Overlay, obj runtime.Object) error {
+	if len(overlays) == 0 {
+		return nil
+	}
+
+	gvks, _, err := clientgoscheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	kind := gvks[0].Kind
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	name, namespace := accessor.GetName(), accessor.GetNamespace()
+
+	for i, o := range overlays {
+		if string(o.Target.Kind) != kind {
+			continue
+		}
+
+		if o.Target.Name != nil && *o.Target.Name != name {
+			continue
+		}
+
+		if err := applyOverlay(o, obj); err != nil {
+			return &OverlayError{Index: i, Err: fmt.Errorf("failed to patch %s %q: %w", kind, name, err)}
+		}
+
+		if accessor.GetName() != name || accessor.GetNamespace() != namespace {
+			return &OverlayError{Index: i, Err: fmt.Errorf("failed to patch %s %q: the name and namespace can't be modified", kind, name)}
+		}
+	}
+
+	return nil
+}
+
+func applyOverlay(o monitoringv1.Overlay, obj runtime.Object) error {
+	patch, err := decodeOverlayPatch(o)
+	if err != nil {
+		return err
+	}
+
+	orig, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch ptr.Deref(o.Type, monitoringv1.JSONPatchOverlayPatchType) {
+	case monitoringv1.StrategicMergeOverlayPatchType:
+		patched, err = strategicpatch.StrategicMergePatch(orig, patch, obj)
+	default:
+		var p jsonpatch.Patch
+		p, err = jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return err
+		}
+		patched, err = p.Apply(orig)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reset the object before decoding the result, otherwise the fields
+	// removed by the patch would be preserved.
+	v := reflect.ValueOf(obj).Elem()
+	v.Set(reflect.Zero(v.Type()))
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(obj)
+}
+
+// decodeOverlayPatch returns the JSON representation of the overlay's patch.
+func decodeOverlayPatch(o monitoringv1.Overlay) ([]byte, error) {
+	patch, err := yaml.YAMLToJSON([]byte(o.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the patch: %w", err)
+	}
+
+	switch t := ptr.Deref(o.Type, monitoringv1.JSONPatchOverlayPatchType); t {
+	case monitoringv1.JSONPatchOverlayPatchType:
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the JSON patch: %w", err)
+		}
+
+		for i, op := range p {
+			switch op.Kind() {
+			case "add", "remove", "replace", "move", "copy", "test":
+			default:
+				return nil, fmt.Errorf("operation %d: unsupported operation %q", i, op.Kind())
+			}
+
+			if _, err := op.Path(); err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i, err)
+			}
+		}
+
+	case monitoringv1.StrategicMergeOverlayPatchType:
+		var m map[string]any
+		if err := json.Unmarshal(patch, &m); err != nil {
+			return nil, fmt.Errorf("the strategic merge patch must be an object: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", t)
+	}
+
+	return patch, nil
+}
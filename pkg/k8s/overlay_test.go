@@ -0,0 +1,301 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestValidateOverlays(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		overlay monitoringv1.Overlay
+		err     bool
+	}{
+		{
+			name: "valid JSON patch",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+				Patch:  `[{"op": "add", "path": "/metadata/labels/foo", "value": "bar"}]`,
+			},
+		},
+		{
+			name: "valid JSON patch in YAML format",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.ServiceOverlayTargetKind},
+				Patch: `
+- op: remove
+  path: /spec/clusterIP
+`,
+			},
+		},
+		{
+			name: "valid strategic merge patch",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.SecretOverlayTargetKind},
+				Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+				Patch:  `{"metadata": {"annotations": {"foo": "bar"}}}`,
+			},
+		},
+		{
+			name: "unsupported kind",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: "Pod"},
+				Patch:  `[]`,
+			},
+			err: true,
+		},
+		{
+			name: "unsupported patch type",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+				Type:   ptr.To(monitoringv1.OverlayPatchType("MergePatch")),
+				Patch:  `{}`,
+			},
+			err: true,
+		},
+		{
+			name: "JSON patch isn't a list",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+				Patch:  `{"op": "remove", "path": "/spec/replicas"}`,
+			},
+			err: true,
+		},
+		{
+			name: "unsupported JSON patch operation",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+				Patch:  `[{"op": "delete", "path": "/spec/replicas"}]`,
+			},
+			err: true,
+		},
+		{
+			name: "JSON patch operation without path",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+				Patch:  `[{"op": "remove"}]`,
+			},
+			err: true,
+		},
+		{
+			name: "strategic merge patch isn't an object",
+			overlay: monitoringv1.Overlay{
+				Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+				Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+				Patch:  `[{"op": "remove", "path": "/spec/replicas"}]`,
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			overlays := []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Patch:  `[]`,
+				},
+				tc.overlay,
+			}
+
+			err := ValidateOverlays(overlays)
+			if !tc.err {
+				require.NoError(t, err)
+				return
+			}
+
+			var oerr *OverlayError
+			require.ErrorAs(t, err, &oerr)
+			require.Equal(t, 1, oerr.Index)
+		})
+	}
+}
+
+func TestApplyOverlays(t *testing.T) {
+	newStatefulSet := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "prometheus-k8s",
+				Namespace: "default",
+				Labels:    map[string]string{"app.kubernetes.io/name": "prometheus"},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To(int32(2)),
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "prometheus-k8s-db"},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		overlays []monitoringv1.Overlay
+		expected func(*appsv1.StatefulSet)
+		err      bool
+	}{
+		{
+			name: "no overlay",
+		},
+		{
+			name: "JSON patch",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Patch: `
+- op: add
+  path: /spec/volumeClaimTemplates/0/metadata/labels
+  value:
+    backup: "true"
+- op: remove
+  path: /spec/replicas
+`,
+				},
+			},
+			expected: func(sset *appsv1.StatefulSet) {
+				sset.Spec.VolumeClaimTemplates[0].Labels = map[string]string{"backup": "true"}
+				sset.Spec.Replicas = nil
+			},
+		},
+		{
+			name: "strategic merge patch",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+					Patch: `
+metadata:
+  labels:
+    team: observability
+spec:
+  volumeClaimTemplates:
+  - metadata:
+      name: prometheus-k8s-db
+      annotations:
+        backup: "true"
+`,
+				},
+			},
+			expected: func(sset *appsv1.StatefulSet) {
+				sset.Labels["team"] = "observability"
+				sset.Spec.VolumeClaimTemplates[0].Annotations = map[string]string{"backup": "true"}
+			},
+		},
+		{
+			name: "overlays applied in order",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Patch:  `[{"op": "add", "path": "/metadata/labels/team", "value": "a"}]`,
+				},
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Patch:  `[{"op": "replace", "path": "/metadata/labels/team", "value": "b"}]`,
+				},
+			},
+			expected: func(sset *appsv1.StatefulSet) {
+				sset.Labels["team"] = "b"
+			},
+		},
+		{
+			name: "other kind and other name are ignored",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.ServiceOverlayTargetKind},
+					Patch:  `[{"op": "remove", "path": "/spec/replicas"}]`,
+				},
+				{
+					Target: monitoringv1.OverlayTarget{
+						Kind: monitoringv1.StatefulSetOverlayTargetKind,
+						Name: ptr.To("prometheus-k8s-shard-1"),
+					},
+					Patch: `[{"op": "remove", "path": "/spec/replicas"}]`,
+				},
+			},
+		},
+		{
+			name: "matching name",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{
+						Kind: monitoringv1.StatefulSetOverlayTargetKind,
+						Name: ptr.To("prometheus-k8s"),
+					},
+					Patch: `[{"op": "remove", "path": "/spec/replicas"}]`,
+				},
+			},
+			expected: func(sset *appsv1.StatefulSet) {
+				sset.Spec.Replicas = nil
+			},
+		},
+		{
+			name: "failing JSON patch",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Patch:  `[{"op": "remove", "path": "/metadata/annotations/foo"}]`,
+				},
+			},
+			err: true,
+		},
+		{
+			name: "unknown field",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+					Patch:  `{"spec": {"replica": 1}}`,
+				},
+			},
+			err: true,
+		},
+		{
+			name: "modified name",
+			overlays: []monitoringv1.Overlay{
+				{
+					Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+					Patch:  `[{"op": "replace", "path": "/metadata/name", "value": "foo"}]`,
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset := newStatefulSet()
+			err := ApplyOverlays(tc.overlays, sset)
+			if tc.err {
+				var oerr *OverlayError
+				require.ErrorAs(t, err, &oerr)
+				return
+			}
+			require.NoError(t, err)
+
+			expected := newStatefulSet()
+			if tc.expected != nil {
+				tc.expected(expected)
+			}
+			require.Equal(t, expected, sset)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	typedpolicyv1 "k8s.io/client-go/kubernetes/typed/policy/v1"
+)
+
+// CreateOrUpdatePodDisruptionBudget creates or updates a PodDisruptionBudget
+// resource with server-side apply.
+func CreateOrUpdatePodDisruptionBudget(ctx context.Context, pdbClient typedpolicyv1.PodDisruptionBudgetInterface, pdb *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error) {
+	return applyTyped(ctx, pdb, pdbClient.Get, pdbClient.Apply, pdbClient.Patch)
+}
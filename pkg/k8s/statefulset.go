@@ -25,14 +25,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
-	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 )
 
 // CreateStatefulSetOrPatchLabels creates a StatefulSet resource.
 // If the StatefulSet already exists, it patches the labels from the input StatefulSet.
+//
+// The fields are owned by the PrometheusOperatorFieldManager field manager
+// until the next update hands them over to the ApplyFieldManager field
+// manager.
 func CreateStatefulSetOrPatchLabels(ctx context.Context, ssetClient clientappsv1.StatefulSetInterface, sset *appsv1.StatefulSet) (*appsv1.StatefulSet, error) {
-	created, err := ssetClient.Create(ctx, sset, metav1.CreateOptions{})
+	created, err := ssetClient.Create(ctx, sset, metav1.CreateOptions{FieldManager: PrometheusOperatorFieldManager})
 	if err == nil {
 		return created, nil
 	}
@@ -60,9 +63,9 @@ func CreateStatefulSetOrPatchLabels(ctx context.Context, ssetClient clientappsv1
 	)
 }
 
-// ForceUpdateStatefulSet updates a StatefulSet resource preserving custom
-// labels and annotations. But when the update operation tries to update
-// immutable fields for example, `.spec.selector`), the function will delete
+// ForceUpdateStatefulSet updates a StatefulSet resource with server-side
+// apply. But when the update operation tries to update immutable fields (for
+// example, `.spec.selector`), the function will delete
 // the statefulset (relying on the higher-level controller to re-create the
 // resource during the next reconciliation).
 //
@@ -70,7 +73,7 @@ func CreateStatefulSetOrPatchLabels(ctx context.Context, ssetClient clientappsv1
 // function is given a string explaining the reason why the update was not
 // possible.
 func ForceUpdateStatefulSet(ctx context.Context, ssetClient clientappsv1.StatefulSetInterface, sset *appsv1.StatefulSet, onDeleteFunc func(string)) error {
-	err := updateStatefulSet(ctx, ssetClient, sset)
+	err := applyStatefulSet(ctx, ssetClient, sset)
 	if err == nil {
 		return nil
 	}
@@ -93,20 +96,19 @@ func ForceUpdateStatefulSet(ctx context.Context, ssetClient clientappsv1.Statefu
 	return ssetClient.Delete(ctx, sset.GetName(), metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationForeground)})
 }
 
-// updateStatefulSet updates a StatefulSet resource preserving custom labels and annotations from the current resource.
-func updateStatefulSet(ctx context.Context, sstClient clientappsv1.StatefulSetInterface, sset *appsv1.StatefulSet) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		existingSset, err := sstClient.Get(ctx, sset.Name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-
-		mergeMetadata(&sset.ObjectMeta, existingSset.ObjectMeta)
-		// Propagate annotations set by kubectl on spec.template.annotations. e.g performing a rolling restart.
-		copyKubectlAnnotations(&sset.Spec.Template.ObjectMeta, existingSset.Spec.Template.Annotations)
+// applyStatefulSet updates a StatefulSet resource with server-side apply.
+// The fields set by other field managers (e.g. the annotations added by
+// `kubectl rollout restart` to the pod template) are preserved.
+func applyStatefulSet(ctx context.Context, ssetClient clientappsv1.StatefulSetInterface, sset *appsv1.StatefulSet) error {
+	_, err := applyTyped(ctx, sset, ssetClient.Get, ssetClient.Apply, ssetClient.Patch)
+	return err
+}
 
-		_, err = sstClient.Update(ctx, sset, metav1.UpdateOptions{})
-		return err
-	})
+// OrphanDeleteStatefulSet deletes a StatefulSet resource without deleting its
+// pods. It is used to modify immutable fields of the StatefulSet spec (for
+// example `.spec.volumeClaimTemplates`) without disrupting the workload: the
+// higher-level controller re-creates the resource during the next
+// reconciliation and the new StatefulSet adopts the orphaned pods.
+func OrphanDeleteStatefulSet(ctx context.Context, ssetClient clientappsv1.StatefulSetInterface, name string) error {
+	return ssetClient.Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationOrphan)})
 }
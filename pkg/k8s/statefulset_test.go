@@ -16,6 +16,7 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"maps"
 	"reflect"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -33,12 +35,12 @@ func TestPropagateKubectlTemplateAnnotations(t *testing.T) {
 	testCases := []struct {
 		name     string
 		source   map[string]string
+		kubectl  map[string]string
 		dest     map[string]string
 		expected map[string]string
 	}{
 		{
-			name:     "no annotations",
-			expected: map[string]string{},
+			name: "no annotations",
 		},
 		{
 			name: "add owned annotation",
@@ -66,18 +68,21 @@ func TestPropagateKubectlTemplateAnnotations(t *testing.T) {
 			source: map[string]string{
 				"test-key": "test-value",
 			},
-			dest:     map[string]string{},
-			expected: map[string]string{},
+			dest: map[string]string{},
 		},
 		{
 			name: "add kubectl annotation",
 			source: map[string]string{
 				"test-key": "test-value",
 			},
-			dest: map[string]string{
+			kubectl: map[string]string{
 				"kubectl.kubernetes.io/restartedAt": "now",
 			},
+			dest: map[string]string{
+				"test-key": "test-value",
+			},
 			expected: map[string]string{
+				"test-key":                          "test-value",
 				"kubectl.kubernetes.io/restartedAt": "now",
 			},
 		},
@@ -86,16 +91,22 @@ func TestPropagateKubectlTemplateAnnotations(t *testing.T) {
 			source: map[string]string{
 				"kubectl.kubernetes.io/restartedAt": "yesterday",
 			},
-			dest: map[string]string{
+			kubectl: map[string]string{
 				"kubectl.kubernetes.io/restartedAt": "now",
 			},
+			dest: map[string]string{
+				"kubectl.kubernetes.io/restartedAt": "yesterday",
+			},
 			expected: map[string]string{
 				"kubectl.kubernetes.io/restartedAt": "yesterday",
 			},
 		},
 		{
-			name: "remove kubectl annotation",
+			name: "remove owned annotation with kubectl annotation",
 			source: map[string]string{
+				"test-key": "test-value",
+			},
+			kubectl: map[string]string{
 				"kubectl.kubernetes.io/restartedAt": "now",
 			},
 			dest: map[string]string{},
@@ -123,25 +134,44 @@ func TestPropagateKubectlTemplateAnnotations(t *testing.T) {
 				},
 			}
 
-			ssetClient := fake.NewClientset(sset).AppsV1().StatefulSets(namespace)
+			ssetClient := fake.NewClientset().AppsV1().StatefulSets(namespace)
+			err := applyStatefulSet(ctx, ssetClient, sset)
+			require.NoError(t, err)
+
+			if len(tc.kubectl) > 0 {
+				// Simulate `kubectl rollout restart`.
+				patch, err := json.Marshal(map[string]any{
+					"spec": map[string]any{
+						"template": map[string]any{
+							"metadata": map[string]any{
+								"annotations": tc.kubectl,
+							},
+						},
+					},
+				})
+				require.NoError(t, err)
+
+				_, err = ssetClient.Patch(ctx, "prometheus", types.StrategicMergePatchType, patch, metav1.PatchOptions{FieldManager: "kubectl-rollout"})
+				require.NoError(t, err)
+			}
 
 			modifiedSset := sset.DeepCopy()
 			modifiedSset.Spec.Template.Annotations = tc.dest
 
-			err := updateStatefulSet(ctx, ssetClient, modifiedSset)
+			err = applyStatefulSet(ctx, ssetClient, modifiedSset)
 			require.NoError(t, err)
 
 			updatedSset, err := ssetClient.Get(ctx, "prometheus", metav1.GetOptions{})
 			require.NoError(t, err)
 
-			if !reflect.DeepEqual(tc.expected, updatedSset.Spec.Template.Annotations) {
+			if !maps.Equal(tc.expected, updatedSset.Spec.Template.Annotations) {
 				t.Errorf("expected annotations %q, got %q", tc.expected, updatedSset.Spec.Template.Annotations)
 			}
 		})
 	}
 }
 
-func TestMergeMetadata_UpdateStatefulSet(t *testing.T) {
+func TestApplyMetadata_UpdateStatefulSet(t *testing.T) {
 	testCases := []struct {
 		name                string
 		expectedLabels      map[string]string
@@ -213,7 +243,7 @@ func TestMergeMetadata_UpdateStatefulSet(t *testing.T) {
 			_, err := ssetClient.Update(context.Background(), modifiedSset, metav1.UpdateOptions{})
 			require.NoError(t, err)
 
-			err = updateStatefulSet(context.Background(), ssetClient, sset)
+			err = applyStatefulSet(context.Background(), ssetClient, sset)
 			require.NoError(t, err)
 
 			updatedSset, err := ssetClient.Get(context.Background(), "prometheus", metav1.GetOptions{})
@@ -764,7 +764,7 @@ func TestSyncEndpointsWhenServiceSyncFails(t *testing.T) {
 	)
 
 	fakeClient.PrependReactor(
-		"get", "services",
+		"patch", "services",
 		func(_ ktesting.Action) (bool, runtime.Object, error) {
 			return true, nil, apierrors.NewInternalError(errors.New("apiserver is down"))
 		},
@@ -916,7 +916,7 @@ func TestSyncEndpointSliceWhenServiceSyncFails(t *testing.T) {
 	)
 
 	fakeClient.PrependReactor(
-		"get", "services",
+		"patch", "services",
 		func(_ ktesting.Action) (bool, runtime.Object, error) {
 			return true, nil, apierrors.NewInternalError(errors.New("apiserver is down"))
 		},
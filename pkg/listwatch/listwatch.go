@@ -107,24 +107,14 @@ func NewNamespaceListWatchFromClient(
 			}
 		}
 
-		return cache.NewFilteredListWatchFromClient(
-			corev1Client.RESTClient(),
-			"namespaces",
-			metav1.NamespaceAll,
-			tweak,
-		), true, nil
+		return newNamespaceListWatch(corev1Client, tweak), true, nil
 	}
 
 	if listWatchAllowed && metadataNameLabelSupported {
 		l.Debug("using privileged namespace lister/watcher")
-		return cache.NewFilteredListWatchFromClient(
-			corev1Client.RESTClient(),
-			"namespaces",
-			metav1.NamespaceAll,
-			func(options *metav1.ListOptions) {
-				TweakByLabel(options, "kubernetes.io/metadata.name", IncludeFilterType, allowedNamespaces)
-			},
-		), true, nil
+		return newNamespaceListWatch(corev1Client, func(options *metav1.ListOptions) {
+			TweakByLabel(options, "kubernetes.io/metadata.name", IncludeFilterType, allowedNamespaces)
+		}), true, nil
 	}
 
 	// At this point, the operator has no list/watch permissions on the
@@ -167,6 +157,27 @@ func NewNamespaceListWatchFromClient(
 	return newPollBasedListerWatcher(ctx, l, corev1Client, namespaces), false, nil
 }
 
+// newNamespaceListWatch returns a lister/watcher for namespaces using the
+// typed client. Unlike cache.NewFilteredListWatchFromClient, it doesn't
+// require a REST client which means that it works with any implementation of
+// the CoreV1 interface (e.g. the fake clientset).
+//
+// If the client implements IsWatchListSemanticsUnSupported(), the reflector
+// honors it.
+func newNamespaceListWatch(corev1Client typedcorev1.CoreV1Interface, optionsModifier func(*metav1.ListOptions)) cache.ListerWatcher {
+	return cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			optionsModifier(&options)
+			return corev1Client.Namespaces().List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.Watch = true
+			optionsModifier(&options)
+			return corev1Client.Namespaces().Watch(ctx, options)
+		},
+	}, corev1Client)
+}
+
 // IsAllNamespaces checks if the given map of namespaces
 // contains only v1.NamespaceAll.
 func IsAllNamespaces(namespaces map[string]struct{}) bool {
@@ -71,6 +71,9 @@ type Config struct {
 	// Repair policy
 	RepairPolicy RepairPolicy
 
+	// Drift policy for the managed objects.
+	DriftPolicy DriftPolicy
+
 	// Event recorder factory.
 	EventRecorderFactory EventRecorderFactory
 
@@ -120,6 +123,7 @@ func DefaultConfig(cpu, memory string) Config {
 			},
 		},
 		RepairPolicy: NoneRepairPolicy,
+		DriftPolicy:  RevertDriftPolicy,
 	}
 }
 
@@ -41,6 +41,10 @@ const (
 
 	// InvalidConfiguration is a generic reason for selected resources that are not valid.
 	InvalidConfiguration = "InvalidConfiguration"
+
+	// QuotaExceeded is the reason for selected resources that exceed the
+	// MonitoringQuota of their namespace.
+	QuotaExceeded = "QuotaExceeded"
 )
 
 // ConfigurationResource is a type constraint that permits only the specific pointer types for configuration resources
@@ -145,18 +149,35 @@ func (crs *ConfigResourceSyncer) GetBindingIndex(bindings []monitoringv1.Workloa
 	return -1
 }
 
-func (crs *ConfigResourceSyncer) newBinding(conditions []monitoringv1.ConfigResourceCondition) monitoringv1.WorkloadBinding {
-	return monitoringv1.WorkloadBinding{
+// BindingOption customizes the workload binding of a configuration resource.
+type BindingOption func(*monitoringv1.WorkloadBinding)
+
+// WithBindingShards records the shards of the workload which use the
+// configuration resource.
+func WithBindingShards(shards []int32) BindingOption {
+	return func(b *monitoringv1.WorkloadBinding) {
+		b.Shards = shards
+	}
+}
+
+func (crs *ConfigResourceSyncer) newBinding(conditions []monitoringv1.ConfigResourceCondition, opts ...BindingOption) monitoringv1.WorkloadBinding {
+	b := monitoringv1.WorkloadBinding{
 		Namespace:  crs.workload.GetNamespace(),
 		Name:       crs.workload.GetName(),
 		Resource:   crs.gvr.Resource,
 		Group:      crs.gvr.Group,
 		Conditions: conditions,
 	}
+
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	return b
 }
 
-func (crs *ConfigResourceSyncer) newUnstructuredBinding(conditions []monitoringv1.ConfigResourceCondition) (map[string]any, error) {
-	b, err := json.Marshal(crs.newBinding(conditions))
+func (crs *ConfigResourceSyncer) newUnstructuredBinding(binding monitoringv1.WorkloadBinding) (map[string]any, error) {
+	b, err := json.Marshal(binding)
 	if err != nil {
 		return nil, err
 	}
@@ -180,8 +201,9 @@ type ConfigurationObject interface {
 // UpdateBinding updates the workload's binding in the configuration resource's
 // status subresource.
 // If the binding is up-to-date, this is a no-operation.
-func (crs *ConfigResourceSyncer) UpdateBinding(ctx context.Context, configResource ConfigurationObject, conditions []monitoringv1.ConfigResourceCondition) error {
+func (crs *ConfigResourceSyncer) UpdateBinding(ctx context.Context, configResource ConfigurationObject, conditions []monitoringv1.ConfigResourceCondition, opts ...BindingOption) error {
 	bindings := configResource.Bindings()
+	newBinding := crs.newBinding(conditions, opts...)
 
 	if len(bindings) == 0 {
 		// When the bindings slice is empty, update the status instead of patch
@@ -195,7 +217,7 @@ func (crs *ConfigResourceSyncer) UpdateBinding(ctx context.Context, configResour
 			Object: map[string]any{},
 		}
 
-		binding, err := crs.newUnstructuredBinding(conditions)
+		binding, err := crs.newUnstructuredBinding(newBinding)
 		if err != nil {
 			return err
 		}
@@ -223,7 +245,7 @@ func (crs *ConfigResourceSyncer) UpdateBinding(ctx context.Context, configResour
 		return nil
 	}
 
-	patch, err := crs.updateBindingPatch(bindings, conditions)
+	patch, err := crs.updateBindingPatch(bindings, newBinding)
 	if err != nil {
 		return fmt.Errorf("failed to build patch status: %w", err)
 	}
@@ -334,10 +356,10 @@ func toGroupVersionResource(o runtime.Object) schema.GroupVersionResource {
 }
 
 // updateBindingPatch returns a RFC-6902 JSON patch which updates the
-// conditions of the resource's status.
+// conditions (and shards) of the resource's status.
 // If the binding doesn't exist, the patch adds it to the status.
 // If the binding is already up-to-date, the return value is empty.
-func (crs *ConfigResourceSyncer) updateBindingPatch(bindings []monitoringv1.WorkloadBinding, conditions []monitoringv1.ConfigResourceCondition) ([]byte, error) {
+func (crs *ConfigResourceSyncer) updateBindingPatch(bindings []monitoringv1.WorkloadBinding, binding monitoringv1.WorkloadBinding) ([]byte, error) {
 	i := crs.GetBindingIndex(bindings)
 	if i < 0 {
 		// Append the workload binding to the slice.
@@ -345,26 +367,43 @@ func (crs *ConfigResourceSyncer) updateBindingPatch(bindings []monitoringv1.Work
 			patchOperation{
 				Op:    "add",
 				Path:  "/status/bindings/-",
-				Value: crs.newBinding(conditions),
+				Value: binding,
 			},
 		})
 	}
 
-	// No need to update the binding if the conditions haven't changed
-	if equalConfigResourceConditions(bindings[i].Conditions, conditions) {
+	var ops patch
+
+	if !equalConfigResourceConditions(bindings[i].Conditions, binding.Conditions) {
+		ops = append(ops, patchOperation{
+			Op:    "replace",
+			Path:  fmt.Sprintf("/status/bindings/%d/conditions", i),
+			Value: binding.Conditions,
+		})
+	}
+
+	if !slices.Equal(bindings[i].Shards, binding.Shards) {
+		op := patchOperation{
+			// The "add" operation replaces the value if the member exists.
+			Op:    "add",
+			Path:  fmt.Sprintf("/status/bindings/%d/shards", i),
+			Value: binding.Shards,
+		}
+		if len(binding.Shards) == 0 {
+			op = patchOperation{
+				Op:   "remove",
+				Path: op.Path,
+			}
+		}
+		ops = append(ops, op)
+	}
+
+	// No need to update the binding if nothing has changed.
+	if len(ops) == 0 {
 		return nil, nil
 	}
 
-	return json.Marshal(
-		append(
-			crs.testBindingExists(i),
-			patchOperation{
-				Op:    "replace",
-				Path:  fmt.Sprintf("/status/bindings/%d/conditions", i),
-				Value: conditions,
-			},
-		),
-	)
+	return json.Marshal(append(crs.testBindingExists(i), ops...))
 }
 
 // removeBindingPatch returns a RFC-6902 JSON patch which removes the
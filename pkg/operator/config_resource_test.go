@@ -207,3 +207,51 @@ func TestConfigResStatusConditionsEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateBindingPatchWithShards(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		TypeMeta:   metav1.TypeMeta{Kind: monitoringv1.PrometheusesKind, APIVersion: monitoringv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+	}
+	crs := NewConfigResourceSyncer(p, nil, nil)
+
+	conditions := []monitoringv1.ConfigResourceCondition{
+		{
+			Type:   monitoringv1.Accepted,
+			Status: monitoringv1.ConditionTrue,
+		},
+	}
+	bindings := []monitoringv1.WorkloadBinding{crs.newBinding(conditions, WithBindingShards([]int32{0}))}
+
+	for _, tc := range []struct {
+		name     string
+		shards   []int32
+		expected string
+	}{
+		{
+			name:   "unchanged",
+			shards: []int32{0},
+		},
+		{
+			name:     "new shards",
+			shards:   []int32{0, 1},
+			expected: `{"op":"add","path":"/status/bindings/0/shards","value":[0,1]}]`,
+		},
+		{
+			name:     "no shards",
+			expected: `{"op":"remove","path":"/status/bindings/0/shards"}]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := crs.updateBindingPatch(bindings, crs.newBinding(conditions, WithBindingShards(tc.shards)))
+			require.NoError(t, err)
+
+			if tc.expected == "" {
+				require.Empty(t, b)
+				return
+			}
+
+			require.Contains(t, string(b), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+)
+
+const applyManagedObjectAction = "ApplyManagedObject"
+
+// DriftPolicy defines how the operator handles the fields of the managed
+// objects which have been modified by other field managers.
+type DriftPolicy string
+
+const (
+	// RevertDriftPolicy takes back the ownership of the modified fields.
+	RevertDriftPolicy DriftPolicy = "revert"
+	// ReportDriftPolicy keeps the modified fields: the managed object isn't
+	// updated until the other field managers release them.
+	ReportDriftPolicy DriftPolicy = "report"
+)
+
+// Set implements the flag.Value interface.
+func (p *DriftPolicy) Set(value string) error {
+	*p = DriftPolicy(value)
+
+	switch *p {
+	case RevertDriftPolicy:
+	case ReportDriftPolicy:
+	default:
+		return fmt.Errorf("invalid value: %s", value)
+	}
+
+	return nil
+}
+
+func (p *DriftPolicy) String() string { return string(*p) }
+
+// WithDriftReporting returns a copy of ctx which reports the drifts detected
+// while applying the managed objects. A drift is logged, counted in the
+// metrics and recorded as a warning event on the object. The changes are
+// reverted unless the policy is ReportDriftPolicy.
+func WithDriftReporting(ctx context.Context, logger *slog.Logger, m *Metrics, er *EventRecorder, policy DriftPolicy) context.Context {
+	return k8s.WithDriftHandler(ctx, func(d k8s.Drift) {
+		fields := make([]string, 0, len(d.Conflicts))
+		for _, c := range d.Conflicts {
+			fields = append(fields, fmt.Sprintf("%s (%s)", c.Field, c.Manager))
+		}
+
+		var name string
+		if o, err := meta.Accessor(d.Object); err == nil {
+			name = o.GetName()
+		}
+
+		outcome := "the changes have been reverted"
+		if !d.Reverted {
+			outcome = "the object isn't updated until the changes are removed"
+		}
+
+		logger.Warn("managed object modified by other field managers, "+outcome, "kind", d.Kind, "name", name, "fields", strings.Join(fields, ", "))
+
+		if m != nil {
+			m.driftCounter.WithLabelValues(d.Kind).Inc()
+		}
+
+		if er != nil {
+			er.Eventf(d.Object, corev1.EventTypeWarning, DriftDetectedEvent, applyManagedObjectAction, "%s %s was modified by other field managers, %s: %s", d.Kind, name, outcome, strings.Join(fields, ", "))
+		}
+	}, policy != ReportDriftPolicy)
+}
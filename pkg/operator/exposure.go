@@ -0,0 +1,569 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+)
+
+const (
+	// ExposureLabelKey is the label identifying the objects generated to
+	// expose the web server of a workload. The value is the base name of the
+	// workload's objects (e.g. "prometheus-<name>").
+	ExposureLabelKey = "operator.prometheus.io/exposure"
+
+	// ingressNginxBackendProtocolAnnotation tells ingress-nginx to connect
+	// to the pods over HTTPS.
+	ingressNginxBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+)
+
+var (
+	// GatewayAPIGroupVersion is the group version of the Gateway API
+	// resources managed by the operator.
+	GatewayAPIGroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1"}
+
+	httpRouteGVR         = GatewayAPIGroupVersion.WithResource("httproutes")
+	backendTLSPolicyGVR  = GatewayAPIGroupVersion.WithResource("backendtlspolicies")
+	exposureResourceGVRs = []schema.GroupVersionResource{httpRouteGVR, backendTLSPolicyGVR}
+)
+
+// ExposureConfig describes the web server of a workload.
+type ExposureConfig struct {
+	// Name is the base name of the generated objects.
+	Name string
+	// PodSelector selects all the pods of the workload.
+	PodSelector map[string]string
+	// PortName and Port identify the container port of the web server.
+	PortName string
+	Port     int32
+	// RoutePrefix is the HTTP path under which the web server is served.
+	RoutePrefix string
+	// WebTLS is true when the web server serves HTTPS.
+	WebTLS bool
+	// Sharded is true when the workload supports sharding.
+	Sharded bool
+	// Overlays are applied to the generated Services.
+	Overlays []monitoringv1.Overlay
+}
+
+// Exposure generates the Services and the Ingresses or Gateway API
+// HTTPRoutes exposing the web server of a workload.
+type Exposure struct {
+	spec     monitoringv1.ExposureSpec
+	config   ExposureConfig
+	backends []exposureBackend
+}
+
+// exposureBackend is a set of pods reachable under the same host name.
+type exposureBackend struct {
+	name     string
+	host     string
+	selector map[string]string
+}
+
+// NewExposure returns an Exposure for the given spec.
+func NewExposure(spec monitoringv1.ExposureSpec, config ExposureConfig) *Exposure {
+	e := &Exposure{
+		spec:   spec,
+		config: config,
+	}
+
+	if exposureHostMode(spec, config.Sharded) == monitoringv1.SingleExposureHostMode {
+		e.backends = append(e.backends, exposureBackend{
+			name:     exposureObjectName(config.Name),
+			host:     spec.Host,
+			selector: maps.Clone(config.PodSelector),
+		})
+	}
+
+	return e
+}
+
+// AddStatefulSet exposes the pods of the statefulset when the host mode is
+// PerShard or PerReplica. It is a no-op for the Single host mode.
+func (e *Exposure) AddStatefulSet(sset *appsv1.StatefulSet) {
+	switch exposureHostMode(e.spec, e.config.Sharded) {
+	case monitoringv1.PerShardExposureHostMode:
+		e.backends = append(e.backends, exposureBackend{
+			name:     exposureObjectName(sset.Name),
+			host:     sset.Name + "." + e.spec.Host,
+			selector: maps.Clone(sset.Spec.Selector.MatchLabels),
+		})
+
+	case monitoringv1.PerReplicaExposureHostMode:
+		for i := range ptr.Deref(sset.Spec.Replicas, 1) {
+			pod := fmt.Sprintf("%s-%d", sset.Name, i)
+
+			selector := maps.Clone(sset.Spec.Selector.MatchLabels)
+			selector[appsv1.StatefulSetPodNameLabel] = pod
+
+			e.backends = append(e.backends, exposureBackend{
+				name:     exposureObjectName(pod),
+				host:     pod + "." + e.spec.Host,
+				selector: selector,
+			})
+		}
+	}
+}
+
+// Services returns the Services selecting the exposed pods.
+func (e *Exposure) Services(namespace string, opts ...ObjectOption) []*corev1.Service {
+	svcs := make([]*corev1.Service, 0, len(e.backends))
+	for _, b := range e.backends {
+		port := corev1.ServicePort{
+			Name:       e.config.PortName,
+			Port:       e.config.Port,
+			TargetPort: intstr.FromString(e.config.PortName),
+		}
+		if e.config.WebTLS {
+			port.AppProtocol = ptr.To("https")
+		}
+
+		svc := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeClusterIP,
+				Ports:    []corev1.ServicePort{port},
+				Selector: b.selector,
+			},
+		}
+		e.updateObject(svc, b, namespace, opts...)
+
+		svcs = append(svcs, svc)
+	}
+
+	return svcs
+}
+
+// Ingresses returns the Ingresses routing the traffic to the Services. It
+// returns nil if the spec doesn't define an Ingress.
+func (e *Exposure) Ingresses(namespace string, opts ...ObjectOption) []*networkingv1.Ingress {
+	if e.spec.Ingress == nil {
+		return nil
+	}
+
+	ings := make([]*networkingv1.Ingress, 0, len(e.backends))
+	for _, b := range e.backends {
+		ing := &networkingv1.Ingress{
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: e.spec.Ingress.IngressClassName,
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: b.host,
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     e.path(),
+										PathType: ptr.To(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: b.name,
+												Port: networkingv1.ServiceBackendPort{Name: e.config.PortName},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		if e.spec.Ingress.TLSSecretName != nil {
+			ing.Spec.TLS = []networkingv1.IngressTLS{
+				{
+					Hosts:      []string{b.host},
+					SecretName: *e.spec.Ingress.TLSSecretName,
+				},
+			}
+		}
+
+		annotations := maps.Clone(e.spec.Ingress.Annotations)
+		if e.config.WebTLS {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			if _, found := annotations[ingressNginxBackendProtocolAnnotation]; !found {
+				annotations[ingressNginxBackendProtocolAnnotation] = "HTTPS"
+			}
+		}
+		ing.SetAnnotations(annotations)
+
+		e.updateObject(ing, b, namespace, opts...)
+
+		ings = append(ings, ing)
+	}
+
+	return ings
+}
+
+// HTTPRoutes returns the Gateway API HTTPRoutes routing the traffic to the
+// Services. It returns nil if the spec doesn't define an HTTPRoute.
+func (e *Exposure) HTTPRoutes(namespace string, opts ...ObjectOption) []*unstructured.Unstructured {
+	if e.spec.HTTPRoute == nil {
+		return nil
+	}
+
+	parentRefs := make([]any, 0, len(e.spec.HTTPRoute.ParentRefs))
+	for _, ref := range e.spec.HTTPRoute.ParentRefs {
+		parentRef := map[string]any{
+			"name":      ref.Name,
+			"namespace": ptr.Deref(ref.Namespace, namespace),
+		}
+		if ref.SectionName != nil {
+			parentRef["sectionName"] = *ref.SectionName
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+
+	routes := make([]*unstructured.Unstructured, 0, len(e.backends))
+	for _, b := range e.backends {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(GatewayAPIGroupVersion.WithKind("HTTPRoute"))
+		route.Object["spec"] = map[string]any{
+			"parentRefs": parentRefs,
+			"hostnames":  []any{b.host},
+			"rules": []any{
+				map[string]any{
+					"matches": []any{
+						map[string]any{
+							"path": map[string]any{
+								"type":  "PathPrefix",
+								"value": e.path(),
+							},
+						},
+					},
+					"backendRefs": []any{
+						map[string]any{
+							"name": b.name,
+							"port": int64(e.config.Port),
+						},
+					},
+				},
+			},
+		}
+		e.updateObject(route, b, namespace, opts...)
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+// BackendTLSPolicies returns the Gateway API BackendTLSPolicies telling the
+// Gateway to connect to the Services over HTTPS. It returns nil if the spec
+// doesn't define an HTTPRoute or if the web server doesn't serve HTTPS.
+func (e *Exposure) BackendTLSPolicies(namespace string, opts ...ObjectOption) []*unstructured.Unstructured {
+	if e.spec.HTTPRoute == nil || !e.config.WebTLS {
+		return nil
+	}
+
+	var (
+		backendTLS = ptr.Deref(e.spec.HTTPRoute.BackendTLS, monitoringv1.BackendTLSValidation{})
+		policies   = make([]*unstructured.Unstructured, 0, len(e.backends))
+	)
+	for _, b := range e.backends {
+		validation := map[string]any{
+			"hostname": ptr.Deref(backendTLS.Hostname, fmt.Sprintf("%s.%s.svc", b.name, namespace)),
+		}
+
+		if len(backendTLS.CACertificateRefs) == 0 {
+			validation["wellKnownCACertificates"] = "System"
+		} else {
+			refs := make([]any, 0, len(backendTLS.CACertificateRefs))
+			for _, ref := range backendTLS.CACertificateRefs {
+				refs = append(refs, map[string]any{
+					"group": "",
+					"kind":  "ConfigMap",
+					"name":  ref.Name,
+				})
+			}
+			validation["caCertificateRefs"] = refs
+		}
+
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(GatewayAPIGroupVersion.WithKind("BackendTLSPolicy"))
+		policy.Object["spec"] = map[string]any{
+			"targetRefs": []any{
+				map[string]any{
+					"group":       "",
+					"kind":        "Service",
+					"name":        b.name,
+					"sectionName": e.config.PortName,
+				},
+			},
+			"validation": validation,
+		}
+		e.updateObject(policy, b, namespace, opts...)
+
+		policies = append(policies, policy)
+	}
+
+	return policies
+}
+
+func (e *Exposure) updateObject(o metav1.Object, b exposureBackend, namespace string, opts ...ObjectOption) {
+	UpdateObject(
+		o,
+		append(
+			[]ObjectOption{
+				WithName(b.name),
+				WithNamespace(namespace),
+				WithLabels(map[string]string{ExposureLabelKey: e.config.Name}),
+			},
+			opts...,
+		)...,
+	)
+}
+
+func (e *Exposure) path() string {
+	return path.Clean("/" + e.config.RoutePrefix)
+}
+
+// ExposureExternalURL returns the external URL of the pods of the given
+// statefulset. For the PerReplica host mode, the URL contains the
+// $(POD_NAME) variable which must be defined in the container's
+// environment.
+func ExposureExternalURL(spec monitoringv1.ExposureSpec, ssetName string, sharded bool, routePrefix string) string {
+	scheme := "http"
+	switch {
+	case spec.Ingress != nil && spec.Ingress.TLSSecretName != nil:
+		scheme = "https"
+	case spec.HTTPRoute != nil:
+		scheme = strings.ToLower(string(ptr.Deref(spec.HTTPRoute.Scheme, monitoringv1.SchemeHTTPS)))
+	}
+
+	host := spec.Host
+	switch exposureHostMode(spec, sharded) {
+	case monitoringv1.PerShardExposureHostMode:
+		host = ssetName + "." + host
+	case monitoringv1.PerReplicaExposureHostMode:
+		host = fmt.Sprintf("$(%s).%s", PodNameEnvVar, host)
+	}
+
+	return scheme + "://" + host + path.Clean("/"+routePrefix)
+}
+
+// ExposurePerReplica returns true when each pod is exposed under its own host
+// name.
+func ExposurePerReplica(spec *monitoringv1.ExposureSpec) bool {
+	return spec != nil && exposureHostMode(*spec, false) == monitoringv1.PerReplicaExposureHostMode
+}
+
+// ReconcileExposure creates or updates the objects generated by the
+// exposure if it isn't nil and deletes the objects labeled with the base
+// name and controlled by the owner which aren't generated anymore.
+//
+// The Gateway API resources are managed only if their CRDs are installed.
+func ReconcileExposure(
+	ctx context.Context,
+	logger *slog.Logger,
+	kclient kubernetes.Interface,
+	dclient dynamic.Interface,
+	owner metav1.Object,
+	name string,
+	e *Exposure,
+	opts ...ObjectOption,
+) error {
+	var (
+		namespace = owner.GetNamespace()
+		expected  = map[string]struct{}{}
+	)
+
+	if e != nil {
+		for _, svc := range e.Services(namespace, opts...) {
+			if err := k8s.ApplyOverlays(e.config.Overlays, svc); err != nil {
+				return err
+			}
+
+			if _, err := k8s.CreateOrUpdateService(ctx, kclient.CoreV1().Services(namespace), svc); err != nil {
+				return fmt.Errorf("failed to reconcile Service %s: %w", svc.Name, err)
+			}
+			expected[svc.Name] = struct{}{}
+		}
+
+		for _, ing := range e.Ingresses(namespace, opts...) {
+			if _, err := k8s.CreateOrUpdateIngress(ctx, kclient.NetworkingV1().Ingresses(namespace), ing); err != nil {
+				return fmt.Errorf("failed to reconcile Ingress %s: %w", ing.Name, err)
+			}
+		}
+
+		if e.spec.HTTPRoute != nil {
+			if err := e.reconcileGatewayAPIResources(ctx, logger, kclient, dclient, namespace, opts...); err != nil {
+				return err
+			}
+		}
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{ExposureLabelKey: name}).String()
+
+	// Don't fail when the operator isn't allowed to manage the resources
+	// and no exposure is requested.
+	ignoreListError := func(err error) bool {
+		return apierrors.IsNotFound(err) || (e == nil && apierrors.IsForbidden(err))
+	}
+
+	var errs []error
+	svcs, err := kclient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		if !ignoreListError(err) {
+			errs = append(errs, fmt.Errorf("failed to list Services: %w", err))
+		}
+	} else {
+		for _, svc := range svcs.Items {
+			if _, found := expected[svc.Name]; found || !metav1.IsControlledBy(&svc, owner) {
+				continue
+			}
+
+			if err := kclient.CoreV1().Services(namespace).Delete(ctx, svc.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete Service %s: %w", svc.Name, err))
+			}
+		}
+	}
+
+	ings, err := kclient.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		if !ignoreListError(err) {
+			errs = append(errs, fmt.Errorf("failed to list Ingresses: %w", err))
+		}
+	} else {
+		for _, ing := range ings.Items {
+			if e != nil && e.spec.Ingress != nil {
+				if _, found := expected[ing.Name]; found {
+					continue
+				}
+			}
+
+			if !metav1.IsControlledBy(&ing, owner) {
+				continue
+			}
+
+			if err := kclient.NetworkingV1().Ingresses(namespace).Delete(ctx, ing.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete Ingress %s: %w", ing.Name, err))
+			}
+		}
+	}
+
+	for _, gvr := range exposureResourceGVRs {
+		var keep map[string]struct{}
+		if e != nil && e.spec.HTTPRoute != nil && (gvr == httpRouteGVR || e.config.WebTLS) {
+			keep = expected
+		}
+
+		client := dclient.Resource(gvr).Namespace(namespace)
+		list, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			// The Gateway API CRDs may not be installed.
+			if !ignoreListError(err) && !apierrors.IsForbidden(err) {
+				errs = append(errs, fmt.Errorf("failed to list %s: %w", gvr.Resource, err))
+			}
+			continue
+		}
+
+		for _, obj := range list.Items {
+			if _, found := keep[obj.GetName()]; found || !metav1.IsControlledBy(&obj, owner) {
+				continue
+			}
+
+			if err := client.Delete(ctx, obj.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete %s %s: %w", gvr.Resource, obj.GetName(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *Exposure) reconcileGatewayAPIResources(ctx context.Context, logger *slog.Logger, kclient kubernetes.Interface, dclient dynamic.Interface, namespace string, opts ...ObjectOption) error {
+	installed, err := k8s.IsAPIGroupVersionResourceSupported(kclient.Discovery(), GatewayAPIGroupVersion, httpRouteGVR.Resource)
+	if err != nil {
+		return fmt.Errorf("failed to check presence of resource %q (group %q): %w", httpRouteGVR.Resource, GatewayAPIGroupVersion, err)
+	}
+
+	if !installed {
+		return fmt.Errorf("resource %q (group %q) not installed in the cluster", httpRouteGVR.Resource, GatewayAPIGroupVersion)
+	}
+
+	for _, route := range e.HTTPRoutes(namespace, opts...) {
+		if _, err := k8s.CreateOrUpdateUnstructured(ctx, dclient.Resource(httpRouteGVR).Namespace(namespace), route); err != nil {
+			return fmt.Errorf("failed to reconcile HTTPRoute %s: %w", route.GetName(), err)
+		}
+	}
+
+	policies := e.BackendTLSPolicies(namespace, opts...)
+	if len(policies) == 0 {
+		return nil
+	}
+
+	installed, err = k8s.IsAPIGroupVersionResourceSupported(kclient.Discovery(), GatewayAPIGroupVersion, backendTLSPolicyGVR.Resource)
+	if err != nil {
+		return fmt.Errorf("failed to check presence of resource %q (group %q): %w", backendTLSPolicyGVR.Resource, GatewayAPIGroupVersion, err)
+	}
+
+	if !installed {
+		logger.Warn(
+			"the web server serves HTTPS but the BackendTLSPolicy resource isn't installed, the Gateway may fail to reach the pods",
+			"resource", backendTLSPolicyGVR.Resource,
+			"group", GatewayAPIGroupVersion.String(),
+		)
+		return nil
+	}
+
+	for _, policy := range policies {
+		if _, err := k8s.CreateOrUpdateUnstructured(ctx, dclient.Resource(backendTLSPolicyGVR).Namespace(namespace), policy); err != nil {
+			return fmt.Errorf("failed to reconcile BackendTLSPolicy %s: %w", policy.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func exposureHostMode(spec monitoringv1.ExposureSpec, sharded bool) monitoringv1.ExposureHostMode {
+	mode := ptr.Deref(spec.HostMode, monitoringv1.SingleExposureHostMode)
+	if mode == monitoringv1.PerShardExposureHostMode && !sharded {
+		return monitoringv1.SingleExposureHostMode
+	}
+
+	return mode
+}
+
+// exposureObjectName returns the name of the objects exposing the pods
+// (e.g. "prometheus-main-web").
+func exposureObjectName(name string) string {
+	return name + "-web"
+}
@@ -0,0 +1,369 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func newExposureStatefulSet(name string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"prometheus":  "test",
+					"statefulset": name,
+				},
+			},
+		},
+	}
+}
+
+func newTestExposure(spec monitoringv1.ExposureSpec, webTLS bool) *Exposure {
+	e := NewExposure(spec, ExposureConfig{
+		Name:        "prometheus-test",
+		PodSelector: map[string]string{"prometheus": "test"},
+		PortName:    "web",
+		Port:        9090,
+		RoutePrefix: "/prometheus/",
+		WebTLS:      webTLS,
+		Sharded:     true,
+	})
+	e.AddStatefulSet(newExposureStatefulSet("prometheus-test", 2))
+	e.AddStatefulSet(newExposureStatefulSet("prometheus-test-shard-1", 2))
+
+	return e
+}
+
+func TestExposureHosts(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		hostMode *monitoringv1.ExposureHostMode
+		sharded  bool
+
+		expectedServices []string
+		expectedHosts    []string
+	}{
+		{
+			name:             "default",
+			sharded:          true,
+			expectedServices: []string{"prometheus-test-web"},
+			expectedHosts:    []string{"example.com"},
+		},
+		{
+			name:             "per-shard",
+			hostMode:         ptr.To(monitoringv1.PerShardExposureHostMode),
+			sharded:          true,
+			expectedServices: []string{"prometheus-test-web", "prometheus-test-shard-1-web"},
+			expectedHosts:    []string{"prometheus-test.example.com", "prometheus-test-shard-1.example.com"},
+		},
+		{
+			name:             "per-shard without sharding",
+			hostMode:         ptr.To(monitoringv1.PerShardExposureHostMode),
+			expectedServices: []string{"prometheus-test-web"},
+			expectedHosts:    []string{"example.com"},
+		},
+		{
+			name:     "per-replica",
+			hostMode: ptr.To(monitoringv1.PerReplicaExposureHostMode),
+			sharded:  true,
+			expectedServices: []string{
+				"prometheus-test-0-web",
+				"prometheus-test-1-web",
+				"prometheus-test-shard-1-0-web",
+				"prometheus-test-shard-1-1-web",
+			},
+			expectedHosts: []string{
+				"prometheus-test-0.example.com",
+				"prometheus-test-1.example.com",
+				"prometheus-test-shard-1-0.example.com",
+				"prometheus-test-shard-1-1.example.com",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewExposure(
+				monitoringv1.ExposureSpec{
+					Host:     "example.com",
+					HostMode: tc.hostMode,
+					Ingress:  &monitoringv1.IngressExposure{},
+				},
+				ExposureConfig{
+					Name:        "prometheus-test",
+					PodSelector: map[string]string{"prometheus": "test"},
+					PortName:    "web",
+					Port:        9090,
+					Sharded:     tc.sharded,
+				},
+			)
+			e.AddStatefulSet(newExposureStatefulSet("prometheus-test", 2))
+			if tc.sharded {
+				e.AddStatefulSet(newExposureStatefulSet("prometheus-test-shard-1", 2))
+			}
+
+			var services []string
+			for _, svc := range e.Services("default") {
+				services = append(services, svc.Name)
+				require.Equal(t, "prometheus-test", svc.Labels[ExposureLabelKey])
+				require.Equal(t, "test", svc.Spec.Selector["prometheus"])
+			}
+			require.Equal(t, tc.expectedServices, services)
+
+			var hosts []string
+			for _, ing := range e.Ingresses("default") {
+				hosts = append(hosts, ing.Spec.Rules[0].Host)
+			}
+			require.Equal(t, tc.expectedHosts, hosts)
+		})
+	}
+}
+
+func TestExposureIngress(t *testing.T) {
+	e := newTestExposure(monitoringv1.ExposureSpec{
+		Host: "example.com",
+		Ingress: &monitoringv1.IngressExposure{
+			IngressClassName: ptr.To("nginx"),
+			Annotations:      map[string]string{"foo": "bar"},
+			TLSSecretName:    ptr.To("example-tls"),
+		},
+	}, true)
+
+	svcs := e.Services("default")
+	require.Len(t, svcs, 1)
+	require.Equal(t, ptr.To("https"), svcs[0].Spec.Ports[0].AppProtocol)
+
+	ings := e.Ingresses("default")
+	require.Len(t, ings, 1)
+
+	ing := ings[0]
+	require.Equal(t, "prometheus-test-web", ing.Name)
+	require.Equal(t, "default", ing.Namespace)
+	require.Equal(t, ptr.To("nginx"), ing.Spec.IngressClassName)
+	require.Equal(t, "bar", ing.Annotations["foo"])
+	require.Equal(t, "HTTPS", ing.Annotations[ingressNginxBackendProtocolAnnotation])
+	require.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: "example-tls"}}, ing.Spec.TLS)
+
+	path := ing.Spec.Rules[0].HTTP.Paths[0]
+	require.Equal(t, "/prometheus", path.Path)
+	require.Equal(t, "prometheus-test-web", path.Backend.Service.Name)
+	require.Equal(t, "web", path.Backend.Service.Port.Name)
+
+	require.Empty(t, e.HTTPRoutes("default"))
+	require.Empty(t, e.BackendTLSPolicies("default"))
+}
+
+func TestExposureHTTPRoute(t *testing.T) {
+	spec := monitoringv1.ExposureSpec{
+		Host: "example.com",
+		HTTPRoute: &monitoringv1.HTTPRouteExposure{
+			ParentRefs: []monitoringv1.GatewayParentReference{
+				{Name: "gateway", Namespace: ptr.To("gateway-system"), SectionName: ptr.To("https")},
+			},
+			BackendTLS: &monitoringv1.BackendTLSValidation{
+				CACertificateRefs: []corev1.LocalObjectReference{{Name: "ca"}},
+			},
+		},
+	}
+
+	e := newTestExposure(spec, false)
+	require.Empty(t, e.Ingresses("default"))
+	require.Empty(t, e.BackendTLSPolicies("default"))
+
+	routes := e.HTTPRoutes("default")
+	require.Len(t, routes, 1)
+	require.Equal(t, "prometheus-test-web", routes[0].GetName())
+
+	hostnames, _, _ := unstructured.NestedStringSlice(routes[0].Object, "spec", "hostnames")
+	require.Equal(t, []string{"example.com"}, hostnames)
+
+	parentRefs, _, _ := unstructured.NestedSlice(routes[0].Object, "spec", "parentRefs")
+	require.Equal(t, []any{map[string]any{"name": "gateway", "namespace": "gateway-system", "sectionName": "https"}}, parentRefs)
+
+	e = newTestExposure(spec, true)
+	policies := e.BackendTLSPolicies("default")
+	require.Len(t, policies, 1)
+
+	hostname, _, _ := unstructured.NestedString(policies[0].Object, "spec", "validation", "hostname")
+	require.Equal(t, "prometheus-test-web.default.svc", hostname)
+
+	caRefs, _, _ := unstructured.NestedSlice(policies[0].Object, "spec", "validation", "caCertificateRefs")
+	require.Equal(t, []any{map[string]any{"group": "", "kind": "ConfigMap", "name": "ca"}}, caRefs)
+}
+
+func TestExposureExternalURL(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		spec     monitoringv1.ExposureSpec
+		sharded  bool
+		expected string
+	}{
+		{
+			name: "ingress without TLS",
+			spec: monitoringv1.ExposureSpec{
+				Host:    "example.com",
+				Ingress: &monitoringv1.IngressExposure{},
+			},
+			expected: "http://example.com/prefix",
+		},
+		{
+			name: "ingress with TLS",
+			spec: monitoringv1.ExposureSpec{
+				Host:    "example.com",
+				Ingress: &monitoringv1.IngressExposure{TLSSecretName: ptr.To("tls")},
+			},
+			expected: "https://example.com/prefix",
+		},
+		{
+			name: "httproute",
+			spec: monitoringv1.ExposureSpec{
+				Host:      "example.com",
+				HTTPRoute: &monitoringv1.HTTPRouteExposure{},
+			},
+			expected: "https://example.com/prefix",
+		},
+		{
+			name: "per-shard",
+			spec: monitoringv1.ExposureSpec{
+				Host:      "example.com",
+				HostMode:  ptr.To(monitoringv1.PerShardExposureHostMode),
+				HTTPRoute: &monitoringv1.HTTPRouteExposure{Scheme: ptr.To(monitoringv1.SchemeHTTP)},
+			},
+			sharded:  true,
+			expected: "http://prometheus-test-shard-1.example.com/prefix",
+		},
+		{
+			name: "per-replica",
+			spec: monitoringv1.ExposureSpec{
+				Host:     "example.com",
+				HostMode: ptr.To(monitoringv1.PerReplicaExposureHostMode),
+				Ingress:  &monitoringv1.IngressExposure{},
+			},
+			expected: "http://$(POD_NAME).example.com/prefix",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ExposureExternalURL(tc.spec, "prometheus-test-shard-1", tc.sharded, "prefix/"))
+		})
+	}
+}
+
+func TestReconcileExposure(t *testing.T) {
+	owner := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			UID:       "1",
+		},
+	}
+	ownerRefs := []metav1.OwnerReference{
+		{
+			APIVersion: "monitoring.coreos.com/v1",
+			Kind:       "Prometheus",
+			Name:       "test",
+			UID:        "1",
+			Controller: ptr.To(true),
+		},
+	}
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			Labels:          map[string]string{ExposureLabelKey: "prometheus-test"},
+			OwnerReferences: ownerRefs,
+		}
+	}
+
+	kclient := fake.NewClientset(
+		// Stale Service of a removed replica.
+		&corev1.Service{ObjectMeta: meta("prometheus-test-1-web")},
+		// Stale Ingress.
+		&networkingv1.Ingress{ObjectMeta: meta("prometheus-test-web")},
+		// Service not controlled by the owner.
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: "default",
+				Labels:    map[string]string{ExposureLabelKey: "prometheus-test"},
+			},
+		},
+	)
+	dclient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			httpRouteGVR:        "HTTPRouteList",
+			backendTLSPolicyGVR: "BackendTLSPolicyList",
+		},
+	)
+
+	e := NewExposure(
+		monitoringv1.ExposureSpec{
+			Host:     "example.com",
+			HostMode: ptr.To(monitoringv1.PerReplicaExposureHostMode),
+		},
+		ExposureConfig{
+			Name:     "prometheus-test",
+			PortName: "web",
+			Port:     9090,
+		},
+	)
+	e.AddStatefulSet(newExposureStatefulSet("prometheus-test", 1))
+
+	ctx := context.Background()
+	err := ReconcileExposure(ctx, slog.New(slog.DiscardHandler), kclient, dclient, owner, "prometheus-test", e, WithManagingOwner(owner))
+	require.NoError(t, err)
+
+	svcs, err := kclient.CoreV1().Services("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, svc := range svcs.Items {
+		names = append(names, svc.Name)
+	}
+	require.ElementsMatch(t, []string{"other", "prometheus-test-0-web"}, names)
+
+	ings, err := kclient.NetworkingV1().Ingresses("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, ings.Items)
+
+	// Removing the exposure deletes the generated Service.
+	err = ReconcileExposure(ctx, slog.New(slog.DiscardHandler), kclient, dclient, owner, "prometheus-test", nil)
+	require.NoError(t, err)
+
+	svcs, err = kclient.CoreV1().Services("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, svcs.Items, 1)
+	require.Equal(t, "other", svcs.Items[0].Name)
+}
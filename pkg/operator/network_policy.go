@@ -0,0 +1,324 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+)
+
+// NetworkPolicyBuilder collects the traffic which the pods of a workload
+// need to accept and to initiate, and generates the corresponding
+// NetworkPolicy.
+type NetworkPolicyBuilder struct {
+	namespace   string
+	podSelector map[string]string
+
+	// Ports exposed by the pods.
+	ports []networkingv1.NetworkPolicyPort
+	// Ports used for the communication between the pods of the workload.
+	peerPorts []networkingv1.NetworkPolicyPort
+
+	allNamespaces bool
+	namespaces    map[string]struct{}
+	kubernetesAPI bool
+}
+
+// NewNetworkPolicyBuilder returns a NetworkPolicyBuilder for the pods
+// matching the selector in the given namespace.
+func NewNetworkPolicyBuilder(namespace string, podSelector map[string]string) *NetworkPolicyBuilder {
+	return &NetworkPolicyBuilder{
+		namespace:   namespace,
+		podSelector: podSelector,
+		namespaces:  map[string]struct{}{},
+	}
+}
+
+// AddPodSpec allows the ingress traffic to the ports exposed by the
+// containers of the pod spec.
+func (b *NetworkPolicyBuilder) AddPodSpec(spec *corev1.PodSpec) {
+	for _, c := range spec.Containers {
+		for _, p := range c.Ports {
+			b.ports = appendNetworkPolicyPort(b.ports, p.Protocol, p.ContainerPort)
+		}
+	}
+}
+
+// AddPeerPort allows the traffic between the pods of the workload on the
+// given port (e.g. the Alertmanager cluster port).
+func (b *NetworkPolicyBuilder) AddPeerPort(protocol corev1.Protocol, port int32) {
+	b.peerPorts = appendNetworkPolicyPort(b.peerPorts, protocol, port)
+}
+
+// AddNamespaces allows the egress traffic to the pods running in the given
+// namespaces.
+func (b *NetworkPolicyBuilder) AddNamespaces(namespaces ...string) {
+	for _, ns := range namespaces {
+		b.namespaces[ns] = struct{}{}
+	}
+}
+
+// AddAllNamespaces allows the egress traffic to the pods running in any
+// namespace.
+func (b *NetworkPolicyBuilder) AddAllNamespaces() {
+	b.allNamespaces = true
+}
+
+// AddAddresses allows the egress traffic to the namespaces of the given
+// addresses. An address can be a URL, a host:port pair or a host name.
+//
+// Only the in-cluster service names (e.g. "<service>.<namespace>.svc" or
+// "<service>" for the namespace of the workload) are taken into account.
+// IP addresses and external host names are ignored.
+func (b *NetworkPolicyBuilder) AddAddresses(addresses ...string) {
+	for _, addr := range addresses {
+		if ns, found := namespaceFromAddress(addr, b.namespace); found {
+			b.namespaces[ns] = struct{}{}
+		}
+	}
+}
+
+// AddKubernetesAPI allows the egress traffic to the Kubernetes API server
+// (e.g. for service discovery).
+func (b *NetworkPolicyBuilder) AddKubernetesAPI() {
+	b.kubernetesAPI = true
+}
+
+// Build returns the NetworkPolicy generated from the collected traffic and
+// the user-defined spec.
+func (b *NetworkPolicyBuilder) Build(ctx context.Context, logger *slog.Logger, kclient kubernetes.Interface, spec monitoringv1.NetworkPolicySpec, opts ...ObjectOption) *networkingv1.NetworkPolicy {
+	np := &networkingv1.NetworkPolicy{
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: maps.Clone(b.podSelector),
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{},
+			Egress:  []networkingv1.NetworkPolicyEgressRule{},
+		},
+	}
+	UpdateObject(np, opts...)
+
+	if len(b.ports) > 0 {
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  spec.IngressFrom,
+			Ports: b.ports,
+		})
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{
+		{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: maps.Clone(b.podSelector),
+			},
+		},
+	}
+	if len(b.peerPorts) > 0 {
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  peers,
+			Ports: b.peerPorts,
+		})
+		np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To:    peers,
+			Ports: b.peerPorts,
+		})
+	}
+
+	// DNS resolution.
+	np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+		Ports: appendNetworkPolicyPort(
+			appendNetworkPolicyPort(nil, corev1.ProtocolUDP, 53),
+			corev1.ProtocolTCP,
+			53,
+		),
+	})
+
+	switch {
+	case b.allNamespaces:
+		np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{}},
+			},
+		})
+	case len(b.namespaces) > 0:
+		np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      corev1.LabelMetadataName,
+								Operator: metav1.LabelSelectorOpIn,
+								Values:   slices.Sorted(maps.Keys(b.namespaces)),
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	if b.kubernetesAPI {
+		rule, err := kubernetesAPIEgressRule(ctx, kclient)
+		if err != nil {
+			logger.Warn("failed to allow the egress traffic to the Kubernetes API server", "err", err)
+		} else if rule != nil {
+			np.Spec.Egress = append(np.Spec.Egress, *rule)
+		}
+	}
+
+	np.Spec.Egress = append(np.Spec.Egress, spec.AdditionalEgress...)
+
+	return np
+}
+
+// ReconcileNetworkPolicy creates or updates the NetworkPolicy if it isn't
+// nil. Otherwise it deletes the NetworkPolicy with the given name.
+func ReconcileNetworkPolicy(ctx context.Context, kclient kubernetes.Interface, namespace, name string, np *networkingv1.NetworkPolicy) error {
+	npClient := kclient.NetworkingV1().NetworkPolicies(namespace)
+
+	if np == nil {
+		err := npClient.Delete(ctx, name, metav1.DeleteOptions{})
+		// Don't fail when the operator isn't allowed to manage
+		// NetworkPolicies and none is requested.
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return fmt.Errorf("failed to delete NetworkPolicy %s: %w", name, err)
+		}
+
+		return nil
+	}
+
+	if _, err := k8s.CreateOrUpdateNetworkPolicy(ctx, npClient, np); err != nil {
+		return fmt.Errorf("failed to reconcile NetworkPolicy %s: %w", np.Name, err)
+	}
+
+	return nil
+}
+
+// kubernetesAPIEgressRule returns the egress rule allowing the traffic to
+// the endpoints of the Kubernetes API server. The rule targets IP blocks
+// because the API server may not run as a pod.
+func kubernetesAPIEgressRule(ctx context.Context, kclient kubernetes.Interface) (*networkingv1.NetworkPolicyEgressRule, error) {
+	//nolint:staticcheck // Ignore SA1019 Endpoints is deprecated but the operator is already allowed to read them.
+	eps, err := kclient.CoreV1().Endpoints(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var rule networkingv1.NetworkPolicyEgressRule
+	for _, subset := range eps.Subsets {
+		for _, addr := range subset.Addresses {
+			ip := net.ParseIP(addr.IP)
+			if ip == nil {
+				continue
+			}
+
+			cidr := addr.IP + "/32"
+			if ip.To4() == nil {
+				cidr = addr.IP + "/128"
+			}
+
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+
+		for _, port := range subset.Ports {
+			rule.Ports = appendNetworkPolicyPort(rule.Ports, port.Protocol, port.Port)
+		}
+	}
+
+	if len(rule.To) == 0 {
+		return nil, nil
+	}
+
+	return &rule, nil
+}
+
+func appendNetworkPolicyPort(ports []networkingv1.NetworkPolicyPort, protocol corev1.Protocol, port int32) []networkingv1.NetworkPolicyPort {
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+
+	for _, p := range ports {
+		if *p.Protocol == protocol && p.Port.IntVal == port {
+			return ports
+		}
+	}
+
+	p := intstr.FromInt32(port)
+	return append(ports, networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	})
+}
+
+// namespaceFromAddress returns the namespace of the in-cluster service
+// targeted by the address.
+func namespaceFromAddress(addr, namespace string) (string, bool) {
+	// Strip the DNS service discovery prefixes supported by Thanos (e.g.
+	// "dnssrv+_grpc._tcp.thanos-query.monitoring.svc").
+	if strings.HasPrefix(addr, "dns") {
+		if _, after, found := strings.Cut(addr, "+"); found {
+			addr = after
+		}
+	}
+
+	host := addr
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || host == "localhost" || net.ParseIP(host) != nil {
+		return "", false
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) == 1 {
+		return namespace, true
+	}
+
+	// <service>.<namespace>.svc[.<cluster domain>] with optional pod
+	// hostname and SRV prefixes.
+	for i := 2; i < len(labels); i++ {
+		if labels[i] == "svc" {
+			return labels[i-1], true
+		}
+	}
+
+	return "", false
+}
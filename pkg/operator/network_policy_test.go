@@ -0,0 +1,192 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestNamespaceFromAddress(t *testing.T) {
+	for _, tc := range []struct {
+		addr  string
+		expNs string
+	}{
+		{addr: "alertmanager-operated", expNs: "default"},
+		{addr: "alertmanager-operated:9093", expNs: "default"},
+		{addr: "http://remote-write:8080/api/v1/write", expNs: "default"},
+		{addr: "http://thanos-receive.monitoring.svc:19291/api/v1/receive", expNs: "monitoring"},
+		{addr: "https://thanos-receive.monitoring.svc.cluster.local./api/v1/receive", expNs: "monitoring"},
+		{addr: "alertmanager-main-0.alertmanager-operated.monitoring.svc:9094", expNs: "monitoring"},
+		{addr: "dnssrv+_grpc._tcp.thanos-query.observability.svc.cluster.local", expNs: "observability"},
+		{addr: "dns+thanos-query.observability.svc:10901", expNs: "observability"},
+		{addr: "http://10.0.0.1:9093"},
+		{addr: "[::1]:9093"},
+		{addr: "localhost:9090"},
+		{addr: "https://remote-write.example.com/api/v1/write"},
+		{addr: ""},
+	} {
+		t.Run(tc.addr, func(t *testing.T) {
+			ns, found := namespaceFromAddress(tc.addr, "default")
+			require.Equal(t, tc.expNs != "", found)
+			require.Equal(t, tc.expNs, ns)
+		})
+	}
+}
+
+func TestNetworkPolicyBuilder(t *testing.T) {
+	podSelector := map[string]string{"alertmanager": "test"}
+
+	//nolint:staticcheck // Ignore SA1019 Endpoints is deprecated.
+	kclient := fake.NewClientset(&corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubernetes",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "172.18.0.2"}},
+				Ports:     []corev1.EndpointPort{{Name: "https", Port: 6443, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+	})
+
+	b := NewNetworkPolicyBuilder("default", podSelector)
+	b.AddPodSpec(&corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name: "alertmanager",
+				Ports: []corev1.ContainerPort{
+					{Name: "web", ContainerPort: 9093},
+					{Name: "mesh-tcp", ContainerPort: 9094, Protocol: corev1.ProtocolTCP},
+					{Name: "mesh-udp", ContainerPort: 9094, Protocol: corev1.ProtocolUDP},
+				},
+			},
+			{
+				Name: "config-reloader",
+				Ports: []corev1.ContainerPort{
+					{Name: "reloader-web", ContainerPort: 8080},
+				},
+			},
+		},
+	})
+	b.AddPeerPort(corev1.ProtocolTCP, 9094)
+	b.AddPeerPort(corev1.ProtocolUDP, 9094)
+	b.AddAddresses("http://receiver.monitoring.svc:8080", "https://example.com")
+	b.AddNamespaces("default")
+	b.AddKubernetesAPI()
+
+	ingressFrom := []networkingv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{corev1.LabelMetadataName: "monitoring"},
+			},
+		},
+	}
+	additionalEgress := networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{
+			{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+		},
+	}
+
+	np := b.Build(
+		context.Background(),
+		slog.New(slog.DiscardHandler),
+		kclient,
+		monitoringv1.NetworkPolicySpec{
+			IngressFrom:      ingressFrom,
+			AdditionalEgress: []networkingv1.NetworkPolicyEgressRule{additionalEgress},
+		},
+		WithName("alertmanager-test"),
+		WithNamespace("default"),
+	)
+
+	require.Equal(t, "alertmanager-test", np.Name)
+	require.Equal(t, "default", np.Namespace)
+	require.Equal(t, podSelector, np.Spec.PodSelector.MatchLabels)
+	require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, np.Spec.PolicyTypes)
+
+	// Ingress.
+	require.Len(t, np.Spec.Ingress, 2)
+	require.Equal(t, ingressFrom, np.Spec.Ingress[0].From)
+	require.Len(t, np.Spec.Ingress[0].Ports, 4)
+	require.Equal(t, podSelector, np.Spec.Ingress[1].From[0].PodSelector.MatchLabels)
+	require.Len(t, np.Spec.Ingress[1].Ports, 2)
+
+	// Egress.
+	require.Len(t, np.Spec.Egress, 5)
+	require.Equal(t, podSelector, np.Spec.Egress[0].To[0].PodSelector.MatchLabels)
+	require.Len(t, np.Spec.Egress[0].Ports, 2)
+
+	require.Empty(t, np.Spec.Egress[1].To)
+	require.Equal(t, intstr.FromInt32(53), *np.Spec.Egress[1].Ports[0].Port)
+
+	require.Equal(t,
+		[]string{"default", "monitoring"},
+		np.Spec.Egress[2].To[0].NamespaceSelector.MatchExpressions[0].Values,
+	)
+
+	require.Equal(t, "172.18.0.2/32", np.Spec.Egress[3].To[0].IPBlock.CIDR)
+	require.Equal(t, intstr.FromInt32(6443), *np.Spec.Egress[3].Ports[0].Port)
+
+	require.Equal(t, additionalEgress, np.Spec.Egress[4])
+
+	// All namespaces.
+	b.AddAllNamespaces()
+	np = b.Build(context.Background(), slog.New(slog.DiscardHandler), kclient, monitoringv1.NetworkPolicySpec{})
+	require.Nil(t, np.Spec.Ingress[0].From)
+	require.Equal(t, &metav1.LabelSelector{}, np.Spec.Egress[2].To[0].NamespaceSelector)
+}
+
+func TestReconcileNetworkPolicy(t *testing.T) {
+	kclient := fake.NewClientset()
+
+	np := NewNetworkPolicyBuilder("default", map[string]string{"prometheus": "test"}).Build(
+		context.Background(),
+		slog.New(slog.DiscardHandler),
+		kclient,
+		monitoringv1.NetworkPolicySpec{},
+		WithName("prometheus-test"),
+		WithNamespace("default"),
+	)
+
+	err := ReconcileNetworkPolicy(context.Background(), kclient, "default", "prometheus-test", np)
+	require.NoError(t, err)
+
+	_, err = kclient.NetworkingV1().NetworkPolicies("default").Get(context.Background(), "prometheus-test", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// Unset the networkPolicy field.
+	err = ReconcileNetworkPolicy(context.Background(), kclient, "default", "prometheus-test", nil)
+	require.NoError(t, err)
+
+	_, err = kclient.NetworkingV1().NetworkPolicies("default").Get(context.Background(), "prometheus-test", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// Deleting a missing NetworkPolicy is a no-op.
+	err = ReconcileNetworkPolicy(context.Background(), kclient, "default", "prometheus-test", nil)
+	require.NoError(t, err)
+}
@@ -16,6 +16,7 @@ package operator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -35,12 +36,21 @@ import (
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/scheme"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
 )
 
 const (
 	// InvalidConfigurationEvent is the  type used for events reporting invalid
 	// configuration resources.
 	InvalidConfigurationEvent = "InvalidConfiguration"
+
+	// QuotaExceededEvent is the type used for events reporting configuration
+	// resources which exceed the MonitoringQuota of their namespace.
+	QuotaExceededEvent = "QuotaExceeded"
+
+	// DriftDetectedEvent is the type used for events reporting managed
+	// objects which have been modified by other field managers.
+	DriftDetectedEvent = "DriftDetected"
 )
 
 var (
@@ -69,6 +79,11 @@ func (rs ReconciliationStatus) Reason() string {
 		return rs.reason
 	}
 
+	var oerr *k8s.OverlayError
+	if errors.As(rs.err, &oerr) {
+		return OverlayFailedReason
+	}
+
 	return "ReconciliationFailed"
 }
 
@@ -96,6 +111,7 @@ type ReconciliationTracker struct {
 	mtx            sync.RWMutex
 	statusByObject map[string]ReconciliationStatus
 	refTracker     map[string]ReferenceTracker
+	storageResized map[string]monitoringv1.Condition
 }
 
 // ReferenceTracker returns true if it has a reference to the object.
@@ -107,6 +123,7 @@ func (rt *ReconciliationTracker) init() {
 	rt.once.Do(func() {
 		rt.statusByObject = map[string]ReconciliationStatus{}
 		rt.refTracker = map[string]ReferenceTracker{}
+		rt.storageResized = map[string]monitoringv1.Condition{}
 	})
 }
 
@@ -208,6 +225,36 @@ func (rt *ReconciliationTracker) GetCondition(k string, gen int64) monitoringv1.
 	return condition
 }
 
+// SetStorageResizedCondition records the StorageResized condition computed
+// during the last reconciliation of the object identified by key. A nil
+// condition removes the recorded condition.
+func (rt *ReconciliationTracker) SetStorageResizedCondition(key string, c *monitoringv1.Condition) {
+	rt.init()
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	if c == nil {
+		delete(rt.storageResized, key)
+		return
+	}
+
+	rt.storageResized[key] = *c
+}
+
+// GetStorageResizedCondition returns the StorageResized condition recorded
+// for the object identified by key or nil if there is none.
+func (rt *ReconciliationTracker) GetStorageResizedCondition(key string) *monitoringv1.Condition {
+	rt.mtx.RLock()
+	defer rt.mtx.RUnlock()
+
+	c, found := rt.storageResized[key]
+	if !found {
+		return nil
+	}
+
+	return &c
+}
+
 // ForgetObject removes the given object from the tracker.
 // It should be called when the controller detects that the object has been deleted.
 func (rt *ReconciliationTracker) ForgetObject(key string) {
@@ -220,6 +267,7 @@ func (rt *ReconciliationTracker) ForgetObject(key string) {
 
 	delete(rt.statusByObject, key)
 	delete(rt.refTracker, key)
+	delete(rt.storageResized, key)
 }
 
 // Describe implements the prometheus.Collector interface.
@@ -264,6 +312,7 @@ type Metrics struct {
 	watchCounter           prometheus.Counter
 	watchFailedCounter     prometheus.Counter
 	stsDeleteCreateCounter prometheus.Counter
+	driftCounter           *prometheus.CounterVec
 	// triggerByCounter is a set of counters keeping track of the amount
 	// of times Prometheus Operator was triggered to reconcile its created
 	// objects. It is split in the dimensions of Kubernetes objects and
@@ -294,6 +343,10 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 			Name: "prometheus_operator_reconcile_sts_delete_create_total",
 			Help: "Number of times that reconciling a statefulset required deleting and re-creating it",
 		}),
+		driftCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "prometheus_operator_managed_object_drifts_total",
+			Help: "Number of times that the fields of a managed object were found modified by other field managers",
+		}, []string{"kind"}),
 		listCounter: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "prometheus_operator_list_operations_total",
 			Help: "Total number of list operations",
@@ -321,6 +374,7 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 	m.reg.MustRegister(
 		m.triggerByCounter,
 		m.stsDeleteCreateCounter,
+		m.driftCounter,
 		m.listCounter,
 		m.listFailedCounter,
 		m.watchCounter,
@@ -0,0 +1,102 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+)
+
+// MakePodDisruptionBudget returns the PodDisruptionBudget protecting the pods
+// of the statefulset. The PodDisruptionBudget has the same name, labels,
+// owner and pod selector as the statefulset.
+func MakePodDisruptionBudget(spec monitoringv1.PodDisruptionBudgetSpec, sset *appsv1.StatefulSet) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            sset.Name,
+			Namespace:       sset.Namespace,
+			Labels:          maps.Clone(sset.Labels),
+			OwnerReferences: sset.OwnerReferences,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:                   sset.Spec.Selector.DeepCopy(),
+			MinAvailable:               spec.MinAvailable,
+			MaxUnavailable:             spec.MaxUnavailable,
+			UnhealthyPodEvictionPolicy: spec.UnhealthyPodEvictionPolicy,
+		},
+	}
+
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		maxUnavailable := intstr.FromInt32(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return pdb
+}
+
+// ReconcilePodDisruptionBudgets creates or updates the given
+// PodDisruptionBudgets and deletes the PodDisruptionBudgets controlled by the
+// owner which match the selector but aren't in the list.
+func ReconcilePodDisruptionBudgets(ctx context.Context, kclient kubernetes.Interface, owner metav1.Object, selector labels.Selector, pdbs []*policyv1.PodDisruptionBudget) error {
+	pdbClient := kclient.PolicyV1().PodDisruptionBudgets(owner.GetNamespace())
+
+	expected := make(map[string]struct{}, len(pdbs))
+	for _, pdb := range pdbs {
+		if _, err := k8s.CreateOrUpdatePodDisruptionBudget(ctx, pdbClient, pdb); err != nil {
+			return fmt.Errorf("failed to reconcile PodDisruptionBudget %s: %w", pdb.Name, err)
+		}
+		expected[pdb.Name] = struct{}{}
+	}
+
+	list, err := pdbClient.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		// Don't fail when the operator isn't allowed to manage
+		// PodDisruptionBudgets and none is requested.
+		if len(pdbs) == 0 && apierrors.IsForbidden(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+
+	var errs []error
+	for _, pdb := range list.Items {
+		if _, found := expected[pdb.Name]; found {
+			continue
+		}
+
+		if !metav1.IsControlledBy(&pdb, owner) {
+			continue
+		}
+
+		if err := pdbClient.Delete(ctx, pdb.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete PodDisruptionBudget %s: %w", pdb.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func newPodDisruptionBudgetStatefulSet(name, shard string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				"prometheus": "test",
+				"shard":      shard,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "monitoring.coreos.com/v1",
+					Kind:       "Prometheus",
+					Name:       "test",
+					UID:        "1",
+					Controller: ptr.To(true),
+				},
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"prometheus": "test",
+					"shard":      shard,
+				},
+			},
+		},
+	}
+}
+
+func TestMakePodDisruptionBudget(t *testing.T) {
+	sset := newPodDisruptionBudgetStatefulSet("prometheus-test-shard-1", "1")
+
+	pdb := MakePodDisruptionBudget(monitoringv1.PodDisruptionBudgetSpec{}, sset)
+	require.Equal(t, "prometheus-test-shard-1", pdb.Name)
+	require.Equal(t, "default", pdb.Namespace)
+	require.Equal(t, sset.Labels, pdb.Labels)
+	require.Equal(t, sset.OwnerReferences, pdb.OwnerReferences)
+	require.Equal(t, sset.Spec.Selector, pdb.Spec.Selector)
+	require.Nil(t, pdb.Spec.MinAvailable)
+	require.Equal(t, ptr.To(intstr.FromInt32(1)), pdb.Spec.MaxUnavailable)
+
+	pdb = MakePodDisruptionBudget(monitoringv1.PodDisruptionBudgetSpec{
+		MinAvailable:               ptr.To(intstr.FromString("50%")),
+		UnhealthyPodEvictionPolicy: ptr.To(policyv1.AlwaysAllow),
+	}, sset)
+	require.Equal(t, ptr.To(intstr.FromString("50%")), pdb.Spec.MinAvailable)
+	require.Nil(t, pdb.Spec.MaxUnavailable)
+	require.Equal(t, ptr.To(policyv1.AlwaysAllow), pdb.Spec.UnhealthyPodEvictionPolicy)
+}
+
+func TestReconcilePodDisruptionBudgets(t *testing.T) {
+	owner := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			UID:       types.UID("1"),
+		},
+	}
+	selector := labels.SelectorFromSet(labels.Set{"prometheus": "test"})
+	spec := monitoringv1.PodDisruptionBudgetSpec{MaxUnavailable: ptr.To(intstr.FromInt32(2))}
+
+	// A PodDisruptionBudget with the same labels but not owned by the
+	// Prometheus object.
+	unmanaged := MakePodDisruptionBudget(spec, newPodDisruptionBudgetStatefulSet("unmanaged", "0"))
+	unmanaged.OwnerReferences = nil
+
+	kclient := fake.NewClientset(
+		MakePodDisruptionBudget(monitoringv1.PodDisruptionBudgetSpec{}, newPodDisruptionBudgetStatefulSet("prometheus-test", "0")),
+		MakePodDisruptionBudget(monitoringv1.PodDisruptionBudgetSpec{}, newPodDisruptionBudgetStatefulSet("prometheus-test-shard-2", "2")),
+		unmanaged,
+	)
+
+	// Scale down from 3 to 2 shards.
+	err := ReconcilePodDisruptionBudgets(
+		context.Background(),
+		kclient,
+		owner,
+		selector,
+		[]*policyv1.PodDisruptionBudget{
+			MakePodDisruptionBudget(spec, newPodDisruptionBudgetStatefulSet("prometheus-test", "0")),
+			MakePodDisruptionBudget(spec, newPodDisruptionBudgetStatefulSet("prometheus-test-shard-1", "1")),
+		},
+	)
+	require.NoError(t, err)
+
+	list, err := kclient.PolicyV1().PodDisruptionBudgets("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+
+	pdbs := map[string]policyv1.PodDisruptionBudget{}
+	for _, pdb := range list.Items {
+		pdbs[pdb.Name] = pdb
+	}
+	require.Len(t, pdbs, 3)
+	require.Contains(t, pdbs, "unmanaged")
+	require.Equal(t, ptr.To(intstr.FromInt32(2)), pdbs["prometheus-test"].Spec.MaxUnavailable)
+	require.Equal(t, ptr.To(intstr.FromInt32(2)), pdbs["prometheus-test-shard-1"].Spec.MaxUnavailable)
+
+	// Unset the podDisruptionBudget field.
+	err = ReconcilePodDisruptionBudgets(context.Background(), kclient, owner, selector, nil)
+	require.NoError(t, err)
+
+	list, err = kclient.PolicyV1().PodDisruptionBudgets("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "unmanaged", list.Items[0].Name)
+}
@@ -0,0 +1,221 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
+)
+
+// ErrQuotaExceeded is returned when a configuration resource exceeds the
+// MonitoringQuota of its namespace.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// MonitoringQuotas holds the effective MonitoringQuota of each namespace.
+type MonitoringQuotas map[string]*monitoringv1alpha1.MonitoringQuotaSpec
+
+// NewMonitoringQuotas returns the effective quotas of the given
+// MonitoringQuota objects. When a namespace has several MonitoringQuota
+// objects, the lowest value of each limit applies.
+func NewMonitoringQuotas(quotas []*monitoringv1alpha1.MonitoringQuota) MonitoringQuotas {
+	mq := MonitoringQuotas{}
+	for _, q := range quotas {
+		spec, found := mq[q.Namespace]
+		if !found {
+			mq[q.Namespace] = q.Spec.DeepCopy()
+			continue
+		}
+
+		spec.ServiceMonitors = minPtr(spec.ServiceMonitors, q.Spec.ServiceMonitors)
+		spec.PodMonitors = minPtr(spec.PodMonitors, q.Spec.PodMonitors)
+		spec.Probes = minPtr(spec.Probes, q.Spec.Probes)
+		spec.ScrapeConfigs = minPtr(spec.ScrapeConfigs, q.Spec.ScrapeConfigs)
+		spec.PrometheusRules = minPtr(spec.PrometheusRules, q.Spec.PrometheusRules)
+		spec.Rules = minPtr(spec.Rules, q.Spec.Rules)
+		spec.SampleLimit = minPtr(spec.SampleLimit, q.Spec.SampleLimit)
+		spec.TargetLimit = minPtr(spec.TargetLimit, q.Spec.TargetLimit)
+	}
+
+	return mq
+}
+
+// ListMonitoringQuotas returns the effective quotas from the MonitoringQuota
+// objects in the informers' cache. It returns nil if the informers are nil.
+func ListMonitoringQuotas(infs *informers.ForResource) (MonitoringQuotas, error) {
+	if infs == nil {
+		return nil, nil
+	}
+
+	var quotas []*monitoringv1alpha1.MonitoringQuota
+	if err := infs.ListAll(labels.Everything(), func(obj any) {
+		quotas = append(quotas, obj.(*monitoringv1alpha1.MonitoringQuota))
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list MonitoringQuota objects: %w", err)
+	}
+
+	return NewMonitoringQuotas(quotas), nil
+}
+
+func minPtr[T cmp.Ordered](a, b *T) *T {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	return ptr.To(min(*a, *b))
+}
+
+// CheckScrapeLimits verifies that the sample and target limits of a
+// resource in the namespace comply with the quota.
+func (mq MonitoringQuotas) CheckScrapeLimits(namespace string, sampleLimit, targetLimit *int64) error {
+	q := mq[namespace]
+	if q == nil {
+		return nil
+	}
+
+	if err := checkScrapeLimit("sampleLimit", sampleLimit, q.SampleLimit); err != nil {
+		return err
+	}
+
+	return checkScrapeLimit("targetLimit", targetLimit, q.TargetLimit)
+}
+
+func checkScrapeLimit(name string, limit, quota *int64) error {
+	if quota == nil {
+		return nil
+	}
+
+	if limit == nil {
+		return fmt.Errorf("%w: %s must be defined and lower than or equal to %d", ErrQuotaExceeded, name, *quota)
+	}
+
+	if *limit > *quota {
+		return fmt.Errorf("%w: %s %d is greater than %d", ErrQuotaExceeded, name, *limit, *quota)
+	}
+
+	return nil
+}
+
+// QuotaUsage counts the resources of a given kind admitted in each
+// namespace.
+type QuotaUsage struct {
+	quotas    MonitoringQuotas
+	kind      string
+	resources map[string]int32
+	rules     map[string]int32
+}
+
+// NewQuotaUsage returns a QuotaUsage for the given kind (e.g.
+// ServiceMonitor).
+func (mq MonitoringQuotas) NewQuotaUsage(kind string) *QuotaUsage {
+	return &QuotaUsage{
+		quotas:    mq,
+		kind:      kind,
+		resources: map[string]int32{},
+		rules:     map[string]int32{},
+	}
+}
+
+func (u *QuotaUsage) maxResources(q *monitoringv1alpha1.MonitoringQuotaSpec) *int32 {
+	switch u.kind {
+	case monitoringv1.ServiceMonitorsKind:
+		return q.ServiceMonitors
+	case monitoringv1.PodMonitorsKind:
+		return q.PodMonitors
+	case monitoringv1.ProbesKind:
+		return q.Probes
+	case monitoringv1alpha1.ScrapeConfigsKind:
+		return q.ScrapeConfigs
+	case monitoringv1.PrometheusRuleKind:
+		return q.PrometheusRules
+	}
+
+	return nil
+}
+
+// Admit accounts for the object if it doesn't exceed the quota of its
+// namespace. Otherwise it returns an error wrapping ErrQuotaExceeded.
+//
+// For PrometheusRule objects, the number of rules is also verified.
+func (u *QuotaUsage) Admit(o metav1.Object) error {
+	ns := o.GetNamespace()
+	q := u.quotas[ns]
+	if q == nil {
+		return nil
+	}
+
+	if maxResources := u.maxResources(q); maxResources != nil && u.resources[ns] >= *maxResources {
+		return fmt.Errorf("%w: the namespace can't have more than %d %s object(s)", ErrQuotaExceeded, *maxResources, u.kind)
+	}
+
+	var rules int32
+	if promRule, ok := o.(*monitoringv1.PrometheusRule); ok {
+		for _, g := range promRule.Spec.Groups {
+			rules += int32(len(g.Rules))
+		}
+
+		if q.Rules != nil && u.rules[ns]+rules > *q.Rules {
+			return fmt.Errorf("%w: the namespace can't have more than %d rules (%d already selected, %d requested)", ErrQuotaExceeded, *q.Rules, u.rules[ns], rules)
+		}
+	}
+
+	u.resources[ns]++
+	u.rules[ns] += rules
+
+	return nil
+}
+
+// SortedByCreationTimestamp returns the keys of the objects ordered by
+// creation timestamp (oldest first) and key. It ensures that the quotas
+// admit the same objects across reconciliations.
+//
+// The values of the map must implement metav1.Object.
+func SortedByCreationTimestamp[T any](objects map[string]T) []string {
+	keys := make([]string, 0, len(objects))
+	for k := range objects {
+		keys = append(keys, k)
+	}
+
+	creationTimestamp := func(k string) metav1.Time {
+		return any(objects[k]).(metav1.Object).GetCreationTimestamp()
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		ta, tb := creationTimestamp(a), creationTimestamp(b)
+		if !ta.Equal(&tb) {
+			if ta.Before(&tb) {
+				return -1
+			}
+			return 1
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	return keys
+}
@@ -0,0 +1,213 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+)
+
+func TestNewMonitoringQuotas(t *testing.T) {
+	mq := NewMonitoringQuotas([]*monitoringv1alpha1.MonitoringQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns1"},
+			Spec: monitoringv1alpha1.MonitoringQuotaSpec{
+				ServiceMonitors: ptr.To(int32(10)),
+				SampleLimit:     ptr.To(int64(1000)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns1"},
+			Spec: monitoringv1alpha1.MonitoringQuotaSpec{
+				ServiceMonitors: ptr.To(int32(5)),
+				PodMonitors:     ptr.To(int32(2)),
+				SampleLimit:     ptr.To(int64(2000)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns2"},
+			Spec: monitoringv1alpha1.MonitoringQuotaSpec{
+				Rules: ptr.To(int32(100)),
+			},
+		},
+	})
+
+	require.Equal(t, MonitoringQuotas{
+		"ns1": {
+			ServiceMonitors: ptr.To(int32(5)),
+			PodMonitors:     ptr.To(int32(2)),
+			SampleLimit:     ptr.To(int64(1000)),
+		},
+		"ns2": {
+			Rules: ptr.To(int32(100)),
+		},
+	}, mq)
+}
+
+func TestCheckScrapeLimits(t *testing.T) {
+	mq := MonitoringQuotas{
+		"ns1": {
+			SampleLimit: ptr.To(int64(1000)),
+			TargetLimit: ptr.To(int64(10)),
+		},
+		"ns2": {
+			ServiceMonitors: ptr.To(int32(1)),
+		},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		namespace   string
+		sampleLimit *int64
+		targetLimit *int64
+		err         bool
+	}{
+		{
+			name:      "no quota",
+			namespace: "default",
+		},
+		{
+			name:      "no scrape limits in quota",
+			namespace: "ns2",
+		},
+		{
+			name:        "limits within quota",
+			namespace:   "ns1",
+			sampleLimit: ptr.To(int64(1000)),
+			targetLimit: ptr.To(int64(5)),
+		},
+		{
+			name:        "missing sample limit",
+			namespace:   "ns1",
+			targetLimit: ptr.To(int64(5)),
+			err:         true,
+		},
+		{
+			name:        "sample limit too high",
+			namespace:   "ns1",
+			sampleLimit: ptr.To(int64(1001)),
+			targetLimit: ptr.To(int64(5)),
+			err:         true,
+		},
+		{
+			name:        "target limit too high",
+			namespace:   "ns1",
+			sampleLimit: ptr.To(int64(100)),
+			targetLimit: ptr.To(int64(50)),
+			err:         true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := mq.CheckScrapeLimits(tc.namespace, tc.sampleLimit, tc.targetLimit)
+			if tc.err {
+				require.ErrorIs(t, err, ErrQuotaExceeded)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	// A nil MonitoringQuotas value admits everything.
+	var nilQuotas MonitoringQuotas
+	require.NoError(t, nilQuotas.CheckScrapeLimits("ns1", nil, nil))
+}
+
+func TestQuotaUsageAdmit(t *testing.T) {
+	mq := MonitoringQuotas{
+		"ns1": {
+			ServiceMonitors: ptr.To(int32(2)),
+			PrometheusRules: ptr.To(int32(3)),
+			Rules:           ptr.To(int32(3)),
+		},
+	}
+
+	t.Run("ServiceMonitor", func(t *testing.T) {
+		u := mq.NewQuotaUsage(monitoringv1.ServiceMonitorsKind)
+
+		for i, expectErr := range []bool{false, false, true, true} {
+			err := u.Admit(&monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "sm", Namespace: "ns1"}})
+			if expectErr {
+				require.ErrorIs(t, err, ErrQuotaExceeded, "object %d", i)
+				continue
+			}
+			require.NoError(t, err, "object %d", i)
+		}
+
+		// Other namespaces aren't affected.
+		require.NoError(t, u.Admit(&monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "sm", Namespace: "ns2"}}))
+	})
+
+	t.Run("PodMonitor without quota", func(t *testing.T) {
+		u := mq.NewQuotaUsage(monitoringv1.PodMonitorsKind)
+
+		for range 5 {
+			require.NoError(t, u.Admit(&monitoringv1.PodMonitor{ObjectMeta: metav1.ObjectMeta{Name: "pm", Namespace: "ns1"}}))
+		}
+	})
+
+	t.Run("PrometheusRule", func(t *testing.T) {
+		u := mq.NewQuotaUsage(monitoringv1.PrometheusRuleKind)
+
+		newRule := func(rules int) *monitoringv1.PrometheusRule {
+			group := monitoringv1.RuleGroup{Name: "group"}
+			for range rules {
+				group.Rules = append(group.Rules, monitoringv1.Rule{Record: "foo"})
+			}
+
+			return &monitoringv1.PrometheusRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "ns1"},
+				Spec:       monitoringv1.PrometheusRuleSpec{Groups: []monitoringv1.RuleGroup{group}},
+			}
+		}
+
+		require.NoError(t, u.Admit(newRule(2)))
+		// Rejected because it would exceed the number of rules.
+		require.ErrorIs(t, u.Admit(newRule(2)), ErrQuotaExceeded)
+		require.NoError(t, u.Admit(newRule(1)))
+		// Rejected because it would exceed the number of objects.
+		require.NoError(t, u.Admit(newRule(0)))
+		require.ErrorIs(t, u.Admit(newRule(0)), ErrQuotaExceeded)
+	})
+}
+
+func TestSortedByCreationTimestamp(t *testing.T) {
+	now := time.Now()
+	newServiceMonitor := func(name string, age time.Duration) *monitoringv1.ServiceMonitor {
+		return &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ns1",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+	}
+
+	keys := SortedByCreationTimestamp(map[string]*monitoringv1.ServiceMonitor{
+		"ns1/c": newServiceMonitor("c", time.Hour),
+		"ns1/a": newServiceMonitor("a", time.Minute),
+		"ns1/b": newServiceMonitor("b", time.Minute),
+		"ns1/d": newServiceMonitor("d", 0),
+	})
+
+	require.Equal(t, []string{"ns1/c", "ns1/a", "ns1/b", "ns1/d"}, keys)
+}
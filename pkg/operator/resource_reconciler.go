@@ -637,6 +637,17 @@ func (rr *ResourceReconciler) EnqueueForReconciliation(obj metav1.Object) {
 	rr.reconcileQ.Add(KeyForObject(obj))
 }
 
+// EnqueueForReconciliationAfter asks for reconciling the object after the
+// given duration. It is useful when the controller waits for resources which
+// aren't watched.
+func (rr *ResourceReconciler) EnqueueForReconciliationAfter(obj metav1.Object, d time.Duration) {
+	if !rr.isManagedByController(obj) {
+		return
+	}
+
+	rr.reconcileQ.AddAfter(KeyForObject(obj), d)
+}
+
 // EnqueueForStatus asks for updating the status of the object.
 func (rr *ResourceReconciler) EnqueueForStatus(obj metav1.Object) {
 	if !rr.isManagedByController(obj) {
@@ -72,19 +72,89 @@ type PrometheusRuleSelector struct {
 	parserOptions parser.Options
 
 	eventRecorder *EventRecorder
+	quotas        MonitoringQuotas
 
 	logger *slog.Logger
 }
 
+// PrometheusRuleSelectorOption customizes the PrometheusRuleSelector.
+type PrometheusRuleSelectorOption func(*PrometheusRuleSelector)
+
+// WithMonitoringQuotas enforces the MonitoringQuota limits when selecting
+// the PrometheusRule objects.
+func WithMonitoringQuotas(quotas MonitoringQuotas) PrometheusRuleSelectorOption {
+	return func(prs *PrometheusRuleSelector) {
+		prs.quotas = quotas
+	}
+}
+
 type PrometheusRuleSelection struct {
 	selection TypedResourcesSelection[*monitoringv1.PrometheusRule] // PrometheusRules selected.
 	ruleFiles map[string]string                                     // Map of rule configuration files serialized to the Prometheus format (key=filename).
+	resources map[string]*monitoringv1.PrometheusRule               // Map of PrometheusRules (key=filename).
 }
 
 func (prs *PrometheusRuleSelection) RuleFiles() map[string]string {
 	return prs.ruleFiles
 }
 
+// RuleFilesForShards distributes the rule groups of the rule files across
+// the given number of shards.
+//
+// The assign function returns the shard evaluating the rule group of the
+// PrometheusRule object. It may modify the group before it gets serialized.
+//
+// It returns the rule files of each shard and the shards evaluating each
+// PrometheusRule object (key=<namespace>/<name>).
+func (prs *PrometheusRuleSelection) RuleFilesForShards(shards int32, assign func(*monitoringv1.PrometheusRule, *monitoringv1.RuleGroup) int32) ([]map[string]string, map[string][]int32, error) {
+	var (
+		ruleFiles  = make([]map[string]string, shards)
+		ruleShards = make(map[string][]int32, len(prs.ruleFiles))
+	)
+	for shard := range shards {
+		ruleFiles[shard] = map[string]string{}
+	}
+
+	for filename, content := range prs.ruleFiles {
+		var spec monitoringv1.PrometheusRuleSpec
+		if err := yaml.Unmarshal([]byte(content), &spec); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal rule file %q: %w", filename, err)
+		}
+
+		var (
+			promRule = prs.resources[filename]
+			groups   = make([][]monitoringv1.RuleGroup, shards)
+		)
+		for i := range spec.Groups {
+			shard := assign(promRule, &spec.Groups[i])
+			if shard < 0 || shard >= shards {
+				return nil, nil, fmt.Errorf("rule group %q of %s/%s assigned to invalid shard %d", spec.Groups[i].Name, promRule.Namespace, promRule.Name, shard)
+			}
+
+			groups[shard] = append(groups[shard], spec.Groups[i])
+		}
+
+		var assigned []int32
+		for shard := range shards {
+			if len(groups[shard]) == 0 {
+				continue
+			}
+
+			b, err := yaml.Marshal(monitoringv1.PrometheusRuleSpec{Groups: groups[shard]})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal content: %w", err)
+			}
+
+			ruleFiles[shard][filename] = string(b)
+			assigned = append(assigned, shard)
+		}
+
+		ruleShards[fmt.Sprintf("%s/%s", promRule.Namespace, promRule.Name)] = assigned
+	}
+
+	return ruleFiles, ruleShards, nil
+}
+
 func (prs *PrometheusRuleSelection) Selected() TypedResourcesSelection[*monitoringv1.PrometheusRule] {
 	return prs.selection
 }
@@ -98,7 +168,7 @@ func (prs *PrometheusRuleSelection) RejectedLen() int {
 }
 
 // NewPrometheusRuleSelector returns a PrometheusRuleSelector pointer.
-func NewPrometheusRuleSelector(ruleFormat RuleConfigurationFormat, version string, labelSelector *metav1.LabelSelector, nsLabeler *namespacelabeler.Labeler, ruleInformer *informers.ForResource, eventRecorder *EventRecorder, logger *slog.Logger, parserOptions parser.Options) (*PrometheusRuleSelector, error) {
+func NewPrometheusRuleSelector(ruleFormat RuleConfigurationFormat, version string, labelSelector *metav1.LabelSelector, nsLabeler *namespacelabeler.Labeler, ruleInformer *informers.ForResource, eventRecorder *EventRecorder, logger *slog.Logger, parserOptions parser.Options, opts ...PrometheusRuleSelectorOption) (*PrometheusRuleSelector, error) {
 	componentVersion, err := semver.ParseTolerant(version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse version: %w", err)
@@ -109,7 +179,7 @@ func NewPrometheusRuleSelector(ruleFormat RuleConfigurationFormat, version strin
 		return nil, fmt.Errorf("convert rule label selector to selector: %w", err)
 	}
 
-	return &PrometheusRuleSelector{
+	prs := &PrometheusRuleSelector{
 		ruleFormat:    ruleFormat,
 		version:       componentVersion,
 		ruleSelector:  ruleSelector,
@@ -118,7 +188,13 @@ func NewPrometheusRuleSelector(ruleFormat RuleConfigurationFormat, version strin
 		parserOptions: parserOptions,
 		eventRecorder: eventRecorder,
 		logger:        logger,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(prs)
+	}
+
+	return prs, nil
 }
 
 func (prs *PrometheusRuleSelector) generateRulesConfiguration(promRule *monitoringv1.PrometheusRule) (string, error) {
@@ -258,13 +334,20 @@ func (prs *PrometheusRuleSelector) Select(namespaces []string) (PrometheusRuleSe
 
 	var (
 		marshalRules    = make(map[string]string, len(promRules))
+		ruleResources   = make(map[string]*monitoringv1.PrometheusRule, len(promRules))
 		rules           = make(TypedResourcesSelection[*monitoringv1.PrometheusRule], len(promRules))
 		namespacedNames = make([]string, 0, len(promRules))
 	)
 
-	accessor := NewAccessor(prs.logger)
+	var (
+		accessor = NewAccessor(prs.logger)
+		usage    = prs.quotas.NewQuotaUsage(monitoringv1.PrometheusRuleKind)
+	)
 
-	for ruleName, promRule := range promRules {
+	// Iterate by order of creation so that the quotas admit the oldest
+	// objects first.
+	for _, ruleName := range SortedByCreationTimestamp(promRules) {
+		promRule := promRules[ruleName]
 		var err error
 		var content string
 		if err := prs.nsLabeler.EnforceNamespaceLabel(promRule); err != nil {
@@ -287,8 +370,18 @@ func (prs *PrometheusRuleSelector) Select(namespaces []string) (PrometheusRuleSe
 			)
 			prs.eventRecorder.Eventf(promRule, corev1.EventTypeWarning, InvalidConfigurationEvent, selectingPrometheusRuleResourcesAction, "PrometheusRule %s was rejected due to invalid configuration: %v", promRule.Name, err)
 			reason = InvalidConfigurationEvent
+		} else if err = usage.Admit(promRule); err != nil {
+			prs.logger.Warn(
+				"skipping prometheusrule",
+				"error", err.Error(),
+				"prometheusrule", promRule.Name,
+				"namespace", promRule.Namespace,
+			)
+			prs.eventRecorder.Eventf(promRule, corev1.EventTypeWarning, QuotaExceededEvent, selectingPrometheusRuleResourcesAction, "PrometheusRule %s was rejected because it exceeds the MonitoringQuota of the namespace: %v", promRule.Name, err)
+			reason = QuotaExceeded
 		} else {
 			marshalRules[ruleName] = content
+			ruleResources[ruleName] = promRule
 			namespacedNames = append(namespacedNames, fmt.Sprintf("%s/%s", promRule.Namespace, promRule.Name))
 		}
 
@@ -310,6 +403,7 @@ func (prs *PrometheusRuleSelector) Select(namespaces []string) (PrometheusRuleSe
 	return PrometheusRuleSelection{
 		selection: rules,
 		ruleFiles: marshalRules,
+		resources: ruleResources,
 	}, nil
 }
 
@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
@@ -830,3 +831,59 @@ func TestPrometheusRuleSync(t *testing.T) {
 		)
 	})
 }
+
+func TestRuleFilesForShards(t *testing.T) {
+	promRule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo",
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{Name: "group-0", Rules: []monitoringv1.Rule{{Record: "a", Expr: intstr.FromString("vector(1)")}}},
+				{Name: "group-1", Rules: []monitoringv1.Rule{{Record: "b", Expr: intstr.FromString("vector(1)")}}},
+				{Name: "group-2", Labels: map[string]string{"shard": "0"}, Rules: []monitoringv1.Rule{{Record: "c", Expr: intstr.FromString("vector(1)")}}},
+			},
+		},
+	}
+
+	content, err := yaml.Marshal(promRule.Spec)
+	require.NoError(t, err)
+
+	prs := PrometheusRuleSelection{
+		ruleFiles: map[string]string{"default-foo-uid.yaml": string(content)},
+		resources: map[string]*monitoringv1.PrometheusRule{"default-foo-uid.yaml": promRule},
+	}
+
+	ruleFiles, ruleShards, err := prs.RuleFilesForShards(3, func(_ *monitoringv1.PrometheusRule, g *monitoringv1.RuleGroup) int32 {
+		if g.Name == "group-1" {
+			return 2
+		}
+
+		// The assign function can modify the group.
+		g.Labels = nil
+		return 0
+	})
+	require.NoError(t, err)
+	require.Len(t, ruleFiles, 3)
+	require.Equal(t, map[string][]int32{"default/foo": {0, 2}}, ruleShards)
+
+	groupNames := func(shard int) []string {
+		var spec monitoringv1.PrometheusRuleSpec
+		require.NoError(t, yaml.Unmarshal([]byte(ruleFiles[shard]["default-foo-uid.yaml"]), &spec))
+
+		var names []string
+		for _, g := range spec.Groups {
+			require.Nil(t, g.Labels)
+			names = append(names, g.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"group-0", "group-2"}, groupNames(0))
+	require.Empty(t, ruleFiles[1])
+	require.Equal(t, []string{"group-1"}, groupNames(2))
+
+	_, _, err = prs.RuleFilesForShards(3, func(*monitoringv1.PrometheusRule, *monitoringv1.RuleGroup) int32 { return 3 })
+	require.Error(t, err)
+}
@@ -21,10 +21,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	sortutil "github.com/prometheus-operator/prometheus-operator/internal/sortutil"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
 )
 
@@ -38,6 +40,7 @@ const MaxSecretDataSizeBytes = corev1.MaxSecretSize - 50_000
 type ShardedSecret struct {
 	template     *corev1.Secret
 	data         map[string][]byte
+	overlays     []monitoringv1.Overlay
 	secretShards []*corev1.Secret
 }
 
@@ -46,6 +49,11 @@ func (s *ShardedSecret) updateSecrets(ctx context.Context, sClient typedcorev1.S
 	secrets := s.shard()
 
 	for _, secret := range secrets {
+		secret = secret.DeepCopy()
+		if err := k8s.ApplyOverlays(s.overlays, secret); err != nil {
+			return err
+		}
+
 		err := k8s.CreateOrUpdateSecret(ctx, sClient, secret)
 		if err != nil {
 			return fmt.Errorf("failed to create secret %q: %w", secret.Name, err)
@@ -143,15 +151,66 @@ func (s *ShardedSecret) Volume(name string) corev1.Volume {
 	return volume
 }
 
-func ReconcileShardedSecret(ctx context.Context, data map[string][]byte, client kubernetes.Interface, template *corev1.Secret) (*ShardedSecret, error) {
+func ReconcileShardedSecret(ctx context.Context, data map[string][]byte, client kubernetes.Interface, template *corev1.Secret, overlays []monitoringv1.Overlay) (*ShardedSecret, error) {
+	shardedSecret := NewShardedSecret(data, template, overlays)
+
+	if err := shardedSecret.Update(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return shardedSecret, nil
+}
+
+// NewShardedSecret returns a ShardedSecret holding the given data without
+// writing the secrets. It should be used when the secrets must be written
+// later with Update.
+//
+// The overlays are applied to the secret shards before they are written.
+func NewShardedSecret(data map[string][]byte, template *corev1.Secret, overlays []monitoringv1.Overlay) *ShardedSecret {
 	shardedSecret := &ShardedSecret{
 		template: template,
 		data:     data,
+		overlays: overlays,
 	}
+	shardedSecret.shard()
+
+	return shardedSecret
+}
 
-	if err := shardedSecret.updateSecrets(ctx, client.CoreV1().Secrets(template.Namespace)); err != nil {
-		return nil, fmt.Errorf("failed to update the TLS secrets: %w", err)
+// Update creates or updates the secrets and deletes the secret shards which
+// aren't used anymore.
+func (s *ShardedSecret) Update(ctx context.Context, client kubernetes.Interface) error {
+	if err := s.updateSecrets(ctx, client.CoreV1().Secrets(s.template.Namespace)); err != nil {
+		return fmt.Errorf("failed to update the TLS secrets: %w", err)
 	}
 
-	return shardedSecret, nil
+	return nil
+}
+
+// SecretGetter retrieves Secret objects by <namespace>/<name> key, typically
+// from an informer's cache.
+type SecretGetter interface {
+	Get(string) (runtime.Object, error)
+}
+
+// CleanupShardedSecret deletes all the secret shards matching the template.
+// It should be used when the sharded secret isn't needed anymore.
+//
+// No API request is sent when the first secret shard isn't found by the
+// getter.
+func CleanupShardedSecret(ctx context.Context, client kubernetes.Interface, getter SecretGetter, template *corev1.Secret) error {
+	shardedSecret := &ShardedSecret{
+		template: template,
+	}
+
+	_, err := getter.Get(fmt.Sprintf("%s/%s", template.Namespace, shardedSecret.secretNameAt(0)))
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get secret %q: %w", shardedSecret.secretNameAt(0), err)
+	}
+
+	return shardedSecret.cleanupExcessSecretShards(ctx, client.CoreV1().Secrets(template.Namespace), -1)
 }
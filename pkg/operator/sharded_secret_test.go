@@ -15,10 +15,19 @@
 package operator
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
 func TestShardedSecret(t *testing.T) {
@@ -87,3 +96,104 @@ func TestShardedSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestShardedSecretOverlays(t *testing.T) {
+	template := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "ns",
+		},
+	}
+	data := map[string][]byte{
+		"one": make([]byte, MaxSecretDataSizeBytes-3), // -3 because of the key size
+		"two": []byte("data"),
+	}
+	overlays := []monitoringv1.Overlay{
+		{
+			Target: monitoringv1.OverlayTarget{Kind: monitoringv1.SecretOverlayTargetKind, Name: ptr.To("secret-1")},
+			Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+			Patch:  `{"metadata": {"labels": {"foo": "bar"}}}`,
+		},
+	}
+
+	client := fake.NewClientset()
+	s, err := ReconcileShardedSecret(context.Background(), data, client, template, overlays)
+	require.NoError(t, err)
+
+	// The secret shards held by the ShardedSecret aren't modified.
+	for _, secret := range s.secretShards {
+		require.Empty(t, secret.Labels)
+	}
+
+	for name, labels := range map[string]map[string]string{
+		"secret-0": nil,
+		"secret-1": {"foo": "bar"},
+	} {
+		secret, err := client.CoreV1().Secrets("ns").Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, labels, secret.Labels)
+	}
+}
+
+type fakeSecretGetter map[string]*corev1.Secret
+
+func (g fakeSecretGetter) Get(key string) (runtime.Object, error) {
+	s, found := g[key]
+	if !found {
+		return nil, apierrors.NewNotFound(corev1.Resource("secrets"), key)
+	}
+
+	return s, nil
+}
+
+func TestCleanupShardedSecret(t *testing.T) {
+	template := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "ns",
+		},
+	}
+
+	for _, tc := range []struct {
+		desc          string
+		secrets       []string
+		expectDeletes int
+	}{
+		{
+			desc:          "no secret",
+			expectDeletes: 0,
+		},
+		{
+			desc:          "two secret shards",
+			secrets:       []string{"secret-0", "secret-1"},
+			expectDeletes: 3,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var (
+				objects []runtime.Object
+				getter  = fakeSecretGetter{}
+			)
+			for _, name := range tc.secrets {
+				s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}}
+				objects = append(objects, s)
+				getter["ns/"+name] = s
+			}
+
+			client := fake.NewClientset(objects...)
+			require.NoError(t, CleanupShardedSecret(context.Background(), client, getter, template))
+
+			var deletes int
+			for _, action := range client.Actions() {
+				if _, ok := action.(clientgotesting.DeleteAction); ok {
+					deletes++
+				}
+			}
+			require.Equal(t, tc.expectDeletes, deletes)
+
+			secrets, err := client.CoreV1().Secrets("ns").List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err)
+			require.Empty(t, secrets.Items)
+		})
+	}
+}
@@ -31,6 +31,10 @@ const (
 	// DeprecatedFieldsInUseReason is used in status conditions to indicate that
 	// the resource uses deprecated fields.
 	DeprecatedFieldsInUseReason = "DeprecatedFieldsInUse"
+
+	// OverlayFailedReason is used in status conditions to indicate that an
+	// overlay is invalid or couldn't be applied to a generated object.
+	OverlayFailedReason = "OverlayFailed"
 )
 
 // StatusGetter represents a workload resource implementing the interface
@@ -47,3 +47,30 @@ func CheckStorageClass(ctx context.Context, canReadStorageClass bool, kclient ku
 
 	return nil
 }
+
+// CheckVolumeExpansion verifies that the storage class allows the expansion
+// of persistent volume claims. It returns nil if the operator doesn't have
+// enough permissions to read storage classes.
+func CheckVolumeExpansion(ctx context.Context, canReadStorageClass bool, kclient kubernetes.Interface, storageClassName string) error {
+	if !canReadStorageClass {
+		return nil
+	}
+
+	if storageClassName == "" {
+		return fmt.Errorf("volume expansion isn't supported without storage class")
+	}
+
+	sc, err := kclient.StorageV1().StorageClasses().Get(ctx, storageClassName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("storage class %q does not exist", storageClassName)
+		}
+		return fmt.Errorf("cannot get %q storageclass: %w", storageClassName, err)
+	}
+
+	if !ptr.Deref(sc.AllowVolumeExpansion, false) {
+		return fmt.Errorf("storage class %q doesn't support volume expansion (allowVolumeExpansion isn't true)", storageClassName)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+)
+
+// ExpandPersistentVolumeClaims increases the storage request of the bound
+// persistent volume claims of the existing statefulset when the desired
+// statefulset requests more storage in its volume claim templates.
+//
+// The volume claim templates being immutable, it returns true when at least
+// one template requests more storage. In this case, the caller should delete
+// the existing statefulset without deleting the pods and re-create it from
+// the desired spec.
+//
+// It fails if the storage class of a persistent volume claim doesn't support
+// volume expansion.
+func ExpandPersistentVolumeClaims(ctx context.Context, kclient kubernetes.Interface, canReadStorageClass bool, existing, desired *appsv1.StatefulSet) (bool, error) {
+	var (
+		expand  bool
+		checked = map[string]struct{}{}
+	)
+
+	for _, tmpl := range desired.Spec.VolumeClaimTemplates {
+		i := slices.IndexFunc(existing.Spec.VolumeClaimTemplates, func(pvc corev1.PersistentVolumeClaim) bool {
+			return pvc.Name == tmpl.Name
+		})
+		if i < 0 {
+			continue
+		}
+
+		size, found := tmpl.Spec.Resources.Requests[corev1.ResourceStorage]
+		if !found {
+			continue
+		}
+
+		current := existing.Spec.VolumeClaimTemplates[i].Spec.Resources.Requests[corev1.ResourceStorage]
+		if size.Cmp(current) <= 0 {
+			continue
+		}
+
+		pvcs, err := listStatefulSetClaims(ctx, kclient, existing, tmpl.Name)
+		if err != nil {
+			return false, err
+		}
+
+		for _, pvc := range pvcs {
+			if pvc.Status.Phase != corev1.ClaimBound {
+				continue
+			}
+
+			request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			if size.Cmp(request) <= 0 {
+				continue
+			}
+
+			storageClassName := ptr.Deref(pvc.Spec.StorageClassName, "")
+			if _, found := checked[storageClassName]; !found {
+				if err := CheckVolumeExpansion(ctx, canReadStorageClass, kclient, storageClassName); err != nil {
+					return false, fmt.Errorf("cannot expand persistent volume claim %s: %w", pvc.Name, err)
+				}
+				checked[storageClassName] = struct{}{}
+			}
+
+			if err := patchClaimStorage(ctx, kclient, &pvc, size); err != nil {
+				return false, fmt.Errorf("failed to expand persistent volume claim %s: %w", pvc.Name, err)
+			}
+		}
+
+		expand = true
+	}
+
+	return expand, nil
+}
+
+func patchClaimStorage(ctx context.Context, kclient kubernetes.Interface, pvc *corev1.PersistentVolumeClaim, size resource.Quantity) error {
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"resources": map[string]any{
+				"requests": map[string]any{
+					string(corev1.ResourceStorage): size.String(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = kclient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Patch(
+		ctx,
+		pvc.Name,
+		types.MergePatchType,
+		patch,
+		metav1.PatchOptions{FieldManager: k8s.PrometheusOperatorFieldManager},
+	)
+
+	return err
+}
+
+// listStatefulSetClaims returns the persistent volume claims created by the
+// statefulset from the given volume claim template.
+func listStatefulSetClaims(ctx context.Context, kclient kubernetes.Interface, sset *appsv1.StatefulSet, template string) ([]corev1.PersistentVolumeClaim, error) {
+	ls, err := metav1.LabelSelectorAsSelector(sset.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := kclient.CoreV1().PersistentVolumeClaims(sset.Namespace).List(ctx, metav1.ListOptions{LabelSelector: ls.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+
+	// The claims are named <template>-<statefulset>-<ordinal>.
+	prefix := fmt.Sprintf("%s-%s-", template, sset.Name)
+
+	pvcs := make([]corev1.PersistentVolumeClaim, 0, len(list.Items))
+	for _, pvc := range list.Items {
+		ordinal, found := strings.CutPrefix(pvc.Name, prefix)
+		if !found {
+			continue
+		}
+
+		if _, err := strconv.Atoi(ordinal); err != nil {
+			continue
+		}
+
+		pvcs = append(pvcs, pvc)
+	}
+
+	return pvcs, nil
+}
+
+// StorageResizeRequeueInterval is the delay after which an object is
+// reconciled again while its persistent volume claims are being resized. The
+// operator doesn't watch persistent volume claims.
+const StorageResizeRequeueInterval = time.Minute
+
+// UpdateStorageResizedCondition computes the StorageResized condition of the
+// object identified by key and records it in the reconciliation tracker. The
+// status updates read the recorded condition instead of listing the
+// persistent volume claims.
+//
+// It returns true when the resize of the persistent volume claims isn't
+// complete. In this case, the caller should reconcile the object again later.
+func UpdateStorageResizedCondition(ctx context.Context, kclient kubernetes.Interface, rt *ReconciliationTracker, key string, ssets []*appsv1.StatefulSet, generation int64) bool {
+	condition := StorageResizedCondition(ctx, kclient, ssets, generation)
+	rt.SetStorageResizedCondition(key, condition)
+
+	return condition != nil && condition.Status != monitoringv1.ConditionTrue
+}
+
+// StorageResizedCondition returns the StorageResized condition reporting
+// whether the bound persistent volume claims of the statefulsets have the
+// capacity requested by the volume claim templates.
+// It returns nil if none of the statefulsets has volume claim templates.
+func StorageResizedCondition(ctx context.Context, kclient kubernetes.Interface, ssets []*appsv1.StatefulSet, generation int64) *monitoringv1.Condition {
+	var (
+		found          bool
+		total, resized int
+	)
+
+	condition := &monitoringv1.Condition{
+		Type:   monitoringv1.StorageResized,
+		Status: monitoringv1.ConditionTrue,
+		LastTransitionTime: metav1.Time{
+			Time: time.Now().UTC(),
+		},
+		ObservedGeneration: generation,
+	}
+
+	for _, sset := range ssets {
+		if sset == nil {
+			continue
+		}
+
+		for _, tmpl := range sset.Spec.VolumeClaimTemplates {
+			size, ok := tmpl.Spec.Resources.Requests[corev1.ResourceStorage]
+			if !ok {
+				continue
+			}
+			found = true
+
+			pvcs, err := listStatefulSetClaims(ctx, kclient, sset, tmpl.Name)
+			if err != nil {
+				condition.Status = monitoringv1.ConditionUnknown
+				condition.Reason = "ListFailed"
+				condition.Message = err.Error()
+				return condition
+			}
+
+			for _, pvc := range pvcs {
+				if pvc.Status.Phase != corev1.ClaimBound {
+					continue
+				}
+
+				total++
+				capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+				if capacity.Cmp(size) >= 0 {
+					resized++
+				}
+			}
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	if resized < total {
+		condition.Status = monitoringv1.ConditionFalse
+		condition.Reason = "ResizeInProgress"
+		condition.Message = fmt.Sprintf("%d/%d persistent volume claims resized", resized, total)
+	}
+
+	return condition
+}
@@ -0,0 +1,266 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func newVolumeExpansionStatefulSet(size string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-test",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test"},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "data"},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse(size),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newVolumeExpansionClaim(name, request, capacity string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": "test"},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("standard"),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(request),
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: phase,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(capacity),
+			},
+		},
+	}
+}
+
+func TestExpandPersistentVolumeClaims(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		desiredSize          string
+		allowVolumeExpansion bool
+		canReadStorageClass  bool
+
+		expand   bool
+		err      bool
+		expSizes map[string]string
+	}{
+		{
+			name:                 "no change",
+			desiredSize:          "10Gi",
+			allowVolumeExpansion: true,
+			canReadStorageClass:  true,
+			expSizes: map[string]string{
+				"data-prometheus-test-0":  "10Gi",
+				"data-prometheus-test-1":  "10Gi",
+				"data-prometheus-test-2":  "10Gi",
+				"data-prometheus-other-0": "10Gi",
+			},
+		},
+		{
+			name:                 "size decrease",
+			desiredSize:          "5Gi",
+			allowVolumeExpansion: true,
+			canReadStorageClass:  true,
+			expSizes: map[string]string{
+				"data-prometheus-test-0":  "10Gi",
+				"data-prometheus-test-1":  "10Gi",
+				"data-prometheus-test-2":  "10Gi",
+				"data-prometheus-other-0": "10Gi",
+			},
+		},
+		{
+			name:                 "size increase",
+			desiredSize:          "20Gi",
+			allowVolumeExpansion: true,
+			canReadStorageClass:  true,
+			expand:               true,
+			expSizes: map[string]string{
+				"data-prometheus-test-0": "20Gi",
+				"data-prometheus-test-1": "20Gi",
+				// Pending claims aren't modified.
+				"data-prometheus-test-2": "10Gi",
+				// Claims from other statefulsets aren't modified.
+				"data-prometheus-other-0": "10Gi",
+			},
+		},
+		{
+			name:                 "expansion not allowed",
+			desiredSize:          "20Gi",
+			allowVolumeExpansion: false,
+			canReadStorageClass:  true,
+			err:                  true,
+		},
+		{
+			name:                 "storage class can't be read",
+			desiredSize:          "20Gi",
+			allowVolumeExpansion: false,
+			canReadStorageClass:  false,
+			expand:               true,
+			expSizes: map[string]string{
+				"data-prometheus-test-0":  "20Gi",
+				"data-prometheus-test-1":  "20Gi",
+				"data-prometheus-test-2":  "10Gi",
+				"data-prometheus-other-0": "10Gi",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kclient := fake.NewClientset(
+				&storagev1.StorageClass{
+					ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+					AllowVolumeExpansion: ptr.To(tc.allowVolumeExpansion),
+				},
+				newVolumeExpansionClaim("data-prometheus-test-0", "10Gi", "10Gi", corev1.ClaimBound),
+				newVolumeExpansionClaim("data-prometheus-test-1", "10Gi", "10Gi", corev1.ClaimBound),
+				newVolumeExpansionClaim("data-prometheus-test-2", "10Gi", "10Gi", corev1.ClaimPending),
+				newVolumeExpansionClaim("data-prometheus-other-0", "10Gi", "10Gi", corev1.ClaimBound),
+			)
+
+			expand, err := ExpandPersistentVolumeClaims(
+				context.Background(),
+				kclient,
+				tc.canReadStorageClass,
+				newVolumeExpansionStatefulSet("10Gi"),
+				newVolumeExpansionStatefulSet(tc.desiredSize),
+			)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expand, expand)
+
+			for name, size := range tc.expSizes {
+				pvc, err := kclient.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), name, metav1.GetOptions{})
+				require.NoError(t, err)
+
+				request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+				require.Equal(t, size, request.String(), name)
+			}
+		})
+	}
+}
+
+func TestStorageResizedCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		objects []runtime.Object
+		sset    *appsv1.StatefulSet
+
+		exp *monitoringv1.Condition
+	}{
+		{
+			name: "no volume claim template",
+			sset: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "prometheus-test", Namespace: "default"},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{},
+				},
+			},
+		},
+		{
+			name: "resized",
+			objects: []runtime.Object{
+				newVolumeExpansionClaim("data-prometheus-test-0", "20Gi", "20Gi", corev1.ClaimBound),
+				newVolumeExpansionClaim("data-prometheus-test-1", "20Gi", "25Gi", corev1.ClaimBound),
+			},
+			sset: newVolumeExpansionStatefulSet("20Gi"),
+			exp: &monitoringv1.Condition{
+				Type:   monitoringv1.StorageResized,
+				Status: monitoringv1.ConditionTrue,
+			},
+		},
+		{
+			name: "resize in progress",
+			objects: []runtime.Object{
+				newVolumeExpansionClaim("data-prometheus-test-0", "20Gi", "20Gi", corev1.ClaimBound),
+				newVolumeExpansionClaim("data-prometheus-test-1", "20Gi", "10Gi", corev1.ClaimBound),
+				newVolumeExpansionClaim("data-prometheus-test-2", "20Gi", "0", corev1.ClaimPending),
+			},
+			sset: newVolumeExpansionStatefulSet("20Gi"),
+			exp: &monitoringv1.Condition{
+				Type:    monitoringv1.StorageResized,
+				Status:  monitoringv1.ConditionFalse,
+				Reason:  "ResizeInProgress",
+				Message: "1/2 persistent volume claims resized",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				kclient = fake.NewClientset(tc.objects...)
+				rt      = &ReconciliationTracker{}
+			)
+
+			inProgress := UpdateStorageResizedCondition(context.Background(), kclient, rt, "default/test", []*appsv1.StatefulSet{tc.sset, nil}, 2)
+
+			c := rt.GetStorageResizedCondition("default/test")
+			if tc.exp == nil {
+				require.False(t, inProgress)
+				require.Nil(t, c)
+				return
+			}
+
+			require.Equal(t, tc.exp.Status != monitoringv1.ConditionTrue, inProgress)
+
+			require.NotNil(t, c)
+			require.Equal(t, tc.exp.Type, c.Type)
+			require.Equal(t, tc.exp.Status, c.Status)
+			require.Equal(t, tc.exp.Reason, c.Reason)
+			require.Equal(t, tc.exp.Message, c.Message)
+			require.Equal(t, int64(2), c.ObservedGeneration)
+
+			rt.ForgetObject("default/test")
+			require.Nil(t, rt.GetStorageResizedCondition("default/test"))
+		})
+	}
+}
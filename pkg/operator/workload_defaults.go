@@ -0,0 +1,277 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
+)
+
+// NewWorkloadDefaultsInformers returns the informers watching the
+// PrometheusOperatorDefaults object with the given name.
+func NewWorkloadDefaultsInformers(mclient monitoringclient.Interface, name string, resyncPeriod time.Duration) (*informers.ForResource, error) {
+	return informers.NewInformersForResource(
+		informers.NewMonitoringInformerFactories(
+			map[string]struct{}{corev1.NamespaceAll: {}},
+			map[string]struct{}{},
+			mclient,
+			resyncPeriod,
+			func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			},
+		),
+		monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.PrometheusOperatorDefaultsName),
+	)
+}
+
+// GetWorkloadDefaults returns the PrometheusOperatorDefaults object with the
+// given name from the informers' cache. It returns nil if the informers are
+// nil or if the object doesn't exist.
+func GetWorkloadDefaults(infs *informers.ForResource, name string) (*monitoringv1alpha1.PrometheusOperatorDefaults, error) {
+	if infs == nil {
+		return nil, nil
+	}
+
+	return GetObjectFromKey[*monitoringv1alpha1.PrometheusOperatorDefaults](infs, name)
+}
+
+// ApplyPrometheusDefaults sets the fields of the Prometheus spec which aren't
+// defined from the PrometheusOperatorDefaults object (which can be nil). It
+// returns the paths of the fields which have been set from the defaults.
+func ApplyPrometheusDefaults(p *monitoringv1.Prometheus, d *monitoringv1alpha1.PrometheusOperatorDefaults) []string {
+	var pd *monitoringv1alpha1.PrometheusDefaults
+	if d != nil {
+		pd = d.Spec.Prometheus
+	}
+
+	return applyCommonPrometheusDefaults(&p.Spec.CommonPrometheusFields, pd)
+}
+
+// ApplyPrometheusAgentDefaults sets the fields of the PrometheusAgent spec
+// which aren't defined from the PrometheusOperatorDefaults object (which can
+// be nil). It returns the paths of the fields which have been set from the
+// defaults.
+func ApplyPrometheusAgentDefaults(p *monitoringv1alpha1.PrometheusAgent, d *monitoringv1alpha1.PrometheusOperatorDefaults) []string {
+	var pd *monitoringv1alpha1.PrometheusDefaults
+	if d != nil {
+		pd = d.Spec.PrometheusAgent
+	}
+
+	return applyCommonPrometheusDefaults(&p.Spec.CommonPrometheusFields, pd)
+}
+
+// ApplyAlertmanagerDefaults sets the fields of the Alertmanager spec which
+// aren't defined from the PrometheusOperatorDefaults object (which can be
+// nil). It returns the paths of the fields which have been set from the
+// defaults.
+func ApplyAlertmanagerDefaults(a *monitoringv1.Alertmanager, d *monitoringv1alpha1.PrometheusOperatorDefaults) []string {
+	if d == nil {
+		return nil
+	}
+
+	return applyWorkloadDefaults(
+		workloadFields{
+			resources:         &a.Spec.Resources,
+			securityContext:   &a.Spec.SecurityContext,
+			tolerations:       &a.Spec.Tolerations,
+			priorityClassName: &a.Spec.PriorityClassName,
+		},
+		d.Spec.Alertmanager,
+	)
+}
+
+// ApplyThanosRulerDefaults sets the fields of the ThanosRuler spec which
+// aren't defined from the PrometheusOperatorDefaults object (which can be
+// nil). It returns the paths of the fields which have been set from the
+// defaults.
+func ApplyThanosRulerDefaults(tr *monitoringv1.ThanosRuler, d *monitoringv1alpha1.PrometheusOperatorDefaults) []string {
+	if d == nil {
+		return nil
+	}
+
+	return applyWorkloadDefaults(
+		workloadFields{
+			resources:         &tr.Spec.Resources,
+			securityContext:   &tr.Spec.SecurityContext,
+			tolerations:       &tr.Spec.Tolerations,
+			priorityClassName: &tr.Spec.PriorityClassName,
+		},
+		d.Spec.ThanosRuler,
+	)
+}
+
+func applyCommonPrometheusDefaults(cpf *monitoringv1.CommonPrometheusFields, d *monitoringv1alpha1.PrometheusDefaults) []string {
+	if d == nil {
+		return nil
+	}
+
+	applied := applyWorkloadDefaults(
+		workloadFields{
+			resources:         &cpf.Resources,
+			securityContext:   &cpf.SecurityContext,
+			tolerations:       &cpf.Tolerations,
+			priorityClassName: &cpf.PriorityClassName,
+		},
+		&d.WorkloadDefaults,
+	)
+
+	for _, l := range []struct {
+		name  string
+		value **int64
+		def   *int64
+	}{
+		{"enforcedSampleLimit", &cpf.EnforcedSampleLimit, d.EnforcedSampleLimit},
+		{"enforcedTargetLimit", &cpf.EnforcedTargetLimit, d.EnforcedTargetLimit},
+		{"enforcedLabelLimit", &cpf.EnforcedLabelLimit, d.EnforcedLabelLimit},
+		{"enforcedLabelNameLengthLimit", &cpf.EnforcedLabelNameLengthLimit, d.EnforcedLabelNameLengthLimit},
+		{"enforcedLabelValueLengthLimit", &cpf.EnforcedLabelValueLengthLimit, d.EnforcedLabelValueLengthLimit},
+		{"enforcedKeepDroppedTargets", &cpf.EnforcedKeepDroppedTargets, d.EnforcedKeepDroppedTargets},
+	} {
+		if *l.value == nil && l.def != nil {
+			*l.value = ptr.To(*l.def)
+			applied = append(applied, l.name)
+		}
+	}
+
+	if cpf.EnforcedBodySizeLimit == "" && d.EnforcedBodySizeLimit != nil {
+		cpf.EnforcedBodySizeLimit = *d.EnforcedBodySizeLimit
+		applied = append(applied, "enforcedBodySizeLimit")
+	}
+
+	return applied
+}
+
+// DefaultedPrometheusValues returns the serialized values of the given
+// Prometheus or PrometheusAgent fields (as returned by ApplyPrometheusDefaults
+// and ApplyPrometheusAgentDefaults). It returns an empty string if no field
+// is given.
+//
+// The controllers add the values to the StatefulSet input hash because the
+// values set from the defaults can change without the object's generation
+// being bumped.
+func DefaultedPrometheusValues(cpf *monitoringv1.CommonPrometheusFields, fields []string) (string, error) {
+	return marshalDefaultedValues(monitoringv1alpha1.PrometheusDefaults{
+		WorkloadDefaults: monitoringv1alpha1.WorkloadDefaults{
+			Resources:         &cpf.Resources,
+			SecurityContext:   cpf.SecurityContext,
+			Tolerations:       cpf.Tolerations,
+			PriorityClassName: &cpf.PriorityClassName,
+		},
+		EnforcedSampleLimit:           cpf.EnforcedSampleLimit,
+		EnforcedTargetLimit:           cpf.EnforcedTargetLimit,
+		EnforcedLabelLimit:            cpf.EnforcedLabelLimit,
+		EnforcedLabelNameLengthLimit:  cpf.EnforcedLabelNameLengthLimit,
+		EnforcedLabelValueLengthLimit: cpf.EnforcedLabelValueLengthLimit,
+		EnforcedKeepDroppedTargets:    cpf.EnforcedKeepDroppedTargets,
+		EnforcedBodySizeLimit:         &cpf.EnforcedBodySizeLimit,
+	}, fields)
+}
+
+// DefaultedWorkloadValues returns the serialized values of the given
+// Alertmanager or ThanosRuler fields (as returned by
+// ApplyAlertmanagerDefaults and ApplyThanosRulerDefaults). See
+// DefaultedPrometheusValues.
+func DefaultedWorkloadValues(resources corev1.ResourceRequirements, securityContext *corev1.PodSecurityContext, tolerations []corev1.Toleration, priorityClassName string, fields []string) (string, error) {
+	return marshalDefaultedValues(monitoringv1alpha1.WorkloadDefaults{
+		Resources:         &resources,
+		SecurityContext:   securityContext,
+		Tolerations:       tolerations,
+		PriorityClassName: &priorityClassName,
+	}, fields)
+}
+
+// marshalDefaultedValues serializes the given fields of v to JSON. The hash
+// of resource.Quantity values doesn't depend on the quantities hence the
+// values can't be hashed directly.
+func marshalDefaultedValues(v any, fields []string) (string, error) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the default values: %w", err)
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return "", fmt.Errorf("failed to unmarshal the default values: %w", err)
+	}
+
+	values := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		values[f] = all[f]
+	}
+
+	b, err = json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the default values: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// workloadFields points to the fields of a workload's spec which can be
+// defaulted by the WorkloadDefaults type.
+type workloadFields struct {
+	resources         *corev1.ResourceRequirements
+	securityContext   **corev1.PodSecurityContext
+	tolerations       *[]corev1.Toleration
+	priorityClassName *string
+}
+
+func applyWorkloadDefaults(f workloadFields, d *monitoringv1alpha1.WorkloadDefaults) []string {
+	if d == nil {
+		return nil
+	}
+
+	var applied []string
+
+	if d.Resources != nil && len(f.resources.Requests) == 0 && len(f.resources.Limits) == 0 && len(f.resources.Claims) == 0 {
+		*f.resources = *d.Resources.DeepCopy()
+		applied = append(applied, "resources")
+	}
+
+	if d.SecurityContext != nil && *f.securityContext == nil {
+		*f.securityContext = d.SecurityContext.DeepCopy()
+		applied = append(applied, "securityContext")
+	}
+
+	if len(d.Tolerations) > 0 && len(*f.tolerations) == 0 {
+		*f.tolerations = make([]corev1.Toleration, len(d.Tolerations))
+		for i := range d.Tolerations {
+			d.Tolerations[i].DeepCopyInto(&(*f.tolerations)[i])
+		}
+		applied = append(applied, "tolerations")
+	}
+
+	if d.PriorityClassName != nil && *f.priorityClassName == "" {
+		*f.priorityClassName = *d.PriorityClassName
+		applied = append(applied, "priorityClassName")
+	}
+
+	return applied
+}
@@ -0,0 +1,176 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+)
+
+func TestApplyPrometheusDefaults(t *testing.T) {
+	defaults := &monitoringv1alpha1.PrometheusOperatorDefaults{
+		Spec: monitoringv1alpha1.PrometheusOperatorDefaultsSpec{
+			Prometheus: &monitoringv1alpha1.PrometheusDefaults{
+				WorkloadDefaults: monitoringv1alpha1.WorkloadDefaults{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+					SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true)},
+					Tolerations: []corev1.Toleration{
+						{Key: "monitoring", Operator: corev1.TolerationOpExists},
+					},
+					PriorityClassName: ptr.To("monitoring"),
+				},
+				EnforcedSampleLimit:   ptr.To(int64(10000)),
+				EnforcedBodySizeLimit: ptr.To(monitoringv1.ByteSize("10MB")),
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		spec            monitoringv1.CommonPrometheusFields
+		defaults        *monitoringv1alpha1.PrometheusOperatorDefaults
+		expected        monitoringv1.CommonPrometheusFields
+		expectedApplied []string
+	}{
+		{
+			name: "no defaults",
+		},
+		{
+			name:     "no defaults for the kind",
+			defaults: &monitoringv1alpha1.PrometheusOperatorDefaults{},
+		},
+		{
+			name:     "empty spec",
+			defaults: defaults,
+			expected: monitoringv1.CommonPrometheusFields{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+				SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true)},
+				Tolerations: []corev1.Toleration{
+					{Key: "monitoring", Operator: corev1.TolerationOpExists},
+				},
+				PriorityClassName:     "monitoring",
+				EnforcedSampleLimit:   ptr.To(int64(10000)),
+				EnforcedBodySizeLimit: "10MB",
+			},
+			expectedApplied: []string{
+				"resources",
+				"securityContext",
+				"tolerations",
+				"priorityClassName",
+				"enforcedSampleLimit",
+				"enforcedBodySizeLimit",
+			},
+		},
+		{
+			name:     "explicit fields take precedence",
+			defaults: defaults,
+			spec: monitoringv1.CommonPrometheusFields{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+				SecurityContext:     &corev1.PodSecurityContext{},
+				PriorityClassName:   "critical",
+				ScrapeInterval:      "15s",
+				EnforcedSampleLimit: ptr.To(int64(0)),
+			},
+			expected: monitoringv1.CommonPrometheusFields{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+				SecurityContext: &corev1.PodSecurityContext{},
+				Tolerations: []corev1.Toleration{
+					{Key: "monitoring", Operator: corev1.TolerationOpExists},
+				},
+				PriorityClassName:     "critical",
+				ScrapeInterval:        "15s",
+				EnforcedSampleLimit:   ptr.To(int64(0)),
+				EnforcedBodySizeLimit: "10MB",
+			},
+			expectedApplied: []string{
+				"tolerations",
+				"enforcedBodySizeLimit",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{CommonPrometheusFields: tc.spec},
+			}
+
+			applied := ApplyPrometheusDefaults(p, tc.defaults)
+			require.Equal(t, tc.expected, p.Spec.CommonPrometheusFields)
+			require.Equal(t, tc.expectedApplied, applied)
+		})
+	}
+
+	// The defaults aren't modified.
+	require.Equal(t, []corev1.Toleration{{Key: "monitoring", Operator: corev1.TolerationOpExists}}, defaults.Spec.Prometheus.Tolerations)
+}
+
+func TestApplyWorkloadDefaults(t *testing.T) {
+	defaults := &monitoringv1alpha1.PrometheusOperatorDefaults{
+		Spec: monitoringv1alpha1.PrometheusOperatorDefaultsSpec{
+			Alertmanager: &monitoringv1alpha1.WorkloadDefaults{
+				PriorityClassName: ptr.To("monitoring"),
+			},
+			ThanosRuler: &monitoringv1alpha1.WorkloadDefaults{
+				PriorityClassName: ptr.To("rules"),
+			},
+		},
+	}
+
+	am := &monitoringv1.Alertmanager{}
+	require.Equal(t, []string{"priorityClassName"}, ApplyAlertmanagerDefaults(am, defaults))
+	require.Equal(t, "monitoring", am.Spec.PriorityClassName)
+
+	tr := &monitoringv1.ThanosRuler{}
+	require.Equal(t, []string{"priorityClassName"}, ApplyThanosRulerDefaults(tr, defaults))
+	require.Equal(t, "rules", tr.Spec.PriorityClassName)
+
+	// The PrometheusAgent defaults are independent from the Prometheus ones.
+	p := &monitoringv1alpha1.PrometheusAgent{}
+	require.Empty(t, ApplyPrometheusAgentDefaults(p, defaults))
+	require.Empty(t, p.Spec.PriorityClassName)
+}
+
+func TestDefaultedPrometheusValues(t *testing.T) {
+	cpf := &monitoringv1.CommonPrometheusFields{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		},
+		PriorityClassName:   "monitoring",
+		EnforcedSampleLimit: ptr.To(int64(10000)),
+	}
+
+	v, err := DefaultedPrometheusValues(cpf, nil)
+	require.NoError(t, err)
+	require.Empty(t, v)
+
+	// Only the given fields are serialized.
+	v, err = DefaultedPrometheusValues(cpf, []string{"resources", "enforcedSampleLimit"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"resources":{"requests":{"memory":"4Gi"}},"enforcedSampleLimit":10000}`, v)
+}
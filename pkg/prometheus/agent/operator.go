@@ -26,6 +26,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -39,11 +40,13 @@ import (
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"github.com/prometheus-operator/prometheus-operator/pkg/httpsd"
 	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
 	"github.com/prometheus-operator/prometheus-operator/pkg/listwatch"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
 	"github.com/prometheus-operator/prometheus-operator/pkg/webconfig"
 )
 
@@ -67,30 +70,48 @@ type Operator struct {
 	accessor *operator.Accessor
 
 	controllerID string
+	driftPolicy  operator.DriftPolicy
 
 	nsPromInf cache.SharedIndexInformer
 	nsMonInf  cache.SharedIndexInformer
 
-	promInfs  *informers.ForResource
-	smonInfs  *informers.ForResource
-	pmonInfs  *informers.ForResource
-	probeInfs *informers.ForResource
-	sconInfs  *informers.ForResource
-	cmapInfs  *informers.ForResource
-	secrInfs  *informers.ForResource
-	ssetInfs  *informers.ForResource
-	dsetInfs  *informers.ForResource
+	promInfs   *informers.ForResource
+	serverInfs *informers.ForResource
+	smonInfs   *informers.ForResource
+	pmonInfs   *informers.ForResource
+	probeInfs  *informers.ForResource
+	sconInfs   *informers.ForResource
+	quotaInfs  *informers.ForResource
+	cmapInfs   *informers.ForResource
+	secrInfs   *informers.ForResource
+	ssetInfs   *informers.ForResource
+	dsetInfs   *informers.ForResource
+
+	// defaultsInfs is nil when no PrometheusOperatorDefaults object is
+	// configured.
+	defaultsInfs     *informers.ForResource
+	workloadDefaults string
 
 	rr *operator.ResourceReconciler
 
-	metrics         *operator.Metrics
-	reconciliations *operator.ReconciliationTracker
+	metrics           *operator.Metrics
+	reconciliations   *operator.ReconciliationTracker
+	scrapeConfigCache *prompkg.ScrapeConfigCache
+	shardNamespaces   *prompkg.ShardNamespaces
+	shardRollouts     *prompkg.ShardRollouts
+	reloadChecker     prompkg.ConfigReloadChecker
+
+	httpSDRegistry *httpsd.Registry
+	httpSDURL      string
+
+	selectionReports *selectionreport.Registry
 
 	config prompkg.Config
 
-	endpointSliceSupported bool // Whether the Kubernetes API supports the EndpointSlice kind.
-	scrapeConfigSupported  bool
-	canReadStorageClass    bool
+	endpointSliceSupported   bool // Whether the Kubernetes API supports the EndpointSlice kind.
+	scrapeConfigSupported    bool
+	monitoringQuotaSupported bool
+	canReadStorageClass      bool
 
 	newEventRecorder operator.NewEventRecorderFunc
 
@@ -120,6 +141,23 @@ func WithScrapeConfig() ControllerOption {
 	}
 }
 
+// WithMonitoringQuota tells that the controller enforces the MonitoringQuota
+// objects.
+func WithMonitoringQuota() ControllerOption {
+	return func(o *Operator) {
+		o.monitoringQuotaSupported = true
+	}
+}
+
+// WithWorkloadDefaults tells that the controller merges the default values
+// from the PrometheusOperatorDefaults object with the given name into the
+// PrometheusAgent objects.
+func WithWorkloadDefaults(name string) ControllerOption {
+	return func(o *Operator) {
+		o.workloadDefaults = name
+	}
+}
+
 // WithStorageClassValidation tells that the controller should verify that the
 // Prometheus spec references a valid StorageClass name.
 func WithStorageClassValidation() ControllerOption {
@@ -144,25 +182,46 @@ func WithPodTopologyLabels() ControllerOption {
 	}
 }
 
-// New creates a new controller.
-func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
-	logger = logger.With("component", controllerName)
-
-	client, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating kubernetes client failed: %w", err)
+// WithHTTPServiceDiscovery tells that the operator serves the static targets
+// of Probe and ScrapeConfig objects from the HTTP service discovery endpoint
+// reachable at the given URL.
+func WithHTTPServiceDiscovery(registry *httpsd.Registry, url string) ControllerOption {
+	return func(o *Operator) {
+		o.httpSDRegistry = registry
+		o.httpSDURL = url
 	}
+}
 
-	mdClient, err := metadata.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating metadata client failed: %w", err)
+// WithSelectionReports tells that the operator publishes the selection
+// reports of the PrometheusAgent objects to the registry.
+func WithSelectionReports(registry *selectionreport.Registry) ControllerOption {
+	return func(o *Operator) {
+		o.selectionReports = registry
 	}
+}
 
-	mclient, err := monitoringclient.NewForConfig(restConfig)
+// New creates a new controller.
+func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
+	clients, err := operator.NewClients(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("instantiating monitoring client failed: %w", err)
+		return nil, err
 	}
 
+	return NewForClients(ctx, clients, c, logger, r, options...)
+}
+
+// NewForClients creates a new controller which interacts with the
+// Kubernetes API using the given clients.
+func NewForClients(ctx context.Context, clients *operator.Clients, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
+	logger = logger.With("component", controllerName)
+
+	var (
+		client   = clients.Kubernetes
+		mdClient = clients.Metadata
+		mclient  = clients.Monitoring
+		err      error
+	)
+
 	// All the metrics exposed by the controller get the controller="prometheus-agent" label.
 	r = prometheus.WrapRegistererWith(prometheus.Labels{"controller": "prometheus-agent"}, r)
 
@@ -182,7 +241,12 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		},
 		metrics:                      operator.NewMetrics(r),
 		reconciliations:              &operator.ReconciliationTracker{},
+		scrapeConfigCache:            prompkg.NewScrapeConfigCache(),
+		shardNamespaces:              prompkg.NewShardNamespaces(),
+		shardRollouts:                prompkg.NewShardRollouts(),
+		reloadChecker:                prompkg.NewConfigReloadChecker(),
 		controllerID:                 c.ControllerID,
+		driftPolicy:                  c.DriftPolicy,
 		newEventRecorder:             c.EventRecorderFactory(client, controllerName),
 		configResourcesStatusEnabled: c.Gates.Enabled(operator.StatusForConfigurationResourcesFeature),
 		topologyShardingEnabled:      c.Gates.Enabled(operator.PrometheusTopologyShardingFeature),
@@ -190,6 +254,7 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 	}
 	o.metrics.MustRegister(
 		o.reconciliations,
+		o.scrapeConfigCache,
 	)
 	for _, opt := range options {
 		opt(o)
@@ -215,6 +280,22 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		return nil, fmt.Errorf("error creating prometheus-agent informers: %w", err)
 	}
 
+	o.serverInfs, err = informers.NewInformersForResource(
+		informers.NewMonitoringInformerFactories(
+			c.Namespaces.PrometheusAllowList,
+			c.Namespaces.DenyList,
+			mclient,
+			resyncPeriod,
+			func(options *metav1.ListOptions) {
+				options.LabelSelector = c.PromSelector.String()
+			},
+		),
+		monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.PrometheusName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating prometheus informers: %w", err)
+	}
+
 	var promStores []cache.Store
 	for _, informer := range o.promInfs.GetInformers() {
 		promStores = append(promStores, informer.Informer().GetStore())
@@ -289,6 +370,29 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		}
 	}
 
+	if o.monitoringQuotaSupported {
+		o.quotaInfs, err = informers.NewInformersForResource(
+			informers.NewMonitoringInformerFactories(
+				c.Namespaces.AllowList,
+				c.Namespaces.DenyList,
+				mclient,
+				resyncPeriod,
+				nil,
+			),
+			monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.MonitoringQuotaName),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating monitoringquota informers: %w", err)
+		}
+	}
+
+	if o.workloadDefaults != "" {
+		o.defaultsInfs, err = operator.NewWorkloadDefaultsInformers(mclient, o.workloadDefaults, resyncPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("error creating prometheusoperatordefaults informers: %w", err)
+		}
+	}
+
 	allowList := c.Namespaces.PrometheusAllowList
 	if c.WatchObjectRefsInAllNamespaces {
 		allowList = operator.MergeAllowLists(
@@ -421,18 +525,23 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 	return o, nil
 }
 
-// Run the controller.
-func (c *Operator) Run(ctx context.Context) error {
-	go c.rr.Run(ctx)
-	defer c.rr.Stop()
-
+// StartInformers starts the informers of the controller and waits for
+// their caches to be synced.
+func (c *Operator) StartInformers(ctx context.Context) error {
 	go c.promInfs.Start(ctx.Done())
+	go c.serverInfs.Start(ctx.Done())
 	go c.smonInfs.Start(ctx.Done())
 	go c.pmonInfs.Start(ctx.Done())
 	go c.probeInfs.Start(ctx.Done())
 	if c.scrapeConfigSupported {
 		go c.sconInfs.Start(ctx.Done())
 	}
+	if c.monitoringQuotaSupported {
+		go c.quotaInfs.Start(ctx.Done())
+	}
+	if c.defaultsInfs != nil {
+		go c.defaultsInfs.Start(ctx.Done())
+	}
 	go c.cmapInfs.Start(ctx.Done())
 	go c.secrInfs.Start(ctx.Done())
 	go c.ssetInfs.Start(ctx.Done())
@@ -443,7 +552,16 @@ func (c *Operator) Run(ctx context.Context) error {
 	if c.nsPromInf != c.nsMonInf {
 		go c.nsPromInf.Run(ctx.Done())
 	}
-	if err := c.waitForCacheSync(ctx); err != nil {
+
+	return c.waitForCacheSync(ctx)
+}
+
+// Run the controller.
+func (c *Operator) Run(ctx context.Context) error {
+	go c.rr.Run(ctx)
+	defer c.rr.Stop()
+
+	if err := c.StartInformers(ctx); err != nil {
 		return err
 	}
 
@@ -483,10 +601,13 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 		informersForResource *informers.ForResource
 	}{
 		{"PrometheusAgent", c.promInfs},
+		{"Prometheus", c.serverInfs},
 		{"ServiceMonitor", c.smonInfs},
 		{"PodMonitor", c.pmonInfs},
 		{"Probe", c.probeInfs},
 		{"ScrapeConfig", c.sconInfs},
+		{"MonitoringQuota", c.quotaInfs},
+		{"PrometheusOperatorDefaults", c.defaultsInfs},
 		{"ConfigMap", c.cmapInfs},
 		{"Secret", c.secrInfs},
 		{"StatefulSet", c.ssetInfs},
@@ -524,6 +645,19 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 // addHandlers adds the eventhandlers to the informers.
 func (c *Operator) addHandlers() {
 	c.promInfs.AddEventHandler(c.rr)
+	c.serverInfs.AddEventHandler(operator.NewEventHandler(
+		c.logger,
+		c.accessor,
+		c.metrics,
+		monitoringv1.PrometheusesKind,
+		c.enqueueForPrometheusNamespace,
+		operator.WithFilter(
+			operator.AnyFilter(
+				operator.GenerationChanged,
+				operator.LabelsChanged,
+			),
+		),
+	))
 
 	c.ssetInfs.AddEventHandler(c.rr)
 
@@ -589,6 +723,28 @@ func (c *Operator) addHandlers() {
 		))
 	}
 
+	if c.quotaInfs != nil {
+		c.quotaInfs.AddEventHandler(operator.NewEventHandler(
+			c.logger,
+			c.accessor,
+			c.metrics,
+			monitoringv1alpha1.MonitoringQuotasKind,
+			c.enqueueForNamespaceFunc(c.nsMonInf.GetStore()),
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
+	if c.defaultsInfs != nil {
+		c.defaultsInfs.AddEventHandler(operator.NewEventHandler(
+			c.logger,
+			c.accessor,
+			c.metrics,
+			monitoringv1alpha1.PrometheusOperatorDefaultsKind,
+			c.enqueueAll,
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
 	hasRefFunc := operator.HasReferenceFunc(
 		c.promInfs,
 		c.reconciliations,
@@ -647,6 +803,11 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 
 	if p == nil {
 		c.reconciliations.ForgetObject(key)
+		c.scrapeConfigCache.Forget(key)
+		c.shardNamespaces.Delete(key)
+		c.shardRollouts.Delete(key)
+		c.forgetHTTPSDTargets(key)
+		c.forgetSelectionReport(key)
 		// Dependent resources are cleaned up by K8s via OwnerReferences
 		return nil
 	}
@@ -668,6 +829,11 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	// Check if the Agent instance is marked for deletion.
 	if c.rr.DeletionInProgress(p) {
 		c.reconciliations.ForgetObject(key)
+		c.scrapeConfigCache.Forget(key)
+		c.shardNamespaces.Delete(key)
+		c.shardRollouts.Delete(key)
+		c.forgetHTTPSDTargets(key)
+		c.forgetSelectionReport(key)
 		return nil
 	}
 
@@ -676,6 +842,34 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return nil
 	}
 
+	ctx = operator.WithDriftReporting(ctx, logger, c.metrics, c.newEventRecorder(p), c.driftPolicy)
+
+	if err := k8s.ValidateOverlays(p.Spec.Overlays); err != nil {
+		return err
+	}
+
+	defaults, err := operator.GetWorkloadDefaults(c.defaultsInfs, c.workloadDefaults)
+	if err != nil {
+		return err
+	}
+	defaulted := operator.ApplyPrometheusAgentDefaults(p, defaults)
+	if len(defaulted) > 0 {
+		logger.Debug("default values applied", "fields", defaulted)
+	}
+	defaultedValues, err := operator.DefaultedPrometheusValues(&p.Spec.CommonPrometheusFields, defaulted)
+	if err != nil {
+		return err
+	}
+
+	var reportDefaults *selectionreport.Defaults
+	if defaults != nil {
+		reportDefaults = &selectionreport.Defaults{
+			Name:       defaults.Name,
+			Generation: defaults.Generation,
+			Fields:     defaulted,
+		}
+	}
+
 	if ptr.Deref(p.Spec.Mode, "") == monitoringv1alpha1.DaemonSetPrometheusAgentMode && !c.daemonSetFeatureGateEnabled {
 		return fmt.Errorf("feature gate for Prometheus Agent's DaemonSet mode is not enabled")
 	}
@@ -683,7 +877,9 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	// Generate the configuration data.
 	var (
 		assetStore = assets.NewStoreBuilder(c.kclient.CoreV1(), c.kclient.CoreV1())
-		opts       = []prompkg.ConfigGeneratorOption{}
+		opts       = []prompkg.ConfigGeneratorOption{
+			prompkg.WithScrapeConfigCache(c.scrapeConfigCache),
+		}
 	)
 	if c.endpointSliceSupported {
 		opts = append(opts, prompkg.WithEndpointSliceSupport())
@@ -697,18 +893,26 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	if c.podTopologyLabelsSupported {
 		opts = append(opts, prompkg.WithPodTopologyLabelsSupport())
 	}
+	if c.httpSDRegistry != nil {
+		opts = append(opts, prompkg.WithOperatorHTTPSD(c.httpSDURL, c.httpSDRegistry))
+	}
+	if ptr.Deref(p.Spec.Mode, "") != monitoringv1alpha1.DaemonSetPrometheusAgentMode && p.Spec.ShardRolloutStrategy != nil {
+		opts = append(opts, prompkg.WithShardConfigurationRollout())
+	}
 
 	cg, err := prompkg.NewConfigGenerator(logger, p, opts...)
 	if err != nil {
 		return err
 	}
 
-	if err := c.createOrUpdateConfigurationSecret(ctx, logger, p, cg, assetStore); err != nil {
+	np := operator.NewNetworkPolicyBuilder(p.Namespace, makeSelectorLabels(p.Name))
+	configSecret, err := c.makeConfigurationSecret(ctx, logger, p, cg, assetStore, np, reportDefaults)
+	if err != nil {
 		return fmt.Errorf("creating config failed: %w", err)
 	}
 	c.reconciliations.UpdateReferenceTracker(key, assetStore.RefTracker())
 
-	tlsAssets, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), c.kclient, prompkg.NewTLSAssetSecret(p, c.config))
+	tlsAssets, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), c.kclient, prompkg.NewTLSAssetSecret(p, c.config), p.Spec.Overlays)
 	if err != nil {
 		return fmt.Errorf("failed to reconcile the TLS secrets: %w", err)
 	}
@@ -719,19 +923,34 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 
 	switch ptr.Deref(p.Spec.Mode, "") {
 	case monitoringv1alpha1.DaemonSetPrometheusAgentMode:
-		err = c.syncDaemonSet(ctx, key, p, cg, tlsAssets)
+		// The daemonset mode doesn't use persistent volume claims.
+		c.reconciliations.SetStorageResizedCondition(key, nil)
+
+		logger.Debug("updating Prometheus configuration secret")
+		if err := k8s.ApplyOverlays(p.Spec.Overlays, configSecret); err != nil {
+			return err
+		}
+
+		if err := k8s.CreateOrUpdateSecret(ctx, c.kclient.CoreV1().Secrets(p.Namespace), configSecret); err != nil {
+			return fmt.Errorf("creating config failed: %w", err)
+		}
+
+		err = c.syncDaemonSet(ctx, key, p, cg, tlsAssets, np)
 	default:
 		if err := operator.CheckStorageClass(ctx, c.canReadStorageClass, c.kclient, p.Spec.Storage); err != nil {
 			return err
 		}
 
-		err = c.syncStatefulSet(ctx, key, p, cg, tlsAssets)
+		err = c.syncStatefulSet(ctx, key, p, defaultedValues, cg, configSecret, tlsAssets, np)
+	}
+	if err != nil {
+		return err
 	}
 
-	return err
+	return c.reconcileNetworkPolicy(ctx, logger, p, np)
 }
 
-func (c *Operator) syncDaemonSet(ctx context.Context, key string, p *monitoringv1alpha1.PrometheusAgent, cg *prompkg.ConfigGenerator, tlsAssets *operator.ShardedSecret) error {
+func (c *Operator) syncDaemonSet(ctx context.Context, key string, p *monitoringv1alpha1.PrometheusAgent, cg *prompkg.ConfigGenerator, tlsAssets *operator.ShardedSecret, np *operator.NetworkPolicyBuilder) error {
 	logger := c.logger.With("key", key)
 
 	dsetClient := c.kclient.AppsV1().DaemonSets(p.Namespace)
@@ -752,6 +971,7 @@ func (c *Operator) syncDaemonSet(ctx context.Context, key string, p *monitoringv
 	if err != nil {
 		return fmt.Errorf("making daemonset failed: %w", err)
 	}
+	np.AddPodSpec(&dset.Spec.Template.Spec)
 
 	if notFound {
 		logger.Debug("creating daemonset")
@@ -789,7 +1009,7 @@ func (c *Operator) syncDaemonSet(ctx context.Context, key string, p *monitoringv
 	return nil
 }
 
-func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitoringv1alpha1.PrometheusAgent, cg *prompkg.ConfigGenerator, tlsAssets *operator.ShardedSecret) error {
+func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitoringv1alpha1.PrometheusAgent, defaultedValues string, cg *prompkg.ConfigGenerator, configSecret *corev1.Secret, tlsAssets *operator.ShardedSecret, np *operator.NetworkPolicyBuilder) error {
 	logger := c.logger.With("key", key)
 
 	if p.Spec.ServiceName != nil {
@@ -809,6 +1029,8 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 			c.config,
 		)
 
+		// The default governing service is shared by all the PrometheusAgent
+		// resources of the namespace, the overlays don't apply to it.
 		if _, err := k8s.CreateOrUpdateService(ctx, c.kclient.CoreV1().Services(p.Namespace), svc); err != nil {
 			return fmt.Errorf("synchronizing default governing service failed: %w", err)
 		}
@@ -816,6 +1038,30 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 
 	ssetClient := c.kclient.AppsV1().StatefulSets(p.Namespace)
 
+	var (
+		pdbs    []*policyv1.PodDisruptionBudget
+		desired []*appsv1.StatefulSet
+	)
+
+	rollout, err := prompkg.NewShardRollout(ctx, c.kclient, c.ssetInfs, c.reloadChecker, p, key)
+	if err != nil {
+		return fmt.Errorf("failed to compute the shard rollout: %w", err)
+	}
+
+	// When the configuration changes are rolled out shard by shard, the
+	// configuration files of the existing shards are updated only when the
+	// shard rollout allows it.
+	shardConfigs, err := prompkg.NewShardConfigurations(ctx, c.kclient, c.ssetInfs, cg, key, configSecret, nil)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("updating Prometheus configuration secret")
+	if err := shardConfigs.Write(ctx, c.kclient); err != nil {
+		return fmt.Errorf("creating config failed: %w", err)
+	}
+	var releasedConfigs bool
+
 	// Ensure we have a StatefulSet running Prometheus Agent deployed and that StatefulSet names are created correctly.
 	expected := prompkg.ExpectedStatefulSetShardNames(p)
 	for shard, ssetName := range expected {
@@ -835,6 +1081,11 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 		if obj != nil {
 			existingStatefulSet = obj.(*appsv1.StatefulSet)
 			if c.rr.DeletionInProgress(existingStatefulSet) {
+				if p.Spec.PodDisruptionBudget != nil {
+					pdbs = append(pdbs, operator.MakePodDisruptionBudget(*p.Spec.PodDisruptionBudget, existingStatefulSet))
+				}
+				np.AddPodSpec(&existingStatefulSet.Spec.Template.Spec)
+
 				// We want to avoid entering a hot-loop of update/delete cycles
 				// here since the sts was marked for deletion in foreground,
 				// which means it may take some time before the finalizers
@@ -846,7 +1097,7 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 			}
 		}
 
-		newSSetInputHash, err := createSSetInputHash(*p, c.config, tlsAssets, existingStatefulSet.Spec)
+		newSSetInputHash, err := createSSetInputHash(*p, defaultedValues, c.config, tlsAssets, existingStatefulSet.Spec)
 		if err != nil {
 			return err
 		}
@@ -864,6 +1115,21 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 		}
 		operator.SanitizeSTS(sset)
 
+		configDigest := shardConfigs.Digest(int32(shard))
+		if configDigest != "" {
+			sset.Annotations[prompkg.ConfigDigestAnnotationKey] = configDigest
+		}
+
+		if err := k8s.ApplyOverlays(p.Spec.Overlays, sset); err != nil {
+			return err
+		}
+		desired = append(desired, sset)
+
+		if p.Spec.PodDisruptionBudget != nil {
+			pdbs = append(pdbs, operator.MakePodDisruptionBudget(*p.Spec.PodDisruptionBudget, sset))
+		}
+		np.AddPodSpec(&sset.Spec.Template.Spec)
+
 		if notFound {
 			logger.Debug("creating statefulset")
 			if _, err := k8s.CreateStatefulSetOrPatchLabels(ctx, ssetClient, sset); err != nil {
@@ -872,7 +1138,8 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 			continue
 		}
 
-		if newSSetInputHash == existingStatefulSet.Annotations[operator.InputHashAnnotationKey] {
+		if newSSetInputHash == existingStatefulSet.Annotations[operator.InputHashAnnotationKey] &&
+			configDigest == existingStatefulSet.Annotations[prompkg.ConfigDigestAnnotationKey] {
 			logger.Debug("new statefulset generation inputs match current, skipping any actions")
 			continue
 		}
@@ -882,6 +1149,31 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 			"existing_hash", existingStatefulSet.Annotations[operator.InputHashAnnotationKey],
 		)
 
+		// The configuration and the statefulset of an existing shard are
+		// updated only when the shard rollout allows it.
+		if !rollout.Allow(sset, existingStatefulSet) {
+			logger.Debug("holding back the statefulset and configuration updates until the previous shards are rolled out")
+			continue
+		}
+
+		if shardConfigs.Release(int32(shard)) {
+			releasedConfigs = true
+		}
+
+		expand, err := operator.ExpandPersistentVolumeClaims(ctx, c.kclient, c.canReadStorageClass, existingStatefulSet, sset)
+		if err != nil {
+			return err
+		}
+
+		if expand {
+			c.metrics.StsDeleteCreateCounter().Inc()
+			logger.Info("recreating StatefulSet because the volume claim templates have been expanded")
+			if err := k8s.OrphanDeleteStatefulSet(ctx, ssetClient, sset.Name); err != nil {
+				return fmt.Errorf("failed to delete statefulset: %w", err)
+			}
+			continue
+		}
+
 		if err = k8s.ForceUpdateStatefulSet(ctx, ssetClient, sset, func(reason string) {
 			c.metrics.StsDeleteCreateCounter().Inc()
 			logger.Info("recreating StatefulSet because the update operation wasn't possible", "reason", reason)
@@ -890,13 +1182,45 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 		}
 	}
 
+	if err := operator.ReconcilePodDisruptionBudgets(
+		ctx,
+		c.kclient,
+		p,
+		labels.SelectorFromSet(labels.Set{prompkg.PrometheusNameLabelName: p.Name, prompkg.PrometheusModeLabelName: prometheusMode}),
+		pdbs,
+	); err != nil {
+		return err
+	}
+
+	if releasedConfigs {
+		logger.Debug("updating the configuration of the rolled out shards")
+		if err := shardConfigs.Write(ctx, c.kclient); err != nil {
+			return fmt.Errorf("creating config failed: %w", err)
+		}
+	}
+
+	c.shardRollouts.Set(key, rollout)
+	if rollout.InProgress() {
+		if stalled := rollout.Stalled(); len(stalled) > 0 {
+			logger.Warn("shard rollout paused because some statefulsets didn't become available within the progress deadline", "statefulsets", strings.Join(stalled, ","))
+		}
+		c.rr.EnqueueForReconciliationAfter(p, prompkg.ShardRolloutRequeueInterval)
+		c.rr.EnqueueForStatus(p)
+	}
+
+	if operator.UpdateStorageResizedCondition(ctx, c.kclient, c.reconciliations, key, desired, p.Generation) {
+		logger.Debug("persistent volume claims resize in progress")
+		c.rr.EnqueueForReconciliationAfter(p, operator.StorageResizeRequeueInterval)
+		c.rr.EnqueueForStatus(p)
+	}
+
 	ssets := map[string]struct{}{}
 	for _, ssetName := range expected {
 		ssets[ssetName] = struct{}{}
 	}
 
 	var deleteErrs []error
-	err := c.ssetInfs.ListAllByNamespace(p.Namespace, labels.SelectorFromSet(labels.Set{prompkg.PrometheusNameLabelName: p.Name, prompkg.PrometheusModeLabelName: prometheusMode}), func(obj any) {
+	err = c.ssetInfs.ListAllByNamespace(p.Namespace, labels.SelectorFromSet(labels.Set{prompkg.PrometheusNameLabelName: p.Name, prompkg.PrometheusModeLabelName: prometheusMode}), func(obj any) {
 		s := obj.(*appsv1.StatefulSet)
 
 		if _, ok := ssets[s.Name]; ok {
@@ -925,57 +1249,135 @@ func (c *Operator) syncStatefulSet(ctx context.Context, key string, p *monitorin
 	return nil
 }
 
-func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, logger *slog.Logger, p *monitoringv1alpha1.PrometheusAgent, cg *prompkg.ConfigGenerator, store *assets.StoreBuilder) error {
-	resourceSelector, err := prompkg.NewResourceSelector(logger, p, store, c.nsMonInf, c.metrics, c.newEventRecorder(p))
+// makeConfigurationSecret returns the configuration secret generated for the
+// PrometheusAgent object.
+func (c *Operator) makeConfigurationSecret(ctx context.Context, logger *slog.Logger, p *monitoringv1alpha1.PrometheusAgent, cg *prompkg.ConfigGenerator, store *assets.StoreBuilder, np *operator.NetworkPolicyBuilder, defaults *selectionreport.Defaults) (*corev1.Secret, error) {
+	quotas, err := operator.ListMonitoringQuotas(c.quotaInfs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var report *selectionreport.Report
+	if c.selectionReports != nil {
+		report = prompkg.NewSelectionReport(monitoringv1alpha1.PrometheusAgentsKind, p)
+		report.SetDefaults(defaults)
+	}
+
+	resourceSelector, err := prompkg.NewResourceSelector(
+		logger,
+		p,
+		store,
+		c.nsMonInf,
+		c.metrics,
+		c.newEventRecorder(p),
+		prompkg.WithMonitoringQuotas(quotas),
+		prompkg.WithSelectionReport(report),
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	smons, err := resourceSelector.SelectServiceMonitors(ctx, c.smonInfs.ListAllByNamespace)
 	if err != nil {
-		return fmt.Errorf("selecting ServiceMonitors failed: %w", err)
+		return nil, fmt.Errorf("selecting ServiceMonitors failed: %w", err)
 	}
 
 	pmons, err := resourceSelector.SelectPodMonitors(ctx, c.pmonInfs.ListAllByNamespace)
 	if err != nil {
-		return fmt.Errorf("selecting PodMonitors failed: %w", err)
+		return nil, fmt.Errorf("selecting PodMonitors failed: %w", err)
 	}
 
 	bmons, err := resourceSelector.SelectProbes(ctx, c.probeInfs.ListAllByNamespace)
 	if err != nil {
-		return fmt.Errorf("selecting Probes failed: %w", err)
+		return nil, fmt.Errorf("selecting Probes failed: %w", err)
 	}
 
 	var scrapeConfigs operator.TypedResourcesSelection[*monitoringv1alpha1.ScrapeConfig]
 	if c.sconInfs != nil {
 		scrapeConfigs, err = resourceSelector.SelectScrapeConfigs(ctx, c.sconInfs.ListAllByNamespace)
 		if err != nil {
-			return fmt.Errorf("selecting ScrapeConfigs failed: %w", err)
+			return nil, fmt.Errorf("selecting ScrapeConfigs failed: %w", err)
 		}
 	}
 
+	cg.AddScrapeResources(report, smons, pmons, bmons, scrapeConfigs)
+	c.selectionReports.Set(report)
+
 	if len(smons)+len(pmons)+len(bmons)+len(scrapeConfigs) == 0 {
 		c.reconciliations.SetReasonAndMessage(operator.KeyForObject(p), operator.NoSelectedResourcesReason, noSelectedResourcesMessage)
 	}
 
+	prompkg.AddNetworkPolicyEgress(
+		np,
+		p,
+		smons.ValidResources(),
+		pmons.ValidResources(),
+		bmons.ValidResources(),
+		scrapeConfigs.ValidResources(),
+	)
+
+	remoteWrite, err := prompkg.ResolveRemoteWriteReferences(c.serverInfs, p.GetNamespace(), p.Spec.RemoteWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	// The configuration is generated from the resolved references.
+	p = p.DeepCopy()
+	p.Spec.RemoteWrite = remoteWrite
+
 	if err := cg.AddRemoteWriteToStore(ctx, store, p.GetNamespace(), p.Spec.RemoteWrite); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := prompkg.AddAPIServerConfigToStore(ctx, store, p.GetNamespace(), p.Spec.APIServerConfig); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := prompkg.AddScrapeClassesToStore(ctx, store, p.GetNamespace(), p.Spec.ScrapeClasses); err != nil {
-		return fmt.Errorf("failed to process scrape classes: %w", err)
+		return nil, fmt.Errorf("failed to process scrape classes: %w", err)
 	}
 
+	if err := prompkg.AddOperatorServiceDiscoveryToStore(ctx, store, p.GetNamespace(), p.Spec.OperatorServiceDiscovery); err != nil {
+		return nil, fmt.Errorf("failed to process operator service discovery: %w", err)
+	}
+
+	// Publish the static targets before updating the configuration so that
+	// they are available as soon as Prometheus reloads.
+	c.updateHTTPSDTargets(p, cg, bmons.ValidResources(), scrapeConfigs.ValidResources())
+
 	sClient := c.kclient.CoreV1().Secrets(p.Namespace)
 	additionalScrapeConfigs, err := k8s.LoadSecretRef(ctx, logger, sClient, p.Spec.AdditionalScrapeConfigs)
 	if err != nil {
-		return fmt.Errorf("loading additional scrape configs from Secret failed: %w", err)
+		return nil, fmt.Errorf("loading additional scrape configs from Secret failed: %w", err)
+	}
+
+	promKey := fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+	if prompkg.PerShardConfiguration(p) {
+		// Each shard has its own configuration file.
+		confs, namespaces, err := cg.GenerateAgentConfigurationPerShard(
+			smons.ValidResources(),
+			pmons.ValidResources(),
+			bmons.ValidResources(),
+			scrapeConfigs.ValidResources(),
+			store,
+			additionalScrapeConfigs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("generating config failed: %w", err)
+		}
+
+		s, err := prompkg.MakeConfigurationSecretPerShard(cg, c.config, confs)
+		if err != nil {
+			return nil, fmt.Errorf("creating compressed secret failed: %w", err)
+		}
+
+		// The namespaces are reported in the status of the shards.
+		c.shardNamespaces.Set(promKey, namespaces)
+		return s, nil
 	}
 
+	c.shardNamespaces.Delete(promKey)
+
 	// Update secret based on the most recent configuration.
 	conf, err := cg.GenerateAgentConfiguration(
 		smons.ValidResources(),
@@ -986,20 +1388,55 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, logger
 		additionalScrapeConfigs,
 	)
 	if err != nil {
-		return fmt.Errorf("generating config failed: %w", err)
+		return nil, fmt.Errorf("generating config failed: %w", err)
 	}
 
 	// Compress config to avoid 1mb secret limit for a while
-	s, err := prompkg.MakeConfigurationSecret(p, c.config, conf)
+	s, err := prompkg.MakeShardsConfigurationSecret(cg, c.config, conf)
 	if err != nil {
-		return fmt.Errorf("creating compressed secret failed: %w", err)
+		return nil, fmt.Errorf("creating compressed secret failed: %w", err)
 	}
 
-	logger.Debug("updating Prometheus configuration secret")
-	return k8s.CreateOrUpdateSecret(ctx, sClient, s)
+	return s, nil
+}
+
+// updateHTTPSDTargets updates the target groups served to the
+// PrometheusAgent pods by the HTTP service discovery endpoint.
+func (c *Operator) updateHTTPSDTargets(p *monitoringv1alpha1.PrometheusAgent, cg *prompkg.ConfigGenerator, probes map[string]*monitoringv1.Probe, sCons map[string]*monitoringv1alpha1.ScrapeConfig) {
+	ownerKey := httpsd.OwnerKey(monitoringv1alpha1.PrometheusAgentsKind, p.Namespace, p.Name)
+
+	groups := cg.HTTPSDTargetGroups(probes, sCons)
+	if groups == nil {
+		c.httpSDRegistry.Delete(ownerKey)
+		return
+	}
+
+	c.httpSDRegistry.Set(ownerKey, prompkg.ShardsNumber(p), groups)
+}
+
+// forgetHTTPSDTargets removes the target groups of the PrometheusAgent object
+// identified by key from the HTTP service discovery endpoint.
+func (c *Operator) forgetHTTPSDTargets(key string) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	c.httpSDRegistry.Delete(httpsd.OwnerKey(monitoringv1alpha1.PrometheusAgentsKind, ns, name))
+}
+
+// forgetSelectionReport removes the selection report of the PrometheusAgent
+// object identified by key.
+func (c *Operator) forgetSelectionReport(key string) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	c.selectionReports.Delete(monitoringv1alpha1.PrometheusAgentsKind, ns, name)
 }
 
-func createSSetInputHash(p monitoringv1alpha1.PrometheusAgent, c prompkg.Config, tlsAssets *operator.ShardedSecret, ssSpec appsv1.StatefulSetSpec) (string, error) {
+func createSSetInputHash(p monitoringv1alpha1.PrometheusAgent, defaultedValues string, c prompkg.Config, tlsAssets *operator.ShardedSecret, ssSpec appsv1.StatefulSetSpec) (string, error) {
 	var http2 *bool
 	if p.Spec.Web != nil && p.Spec.Web.HTTPConfig != nil {
 		http2 = p.Spec.Web.HTTPConfig.HTTP2
@@ -1011,21 +1448,25 @@ func createSSetInputHash(p monitoringv1alpha1.PrometheusAgent, c prompkg.Config,
 	ssSpec.RevisionHistoryLimit = nil
 
 	hash, err := hashstructure.Hash(struct {
-		PrometheusLabels      map[string]string
-		PrometheusAnnotations map[string]string
-		PrometheusGeneration  int64
-		PrometheusWebHTTP2    *bool
-		Config                prompkg.Config
-		StatefulSetSpec       appsv1.StatefulSetSpec
-		ShardedSecret         *operator.ShardedSecret
+		PrometheusLabels          map[string]string
+		PrometheusAnnotations     map[string]string
+		PrometheusGeneration      int64
+		PrometheusWebHTTP2        *bool
+		PrometheusOverlays        []monitoringv1.Overlay
+		PrometheusDefaultedValues string
+		Config                    prompkg.Config
+		StatefulSetSpec           appsv1.StatefulSetSpec
+		ShardedSecret             *operator.ShardedSecret
 	}{
-		PrometheusLabels:      p.Labels,
-		PrometheusAnnotations: p.Annotations,
-		PrometheusGeneration:  p.Generation,
-		PrometheusWebHTTP2:    http2,
-		Config:                c,
-		StatefulSetSpec:       ssSpec,
-		ShardedSecret:         tlsAssets,
+		PrometheusLabels:          p.Labels,
+		PrometheusAnnotations:     p.Annotations,
+		PrometheusGeneration:      p.Generation,
+		PrometheusWebHTTP2:        http2,
+		PrometheusOverlays:        p.Spec.Overlays,
+		PrometheusDefaultedValues: defaultedValues,
+		Config:                    c,
+		StatefulSetSpec:           ssSpec,
+		ShardedSecret:             tlsAssets,
 	},
 		nil,
 	)
@@ -1057,6 +1498,9 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get prometheus agent status: %w", err)
 	}
+	c.shardNamespaces.UpdateStatus(key, pStatus)
+	c.shardRollouts.UpdateStatus(key, p.Status.Conditions, pStatus)
+
 	p.Status = *pStatus
 
 	selectorLabels := makeSelectorLabels(p.Name)
@@ -1101,13 +1545,49 @@ func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, p *monitor
 		operator.WithManagingOwner(p),
 	)
 
-	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, c.kclient.CoreV1().Secrets(p.Namespace), s); err != nil {
+	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, c.kclient.CoreV1().Secrets(p.Namespace), s, p.Spec.Overlays); err != nil {
 		return fmt.Errorf("failed to reconcile web config secret: %w", err)
 	}
 
 	return nil
 }
 
+// enqueueAll enqueues all the PrometheusAgent objects.
+func (c *Operator) enqueueAll(string) {
+	err := c.promInfs.ListAll(labels.Everything(), func(obj any) {
+		c.rr.EnqueueForReconciliation(obj.(*monitoringv1alpha1.PrometheusAgent))
+	})
+	if err != nil {
+		c.logger.Error(
+			"listing all PrometheusAgent instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+// enqueueForPrometheusNamespace enqueues the PrometheusAgent objects
+// referencing Prometheus objects in the given namespace for remote write.
+func (c *Operator) enqueueForPrometheusNamespace(nsName string) {
+	err := c.promInfs.ListAll(labels.Everything(), func(obj any) {
+		p := obj.(*monitoringv1alpha1.PrometheusAgent)
+
+		refs := make([]*monitoringv1.PrometheusReference, 0, len(p.Spec.RemoteWrite))
+		for _, rw := range p.Spec.RemoteWrite {
+			refs = append(refs, rw.PrometheusRef)
+		}
+
+		if prompkg.ReferencesNamespace(p.Namespace, nsName, refs...) {
+			c.rr.EnqueueForReconciliation(p)
+		}
+	})
+	if err != nil {
+		c.logger.Error(
+			"listing all PrometheusAgent instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
 func (c *Operator) enqueueForNamespaceFunc(gbk operator.GetByKeyer) func(string) {
 	return func(ns string) {
 		c.enqueueForNamespace(gbk, ns)
@@ -1259,6 +1739,33 @@ func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo any) {
 	}
 }
 
+// reconcileNetworkPolicy creates or updates the NetworkPolicy of the
+// Prometheus Agent pods when it is defined and deletes it otherwise.
+func (c *Operator) reconcileNetworkPolicy(ctx context.Context, logger *slog.Logger, p *monitoringv1alpha1.PrometheusAgent, np *operator.NetworkPolicyBuilder) error {
+	name := prompkg.NetworkPolicyName(p)
+	if p.Spec.NetworkPolicy == nil {
+		return operator.ReconcileNetworkPolicy(ctx, c.kclient, p.Namespace, name, nil)
+	}
+
+	return operator.ReconcileNetworkPolicy(
+		ctx,
+		c.kclient,
+		p.Namespace,
+		name,
+		np.Build(
+			ctx,
+			logger,
+			c.kclient,
+			*p.Spec.NetworkPolicy,
+			operator.WithName(name),
+			operator.WithNamespace(p.Namespace),
+			operator.WithLabels(c.config.Labels),
+			operator.WithAnnotations(c.config.Annotations),
+			operator.WithManagingOwner(p),
+		),
+	)
+}
+
 func makeSelectorLabels(name string) map[string]string {
 	return map[string]string{
 		operator.ApplicationNameLabelKey:     applicationNameLabelValue,
@@ -17,6 +17,7 @@ package prometheusagent
 import (
 	"fmt"
 	"maps"
+	"path"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -214,6 +215,9 @@ func makeStatefulSetSpec(
 	if topologyZone != "" {
 		reloaderOpts = append(reloaderOpts, operator.InzoneShard(new(cg.InzoneShardForShard(shard))))
 	}
+	if cg.PerShardConfigurationFiles() {
+		reloaderOpts = append(reloaderOpts, operator.ConfigFile(path.Join(prompkg.ConfDir, cg.ConfigFilenameForShard(shard))))
+	}
 	operatorInitContainers = append(operatorInitContainers,
 		prompkg.BuildConfigReloader(
 			p,
@@ -0,0 +1,228 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+// appendAnnotationDiscoveryConfigs appends the scrape configurations
+// discovering the targets from the prometheus.io/* annotations.
+func (cg *ConfigGenerator) appendAnnotationDiscoveryConfigs(
+	slices []yaml.MapSlice,
+	apiserverConfig *monitoringv1.APIServerConfig,
+	store *assets.StoreBuilder,
+	shards int32,
+) ([]yaml.MapSlice, error) {
+	ad := cg.prom.GetCommonPrometheusFields().AnnotationDiscovery
+	if ad == nil {
+		return slices, nil
+	}
+
+	if ad.ScrapeClassName != nil {
+		if _, found := cg.scrapeClasses[*ad.ScrapeClassName]; !found {
+			return nil, fmt.Errorf("annotationDiscovery: scrape class %q not found", *ad.ScrapeClassName)
+		}
+	}
+
+	roles := ad.Roles
+	if len(roles) == 0 {
+		roles = []monitoringv1.AnnotationDiscoveryRole{
+			monitoringv1.PodAnnotationDiscoveryRole,
+			monitoringv1.ServiceAnnotationDiscoveryRole,
+		}
+	}
+
+	for _, role := range roles {
+		switch role {
+		case monitoringv1.PodAnnotationDiscoveryRole:
+			slices = append(slices, cg.generateAnnotationDiscoveryConfig(ad, role, apiserverConfig, store, shards))
+
+		case monitoringv1.ServiceAnnotationDiscoveryRole:
+			if cg.daemonSet {
+				cg.logger.Warn("ignoring the Service role of annotationDiscovery not supported in DaemonSet mode")
+				continue
+			}
+
+			slices = append(slices, cg.generateAnnotationDiscoveryConfig(ad, role, apiserverConfig, store, shards))
+
+		default:
+			return nil, fmt.Errorf("annotationDiscovery: unsupported role %q", role)
+		}
+	}
+
+	return slices, nil
+}
+
+func (cg *ConfigGenerator) generateAnnotationDiscoveryConfig(
+	ad *monitoringv1.AnnotationDiscovery,
+	role monitoringv1.AnnotationDiscoveryRole,
+	apiserverConfig *monitoringv1.APIServerConfig,
+	store *assets.StoreBuilder,
+	shards int32,
+) yaml.MapSlice {
+	var (
+		cpf           = cg.prom.GetCommonPrometheusFields()
+		promNamespace = cg.prom.GetObjectMeta().GetNamespace()
+		scrapeClass   = cg.getScrapeClassOrDefault(ad.ScrapeClassName)
+		s             = store.ForNamespace(promNamespace)
+
+		jobName          = "annotationDiscovery/pod"
+		sdRole           = kubernetesSDRolePod
+		annotationPrefix = "__meta_kubernetes_pod_annotation_prometheus_io_"
+		// Minimum version supporting attach_metadata for the role.
+		attachMetaVersion = "2.35.0"
+	)
+
+	if role == monitoringv1.ServiceAnnotationDiscoveryRole {
+		jobName = "annotationDiscovery/service"
+		sdRole = cg.defaultEndpointRoleFlavor()
+		annotationPrefix = "__meta_kubernetes_service_annotation_prometheus_io_"
+		attachMetaVersion = "2.37.0"
+	}
+
+	cfg := yaml.MapSlice{
+		{
+			Key:   "job_name",
+			Value: jobName,
+		},
+	}
+
+	cfg = append(cfg, cg.generateK8SSDConfig(
+		ad.NamespaceSelector,
+		promNamespace,
+		apiserverConfig,
+		s,
+		sdRole,
+		cg.mergeAttachMetadataForTopology(mergeAttachMetadataWithScrapeClass(nil, scrapeClass, attachMetaVersion), attachMetaVersion),
+	))
+
+	scrapeInterval, scrapeTimeout := cg.mergeScrapeIntervalsWithScrapeClass("", "", scrapeClass)
+	if scrapeInterval != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: scrapeInterval})
+	}
+	if scrapeTimeout != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: scrapeTimeout})
+	}
+
+	cfg = cg.addTLStoYaml(cfg, s, mergeSafeTLSConfigWithScrapeClass(nil, scrapeClass))
+	cfg = cg.addAuthorizationToYaml(cfg, s, mergeSafeAuthorizationWithScrapeClass(nil, scrapeClass))
+
+	relabelings := initRelabelings()
+
+	if role == monitoringv1.PodAnnotationDiscoveryRole {
+		relabelings = append(relabelings, generateRunningFilter())
+	}
+
+	// Keep only the annotated objects and apply the annotation overrides.
+	relabelings = append(relabelings, []yaml.MapSlice{
+		{
+			{Key: "action", Value: "keep"},
+			{Key: "source_labels", Value: []string{annotationPrefix + "scrape"}},
+			{Key: "regex", Value: "true"},
+		},
+		{
+			{Key: "source_labels", Value: []string{annotationPrefix + "scheme"}},
+			{Key: "target_label", Value: "__scheme__"},
+			{Key: "regex", Value: "(https?)"},
+		},
+		{
+			{Key: "source_labels", Value: []string{annotationPrefix + "path"}},
+			{Key: "target_label", Value: "__metrics_path__"},
+			{Key: "regex", Value: "(.+)"},
+		},
+		// IPv6 addresses without port (e.g. pods without container ports)
+		// need to be enclosed in brackets.
+		{
+			{Key: "source_labels", Value: []string{"__address__", annotationPrefix + "port"}},
+			{Key: "target_label", Value: "__address__"},
+			{Key: "regex", Value: `([0-9A-Fa-f]*(?::[0-9A-Fa-f]*){2,});(\d+)`},
+			{Key: "replacement", Value: "[$1]:$2"},
+		},
+		// Replace the port of the address (if any) which can be a hostname,
+		// an IPv4 address or an IPv6 address enclosed in brackets.
+		{
+			{Key: "source_labels", Value: []string{"__address__", annotationPrefix + "port"}},
+			{Key: "target_label", Value: "__address__"},
+			{Key: "regex", Value: `(.+?)(?::\d+)?;(\d+)`},
+			{Key: "replacement", Value: "$1:$2"},
+		},
+		{
+			{Key: "source_labels", Value: []string{"__meta_kubernetes_namespace"}},
+			{Key: "target_label", Value: "namespace"},
+		},
+	}...)
+
+	if role == monitoringv1.ServiceAnnotationDiscoveryRole {
+		relabelings = append(relabelings, yaml.MapSlice{
+			{Key: "source_labels", Value: []string{"__meta_kubernetes_service_name"}},
+			{Key: "target_label", Value: "service"},
+		})
+	}
+
+	relabelings = append(relabelings, yaml.MapSlice{
+		{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_name"}},
+		{Key: "target_label", Value: "pod"},
+	})
+
+	// Add scrape class relabelings if there is any.
+	relabelings = append(relabelings, generateRelabelConfig(scrapeClass.Relabelings)...)
+
+	// The targets span several namespaces: the enforced namespace label is
+	// set from the namespace of the discovered object. Because of security
+	// risks, it is appended as the last relabeling.
+	if cpf.EnforcedNamespaceLabel != "" {
+		relabelings = append(relabelings, yaml.MapSlice{
+			{Key: "source_labels", Value: []string{"__meta_kubernetes_namespace"}},
+			{Key: "target_label", Value: cpf.EnforcedNamespaceLabel},
+		})
+	}
+
+	// DaemonSet mode doesn't support sharding.
+	if !cg.daemonSet {
+		// When the resources are assigned to shards, the targets are
+		// distributed by namespace.
+		if a := NewShardAssigner(cg.prom, cg.logger); a != nil {
+			relabelings = cg.appendNamespaceShardingRelabelings(relabelings, shards, a)
+		} else {
+			relabelings = cg.appendShardingRelabelingWithAddress(relabelings, shards)
+		}
+	}
+
+	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
+
+	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, mergeLimitWithScrapeClass(ad.SampleLimit, scrapeClass.SampleLimit), cpf.EnforcedSampleLimit)
+	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, mergeLimitWithScrapeClass(ad.TargetLimit, scrapeClass.TargetLimit), cpf.EnforcedTargetLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, scrapeClass.LabelLimit, cpf.EnforcedLabelLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, scrapeClass.LabelNameLengthLimit, cpf.EnforcedLabelNameLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, scrapeClass.LabelValueLengthLimit, cpf.EnforcedLabelValueLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, keepDroppedTargetsKey, nil, cpf.EnforcedKeepDroppedTargets)
+	cfg = cg.addFallbackScrapeProtocol(cfg, mergeFallbackScrapeProtocolWithScrapeClass(nil, scrapeClass))
+
+	if bodySizeLimit := getLowerByteSize(scrapeClass.BodySizeLimit, &cpf); !isByteSizeEmpty(bodySizeLimit) {
+		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", bodySizeLimit)
+	}
+
+	if len(scrapeClass.MetricRelabelings) > 0 {
+		cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: generateRelabelConfig(scrapeClass.MetricRelabelings)})
+	}
+
+	return cfg
+}
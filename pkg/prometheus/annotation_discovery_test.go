@@ -0,0 +1,206 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+	"gotest.tools/v3/golden"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+func TestAnnotationDiscovery(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		update    func(*monitoringv1.Prometheus)
+		opts      []ConfigGeneratorOption
+		golden    string
+		expectErr bool
+	}{
+		{
+			name: "default",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{}
+			},
+			golden: "AnnotationDiscovery.golden",
+		},
+		{
+			name: "pod role in all namespaces with endpointslice",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.ServiceDiscoveryRole = ptr.To(monitoringv1.EndpointSliceRole)
+				p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{
+					Roles:             []monitoringv1.AnnotationDiscoveryRole{monitoringv1.PodAnnotationDiscoveryRole},
+					NamespaceSelector: monitoringv1.NamespaceSelector{Any: true},
+				}
+			},
+			golden: "AnnotationDiscoveryPodRoleAllNamespaces.golden",
+		},
+		{
+			name: "enforced namespace label, limits and sharding",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.Shards = ptr.To(int32(2))
+				p.Spec.EnforcedNamespaceLabel = "tenant"
+				p.Spec.EnforcedSampleLimit = ptr.To(int64(1000))
+				p.Spec.EnforcedTargetLimit = ptr.To(int64(50))
+				p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{
+					Roles: []monitoringv1.AnnotationDiscoveryRole{monitoringv1.ServiceAnnotationDiscoveryRole},
+					NamespaceSelector: monitoringv1.NamespaceSelector{
+						MatchNames: []string{"team-a", "team-b"},
+					},
+					SampleLimit: ptr.To(int64(5000)),
+					TargetLimit: ptr.To(int64(10)),
+				}
+			},
+			golden: "AnnotationDiscoveryEnforcedLimits.golden",
+		},
+		{
+			name: "scrape class",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.ScrapeClasses = []monitoringv1.ScrapeClass{
+					{
+						Name:           "annotations",
+						ScrapeInterval: ptr.To(monitoringv1.Duration("1m")),
+						SampleLimit:    ptr.To(int64(100)),
+						Relabelings: []monitoringv1.RelabelConfig{
+							{
+								TargetLabel: "discovery",
+								Replacement: ptr.To("annotations"),
+							},
+						},
+						MetricRelabelings: []monitoringv1.RelabelConfig{
+							{
+								Action: "labeldrop",
+								Regex:  "noisy_.+",
+							},
+						},
+					},
+				}
+				p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{
+					Roles:           []monitoringv1.AnnotationDiscoveryRole{monitoringv1.PodAnnotationDiscoveryRole},
+					ScrapeClassName: ptr.To("annotations"),
+				}
+			},
+			golden: "AnnotationDiscoveryScrapeClass.golden",
+		},
+		{
+			name: "unknown scrape class",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{
+					ScrapeClassName: ptr.To("unknown"),
+				}
+			},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := defaultPrometheus()
+			tc.update(p)
+
+			cg := mustNewConfigGenerator(t, p, tc.opts...)
+			cfg, err := cg.GenerateServerConfiguration(
+				p,
+				nil,
+				nil,
+				nil,
+				nil,
+				&assets.StoreBuilder{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			golden.Assert(t, string(cfg), tc.golden)
+		})
+	}
+}
+
+func TestAnnotationDiscoveryDaemonSet(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{}
+
+	cg := mustNewConfigGenerator(t, p, WithDaemonSet())
+	cfg, err := cg.GenerateAgentConfiguration(
+		nil,
+		nil,
+		nil,
+		nil,
+		&assets.StoreBuilder{},
+		nil,
+	)
+	require.NoError(t, err)
+	golden.Assert(t, string(cfg), "AnnotationDiscoveryDaemonSet.golden")
+}
+
+func TestAnnotationDiscoveryAddress(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{
+		Roles: []monitoringv1.AnnotationDiscoveryRole{monitoringv1.PodAnnotationDiscoveryRole},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	b, err := cg.GenerateServerConfiguration(p, nil, nil, nil, nil, &assets.StoreBuilder{}, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	var cfg struct {
+		ScrapeConfigs []struct {
+			RelabelConfigs []*relabel.Config `yaml:"relabel_configs"`
+		} `yaml:"scrape_configs"`
+	}
+	// The shard variable is expanded by the config-reloader.
+	require.NoError(t, yaml.Unmarshal(bytes.ReplaceAll(b, []byte("$(SHARD)"), []byte("0")), &cfg))
+	require.Len(t, cfg.ScrapeConfigs, 1)
+	for _, rc := range cfg.ScrapeConfigs[0].RelabelConfigs {
+		rc.NameValidationScheme = model.UTF8Validation
+	}
+
+	for _, tc := range []struct {
+		address  string
+		expected string
+	}{
+		{address: "10.0.0.1", expected: "10.0.0.1:9100"},
+		{address: "10.0.0.1:8080", expected: "10.0.0.1:9100"},
+		{address: "pod.example.com:8080", expected: "pod.example.com:9100"},
+		{address: "[::1]:8080", expected: "[::1]:9100"},
+		{address: "[fd00:10:244::5]:8080", expected: "[fd00:10:244::5]:9100"},
+		{address: "fd00:10:244::5", expected: "[fd00:10:244::5]:9100"},
+	} {
+		t.Run(tc.address, func(t *testing.T) {
+			lb := labels.NewBuilder(labels.FromStrings(
+				"__address__", tc.address,
+				"__meta_kubernetes_namespace", "default",
+				"__meta_kubernetes_pod_phase", "Running",
+				"__meta_kubernetes_pod_annotation_prometheus_io_scrape", "true",
+				"__meta_kubernetes_pod_annotation_prometheus_io_port", "9100",
+			))
+			require.True(t, relabel.ProcessBuilder(lb, cfg.ScrapeConfigs[0].RelabelConfigs...))
+			require.Equal(t, tc.expected, lb.Get("__address__"))
+		})
+	}
+}
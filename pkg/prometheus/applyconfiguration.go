@@ -64,7 +64,8 @@ func prometheusStatusApplyConfigurationFromPrometheusStatus(status *monitoringv1
 				WithReplicas(shardStatus.Replicas).
 				WithUpdatedReplicas(shardStatus.UpdatedReplicas).
 				WithAvailableReplicas(shardStatus.AvailableReplicas).
-				WithUnavailableReplicas(shardStatus.UnavailableReplicas),
+				WithUnavailableReplicas(shardStatus.UnavailableReplicas).
+				WithNamespaces(shardStatus.Namespaces...),
 		)
 	}
 
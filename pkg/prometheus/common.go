@@ -21,7 +21,9 @@ import (
 	"path"
 	"path/filepath"
 
+	"github.com/prometheus/prometheus/promql/parser"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -43,6 +45,7 @@ const (
 	// It is here at the moment because promcfg uses it, and moving as is will cause import cycle error.
 	// nolint:godoclint
 	RulesDir               = "/etc/prometheus/rules"
+	ScrapeConfigFilesDir   = "/etc/prometheus/scrape_configs"
 	secretsDir             = "/etc/prometheus/secrets/"
 	configmapsDir          = "/etc/prometheus/configmaps/"
 	ConfigFilename         = "prometheus.yaml.gz"
@@ -52,6 +55,10 @@ const (
 	DefaultLogDirectory    = "/var/log/prometheus"
 	DefaultRetention       = "24h"
 
+	// ServerGoverningServiceName is the name of the governing service
+	// created for Prometheus (server mode) resources.
+	ServerGoverningServiceName = "prometheus-operated"
+
 	// DefaultTerminationGracePeriodSeconds defines how long Kubernetes should
 	// wait before killing Prometheus on pod termination.
 	// Prometheus may take a significant time to shut down due to data
@@ -84,6 +91,55 @@ func RetentionTimeOrDefault(retention monitoringv1.Duration, retentionSize monit
 	return retention
 }
 
+// RetentionSize returns the configured size-based retention. When
+// retentionSizePercentage is set, the value is computed from the storage size
+// and rounded down to the mebibyte.
+func RetentionSize(spec monitoringv1.PrometheusSpec) monitoringv1.ByteSize {
+	if spec.RetentionSizePercentage == nil {
+		return spec.RetentionSize
+	}
+
+	size := storageSize(spec.Storage)
+	if size == nil {
+		return ""
+	}
+
+	mib := size.Value() * int64(*spec.RetentionSizePercentage) / 100 / (1 << 20)
+	if mib <= 0 {
+		return ""
+	}
+
+	return monitoringv1.ByteSize(fmt.Sprintf("%dMB", mib))
+}
+
+// storageSize returns the size of the volume storing the Prometheus data or
+// nil if it isn't defined.
+func storageSize(storage *monitoringv1.StorageSpec) *resource.Quantity {
+	if storage == nil {
+		return nil
+	}
+
+	var requests corev1.ResourceList
+	switch {
+	case storage.EmptyDir != nil:
+		return storage.EmptyDir.SizeLimit
+	case storage.Ephemeral != nil:
+		if storage.Ephemeral.VolumeClaimTemplate == nil {
+			return nil
+		}
+		requests = storage.Ephemeral.VolumeClaimTemplate.Spec.Resources.Requests
+	default:
+		requests = storage.VolumeClaimTemplate.Spec.Resources.Requests
+	}
+
+	size, found := requests[corev1.ResourceStorage]
+	if !found {
+		return nil
+	}
+
+	return &size
+}
+
 // LabelSelectorForStatefulSets returns a label selector which selects
 // statefulsets deployed with the server or agent mode.
 func LabelSelectorForStatefulSets(mode string) string {
@@ -100,15 +156,15 @@ func ExpectedStatefulSetShardNames(
 	p monitoringv1.PrometheusInterface,
 ) []string {
 	res := []string{}
-	for i := int32(0); i < shardsNumber(p); i++ {
+	for i := int32(0); i < ShardsNumber(p); i++ {
 		res = append(res, prometheusNameByShard(p, i))
 	}
 
 	return res
 }
 
-// shardsNumber returns the normalized number of shards.
-func shardsNumber(
+// ShardsNumber returns the normalized number of shards.
+func ShardsNumber(
 	p monitoringv1.PrometheusInterface,
 ) int32 {
 	cpf := p.GetCommonPrometheusFields()
@@ -134,6 +190,26 @@ func ReplicasNumberPtr(
 	return &replicas
 }
 
+// ParserOptions returns the PromQL parser options matching the feature flags
+// enabled for Prometheus.
+func ParserOptions(p monitoringv1.PrometheusInterface) parser.Options {
+	var opts parser.Options
+	for _, f := range p.GetCommonPrometheusFields().EnableFeatures {
+		switch f {
+		case "promql-experimental-functions":
+			opts.EnableExperimentalFunctions = true
+		case "promql-duration-expr":
+			opts.ExperimentalDurationExpr = true
+		case "promql-extended-range-selectors":
+			opts.EnableExtendedRangeSelectors = true
+		case "promql-binop-fill-modifiers":
+			opts.EnableBinopFillModifiers = true
+		}
+	}
+
+	return opts
+}
+
 func prometheusNameByShard(p monitoringv1.PrometheusInterface, shard int32) string {
 	base := PrefixedName(p)
 	if shard == 0 {
@@ -157,10 +233,44 @@ func MakeConfigurationSecret(p monitoringv1.PrometheusInterface, config Config,
 		return nil, err
 	}
 
+	return makeConfigurationSecret(p, config, map[string][]byte{ConfigFilename: promConfig}), nil
+}
+
+// MakeConfigurationSecretPerShard returns the configuration secret holding
+// one configuration file per shard.
+func MakeConfigurationSecretPerShard(cg *ConfigGenerator, config Config, data [][]byte) (*corev1.Secret, error) {
+	files := make(map[string][]byte, len(data))
+	for shard, d := range data {
+		promConfig, err := compress(d)
+		if err != nil {
+			return nil, err
+		}
+
+		files[cg.ConfigFilenameForShard(int32(shard))] = promConfig
+	}
+
+	return makeConfigurationSecret(cg.prom, config, files), nil
+}
+
+// MakeShardsConfigurationSecret returns the configuration secret holding
+// the same configuration for all shards. The configuration is duplicated
+// when each shard reads its own configuration file.
+func MakeShardsConfigurationSecret(cg *ConfigGenerator, config Config, data []byte) (*corev1.Secret, error) {
+	if !cg.PerShardConfigurationFiles() {
+		return MakeConfigurationSecret(cg.prom, config, data)
+	}
+
+	confs := make([][]byte, ShardsNumber(cg.prom))
+	for i := range confs {
+		confs[i] = data
+	}
+
+	return MakeConfigurationSecretPerShard(cg, config, confs)
+}
+
+func makeConfigurationSecret(p monitoringv1.PrometheusInterface, config Config, files map[string][]byte) *corev1.Secret {
 	s := &corev1.Secret{
-		Data: map[string][]byte{
-			ConfigFilename: promConfig,
-		},
+		Data: files,
 	}
 
 	operator.UpdateObject(
@@ -171,7 +281,7 @@ func MakeConfigurationSecret(p monitoringv1.PrometheusInterface, config Config,
 		operator.WithName(ConfigSecretName(p)),
 	)
 
-	return s, nil
+	return s
 }
 
 func ConfigSecretName(p monitoringv1.PrometheusInterface) string {
@@ -182,6 +292,10 @@ func TLSAssetsSecretName(p monitoringv1.PrometheusInterface) string {
 	return fmt.Sprintf("%s-tls-assets", PrefixedName(p))
 }
 
+func ScrapeConfigFilesSecretName(p monitoringv1.PrometheusInterface, shard int32) string {
+	return fmt.Sprintf("%s-scrape-configs", prometheusNameByShard(p, shard))
+}
+
 func WebConfigSecretName(p monitoringv1.PrometheusInterface) string {
 	return fmt.Sprintf("%s-web-config", PrefixedName(p))
 }
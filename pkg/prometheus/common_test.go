@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/ptr"
@@ -453,3 +454,107 @@ func TestLabelSelectorForStatefulSets(t *testing.T) {
 		})
 	}
 }
+
+func TestRetentionSize(t *testing.T) {
+	pvc := func(size string) *monitoringv1.StorageSpec {
+		return &monitoringv1.StorageSpec{
+			VolumeClaimTemplate: monitoringv1.EmbeddedPersistentVolumeClaim{
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(size),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		spec monitoringv1.PrometheusSpec
+		exp  monitoringv1.ByteSize
+	}{
+		{
+			name: "no retention size",
+		},
+		{
+			name: "explicit retention size",
+			spec: monitoringv1.PrometheusSpec{
+				RetentionSize: "1GB",
+			},
+			exp: "1GB",
+		},
+		{
+			name: "percentage of the persistent volume claim",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Storage: pvc("10Gi"),
+				},
+				RetentionSizePercentage: ptr.To(int32(80)),
+			},
+			exp: "8192MB",
+		},
+		{
+			name: "percentage of the persistent volume claim in decimal unit",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Storage: pvc("10G"),
+				},
+				RetentionSizePercentage: ptr.To(int32(85)),
+			},
+			exp: "8106MB",
+		},
+		{
+			name: "percentage of the emptyDir size limit",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Storage: &monitoringv1.StorageSpec{
+						EmptyDir: &corev1.EmptyDirVolumeSource{
+							SizeLimit: ptr.To(resource.MustParse("2Gi")),
+						},
+					},
+				},
+				RetentionSizePercentage: ptr.To(int32(50)),
+			},
+			exp: "1024MB",
+		},
+		{
+			name: "percentage of the ephemeral volume claim",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Storage: &monitoringv1.StorageSpec{
+						Ephemeral: &corev1.EphemeralVolumeSource{
+							VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+								Spec: pvc("1Gi").VolumeClaimTemplate.Spec,
+							},
+						},
+					},
+				},
+				RetentionSizePercentage: ptr.To(int32(90)),
+			},
+			exp: "921MB",
+		},
+		{
+			name: "percentage without storage size",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Storage: &monitoringv1.StorageSpec{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				RetentionSizePercentage: ptr.To(int32(80)),
+			},
+		},
+		{
+			name: "percentage without storage",
+			spec: monitoringv1.PrometheusSpec{
+				RetentionSizePercentage: ptr.To(int32(80)),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, RetentionSize(tc.spec))
+		})
+	}
+}
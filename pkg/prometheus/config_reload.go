@@ -0,0 +1,145 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	configReloaderContainerName = "config-reloader"
+	configReloaderWebPortName   = "reloader-web"
+
+	lastReloadSuccessfulMetric   = "reloader_last_reload_successful"
+	lastReloadSuccessTimeMetric  = "reloader_last_reload_success_timestamp_seconds"
+	configReloadCheckHTTPTimeout = 5 * time.Second
+)
+
+// ConfigReloadChecker verifies the configuration reloads of the Prometheus
+// pods.
+type ConfigReloadChecker interface {
+	// ConfigReloadedSince returns true if the last configuration reload of
+	// the pod was successful and happened after t.
+	ConfigReloadedSince(ctx context.Context, pod *corev1.Pod, t time.Time) (bool, error)
+}
+
+// NewConfigReloadChecker returns a ConfigReloadChecker which reads the
+// metrics exposed by the config-reloader sidecar of the pods.
+//
+// When the config-reloader doesn't expose its web server outside of the pod
+// (e.g. with listenLocal), the reload is assumed to be successful.
+func NewConfigReloadChecker() ConfigReloadChecker {
+	return &reloaderMetricsChecker{
+		client: &http.Client{Timeout: configReloadCheckHTTPTimeout},
+	}
+}
+
+type reloaderMetricsChecker struct {
+	client *http.Client
+}
+
+func (c *reloaderMetricsChecker) ConfigReloadedSince(ctx context.Context, pod *corev1.Pod, t time.Time) (bool, error) {
+	port := configReloaderWebPort(pod)
+	if port == 0 {
+		return true, nil
+	}
+
+	if pod.Status.PodIP == "" {
+		return false, nil
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port)))),
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the config-reloader metrics of pod %s: %w", pod.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to get the config-reloader metrics of pod %s: unexpected status code %d", pod.Name, resp.StatusCode)
+	}
+
+	return configReloadedSince(resp.Body, t)
+}
+
+// configReloaderWebPort returns the port of the config-reloader's web
+// server or zero if it isn't exposed.
+func configReloaderWebPort(pod *corev1.Pod) int32 {
+	for _, c := range pod.Spec.Containers {
+		if c.Name != configReloaderContainerName {
+			continue
+		}
+
+		for _, p := range c.Ports {
+			if p.Name == configReloaderWebPortName {
+				return p.ContainerPort
+			}
+		}
+	}
+
+	return 0
+}
+
+// configReloadedSince parses the config-reloader metrics and returns true if
+// the last reload was successful and happened after t.
+func configReloadedSince(r io.Reader, t time.Time) (bool, error) {
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	mfs, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the config-reloader metrics: %w", err)
+	}
+
+	successful, found := gaugeValue(mfs[lastReloadSuccessfulMetric])
+	if !found || successful != 1 {
+		return false, nil
+	}
+
+	ts, found := gaugeValue(mfs[lastReloadSuccessTimeMetric])
+	if !found {
+		return false, nil
+	}
+
+	sec, frac := math.Modf(ts)
+	return !time.Unix(int64(sec), int64(frac*1e9)).Before(t), nil
+}
+
+func gaugeValue(mf *dto.MetricFamily) (float64, bool) {
+	if mf == nil || len(mf.GetMetric()) == 0 || mf.GetMetric()[0].GetGauge() == nil {
+		return 0, false
+	}
+
+	return mf.GetMetric()[0].GetGauge().GetValue(), true
+}
@@ -0,0 +1,84 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfigReloadedSince(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+
+	for _, tc := range []struct {
+		name    string
+		metrics string
+
+		expReloaded bool
+	}{
+		{
+			name: "reloaded after",
+			metrics: `# TYPE reloader_last_reload_successful gauge
+reloader_last_reload_successful 1
+# TYPE reloader_last_reload_success_timestamp_seconds gauge
+reloader_last_reload_success_timestamp_seconds 1.7000000105e+09
+`,
+			expReloaded: true,
+		},
+		{
+			name: "reloaded before",
+			metrics: `# TYPE reloader_last_reload_successful gauge
+reloader_last_reload_successful 1
+# TYPE reloader_last_reload_success_timestamp_seconds gauge
+reloader_last_reload_success_timestamp_seconds 1.69999999e+09
+`,
+		},
+		{
+			name: "last reload failed",
+			metrics: `# TYPE reloader_last_reload_successful gauge
+reloader_last_reload_successful 0
+# TYPE reloader_last_reload_success_timestamp_seconds gauge
+reloader_last_reload_success_timestamp_seconds 1.7000000105e+09
+`,
+		},
+		{
+			name:    "missing metrics",
+			metrics: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reloaded, err := configReloadedSince(strings.NewReader(tc.metrics), since)
+			require.NoError(t, err)
+			require.Equal(t, tc.expReloaded, reloaded)
+		})
+	}
+}
+
+func TestConfigReloadCheckerWithoutWebPort(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: configReloaderContainerName}},
+		},
+	}
+
+	reloaded, err := NewConfigReloadChecker().ConfigReloadedSince(context.Background(), pod, time.Now())
+	require.NoError(t, err)
+	require.True(t, reloaded)
+}
@@ -0,0 +1,177 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"maps"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/httpsd"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// WithOperatorHTTPSD tells the config generator that the operator serves
+// the HTTP service discovery endpoint at the given URL.
+//
+// When the Prometheus object enables the operator's service discovery, the
+// static targets of Probe and ScrapeConfig resources are discovered from
+// this endpoint instead of being written in the configuration. The requests
+// are authenticated with the bearer token issued by the registry for the
+// Prometheus object.
+func WithOperatorHTTPSD(url string, registry *httpsd.Registry) ConfigGeneratorOption {
+	return func(cg *ConfigGenerator) {
+		cg.operatorHTTPSDURL = url
+		cg.operatorHTTPSDRegistry = registry
+	}
+}
+
+func operatorHTTPSDOwnerKind(p monitoringv1.PrometheusInterface) string {
+	if _, ok := p.(*monitoringv1alpha1.PrometheusAgent); ok {
+		return monitoringv1alpha1.PrometheusAgentsKind
+	}
+
+	return monitoringv1.PrometheusesKind
+}
+
+// operatorHTTPSDEnabled returns true if the static targets should be
+// discovered from the operator's HTTP service discovery endpoint.
+func (cg *ConfigGenerator) operatorHTTPSDEnabled() bool {
+	if cg.operatorHTTPSDURL == "" || cg.prom == nil {
+		return false
+	}
+
+	if cg.prom.GetCommonPrometheusFields().OperatorServiceDiscovery == nil {
+		return false
+	}
+
+	if cgHTTPSD := cg.WithMinimumVersion("2.21.0"); !cgHTTPSD.IsCompatible() {
+		cgHTTPSD.Warn("operatorServiceDiscovery")
+		return false
+	}
+
+	return true
+}
+
+// operatorHTTPSDConfig returns the http_sd_config pointing to the operator's
+// endpoint for the given resource.
+//
+// The URL contains the $(SHARD) variable which is expanded by the
+// config-reloader.
+func (cg *ConfigGenerator) operatorHTTPSDConfig(s assets.StoreGetter, kind, namespace, name string) yaml.MapSlice {
+	var (
+		osd       = cg.prom.GetCommonPrometheusFields().OperatorServiceDiscovery
+		objMeta   = cg.prom.GetObjectMeta()
+		ownerKind = operatorHTTPSDOwnerKind(cg.prom)
+		token     = cg.operatorHTTPSDRegistry.Token(httpsd.OwnerKey(ownerKind, objMeta.GetNamespace(), objMeta.GetName()))
+	)
+
+	cfg := cg.addSafeTLStoYaml(yaml.MapSlice{}, s, osd.TLSConfig)
+
+	// The token restricts the access to the targets of this Prometheus object.
+	if cg.WithMinimumVersion("2.26.0").IsCompatible() {
+		cfg = append(cfg, yaml.MapItem{
+			Key: "authorization",
+			Value: yaml.MapSlice{
+				{Key: "type", Value: "Bearer"},
+				{Key: "credentials", Value: token},
+			},
+		})
+	} else {
+		cfg = append(cfg, yaml.MapItem{Key: "bearer_token", Value: token})
+	}
+
+	cfg = append(cfg, yaml.MapItem{
+		Key: "url",
+		Value: strings.TrimSuffix(cg.operatorHTTPSDURL, "/") + httpsd.Path(
+			ownerKind,
+			objMeta.GetNamespace(),
+			objMeta.GetName(),
+			"$("+operator.ShardEnvVar+")",
+			kind,
+			namespace,
+			name,
+		),
+	})
+
+	if osd.RefreshInterval != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "refresh_interval", Value: *osd.RefreshInterval})
+	}
+
+	return cfg
+}
+
+// HTTPSDTargetGroups returns the target groups served by the operator's HTTP
+// service discovery endpoint, indexed by resource key.
+//
+// It returns nil if the Prometheus object doesn't use the operator's service
+// discovery.
+func (cg *ConfigGenerator) HTTPSDTargetGroups(
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+) map[string][]httpsd.TargetGroup {
+	if !cg.operatorHTTPSDEnabled() {
+		return nil
+	}
+
+	groups := map[string][]httpsd.TargetGroup{}
+
+	for _, probe := range probes {
+		sc := probe.Spec.Targets.StaticConfig
+		if sc == nil {
+			continue
+		}
+
+		labels := make(map[string]string, len(sc.Labels)+1)
+		maps.Copy(labels, sc.Labels)
+		if _, found := labels["namespace"]; !found {
+			labels["namespace"] = probe.Namespace
+		}
+
+		groups[httpsd.ResourceKey(monitoringv1.ProbesKind, probe.Namespace, probe.Name)] = []httpsd.TargetGroup{
+			{
+				Targets: sc.Targets,
+				Labels:  labels,
+			},
+		}
+	}
+
+	for _, sCon := range sCons {
+		if len(sCon.Spec.StaticConfigs) == 0 {
+			continue
+		}
+
+		tgs := make([]httpsd.TargetGroup, 0, len(sCon.Spec.StaticConfigs))
+		for _, sc := range sCon.Spec.StaticConfigs {
+			targets := make([]string, 0, len(sc.Targets))
+			for _, t := range sc.Targets {
+				targets = append(targets, string(t))
+			}
+
+			tgs = append(tgs, httpsd.TargetGroup{
+				Targets: targets,
+				Labels:  sc.Labels,
+			})
+		}
+
+		groups[httpsd.ResourceKey(monitoringv1alpha1.ScrapeConfigsKind, sCon.Namespace, sCon.Name)] = tgs
+	}
+
+	return groups
+}
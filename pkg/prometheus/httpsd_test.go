@@ -0,0 +1,186 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gotest.tools/v3/golden"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/httpsd"
+)
+
+func newTestHTTPSDRegistry() *httpsd.Registry {
+	return httpsd.NewRegistry(slog.New(slog.DiscardHandler), []byte("0123456789abcdef0123456789abcdef"))
+}
+
+func TestOperatorServiceDiscovery(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		version    string
+		osd        *monitoringv1.OperatorServiceDiscovery
+		url        string
+		goldenFile string
+		enabled    bool
+	}{
+		{
+			name:       "operator service discovery",
+			osd:        &monitoringv1.OperatorServiceDiscovery{},
+			url:        "https://prometheus-operator.monitoring.svc:8443/",
+			goldenFile: "OperatorServiceDiscovery.golden",
+			enabled:    true,
+		},
+		{
+			name: "operator service discovery with TLS and refresh interval",
+			osd: &monitoringv1.OperatorServiceDiscovery{
+				RefreshInterval: ptr.To(monitoringv1.Duration("10s")),
+				TLSConfig: &monitoringv1.SafeTLSConfig{
+					CA: monitoringv1.SecretOrConfigMap{
+						Secret: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "httpsd-tls"},
+							Key:                  "ca.crt",
+						},
+					},
+					Cert: monitoringv1.SecretOrConfigMap{
+						Secret: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "httpsd-tls"},
+							Key:                  "tls.crt",
+						},
+					},
+					KeySecret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "httpsd-tls"},
+						Key:                  "tls.key",
+					},
+				},
+			},
+			url:        "https://prometheus-operator.monitoring.svc:8443",
+			goldenFile: "OperatorServiceDiscoveryWithTLS.golden",
+			enabled:    true,
+		},
+		{
+			name:       "bearer token before Prometheus v2.26.0",
+			version:    "v2.25.0",
+			osd:        &monitoringv1.OperatorServiceDiscovery{},
+			url:        "https://prometheus-operator.monitoring.svc:8443",
+			goldenFile: "OperatorServiceDiscoveryBearerToken.golden",
+			enabled:    true,
+		},
+		{
+			name:       "operator not serving the endpoint",
+			osd:        &monitoringv1.OperatorServiceDiscovery{},
+			goldenFile: "OperatorServiceDiscoveryDisabled.golden",
+		},
+		{
+			name:       "not enabled by the Prometheus resource",
+			url:        "https://prometheus-operator.monitoring.svc:8443",
+			goldenFile: "OperatorServiceDiscoveryDisabled.golden",
+		},
+		{
+			name:       "unsupported version",
+			version:    "v2.20.0",
+			osd:        &monitoringv1.OperatorServiceDiscovery{},
+			url:        "https://prometheus-operator.monitoring.svc:8443",
+			goldenFile: "OperatorServiceDiscoveryUnsupportedVersion.golden",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := defaultPrometheus()
+			p.Spec.Version = tc.version
+			p.Spec.OperatorServiceDiscovery = tc.osd
+
+			var opts []ConfigGeneratorOption
+			if tc.url != "" {
+				opts = append(opts, WithOperatorHTTPSD(tc.url, newTestHTTPSDRegistry()))
+			}
+
+			sc := defaultScrapeConfig()
+			sc.Spec.StaticConfigs = []monitoringv1alpha1.StaticConfig{
+				{
+					Targets: []monitoringv1alpha1.Target{"foo:9090", "bar:9090"},
+					Labels:  map[string]string{"env": "prod"},
+				},
+				{
+					Targets: []monitoringv1alpha1.Target{"baz:9090"},
+				},
+			}
+
+			var (
+				cg     = mustNewConfigGenerator(t, p, opts...)
+				probes = map[string]*monitoringv1.Probe{"default/defaultProbe": defaultProbe()}
+				sCons  = map[string]*monitoringv1alpha1.ScrapeConfig{"default/defaultScrapeConfig": sc}
+			)
+
+			groups := cg.HTTPSDTargetGroups(probes, sCons)
+			if !tc.enabled {
+				require.Nil(t, groups)
+			} else {
+				require.Equal(t, map[string][]httpsd.TargetGroup{
+					"probe/default/defaultProbe": {
+						{
+							Targets: []string{"prometheus.io", "promcon.io"},
+							Labels:  map[string]string{"namespace": "custom", "static": "label"},
+						},
+					},
+					"scrapeconfig/default/defaultScrapeConfig": {
+						{
+							Targets: []string{"foo:9090", "bar:9090"},
+							Labels:  map[string]string{"env": "prod"},
+						},
+						{
+							Targets: []string{"baz:9090"},
+						},
+					},
+				}, groups)
+			}
+
+			cfg, err := cg.GenerateServerConfiguration(
+				p,
+				nil,
+				nil,
+				probes,
+				sCons,
+				assets.NewTestStoreBuilder(),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			require.NoError(t, err)
+			golden.Assert(t, string(cfg), tc.goldenFile)
+		})
+	}
+}
+
+func TestOperatorServiceDiscoveryProbeNamespaceLabel(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.OperatorServiceDiscovery = &monitoringv1.OperatorServiceDiscovery{}
+
+	probe := defaultProbe()
+	probe.Spec.Targets.StaticConfig.Labels = nil
+
+	cg := mustNewConfigGenerator(t, p, WithOperatorHTTPSD("http://prometheus-operator:8081", newTestHTTPSDRegistry()))
+	groups := cg.HTTPSDTargetGroups(map[string]*monitoringv1.Probe{"default/defaultProbe": probe}, nil)
+
+	require.Equal(t, map[string]string{"namespace": "default"}, groups["probe/default/defaultProbe"][0].Labels)
+	// The Probe object isn't modified.
+	require.Nil(t, probe.Spec.Targets.StaticConfig.Labels)
+}
@@ -0,0 +1,102 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// AddNetworkPolicyEgress allows the Prometheus pods to reach the Kubernetes
+// API server, the namespaces of the selected scrape resources and the
+// remote-write endpoints.
+//
+// The targets running in the host network (e.g. kubelet or node-exporter)
+// aren't matched by the namespace rules and need to be allowed with
+// additionalEgress.
+func AddNetworkPolicyEgress(
+	b *operator.NetworkPolicyBuilder,
+	p monitoringv1.PrometheusInterface,
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+) {
+	cpf := p.GetCommonPrometheusFields()
+
+	// The Kubernetes service discovery watches the API server.
+	b.AddKubernetesAPI()
+
+	addNamespaceSelector := func(namespace string, nsel monitoringv1.NamespaceSelector) {
+		switch {
+		case cpf.IgnoreNamespaceSelectors || (!nsel.Any && len(nsel.MatchNames) == 0):
+			b.AddNamespaces(namespace)
+		case nsel.Any:
+			b.AddAllNamespaces()
+		default:
+			b.AddNamespaces(nsel.MatchNames...)
+		}
+	}
+
+	for _, sMon := range sMons {
+		addNamespaceSelector(sMon.Namespace, sMon.Spec.NamespaceSelector)
+	}
+
+	for _, pMon := range pMons {
+		addNamespaceSelector(pMon.Namespace, pMon.Spec.NamespaceSelector)
+	}
+
+	for _, probe := range probes {
+		b.AddAddresses(probe.Spec.ProberSpec.URL)
+	}
+
+	for _, sCon := range sCons {
+		for _, sc := range sCon.Spec.StaticConfigs {
+			for _, target := range sc.Targets {
+				b.AddAddresses(string(target))
+			}
+		}
+
+		for _, sd := range sCon.Spec.KubernetesSDConfigs {
+			if sd.Namespaces == nil || (len(sd.Namespaces.Names) == 0 && !ptr.Deref(sd.Namespaces.IncludeOwnNamespace, false)) {
+				b.AddAllNamespaces()
+				continue
+			}
+
+			b.AddNamespaces(sd.Namespaces.Names...)
+			if ptr.Deref(sd.Namespaces.IncludeOwnNamespace, false) {
+				b.AddNamespaces(p.GetObjectMeta().GetNamespace())
+			}
+		}
+	}
+
+	for _, rw := range cpf.RemoteWrite {
+		if rw.PrometheusRef != nil {
+			b.AddNamespaces(ptr.Deref(rw.PrometheusRef.Namespace, p.GetObjectMeta().GetNamespace()))
+			continue
+		}
+
+		b.AddAddresses(string(rw.URL))
+	}
+}
+
+// NetworkPolicyName returns the name of the NetworkPolicy generated for the
+// Prometheus pods.
+func NetworkPolicyName(p monitoringv1.PrometheusInterface) string {
+	return PrefixedName(p)
+}
@@ -59,8 +59,11 @@ type StatefulSetGetter interface {
 // ReconciledConditionGetter returns the Reconciled condition for the
 // workload resource identified by <namespace>/<name>. The second argument is
 // the observed generation.
+// It also returns the StorageResized condition computed during the last
+// reconciliation (if any).
 type ReconciledConditionGetter interface {
 	GetCondition(string, int64) monitoringv1.Condition
+	GetStorageResizedCondition(string) *monitoringv1.Condition
 }
 
 // DeletionChecker returns true if the given object is being deleted.
@@ -118,6 +121,25 @@ func NewTLSAssetSecret(p monitoringv1.PrometheusInterface, config Config) *corev
 	return s
 }
 
+// NewScrapeConfigFilesSecret returns the template of the Secret(s) holding the
+// per-resource scrape configuration files of the given shard.
+func NewScrapeConfigFilesSecret(p monitoringv1.PrometheusInterface, config Config, shard int32) *corev1.Secret {
+	s := &corev1.Secret{
+		Data: map[string][]byte{},
+	}
+
+	operator.UpdateObject(
+		s,
+		operator.WithLabels(config.Labels),
+		operator.WithAnnotations(config.Annotations),
+		operator.WithManagingOwner(p),
+		operator.WithName(ScrapeConfigFilesSecretName(p, shard)),
+		operator.WithNamespace(p.GetObjectMeta().GetNamespace()),
+	)
+
+	return s
+}
+
 // validateRemoteWriteSpec checks that mutually exclusive configurations are not
 // included in the Prometheus remoteWrite configuration section, while also validating
 // the RemoteWriteSpec child fields.
@@ -131,6 +153,7 @@ func (cg *ConfigGenerator) validateRemoteWriteSpec(spec monitoringv1.RemoteWrite
 		"authorization": spec.Authorization,
 		"sigv4":         spec.Sigv4,
 		"azureAd":       spec.AzureAD,
+		"googleIAM":     spec.GoogleIAM,
 	} {
 		if reflect.ValueOf(v).IsNil() {
 			continue
@@ -342,9 +365,8 @@ func (sr *StatusReporter) Process(ctx context.Context, logger *slog.Logger, p mo
 		}
 	}
 
-	pStatus.Conditions = operator.UpdateConditions(
-		p.GetStatus().Conditions,
-		monitoringv1.Condition{
+	conditions := []monitoringv1.Condition{
+		{
 			Type:    monitoringv1.Available,
 			Status:  combinedStatus(statuses),
 			Reason:  combinedReason(reasons),
@@ -355,7 +377,12 @@ func (sr *StatusReporter) Process(ctx context.Context, logger *slog.Logger, p mo
 			ObservedGeneration: p.GetObjectMeta().GetGeneration(),
 		},
 		sr.rcg.GetCondition(key, p.GetObjectMeta().GetGeneration()),
-	)
+	}
+	if cond := sr.rcg.GetStorageResizedCondition(key); cond != nil {
+		conditions = append(conditions, *cond)
+	}
+
+	pStatus.Conditions = operator.UpdateConditions(p.GetStatus().Conditions, conditions...)
 
 	return &pStatus, nil
 }
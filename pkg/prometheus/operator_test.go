@@ -80,6 +80,30 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 				Sigv4: &monitoringv1.Sigv4{},
 			},
 		},
+		{
+			name: "with_GoogleIAM",
+			spec: monitoringv1.RemoteWriteSpec{
+				GoogleIAM: &monitoringv1.GoogleIAM{},
+			},
+		},
+		{
+			name: "with_GoogleIAM_and_SigV4",
+			spec: monitoringv1.RemoteWriteSpec{
+				GoogleIAM: &monitoringv1.GoogleIAM{},
+				Sigv4:     &monitoringv1.Sigv4{},
+			},
+			expectErr: true,
+		},
+		{
+			name: "with_GoogleIAM_and_AzureAD",
+			spec: monitoringv1.RemoteWriteSpec{
+				GoogleIAM: &monitoringv1.GoogleIAM{},
+				AzureAD: &monitoringv1.AzureAD{
+					ManagedIdentity: &monitoringv1.ManagedIdentity{},
+				},
+			},
+			expectErr: true,
+		},
 		{
 			name: "with_OAuth2_and_SigV4",
 			spec: monitoringv1.RemoteWriteSpec{
@@ -440,6 +464,10 @@ func (rcg *fakeReconciledConditionGetter) GetCondition(_ string, n int64) monito
 	}
 }
 
+func (rcg *fakeReconciledConditionGetter) GetStorageResizedCondition(_ string) *monitoringv1.Condition {
+	return nil
+}
+
 type fakeDeletionChecker struct{}
 
 func (dc *fakeDeletionChecker) DeletionInProgress(_ metav1.Object) bool { return false }
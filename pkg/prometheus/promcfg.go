@@ -37,12 +37,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 
 	sortutil "github.com/prometheus-operator/prometheus-operator/internal/sortutil"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/httpsd"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespacelabeler"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus/validation"
@@ -94,6 +96,22 @@ type ConfigGenerator struct {
 	inlineTLSConfig             bool
 
 	bypassVersionCheck bool
+
+	scrapeConfigCache *ScrapeConfigCache
+	// specHash is the digest of the generator's inputs used to validate the
+	// scrape config cache entries.
+	specHash string
+
+	// operatorHTTPSDURL is the base URL of the HTTP service discovery
+	// endpoint served by the operator.
+	operatorHTTPSDURL string
+	// operatorHTTPSDRegistry issues the bearer tokens authenticating the
+	// requests to the HTTP service discovery endpoint.
+	operatorHTTPSDRegistry *httpsd.Registry
+
+	// shardConfigurationRollout is true when the configuration changes are
+	// rolled out shard by shard.
+	shardConfigurationRollout bool
 }
 
 type ConfigGeneratorOption func(*ConfigGenerator)
@@ -139,6 +157,15 @@ func WithInlineTLSConfig() ConfigGeneratorOption {
 	}
 }
 
+// WithShardConfigurationRollout tells the config generator that the
+// configuration changes are rolled out shard by shard. In this case, each
+// shard has its own configuration file.
+func WithShardConfigurationRollout() ConfigGeneratorOption {
+	return func(cg *ConfigGenerator) {
+		cg.shardConfigurationRollout = true
+	}
+}
+
 // WithoutVersionCheck returns a [ConfigGenerator] which doesn't perform any
 // version check.
 func WithoutVersionCheck() ConfigGeneratorOption {
@@ -194,6 +221,13 @@ func NewConfigGenerator(
 		opt(cg)
 	}
 
+	if cg.scrapeConfigCache != nil {
+		cg.specHash, err = cg.scrapeConfigCacheSpecHash()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the scrape config cache hash: %w", err)
+		}
+	}
+
 	return cg, nil
 }
 
@@ -250,6 +284,12 @@ func getScrapeClassConfig(p monitoringv1.PrometheusInterface) (map[string]monito
 			return nil, "", fmt.Errorf("invalid authorization for scrapeClass %s: %w", scrapeClass.Name, err)
 		}
 
+		if scrapeClass.ScrapeInterval != nil && scrapeClass.ScrapeTimeout != nil {
+			if err := CompareScrapeTimeoutToScrapeInterval(*scrapeClass.ScrapeTimeout, *scrapeClass.ScrapeInterval); err != nil {
+				return nil, "", fmt.Errorf("invalid scrape timeout for scrapeClass %s: %w", scrapeClass.Name, err)
+			}
+		}
+
 		if ptr.Deref(scrapeClass.Default, false) {
 			if defaultScrapeClass != "" {
 				return nil, "", fmt.Errorf("multiple default scrape classes defined")
@@ -287,6 +327,10 @@ func (cg *ConfigGenerator) WithKeyVals(keyvals ...any) *ConfigGenerator {
 		podTopologyLabelsSupported:  cg.podTopologyLabelsSupported,
 		inlineTLSConfig:             cg.inlineTLSConfig,
 		bypassVersionCheck:          cg.bypassVersionCheck,
+		scrapeConfigCache:           cg.scrapeConfigCache,
+		specHash:                    cg.specHash,
+		operatorHTTPSDURL:           cg.operatorHTTPSDURL,
+		operatorHTTPSDRegistry:      cg.operatorHTTPSDRegistry,
 	}
 }
 
@@ -314,6 +358,11 @@ func (cg *ConfigGenerator) WithMinimumVersion(version string) *ConfigGenerator {
 			podTopologyLabelsSupported:  cg.podTopologyLabelsSupported,
 			inlineTLSConfig:             cg.inlineTLSConfig,
 			bypassVersionCheck:          cg.bypassVersionCheck,
+			scrapeConfigCache:           cg.scrapeConfigCache,
+			specHash:                    cg.specHash,
+			operatorHTTPSDURL:           cg.operatorHTTPSDURL,
+			operatorHTTPSDRegistry:      cg.operatorHTTPSDRegistry,
+			shardConfigurationRollout:   cg.shardConfigurationRollout,
 		}
 	}
 
@@ -344,6 +393,11 @@ func (cg *ConfigGenerator) WithMaximumVersion(version string) *ConfigGenerator {
 			podTopologyLabelsSupported:  cg.podTopologyLabelsSupported,
 			inlineTLSConfig:             cg.inlineTLSConfig,
 			bypassVersionCheck:          cg.bypassVersionCheck,
+			scrapeConfigCache:           cg.scrapeConfigCache,
+			specHash:                    cg.specHash,
+			operatorHTTPSDURL:           cg.operatorHTTPSDURL,
+			operatorHTTPSDRegistry:      cg.operatorHTTPSDRegistry,
+			shardConfigurationRollout:   cg.shardConfigurationRollout,
 		}
 	}
 
@@ -628,6 +682,74 @@ func mergeAttachMetadataWithScrapeClass(attachMetadata *monitoringv1.AttachMetad
 	}
 }
 
+// mergeScrapeIntervalsWithScrapeClass returns the scrape interval and timeout
+// of a scrape job. The values defined by the scrape resource take precedence
+// over the scrape class. Like Prometheus does for the global scrape timeout,
+// the scrape class timeout is ignored when it is greater than the effective
+// scrape interval of the job.
+func (cg *ConfigGenerator) mergeScrapeIntervalsWithScrapeClass(interval, timeout monitoringv1.Duration, scrapeClass monitoringv1.ScrapeClass) (monitoringv1.Duration, monitoringv1.Duration) {
+	if interval == "" {
+		interval = ptr.Deref(scrapeClass.ScrapeInterval, "")
+	}
+
+	if timeout != "" || scrapeClass.ScrapeTimeout == nil {
+		return interval, timeout
+	}
+
+	effectiveInterval := interval
+	if effectiveInterval == "" {
+		effectiveInterval = cg.prom.GetCommonPrometheusFields().ScrapeInterval
+	}
+
+	if effectiveInterval != "" {
+		if err := CompareScrapeTimeoutToScrapeInterval(*scrapeClass.ScrapeTimeout, effectiveInterval); err != nil {
+			return interval, timeout
+		}
+	}
+
+	return interval, *scrapeClass.ScrapeTimeout
+}
+
+func mergeLimitWithScrapeClass(limit *int64, scrapeClassLimit *int64) *int64 {
+	if limit == nil {
+		return scrapeClassLimit
+	}
+
+	return limit
+}
+
+// effectiveScrapeLimit returns the limit applied by Prometheus to the
+// targets of a resource given the limit of the resource, the limit of its
+// scrape class, the global limit and the enforced limit. A nil value means
+// that the targets aren't limited.
+func effectiveScrapeLimit(version semver.Version, limit, scrapeClassLimit, globalLimit, enforcedLimit *int64) *int64 {
+	limit = mergeLimitWithScrapeClass(limit, scrapeClassLimit)
+
+	// With Prometheus >= 2.45.0, the global limit applies to the scrape
+	// configurations without limit.
+	if limit == nil && version.GTE(semver.MustParse("2.45.0")) {
+		limit = globalLimit
+	}
+
+	if ptr.Deref(limit, 0) <= 0 {
+		limit = nil
+	}
+
+	if ptr.Deref(enforcedLimit, 0) > 0 && (limit == nil || *limit > *enforcedLimit) {
+		return enforcedLimit
+	}
+
+	return limit
+}
+
+func mergeBodySizeLimitWithScrapeClass(bodySizeLimit *monitoringv1.ByteSize, scrapeClass monitoringv1.ScrapeClass) *monitoringv1.ByteSize {
+	if isByteSizeEmpty(bodySizeLimit) {
+		return scrapeClass.BodySizeLimit
+	}
+
+	return bodySizeLimit
+}
+
 func mergeFallbackScrapeProtocolWithScrapeClass(fallbackScrapeProtocol *monitoringv1.ScrapeProtocol, scrapeClass monitoringv1.ScrapeClass) *monitoringv1.ScrapeProtocol {
 	if fallbackScrapeProtocol == nil {
 		fallbackScrapeProtocol = scrapeClass.FallbackScrapeProtocol
@@ -1005,6 +1127,126 @@ func (cg *ConfigGenerator) GenerateServerConfiguration(
 	additionalAlertRelabelConfigs []byte,
 	additionalAlertManagerConfigs []byte,
 	ruleConfigMapNames []string,
+) ([]byte, error) {
+	cg.pruneScrapeConfigCache(sMons, pMons, probes, sCons)
+
+	return cg.generateServerConfiguration(
+		p,
+		sMons,
+		pMons,
+		probes,
+		sCons,
+		store,
+		additionalScrapeConfigs,
+		additionalAlertRelabelConfigs,
+		additionalAlertManagerConfigs,
+		ruleConfigMapNames,
+	)
+}
+
+// GenerateServerConfigurationPerShard creates the serialized YAML
+// representation of the Prometheus Server configuration for each shard when
+// the sharding strategy assigns the resources to shards.
+//
+// The configuration of a shard only contains the scrape jobs of the resources
+// assigned to the shard. It also returns the sorted list of namespaces
+// assigned to each shard.
+func (cg *ConfigGenerator) GenerateServerConfigurationPerShard(
+	p *monitoringv1.Prometheus,
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	store *assets.StoreBuilder,
+	additionalScrapeConfigs []byte,
+	additionalAlertRelabelConfigs []byte,
+	additionalAlertManagerConfigs []byte,
+	ruleConfigMapNames []string,
+) ([][]byte, [][]string, error) {
+	return cg.generateConfigurationPerShard(sMons, pMons, probes, sCons, func(
+		sMons map[string]*monitoringv1.ServiceMonitor,
+		pMons map[string]*monitoringv1.PodMonitor,
+		probes map[string]*monitoringv1.Probe,
+		sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	) ([]byte, error) {
+		return cg.generateServerConfiguration(
+			p,
+			sMons,
+			pMons,
+			probes,
+			sCons,
+			store,
+			additionalScrapeConfigs,
+			additionalAlertRelabelConfigs,
+			additionalAlertManagerConfigs,
+			ruleConfigMapNames,
+		)
+	})
+}
+
+type generateConfigurationFunc func(
+	map[string]*monitoringv1.ServiceMonitor,
+	map[string]*monitoringv1.PodMonitor,
+	map[string]*monitoringv1.Probe,
+	map[string]*monitoringv1alpha1.ScrapeConfig,
+) ([]byte, error)
+
+func (cg *ConfigGenerator) generateConfigurationPerShard(
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	generate generateConfigurationFunc,
+) ([][]byte, [][]string, error) {
+	a := NewShardAssigner(cg.prom, cg.logger)
+	if a == nil {
+		return nil, nil, fmt.Errorf("the sharding strategy doesn't assign resources to shards")
+	}
+
+	// Prune the cache once with all the resources since the configuration
+	// of a shard only includes a subset of them.
+	cg.pruneScrapeConfigCache(sMons, pMons, probes, sCons)
+
+	var (
+		configs    = make([][]byte, 0, a.Shards())
+		namespaces = make([][]string, 0, a.Shards())
+	)
+
+	for shard := range a.Shards() {
+		conf, err := generate(
+			filterForShard(a, sMons, shard),
+			filterForShard(a, pMons, shard),
+			filterForShard(a, probes, shard),
+			filterForShard(a, sCons, shard),
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("shard %d: %w", shard, err)
+		}
+
+		configs = append(configs, conf)
+
+		ns := sets.New(a.pinnedNamespaces(shard)...)
+		addNamespacesForShard(a, sMons, shard, ns)
+		addNamespacesForShard(a, pMons, shard, ns)
+		addNamespacesForShard(a, probes, shard, ns)
+		addNamespacesForShard(a, sCons, shard, ns)
+		namespaces = append(namespaces, sets.List(ns))
+	}
+
+	return configs, namespaces, nil
+}
+
+func (cg *ConfigGenerator) generateServerConfiguration(
+	p *monitoringv1.Prometheus,
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	store *assets.StoreBuilder,
+	additionalScrapeConfigs []byte,
+	additionalAlertRelabelConfigs []byte,
+	additionalAlertManagerConfigs []byte,
+	ruleConfigMapNames []string,
 ) ([]byte, error) {
 	cpf := cg.prom.GetCommonPrometheusFields()
 
@@ -1034,15 +1276,36 @@ func (cg *ConfigGenerator) GenerateServerConfiguration(
 	var (
 		scrapeConfigs   []yaml.MapSlice
 		apiserverConfig = cpf.APIServerConfig
-		shards          = shardsNumber(cg.prom)
+		shards          = ShardsNumber(cg.prom)
 	)
 
-	scrapeConfigs = cg.appendServiceMonitorConfigs(scrapeConfigs, sMons, apiserverConfig, store, shards)
-	scrapeConfigs = cg.appendPodMonitorConfigs(scrapeConfigs, pMons, apiserverConfig, store, shards)
-	scrapeConfigs = cg.appendProbeConfigs(scrapeConfigs, probes, apiserverConfig, store, shards)
-	scrapeConfigs, err := cg.appendScrapeConfigs(scrapeConfigs, sCons, store, shards)
+	if cg.ScrapeConfigFilesEnabled(p) {
+		// The scrape configurations of the selected resources are rendered
+		// by GenerateScrapeConfigFiles().
+		scrapeConfigFiles := []string{filepath.Join(ScrapeConfigFilesDir, "*.yaml")}
+		if PerShardConfiguration(p) {
+			// Only reference the files of the shard's resources.
+			scrapeConfigFiles = scrapeConfigFilePaths(sMons, pMons, probes, sCons)
+		}
+
+		cfg = append(cfg, yaml.MapItem{
+			Key:   "scrape_config_files",
+			Value: scrapeConfigFiles,
+		})
+	} else {
+		var err error
+		scrapeConfigs = cg.appendServiceMonitorConfigs(scrapeConfigs, sMons, apiserverConfig, store, shards)
+		scrapeConfigs = cg.appendPodMonitorConfigs(scrapeConfigs, pMons, apiserverConfig, store, shards)
+		scrapeConfigs = cg.appendProbeConfigs(scrapeConfigs, probes, apiserverConfig, store, shards)
+		scrapeConfigs, err = cg.appendScrapeConfigs(scrapeConfigs, sCons, store, shards)
+		if err != nil {
+			return nil, fmt.Errorf("generate scrape configs: %w", err)
+		}
+	}
+
+	scrapeConfigs, err := cg.appendAnnotationDiscoveryConfigs(scrapeConfigs, apiserverConfig, store, shards)
 	if err != nil {
-		return nil, fmt.Errorf("generate scrape configs: %w", err)
+		return nil, fmt.Errorf("generate annotation discovery configs: %w", err)
 	}
 
 	scrapeConfigs, err = cg.appendAdditionalScrapeConfigs(scrapeConfigs, additionalScrapeConfigs, shards)
@@ -1055,7 +1318,7 @@ func (cg *ConfigGenerator) GenerateServerConfiguration(
 	})
 
 	// Storage config
-	cfg, err = cg.appendStorageSettingsConfig(cfg, p.Spec.Exemplars, p.Spec.Retention, p.Spec.RetentionSize)
+	cfg, err = cg.appendStorageSettingsConfig(cfg, p.Spec.Exemplars, p.Spec.Retention, RetentionSize(p.Spec))
 	if err != nil {
 		return nil, fmt.Errorf("generating storage_settings configuration failed: %w", err)
 	}
@@ -1444,6 +1707,29 @@ func getStatupProbePeriodSecondsAndFailureThreshold(maxStartupDurationSeconds *i
 	return int32(startupPeriodSeconds), int32(startupFailureThreshold)
 }
 
+// podMonitorJobName returns the name of the scrape job generated for the
+// i-th endpoint of the PodMonitor.
+func podMonitorJobName(m *monitoringv1.PodMonitor, i int) string {
+	return fmt.Sprintf("podMonitor/%s/%s/%d", m.Namespace, m.Name, i)
+}
+
+// serviceMonitorJobName returns the name of the scrape job generated for the
+// i-th endpoint of the ServiceMonitor.
+func serviceMonitorJobName(m *monitoringv1.ServiceMonitor, i int) string {
+	return fmt.Sprintf("serviceMonitor/%s/%s/%d", m.Namespace, m.Name, i)
+}
+
+// probeJobName returns the name of the scrape job generated for the Probe.
+func probeJobName(m *monitoringv1.Probe) string {
+	return fmt.Sprintf("probe/%s/%s", m.Namespace, m.Name)
+}
+
+// scrapeConfigJobName returns the name of the scrape job generated for the
+// ScrapeConfig.
+func scrapeConfigJobName(sc *monitoringv1alpha1.ScrapeConfig) string {
+	return fmt.Sprintf("scrapeConfig/%s/%s", sc.Namespace, sc.Name)
+}
+
 func (cg *ConfigGenerator) generatePodMonitorConfig(
 	m *monitoringv1.PodMonitor,
 	ep monitoringv1.PodMetricsEndpoint,
@@ -1456,7 +1742,7 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	cfg := yaml.MapSlice{
 		{
 			Key:   "job_name",
-			Value: fmt.Sprintf("podMonitor/%s/%s/%d", m.Namespace, m.Name, i),
+			Value: podMonitorJobName(m, i),
 		},
 	}
 	cfg = cg.AddHonorLabels(cfg, ep.HonorLabels)
@@ -1479,11 +1765,12 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 			attachMetaConfig,
 			cg.withK8SRoleSelectorConfig(m.Spec.Selector, m.Spec.SelectorMechanism, roleSelectors)))
 
-	if ep.Interval != "" {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: ep.Interval})
+	scrapeInterval, scrapeTimeout := cg.mergeScrapeIntervalsWithScrapeClass(ep.Interval, ep.ScrapeTimeout, scrapeClass)
+	if scrapeInterval != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: scrapeInterval})
 	}
-	if ep.ScrapeTimeout != "" {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: ep.ScrapeTimeout})
+	if scrapeTimeout != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: scrapeTimeout})
 	}
 	if ep.Path != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: ep.Path})
@@ -1662,22 +1949,22 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 
 	// DaemonSet mode doesn't support sharding.
 	if !cg.daemonSet {
-		relabelings = cg.appendShardingRelabelingWithAddress(relabelings, shards)
+		relabelings = cg.appendResourceShardingRelabelings(relabelings, shards, "__address__")
 	}
 
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 
-	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, m.Spec.SampleLimit, cpf.EnforcedSampleLimit)
-	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, m.Spec.TargetLimit, cpf.EnforcedTargetLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, m.Spec.LabelLimit, cpf.EnforcedLabelLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, cpf.EnforcedLabelNameLengthLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, cpf.EnforcedLabelValueLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, mergeLimitWithScrapeClass(m.Spec.SampleLimit, scrapeClass.SampleLimit), cpf.EnforcedSampleLimit)
+	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, mergeLimitWithScrapeClass(m.Spec.TargetLimit, scrapeClass.TargetLimit), cpf.EnforcedTargetLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelLimit, scrapeClass.LabelLimit), cpf.EnforcedLabelLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelNameLengthLimit, scrapeClass.LabelNameLengthLimit), cpf.EnforcedLabelNameLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelValueLengthLimit, scrapeClass.LabelValueLengthLimit), cpf.EnforcedLabelValueLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, keepDroppedTargetsKey, m.Spec.KeepDroppedTargets, cpf.EnforcedKeepDroppedTargets)
 	cfg = cg.addNativeHistogramConfig(cfg, m.Spec.NativeHistogramConfig)
 	cfg = cg.addScrapeProtocols(cfg, m.Spec.ScrapeProtocols)
 	cfg = cg.addFallbackScrapeProtocol(cfg, mergeFallbackScrapeProtocolWithScrapeClass(m.Spec.FallbackScrapeProtocol, scrapeClass))
 
-	if bodySizeLimit := getLowerByteSize(m.Spec.BodySizeLimit, &cpf); !isByteSizeEmpty(bodySizeLimit) {
+	if bodySizeLimit := getLowerByteSize(mergeBodySizeLimitWithScrapeClass(m.Spec.BodySizeLimit, scrapeClass), &cpf); !isByteSizeEmpty(bodySizeLimit) {
 		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", bodySizeLimit)
 	}
 
@@ -1703,7 +1990,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 ) yaml.MapSlice {
 	scrapeClass := cg.getScrapeClassOrDefault(m.Spec.ScrapeClassName)
 
-	jobName := fmt.Sprintf("probe/%s/%s", m.Namespace, m.Name)
+	jobName := probeJobName(m)
 	cfg := yaml.MapSlice{
 		{
 			Key:   "job_name",
@@ -1716,11 +2003,12 @@ func (cg *ConfigGenerator) generateProbeConfig(
 
 	cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: m.Spec.ProberSpec.Path})
 
-	if m.Spec.Interval != "" {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: m.Spec.Interval})
+	scrapeInterval, scrapeTimeout := cg.mergeScrapeIntervalsWithScrapeClass(m.Spec.Interval, m.Spec.ScrapeTimeout, scrapeClass)
+	if scrapeInterval != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: scrapeInterval})
 	}
-	if m.Spec.ScrapeTimeout != "" {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: m.Spec.ScrapeTimeout})
+	if scrapeTimeout != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: scrapeTimeout})
 	}
 	if m.Spec.ProberSpec.Scheme != nil {
 		cfg = append(cfg, yaml.MapItem{Key: "scheme", Value: m.Spec.ProberSpec.Scheme.String()})
@@ -1744,18 +2032,18 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	}
 
 	cpf := cg.prom.GetCommonPrometheusFields()
-	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, m.Spec.SampleLimit, cpf.EnforcedSampleLimit)
-	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, m.Spec.TargetLimit, cpf.EnforcedTargetLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, m.Spec.LabelLimit, cpf.EnforcedLabelLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, cpf.EnforcedLabelNameLengthLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, cpf.EnforcedLabelValueLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, mergeLimitWithScrapeClass(m.Spec.SampleLimit, scrapeClass.SampleLimit), cpf.EnforcedSampleLimit)
+	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, mergeLimitWithScrapeClass(m.Spec.TargetLimit, scrapeClass.TargetLimit), cpf.EnforcedTargetLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelLimit, scrapeClass.LabelLimit), cpf.EnforcedLabelLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelNameLengthLimit, scrapeClass.LabelNameLengthLimit), cpf.EnforcedLabelNameLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelValueLengthLimit, scrapeClass.LabelValueLengthLimit), cpf.EnforcedLabelValueLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, keepDroppedTargetsKey, m.Spec.KeepDroppedTargets, cpf.EnforcedKeepDroppedTargets)
 	cfg = cg.addNativeHistogramConfig(cfg, m.Spec.NativeHistogramConfig)
 	cfg = cg.addScrapeProtocols(cfg, m.Spec.ScrapeProtocols)
 	cfg = cg.addFallbackScrapeProtocol(cfg, mergeFallbackScrapeProtocolWithScrapeClass(m.Spec.FallbackScrapeProtocol, scrapeClass))
 
-	if cpf.EnforcedBodySizeLimit != "" {
-		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", cpf.EnforcedBodySizeLimit)
+	if bodySizeLimit := getLowerByteSize(scrapeClass.BodySizeLimit, &cpf); !isByteSizeEmpty(bodySizeLimit) {
+		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", bodySizeLimit)
 	}
 
 	relabelings := initRelabelings()
@@ -1781,27 +2069,42 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	// checks for m.Spec.Targets.StaticConfig.
 	switch {
 	case m.Spec.Targets.StaticConfig != nil:
-		// Generate static_config section.
-		staticConfig := yaml.MapSlice{
-			{Key: "targets", Value: m.Spec.Targets.StaticConfig.Targets},
-		}
+		if cg.operatorHTTPSDEnabled() {
+			// The targets are served by the operator's HTTP SD endpoint.
+			cfg = append(cfg, yaml.MapItem{
+				Key: "http_sd_configs",
+				Value: []yaml.MapSlice{
+					cg.operatorHTTPSDConfig(
+						store.ForNamespace(cg.prom.GetObjectMeta().GetNamespace()),
+						monitoringv1.ProbesKind,
+						m.Namespace,
+						m.Name,
+					),
+				},
+			})
+		} else {
+			// Generate static_config section.
+			staticConfig := yaml.MapSlice{
+				{Key: "targets", Value: m.Spec.Targets.StaticConfig.Targets},
+			}
 
-		if m.Spec.Targets.StaticConfig.Labels != nil {
-			if _, ok := m.Spec.Targets.StaticConfig.Labels["namespace"]; !ok {
-				m.Spec.Targets.StaticConfig.Labels["namespace"] = m.Namespace
+			if m.Spec.Targets.StaticConfig.Labels != nil {
+				if _, ok := m.Spec.Targets.StaticConfig.Labels["namespace"]; !ok {
+					m.Spec.Targets.StaticConfig.Labels["namespace"] = m.Namespace
+				}
+			} else {
+				m.Spec.Targets.StaticConfig.Labels = map[string]string{"namespace": m.Namespace}
 			}
-		} else {
-			m.Spec.Targets.StaticConfig.Labels = map[string]string{"namespace": m.Namespace}
-		}
 
-		staticConfig = append(staticConfig, yaml.MapSlice{
-			{Key: "labels", Value: m.Spec.Targets.StaticConfig.Labels},
-		}...)
+			staticConfig = append(staticConfig, yaml.MapSlice{
+				{Key: "labels", Value: m.Spec.Targets.StaticConfig.Labels},
+			}...)
 
-		cfg = append(cfg, yaml.MapItem{
-			Key:   "static_configs",
-			Value: []yaml.MapSlice{staticConfig},
-		})
+			cfg = append(cfg, yaml.MapItem{
+				Key:   "static_configs",
+				Value: []yaml.MapSlice{staticConfig},
+			})
+		}
 
 		// Relabelings for prober.
 		relabelings = append(relabelings, []yaml.MapSlice{
@@ -1918,7 +2221,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 		relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, m.Spec.Targets.Ingress.RelabelConfigs))...)
 	}
 
-	relabelings = cg.appendShardingRelabelingForProbes(relabelings, shards)
+	relabelings = cg.appendResourceShardingRelabelings(relabelings, shards, "__param_target")
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 
 	if m.Spec.BearerTokenSecret != nil { //nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
@@ -1959,7 +2262,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	cfg := yaml.MapSlice{
 		{
 			Key:   "job_name",
-			Value: fmt.Sprintf("serviceMonitor/%s/%s/%d", m.Namespace, m.Name, i),
+			Value: serviceMonitorJobName(m, i),
 		},
 	}
 	cfg = cg.AddHonorLabels(cfg, ep.HonorLabels)
@@ -1989,11 +2292,12 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 		cg.withK8SRoleSelectorConfig(m.Spec.Selector, m.Spec.SelectorMechanism, roleSelectors)),
 	)
 
-	if ep.Interval != "" {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: ep.Interval})
+	scrapeInterval, scrapeTimeout := cg.mergeScrapeIntervalsWithScrapeClass(ep.Interval, ep.ScrapeTimeout, scrapeClass)
+	if scrapeInterval != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: scrapeInterval})
 	}
-	if ep.ScrapeTimeout != "" {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: ep.ScrapeTimeout})
+	if scrapeTimeout != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: scrapeTimeout})
 	}
 	if ep.Path != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: ep.Path})
@@ -2211,20 +2515,20 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	labeler := namespacelabeler.New(cpf.EnforcedNamespaceLabel, cpf.ExcludedFromEnforcement, false)
 	relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, ep.RelabelConfigs))...)
 
-	relabelings = cg.appendShardingRelabelingWithAddress(relabelings, shards)
+	relabelings = cg.appendResourceShardingRelabelings(relabelings, shards, "__address__")
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 
-	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, m.Spec.SampleLimit, cpf.EnforcedSampleLimit)
-	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, m.Spec.TargetLimit, cpf.EnforcedTargetLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, m.Spec.LabelLimit, cpf.EnforcedLabelLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, cpf.EnforcedLabelNameLengthLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, cpf.EnforcedLabelValueLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, mergeLimitWithScrapeClass(m.Spec.SampleLimit, scrapeClass.SampleLimit), cpf.EnforcedSampleLimit)
+	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, mergeLimitWithScrapeClass(m.Spec.TargetLimit, scrapeClass.TargetLimit), cpf.EnforcedTargetLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelLimit, scrapeClass.LabelLimit), cpf.EnforcedLabelLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelNameLengthLimit, scrapeClass.LabelNameLengthLimit), cpf.EnforcedLabelNameLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, mergeLimitWithScrapeClass(m.Spec.LabelValueLengthLimit, scrapeClass.LabelValueLengthLimit), cpf.EnforcedLabelValueLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, keepDroppedTargetsKey, m.Spec.KeepDroppedTargets, cpf.EnforcedKeepDroppedTargets)
 	cfg = cg.addNativeHistogramConfig(cfg, m.Spec.NativeHistogramConfig)
 	cfg = cg.addScrapeProtocols(cfg, m.Spec.ScrapeProtocols)
 	cfg = cg.addFallbackScrapeProtocol(cfg, mergeFallbackScrapeProtocolWithScrapeClass(m.Spec.FallbackScrapeProtocol, scrapeClass))
 
-	if bodySizeLimit := getLowerByteSize(m.Spec.BodySizeLimit, &cpf); !isByteSizeEmpty(bodySizeLimit) {
+	if bodySizeLimit := getLowerByteSize(mergeBodySizeLimitWithScrapeClass(m.Spec.BodySizeLimit, scrapeClass), &cpf); !isByteSizeEmpty(bodySizeLimit) {
 		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", bodySizeLimit)
 	}
 
@@ -2271,10 +2575,6 @@ func (cg *ConfigGenerator) appendShardingRelabelingWithAddress(relabelings []yam
 	return cg.appendShardingRelabelingWithLabel(relabelings, shards, "__address__")
 }
 
-func (cg *ConfigGenerator) appendShardingRelabelingForProbes(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
-	return cg.appendShardingRelabelingWithLabel(relabelings, shards, "__param_target")
-}
-
 func (cg *ConfigGenerator) appendShardingRelabelingWithAddressIfMissing(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
 	for i, relabeling := range relabelings {
 		for _, relabelItem := range relabeling {
@@ -2301,7 +2601,24 @@ func generateInRangeShardPattern(shards int32) string {
 	return strings.Join(inRangeShards, "|")
 }
 
-func (cg *ConfigGenerator) appendShardingRelabelingWithLabel(relabelings []yaml.MapSlice, shards int32, shardLabel string) []yaml.MapSlice {
+// appendResourceShardingRelabelings appends the sharding relabelings to the
+// scrape configuration of a ServiceMonitor, PodMonitor, Probe or
+// ScrapeConfig resource.
+//
+// When the resources are assigned to shards, the job is only present in the
+// configuration of its shard and the targets aren't distributed by the value
+// of shardLabel.
+func (cg *ConfigGenerator) appendResourceShardingRelabelings(relabelings []yaml.MapSlice, shards int32, shardLabel string) []yaml.MapSlice {
+	if PerShardConfiguration(cg.prom) {
+		return cg.appendInRangeShardRelabelings(relabelings, shards)
+	}
+
+	return cg.appendShardingRelabelingWithLabel(relabelings, shards, shardLabel)
+}
+
+// appendInRangeShardRelabelings appends the relabelings dropping all
+// targets on inactive shards when the retention policies are enabled.
+func (cg *ConfigGenerator) appendInRangeShardRelabelings(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
 	if cg.prometheusRetentionPolicies {
 		relabelings = append(relabelings,
 			// Capture the current SHARD environment variable value.
@@ -2320,6 +2637,12 @@ func (cg *ConfigGenerator) appendShardingRelabelingWithLabel(relabelings []yaml.
 		)
 	}
 
+	return relabelings
+}
+
+func (cg *ConfigGenerator) appendShardingRelabelingWithLabel(relabelings []yaml.MapSlice, shards int32, shardLabel string) []yaml.MapSlice {
+	relabelings = cg.appendInRangeShardRelabelings(relabelings, shards)
+
 	modulus := shards
 	shardEnvVar := operator.ShardEnvVar
 	if cg.isTopologyShardingActive() {
@@ -2616,8 +2939,22 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(alerting *monitoringv1.Ale
 
 		cfg = cg.addProxyConfigtoYaml(cfg, store, am.ProxyConfig)
 
-		ns := ptr.Deref(am.Namespace, cg.prom.GetObjectMeta().GetNamespace())
-		cfg = append(cfg, cg.generateK8SSDConfig(monitoringv1.NamespaceSelector{}, ns, apiserverConfig, store, cg.defaultEndpointRoleFlavor(), nil))
+		if am.Name != "" {
+			ns := ptr.Deref(am.Namespace, cg.prom.GetObjectMeta().GetNamespace())
+			cfg = append(cfg, cg.generateK8SSDConfig(monitoringv1.NamespaceSelector{}, ns, apiserverConfig, store, cg.defaultEndpointRoleFlavor(), nil))
+		}
+
+		if len(am.StaticConfigs) > 0 {
+			staticConfigs := make([]yaml.MapSlice, 0, len(am.StaticConfigs))
+			for _, sc := range am.StaticConfigs {
+				staticConfigs = append(staticConfigs, yaml.MapSlice{{Key: "targets", Value: sc.Targets}})
+			}
+			cfg = append(cfg, yaml.MapItem{Key: "static_configs", Value: staticConfigs})
+		}
+
+		if len(am.DNSSDConfigs) > 0 {
+			cfg = append(cfg, yaml.MapItem{Key: "dns_sd_configs", Value: generateAlertmanagerDNSSDConfigs(am.DNSSDConfigs)})
+		}
 
 		//nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
 		if am.BearerTokenFile != "" {
@@ -2646,35 +2983,39 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(alerting *monitoringv1.Ale
 
 		var relabelings []yaml.MapSlice
 
-		relabelings = append(relabelings, yaml.MapSlice{
-			{Key: "action", Value: "keep"},
-			{Key: "source_labels", Value: []string{"__meta_kubernetes_service_name"}},
-			{Key: "regex", Value: am.Name},
-		})
-
-		if am.Port.StrVal != "" {
-			sourceLabels := []string{"__meta_kubernetes_endpoint_port_name"}
-			if cg.defaultEndpointRoleFlavor() == kubernetesSDRoleEndpointSlice {
-				sourceLabels = []string{"__meta_kubernetes_endpointslice_port_name"}
-			}
+		if am.Name != "" {
 			relabelings = append(relabelings, yaml.MapSlice{
 				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: sourceLabels},
-				{Key: "regex", Value: am.Port.String()},
-			})
-		} else if am.Port.IntVal != 0 {
-			relabelings = append(relabelings, yaml.MapSlice{
-				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_number"}},
-				{Key: "regex", Value: am.Port.String()},
+				{Key: "source_labels", Value: []string{"__meta_kubernetes_service_name"}},
+				{Key: "regex", Value: am.Name},
 			})
+
+			if am.Port.StrVal != "" {
+				sourceLabels := []string{"__meta_kubernetes_endpoint_port_name"}
+				if cg.defaultEndpointRoleFlavor() == kubernetesSDRoleEndpointSlice {
+					sourceLabels = []string{"__meta_kubernetes_endpointslice_port_name"}
+				}
+				relabelings = append(relabelings, yaml.MapSlice{
+					{Key: "action", Value: "keep"},
+					{Key: "source_labels", Value: sourceLabels},
+					{Key: "regex", Value: am.Port.String()},
+				})
+			} else if am.Port.IntVal != 0 {
+				relabelings = append(relabelings, yaml.MapSlice{
+					{Key: "action", Value: "keep"},
+					{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_container_port_number"}},
+					{Key: "regex", Value: am.Port.String()},
+				})
+			}
 		}
 
 		if len(am.RelabelConfigs) != 0 {
 			relabelings = append(relabelings, generateRelabelConfig(am.RelabelConfigs)...)
 		}
 
-		cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
+		if len(relabelings) > 0 {
+			cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
+		}
 
 		// Append alert_relabel_configs, if any, to the config
 		if len(am.AlertRelabelConfigs) > 0 {
@@ -2686,6 +3027,29 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(alerting *monitoringv1.Ale
 	return alertmanagerConfigs
 }
 
+func generateAlertmanagerDNSSDConfigs(dnsSDConfigs []monitoringv1.AlertmanagerDNSSDConfig) []yaml.MapSlice {
+	configs := make([]yaml.MapSlice, 0, len(dnsSDConfigs))
+	for _, dc := range dnsSDConfigs {
+		config := yaml.MapSlice{{Key: "names", Value: dc.Names}}
+
+		if dc.RefreshInterval != nil {
+			config = append(config, yaml.MapItem{Key: "refresh_interval", Value: dc.RefreshInterval})
+		}
+
+		if dc.Type != nil {
+			config = append(config, yaml.MapItem{Key: "type", Value: dc.Type})
+		}
+
+		if dc.Port != nil {
+			config = append(config, yaml.MapItem{Key: "port", Value: dc.Port})
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs
+}
+
 func (cg *ConfigGenerator) generateAdditionalScrapeConfigs(
 	additionalScrapeConfigs []byte,
 	shards int32,
@@ -3022,6 +3386,15 @@ func (cg *ConfigGenerator) GenerateRemoteWriteConfig(rws []monitoringv1.RemoteWr
 			cfg = cg.WithMinimumVersion("2.45.0").AppendMapItem(cfg, "azuread", azureAd)
 		}
 
+		if spec.GoogleIAM != nil {
+			googleIAM := yaml.MapSlice{}
+			if spec.GoogleIAM.Credentials != nil {
+				googleIAM = append(googleIAM, yaml.MapItem{Key: "credentials_file", Value: path.Join(tlsAssetsDir, s.TLSAsset(spec.GoogleIAM.Credentials))})
+			}
+
+			cfg = cg.WithMinimumVersion("2.55.0").AppendMapItem(cfg, "google_iam", googleIAM)
+		}
+
 		if spec.FollowRedirects != nil {
 			cfg = cg.WithMinimumVersion("2.26.0").AppendMapItem(cfg, "follow_redirects", spec.FollowRedirects)
 		}
@@ -3209,6 +3582,154 @@ func (cg *ConfigGenerator) appendScrapeFailureLogFile(slice yaml.MapSlice, scrap
 	return cg.WithMinimumVersion("2.55.0").AppendMapItem(slice, "scrape_failure_log_file", logFilePath(*scrapeFailureLogFile))
 }
 
+// ScrapeConfigFilesEnabled returns true when the scrape configurations of the
+// ServiceMonitor, PodMonitor, Probe and ScrapeConfig resources should be
+// rendered into separate files referenced by `scrape_config_files`.
+func (cg *ConfigGenerator) ScrapeConfigFilesEnabled(p *monitoringv1.Prometheus) bool {
+	if ptr.Deref(p.Spec.ScrapeConfigRendering, monitoringv1.InlineScrapeConfigRendering) != monitoringv1.PerResourceScrapeConfigRendering {
+		return false
+	}
+
+	scg := cg.WithMinimumVersion("2.43.0")
+	if !scg.IsCompatible() {
+		scg.Warn("scrapeConfigRendering")
+		return false
+	}
+
+	return true
+}
+
+// GenerateScrapeConfigFiles returns the scrape configuration of each
+// ServiceMonitor, PodMonitor, Probe and ScrapeConfig resource as a separate
+// file. The keys of the returned map are the file names.
+//
+// Each file is a valid Prometheus configuration file for the
+// `scrape_config_files` field. The content of a file only depends on the
+// associated resource (and the Prometheus spec) which means that unchanged
+// resources produce identical files.
+//
+// The files contain the shard variables which need to be expanded with
+// ExpandShardVariables() before being mounted into the shard's pods.
+func (cg *ConfigGenerator) GenerateScrapeConfigFiles(
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	store *assets.StoreBuilder,
+) (map[string][]byte, error) {
+	var (
+		cpf             = cg.prom.GetCommonPrometheusFields()
+		apiserverConfig = cpf.APIServerConfig
+		shards          = ShardsNumber(cg.prom)
+		files           = make(map[string][]byte, len(sMons)+len(pMons)+len(probes)+len(sCons))
+	)
+
+	cg.pruneScrapeConfigCache(sMons, pMons, probes, sCons)
+
+	addFile := func(kind, identifier string, scrapeConfigs []yaml.MapSlice) error {
+		b, err := yaml.Marshal(yaml.MapSlice{{Key: "scrape_configs", Value: scrapeConfigs}})
+		if err != nil {
+			return fmt.Errorf("failed to marshal scrape configuration for %s %s: %w", kind, identifier, err)
+		}
+
+		files[scrapeConfigFileName(kind, identifier)] = b
+		return nil
+	}
+
+	for _, identifier := range sortutil.SortedKeys(sMons) {
+		scrapeConfigs := cg.appendServiceMonitorConfigs(nil, map[string]*monitoringv1.ServiceMonitor{identifier: sMons[identifier]}, apiserverConfig, store, shards)
+		if err := addFile(monitoringv1.ServiceMonitorsKind, identifier, scrapeConfigs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, identifier := range sortutil.SortedKeys(pMons) {
+		scrapeConfigs := cg.appendPodMonitorConfigs(nil, map[string]*monitoringv1.PodMonitor{identifier: pMons[identifier]}, apiserverConfig, store, shards)
+		if err := addFile(monitoringv1.PodMonitorsKind, identifier, scrapeConfigs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, identifier := range sortutil.SortedKeys(probes) {
+		scrapeConfigs := cg.appendProbeConfigs(nil, map[string]*monitoringv1.Probe{identifier: probes[identifier]}, apiserverConfig, store, shards)
+		if err := addFile(monitoringv1.ProbesKind, identifier, scrapeConfigs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, identifier := range sortutil.SortedKeys(sCons) {
+		scrapeConfigs, err := cg.appendScrapeConfigs(nil, map[string]*monitoringv1alpha1.ScrapeConfig{identifier: sCons[identifier]}, store, shards)
+		if err != nil {
+			return nil, fmt.Errorf("generate scrape configs: %w", err)
+		}
+
+		if err := addFile(monitoringv1alpha1.ScrapeConfigsKind, identifier, scrapeConfigs); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// ExpandShardVariables returns a copy of the scrape configuration files
+// where the shard variables ($(SHARD), $(TOPOLOGY_ZONE) and $(INZONE_SHARD))
+// are replaced by the values of the given shard.
+//
+// Unlike the main configuration file, the config-reloader doesn't expand the
+// environment variables in the files of the watched directories hence the
+// files need to be rendered for each shard.
+func (cg *ConfigGenerator) ExpandShardVariables(files map[string][]byte, shard int32) map[string][]byte {
+	oldnew := []string{fmt.Sprintf("$(%s)", operator.ShardEnvVar), strconv.Itoa(int(shard))}
+	if zone := cg.TopologyZoneForShard(shard); zone != "" {
+		oldnew = append(oldnew,
+			fmt.Sprintf("$(%s)", operator.TopologyZoneEnvVar), zone,
+			fmt.Sprintf("$(%s)", operator.InzoneShardEnvVar), strconv.Itoa(int(cg.InzoneShardForShard(shard))),
+		)
+	}
+
+	var (
+		r        = strings.NewReplacer(oldnew...)
+		expanded = make(map[string][]byte, len(files))
+	)
+	for name, b := range files {
+		expanded[name] = []byte(r.Replace(string(b)))
+	}
+
+	return expanded
+}
+
+// scrapeConfigFilePaths returns the paths of the scrape configuration files
+// for the given resources.
+func scrapeConfigFilePaths(
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+) []string {
+	paths := []string{}
+	for _, kinds := range []struct {
+		kind        string
+		identifiers []string
+	}{
+		{kind: monitoringv1.ServiceMonitorsKind, identifiers: sortutil.SortedKeys(sMons)},
+		{kind: monitoringv1.PodMonitorsKind, identifiers: sortutil.SortedKeys(pMons)},
+		{kind: monitoringv1.ProbesKind, identifiers: sortutil.SortedKeys(probes)},
+		{kind: monitoringv1alpha1.ScrapeConfigsKind, identifiers: sortutil.SortedKeys(sCons)},
+	} {
+		for _, identifier := range kinds.identifiers {
+			paths = append(paths, filepath.Join(ScrapeConfigFilesDir, scrapeConfigFileName(kinds.kind, identifier)))
+		}
+	}
+
+	return paths
+}
+
+// scrapeConfigFileName returns the name of the scrape configuration file for
+// the given resource kind and <namespace>/<name> identifier.
+func scrapeConfigFileName(kind, identifier string) string {
+	return fmt.Sprintf("%s_%s.yaml", strings.ToLower(kind), strings.ReplaceAll(identifier, "/", "_"))
+}
+
 func (cg *ConfigGenerator) appendRuleFiles(slice yaml.MapSlice, ruleFiles []string, ruleSelector *metav1.LabelSelector) yaml.MapSlice {
 	if ruleSelector != nil {
 		ruleFilePaths := []string{}
@@ -3232,17 +3753,24 @@ func (cg *ConfigGenerator) appendServiceMonitorConfigs(
 	shards int32) []yaml.MapSlice {
 
 	for _, identifier := range sortutil.SortedKeys(serviceMonitors) {
-		for i, ep := range serviceMonitors[identifier].Spec.Endpoints {
-			slices = append(slices,
-				cg.WithKeyVals("service_monitor", identifier).generateServiceMonitorConfig(
-					serviceMonitors[identifier],
-					ep, i,
-					apiserverConfig,
-					store,
-					shards,
-				),
-			)
-		}
+		sm := serviceMonitors[identifier]
+		// The generation never fails for ServiceMonitors.
+		cfgs, _ := cg.cachedScrapeConfigs(monitoringv1.ServiceMonitorsKind, sm, sm.Spec.ScrapeClassName, store, func(cg *ConfigGenerator, store *assets.StoreBuilder) ([]yaml.MapSlice, error) {
+			var cfgs []yaml.MapSlice
+			for i, ep := range sm.Spec.Endpoints {
+				cfgs = append(cfgs,
+					cg.WithKeyVals("service_monitor", identifier).generateServiceMonitorConfig(
+						sm,
+						ep, i,
+						apiserverConfig,
+						store,
+						shards,
+					),
+				)
+			}
+			return cfgs, nil
+		})
+		slices = append(slices, cfgs...)
 	}
 	return slices
 }
@@ -3255,16 +3783,23 @@ func (cg *ConfigGenerator) appendPodMonitorConfigs(
 	shards int32) []yaml.MapSlice {
 
 	for _, identifier := range sortutil.SortedKeys(podMonitors) {
-		for i, ep := range podMonitors[identifier].Spec.PodMetricsEndpoints {
-			slices = append(slices,
-				cg.WithKeyVals("pod_monitor", identifier).generatePodMonitorConfig(
-					podMonitors[identifier], ep, i,
-					apiserverConfig,
-					store,
-					shards,
-				),
-			)
-		}
+		pm := podMonitors[identifier]
+		// The generation never fails for PodMonitors.
+		cfgs, _ := cg.cachedScrapeConfigs(monitoringv1.PodMonitorsKind, pm, pm.Spec.ScrapeClassName, store, func(cg *ConfigGenerator, store *assets.StoreBuilder) ([]yaml.MapSlice, error) {
+			var cfgs []yaml.MapSlice
+			for i, ep := range pm.Spec.PodMetricsEndpoints {
+				cfgs = append(cfgs,
+					cg.WithKeyVals("pod_monitor", identifier).generatePodMonitorConfig(
+						pm, ep, i,
+						apiserverConfig,
+						store,
+						shards,
+					),
+				)
+			}
+			return cfgs, nil
+		})
+		slices = append(slices, cfgs...)
 	}
 
 	return slices
@@ -3278,14 +3813,19 @@ func (cg *ConfigGenerator) appendProbeConfigs(
 	shards int32) []yaml.MapSlice {
 
 	for _, identifier := range sortutil.SortedKeys(probes) {
-		slices = append(slices,
-			cg.WithKeyVals("probe", identifier).generateProbeConfig(
-				probes[identifier],
-				apiserverConfig,
-				store,
-				shards,
-			),
-		)
+		probe := probes[identifier]
+		// The generation never fails for Probes.
+		cfgs, _ := cg.cachedScrapeConfigs(monitoringv1.ProbesKind, probe, probe.Spec.ScrapeClassName, store, func(cg *ConfigGenerator, store *assets.StoreBuilder) ([]yaml.MapSlice, error) {
+			return []yaml.MapSlice{
+				cg.WithKeyVals("probe", identifier).generateProbeConfig(
+					probe,
+					apiserverConfig,
+					store,
+					shards,
+				),
+			}, nil
+		})
+		slices = append(slices, cfgs...)
 	}
 
 	return slices
@@ -3308,6 +3848,44 @@ func (cg *ConfigGenerator) GenerateAgentConfiguration(
 	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
 	store *assets.StoreBuilder,
 	additionalScrapeConfigs []byte,
+) ([]byte, error) {
+	cg.pruneScrapeConfigCache(sMons, pMons, probes, sCons)
+
+	return cg.generateAgentConfiguration(sMons, pMons, probes, sCons, store, additionalScrapeConfigs)
+}
+
+// GenerateAgentConfigurationPerShard creates the serialized YAML
+// representation of the Prometheus Agent configuration for each shard when
+// the sharding strategy assigns the resources to shards.
+//
+// The configuration of a shard only contains the scrape jobs of the resources
+// assigned to the shard. It also returns the sorted list of namespaces
+// assigned to each shard.
+func (cg *ConfigGenerator) GenerateAgentConfigurationPerShard(
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	store *assets.StoreBuilder,
+	additionalScrapeConfigs []byte,
+) ([][]byte, [][]string, error) {
+	return cg.generateConfigurationPerShard(sMons, pMons, probes, sCons, func(
+		sMons map[string]*monitoringv1.ServiceMonitor,
+		pMons map[string]*monitoringv1.PodMonitor,
+		probes map[string]*monitoringv1.Probe,
+		sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	) ([]byte, error) {
+		return cg.generateAgentConfiguration(sMons, pMons, probes, sCons, store, additionalScrapeConfigs)
+	})
+}
+
+func (cg *ConfigGenerator) generateAgentConfiguration(
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+	store *assets.StoreBuilder,
+	additionalScrapeConfigs []byte,
 ) ([]byte, error) {
 	cpf := cg.prom.GetCommonPrometheusFields()
 
@@ -3330,11 +3908,16 @@ func (cg *ConfigGenerator) GenerateAgentConfiguration(
 	var (
 		scrapeConfigs   []yaml.MapSlice
 		apiserverConfig = cpf.APIServerConfig
-		shards          = shardsNumber(cg.prom)
+		shards          = ShardsNumber(cg.prom)
 	)
 
 	scrapeConfigs = cg.appendPodMonitorConfigs(scrapeConfigs, pMons, apiserverConfig, store, shards)
-	scrapeConfigs, err := cg.appendAdditionalScrapeConfigs(scrapeConfigs, additionalScrapeConfigs, shards)
+	scrapeConfigs, err := cg.appendAnnotationDiscoveryConfigs(scrapeConfigs, apiserverConfig, store, shards)
+	if err != nil {
+		return nil, fmt.Errorf("generate annotation discovery configs: %w", err)
+	}
+
+	scrapeConfigs, err = cg.appendAdditionalScrapeConfigs(scrapeConfigs, additionalScrapeConfigs, shards)
 	if err != nil {
 		return nil, fmt.Errorf("generate additional scrape configs: %w", err)
 	}
@@ -3430,14 +4013,26 @@ func (cg *ConfigGenerator) appendScrapeConfigs(
 	shards int32) ([]yaml.MapSlice, error) {
 
 	for _, identifier := range sortutil.SortedKeys(scrapeConfigs) {
-		cfgGenerator := cg.WithKeyVals("scrapeconfig", identifier)
-		scrapeConfig, err := cfgGenerator.generateScrapeConfig(scrapeConfigs[identifier], store.ForNamespace(scrapeConfigs[identifier].GetNamespace()), shards)
+		sc := scrapeConfigs[identifier]
+		cfgs, err := cg.cachedScrapeConfigs(monitoringv1alpha1.ScrapeConfigsKind, sc, sc.Spec.ScrapeClassName, store, func(cg *ConfigGenerator, store *assets.StoreBuilder) ([]yaml.MapSlice, error) {
+			cfgGenerator := cg.WithKeyVals("scrapeconfig", identifier)
+			scrapeConfig, err := cfgGenerator.generateScrapeConfig(
+				sc,
+				store.ForNamespace(sc.GetNamespace()),
+				store.ForNamespace(cg.prom.GetObjectMeta().GetNamespace()),
+				shards,
+			)
+			if err != nil {
+				return nil, err
+			}
 
+			return []yaml.MapSlice{scrapeConfig}, nil
+		})
 		if err != nil {
 			return slices, err
 		}
 
-		slices = append(slices, scrapeConfig)
+		slices = append(slices, cfgs...)
 	}
 
 	return slices, nil
@@ -3446,11 +4041,12 @@ func (cg *ConfigGenerator) appendScrapeConfigs(
 func (cg *ConfigGenerator) generateScrapeConfig(
 	sc *monitoringv1alpha1.ScrapeConfig,
 	s assets.StoreGetter,
+	promStore assets.StoreGetter,
 	shards int32,
 ) (yaml.MapSlice, error) {
 	scrapeClass := cg.getScrapeClassOrDefault(sc.Spec.ScrapeClassName)
 
-	jobName := fmt.Sprintf("scrapeConfig/%s/%s", sc.Namespace, sc.Name)
+	jobName := scrapeConfigJobName(sc)
 
 	cfg := yaml.MapSlice{
 		{
@@ -3501,12 +4097,13 @@ func (cg *ConfigGenerator) generateScrapeConfig(
 		cfg = cg.WithMinimumVersion("2.35.0").AppendMapItem(cfg, "enable_http2", *sc.Spec.EnableHTTP2)
 	}
 
-	if sc.Spec.ScrapeInterval != nil {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: *sc.Spec.ScrapeInterval})
+	scrapeInterval, scrapeTimeout := cg.mergeScrapeIntervalsWithScrapeClass(ptr.Deref(sc.Spec.ScrapeInterval, ""), ptr.Deref(sc.Spec.ScrapeTimeout, ""), scrapeClass)
+	if scrapeInterval != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: scrapeInterval})
 	}
 
-	if sc.Spec.ScrapeTimeout != nil {
-		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: *sc.Spec.ScrapeTimeout})
+	if scrapeTimeout != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: scrapeTimeout})
 	}
 
 	cfg = cg.addScrapeProtocols(cfg, sc.Spec.ScrapeProtocols)
@@ -3526,20 +4123,24 @@ func (cg *ConfigGenerator) generateScrapeConfig(
 
 	cfg = cg.addTLStoYaml(cfg, s, mergeSafeTLSConfigWithScrapeClass(sc.Spec.TLSConfig, scrapeClass))
 
-	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, sc.Spec.SampleLimit, cpf.EnforcedSampleLimit)
-	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, sc.Spec.TargetLimit, cpf.EnforcedTargetLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, sc.Spec.LabelLimit, cpf.EnforcedLabelLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, sc.Spec.LabelNameLengthLimit, cpf.EnforcedLabelNameLengthLimit)
-	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, sc.Spec.LabelValueLengthLimit, cpf.EnforcedLabelValueLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, sampleLimitKey, mergeLimitWithScrapeClass(sc.Spec.SampleLimit, scrapeClass.SampleLimit), cpf.EnforcedSampleLimit)
+	cfg = cg.AddLimitsToYAML(cfg, targetLimitKey, mergeLimitWithScrapeClass(sc.Spec.TargetLimit, scrapeClass.TargetLimit), cpf.EnforcedTargetLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelLimitKey, mergeLimitWithScrapeClass(sc.Spec.LabelLimit, scrapeClass.LabelLimit), cpf.EnforcedLabelLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelNameLengthLimitKey, mergeLimitWithScrapeClass(sc.Spec.LabelNameLengthLimit, scrapeClass.LabelNameLengthLimit), cpf.EnforcedLabelNameLengthLimit)
+	cfg = cg.AddLimitsToYAML(cfg, labelValueLengthLimitKey, mergeLimitWithScrapeClass(sc.Spec.LabelValueLengthLimit, scrapeClass.LabelValueLengthLimit), cpf.EnforcedLabelValueLengthLimit)
 	cfg = cg.AddLimitsToYAML(cfg, keepDroppedTargetsKey, sc.Spec.KeepDroppedTargets, cpf.EnforcedKeepDroppedTargets)
 	cfg = cg.addNativeHistogramConfig(cfg, sc.Spec.NativeHistogramConfig)
 
-	if bodySizeLimit := getLowerByteSize(sc.Spec.BodySizeLimit, &cpf); !isByteSizeEmpty(bodySizeLimit) {
+	if bodySizeLimit := getLowerByteSize(mergeBodySizeLimitWithScrapeClass(sc.Spec.BodySizeLimit, scrapeClass), &cpf); !isByteSizeEmpty(bodySizeLimit) {
 		cfg = cg.WithMinimumVersion("2.28.0").AppendMapItem(cfg, "body_size_limit", bodySizeLimit)
 	}
 
+	// The static targets are served by the operator's HTTP SD endpoint when
+	// enabled.
+	operatorHTTPSD := len(sc.Spec.StaticConfigs) > 0 && cg.operatorHTTPSDEnabled()
+
 	// StaticConfig
-	if len(sc.Spec.StaticConfigs) > 0 {
+	if len(sc.Spec.StaticConfigs) > 0 && !operatorHTTPSD {
 		configs := make([][]yaml.MapItem, len(sc.Spec.StaticConfigs))
 		for i, config := range sc.Spec.StaticConfigs {
 			configs[i] = []yaml.MapItem{
@@ -3584,40 +4185,47 @@ func (cg *ConfigGenerator) generateScrapeConfig(
 	}
 
 	// HTTPSDConfig
-	if len(sc.Spec.HTTPSDConfigs) > 0 {
-		configs := make([][]yaml.MapItem, len(sc.Spec.HTTPSDConfigs))
-		for i, config := range sc.Spec.HTTPSDConfigs {
-			configs[i] = cg.addBasicAuthToYaml(configs[i], s, config.BasicAuth)
-			configs[i] = cg.addSafeAuthorizationToYaml(configs[i], s, config.Authorization)
-			configs[i] = cg.addSafeTLStoYaml(configs[i], s, config.TLSConfig)
-			configs[i] = cg.addProxyConfigtoYaml(configs[i], s, config.ProxyConfig)
-			configs[i] = cg.addOAuth2ToYaml(configs[i], s, config.OAuth2)
+	if len(sc.Spec.HTTPSDConfigs) > 0 || operatorHTTPSD {
+		configs := make([][]yaml.MapItem, 0, len(sc.Spec.HTTPSDConfigs)+1)
+		if operatorHTTPSD {
+			configs = append(configs, cg.operatorHTTPSDConfig(promStore, monitoringv1alpha1.ScrapeConfigsKind, sc.Namespace, sc.Name))
+		}
 
-			configs[i] = append(configs[i], yaml.MapItem{
+		for _, config := range sc.Spec.HTTPSDConfigs {
+			var httpSDConfig yaml.MapSlice
+			httpSDConfig = cg.addBasicAuthToYaml(httpSDConfig, s, config.BasicAuth)
+			httpSDConfig = cg.addSafeAuthorizationToYaml(httpSDConfig, s, config.Authorization)
+			httpSDConfig = cg.addSafeTLStoYaml(httpSDConfig, s, config.TLSConfig)
+			httpSDConfig = cg.addProxyConfigtoYaml(httpSDConfig, s, config.ProxyConfig)
+			httpSDConfig = cg.addOAuth2ToYaml(httpSDConfig, s, config.OAuth2)
+
+			httpSDConfig = append(httpSDConfig, yaml.MapItem{
 				Key:   "url",
 				Value: config.URL,
 			})
 
 			if config.RefreshInterval != nil {
-				configs[i] = append(configs[i], yaml.MapItem{
+				httpSDConfig = append(httpSDConfig, yaml.MapItem{
 					Key:   "refresh_interval",
 					Value: config.RefreshInterval,
 				})
 			}
 
 			if config.FollowRedirects != nil {
-				configs[i] = append(configs[i], yaml.MapItem{
+				httpSDConfig = append(httpSDConfig, yaml.MapItem{
 					Key:   "follow_redirects",
 					Value: config.FollowRedirects,
 				})
 			}
 
 			if config.EnableHTTP2 != nil {
-				configs[i] = append(configs[i], yaml.MapItem{
+				httpSDConfig = append(httpSDConfig, yaml.MapItem{
 					Key:   "enable_http2",
 					Value: config.EnableHTTP2,
 				})
 			}
+
+			configs = append(configs, httpSDConfig)
 		}
 		cfg = append(cfg, yaml.MapItem{
 			Key:   "http_sd_configs",
@@ -5066,7 +5674,10 @@ func (cg *ConfigGenerator) generateScrapeConfig(
 		relabelings = append(relabelings, generateRelabelConfig(labeler.GetRelabelingConfigs(sc.TypeMeta, sc.ObjectMeta, sc.Spec.RelabelConfigs))...)
 	}
 
-	if shards != 1 {
+	switch {
+	case PerShardConfiguration(cg.prom):
+		relabelings = cg.appendInRangeShardRelabelings(relabelings, shards)
+	case shards != 1:
 		relabelings = cg.appendShardingRelabelingWithAddressIfMissing(relabelings, shards)
 	}
 
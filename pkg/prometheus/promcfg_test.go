@@ -1486,6 +1486,57 @@ func TestAlertmanagerTimeoutConfig(t *testing.T) {
 	require.NoError(t, err)
 	golden.Assert(t, string(cfg), "AlertmanagerTimeoutConfig.golden")
 }
+func TestAlertmanagerDiscoveryConfigs(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.Alerting = &monitoringv1.AlertingSpec{
+		Alertmanagers: []monitoringv1.AlertmanagerEndpoints{
+			{
+				StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{
+					{Targets: []string{"alertmanager-0.example.com:9093", "alertmanager-1.example.com:9093"}},
+				},
+				Scheme:     ptr.To(monitoringv1.SchemeHTTPS),
+				APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+			},
+			{
+				DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{
+					{
+						Names: []string{"_web._tcp.alertmanager.example.com"},
+					},
+					{
+						Names:           []string{"alertmanager.example.com"},
+						Type:            ptr.To(monitoringv1.AlertmanagerDNSRecordTypeA),
+						Port:            ptr.To(int32(9093)),
+						RefreshInterval: ptr.To(monitoringv1.Duration("1m")),
+					},
+				},
+				RelabelConfigs: []monitoringv1.RelabelConfig{
+					{
+						Action:       "drop",
+						SourceLabels: []monitoringv1.LabelName{"__address__"},
+						Regex:        "10\\..*",
+					},
+				},
+			},
+		},
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	cfg, err := cg.GenerateServerConfiguration(
+		p,
+		nil,
+		nil,
+		nil,
+		nil,
+		&assets.StoreBuilder{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	golden.Assert(t, string(cfg), "AlertmanagerDiscoveryConfigs.golden")
+}
+
 func TestAlertmanagerEnableHttp2(t *testing.T) {
 	for _, tc := range []struct {
 		version     string
@@ -4744,6 +4795,37 @@ func TestRemoteWriteConfig(t *testing.T) {
 			},
 			golden: "RemoteWriteConfig_MessageVersion2_with_metadata.golden",
 		},
+		{
+			version: "v2.55.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				GoogleIAM: &monitoringv1.GoogleIAM{
+					Credentials: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "gcp-credentials",
+						},
+						Key: "credentials.json",
+					},
+				},
+			},
+			golden: "RemoteWriteConfigGoogleIAM_v2.55.0.golden",
+		},
+		{
+			version: "v2.55.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:       "http://example.com",
+				GoogleIAM: &monitoringv1.GoogleIAM{},
+			},
+			golden: "RemoteWriteConfigGoogleIAMDefaultCredentials_v2.55.0.golden",
+		},
+		{
+			version: "v2.54.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:       "http://example.com",
+				GoogleIAM: &monitoringv1.GoogleIAM{},
+			},
+			golden: "RemoteWriteConfigGoogleIAM_v2.54.0.golden",
+		},
 	} {
 		t.Run(fmt.Sprintf("i=%d,version=%s", i, tc.version), func(t *testing.T) {
 			p := defaultPrometheus()
@@ -13893,6 +13975,139 @@ func TestScrapeClassFallbackScrapeProtocol(t *testing.T) {
 	}
 }
 
+func TestScrapeClassLimitsAndIntervals(t *testing.T) {
+	bulkScrapeClass := monitoringv1.ScrapeClass{
+		Name:                  "bulk",
+		ScrapeInterval:        ptr.To(monitoringv1.Duration("2m")),
+		ScrapeTimeout:         ptr.To(monitoringv1.Duration("1m")),
+		SampleLimit:           ptr.To(int64(100000)),
+		TargetLimit:           ptr.To(int64(1000)),
+		LabelLimit:            ptr.To(int64(50)),
+		LabelNameLengthLimit:  ptr.To(int64(100)),
+		LabelValueLengthLimit: ptr.To(int64(200)),
+		BodySizeLimit:         ptr.To(monitoringv1.ByteSize("50MB")),
+	}
+	defaultBulkScrapeClass := *bulkScrapeClass.DeepCopy()
+	defaultBulkScrapeClass.Default = new(true)
+
+	serviceMonitorWithoutInterval := defaultServiceMonitor()
+	serviceMonitorWithoutInterval.Spec.Endpoints[0].Interval = ""
+
+	serviceMonitorWithLimits := defaultServiceMonitor()
+	serviceMonitorWithLimits.Spec.Endpoints[0].ScrapeTimeout = "10s"
+	serviceMonitorWithLimits.Spec.SampleLimit = ptr.To(int64(500))
+	serviceMonitorWithLimits.Spec.LabelLimit = ptr.To(int64(0))
+	serviceMonitorWithLimits.Spec.BodySizeLimit = ptr.To(monitoringv1.ByteSize("10MB"))
+
+	podMonitorWithScrapeClass := defaultPodMonitor()
+	podMonitorWithScrapeClass.Spec.ScrapeClassName = new("bulk")
+	probeWithScrapeClass := defaultProbe()
+	probeWithScrapeClass.Spec.ScrapeClassName = new("bulk")
+	scrapeConfigWithScrapeClass := defaultScrapeConfig()
+	scrapeConfigWithScrapeClass.Spec.ScrapeClassName = new("bulk")
+
+	for _, tc := range []struct {
+		name            string
+		scrapeClasses   []monitoringv1.ScrapeClass
+		enforcedLimits  func(*monitoringv1.CommonPrometheusFields)
+		serviceMonitors map[string]*monitoringv1.ServiceMonitor
+		podMonitors     map[string]*monitoringv1.PodMonitor
+		probes          map[string]*monitoringv1.Probe
+		scrapeConfigs   map[string]*monitoringv1alpha1.ScrapeConfig
+		goldenFile      string
+	}{
+		{
+			name:            "ServiceMonitor with default ScrapeClass limits and intervals",
+			scrapeClasses:   []monitoringv1.ScrapeClass{defaultBulkScrapeClass},
+			serviceMonitors: map[string]*monitoringv1.ServiceMonitor{"monitor": serviceMonitorWithoutInterval},
+			goldenFile:      "serviceMonitorObjectWithDefaultScrapeClassWithLimitsAndIntervals.golden",
+		},
+		{
+			// The scrape class timeout (1m) is greater than the ServiceMonitor's interval (30s) and should be ignored.
+			name:            "ServiceMonitor with scrape interval lower than the ScrapeClass timeout",
+			scrapeClasses:   []monitoringv1.ScrapeClass{defaultBulkScrapeClass},
+			serviceMonitors: map[string]*monitoringv1.ServiceMonitor{"monitor": defaultServiceMonitor()},
+			goldenFile:      "serviceMonitorObjectWithDefaultScrapeClassWithIgnoredScrapeTimeout.golden",
+		},
+		{
+			name:            "ServiceMonitor overriding ScrapeClass limits and intervals",
+			scrapeClasses:   []monitoringv1.ScrapeClass{defaultBulkScrapeClass},
+			serviceMonitors: map[string]*monitoringv1.ServiceMonitor{"monitor": serviceMonitorWithLimits},
+			goldenFile:      "serviceMonitorObjectOverridingScrapeClassLimitsAndIntervals.golden",
+		},
+		{
+			name:          "ServiceMonitor with ScrapeClass limits and lower enforced limits",
+			scrapeClasses: []monitoringv1.ScrapeClass{defaultBulkScrapeClass},
+			enforcedLimits: func(cpf *monitoringv1.CommonPrometheusFields) {
+				cpf.Version = "v2.44.0"
+				cpf.EnforcedSampleLimit = ptr.To(int64(1000))
+				cpf.EnforcedTargetLimit = ptr.To(int64(10000))
+				cpf.EnforcedBodySizeLimit = "1MB"
+			},
+			serviceMonitors: map[string]*monitoringv1.ServiceMonitor{"monitor": serviceMonitorWithoutInterval},
+			goldenFile:      "serviceMonitorObjectWithDefaultScrapeClassLimitsAndEnforcedLimits.golden",
+		},
+		{
+			name:          "PodMonitor with non-default ScrapeClass limits and intervals",
+			scrapeClasses: []monitoringv1.ScrapeClass{bulkScrapeClass},
+			podMonitors:   map[string]*monitoringv1.PodMonitor{"monitor": podMonitorWithScrapeClass},
+			goldenFile:    "podMonitorObjectWithNonDefaultScrapeClassWithLimitsAndIntervals.golden",
+		},
+		{
+			name:          "Probe with non-default ScrapeClass limits and intervals",
+			scrapeClasses: []monitoringv1.ScrapeClass{bulkScrapeClass},
+			probes:        map[string]*monitoringv1.Probe{"monitor": probeWithScrapeClass},
+			goldenFile:    "probeObjectWithNonDefaultScrapeClassWithLimitsAndIntervals.golden",
+		},
+		{
+			name:          "ScrapeConfig with non-default ScrapeClass limits and intervals",
+			scrapeClasses: []monitoringv1.ScrapeClass{bulkScrapeClass},
+			scrapeConfigs: map[string]*monitoringv1alpha1.ScrapeConfig{"monitor": scrapeConfigWithScrapeClass},
+			goldenFile:    "scrapeConfigObjectWithNonDefaultScrapeClassWithLimitsAndIntervals.golden",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := defaultPrometheus()
+			p.Spec.ScrapeClasses = tc.scrapeClasses
+			if tc.enforcedLimits != nil {
+				tc.enforcedLimits(&p.Spec.CommonPrometheusFields)
+			}
+			cg := mustNewConfigGenerator(t, p)
+
+			cfg, err := cg.GenerateServerConfiguration(
+				p,
+				tc.serviceMonitors,
+				tc.podMonitors,
+				tc.probes,
+				tc.scrapeConfigs,
+				&assets.StoreBuilder{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			require.NoError(t, err)
+			golden.Assert(t, string(cfg), tc.goldenFile)
+		})
+	}
+}
+
+func TestScrapeClassInvalidScrapeTimeout(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.ScrapeClasses = []monitoringv1.ScrapeClass{
+		{
+			Name:           "invalid",
+			ScrapeInterval: ptr.To(monitoringv1.Duration("10s")),
+			ScrapeTimeout:  ptr.To(monitoringv1.Duration("20s")),
+		},
+	}
+
+	_, err := NewConfigGenerator(newLogger(), p)
+	require.Error(t, err)
+	require.Equal(t, `failed to parse scrape classes: invalid scrape timeout for scrapeClass invalid: scrapeTimeout "20s" greater than scrapeInterval "10s"`, err.Error())
+}
+
 func TestGenerateAlertmanagerConfig(t *testing.T) {
 	for _, tc := range []struct {
 		alerting *monitoringv1.AlertingSpec
@@ -15114,3 +15329,101 @@ func TestInzoneShardForShard(t *testing.T) {
 		})
 	}
 }
+
+func TestScrapeConfigRenderingPerResource(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		version    string
+		goldenFile string
+		files      []string
+	}{
+		{
+			name:       "per-resource files",
+			version:    operator.DefaultPrometheusVersion,
+			goldenFile: "ScrapeConfigRenderingPerResource.golden",
+			files: []string{
+				"podmonitor_default_defaultPodMonitor.yaml",
+				"probe_default_defaultProbe.yaml",
+				"scrapeconfig_default_defaultScrapeConfig.yaml",
+				"servicemonitor_default_defaultServiceMonitor.yaml",
+			},
+		},
+		{
+			name:       "unsupported version falls back to inline",
+			version:    "v2.42.0",
+			goldenFile: "ScrapeConfigRenderingPerResourceUnsupportedVersion.golden",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := defaultPrometheus()
+			p.Spec.Version = tc.version
+			p.Spec.ScrapeConfigRendering = ptr.To(monitoringv1.PerResourceScrapeConfigRendering)
+
+			var (
+				cg     = mustNewConfigGenerator(t, p)
+				sMons  = map[string]*monitoringv1.ServiceMonitor{"default/defaultServiceMonitor": defaultServiceMonitor()}
+				pMons  = map[string]*monitoringv1.PodMonitor{"default/defaultPodMonitor": defaultPodMonitor()}
+				probes = map[string]*monitoringv1.Probe{"default/defaultProbe": defaultProbe()}
+				sCons  = map[string]*monitoringv1alpha1.ScrapeConfig{"default/defaultScrapeConfig": defaultScrapeConfig()}
+			)
+
+			cfg, err := cg.GenerateServerConfiguration(
+				p,
+				sMons,
+				pMons,
+				probes,
+				sCons,
+				&assets.StoreBuilder{},
+				[]byte(`- job_name: additional`),
+				nil,
+				nil,
+				nil,
+			)
+			require.NoError(t, err)
+			golden.Assert(t, string(cfg), tc.goldenFile)
+
+			if !cg.ScrapeConfigFilesEnabled(p) {
+				require.Empty(t, tc.files)
+				return
+			}
+
+			files, err := cg.GenerateScrapeConfigFiles(sMons, pMons, probes, sCons, &assets.StoreBuilder{})
+			require.NoError(t, err)
+			require.Len(t, files, len(tc.files))
+			for _, name := range tc.files {
+				require.Contains(t, files, name)
+				golden.Assert(t, string(files[name]), "ScrapeConfigRenderingPerResource_"+name+".golden")
+			}
+		})
+	}
+}
+
+func TestScrapeConfigRenderingPerResourceShardVariables(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.Shards = ptr.To(int32(2))
+	p.Spec.ScrapeConfigRendering = ptr.To(monitoringv1.PerResourceScrapeConfigRendering)
+	p.Spec.OperatorServiceDiscovery = &monitoringv1.OperatorServiceDiscovery{}
+
+	var (
+		cg     = mustNewConfigGenerator(t, p, WithOperatorHTTPSD("http://prometheus-operator:8081", newTestHTTPSDRegistry()))
+		sMons  = map[string]*monitoringv1.ServiceMonitor{"default/defaultServiceMonitor": defaultServiceMonitor()}
+		probes = map[string]*monitoringv1.Probe{"default/defaultProbe": defaultProbe()}
+	)
+
+	files, err := cg.GenerateScrapeConfigFiles(sMons, nil, probes, nil, &assets.StoreBuilder{})
+	require.NoError(t, err)
+	require.Contains(t, string(files["servicemonitor_default_defaultServiceMonitor.yaml"]), "$(SHARD)")
+	require.Contains(t, string(files["probe_default_defaultProbe.yaml"]), "$(SHARD)")
+
+	for shard := range int32(2) {
+		expanded := cg.ExpandShardVariables(files, shard)
+		require.Len(t, expanded, len(files))
+
+		for name, b := range expanded {
+			require.NotContains(t, string(b), "$(", "file %s", name)
+		}
+
+		require.Contains(t, string(expanded["servicemonitor_default_defaultServiceMonitor.yaml"]), fmt.Sprintf("regex: %d;|.+;.+", shard))
+		require.Contains(t, string(expanded["probe_default_defaultProbe.yaml"]), fmt.Sprintf("/shards/%d/", shard))
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+const (
+	webPort = 9090
+
+	remoteWritePath = "/api/v1/write"
+	remoteReadPath  = "/api/v1/read"
+)
+
+// Endpoint describes how to reach the HTTP API of the pods of a Prometheus
+// object.
+type Endpoint struct {
+	// Scheme is either "http" or "https".
+	Scheme string
+	// PathPrefix is the HTTP route prefix of the Prometheus API.
+	PathPrefix string
+	// Addresses are the addresses (<host>:<port>) of the Prometheus pods.
+	Addresses []string
+	// TLSConfig is the TLS configuration trusting the serving certificate
+	// of the Prometheus pods. It is nil when web TLS isn't enabled or when
+	// the certificate can't be trusted automatically.
+	TLSConfig *monitoringv1.SafeTLSConfig
+}
+
+// URLs returns the URLs of the given API path for each Prometheus pod.
+func (e Endpoint) URLs(apiPath string) []monitoringv1.URL {
+	urls := make([]monitoringv1.URL, 0, len(e.Addresses))
+	for _, addr := range e.Addresses {
+		u := url.URL{
+			Scheme: e.Scheme,
+			Host:   addr,
+			Path:   path.Join(e.PathPrefix, apiPath),
+		}
+		urls = append(urls, monitoringv1.URL(u.String()))
+	}
+
+	return urls
+}
+
+// EndpointForReference returns the endpoint of the referenced Prometheus
+// object with the addresses of all the replicas.
+//
+// The serving certificate is trusted only if the Prometheus lives in
+// namespace (the namespace of the referencing object) because the TLS assets
+// can't be read from other namespaces.
+func EndpointForReference(p *monitoringv1.Prometheus, namespace string) (Endpoint, error) {
+	if ShardsNumber(p) > 1 {
+		return Endpoint{}, fmt.Errorf("prometheus %s/%s is sharded", p.Namespace, p.Name)
+	}
+
+	e, err := endpointForReference(p, namespace)
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	for i := range *ReplicasNumberPtr(p) {
+		e.Addresses = append(e.Addresses, podAddress(p, 0, i))
+	}
+
+	return e, nil
+}
+
+// shardsEndpointForReference returns the endpoint of the referenced
+// Prometheus object with the address of the first replica of each shard.
+// Because the replicas of a shard hold the same data, it is the endpoint to
+// use for reading the data.
+func shardsEndpointForReference(p *monitoringv1.Prometheus, namespace string) (Endpoint, error) {
+	e, err := endpointForReference(p, namespace)
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	if *ReplicasNumberPtr(p) == 0 {
+		return e, nil
+	}
+
+	for shard := range ShardsNumber(p) {
+		e.Addresses = append(e.Addresses, podAddress(p, shard, 0))
+	}
+
+	return e, nil
+}
+
+func endpointForReference(p *monitoringv1.Prometheus, namespace string) (Endpoint, error) {
+	if p.Spec.ListenLocal {
+		return Endpoint{}, fmt.Errorf("prometheus %s/%s listens on localhost only", p.Namespace, p.Name)
+	}
+
+	svc := ptr.Deref(p.Spec.ServiceName, ServerGoverningServiceName)
+	e := Endpoint{
+		Scheme:     p.Spec.PrometheusURIScheme(),
+		PathPrefix: p.Spec.WebRoutePrefix(),
+	}
+
+	if p.Spec.Web != nil && p.Spec.Web.TLSConfig != nil && p.Namespace == namespace {
+		cert := p.Spec.Web.TLSConfig.Cert
+		if cert.Secret != nil || cert.ConfigMap != nil {
+			e.TLSConfig = &monitoringv1.SafeTLSConfig{
+				CA:         *cert.DeepCopy(),
+				ServerName: ptr.To(fmt.Sprintf("%s.%s.svc", svc, p.Namespace)),
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// podAddress returns the address (<host>:<port>) of the Prometheus pod for
+// the given shard and replica.
+func podAddress(p *monitoringv1.Prometheus, shard, replica int32) string {
+	svc := ptr.Deref(p.Spec.ServiceName, ServerGoverningServiceName)
+	return fmt.Sprintf("%s-%d.%s.%s.svc:%d", prometheusNameByShard(p, shard), replica, svc, p.Namespace, webPort)
+}
+
+// RemoteWriteSpecsForReference returns the remote-write configurations
+// targeting the receiver endpoint of each pod of the referenced Prometheus
+// object.
+func RemoteWriteSpecsForReference(rw monitoringv1.RemoteWriteSpec, p *monitoringv1.Prometheus, namespace string) ([]monitoringv1.RemoteWriteSpec, error) {
+	if !p.Spec.EnableRemoteWriteReceiver {
+		return nil, fmt.Errorf("prometheus %s/%s doesn't enable the remote-write receiver", p.Namespace, p.Name)
+	}
+
+	e, err := EndpointForReference(p, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := e.URLs(remoteWritePath)
+	ret := make([]monitoringv1.RemoteWriteSpec, 0, len(urls))
+	for i, u := range urls {
+		spec := *rw.DeepCopy()
+		spec.PrometheusRef = nil
+		spec.URL = u
+		if spec.Name != nil && len(urls) > 1 {
+			spec.Name = ptr.To(fmt.Sprintf("%s-%d", *spec.Name, i))
+		}
+
+		if spec.TLSConfig == nil && e.TLSConfig != nil {
+			spec.TLSConfig = &monitoringv1.TLSConfig{SafeTLSConfig: *e.TLSConfig.DeepCopy()}
+		}
+
+		ret = append(ret, spec)
+	}
+
+	return ret, nil
+}
+
+// RemoteReadSpecsForReference returns the remote-read configurations
+// targeting the read endpoint of each shard of the referenced Prometheus
+// object. Reading from all the replicas would only return duplicated series.
+func RemoteReadSpecsForReference(rr monitoringv1.RemoteReadSpec, p *monitoringv1.Prometheus, namespace string) ([]monitoringv1.RemoteReadSpec, error) {
+	e, err := shardsEndpointForReference(p, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := e.URLs(remoteReadPath)
+	ret := make([]monitoringv1.RemoteReadSpec, 0, len(urls))
+	for i, u := range urls {
+		spec := *rr.DeepCopy()
+		spec.PrometheusRef = nil
+		spec.URL = u
+		if spec.Name != "" && len(urls) > 1 {
+			spec.Name = fmt.Sprintf("%s-%d", spec.Name, i)
+		}
+
+		if spec.TLSConfig == nil && e.TLSConfig != nil {
+			spec.TLSConfig = &monitoringv1.TLSConfig{SafeTLSConfig: *e.TLSConfig.DeepCopy()}
+		}
+
+		ret = append(ret, spec)
+	}
+
+	return ret, nil
+}
+
+// ResolveRemoteWriteReferences returns the remote-write configurations with
+// the Prometheus references replaced by the configurations derived from the
+// referenced objects. It fails if a referenced object doesn't exist.
+func ResolveRemoteWriteReferences(infs *informers.ForResource, namespace string, rws []monitoringv1.RemoteWriteSpec) ([]monitoringv1.RemoteWriteSpec, error) {
+	ret := make([]monitoringv1.RemoteWriteSpec, 0, len(rws))
+	for i, rw := range rws {
+		if rw.PrometheusRef == nil {
+			ret = append(ret, rw)
+			continue
+		}
+
+		p, err := GetReferencedPrometheus(infs, namespace, *rw.PrometheusRef)
+		if err != nil {
+			return nil, fmt.Errorf("remoteWrite[%d]: %w", i, err)
+		}
+
+		specs, err := RemoteWriteSpecsForReference(rw, p, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("remoteWrite[%d]: %w", i, err)
+		}
+
+		ret = append(ret, specs...)
+	}
+
+	return ret, nil
+}
+
+// ResolveRemoteReadReferences returns the remote-read configurations with
+// the Prometheus references replaced by the configurations derived from the
+// referenced objects. It fails if a referenced object doesn't exist.
+func ResolveRemoteReadReferences(infs *informers.ForResource, namespace string, rrs []monitoringv1.RemoteReadSpec) ([]monitoringv1.RemoteReadSpec, error) {
+	ret := make([]monitoringv1.RemoteReadSpec, 0, len(rrs))
+	for i, rr := range rrs {
+		if rr.PrometheusRef == nil {
+			ret = append(ret, rr)
+			continue
+		}
+
+		p, err := GetReferencedPrometheus(infs, namespace, *rr.PrometheusRef)
+		if err != nil {
+			return nil, fmt.Errorf("remoteRead[%d]: %w", i, err)
+		}
+
+		specs, err := RemoteReadSpecsForReference(rr, p, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("remoteRead[%d]: %w", i, err)
+		}
+
+		ret = append(ret, specs...)
+	}
+
+	return ret, nil
+}
+
+// GetReferencedPrometheus returns the Prometheus object referenced by ref
+// from the referencing namespace. It fails if the object doesn't exist.
+func GetReferencedPrometheus(infs *informers.ForResource, namespace string, ref monitoringv1.PrometheusReference) (*monitoringv1.Prometheus, error) {
+	ns := ptr.Deref(ref.Namespace, namespace)
+
+	p, err := operator.GetObjectFromKey[*monitoringv1.Prometheus](infs, ns+"/"+ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, fmt.Errorf("prometheus %s/%s not found", ns, ref.Name)
+	}
+
+	return p, nil
+}
+
+// ReferencesNamespace returns true if any of the references made from the
+// referencing namespace points to a Prometheus object in namespace.
+func ReferencesNamespace(referencingNamespace, namespace string, refs ...*monitoringv1.PrometheusReference) bool {
+	for _, ref := range refs {
+		if ref != nil && ptr.Deref(ref.Namespace, referencingNamespace) == namespace {
+			return true
+		}
+	}
+
+	return false
+}
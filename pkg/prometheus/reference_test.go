@@ -0,0 +1,271 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestEndpointForReference(t *testing.T) {
+	webTLS := &monitoringv1.PrometheusWebSpec{
+		WebConfigFileFields: monitoringv1.WebConfigFileFields{
+			TLSConfig: &monitoringv1.WebTLSConfig{
+				Cert: monitoringv1.SecretOrConfigMap{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "web-tls"},
+						Key:                  "tls.crt",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		spec      monitoringv1.PrometheusSpec
+		namespace string
+
+		exp Endpoint
+		err bool
+	}{
+		{
+			name:      "default",
+			namespace: "default",
+			exp: Endpoint{
+				Scheme:     "http",
+				PathPrefix: "/",
+				Addresses:  []string{"prometheus-main-0.prometheus-operated.monitoring.svc:9090"},
+			},
+		},
+		{
+			name: "replicas and route prefix",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Replicas:    ptr.To(int32(2)),
+					RoutePrefix: "/prometheus",
+					ServiceName: ptr.To("custom"),
+				},
+			},
+			namespace: "default",
+			exp: Endpoint{
+				Scheme:     "http",
+				PathPrefix: "/prometheus",
+				Addresses: []string{
+					"prometheus-main-0.custom.monitoring.svc:9090",
+					"prometheus-main-1.custom.monitoring.svc:9090",
+				},
+			},
+		},
+		{
+			name: "web TLS in the same namespace",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Web: webTLS,
+				},
+			},
+			namespace: "monitoring",
+			exp: Endpoint{
+				Scheme:     "https",
+				PathPrefix: "/",
+				Addresses:  []string{"prometheus-main-0.prometheus-operated.monitoring.svc:9090"},
+				TLSConfig: &monitoringv1.SafeTLSConfig{
+					CA:         webTLS.TLSConfig.Cert,
+					ServerName: ptr.To("prometheus-operated.monitoring.svc"),
+				},
+			},
+		},
+		{
+			name: "web TLS in another namespace",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Web: webTLS,
+				},
+			},
+			namespace: "default",
+			exp: Endpoint{
+				Scheme:     "https",
+				PathPrefix: "/",
+				Addresses:  []string{"prometheus-main-0.prometheus-operated.monitoring.svc:9090"},
+			},
+		},
+		{
+			name: "listen local",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					ListenLocal: true,
+				},
+			},
+			err: true,
+		},
+		{
+			name: "sharded",
+			spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Shards: ptr.To(int32(2)),
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+				Spec:       tc.spec,
+			}
+
+			e, err := EndpointForReference(p, tc.namespace)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, e)
+		})
+	}
+}
+
+func TestRemoteWriteSpecsForReference(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Replicas:                  ptr.To(int32(2)),
+				EnableRemoteWriteReceiver: true,
+			},
+		},
+	}
+
+	rw := monitoringv1.RemoteWriteSpec{
+		Name:          ptr.To("central"),
+		PrometheusRef: &monitoringv1.PrometheusReference{Name: "main"},
+		SendExemplars: ptr.To(true),
+	}
+
+	rws, err := RemoteWriteSpecsForReference(rw, p, "monitoring")
+	require.NoError(t, err)
+	require.Equal(t, []monitoringv1.RemoteWriteSpec{
+		{
+			URL:           "http://prometheus-main-0.prometheus-operated.monitoring.svc:9090/api/v1/write",
+			Name:          ptr.To("central-0"),
+			SendExemplars: ptr.To(true),
+		},
+		{
+			URL:           "http://prometheus-main-1.prometheus-operated.monitoring.svc:9090/api/v1/write",
+			Name:          ptr.To("central-1"),
+			SendExemplars: ptr.To(true),
+		},
+	}, rws)
+
+	// The receiver must be enabled.
+	p.Spec.EnableRemoteWriteReceiver = false
+	_, err = RemoteWriteSpecsForReference(rw, p, "monitoring")
+	require.Error(t, err)
+}
+
+func TestRemoteReadSpecsForReference(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		replicas *int32
+		shards   *int32
+		exp      []monitoringv1.RemoteReadSpec
+	}{
+		{
+			name: "default",
+			exp: []monitoringv1.RemoteReadSpec{
+				{
+					URL:        "http://prometheus-main-0.prometheus-operated.monitoring.svc:9090/prometheus/api/v1/read",
+					Name:       "central",
+					ReadRecent: true,
+				},
+			},
+		},
+		{
+			name:     "2 replicas",
+			replicas: ptr.To(int32(2)),
+			exp: []monitoringv1.RemoteReadSpec{
+				{
+					URL:        "http://prometheus-main-0.prometheus-operated.monitoring.svc:9090/prometheus/api/v1/read",
+					Name:       "central",
+					ReadRecent: true,
+				},
+			},
+		},
+		{
+			name:     "2 replicas and 2 shards",
+			replicas: ptr.To(int32(2)),
+			shards:   ptr.To(int32(2)),
+			exp: []monitoringv1.RemoteReadSpec{
+				{
+					URL:        "http://prometheus-main-0.prometheus-operated.monitoring.svc:9090/prometheus/api/v1/read",
+					Name:       "central-0",
+					ReadRecent: true,
+				},
+				{
+					URL:        "http://prometheus-main-shard-1-0.prometheus-operated.monitoring.svc:9090/prometheus/api/v1/read",
+					Name:       "central-1",
+					ReadRecent: true,
+				},
+			},
+		},
+		{
+			name:     "0 replicas",
+			replicas: ptr.To(int32(0)),
+			exp:      []monitoringv1.RemoteReadSpec{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						RoutePrefix: "/prometheus",
+						Replicas:    tc.replicas,
+						Shards:      tc.shards,
+					},
+				},
+			}
+
+			rr := monitoringv1.RemoteReadSpec{
+				Name:          "central",
+				PrometheusRef: &monitoringv1.PrometheusReference{Name: "main"},
+				ReadRecent:    true,
+			}
+
+			rrs, err := RemoteReadSpecsForReference(rr, p, "default")
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, rrs)
+		})
+	}
+}
+
+func TestReferencesNamespace(t *testing.T) {
+	refs := []*monitoringv1.PrometheusReference{
+		nil,
+		{Name: "main"},
+		{Name: "other", Namespace: ptr.To("monitoring")},
+	}
+
+	require.True(t, ReferencesNamespace("default", "default", refs...))
+	require.True(t, ReferencesNamespace("default", "monitoring", refs...))
+	require.False(t, ReferencesNamespace("default", "apps", refs...))
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus/validation"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
 )
 
 const (
@@ -65,6 +66,28 @@ type ResourceSelector struct {
 	accessor           *operator.Accessor
 
 	eventRecorder *operator.EventRecorder
+	quotas        operator.MonitoringQuotas
+
+	report *selectionreport.Report
+}
+
+// ResourceSelectorOption customizes the ResourceSelector.
+type ResourceSelectorOption func(*ResourceSelector)
+
+// WithMonitoringQuotas enforces the MonitoringQuota limits when selecting
+// the resources.
+func WithMonitoringQuotas(quotas operator.MonitoringQuotas) ResourceSelectorOption {
+	return func(rs *ResourceSelector) {
+		rs.quotas = quotas
+	}
+}
+
+// WithSelectionReport records the selectors and the candidate resources
+// which aren't selected into the report.
+func WithSelectionReport(report *selectionreport.Report) ResourceSelectorOption {
+	return func(rs *ResourceSelector) {
+		rs.report = report
+	}
 }
 
 type ListAllByNamespaceFn func(namespace string, selector labels.Selector, appendFn cache.AppendFunc) error
@@ -76,6 +99,7 @@ func NewResourceSelector(
 	namespaceInformers cache.SharedIndexInformer,
 	metrics *operator.Metrics,
 	eventRecorder *operator.EventRecorder,
+	opts ...ResourceSelectorOption,
 ) (*ResourceSelector, error) {
 	promVersion := operator.StringValOrDefault(p.GetCommonPrometheusFields().Version, operator.DefaultPrometheusVersion)
 	version, err := semver.ParseTolerant(promVersion)
@@ -83,7 +107,7 @@ func NewResourceSelector(
 		return nil, fmt.Errorf("failed to parse Prometheus version: %w", err)
 	}
 
-	return &ResourceSelector{
+	rs := &ResourceSelector{
 		l:                  l,
 		p:                  p,
 		version:            version,
@@ -92,7 +116,13 @@ func NewResourceSelector(
 		metrics:            metrics,
 		eventRecorder:      eventRecorder,
 		accessor:           operator.NewAccessor(l),
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(rs)
+	}
+
+	return rs, nil
 }
 
 func selectObjects[T operator.ConfigurationResource](
@@ -142,18 +172,34 @@ func selectObjects[T operator.ConfigurationResource](
 		rejected int
 		valid    []string
 		res      = make(operator.TypedResourcesSelection[T], len(objects))
+		usage    = rs.quotas.NewQuotaUsage(kind)
 	)
 
-	for namespaceAndName, obj := range objects {
-		var reason string
-		o := obj.(T)
+	// Iterate by order of creation so that the quotas admit the oldest
+	// objects first.
+	for _, namespaceAndName := range operator.SortedByCreationTimestamp(objects) {
+		var (
+			reason string
+			obj    = objects[namespaceAndName]
+			o      = obj.(T)
+		)
 		err := checkFn(ctx, o)
-		if err != nil {
+		if err == nil {
+			err = usage.Admit(obj.(metav1.Object))
+		}
+
+		switch {
+		case errors.Is(err, operator.ErrQuotaExceeded):
+			rejected++
+			reason = operator.QuotaExceeded
+			logger.Warn("skipping object", "error", err.Error(), "object", namespaceAndName)
+			rs.eventRecorder.Eventf(obj, corev1.EventTypeWarning, operator.QuotaExceededEvent, selectingConfigurationResourcesAction, "%q was rejected because it exceeds the MonitoringQuota of the namespace: %v", namespaceAndName, err)
+		case err != nil:
 			rejected++
 			reason = operator.InvalidConfiguration
 			logger.Warn("skipping object", "error", err.Error(), "object", namespaceAndName)
 			rs.eventRecorder.Eventf(obj, corev1.EventTypeWarning, operator.InvalidConfigurationEvent, selectingConfigurationResourcesAction, "%q was rejected due to invalid configuration: %v", namespaceAndName, err)
-		} else {
+		default:
 			valid = append(valid, namespaceAndName)
 		}
 
@@ -162,6 +208,10 @@ func selectObjects[T operator.ConfigurationResource](
 
 	logger.Debug("valid objects selected", "objects", strings.Join(valid, ","))
 
+	if err := AddSelectionCandidates(rs.report, kind, namespaces, labelSelector, listFn, res); err != nil {
+		return nil, fmt.Errorf("failed to report the candidate objects: %w", err)
+	}
+
 	if pKey, ok := rs.accessor.MetaNamespaceKey(rs.p); ok {
 		rs.metrics.SetSelectedResources(pKey, kind, len(res))
 		rs.metrics.SetRejectedResources(pKey, kind, rejected)
@@ -190,6 +240,10 @@ func (rs *ResourceSelector) SelectServiceMonitors(ctx context.Context, listFn Li
 
 // checkServiceMonitor verifies that the ServiceMonitor object is valid.
 func (rs *ResourceSelector) checkServiceMonitor(ctx context.Context, sm *monitoringv1.ServiceMonitor) error {
+	if err := rs.checkScrapeLimits(sm.Namespace, sm.Spec.ScrapeClassName, sm.Spec.SampleLimit, sm.Spec.TargetLimit); err != nil {
+		return err
+	}
+
 	cpf := rs.p.GetCommonPrometheusFields()
 
 	if _, err := metav1.LabelSelectorAsSelector(&sm.Spec.Selector); err != nil {
@@ -238,7 +292,7 @@ func (rs *ResourceSelector) checkServiceMonitor(ctx context.Context, sm *monitor
 			return fmt.Errorf("%w: authorization: %w", epErr, err)
 		}
 
-		if err := validateScrapeIntervalAndTimeout(rs.p, endpoint.Interval, endpoint.ScrapeTimeout); err != nil {
+		if err := validateScrapeIntervalAndTimeout(rs.p, sm.Spec.ScrapeClassName, endpoint.Interval, endpoint.ScrapeTimeout); err != nil {
 			return fmt.Errorf("%w: %w", epErr, err)
 		}
 
@@ -288,16 +342,57 @@ func testForArbitraryFSAccess(e monitoringv1.Endpoint) error {
 	return nil
 }
 
-func validateScrapeIntervalAndTimeout(p monitoringv1.PrometheusInterface, scrapeInterval, scrapeTimeout monitoringv1.Duration) error {
+func validateScrapeIntervalAndTimeout(p monitoringv1.PrometheusInterface, scrapeClassName *string, scrapeInterval, scrapeTimeout monitoringv1.Duration) error {
 	if scrapeTimeout == "" {
 		return nil
 	}
+	if scrapeInterval == "" {
+		if sc, found := getScrapeClass(p, scrapeClassName); found && sc.ScrapeInterval != nil {
+			scrapeInterval = *sc.ScrapeInterval
+		}
+	}
 	if scrapeInterval == "" {
 		scrapeInterval = p.GetCommonPrometheusFields().ScrapeInterval
 	}
 	return CompareScrapeTimeoutToScrapeInterval(scrapeTimeout, scrapeInterval)
 }
 
+// checkScrapeLimits verifies that the effective sample and target limits of
+// a resource comply with the quota of its namespace. The effective limits
+// take into account the scrape class, the global limits and the enforced
+// limits like the config generator does.
+func (rs *ResourceSelector) checkScrapeLimits(namespace string, scrapeClassName *string, sampleLimit, targetLimit *int64) error {
+	var (
+		cpf   = rs.p.GetCommonPrometheusFields()
+		sc, _ = getScrapeClass(rs.p, scrapeClassName)
+	)
+
+	return rs.quotas.CheckScrapeLimits(
+		namespace,
+		effectiveScrapeLimit(rs.version, sampleLimit, sc.SampleLimit, cpf.SampleLimit, cpf.EnforcedSampleLimit),
+		effectiveScrapeLimit(rs.version, targetLimit, sc.TargetLimit, cpf.TargetLimit, cpf.EnforcedTargetLimit),
+	)
+}
+
+// getScrapeClass returns the scrape class matching the given name or the
+// default scrape class if the name is empty.
+func getScrapeClass(p monitoringv1.PrometheusInterface, name *string) (monitoringv1.ScrapeClass, bool) {
+	for _, c := range p.GetCommonPrometheusFields().ScrapeClasses {
+		if ptr.Deref(name, "") != "" {
+			if c.Name == *name {
+				return c, true
+			}
+			continue
+		}
+
+		if ptr.Deref(c.Default, false) {
+			return c, true
+		}
+	}
+
+	return monitoringv1.ScrapeClass{}, false
+}
+
 func validateScrapeClass(p monitoringv1.PrometheusInterface, sc *string) error {
 	if ptr.Deref(sc, "") == "" {
 		return nil
@@ -340,6 +435,10 @@ func (rs *ResourceSelector) SelectPodMonitors(ctx context.Context, listFn ListAl
 
 // checkPodMonitor verifies that the PodMonitor object is valid.
 func (rs *ResourceSelector) checkPodMonitor(ctx context.Context, pm *monitoringv1.PodMonitor) error {
+	if err := rs.checkScrapeLimits(pm.Namespace, pm.Spec.ScrapeClassName, pm.Spec.SampleLimit, pm.Spec.TargetLimit); err != nil {
+		return err
+	}
+
 	if _, err := metav1.LabelSelectorAsSelector(&pm.Spec.Selector); err != nil {
 		return fmt.Errorf("failed to parse label selector: %w", err)
 	}
@@ -350,7 +449,7 @@ func (rs *ResourceSelector) checkPodMonitor(ctx context.Context, pm *monitoringv
 
 	for i, endpoint := range pm.Spec.PodMetricsEndpoints {
 		epErr := fmt.Errorf("endpoint[%d]", i)
-		if err := validateScrapeIntervalAndTimeout(rs.p, endpoint.Interval, endpoint.ScrapeTimeout); err != nil {
+		if err := validateScrapeIntervalAndTimeout(rs.p, pm.Spec.ScrapeClassName, endpoint.Interval, endpoint.ScrapeTimeout); err != nil {
 			return fmt.Errorf("%w: %w", epErr, err)
 		}
 
@@ -432,6 +531,10 @@ func (rs *ResourceSelector) SelectProbes(ctx context.Context, listFn ListAllByNa
 
 // checkProbe verifies that the Probe object is valid.
 func (rs *ResourceSelector) checkProbe(ctx context.Context, probe *monitoringv1.Probe) error {
+	if err := rs.checkScrapeLimits(probe.Namespace, probe.Spec.ScrapeClassName, probe.Spec.SampleLimit, probe.Spec.TargetLimit); err != nil {
+		return err
+	}
+
 	if err := validateScrapeClass(rs.p, probe.Spec.ScrapeClassName); err != nil {
 		return fmt.Errorf("scrapeClassName: %w", err)
 	}
@@ -462,7 +565,7 @@ func (rs *ResourceSelector) checkProbe(ctx context.Context, probe *monitoringv1.
 		return fmt.Errorf("oauth2: %w", err)
 	}
 
-	if err := validateScrapeIntervalAndTimeout(rs.p, probe.Spec.Interval, probe.Spec.ScrapeTimeout); err != nil {
+	if err := validateScrapeIntervalAndTimeout(rs.p, probe.Spec.ScrapeClassName, probe.Spec.Interval, probe.Spec.ScrapeTimeout); err != nil {
 		return err
 	}
 
@@ -567,6 +670,10 @@ func (rs *ResourceSelector) SelectScrapeConfigs(ctx context.Context, listFn List
 
 // checkScrapeConfig verifies that the ScrapeConfig object is valid.
 func (rs *ResourceSelector) checkScrapeConfig(ctx context.Context, sc *monitoringv1alpha1.ScrapeConfig) error {
+	if err := rs.checkScrapeLimits(sc.Namespace, sc.Spec.ScrapeClassName, sc.Spec.SampleLimit, sc.Spec.TargetLimit); err != nil {
+		return err
+	}
+
 	if err := validateScrapeClass(rs.p, sc.Spec.ScrapeClassName); err != nil {
 		return err
 	}
@@ -600,7 +707,7 @@ func (rs *ResourceSelector) checkScrapeConfig(ctx context.Context, sc *monitorin
 		scrapeTimeout = *sc.Spec.ScrapeTimeout
 	}
 
-	if err := validateScrapeIntervalAndTimeout(rs.p, scrapeInterval, scrapeTimeout); err != nil {
+	if err := validateScrapeIntervalAndTimeout(rs.p, sc.Spec.ScrapeClassName, scrapeInterval, scrapeTimeout); err != nil {
 		return err
 	}
 
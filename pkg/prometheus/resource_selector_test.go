@@ -19,6 +19,7 @@ import (
 	"log/slog"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
@@ -33,6 +34,7 @@ import (
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
 )
 
 var (
@@ -597,10 +599,59 @@ func TestValidateScrapeIntervalAndTimeout(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			scenario: "only scrape timeout specified at service monitor spec and valid compared to scrape class scrapeInterval",
+			prometheus: monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						ScrapeInterval: "15s",
+						ScrapeClasses: []monitoringv1.ScrapeClass{
+							{
+								Name:           "slow",
+								ScrapeInterval: ptr.To(monitoringv1.Duration("2m")),
+							},
+						},
+					},
+				},
+			},
+			smSpec: monitoringv1.ServiceMonitorSpec{
+				ScrapeClassName: ptr.To("slow"),
+				Endpoints: []monitoringv1.Endpoint{
+					{
+						ScrapeTimeout: "60s",
+					},
+				},
+			},
+		},
+		{
+			scenario: "only scrape timeout specified at service monitor spec but invalid compared to default scrape class scrapeInterval",
+			prometheus: monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						ScrapeInterval: "2m",
+						ScrapeClasses: []monitoringv1.ScrapeClass{
+							{
+								Name:           "fast",
+								Default:        ptr.To(true),
+								ScrapeInterval: ptr.To(monitoringv1.Duration("10s")),
+							},
+						},
+					},
+				},
+			},
+			smSpec: monitoringv1.ServiceMonitorSpec{
+				Endpoints: []monitoringv1.Endpoint{
+					{
+						ScrapeTimeout: "60s",
+					},
+				},
+			},
+			expectedErr: true,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			for _, endpoint := range tc.smSpec.Endpoints {
-				err := validateScrapeIntervalAndTimeout(&tc.prometheus, endpoint.Interval, endpoint.ScrapeTimeout)
+				err := validateScrapeIntervalAndTimeout(&tc.prometheus, tc.smSpec.ScrapeClassName, endpoint.Interval, endpoint.ScrapeTimeout)
 				t.Logf("err %v", err)
 				if tc.expectedErr {
 					require.Error(t, err)
@@ -1209,6 +1260,342 @@ func TestSelectServiceMonitors(t *testing.T) {
 	}
 }
 
+func TestSelectServiceMonitorsWithQuotas(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+
+	quotas := operator.NewMonitoringQuotas([]*monitoringv1alpha1.MonitoringQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "test"},
+			Spec: monitoringv1alpha1.MonitoringQuotaSpec{
+				ServiceMonitors: ptr.To(int32(2)),
+				SampleLimit:     ptr.To(int64(1000)),
+			},
+		},
+	})
+
+	cs := fake.NewClientset()
+	rs, err := NewResourceSelector(
+		newLogger(),
+		p,
+		assets.NewStoreBuilder(cs.CoreV1(), cs.CoreV1()),
+		nil,
+		operator.NewMetrics(prometheus.NewPedanticRegistry()),
+		operator.NewFakeRecorder(10, p),
+		WithMonitoringQuotas(quotas),
+	)
+	require.NoError(t, err)
+
+	now := time.Now()
+	newServiceMonitor := func(name string, age time.Duration, sampleLimit *int64) *monitoringv1.ServiceMonitor {
+		return &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "test",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: monitoringv1.ServiceMonitorSpec{
+				SampleLimit: sampleLimit,
+			},
+		}
+	}
+
+	sms := []*monitoringv1.ServiceMonitor{
+		// Rejected because the sample limit isn't defined.
+		newServiceMonitor("no-sample-limit", 4*time.Hour, nil),
+		// Rejected because the sample limit is greater than the quota.
+		newServiceMonitor("high-sample-limit", 3*time.Hour, ptr.To(int64(2000))),
+		newServiceMonitor("oldest", 2*time.Hour, ptr.To(int64(1000))),
+		newServiceMonitor("old", time.Hour, ptr.To(int64(100))),
+		// Rejected because the namespace already has 2 ServiceMonitors.
+		newServiceMonitor("newest", 0, ptr.To(int64(100))),
+	}
+
+	selection, err := rs.SelectServiceMonitors(context.Background(), func(_ string, _ labels.Selector, appendFn cache.AppendFunc) error {
+		for _, sm := range sms {
+			appendFn(sm)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, selection, len(sms))
+
+	valid := selection.ValidResources()
+	require.Len(t, valid, 2)
+	require.Contains(t, valid, "test/oldest")
+	require.Contains(t, valid, "test/old")
+
+	for _, k := range []string{"test/no-sample-limit", "test/high-sample-limit", "test/newest"} {
+		res := selection[k]
+		conditions := res.Conditions()
+		require.Len(t, conditions, 1)
+		require.Equal(t, monitoringv1.ConditionFalse, conditions[0].Status, k)
+		require.Equal(t, operator.QuotaExceeded, conditions[0].Reason, k)
+	}
+}
+
+func TestSelectServiceMonitorsWithQuotaEffectiveLimits(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		update      func(*monitoringv1.Prometheus)
+		scrapeClass *string
+		sampleLimit *int64
+		expected    bool
+	}{
+		{
+			name:     "no limit",
+			expected: false,
+		},
+		{
+			name:        "explicit zero limit",
+			sampleLimit: ptr.To(int64(0)),
+			expected:    false,
+		},
+		{
+			name: "limit from the default scrape class",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.ScrapeClasses = []monitoringv1.ScrapeClass{{Name: "default", Default: ptr.To(true), SampleLimit: ptr.To(int64(500))}}
+			},
+			expected: true,
+		},
+		{
+			name: "limit from the scrape class greater than the quota",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.ScrapeClasses = []monitoringv1.ScrapeClass{{Name: "large", SampleLimit: ptr.To(int64(2000))}}
+			},
+			scrapeClass: ptr.To("large"),
+			expected:    false,
+		},
+		{
+			name: "resource limit takes precedence over the scrape class",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.ScrapeClasses = []monitoringv1.ScrapeClass{{Name: "large", SampleLimit: ptr.To(int64(2000))}}
+			},
+			scrapeClass: ptr.To("large"),
+			sampleLimit: ptr.To(int64(100)),
+			expected:    true,
+		},
+		{
+			name: "enforced limit without resource limit",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.EnforcedSampleLimit = ptr.To(int64(800))
+			},
+			expected: true,
+		},
+		{
+			name: "enforced limit lower than the resource limit",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.EnforcedSampleLimit = ptr.To(int64(800))
+			},
+			sampleLimit: ptr.To(int64(2000)),
+			expected:    true,
+		},
+		{
+			name: "enforced limit greater than the quota",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.EnforcedSampleLimit = ptr.To(int64(2000))
+			},
+			expected: false,
+		},
+		{
+			name: "global limit",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.SampleLimit = ptr.To(int64(900))
+			},
+			expected: true,
+		},
+		{
+			name: "global limit not supported by the Prometheus version",
+			update: func(p *monitoringv1.Prometheus) {
+				p.Spec.Version = "v2.44.0"
+				p.Spec.SampleLimit = ptr.To(int64(900))
+			},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+			}
+			if tc.update != nil {
+				tc.update(p)
+			}
+
+			quotas := operator.NewMonitoringQuotas([]*monitoringv1alpha1.MonitoringQuota{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "test"},
+					Spec: monitoringv1alpha1.MonitoringQuotaSpec{
+						SampleLimit: ptr.To(int64(1000)),
+					},
+				},
+			})
+
+			cs := fake.NewClientset()
+			rs, err := NewResourceSelector(
+				newLogger(),
+				p,
+				assets.NewStoreBuilder(cs.CoreV1(), cs.CoreV1()),
+				nil,
+				operator.NewMetrics(prometheus.NewPedanticRegistry()),
+				operator.NewFakeRecorder(10, p),
+				WithMonitoringQuotas(quotas),
+			)
+			require.NoError(t, err)
+
+			sm := &monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					ScrapeClassName: tc.scrapeClass,
+					SampleLimit:     tc.sampleLimit,
+				},
+			}
+
+			selection, err := rs.SelectServiceMonitors(context.Background(), func(_ string, _ labels.Selector, appendFn cache.AppendFunc) error {
+				appendFn(sm)
+				return nil
+			})
+			require.NoError(t, err)
+
+			if tc.expected {
+				require.Len(t, selection.ValidResources(), 1)
+			} else {
+				require.Empty(t, selection.ValidResources())
+			}
+		})
+	}
+}
+
+func TestSelectServiceMonitorsWithSelectionReport(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Shards: ptr.To(int32(2)),
+				ServiceMonitorSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"group": "a"},
+				},
+				ScrapeClasses: []monitoringv1.ScrapeClass{
+					{
+						Name:    "default-class",
+						Default: ptr.To(true),
+					},
+				},
+			},
+		},
+	}
+
+	report := NewSelectionReport(monitoringv1.PrometheusesKind, p)
+
+	cs := fake.NewClientset()
+	rs, err := NewResourceSelector(
+		newLogger(),
+		p,
+		assets.NewStoreBuilder(cs.CoreV1(), cs.CoreV1()),
+		nil,
+		operator.NewMetrics(prometheus.NewPedanticRegistry()),
+		operator.NewFakeRecorder(10, p),
+		WithSelectionReport(report),
+	)
+	require.NoError(t, err)
+
+	newServiceMonitor := func(namespace, name string, lbls map[string]string, scrapeClass *string) *monitoringv1.ServiceMonitor {
+		return &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    lbls,
+			},
+			Spec: monitoringv1.ServiceMonitorSpec{
+				ScrapeClassName: scrapeClass,
+				Endpoints:       []monitoringv1.Endpoint{{Port: "web"}, {Port: "metrics"}},
+			},
+		}
+	}
+
+	sms := []*monitoringv1.ServiceMonitor{
+		newServiceMonitor("test", "accepted", map[string]string{"group": "a"}, nil),
+		newServiceMonitor("test", "rejected", map[string]string{"group": "a"}, ptr.To("missing")),
+		newServiceMonitor("test", "unmatched-labels", nil, nil),
+		newServiceMonitor("other", "unmatched-namespace", map[string]string{"group": "a"}, nil),
+	}
+
+	selection, err := rs.SelectServiceMonitors(context.Background(), func(namespace string, selector labels.Selector, appendFn cache.AppendFunc) error {
+		for _, sm := range sms {
+			if namespace != metav1.NamespaceAll && namespace != sm.Namespace {
+				continue
+			}
+
+			if selector.Matches(labels.Set(sm.Labels)) {
+				appendFn(sm)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []selectionreport.Selector{
+		{
+			Kind:          monitoringv1.ServiceMonitorsKind,
+			Namespaces:    []string{"test"},
+			LabelSelector: "group=a",
+		},
+	}, report.Selectors)
+
+	cg, err := NewConfigGenerator(newLogger(), p)
+	require.NoError(t, err)
+	cg.AddScrapeResources(report, selection, nil, nil, nil)
+
+	// All the candidate objects are reported.
+	resources := map[string]selectionreport.Resource{}
+	for _, res := range report.Resources {
+		resources[res.Namespace+"/"+res.Name] = res
+	}
+	require.Len(t, resources, 4)
+
+	require.Equal(t, selectionreport.Resource{
+		Kind:                     monitoringv1.ServiceMonitorsKind,
+		Namespace:                "test",
+		Name:                     "accepted",
+		NamespaceSelectorMatched: true,
+		LabelSelectorMatched:     true,
+		Status:                   selectionreport.AcceptedResourceStatus,
+		ScrapeClass:              "default-class",
+		Shards:                   []int32{0, 1},
+		JobNames:                 []string{"serviceMonitor/test/accepted/0", "serviceMonitor/test/accepted/1"},
+	}, resources["test/accepted"])
+
+	rejected := resources["test/rejected"]
+	require.Equal(t, selectionreport.RejectedResourceStatus, rejected.Status)
+	require.Equal(t, operator.InvalidConfiguration, rejected.Reason)
+	require.NotEmpty(t, rejected.Message)
+	require.Empty(t, rejected.JobNames)
+	require.True(t, rejected.NamespaceSelectorMatched)
+	require.True(t, rejected.LabelSelectorMatched)
+
+	unmatched := resources["test/unmatched-labels"]
+	require.Equal(t, selectionreport.NotSelectedResourceStatus, unmatched.Status)
+	require.True(t, unmatched.NamespaceSelectorMatched)
+	require.False(t, unmatched.LabelSelectorMatched)
+
+	unmatched = resources["other/unmatched-namespace"]
+	require.Equal(t, selectionreport.NotSelectedResourceStatus, unmatched.Status)
+	require.False(t, unmatched.NamespaceSelectorMatched)
+	require.True(t, unmatched.LabelSelectorMatched)
+}
+
 func TestSelectPodMonitors(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
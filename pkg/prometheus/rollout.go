@@ -0,0 +1,526 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	sortutil "github.com/prometheus-operator/prometheus-operator/internal/sortutil"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+const (
+	// RolloutStartedAtAnnotationKey records when the operator updated the
+	// statefulset of a shard as part of a progressive rollout. It is removed
+	// once the shard is rolled out.
+	RolloutStartedAtAnnotationKey = "operator.prometheus.io/rollout-started-at"
+
+	// RolloutConfigReloadAnnotationKey records that the configuration of the
+	// shard has changed as part of a progressive rollout. The shard is rolled
+	// out only when its pods have reloaded the configuration successfully.
+	RolloutConfigReloadAnnotationKey = "operator.prometheus.io/rollout-config-reload"
+
+	// ConfigDigestAnnotationKey records the digest of the configuration
+	// files written for the shard when the configuration changes are rolled
+	// out shard by shard.
+	ConfigDigestAnnotationKey = "operator.prometheus.io/config-digest"
+
+	// ShardRolloutRequeueInterval is the interval at which the resource is
+	// reconciled again while a progressive rollout is in progress.
+	ShardRolloutRequeueInterval = 15 * time.Second
+
+	defaultShardRolloutProgressDeadline = 10 * time.Minute
+)
+
+// ShardRollout decides which shards can be updated when a shard rollout
+// strategy is defined.
+type ShardRollout struct {
+	strategy   *monitoringv1.ShardRolloutStrategy
+	deadline   time.Duration
+	now        time.Time
+	shards     int
+	generation int64
+
+	// Number of available shards which can still be updated.
+	budget int
+	// Shards which aren't available, don't exist yet or have been updated.
+	progressing map[string]struct{}
+	// Shards which didn't become available within the progress deadline.
+	stalled []string
+	// Shards which have been held back.
+	pending map[string]struct{}
+}
+
+// NewShardRollout returns a ShardRollout for the statefulsets of the given
+// Prometheus or PrometheusAgent resource.
+//
+// The config reload checker verifies that the pods of a shard have reloaded
+// the configuration successfully when the configuration of the shard has
+// changed.
+func NewShardRollout(ctx context.Context, kclient kubernetes.Interface, ssg StatefulSetGetter, rc ConfigReloadChecker, p monitoringv1.PrometheusInterface, key string) (*ShardRollout, error) {
+	strategy := p.GetCommonPrometheusFields().ShardRolloutStrategy
+	sr := &ShardRollout{
+		strategy:    strategy,
+		now:         time.Now().UTC(),
+		generation:  p.GetObjectMeta().GetGeneration(),
+		progressing: map[string]struct{}{},
+		pending:     map[string]struct{}{},
+	}
+
+	if strategy == nil {
+		return sr, nil
+	}
+
+	deadline, err := shardRolloutProgressDeadline(strategy)
+	if err != nil {
+		return nil, err
+	}
+	sr.deadline = deadline
+
+	expected := ExpectedStatefulSetShardNames(p)
+	sr.shards = len(expected)
+
+	var unavailable int
+	for shard, ssetName := range expected {
+		obj, err := ssg.Get(KeyToStatefulSetKey(p, key, shard))
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				sr.progressing[ssetName] = struct{}{}
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to retrieve statefulset: %w", err)
+		}
+
+		sset := obj.(*appsv1.StatefulSet)
+		if sset.DeletionTimestamp != nil {
+			sr.progressing[ssetName] = struct{}{}
+			continue
+		}
+
+		rolledOut, err := shardRolledOut(ctx, kclient, rc, sset, ReplicasNumberPtr(p))
+		if err != nil {
+			return nil, err
+		}
+
+		if rolledOut {
+			// Forget the rollout so that a later unavailability of the shard
+			// isn't mistaken for a stalled rollout.
+			if err := clearRolloutAnnotations(ctx, kclient, sset); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		unavailable++
+		sr.progressing[sset.Name] = struct{}{}
+		if shardRolloutStalled(sset, deadline, sr.now) {
+			sr.stalled = append(sr.stalled, sset.Name)
+		}
+	}
+
+	sr.budget = int(ptr.Deref(strategy.BatchSize, 1)) - unavailable
+
+	return sr, nil
+}
+
+// Allow returns true if the statefulset of a shard can be updated. If yes,
+// it records the start of the rollout in the annotations of the desired
+// statefulset and whether the configuration of the shard changes compared
+// to the current statefulset.
+//
+// The caller must not write the configuration of a shard which isn't
+// allowed.
+func (sr *ShardRollout) Allow(desired, current *appsv1.StatefulSet) bool {
+	if sr.strategy == nil {
+		return true
+	}
+
+	if _, found := sr.progressing[desired.Name]; !found {
+		if len(sr.stalled) > 0 || sr.budget <= 0 {
+			sr.pending[desired.Name] = struct{}{}
+			return false
+		}
+
+		sr.budget--
+		sr.progressing[desired.Name] = struct{}{}
+	}
+
+	if desired.Annotations == nil {
+		desired.Annotations = map[string]string{}
+	}
+	desired.Annotations[RolloutStartedAtAnnotationKey] = sr.now.Format(time.RFC3339)
+	if current != nil && desired.Annotations[ConfigDigestAnnotationKey] != current.Annotations[ConfigDigestAnnotationKey] {
+		desired.Annotations[RolloutConfigReloadAnnotationKey] = "true"
+	}
+
+	return true
+}
+
+// InProgress returns true if some shards are held back or aren't available
+// yet.
+func (sr *ShardRollout) InProgress() bool {
+	return len(sr.progressing)+len(sr.pending) > 0
+}
+
+// Stalled returns the names of the statefulsets which didn't become
+// available within the progress deadline.
+func (sr *ShardRollout) Stalled() []string {
+	return sr.stalled
+}
+
+// Condition returns the RolledOut condition. It returns nil if no shard
+// rollout strategy is defined.
+func (sr *ShardRollout) Condition() *monitoringv1.Condition {
+	if sr.strategy == nil {
+		return nil
+	}
+
+	condition := &monitoringv1.Condition{
+		Type:   monitoringv1.RolledOut,
+		Status: monitoringv1.ConditionTrue,
+		LastTransitionTime: metav1.Time{
+			Time: sr.now,
+		},
+		ObservedGeneration: sr.generation,
+	}
+
+	switch {
+	case len(sr.stalled) > 0:
+		condition.Status = monitoringv1.ConditionFalse
+		condition.Reason = "ProgressDeadlineExceeded"
+		condition.Message = fmt.Sprintf("rollout paused because %s didn't become available or reload the configuration within %s", strings.Join(sr.stalled, ", "), sr.deadline)
+	case sr.InProgress():
+		condition.Status = monitoringv1.ConditionFalse
+		condition.Reason = "RolloutInProgress"
+		condition.Message = fmt.Sprintf("%d/%d shards rolled out", sr.shards-len(sr.progressing)-len(sr.pending), sr.shards)
+	}
+
+	return condition
+}
+
+func shardRolloutProgressDeadline(strategy *monitoringv1.ShardRolloutStrategy) (time.Duration, error) {
+	if strategy.ProgressDeadline == nil {
+		return defaultShardRolloutProgressDeadline, nil
+	}
+
+	d, err := model.ParseDuration(string(*strategy.ProgressDeadline))
+	if err != nil {
+		return 0, fmt.Errorf("invalid progress deadline %q: %w", *strategy.ProgressDeadline, err)
+	}
+
+	return time.Duration(d), nil
+}
+
+// shardRolledOut returns true if the statefulset is reconciled, all its
+// pods run the latest revision and the expected number of pods are ready.
+// When the configuration of the shard has changed, all the pods must also
+// have reloaded the configuration successfully since the start of the
+// rollout.
+func shardRolledOut(ctx context.Context, kclient kubernetes.Interface, rc ConfigReloadChecker, sset *appsv1.StatefulSet, replicas *int32) (bool, error) {
+	if sset.Generation != sset.Status.ObservedGeneration {
+		return false, nil
+	}
+
+	stsReporter, err := operator.NewStatefulSetReporter(ctx, kclient, sset)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve statefulset state: %w", err)
+	}
+
+	if len(stsReporter.UpdatedPods()) < len(stsReporter.Pods) {
+		return false, nil
+	}
+
+	status, _ := stsReporter.StatusAndReasonForAvailableCondition(int(ptr.Deref(replicas, 1)))
+	if status != monitoringv1.ConditionTrue {
+		return false, nil
+	}
+
+	if sset.Annotations[RolloutConfigReloadAnnotationKey] == "" {
+		return true, nil
+	}
+
+	startedAt, err := time.Parse(time.RFC3339, sset.Annotations[RolloutStartedAtAnnotationKey])
+	if err != nil {
+		return true, nil
+	}
+
+	for _, pod := range stsReporter.Pods {
+		reloaded, err := rc.ConfigReloadedSince(ctx, (*corev1.Pod)(&pod), startedAt)
+		if err != nil || !reloaded {
+			// The shard is considered as progressing until the status of
+			// the reload can be retrieved.
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// clearRolloutAnnotations removes the annotations recording the rollout
+// from the statefulset.
+func clearRolloutAnnotations(ctx context.Context, kclient kubernetes.Interface, sset *appsv1.StatefulSet) error {
+	patch := map[string]any{}
+	for _, k := range []string{RolloutStartedAtAnnotationKey, RolloutConfigReloadAnnotationKey} {
+		if _, found := sset.Annotations[k]; found {
+			patch[k] = nil
+		}
+	}
+
+	if len(patch) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": patch}})
+	if err != nil {
+		return err
+	}
+
+	if _, err := kclient.AppsV1().StatefulSets(sset.Namespace).Patch(ctx, sset.Name, types.MergePatchType, b, metav1.PatchOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to clear the rollout annotations of statefulset %s: %w", sset.Name, err)
+	}
+
+	return nil
+}
+
+// shardRolloutStalled returns true if the statefulset has been updated by
+// the operator for longer than the progress deadline.
+func shardRolloutStalled(sset *appsv1.StatefulSet, deadline time.Duration, now time.Time) bool {
+	startedAt, err := time.Parse(time.RFC3339, sset.Annotations[RolloutStartedAtAnnotationKey])
+	if err != nil {
+		return false
+	}
+
+	return now.Sub(startedAt) > deadline
+}
+
+// ShardRollouts records the RolledOut condition of the Prometheus objects
+// which define a shard rollout strategy.
+//
+// It is safe for concurrent use.
+type ShardRollouts struct {
+	mtx sync.RWMutex
+	// conditions are indexed by Prometheus key (<namespace>/<name>).
+	conditions map[string]monitoringv1.Condition
+}
+
+// NewShardRollouts returns an empty ShardRollouts.
+func NewShardRollouts() *ShardRollouts {
+	return &ShardRollouts{
+		conditions: map[string]monitoringv1.Condition{},
+	}
+}
+
+// Set records the state of the rollout for the Prometheus object identified
+// by key.
+func (sr *ShardRollouts) Set(key string, rollout *ShardRollout) {
+	condition := rollout.Condition()
+	if condition == nil {
+		sr.Delete(key)
+		return
+	}
+
+	sr.mtx.Lock()
+	defer sr.mtx.Unlock()
+
+	sr.conditions[key] = *condition
+}
+
+// Delete removes the state of the rollout for the Prometheus object
+// identified by key.
+func (sr *ShardRollouts) Delete(key string) {
+	sr.mtx.Lock()
+	defer sr.mtx.Unlock()
+
+	delete(sr.conditions, key)
+}
+
+// UpdateStatus adds the RolledOut condition to the status of the Prometheus
+// object identified by key. The current conditions are used to preserve the
+// last transition time.
+func (sr *ShardRollouts) UpdateStatus(key string, current []monitoringv1.Condition, status *monitoringv1.PrometheusStatus) {
+	sr.mtx.RLock()
+	defer sr.mtx.RUnlock()
+
+	condition, found := sr.conditions[key]
+	if !found {
+		return
+	}
+
+	status.Conditions = operator.UpdateConditions(current, append(status.Conditions, condition)...)
+}
+
+// ShardConfigDigest returns the digest of the configuration files of a
+// shard.
+func ShardConfigDigest(configFile []byte, scrapeConfigFiles map[string][]byte) string {
+	h := sha256.New()
+	h.Write(configFile)
+	for _, name := range sortutil.SortedKeys(scrapeConfigFiles) {
+		fmt.Fprintf(h, "\x00%s\x00", name)
+		h.Write(scrapeConfigFiles[name])
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// ShardConfigurations writes the configuration secret of a Prometheus or
+// PrometheusAgent resource.
+//
+// When the configuration changes are rolled out shard by shard, the
+// configuration files of the existing shards whose configuration changes
+// keep their current content until the shards are released.
+type ShardConfigurations struct {
+	cg       *ConfigGenerator
+	desired  *corev1.Secret
+	current  *corev1.Secret
+	digests  map[int32]string
+	deferred map[int32]struct{}
+}
+
+// NewShardConfigurations returns the ShardConfigurations for the desired
+// configuration secret and scrape configuration files.
+func NewShardConfigurations(ctx context.Context, kclient kubernetes.Interface, ssg StatefulSetGetter, cg *ConfigGenerator, key string, desired *corev1.Secret, scrapeConfigFiles map[string][]byte) (*ShardConfigurations, error) {
+	sc := &ShardConfigurations{
+		cg:       cg,
+		desired:  desired,
+		digests:  map[int32]string{},
+		deferred: map[int32]struct{}{},
+	}
+
+	if !cg.ShardConfigurationRollout() {
+		return sc, nil
+	}
+
+	for shard := range ShardsNumber(cg.prom) {
+		sc.digests[shard] = ShardConfigDigest(desired.Data[cg.ConfigFilenameForShard(shard)], cg.ExpandShardVariables(scrapeConfigFiles, shard))
+
+		obj, err := ssg.Get(KeyToStatefulSetKey(cg.prom, key, int(shard)))
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to retrieve statefulset: %w", err)
+		}
+
+		sset := obj.(*appsv1.StatefulSet)
+		if sset.Annotations[ConfigDigestAnnotationKey] != sc.digests[shard] {
+			sc.deferred[shard] = struct{}{}
+		}
+	}
+
+	if len(sc.deferred) == 0 {
+		return sc, nil
+	}
+
+	current, err := kclient.CoreV1().Secrets(cg.prom.GetObjectMeta().GetNamespace()).Get(ctx, desired.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to retrieve the configuration secret: %w", err)
+	}
+
+	if err == nil {
+		sc.current = current
+	}
+
+	return sc, nil
+}
+
+// Digest returns the digest of the configuration files of the shard. It
+// returns an empty string when the configuration changes aren't rolled out
+// shard by shard.
+func (sc *ShardConfigurations) Digest(shard int32) string {
+	return sc.digests[shard]
+}
+
+// Deferred returns true if the configuration changes of the shard are
+// deferred. The scrape configuration files of a deferred shard must not be
+// written.
+func (sc *ShardConfigurations) Deferred(shard int32) bool {
+	_, found := sc.deferred[shard]
+	return found
+}
+
+// Release stops deferring the configuration changes of the shard. It
+// returns true if the configuration secret needs to be written again.
+func (sc *ShardConfigurations) Release(shard int32) bool {
+	if !sc.Deferred(shard) {
+		return false
+	}
+
+	delete(sc.deferred, shard)
+	return true
+}
+
+// Write creates or updates the configuration secret.
+func (sc *ShardConfigurations) Write(ctx context.Context, kclient kubernetes.Interface) error {
+	shards := make([]int32, 0, len(sc.deferred))
+	for shard := range sc.deferred {
+		shards = append(shards, shard)
+	}
+
+	s := deferShardConfigurations(sc.cg, sc.desired, sc.current, shards)
+	if err := k8s.ApplyOverlays(sc.cg.prom.GetCommonPrometheusFields().Overlays, s); err != nil {
+		return err
+	}
+
+	return k8s.CreateOrUpdateSecret(ctx, kclient.CoreV1().Secrets(sc.cg.prom.GetObjectMeta().GetNamespace()), s)
+}
+
+// deferShardConfigurations returns a copy of the desired configuration
+// secret where the configuration files of the given shards keep their
+// current content.
+//
+// The files of the current secret which aren't generated anymore are kept
+// as long as some shards are deferred because the pods of these shards may
+// still read them (e.g. when the shards switch to per-shard configuration
+// files).
+func deferShardConfigurations(cg *ConfigGenerator, desired, current *corev1.Secret, shards []int32) *corev1.Secret {
+	if len(shards) == 0 || current == nil {
+		return desired
+	}
+
+	s := desired.DeepCopy()
+	for _, shard := range shards {
+		name := cg.ConfigFilenameForShard(shard)
+		if b, found := current.Data[name]; found {
+			s.Data[name] = b
+		}
+	}
+
+	for name, b := range current.Data {
+		if _, found := s.Data[name]; !found {
+			s.Data[name] = b
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,397 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+type fakeConfigReloadChecker struct {
+	reloaded bool
+	err      error
+}
+
+func (f fakeConfigReloadChecker) ConfigReloadedSince(context.Context, *corev1.Pod, time.Time) (bool, error) {
+	return f.reloaded, f.err
+}
+
+func newRolloutStatefulSetAndPod(name string, ready bool, startedAt time.Time) (appsv1.StatefulSet, *corev1.Pod) {
+	sset := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "default",
+			Generation: 1,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"statefulset": name},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			UpdateRevision:     "2",
+		},
+	}
+	if !startedAt.IsZero() {
+		sset.Annotations = map[string]string{
+			RolloutStartedAtAnnotationKey: startedAt.Format(time.RFC3339),
+		}
+	}
+
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				"statefulset":                         name,
+				appsv1.ControllerRevisionHashLabelKey: "2",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "StatefulSet", Name: name},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: status},
+			},
+		},
+	}
+
+	return sset, pod
+}
+
+func TestShardRollout(t *testing.T) {
+	var (
+		now   = time.Now()
+		names = []string{"prometheus-test", "prometheus-test-shard-1", "prometheus-test-shard-2"}
+	)
+
+	for _, tc := range []struct {
+		name        string
+		strategy    *monitoringv1.ShardRolloutStrategy
+		unavailable map[string]time.Time
+
+		expAllowed   []bool
+		expCondition *monitoringv1.Condition
+	}{
+		{
+			name:       "no strategy",
+			expAllowed: []bool{true, true, true},
+		},
+		{
+			name:       "sequential rollout",
+			strategy:   &monitoringv1.ShardRolloutStrategy{},
+			expAllowed: []bool{true, false, false},
+			expCondition: &monitoringv1.Condition{
+				Status:  monitoringv1.ConditionFalse,
+				Reason:  "RolloutInProgress",
+				Message: "0/3 shards rolled out",
+			},
+		},
+		{
+			name:     "sequential rollout with unavailable shard",
+			strategy: &monitoringv1.ShardRolloutStrategy{},
+			unavailable: map[string]time.Time{
+				"prometheus-test": now.Add(-time.Minute),
+			},
+			expAllowed: []bool{true, false, false},
+			expCondition: &monitoringv1.Condition{
+				Status:  monitoringv1.ConditionFalse,
+				Reason:  "RolloutInProgress",
+				Message: "0/3 shards rolled out",
+			},
+		},
+		{
+			name: "batch rollout with unavailable shard",
+			strategy: &monitoringv1.ShardRolloutStrategy{
+				BatchSize: ptr.To(int32(2)),
+			},
+			unavailable: map[string]time.Time{
+				"prometheus-test": now.Add(-time.Minute),
+			},
+			expAllowed: []bool{true, true, false},
+			expCondition: &monitoringv1.Condition{
+				Status:  monitoringv1.ConditionFalse,
+				Reason:  "RolloutInProgress",
+				Message: "0/3 shards rolled out",
+			},
+		},
+		{
+			name: "progress deadline exceeded",
+			strategy: &monitoringv1.ShardRolloutStrategy{
+				BatchSize:        ptr.To(int32(3)),
+				ProgressDeadline: ptr.To(monitoringv1.Duration("5m")),
+			},
+			unavailable: map[string]time.Time{
+				"prometheus-test": now.Add(-10 * time.Minute),
+			},
+			expAllowed: []bool{true, false, false},
+			expCondition: &monitoringv1.Condition{
+				Status:  monitoringv1.ConditionFalse,
+				Reason:  "ProgressDeadlineExceeded",
+				Message: "rollout paused because prometheus-test didn't become available or reload the configuration within 5m0s",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				ssets []appsv1.StatefulSet
+				pods  []*corev1.Pod
+			)
+			for _, name := range names {
+				startedAt, found := tc.unavailable[name]
+				sset, pod := newRolloutStatefulSetAndPod(name, !found, startedAt)
+				ssets = append(ssets, sset)
+				pods = append(pods, pod)
+			}
+
+			kclient := fake.NewClientset()
+			for _, pod := range pods {
+				require.NoError(t, kclient.Tracker().Add(pod))
+			}
+
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 2,
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Shards:               ptr.To(int32(3)),
+						ShardRolloutStrategy: tc.strategy,
+					},
+				},
+			}
+
+			rollout, err := NewShardRollout(context.Background(), kclient, fakeStatefulSetGetter(ssets), fakeConfigReloadChecker{reloaded: true}, p, "default/test")
+			require.NoError(t, err)
+
+			for i, name := range names {
+				desired := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name}}
+				allowed := rollout.Allow(desired, &ssets[i])
+				require.Equal(t, tc.expAllowed[i], allowed, name)
+
+				_, annotated := desired.Annotations[RolloutStartedAtAnnotationKey]
+				require.Equal(t, allowed && tc.strategy != nil, annotated, name)
+
+				_, reload := desired.Annotations[RolloutConfigReloadAnnotationKey]
+				require.False(t, reload, name)
+			}
+
+			c := rollout.Condition()
+			if tc.expCondition == nil {
+				require.Nil(t, c)
+				require.False(t, rollout.InProgress())
+				return
+			}
+
+			require.NotNil(t, c)
+			require.Equal(t, monitoringv1.RolledOut, c.Type)
+			require.Equal(t, tc.expCondition.Status, c.Status)
+			require.Equal(t, tc.expCondition.Reason, c.Reason)
+			require.Equal(t, tc.expCondition.Message, c.Message)
+			require.Equal(t, int64(2), c.ObservedGeneration)
+			require.True(t, rollout.InProgress())
+		})
+	}
+}
+
+func TestShardRolloutCompleted(t *testing.T) {
+	sset, pod := newRolloutStatefulSetAndPod("prometheus-test", true, time.Now().Add(-time.Hour))
+	kclient := fake.NewClientset(pod, &sset)
+
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				ShardRolloutStrategy: &monitoringv1.ShardRolloutStrategy{},
+			},
+		},
+	}
+
+	rollout, err := NewShardRollout(context.Background(), kclient, fakeStatefulSetGetter{sset}, fakeConfigReloadChecker{reloaded: true}, p, "default/test")
+	require.NoError(t, err)
+	require.False(t, rollout.InProgress())
+
+	// The rollout annotations are removed once the shard is rolled out.
+	got, err := kclient.AppsV1().StatefulSets("default").Get(context.Background(), "prometheus-test", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, got.Annotations, RolloutStartedAtAnnotationKey)
+
+	c := rollout.Condition()
+	require.NotNil(t, c)
+	require.Equal(t, monitoringv1.ConditionTrue, c.Status)
+
+	rollouts := NewShardRollouts()
+	rollouts.Set("default/test", rollout)
+
+	status := &monitoringv1.PrometheusStatus{}
+	rollouts.UpdateStatus("default/test", nil, status)
+	require.Len(t, status.Conditions, 1)
+	require.Equal(t, monitoringv1.RolledOut, status.Conditions[0].Type)
+
+	rollouts.Delete("default/test")
+	status = &monitoringv1.PrometheusStatus{}
+	rollouts.UpdateStatus("default/test", nil, status)
+	require.Empty(t, status.Conditions)
+}
+
+func TestShardRolloutConfigReload(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		checker fakeConfigReloadChecker
+
+		expRolledOut bool
+	}{
+		{
+			name:         "reloaded",
+			checker:      fakeConfigReloadChecker{reloaded: true},
+			expRolledOut: true,
+		},
+		{
+			name:    "not reloaded",
+			checker: fakeConfigReloadChecker{},
+		},
+		{
+			name:    "reload status unavailable",
+			checker: fakeConfigReloadChecker{err: errors.New("connection refused")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, pod := newRolloutStatefulSetAndPod("prometheus-test", true, time.Now().Add(-time.Minute))
+			sset.Annotations[RolloutConfigReloadAnnotationKey] = "true"
+			kclient := fake.NewClientset(pod, &sset)
+
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						ShardRolloutStrategy: &monitoringv1.ShardRolloutStrategy{},
+					},
+				},
+			}
+
+			rollout, err := NewShardRollout(context.Background(), kclient, fakeStatefulSetGetter{sset}, tc.checker, p, "default/test")
+			require.NoError(t, err)
+			require.Equal(t, !tc.expRolledOut, rollout.InProgress())
+
+			got, err := kclient.AppsV1().StatefulSets("default").Get(context.Background(), "prometheus-test", metav1.GetOptions{})
+			require.NoError(t, err)
+			if tc.expRolledOut {
+				require.NotContains(t, got.Annotations, RolloutStartedAtAnnotationKey)
+				require.NotContains(t, got.Annotations, RolloutConfigReloadAnnotationKey)
+				return
+			}
+
+			require.Contains(t, got.Annotations, RolloutStartedAtAnnotationKey)
+			require.Contains(t, got.Annotations, RolloutConfigReloadAnnotationKey)
+		})
+	}
+}
+
+func TestShardRolloutAllowConfigChange(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				ShardRolloutStrategy: &monitoringv1.ShardRolloutStrategy{},
+			},
+		},
+	}
+
+	rollout, err := NewShardRollout(context.Background(), fake.NewClientset(), fakeStatefulSetGetter{}, fakeConfigReloadChecker{}, p, "default/test")
+	require.NoError(t, err)
+
+	current := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "prometheus-test",
+		Annotations: map[string]string{ConfigDigestAnnotationKey: "a"},
+	}}
+	desired := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "prometheus-test",
+		Annotations: map[string]string{ConfigDigestAnnotationKey: "b"},
+	}}
+
+	require.True(t, rollout.Allow(desired, current))
+	require.Equal(t, "true", desired.Annotations[RolloutConfigReloadAnnotationKey])
+}
+
+func TestDeferShardConfigurations(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Shards:               ptr.To(int32(2)),
+				ShardRolloutStrategy: &monitoringv1.ShardRolloutStrategy{},
+			},
+		},
+	}
+	cg, err := NewConfigGenerator(nil, p, WithShardConfigurationRollout())
+	require.NoError(t, err)
+
+	desired := &corev1.Secret{Data: map[string][]byte{
+		"prometheus-shard-0.yaml.gz": []byte("new-0"),
+		"prometheus-shard-1.yaml.gz": []byte("new-1"),
+	}}
+	current := &corev1.Secret{Data: map[string][]byte{
+		ConfigFilename:               []byte("old"),
+		"prometheus-shard-0.yaml.gz": []byte("old-0"),
+		"prometheus-shard-1.yaml.gz": []byte("old-1"),
+	}}
+
+	// No deferred shard.
+	require.Equal(t, desired.Data, deferShardConfigurations(cg, desired, current, nil).Data)
+
+	// The deferred shard keeps its current file and the stale files are kept.
+	require.Equal(t,
+		map[string][]byte{
+			ConfigFilename:               []byte("old"),
+			"prometheus-shard-0.yaml.gz": []byte("new-0"),
+			"prometheus-shard-1.yaml.gz": []byte("old-1"),
+		},
+		deferShardConfigurations(cg, desired, current, []int32{1}).Data,
+	)
+	require.Equal(t, []byte("new-1"), desired.Data["prometheus-shard-1.yaml.gz"])
+}
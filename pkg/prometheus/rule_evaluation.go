@@ -0,0 +1,116 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// defaultRuleGroupShardLabelName is the default rule group label identifying
+// the shard evaluating the group with the GroupLabel mode.
+const defaultRuleGroupShardLabelName = "prometheus_operator_shard"
+
+// PerShardRuleEvaluation returns true when the shards don't evaluate the
+// same rules. In this case, each shard has its own rule ConfigMaps.
+func PerShardRuleEvaluation(p *monitoringv1.Prometheus) bool {
+	re := p.Spec.RuleEvaluation
+	if re == nil {
+		return false
+	}
+
+	return ptr.Deref(re.Mode, monitoringv1.AllShardsRuleEvaluationMode) != monitoringv1.AllShardsRuleEvaluationMode
+}
+
+// RuleGroupAssigner assigns rule groups to the shards of a Prometheus
+// object.
+type RuleGroupAssigner struct {
+	shards    int32
+	mode      monitoringv1.RuleEvaluationMode
+	shard     int32
+	labelName string
+	logger    *slog.Logger
+}
+
+// NewRuleGroupAssigner returns a RuleGroupAssigner for the Prometheus object.
+// It returns nil when all the shards evaluate the same rules.
+func NewRuleGroupAssigner(p *monitoringv1.Prometheus, logger *slog.Logger) *RuleGroupAssigner {
+	if !PerShardRuleEvaluation(p) {
+		return nil
+	}
+
+	re := p.Spec.RuleEvaluation
+	a := &RuleGroupAssigner{
+		shards:    ShardsNumber(p),
+		mode:      *re.Mode,
+		shard:     ptr.Deref(re.Shard, 0),
+		labelName: ptr.Deref(re.GroupLabelName, defaultRuleGroupShardLabelName),
+		logger:    logger,
+	}
+
+	if a.shard >= a.shards {
+		logger.Warn("rule evaluation shard is out of range, using the first shard instead", "shard", a.shard, "shards", a.shards)
+		a.shard = 0
+	}
+
+	return a
+}
+
+// Shards returns the number of shards.
+func (a *RuleGroupAssigner) Shards() int32 {
+	return a.shards
+}
+
+// Assign returns the shard evaluating the rule group of the PrometheusRule
+// object.
+// With the GroupLabel mode, the shard label is removed from the group.
+func (a *RuleGroupAssigner) Assign(promRule *monitoringv1.PrometheusRule, group *monitoringv1.RuleGroup) int32 {
+	switch a.mode {
+	case monitoringv1.HashGroupsRuleEvaluationMode:
+		return hashmod(fmt.Sprintf("%s/%s/%s", promRule.Namespace, promRule.Name, group.Name), a.shards)
+
+	case monitoringv1.GroupLabelRuleEvaluationMode:
+		v, found := group.Labels[a.labelName]
+		if !found {
+			return a.shard
+		}
+
+		delete(group.Labels, a.labelName)
+		if len(group.Labels) == 0 {
+			group.Labels = nil
+		}
+
+		shard, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || shard < 0 || int32(shard) >= a.shards {
+			a.logger.Warn("invalid rule group shard, using the default shard instead",
+				"prometheusrule", promRule.Name,
+				"namespace", promRule.Namespace,
+				"group", group.Name,
+				"value", v,
+				"shard", a.shard,
+			)
+			return a.shard
+		}
+
+		return int32(shard)
+	}
+
+	return a.shard
+}
@@ -0,0 +1,94 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestRuleGroupAssigner(t *testing.T) {
+	promRule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rules"},
+	}
+	newPrometheus := func(re *monitoringv1.RuleEvaluation) *monitoringv1.Prometheus {
+		p := defaultPrometheus()
+		p.Spec.Shards = ptr.To(int32(3))
+		p.Spec.RuleEvaluation = re
+		return p
+	}
+
+	t.Run("all shards", func(t *testing.T) {
+		require.Nil(t, NewRuleGroupAssigner(newPrometheus(nil), slog.New(slog.DiscardHandler)))
+		require.Nil(t, NewRuleGroupAssigner(newPrometheus(&monitoringv1.RuleEvaluation{
+			Mode: ptr.To(monitoringv1.AllShardsRuleEvaluationMode),
+		}), slog.New(slog.DiscardHandler)))
+	})
+
+	t.Run("single shard", func(t *testing.T) {
+		a := NewRuleGroupAssigner(newPrometheus(&monitoringv1.RuleEvaluation{
+			Mode:  ptr.To(monitoringv1.SingleShardRuleEvaluationMode),
+			Shard: ptr.To(int32(2)),
+		}), slog.New(slog.DiscardHandler))
+		require.NotNil(t, a)
+		require.Equal(t, int32(3), a.Shards())
+		require.Equal(t, int32(2), a.Assign(promRule, &monitoringv1.RuleGroup{Name: "foo"}))
+
+		// Out of range.
+		a = NewRuleGroupAssigner(newPrometheus(&monitoringv1.RuleEvaluation{
+			Mode:  ptr.To(monitoringv1.SingleShardRuleEvaluationMode),
+			Shard: ptr.To(int32(3)),
+		}), slog.New(slog.DiscardHandler))
+		require.Equal(t, int32(0), a.Assign(promRule, &monitoringv1.RuleGroup{Name: "foo"}))
+	})
+
+	t.Run("hash groups", func(t *testing.T) {
+		a := NewRuleGroupAssigner(newPrometheus(&monitoringv1.RuleEvaluation{
+			Mode: ptr.To(monitoringv1.HashGroupsRuleEvaluationMode),
+		}), slog.New(slog.DiscardHandler))
+
+		for _, name := range []string{"foo", "bar", "baz"} {
+			require.Equal(t, hashmod("default/rules/"+name, 3), a.Assign(promRule, &monitoringv1.RuleGroup{Name: name}))
+		}
+	})
+
+	t.Run("group label", func(t *testing.T) {
+		a := NewRuleGroupAssigner(newPrometheus(&monitoringv1.RuleEvaluation{
+			Mode:  ptr.To(monitoringv1.GroupLabelRuleEvaluationMode),
+			Shard: ptr.To(int32(1)),
+		}), slog.New(slog.DiscardHandler))
+
+		g := &monitoringv1.RuleGroup{Name: "foo", Labels: map[string]string{defaultRuleGroupShardLabelName: "2", "team": "a"}}
+		require.Equal(t, int32(2), a.Assign(promRule, g))
+		require.Equal(t, map[string]string{"team": "a"}, g.Labels)
+
+		g = &monitoringv1.RuleGroup{Name: "foo", Labels: map[string]string{defaultRuleGroupShardLabelName: "0"}}
+		require.Equal(t, int32(0), a.Assign(promRule, g))
+		require.Nil(t, g.Labels)
+
+		// Invalid values and groups without label are evaluated by the
+		// default shard.
+		for _, v := range []string{"3", "-1", "x"} {
+			require.Equal(t, int32(1), a.Assign(promRule, &monitoringv1.RuleGroup{Name: "foo", Labels: map[string]string{defaultRuleGroupShardLabelName: v}}))
+		}
+		require.Equal(t, int32(1), a.Assign(promRule, &monitoringv1.RuleGroup{Name: "foo"}))
+	})
+}
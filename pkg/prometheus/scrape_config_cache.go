@@ -0,0 +1,367 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+// ScrapeConfigCache memoizes the scrape configurations generated from the
+// ServiceMonitor, PodMonitor, Probe and ScrapeConfig resources across
+// reconciliations.
+//
+// A cached entry is reused when all the following inputs are unchanged:
+// * The UID and resource version of the resource.
+// * The scrape class of the resource.
+// * The spec of the Prometheus object and the options of the config generator.
+// * The resource versions of the secrets and configmaps read during the
+// generation.
+//
+// It is safe for concurrent use.
+type ScrapeConfigCache struct {
+	mtx sync.Mutex
+	// entries are indexed by Prometheus key (<namespace>/<name>) and by
+	// resource key (<kind>/<uid>).
+	entries map[string]map[string]*scrapeConfigCacheEntry
+
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+}
+
+type scrapeConfigCacheEntry struct {
+	resourceVersion string
+	scrapeClass     string
+	specHash        string
+	assetVersions   assets.AssetVersions
+
+	scrapeConfigs []yaml.MapSlice
+	// logRecords are the warnings logged during the generation which are
+	// logged again when the entry is reused.
+	logRecords []slog.Record
+}
+
+// NewScrapeConfigCache returns an empty cache.
+func NewScrapeConfigCache() *ScrapeConfigCache {
+	return &ScrapeConfigCache{
+		entries: map[string]map[string]*scrapeConfigCacheEntry{},
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "prometheus_operator_scrape_config_cache_hits_total",
+			Help: "Number of times the scrape configuration of a resource was reused from the cache",
+		}, []string{"resource"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "prometheus_operator_scrape_config_cache_misses_total",
+			Help: "Number of times the scrape configuration of a resource had to be generated",
+		}, []string{"resource"}),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *ScrapeConfigCache) Describe(ch chan<- *prometheus.Desc) {
+	c.hits.Describe(ch)
+	c.misses.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *ScrapeConfigCache) Collect(ch chan<- prometheus.Metric) {
+	c.hits.Collect(ch)
+	c.misses.Collect(ch)
+}
+
+// Forget removes all the entries associated to the Prometheus object
+// identified by key (<namespace>/<name>).
+func (c *ScrapeConfigCache) Forget(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.entries, key)
+}
+
+func (c *ScrapeConfigCache) get(promKey, resourceKey string) *scrapeConfigCacheEntry {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.entries[promKey][resourceKey]
+}
+
+func (c *ScrapeConfigCache) set(promKey, resourceKey string, entry *scrapeConfigCacheEntry) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, found := c.entries[promKey]; !found {
+		c.entries[promKey] = map[string]*scrapeConfigCacheEntry{}
+	}
+
+	c.entries[promKey][resourceKey] = entry
+}
+
+// prune removes the entries of the Prometheus object which aren't part of
+// the given resource keys.
+func (c *ScrapeConfigCache) prune(promKey string, resourceKeys map[string]struct{}) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for k := range c.entries[promKey] {
+		if _, found := resourceKeys[k]; !found {
+			delete(c.entries[promKey], k)
+		}
+	}
+}
+
+// WithScrapeConfigCache tells the config generator to reuse the scrape
+// configurations stored in the cache when possible.
+func WithScrapeConfigCache(c *ScrapeConfigCache) ConfigGeneratorOption {
+	return func(cg *ConfigGenerator) {
+		cg.scrapeConfigCache = c
+	}
+}
+
+// scrapeConfigCacheSpecHash returns a digest of all the config generator's
+// inputs which don't depend on the resource.
+func (cg *ConfigGenerator) scrapeConfigCacheSpecHash() (string, error) {
+	var spec any
+	switch p := cg.prom.(type) {
+	case *monitoringv1.Prometheus:
+		spec = p.Spec
+	case *monitoringv1alpha1.PrometheusAgent:
+		spec = p.Spec
+	default:
+		spec = cg.prom.GetCommonPrometheusFields()
+	}
+
+	b, err := json.Marshal(struct {
+		Spec                        any
+		Version                     string
+		EndpointSliceSupported      bool
+		DaemonSet                   bool
+		PrometheusTopologySharding  bool
+		PrometheusRetentionPolicies bool
+		PodTopologyLabelsSupported  bool
+		InlineTLSConfig             bool
+		BypassVersionCheck          bool
+		OperatorHTTPSDURL           string
+	}{
+		Spec:                        spec,
+		Version:                     cg.version.String(),
+		EndpointSliceSupported:      cg.endpointSliceSupported,
+		DaemonSet:                   cg.daemonSet,
+		PrometheusTopologySharding:  cg.prometheusTopologySharding,
+		PrometheusRetentionPolicies: cg.prometheusRetentionPolicies,
+		PodTopologyLabelsSupported:  cg.podTopologyLabelsSupported,
+		InlineTLSConfig:             cg.inlineTLSConfig,
+		BypassVersionCheck:          cg.bypassVersionCheck,
+		OperatorHTTPSDURL:           cg.operatorHTTPSDURL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+func (cg *ConfigGenerator) scrapeConfigCachePromKey() string {
+	objMeta := cg.prom.GetObjectMeta()
+	return fmt.Sprintf("%s/%s", objMeta.GetNamespace(), objMeta.GetName())
+}
+
+func scrapeConfigCacheResourceKey(kind string, obj metav1.Object) string {
+	return fmt.Sprintf("%s/%s", kind, obj.GetUID())
+}
+
+// cachedScrapeConfigs returns the scrape configurations of the resource from
+// the cache if the entry is still valid. Otherwise it calls generate() and
+// stores the result into the cache.
+//
+// generate() must read secrets and configmaps only from the store passed as
+// argument and log messages only with the config generator passed as
+// argument: the warnings are stored with the entry and logged again on cache
+// hits.
+func (cg *ConfigGenerator) cachedScrapeConfigs(
+	kind string,
+	obj metav1.Object,
+	scrapeClass *string,
+	store *assets.StoreBuilder,
+	generate func(*ConfigGenerator, *assets.StoreBuilder) ([]yaml.MapSlice, error),
+) ([]yaml.MapSlice, error) {
+	// Objects without UID (e.g. in unit tests) can't be identified reliably.
+	if cg.scrapeConfigCache == nil || cg.specHash == "" || obj.GetUID() == "" {
+		return generate(cg, store)
+	}
+
+	var (
+		promKey     = cg.scrapeConfigCachePromKey()
+		resourceKey = scrapeConfigCacheResourceKey(kind, obj)
+		entry       = cg.scrapeConfigCache.get(promKey, resourceKey)
+	)
+
+	if entry != nil &&
+		entry.resourceVersion == obj.GetResourceVersion() &&
+		entry.scrapeClass == ptr.Deref(scrapeClass, "") &&
+		entry.specHash == cg.specHash &&
+		store.MatchesAssetVersions(entry.assetVersions) {
+		cg.scrapeConfigCache.hits.WithLabelValues(kind).Inc()
+		replayLogRecords(cg.logger.Handler(), entry.logRecords)
+		return entry.scrapeConfigs, nil
+	}
+
+	cg.scrapeConfigCache.misses.WithLabelValues(kind).Inc()
+
+	var (
+		assetVersions = assets.AssetVersions{}
+		recorder      = newLogRecorder(cg.logger.Handler())
+		rcg           = cg.withLogger(slog.New(recorder))
+	)
+	scrapeConfigs, err := generate(rcg, store.WithAssetVersions(assetVersions))
+	if err != nil {
+		return nil, err
+	}
+
+	cg.scrapeConfigCache.set(promKey, resourceKey, &scrapeConfigCacheEntry{
+		resourceVersion: obj.GetResourceVersion(),
+		scrapeClass:     ptr.Deref(scrapeClass, ""),
+		specHash:        cg.specHash,
+		assetVersions:   assetVersions,
+		scrapeConfigs:   scrapeConfigs,
+		logRecords:      *recorder.records,
+	})
+
+	return scrapeConfigs, nil
+}
+
+// withLogger returns a copy of the config generator using the given logger.
+func (cg *ConfigGenerator) withLogger(logger *slog.Logger) *ConfigGenerator {
+	c := *cg
+	c.logger = logger
+	return &c
+}
+
+// logRecorder is a slog.Handler which records the log records of level
+// warning and above before passing them to the next handler.
+type logRecorder struct {
+	next    slog.Handler
+	attrs   []slog.Attr
+	records *[]slog.Record
+}
+
+func newLogRecorder(next slog.Handler) *logRecorder {
+	return &logRecorder{
+		next:    next,
+		records: &[]slog.Record{},
+	}
+}
+
+// Enabled implements the slog.Handler interface.
+func (l *logRecorder) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || l.next.Enabled(ctx, level)
+}
+
+// Handle implements the slog.Handler interface.
+func (l *logRecorder) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		rec := r.Clone()
+		rec.AddAttrs(l.attrs...)
+		*l.records = append(*l.records, rec)
+	}
+
+	if !l.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	return l.next.Handle(ctx, r)
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (l *logRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logRecorder{
+		next:    l.next.WithAttrs(attrs),
+		attrs:   append(slices.Clone(l.attrs), attrs...),
+		records: l.records,
+	}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (l *logRecorder) WithGroup(name string) slog.Handler {
+	return &logRecorder{
+		next:    l.next.WithGroup(name),
+		attrs:   l.attrs,
+		records: l.records,
+	}
+}
+
+// replayLogRecords passes the recorded log records to the handler.
+func replayLogRecords(h slog.Handler, records []slog.Record) {
+	ctx := context.Background()
+	for _, r := range records {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+
+		rec := slog.NewRecord(time.Now(), r.Level, r.Message, r.PC)
+		r.Attrs(func(a slog.Attr) bool {
+			rec.AddAttrs(a)
+			return true
+		})
+
+		_ = h.Handle(ctx, rec)
+	}
+}
+
+// pruneScrapeConfigCache removes the cached entries of resources which
+// aren't selected anymore.
+func (cg *ConfigGenerator) pruneScrapeConfigCache(
+	sMons map[string]*monitoringv1.ServiceMonitor,
+	pMons map[string]*monitoringv1.PodMonitor,
+	probes map[string]*monitoringv1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
+) {
+	if cg.scrapeConfigCache == nil {
+		return
+	}
+
+	resourceKeys := make(map[string]struct{}, len(sMons)+len(pMons)+len(probes)+len(sCons))
+	for _, sm := range sMons {
+		resourceKeys[scrapeConfigCacheResourceKey(monitoringv1.ServiceMonitorsKind, sm)] = struct{}{}
+	}
+	for _, pm := range pMons {
+		resourceKeys[scrapeConfigCacheResourceKey(monitoringv1.PodMonitorsKind, pm)] = struct{}{}
+	}
+	for _, probe := range probes {
+		resourceKeys[scrapeConfigCacheResourceKey(monitoringv1.ProbesKind, probe)] = struct{}{}
+	}
+	for _, sc := range sCons {
+		resourceKeys[scrapeConfigCacheResourceKey(monitoringv1alpha1.ScrapeConfigsKind, sc)] = struct{}{}
+	}
+
+	cg.scrapeConfigCache.prune(cg.scrapeConfigCachePromKey(), resourceKeys)
+}
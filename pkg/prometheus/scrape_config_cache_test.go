@@ -0,0 +1,229 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+type scrapeConfigCacheTestResources struct {
+	sMons  map[string]*monitoringv1.ServiceMonitor
+	pMons  map[string]*monitoringv1.PodMonitor
+	probes map[string]*monitoringv1.Probe
+	sCons  map[string]*monitoringv1alpha1.ScrapeConfig
+	secret *corev1.Secret
+}
+
+func newScrapeConfigCacheTestResources() *scrapeConfigCacheTestResources {
+	sm := defaultServiceMonitor()
+	sm.UID = types.UID("sm-uid")
+	sm.ResourceVersion = "1"
+	sm.Spec.Endpoints[0].BasicAuth = &monitoringv1.BasicAuth{
+		Username: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "auth"},
+			Key:                  "username",
+		},
+		Password: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "auth"},
+			Key:                  "password",
+		},
+	}
+
+	pm := defaultPodMonitor()
+	pm.UID = types.UID("pm-uid")
+	pm.ResourceVersion = "1"
+
+	probe := defaultProbe()
+	probe.UID = types.UID("probe-uid")
+	probe.ResourceVersion = "1"
+
+	sc := defaultScrapeConfig()
+	sc.UID = types.UID("sc-uid")
+	sc.ResourceVersion = "1"
+
+	secret := &corev1.Secret{}
+	secret.Name = "auth"
+	secret.Namespace = "default"
+	secret.ResourceVersion = "1"
+	secret.Data = map[string][]byte{
+		"username": []byte("user"),
+		"password": []byte("pass"),
+	}
+
+	return &scrapeConfigCacheTestResources{
+		sMons:  map[string]*monitoringv1.ServiceMonitor{"default/defaultServiceMonitor": sm},
+		pMons:  map[string]*monitoringv1.PodMonitor{"default/defaultPodMonitor": pm},
+		probes: map[string]*monitoringv1.Probe{"default/defaultProbe": probe},
+		sCons:  map[string]*monitoringv1alpha1.ScrapeConfig{"default/defaultScrapeConfig": sc},
+		secret: secret,
+	}
+}
+
+func (r *scrapeConfigCacheTestResources) generate(t *testing.T, p *monitoringv1.Prometheus, opts ...ConfigGeneratorOption) string {
+	t.Helper()
+
+	cg, err := NewConfigGenerator(newLogger(), p, opts...)
+	require.NoError(t, err)
+
+	cfg, err := cg.GenerateServerConfiguration(
+		p,
+		r.sMons,
+		r.pMons,
+		r.probes,
+		r.sCons,
+		assets.NewTestStoreBuilder(r.secret),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	return string(cfg)
+}
+
+func requireCacheCounts(t *testing.T, c *ScrapeConfigCache, kind string, hits, misses float64) {
+	t.Helper()
+
+	require.Equal(t, hits, testutil.ToFloat64(c.hits.WithLabelValues(kind)), "hits for %s", kind)
+	require.Equal(t, misses, testutil.ToFloat64(c.misses.WithLabelValues(kind)), "misses for %s", kind)
+}
+
+func TestScrapeConfigCache(t *testing.T) {
+	var (
+		p     = defaultPrometheus()
+		cache = NewScrapeConfigCache()
+		res   = newScrapeConfigCacheTestResources()
+	)
+
+	// The first generation populates the cache.
+	require.Equal(t, res.generate(t, p), res.generate(t, p, WithScrapeConfigCache(cache)))
+	for _, kind := range []string{monitoringv1.ServiceMonitorsKind, monitoringv1.PodMonitorsKind, monitoringv1.ProbesKind, monitoringv1alpha1.ScrapeConfigsKind} {
+		requireCacheCounts(t, cache, kind, 0, 1)
+	}
+
+	// Nothing changed: all the scrape configurations come from the cache.
+	require.Equal(t, res.generate(t, p), res.generate(t, p, WithScrapeConfigCache(cache)))
+	for _, kind := range []string{monitoringv1.ServiceMonitorsKind, monitoringv1.PodMonitorsKind, monitoringv1.ProbesKind, monitoringv1alpha1.ScrapeConfigsKind} {
+		requireCacheCounts(t, cache, kind, 1, 1)
+	}
+
+	// Update the PodMonitor.
+	pm := res.pMons["default/defaultPodMonitor"].DeepCopy()
+	pm.ResourceVersion = "2"
+	pm.Spec.PodMetricsEndpoints[0].Interval = "10s"
+	res.pMons["default/defaultPodMonitor"] = pm
+
+	require.Equal(t, res.generate(t, p), res.generate(t, p, WithScrapeConfigCache(cache)))
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 2, 1)
+	requireCacheCounts(t, cache, monitoringv1.PodMonitorsKind, 1, 2)
+	requireCacheCounts(t, cache, monitoringv1.ProbesKind, 2, 1)
+	requireCacheCounts(t, cache, monitoringv1alpha1.ScrapeConfigsKind, 2, 1)
+
+	// Update the secret referenced by the ServiceMonitor.
+	res.secret = res.secret.DeepCopy()
+	res.secret.ResourceVersion = "2"
+	res.secret.Data["password"] = []byte("new-pass")
+
+	cfg := res.generate(t, p, WithScrapeConfigCache(cache))
+	require.Equal(t, res.generate(t, p), cfg)
+	require.Contains(t, cfg, "new-pass")
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 2, 2)
+	requireCacheCounts(t, cache, monitoringv1.PodMonitorsKind, 2, 2)
+
+	// Update the scrape class of the Probe.
+	p.Spec.ScrapeClasses = []monitoringv1.ScrapeClass{{Name: "custom", SampleLimit: new(int64(100))}}
+	probe := res.probes["default/defaultProbe"].DeepCopy()
+	probe.Spec.ScrapeClassName = new("custom")
+	res.probes["default/defaultProbe"] = probe
+
+	// The Prometheus spec changed too so all entries are invalidated.
+	require.Equal(t, res.generate(t, p), res.generate(t, p, WithScrapeConfigCache(cache)))
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 2, 3)
+	requireCacheCounts(t, cache, monitoringv1.PodMonitorsKind, 2, 3)
+	requireCacheCounts(t, cache, monitoringv1.ProbesKind, 3, 2)
+	requireCacheCounts(t, cache, monitoringv1alpha1.ScrapeConfigsKind, 3, 2)
+
+	// Unselected resources are removed from the cache.
+	delete(res.sMons, "default/defaultServiceMonitor")
+	require.Equal(t, res.generate(t, p), res.generate(t, p, WithScrapeConfigCache(cache)))
+	require.Len(t, cache.entries["default/test"], 3)
+
+	cache.Forget("default/test")
+	require.Empty(t, cache.entries)
+}
+
+func TestScrapeConfigCacheSpecChange(t *testing.T) {
+	var (
+		p     = defaultPrometheus()
+		cache = NewScrapeConfigCache()
+		res   = newScrapeConfigCacheTestResources()
+	)
+
+	res.generate(t, p, WithScrapeConfigCache(cache))
+
+	p.Spec.Shards = new(int32(2))
+	require.Equal(t, res.generate(t, p), res.generate(t, p, WithScrapeConfigCache(cache)))
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 0, 2)
+
+	// Changing the generator's options invalidates the cache too.
+	require.Equal(t, res.generate(t, p, WithEndpointSliceSupport()), res.generate(t, p, WithScrapeConfigCache(cache), WithEndpointSliceSupport()))
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 0, 3)
+}
+
+func TestScrapeConfigCacheWarnings(t *testing.T) {
+	var (
+		p     = defaultPrometheus()
+		cache = NewScrapeConfigCache()
+		res   = newScrapeConfigCacheTestResources()
+	)
+	p.Spec.Version = "v2.55.0"
+	res.sMons["default/defaultServiceMonitor"].Spec.ScrapeProtocols = []monitoringv1.ScrapeProtocol{monitoringv1.PrometheusText1_0_0}
+
+	generate := func() string {
+		var buf bytes.Buffer
+		cg, err := NewConfigGenerator(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})), p, WithScrapeConfigCache(cache))
+		require.NoError(t, err)
+
+		_, err = cg.GenerateServerConfiguration(p, res.sMons, res.pMons, res.probes, res.sCons, assets.NewTestStoreBuilder(res.secret), nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		return buf.String()
+	}
+
+	miss := generate()
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 0, 1)
+	require.Equal(t, 1, strings.Count(miss, "scrapeProtocol=PrometheusText1.0.0"), miss)
+	require.Contains(t, miss, "service_monitor=default/defaultServiceMonitor")
+
+	// The warning is logged again when the scrape configuration comes from the cache.
+	hit := generate()
+	requireCacheCounts(t, cache, monitoringv1.ServiceMonitorsKind, 1, 1)
+	require.Equal(t, 1, strings.Count(hit, "scrapeProtocol=PrometheusText1.0.0"), hit)
+	require.Contains(t, hit, "service_monitor=default/defaultServiceMonitor")
+	require.Contains(t, hit, "version=v2.55.0")
+}
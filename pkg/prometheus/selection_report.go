@@ -0,0 +1,193 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
+)
+
+// NewSelectionReport returns an empty selection report for the Prometheus
+// or PrometheusAgent object.
+func NewSelectionReport(kind string, p monitoringv1.PrometheusInterface) *selectionreport.Report {
+	return selectionreport.NewReport(
+		kind,
+		p.GetObjectMeta().GetNamespace(),
+		p.GetObjectMeta().GetName(),
+		p.GetObjectMeta().GetGeneration(),
+		ShardsNumber(p),
+	)
+}
+
+// AddSelectionCandidates records into the report the namespaces and the
+// label selector used to select the resources of the given kind. It also
+// adds all the resources returned by listFn which aren't part of the
+// selection, with the selectors which didn't match them.
+//
+// The selected resources are added by AddSelectedResources().
+func AddSelectionCandidates[T operator.ConfigurationResource](
+	report *selectionreport.Report,
+	kind string,
+	namespaces []string,
+	selector labels.Selector,
+	listFn ListAllByNamespaceFn,
+	selection operator.TypedResourcesSelection[T],
+) error {
+	if report == nil {
+		return nil
+	}
+
+	report.AddSelector(selectionreport.Selector{
+		Kind:          kind,
+		Namespaces:    namespaces,
+		LabelSelector: selector.String(),
+	})
+
+	selectedNamespaces := sets.New(namespaces...)
+	return listFn(metav1.NamespaceAll, labels.Everything(), func(o any) {
+		obj, ok := o.(metav1.Object)
+		if !ok {
+			return
+		}
+
+		if _, found := selection[fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())]; found {
+			return
+		}
+
+		report.Add(selectionreport.Resource{
+			Kind:                     kind,
+			Namespace:                obj.GetNamespace(),
+			Name:                     obj.GetName(),
+			NamespaceSelectorMatched: selectedNamespaces.Has(obj.GetNamespace()),
+			LabelSelectorMatched:     selector.Matches(labels.Set(obj.GetLabels())),
+			Status:                   selectionreport.NotSelectedResourceStatus,
+		})
+	})
+}
+
+// AddSelectedResources adds to the report the resources selected by the
+// resource selector.
+//
+// The details function is called for the accepted resources to complete the
+// report (e.g. with the scrape job names).
+func AddSelectedResources[T operator.ConfigurationResource](
+	report *selectionreport.Report,
+	kind string,
+	selection operator.TypedResourcesSelection[T],
+	details func(T, *selectionreport.Resource),
+) {
+	if report == nil {
+		return
+	}
+
+	for key, selected := range selection {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+
+		res := selectionreport.Resource{
+			Kind:                     kind,
+			Namespace:                namespace,
+			Name:                     name,
+			NamespaceSelectorMatched: true,
+			LabelSelectorMatched:     true,
+		}
+
+		condition := selected.Conditions()[0]
+		switch condition.Status {
+		case monitoringv1.ConditionTrue:
+			res.Status = selectionreport.AcceptedResourceStatus
+			if details != nil {
+				details(selected.Resource(), &res)
+			}
+		default:
+			res.Status = selectionreport.RejectedResourceStatus
+			res.Reason = condition.Reason
+			res.Message = condition.Message
+		}
+
+		report.Add(res)
+	}
+}
+
+// AddScrapeResources adds to the report the selected ServiceMonitor,
+// PodMonitor, Probe and ScrapeConfig resources. The scrape classes, shards
+// and job names of the accepted resources are resolved by the config
+// generator.
+func (cg *ConfigGenerator) AddScrapeResources(
+	report *selectionreport.Report,
+	sMons operator.TypedResourcesSelection[*monitoringv1.ServiceMonitor],
+	pMons operator.TypedResourcesSelection[*monitoringv1.PodMonitor],
+	probes operator.TypedResourcesSelection[*monitoringv1.Probe],
+	scrapeConfigs operator.TypedResourcesSelection[*monitoringv1alpha1.ScrapeConfig],
+) {
+	if report == nil {
+		return
+	}
+
+	shardAssigner := NewShardAssigner(cg.prom, cg.logger)
+	details := func(o metav1.Object, scrapeClassName *string, res *selectionreport.Resource) {
+		res.ScrapeClass = cg.getScrapeClassOrDefault(scrapeClassName).Name
+		res.Shards = ShardsForResource(cg.prom, shardAssigner, o)
+	}
+
+	AddSelectedResources(report, monitoringv1.ServiceMonitorsKind, sMons, func(m *monitoringv1.ServiceMonitor, res *selectionreport.Resource) {
+		details(m, m.Spec.ScrapeClassName, res)
+		for i := range m.Spec.Endpoints {
+			res.JobNames = append(res.JobNames, serviceMonitorJobName(m, i))
+		}
+	})
+
+	AddSelectedResources(report, monitoringv1.PodMonitorsKind, pMons, func(m *monitoringv1.PodMonitor, res *selectionreport.Resource) {
+		details(m, m.Spec.ScrapeClassName, res)
+		for i := range m.Spec.PodMetricsEndpoints {
+			res.JobNames = append(res.JobNames, podMonitorJobName(m, i))
+		}
+	})
+
+	AddSelectedResources(report, monitoringv1.ProbesKind, probes, func(m *monitoringv1.Probe, res *selectionreport.Resource) {
+		details(m, m.Spec.ScrapeClassName, res)
+		res.JobNames = []string{probeJobName(m)}
+	})
+
+	AddSelectedResources(report, monitoringv1alpha1.ScrapeConfigsKind, scrapeConfigs, func(sc *monitoringv1alpha1.ScrapeConfig, res *selectionreport.Resource) {
+		details(sc, sc.Spec.ScrapeClassName, res)
+		res.JobNames = []string{scrapeConfigJobName(sc)}
+	})
+}
+
+// ShardsForResource returns the shards handling the resource.
+func ShardsForResource(p monitoringv1.PrometheusInterface, shardAssigner *ShardAssigner, o metav1.Object) []int32 {
+	if shardAssigner != nil {
+		return []int32{shardAssigner.ShardFor(o)}
+	}
+
+	shards := make([]int32, 0, ShardsNumber(p))
+	for shard := range ShardsNumber(p) {
+		shards = append(shards, shard)
+	}
+
+	return shards
+}
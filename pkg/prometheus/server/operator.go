@@ -15,12 +15,14 @@
 package prometheus
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +31,7 @@ import (
 	"github.com/prometheus/common/model"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -42,16 +45,19 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
 
+	alertmanagerpkg "github.com/prometheus-operator/prometheus-operator/pkg/alertmanager"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"github.com/prometheus-operator/prometheus-operator/pkg/httpsd"
 	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
 	"github.com/prometheus-operator/prometheus-operator/pkg/listwatch"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
 	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus/validation"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
 	"github.com/prometheus-operator/prometheus-operator/pkg/webconfig"
 )
 
@@ -82,6 +88,7 @@ type Operator struct {
 	config   prompkg.Config
 
 	controllerID string
+	driftPolicy  operator.DriftPolicy
 
 	nsPromInf cache.SharedIndexInformer
 	nsMonInf  cache.SharedIndexInformer
@@ -91,19 +98,37 @@ type Operator struct {
 	pmonInfs  *informers.ForResource
 	probeInfs *informers.ForResource
 	sconInfs  *informers.ForResource
+	quotaInfs *informers.ForResource
+	amInfs    *informers.ForResource
 	ruleInfs  *informers.ForResource
 	cmapInfs  *informers.ForResource
 	secrInfs  *informers.ForResource
 	ssetInfs  *informers.ForResource
 
+	// defaultsInfs is nil when no PrometheusOperatorDefaults object is
+	// configured.
+	defaultsInfs     *informers.ForResource
+	workloadDefaults string
+
 	rr *operator.ResourceReconciler
 
 	metrics         *operator.Metrics
 	reconciliations *operator.ReconciliationTracker
 	statusReporter  *prompkg.StatusReporter
 
+	scrapeConfigCache *prompkg.ScrapeConfigCache
+	shardNamespaces   *prompkg.ShardNamespaces
+	shardRollouts     *prompkg.ShardRollouts
+	reloadChecker     prompkg.ConfigReloadChecker
+
+	httpSDRegistry *httpsd.Registry
+	httpSDURL      string
+
+	selectionReports *selectionreport.Registry
+
 	endpointSliceSupported        bool
 	scrapeConfigSupported         bool
+	monitoringQuotaSupported      bool
 	canReadStorageClass           bool
 	disableUnmanagedConfiguration bool
 	retentionPoliciesEnabled      bool
@@ -125,6 +150,12 @@ type selectedConfigResources struct {
 	bMons         operator.TypedResourcesSelection[*monitoringv1.Probe]
 	scrapeConfigs operator.TypedResourcesSelection[*monitoringv1alpha1.ScrapeConfig]
 	rules         operator.PrometheusRuleSelection
+	// ruleShards are the shards evaluating the selected PrometheusRules
+	// (key=<namespace>/<name>). It is nil when all shards evaluate the same
+	// rules.
+	ruleShards map[string][]int32
+	// report is nil when the selection reports are disabled.
+	report *selectionreport.Report
 }
 
 func (s *selectedConfigResources) Len() int {
@@ -149,6 +180,23 @@ func WithScrapeConfig() ControllerOption {
 	}
 }
 
+// WithMonitoringQuota tells that the controller enforces the MonitoringQuota
+// objects.
+func WithMonitoringQuota() ControllerOption {
+	return func(o *Operator) {
+		o.monitoringQuotaSupported = true
+	}
+}
+
+// WithWorkloadDefaults tells that the controller merges the default values
+// from the PrometheusOperatorDefaults object with the given name into the
+// Prometheus objects.
+func WithWorkloadDefaults(name string) ControllerOption {
+	return func(o *Operator) {
+		o.workloadDefaults = name
+	}
+}
+
 // WithStorageClassValidation tells that the controller should verify that the
 // Prometheus spec references a valid StorageClass name.
 func WithStorageClassValidation() ControllerOption {
@@ -183,29 +231,46 @@ func WithPodTopologyLabels() ControllerOption {
 	}
 }
 
-// New creates a new controller.
-func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger *slog.Logger, r prometheus.Registerer, opts ...ControllerOption) (*Operator, error) {
-	logger = logger.With("component", controllerName)
-
-	client, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating kubernetes client failed: %w", err)
+// WithHTTPServiceDiscovery tells that the operator serves the static targets
+// of Probe and ScrapeConfig objects from the HTTP service discovery endpoint
+// reachable at the given URL.
+func WithHTTPServiceDiscovery(registry *httpsd.Registry, url string) ControllerOption {
+	return func(o *Operator) {
+		o.httpSDRegistry = registry
+		o.httpSDURL = url
 	}
+}
 
-	dclient, err := dynamic.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating dynamic client failed: %w", err)
+// WithSelectionReports tells that the operator publishes the selection
+// reports of the Prometheus objects to the registry.
+func WithSelectionReports(registry *selectionreport.Registry) ControllerOption {
+	return func(o *Operator) {
+		o.selectionReports = registry
 	}
+}
 
-	mdClient, err := metadata.NewForConfig(restConfig)
+// New creates a new controller.
+func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger *slog.Logger, r prometheus.Registerer, opts ...ControllerOption) (*Operator, error) {
+	clients, err := operator.NewClients(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("instantiating metadata client failed: %w", err)
+		return nil, err
 	}
 
-	mclient, err := monitoringclient.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating monitoring client failed: %w", err)
-	}
+	return NewForClients(ctx, clients, c, logger, r, opts...)
+}
+
+// NewForClients creates a new controller which interacts with the
+// Kubernetes API using the given clients.
+func NewForClients(ctx context.Context, clients *operator.Clients, c operator.Config, logger *slog.Logger, r prometheus.Registerer, opts ...ControllerOption) (*Operator, error) {
+	logger = logger.With("component", controllerName)
+
+	var (
+		client   = clients.Kubernetes
+		dclient  = clients.Dynamic
+		mdClient = clients.Metadata
+		mclient  = clients.Monitoring
+		err      error
+	)
 
 	// All the metrics exposed by the controller get the controller="prometheus" label.
 	r = prometheus.WrapRegistererWith(prometheus.Labels{"controller": "prometheus"}, r)
@@ -227,10 +292,15 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 			Labels:                         c.Labels,
 			WatchObjectRefsInAllNamespaces: c.WatchObjectRefsInAllNamespaces,
 		},
-		metrics:         operator.NewMetrics(r),
-		reconciliations: &operator.ReconciliationTracker{},
+		metrics:           operator.NewMetrics(r),
+		reconciliations:   &operator.ReconciliationTracker{},
+		scrapeConfigCache: prompkg.NewScrapeConfigCache(),
+		shardNamespaces:   prompkg.NewShardNamespaces(),
+		shardRollouts:     prompkg.NewShardRollouts(),
+		reloadChecker:     prompkg.NewConfigReloadChecker(),
 
 		controllerID:             c.ControllerID,
+		driftPolicy:              c.DriftPolicy,
 		newEventRecorder:         c.EventRecorderFactory(client, controllerName),
 		retentionPoliciesEnabled: c.Gates.Enabled(operator.PrometheusShardRetentionPolicyFeature),
 		topologyShardingEnabled:  c.Gates.Enabled(operator.PrometheusTopologyShardingFeature),
@@ -244,7 +314,7 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		o.finalizerSyncer = operator.NewFinalizerSyncer(mdClient, monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.PrometheusName))
 	}
 
-	o.metrics.MustRegister(o.reconciliations)
+	o.metrics.MustRegister(o.reconciliations, o.scrapeConfigCache)
 
 	o.promInfs, err = informers.NewInformersForResource(
 		informers.NewMonitoringInformerFactories(
@@ -335,6 +405,46 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 			return nil, fmt.Errorf("error creating scrapeconfigs informers: %w", err)
 		}
 	}
+
+	if o.monitoringQuotaSupported {
+		o.quotaInfs, err = informers.NewInformersForResource(
+			informers.NewMonitoringInformerFactories(
+				c.Namespaces.AllowList,
+				c.Namespaces.DenyList,
+				mclient,
+				resyncPeriod,
+				nil,
+			),
+			monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.MonitoringQuotaName),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating monitoringquota informers: %w", err)
+		}
+	}
+
+	if o.workloadDefaults != "" {
+		o.defaultsInfs, err = operator.NewWorkloadDefaultsInformers(mclient, o.workloadDefaults, resyncPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("error creating prometheusoperatordefaults informers: %w", err)
+		}
+	}
+
+	// The Alertmanager objects are watched to resolve the references from
+	// the alerting configuration.
+	o.amInfs, err = informers.NewInformersForResource(
+		informers.NewMonitoringInformerFactories(
+			c.Namespaces.AlertmanagerAllowList,
+			c.Namespaces.DenyList,
+			mclient,
+			resyncPeriod,
+			nil,
+		),
+		monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.AlertmanagerName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating alertmanager informers: %w", err)
+	}
+
 	o.ruleInfs, err = informers.NewInformersForResource(
 		informers.NewMonitoringInformerFactories(
 			c.Namespaces.AllowList,
@@ -463,6 +573,9 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 		{"PrometheusRule", c.ruleInfs},
 		{"Probe", c.probeInfs},
 		{"ScrapeConfig", c.sconInfs},
+		{"MonitoringQuota", c.quotaInfs},
+		{"PrometheusOperatorDefaults", c.defaultsInfs},
+		{"Alertmanager", c.amInfs},
 		{"ConfigMap", c.cmapInfs},
 		{"Secret", c.secrInfs},
 		{"StatefulSet", c.ssetInfs},
@@ -499,6 +612,19 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 // addHandlers adds the eventhandlers to the informers.
 func (c *Operator) addHandlers() {
 	c.promInfs.AddEventHandler(c.rr)
+	c.promInfs.AddEventHandler(operator.NewEventHandler(
+		c.logger,
+		c.accessor,
+		c.metrics,
+		monitoringv1.PrometheusesKind,
+		c.enqueueForPrometheusNamespace,
+		operator.WithFilter(
+			operator.AnyFilter(
+				operator.GenerationChanged,
+				operator.LabelsChanged,
+			),
+		),
+	))
 
 	c.ssetInfs.AddEventHandler(c.rr)
 
@@ -560,6 +686,42 @@ func (c *Operator) addHandlers() {
 		))
 	}
 
+	if c.quotaInfs != nil {
+		c.quotaInfs.AddEventHandler(operator.NewEventHandler(
+			c.logger,
+			c.accessor,
+			c.metrics,
+			monitoringv1alpha1.MonitoringQuotasKind,
+			c.enqueueForNamespaceFunc(c.nsMonInf.GetStore()),
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
+	if c.defaultsInfs != nil {
+		c.defaultsInfs.AddEventHandler(operator.NewEventHandler(
+			c.logger,
+			c.accessor,
+			c.metrics,
+			monitoringv1alpha1.PrometheusOperatorDefaultsKind,
+			c.enqueueAll,
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
+	c.amInfs.AddEventHandler(operator.NewEventHandler(
+		c.logger,
+		c.accessor,
+		c.metrics,
+		monitoringv1.AlertmanagersKind,
+		c.enqueueForAlertmanagerNamespace,
+		operator.WithFilter(
+			operator.AnyFilter(
+				operator.GenerationChanged,
+				operator.LabelsChanged,
+			),
+		),
+	))
+
 	c.ruleInfs.AddEventHandler(operator.NewEventHandler(
 		c.logger,
 		c.accessor,
@@ -615,11 +777,9 @@ func (c *Operator) addHandlers() {
 	})
 }
 
-// Run the controller.
-func (c *Operator) Run(ctx context.Context) error {
-	go c.rr.Run(ctx)
-	defer c.rr.Stop()
-
+// StartInformers starts the informers of the controller and waits for
+// their caches to be synced.
+func (c *Operator) StartInformers(ctx context.Context) error {
 	go c.promInfs.Start(ctx.Done())
 	go c.smonInfs.Start(ctx.Done())
 	go c.pmonInfs.Start(ctx.Done())
@@ -627,6 +787,13 @@ func (c *Operator) Run(ctx context.Context) error {
 	if c.scrapeConfigSupported {
 		go c.sconInfs.Start(ctx.Done())
 	}
+	if c.monitoringQuotaSupported {
+		go c.quotaInfs.Start(ctx.Done())
+	}
+	if c.defaultsInfs != nil {
+		go c.defaultsInfs.Start(ctx.Done())
+	}
+	go c.amInfs.Start(ctx.Done())
 	go c.ruleInfs.Start(ctx.Done())
 	go c.cmapInfs.Start(ctx.Done())
 	go c.secrInfs.Start(ctx.Done())
@@ -635,7 +802,16 @@ func (c *Operator) Run(ctx context.Context) error {
 	if c.nsPromInf != c.nsMonInf {
 		go c.nsPromInf.Run(ctx.Done())
 	}
-	if err := c.waitForCacheSync(ctx); err != nil {
+
+	return c.waitForCacheSync(ctx)
+}
+
+// Run the controller.
+func (c *Operator) Run(ctx context.Context) error {
+	go c.rr.Run(ctx)
+	defer c.rr.Stop()
+
+	if err := c.StartInformers(ctx); err != nil {
 		return err
 	}
 
@@ -713,6 +889,69 @@ func (c *Operator) enqueueForNamespaceFunc(gbk operator.GetByKeyer) func(string)
 	}
 }
 
+// enqueueAll enqueues all the Prometheus objects.
+func (c *Operator) enqueueAll(string) {
+	err := c.promInfs.ListAll(labels.Everything(), func(obj any) {
+		c.rr.EnqueueForReconciliation(obj.(*monitoringv1.Prometheus))
+	})
+	if err != nil {
+		c.logger.Error(
+			"listing all Prometheus instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+// enqueueForAlertmanagerNamespace enqueues the Prometheus objects referencing
+// Alertmanager objects in the given namespace.
+func (c *Operator) enqueueForAlertmanagerNamespace(nsName string) {
+	err := c.promInfs.ListAll(labels.Everything(), func(obj any) {
+		p := obj.(*monitoringv1.Prometheus)
+		if p.Spec.Alerting == nil {
+			return
+		}
+
+		for _, am := range p.Spec.Alerting.Alertmanagers {
+			if am.AlertmanagerRef != nil && ptr.Deref(am.AlertmanagerRef.Namespace, p.Namespace) == nsName {
+				c.rr.EnqueueForReconciliation(p)
+				return
+			}
+		}
+	})
+	if err != nil {
+		c.logger.Error(
+			"listing all Prometheus instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+// enqueueForPrometheusNamespace enqueues the Prometheus objects referencing
+// Prometheus objects in the given namespace for remote write or remote read.
+func (c *Operator) enqueueForPrometheusNamespace(nsName string) {
+	err := c.promInfs.ListAll(labels.Everything(), func(obj any) {
+		p := obj.(*monitoringv1.Prometheus)
+
+		refs := make([]*monitoringv1.PrometheusReference, 0, len(p.Spec.RemoteWrite)+len(p.Spec.RemoteRead))
+		for _, rw := range p.Spec.RemoteWrite {
+			refs = append(refs, rw.PrometheusRef)
+		}
+		for _, rr := range p.Spec.RemoteRead {
+			refs = append(refs, rr.PrometheusRef)
+		}
+
+		if prompkg.ReferencesNamespace(p.Namespace, nsName, refs...) {
+			c.rr.EnqueueForReconciliation(p)
+		}
+	})
+	if err != nil {
+		c.logger.Error(
+			"listing all Prometheus instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
 // enqueueForNamespace enqueues all Prometheus object keys that belong to the
 // given namespace or select objects in the given namespace.
 func (c *Operator) enqueueForNamespace(gbk operator.GetByKeyer, nsName string) {
@@ -906,6 +1145,11 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 
 	if p == nil {
 		c.reconciliations.ForgetObject(key)
+		c.scrapeConfigCache.Forget(key)
+		c.shardNamespaces.Delete(key)
+		c.shardRollouts.Delete(key)
+		c.forgetHTTPSDTargets(key)
+		c.forgetSelectionReport(key)
 		// Dependent resources are cleaned up by K8s via OwnerReferences
 		return closure, nil
 	}
@@ -928,6 +1172,11 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 
 	if c.rr.DeletionInProgress(p) {
 		c.reconciliations.ForgetObject(key)
+		c.scrapeConfigCache.Forget(key)
+		c.shardNamespaces.Delete(key)
+		c.shardRollouts.Delete(key)
+		c.forgetHTTPSDTargets(key)
+		c.forgetSelectionReport(key)
 		return closure, nil
 	}
 
@@ -936,6 +1185,25 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		return closure, nil
 	}
 
+	ctx = operator.WithDriftReporting(ctx, logger, c.metrics, c.newEventRecorder(p), c.driftPolicy)
+
+	if err := k8s.ValidateOverlays(p.Spec.Overlays); err != nil {
+		return closure, err
+	}
+
+	defaults, err := operator.GetWorkloadDefaults(c.defaultsInfs, c.workloadDefaults)
+	if err != nil {
+		return closure, err
+	}
+	defaulted := operator.ApplyPrometheusDefaults(p, defaults)
+	if len(defaulted) > 0 {
+		logger.Debug("default values applied", "fields", defaulted)
+	}
+	defaultedValues, err := operator.DefaultedPrometheusValues(&p.Spec.CommonPrometheusFields, defaulted)
+	if err != nil {
+		return closure, err
+	}
+
 	c.recordDeprecatedFields(key, logger, p)
 
 	if err := operator.CheckStorageClass(ctx, c.canReadStorageClass, c.kclient, p.Spec.Storage); err != nil {
@@ -950,6 +1218,14 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		return closure, err
 	}
 
+	if defaults != nil {
+		resources.report.SetDefaults(&selectionreport.Defaults{
+			Name:       defaults.Name,
+			Generation: defaults.Generation,
+			Fields:     defaulted,
+		})
+	}
+
 	// Returns updateConfigResourcesStatus as the closure
 	// so that we can call it at the end of each sync.
 	closure = func(ctx context.Context) error {
@@ -960,12 +1236,15 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		c.reconciliations.SetReasonAndMessage(key, operator.NoSelectedResourcesReason, noSelectedResourcesMessage)
 	}
 
-	ruleConfigMapNames, err := c.createOrUpdateRuleConfigMaps(ctx, p, resources.rules, logger)
+	ruleConfigMapNames, ruleShards, err := c.createOrUpdateRuleConfigMaps(ctx, p, resources.rules, logger)
 	if err != nil {
 		return closure, err
 	}
+	resources.ruleShards = ruleShards
 
-	opts := []prompkg.ConfigGeneratorOption{}
+	opts := []prompkg.ConfigGeneratorOption{
+		prompkg.WithScrapeConfigCache(c.scrapeConfigCache),
+	}
 	if c.endpointSliceSupported {
 		opts = append(opts, prompkg.WithEndpointSliceSupport())
 	}
@@ -978,21 +1257,41 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 	if c.podTopologyLabelsSupported {
 		opts = append(opts, prompkg.WithPodTopologyLabelsSupport())
 	}
+	if c.httpSDRegistry != nil {
+		opts = append(opts, prompkg.WithOperatorHTTPSD(c.httpSDURL, c.httpSDRegistry))
+	}
+	if p.Spec.ShardRolloutStrategy != nil && !c.unmanagedPrometheusConfiguration(p) {
+		opts = append(opts, prompkg.WithShardConfigurationRollout())
+	}
 	cg, err := prompkg.NewConfigGenerator(logger, p, opts...)
 	if err != nil {
 		return closure, err
 	}
 
-	if err := c.createOrUpdateConfigurationSecret(ctx, logger, p, cg, ruleConfigMapNames, assetStore, resources); err != nil {
+	if err := c.updateSelectionReport(p, cg, resources); err != nil {
+		return closure, err
+	}
+
+	// Publish the static targets before updating the configuration so that
+	// they are available as soon as Prometheus reloads.
+	c.updateHTTPSDTargets(p, cg, resources)
+
+	configSecret, err := c.makeConfigurationSecret(ctx, logger, p, cg, ruleConfigMapNames, assetStore, resources)
+	if err != nil {
 		return closure, fmt.Errorf("creating config failed: %w", err)
 	}
 	c.reconciliations.UpdateReferenceTracker(key, assetStore.RefTracker())
 
-	tlsAssets, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), c.kclient, prompkg.NewTLSAssetSecret(p, c.config))
+	tlsAssets, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), c.kclient, prompkg.NewTLSAssetSecret(p, c.config), p.Spec.Overlays)
 	if err != nil {
 		return closure, fmt.Errorf("failed to reconcile the TLS secrets: %w", err)
 	}
 
+	scrapeConfigFiles, err := c.generateScrapeConfigFiles(ctx, p, cg, assetStore, resources)
+	if err != nil {
+		return closure, err
+	}
+
 	if err := c.createOrUpdateWebConfigSecret(ctx, p); err != nil {
 		return closure, fmt.Errorf("synchronizing web config secret failed: %w", err)
 	}
@@ -1009,30 +1308,41 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			return closure, err
 		}
 	} else {
-		// Reconcile the default governing service.
-		svc := prompkg.BuildStatefulSetService(
-			governingServiceName,
-			map[string]string{
-				operator.ApplicationNameLabelKey: applicationNameLabelValue,
-			},
-			p,
-			c.config,
-		)
+		if err := c.createOrUpdateGoverningService(ctx, p); err != nil {
+			return closure, err
+		}
+	}
 
-		if p.Spec.Thanos != nil {
-			svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
-				Name:       "grpc",
-				Port:       10901,
-				TargetPort: intstr.FromString("grpc"),
-			})
+	ssetClient := c.kclient.AppsV1().StatefulSets(p.Namespace)
+
+	var (
+		pdbs     []*policyv1.PodDisruptionBudget
+		desired  []*appsv1.StatefulSet
+		np       = operator.NewNetworkPolicyBuilder(p.Namespace, makeSelectorLabels(p.Name))
+		exposure = c.newExposure(p)
+	)
+
+	rollout, err := prompkg.NewShardRollout(ctx, c.kclient, c.ssetInfs, c.reloadChecker, p, key)
+	if err != nil {
+		return closure, fmt.Errorf("failed to compute the shard rollout: %w", err)
+	}
+
+	// When the configuration changes are rolled out shard by shard, the
+	// configuration files of the existing shards are updated only when the
+	// shard rollout allows it.
+	var shardConfigs *prompkg.ShardConfigurations
+	if configSecret != nil {
+		shardConfigs, err = prompkg.NewShardConfigurations(ctx, c.kclient, c.ssetInfs, cg, key, configSecret, scrapeConfigFiles)
+		if err != nil {
+			return closure, err
 		}
 
-		if _, err := k8s.CreateOrUpdateService(ctx, c.kclient.CoreV1().Services(p.Namespace), svc); err != nil {
-			return closure, fmt.Errorf("synchronizing default governing service failed: %w", err)
+		logger.Debug("updating Prometheus configuration secret")
+		if err := shardConfigs.Write(ctx, c.kclient); err != nil {
+			return closure, fmt.Errorf("creating config failed: %w", err)
 		}
 	}
-
-	ssetClient := c.kclient.AppsV1().StatefulSets(p.Namespace)
+	var releasedConfigs bool
 
 	// Reconcile all active statefulset shards.
 	expected := prompkg.ExpectedStatefulSetShardNames(p)
@@ -1053,6 +1363,11 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		if obj != nil {
 			existingStatefulSet = obj.(*appsv1.StatefulSet)
 			if c.rr.DeletionInProgress(existingStatefulSet) {
+				if p.Spec.PodDisruptionBudget != nil {
+					pdbs = append(pdbs, operator.MakePodDisruptionBudget(*p.Spec.PodDisruptionBudget, existingStatefulSet))
+				}
+				np.AddPodSpec(&existingStatefulSet.Spec.Template.Spec)
+
 				// We want to avoid entering a hot-loop of update/delete cycles
 				// here since the sts was marked for deletion in foreground,
 				// which means it may take some time before the finalizers
@@ -1064,7 +1379,9 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			}
 		}
 
-		newSSetInputHash, err := createSSetInputHash(*p, c.config, ruleConfigMapNames, tlsAssets, existingStatefulSet.Spec)
+		shardScrapeConfigFiles := c.makeScrapeConfigFilesSecret(p, cg, scrapeConfigFiles, int32(shard))
+
+		newSSetInputHash, err := createSSetInputHash(*p, defaultedValues, c.config, ruleConfigMapNames, tlsAssets, shardScrapeConfigFiles, existingStatefulSet.Spec)
 		if err != nil {
 			return closure, err
 		}
@@ -1077,12 +1394,73 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			ruleConfigMapNames,
 			newSSetInputHash,
 			int32(shard),
-			tlsAssets)
+			tlsAssets,
+			shardScrapeConfigFiles)
 		if err != nil {
 			return closure, fmt.Errorf("making statefulset failed: %w", err)
 		}
 		operator.SanitizeSTS(sset)
 
+		var configDigest string
+		if shardConfigs != nil {
+			configDigest = shardConfigs.Digest(int32(shard))
+		}
+		if configDigest != "" {
+			sset.Annotations[prompkg.ConfigDigestAnnotationKey] = configDigest
+		}
+
+		if err := k8s.ApplyOverlays(p.Spec.Overlays, sset); err != nil {
+			return closure, err
+		}
+		desired = append(desired, sset)
+
+		if p.Spec.PodDisruptionBudget != nil {
+			pdbs = append(pdbs, operator.MakePodDisruptionBudget(*p.Spec.PodDisruptionBudget, sset))
+		}
+		np.AddPodSpec(&sset.Spec.Template.Spec)
+		if exposure != nil {
+			exposure.AddStatefulSet(sset)
+		}
+
+		var existing *appsv1.StatefulSet
+		if obj != nil {
+			existing = existingStatefulSet
+		}
+
+		changed := newSSetInputHash != existingStatefulSet.Annotations[operator.InputHashAnnotationKey] ||
+			configDigest != existingStatefulSet.Annotations[prompkg.ConfigDigestAnnotationKey]
+
+		// The configuration and the statefulset of an existing shard are
+		// updated only when the shard rollout allows it.
+		if existing != nil && changed {
+			if !rollout.Allow(sset, existing) {
+				logger.Debug("holding back the statefulset and configuration updates until the previous shards are rolled out")
+				continue
+			}
+
+			if shardConfigs != nil && shardConfigs.Release(int32(shard)) {
+				releasedConfigs = true
+			}
+		}
+
+		if shardScrapeConfigFiles != nil {
+			if err := shardScrapeConfigFiles.Update(ctx, c.kclient); err != nil {
+				return closure, fmt.Errorf("failed to reconcile the scrape configuration secrets: %w", err)
+			}
+		}
+
+		migrating, err := c.migrateStorage(ctx, logger, p, existing, sset)
+		if err != nil {
+			return closure, fmt.Errorf("failed to migrate the storage: %w", err)
+		}
+
+		if migrating {
+			logger.Debug("storage migration in progress")
+			c.rr.EnqueueForReconciliationAfter(p, storageMigrationRequeueInterval)
+			c.rr.EnqueueForStatus(p)
+			continue
+		}
+
 		if notFound {
 			logger.Debug("creating statefulset")
 			if _, err := k8s.CreateStatefulSetOrPatchLabels(ctx, ssetClient, sset); err != nil {
@@ -1091,7 +1469,7 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			continue
 		}
 
-		if newSSetInputHash == existingStatefulSet.Annotations[operator.InputHashAnnotationKey] {
+		if !changed {
 			logger.Debug("new statefulset generation inputs match current, skipping any actions")
 			continue
 		}
@@ -1102,6 +1480,20 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			"existing_hash", existingStatefulSet.Annotations[operator.InputHashAnnotationKey],
 		)
 
+		expand, err := operator.ExpandPersistentVolumeClaims(ctx, c.kclient, c.canReadStorageClass, existingStatefulSet, sset)
+		if err != nil {
+			return closure, err
+		}
+
+		if expand {
+			c.metrics.StsDeleteCreateCounter().Inc()
+			logger.Info("recreating StatefulSet because the volume claim templates have been expanded")
+			if err := k8s.OrphanDeleteStatefulSet(ctx, ssetClient, sset.Name); err != nil {
+				return closure, fmt.Errorf("failed to delete statefulset: %w", err)
+			}
+			continue
+		}
+
 		if err = k8s.ForceUpdateStatefulSet(ctx, ssetClient, sset, func(reason string) {
 			c.metrics.StsDeleteCreateCounter().Inc()
 			logger.Info("recreating StatefulSet because the update operation wasn't possible", "reason", reason)
@@ -1110,6 +1502,57 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		}
 	}
 
+	if err := operator.ReconcilePodDisruptionBudgets(
+		ctx,
+		c.kclient,
+		p,
+		labels.SelectorFromSet(labels.Set{prompkg.PrometheusNameLabelName: p.Name, prompkg.PrometheusModeLabelName: prometheusMode}),
+		pdbs,
+	); err != nil {
+		return closure, err
+	}
+
+	if err := c.reconcileNetworkPolicy(ctx, logger, p, np, resources); err != nil {
+		return closure, err
+	}
+
+	if err := operator.ReconcileExposure(
+		ctx,
+		logger,
+		c.kclient,
+		c.dclient,
+		p,
+		prompkg.PrefixedName(p),
+		exposure,
+		operator.WithLabels(c.config.Labels),
+		operator.WithAnnotations(c.config.Annotations),
+		operator.WithManagingOwner(p),
+	); err != nil {
+		return closure, fmt.Errorf("failed to reconcile the exposure: %w", err)
+	}
+
+	if releasedConfigs {
+		logger.Debug("updating the configuration of the rolled out shards")
+		if err := shardConfigs.Write(ctx, c.kclient); err != nil {
+			return closure, fmt.Errorf("creating config failed: %w", err)
+		}
+	}
+
+	c.shardRollouts.Set(key, rollout)
+	if rollout.InProgress() {
+		if stalled := rollout.Stalled(); len(stalled) > 0 {
+			logger.Warn("shard rollout paused because some statefulsets didn't become available within the progress deadline", "statefulsets", strings.Join(stalled, ","))
+		}
+		c.rr.EnqueueForReconciliationAfter(p, prompkg.ShardRolloutRequeueInterval)
+		c.rr.EnqueueForStatus(p)
+	}
+
+	if operator.UpdateStorageResizedCondition(ctx, c.kclient, c.reconciliations, key, desired, p.Generation) {
+		logger.Debug("persistent volume claims resize in progress")
+		c.rr.EnqueueForReconciliationAfter(p, operator.StorageResizeRequeueInterval)
+		c.rr.EnqueueForStatus(p)
+	}
+
 	ssets := map[string]struct{}{}
 	for _, ssetName := range expected {
 		ssets[ssetName] = struct{}{}
@@ -1135,7 +1578,20 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			return
 		}
 
+		shard, err := strconv.ParseInt(s.Labels[prompkg.ShardLabelName], 10, 32)
+		if err != nil {
+			logger.Warn("failed to parse the shard label", "err", err, "statefulset", fmt.Sprintf("%s/%s", s.Namespace, s.Name))
+			shard = -1
+		}
+
 		if !shouldDelete {
+			// The scrape configuration files of the retained shard are
+			// refreshed so that it stops scraping the targets.
+			if shard >= 0 {
+				if _, err := c.createOrUpdateScrapeConfigFilesSecret(ctx, p, cg, scrapeConfigFiles, int32(shard)); err != nil {
+					deleteErrs = append(deleteErrs, err)
+				}
+			}
 			return
 		}
 
@@ -1143,6 +1599,13 @@ func (c *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			if !apierrors.IsNotFound(err) {
 				deleteErrs = append(deleteErrs, fmt.Errorf("failed to delete StatefulSet %s: %w", s.GetName(), err))
 			}
+			return
+		}
+
+		if shard > 0 {
+			if err := c.cleanupScrapeConfigFilesSecret(ctx, p, int32(shard)); err != nil {
+				deleteErrs = append(deleteErrs, err)
+			}
 		}
 	})
 	if err != nil {
@@ -1194,7 +1657,7 @@ func (c *Operator) updateConfigResourcesStatus(ctx context.Context, p *monitorin
 
 	// Update the status of selected prometheusRules.
 	for key, configResource := range resources.rules.Selected() {
-		if err := configResourceSyncer.UpdateBinding(ctx, configResource.Resource(), configResource.Conditions()); err != nil {
+		if err := configResourceSyncer.UpdateBinding(ctx, configResource.Resource(), configResource.Conditions(), operator.WithBindingShards(resources.ruleShards[key])); err != nil {
 			return fmt.Errorf("failed to update PrometheusRule %s status: %w", key, err)
 		}
 	}
@@ -1351,7 +1814,7 @@ func gracePeriodForPrometheusStorage(p *monitoringv1.Prometheus) (time.Duration,
 	if p.Spec.ShardRetentionPolicy.Retain != nil {
 		retention = p.Spec.ShardRetentionPolicy.Retain.RetentionPeriod
 	} else {
-		if p.Spec.RetentionSize != "" && p.Spec.Retention == "" {
+		if prompkg.RetentionSize(p.Spec) != "" && p.Spec.Retention == "" {
 			return time.Duration(0), nil
 		}
 
@@ -1390,6 +1853,13 @@ func (c *Operator) UpdateStatus(ctx context.Context, key string) error {
 		return fmt.Errorf("failed to get prometheus status: %w", err)
 	}
 
+	c.shardNamespaces.UpdateStatus(key, pStatus)
+	c.shardRollouts.UpdateStatus(key, p.Status.Conditions, pStatus)
+
+	if cond := c.storageMigratedCondition(ctx, p); cond != nil {
+		pStatus.Conditions = operator.UpdateConditions(p.Status.Conditions, append(pStatus.Conditions, *cond)...)
+	}
+
 	p.Status = *pStatus
 	selectorLabels := makeSelectorLabels(p.Name)
 	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: selectorLabels})
@@ -1465,10 +1935,11 @@ func (c *Operator) unmanagedPrometheusConfiguration(p *monitoringv1.Prometheus)
 		p.Spec.ServiceMonitorSelector == nil &&
 		p.Spec.PodMonitorSelector == nil &&
 		p.Spec.ProbeSelector == nil &&
-		p.Spec.ScrapeConfigSelector == nil
+		p.Spec.ScrapeConfigSelector == nil &&
+		p.Spec.AnnotationDiscovery == nil
 }
 
-func createSSetInputHash(p monitoringv1.Prometheus, c prompkg.Config, ruleConfigMapNames []string, tlsAssets *operator.ShardedSecret, ssSpec appsv1.StatefulSetSpec) (string, error) {
+func createSSetInputHash(p monitoringv1.Prometheus, defaultedValues string, c prompkg.Config, ruleConfigMapNames []string, tlsAssets *operator.ShardedSecret, scrapeConfigFiles *operator.ShardedSecret, ssSpec appsv1.StatefulSetSpec) (string, error) {
 	var http2 *bool
 	if p.Spec.Web != nil && p.Spec.Web.HTTPConfig != nil {
 		http2 = p.Spec.Web.HTTPConfig.HTTP2
@@ -1480,23 +1951,29 @@ func createSSetInputHash(p monitoringv1.Prometheus, c prompkg.Config, ruleConfig
 	ssSpec.RevisionHistoryLimit = nil
 
 	hash, err := hashstructure.Hash(struct {
-		PrometheusLabels      map[string]string
-		PrometheusAnnotations map[string]string
-		PrometheusGeneration  int64
-		PrometheusWebHTTP2    *bool
-		Config                prompkg.Config
-		StatefulSetSpec       appsv1.StatefulSetSpec
-		RuleConfigMaps        []string `hash:"set"`
-		ShardedSecret         *operator.ShardedSecret
+		PrometheusLabels          map[string]string
+		PrometheusAnnotations     map[string]string
+		PrometheusGeneration      int64
+		PrometheusWebHTTP2        *bool
+		PrometheusOverlays        []monitoringv1.Overlay
+		PrometheusDefaultedValues string
+		Config                    prompkg.Config
+		StatefulSetSpec           appsv1.StatefulSetSpec
+		RuleConfigMaps            []string `hash:"set"`
+		ShardedSecret             *operator.ShardedSecret
+		ScrapeConfigFiles         *operator.ShardedSecret
 	}{
-		PrometheusLabels:      p.Labels,
-		PrometheusAnnotations: p.Annotations,
-		PrometheusGeneration:  p.Generation,
-		PrometheusWebHTTP2:    http2,
-		Config:                c,
-		StatefulSetSpec:       ssSpec,
-		RuleConfigMaps:        ruleConfigMapNames,
-		ShardedSecret:         tlsAssets,
+		PrometheusLabels:          p.Labels,
+		PrometheusAnnotations:     p.Annotations,
+		PrometheusGeneration:      p.Generation,
+		PrometheusWebHTTP2:        http2,
+		PrometheusOverlays:        p.Spec.Overlays,
+		PrometheusDefaultedValues: defaultedValues,
+		Config:                    c,
+		StatefulSetSpec:           ssSpec,
+		RuleConfigMaps:            ruleConfigMapNames,
+		ShardedSecret:             tlsAssets,
+		ScrapeConfigFiles:         scrapeConfigFiles,
 	},
 		nil,
 	)
@@ -1509,8 +1986,26 @@ func createSSetInputHash(p monitoringv1.Prometheus, c prompkg.Config, ruleConfig
 
 // getSeletedConfigResources returns all the configuration resources (PodMonitor, ServiceMonitor, Probes and ScrapeConfigs) selected by the Prometheus.
 func (c *Operator) getSelectedConfigResources(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, store *assets.StoreBuilder) (*selectedConfigResources, error) {
-	resourceSelector, err := prompkg.NewResourceSelector(logger, p, store, c.nsMonInf, c.metrics, c.newEventRecorder(p))
+	quotas, err := operator.ListMonitoringQuotas(c.quotaInfs)
+	if err != nil {
+		return nil, err
+	}
+
+	var report *selectionreport.Report
+	if c.selectionReports != nil {
+		report = prompkg.NewSelectionReport(monitoringv1.PrometheusesKind, p)
+	}
 
+	resourceSelector, err := prompkg.NewResourceSelector(
+		logger,
+		p,
+		store,
+		c.nsMonInf,
+		c.metrics,
+		c.newEventRecorder(p),
+		prompkg.WithMonitoringQuotas(quotas),
+		prompkg.WithSelectionReport(report),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -1537,7 +2032,7 @@ func (c *Operator) getSelectedConfigResources(ctx context.Context, logger *slog.
 		}
 	}
 
-	rules, err := c.selectPrometheusRules(p, logger)
+	rules, err := c.selectPrometheusRules(p, quotas, logger)
 	if err != nil {
 		return nil, fmt.Errorf("selecting PrometheusRule failed: %w", err)
 	}
@@ -1548,10 +2043,14 @@ func (c *Operator) getSelectedConfigResources(ctx context.Context, logger *slog.
 		pMons:         pmons,
 		scrapeConfigs: scrapeConfigs,
 		rules:         rules,
+		report:        report,
 	}, nil
 }
 
-func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, ruleConfigMapNames []string, store *assets.StoreBuilder, resources *selectedConfigResources) error {
+// makeConfigurationSecret returns the configuration secret generated for the
+// Prometheus object. It returns nil when the configuration isn't managed by
+// the operator.
+func (c *Operator) makeConfigurationSecret(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, ruleConfigMapNames []string, store *assets.StoreBuilder, resources *selectedConfigResources) (*corev1.Secret, error) {
 	// If no service/pod monitor and probe selectors are configured, the user
 	// wants to manage configuration themselves. Let's create an empty Secret
 	// if it doesn't exist.
@@ -1559,7 +2058,7 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, logger
 
 		s, err := prompkg.MakeConfigurationSecret(p, c.config, nil)
 		if err != nil {
-			return fmt.Errorf("failed to generate empty configuration secret: %w", err)
+			return nil, fmt.Errorf("failed to generate empty configuration secret: %w", err)
 		}
 
 		sClient := c.kclient.CoreV1().Secrets(p.Namespace)
@@ -1567,59 +2066,120 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, logger
 		if apierrors.IsNotFound(err) {
 			logger.Debug("creating an empty configuration secret")
 			if _, err := c.kclient.CoreV1().Secrets(p.Namespace).Create(ctx, s, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
-				return fmt.Errorf("failed to create an empty configuration secret: %w", err)
+				return nil, fmt.Errorf("failed to create an empty configuration secret: %w", err)
 			}
 
-			return nil
+			return nil, nil
 		}
 
-		return err
+		return nil, err
+	}
+
+	remoteWrite, err := prompkg.ResolveRemoteWriteReferences(c.promInfs, p.GetNamespace(), p.Spec.RemoteWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteRead, err := prompkg.ResolveRemoteReadReferences(c.promInfs, p.GetNamespace(), p.Spec.RemoteRead)
+	if err != nil {
+		return nil, err
 	}
 
+	// The configuration is generated from the resolved references.
+	p = p.DeepCopy()
+	p.Spec.RemoteWrite = remoteWrite
+	p.Spec.RemoteRead = remoteRead
+
 	if err := prompkg.AddRemoteReadsToStore(ctx, store, p.GetNamespace(), p.Spec.RemoteRead); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := cg.AddRemoteWriteToStore(ctx, store, p.GetNamespace(), p.Spec.RemoteWrite); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := prompkg.AddAPIServerConfigToStore(ctx, store, p.GetNamespace(), p.Spec.APIServerConfig); err != nil {
-		return err
+		return nil, err
 	}
 
 	if p.Spec.Alerting != nil {
-		ams := p.Spec.Alerting.Alertmanagers
+		ams := make([]monitoringv1.AlertmanagerEndpoints, 0, len(p.Spec.Alerting.Alertmanagers))
 
-		for i, am := range ams {
+		for i, am := range p.Spec.Alerting.Alertmanagers {
 			if err := validateAlertmanagerEndpoints(p, am); err != nil {
-				return fmt.Errorf("alertmanager %d: %w", i, err)
+				return nil, fmt.Errorf("alertmanager %d: %w", i, err)
 			}
+
+			if am.AlertmanagerRef != nil {
+				var err error
+				if am, err = c.resolveAlertmanagerReference(p, am); err != nil {
+					return nil, fmt.Errorf("alertmanager %d: %w", i, err)
+				}
+			}
+
+			ams = append(ams, am)
 		}
 
+		p.Spec.Alerting.Alertmanagers = ams
+
 		if err := addAlertmanagerEndpointsToStore(ctx, store, p.GetNamespace(), ams); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if err := prompkg.AddScrapeClassesToStore(ctx, store, p.GetNamespace(), p.Spec.ScrapeClasses); err != nil {
-		return fmt.Errorf("failed to process scrape classes: %w", err)
+		return nil, fmt.Errorf("failed to process scrape classes: %w", err)
+	}
+
+	if err := prompkg.AddOperatorServiceDiscoveryToStore(ctx, store, p.GetNamespace(), p.Spec.OperatorServiceDiscovery); err != nil {
+		return nil, fmt.Errorf("failed to process operator service discovery: %w", err)
 	}
 
 	sClient := c.kclient.CoreV1().Secrets(p.Namespace)
 	additionalScrapeConfigs, err := k8s.LoadSecretRef(ctx, logger, sClient, p.Spec.AdditionalScrapeConfigs)
 	if err != nil {
-		return fmt.Errorf("loading additional scrape configs from Secret failed: %w", err)
+		return nil, fmt.Errorf("loading additional scrape configs from Secret failed: %w", err)
 	}
 	additionalAlertRelabelConfigs, err := k8s.LoadSecretRef(ctx, logger, sClient, p.Spec.AdditionalAlertRelabelConfigs)
 	if err != nil {
-		return fmt.Errorf("loading additional alert relabel configs from Secret failed: %w", err)
+		return nil, fmt.Errorf("loading additional alert relabel configs from Secret failed: %w", err)
 	}
 	additionalAlertManagerConfigs, err := k8s.LoadSecretRef(ctx, logger, sClient, p.Spec.AdditionalAlertManagerConfigs)
 	if err != nil {
-		return fmt.Errorf("loading additional alert manager configs from Secret failed: %w", err)
+		return nil, fmt.Errorf("loading additional alert manager configs from Secret failed: %w", err)
 	}
 
+	promKey := fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+	if prompkg.PerShardConfiguration(p) {
+		// Each shard has its own configuration file.
+		confs, namespaces, err := cg.GenerateServerConfigurationPerShard(
+			p,
+			resources.sMons.ValidResources(),
+			resources.pMons.ValidResources(),
+			resources.bMons.ValidResources(),
+			resources.scrapeConfigs.ValidResources(),
+			store,
+			additionalScrapeConfigs,
+			additionalAlertRelabelConfigs,
+			additionalAlertManagerConfigs,
+			ruleConfigMapNames,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("generating config failed: %w", err)
+		}
+
+		s, err := prompkg.MakeConfigurationSecretPerShard(cg, c.config, confs)
+		if err != nil {
+			return nil, fmt.Errorf("creating compressed secret failed: %w", err)
+		}
+
+		// The namespaces are reported in the status of the shards.
+		c.shardNamespaces.Set(promKey, namespaces)
+		return s, nil
+	}
+
+	c.shardNamespaces.Delete(promKey)
+
 	// Update secret based on the most recent configuration.
 	conf, err := cg.GenerateServerConfiguration(
 		p,
@@ -1634,17 +2194,133 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, logger
 		ruleConfigMapNames,
 	)
 	if err != nil {
-		return fmt.Errorf("generating config failed: %w", err)
+		return nil, fmt.Errorf("generating config failed: %w", err)
 	}
 
 	// Compress config to avoid 1mb secret limit for a while
-	s, err := prompkg.MakeConfigurationSecret(p, c.config, conf)
+	s, err := prompkg.MakeShardsConfigurationSecret(cg, c.config, conf)
+	if err != nil {
+		return nil, fmt.Errorf("creating compressed secret failed: %w", err)
+	}
+
+	return s, nil
+}
+
+// updateHTTPSDTargets updates the target groups served to the Prometheus
+// shards by the HTTP service discovery endpoint.
+func (c *Operator) updateHTTPSDTargets(p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, resources *selectedConfigResources) {
+	ownerKey := httpsd.OwnerKey(monitoringv1.PrometheusesKind, p.Namespace, p.Name)
+
+	groups := cg.HTTPSDTargetGroups(resources.bMons.ValidResources(), resources.scrapeConfigs.ValidResources())
+	if c.unmanagedPrometheusConfiguration(p) || groups == nil {
+		c.httpSDRegistry.Delete(ownerKey)
+		return
+	}
+
+	c.httpSDRegistry.Set(ownerKey, prompkg.ShardsNumber(p), groups)
+}
+
+// forgetHTTPSDTargets removes the target groups of the Prometheus object
+// identified by key from the HTTP service discovery endpoint.
+func (c *Operator) forgetHTTPSDTargets(key string) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	c.httpSDRegistry.Delete(httpsd.OwnerKey(monitoringv1.PrometheusesKind, ns, name))
+}
+
+// updateSelectionReport completes the selection report with the selected
+// configuration resources and publishes it.
+func (c *Operator) updateSelectionReport(p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, resources *selectedConfigResources) error {
+	if resources.report == nil {
+		return nil
+	}
+
+	cg.AddScrapeResources(resources.report, resources.sMons, resources.pMons, resources.bMons, resources.scrapeConfigs)
+	if err := c.reportPrometheusRules(p, resources); err != nil {
+		return fmt.Errorf("failed to report the PrometheusRules: %w", err)
+	}
+
+	c.selectionReports.Set(resources.report)
+	return nil
+}
+
+// forgetSelectionReport removes the selection report of the Prometheus
+// object identified by key.
+func (c *Operator) forgetSelectionReport(key string) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	c.selectionReports.Delete(monitoringv1.PrometheusesKind, ns, name)
+}
+
+// generateScrapeConfigFiles returns the per-resource scrape configuration
+// files. It returns nil and deletes the existing secrets when the
+// configuration is rendered inline.
+func (c *Operator) generateScrapeConfigFiles(ctx context.Context, p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, store *assets.StoreBuilder, resources *selectedConfigResources) (map[string][]byte, error) {
+	if c.unmanagedPrometheusConfiguration(p) || !cg.ScrapeConfigFilesEnabled(p) {
+		for shard := range prompkg.ShardsNumber(p) {
+			if err := c.cleanupScrapeConfigFilesSecret(ctx, p, shard); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	files, err := cg.GenerateScrapeConfigFiles(
+		resources.sMons.ValidResources(),
+		resources.pMons.ValidResources(),
+		resources.bMons.ValidResources(),
+		resources.scrapeConfigs.ValidResources(),
+		store,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generating scrape configuration files failed: %w", err)
+	}
+
+	return files, nil
+}
+
+// createOrUpdateScrapeConfigFilesSecret reconciles the secrets holding the
+// scrape configuration files of the given shard. It returns nil when the
+// configuration is rendered inline.
+func (c *Operator) createOrUpdateScrapeConfigFilesSecret(ctx context.Context, p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, files map[string][]byte, shard int32) (*operator.ShardedSecret, error) {
+	if files == nil {
+		return nil, nil
+	}
+
+	sset, err := operator.ReconcileShardedSecret(ctx, cg.ExpandShardVariables(files, shard), c.kclient, prompkg.NewScrapeConfigFilesSecret(p, c.config, shard), p.Spec.Overlays)
 	if err != nil {
-		return fmt.Errorf("creating compressed secret failed: %w", err)
+		return nil, fmt.Errorf("failed to reconcile the scrape configuration secrets: %w", err)
+	}
+
+	return sset, nil
+}
+
+// makeScrapeConfigFilesSecret returns the secrets holding the scrape
+// configuration files of the given shard without writing them. It returns
+// nil when the configuration is rendered inline.
+func (c *Operator) makeScrapeConfigFilesSecret(p *monitoringv1.Prometheus, cg *prompkg.ConfigGenerator, files map[string][]byte, shard int32) *operator.ShardedSecret {
+	if files == nil {
+		return nil
+	}
+
+	return operator.NewShardedSecret(cg.ExpandShardVariables(files, shard), prompkg.NewScrapeConfigFilesSecret(p, c.config, shard), p.Spec.Overlays)
+}
+
+// cleanupScrapeConfigFilesSecret deletes the secrets holding the scrape
+// configuration files of the given shard.
+func (c *Operator) cleanupScrapeConfigFilesSecret(ctx context.Context, p *monitoringv1.Prometheus, shard int32) error {
+	if err := operator.CleanupShardedSecret(ctx, c.kclient, c.secrInfs, prompkg.NewScrapeConfigFilesSecret(p, c.config, shard)); err != nil {
+		return fmt.Errorf("failed to delete the scrape configuration secrets: %w", err)
 	}
 
-	logger.Debug("updating Prometheus configuration secret")
-	return k8s.CreateOrUpdateSecret(ctx, sClient, s)
+	return nil
 }
 
 func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, p *monitoringv1.Prometheus) error {
@@ -1670,13 +2346,41 @@ func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, p *monitor
 		operator.WithManagingOwner(p),
 	)
 
-	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, c.kclient.CoreV1().Secrets(p.Namespace), s); err != nil {
+	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, c.kclient.CoreV1().Secrets(p.Namespace), s, p.Spec.Overlays); err != nil {
 		return fmt.Errorf("failed to reconcile web config secret: %w", err)
 	}
 
 	return nil
 }
 
+// createOrUpdateGoverningService reconciles the default governing service.
+// The service is shared by all the Prometheus resources of the namespace
+// hence the overlays don't apply to it.
+func (c *Operator) createOrUpdateGoverningService(ctx context.Context, p *monitoringv1.Prometheus) error {
+	svc := prompkg.BuildStatefulSetService(
+		governingServiceName,
+		map[string]string{
+			operator.ApplicationNameLabelKey: applicationNameLabelValue,
+		},
+		p,
+		c.config,
+	)
+
+	if p.Spec.Thanos != nil {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:       "grpc",
+			Port:       10901,
+			TargetPort: intstr.FromString("grpc"),
+		})
+	}
+
+	if _, err := k8s.CreateOrUpdateService(ctx, c.kclient.CoreV1().Services(p.Namespace), svc); err != nil {
+		return fmt.Errorf("synchronizing default governing service failed: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Operator) createOrUpdateThanosConfigSecret(ctx context.Context, p *monitoringv1.Prometheus) error {
 	secret, err := buildPrometheusHTTPClientConfigSecret(p)
 	if err != nil {
@@ -1690,9 +2394,96 @@ func (c *Operator) createOrUpdateThanosConfigSecret(ctx context.Context, p *moni
 		operator.WithManagingOwner(p),
 	)
 
+	if err := k8s.ApplyOverlays(p.Spec.Overlays, secret); err != nil {
+		return err
+	}
+
 	return k8s.CreateOrUpdateSecret(ctx, c.kclient.CoreV1().Secrets(secret.Namespace), secret)
 }
 
+// reconcileNetworkPolicy creates or updates the NetworkPolicy of the
+// Prometheus pods when it is defined and deletes it otherwise.
+func (c *Operator) reconcileNetworkPolicy(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, np *operator.NetworkPolicyBuilder, resources *selectedConfigResources) error {
+	name := prompkg.NetworkPolicyName(p)
+	if p.Spec.NetworkPolicy == nil {
+		return operator.ReconcileNetworkPolicy(ctx, c.kclient, p.Namespace, name, nil)
+	}
+
+	prompkg.AddNetworkPolicyEgress(
+		np,
+		p,
+		resources.sMons.ValidResources(),
+		resources.pMons.ValidResources(),
+		resources.bMons.ValidResources(),
+		resources.scrapeConfigs.ValidResources(),
+	)
+
+	for _, rr := range p.Spec.RemoteRead {
+		if rr.PrometheusRef != nil {
+			np.AddNamespaces(ptr.Deref(rr.PrometheusRef.Namespace, p.Namespace))
+			continue
+		}
+
+		np.AddAddresses(string(rr.URL))
+	}
+
+	if p.Spec.Alerting != nil {
+		for _, am := range p.Spec.Alerting.Alertmanagers {
+			switch {
+			case am.AlertmanagerRef != nil:
+				np.AddNamespaces(ptr.Deref(am.AlertmanagerRef.Namespace, p.Namespace))
+			case am.Name != "":
+				np.AddNamespaces(ptr.Deref(am.Namespace, p.Namespace))
+			}
+
+			for _, sc := range am.StaticConfigs {
+				np.AddAddresses(sc.Targets...)
+			}
+
+			for _, sd := range am.DNSSDConfigs {
+				np.AddAddresses(sd.Names...)
+			}
+		}
+	}
+
+	return operator.ReconcileNetworkPolicy(
+		ctx,
+		c.kclient,
+		p.Namespace,
+		name,
+		np.Build(
+			ctx,
+			logger,
+			c.kclient,
+			*p.Spec.NetworkPolicy,
+			operator.WithName(name),
+			operator.WithNamespace(p.Namespace),
+			operator.WithLabels(c.config.Labels),
+			operator.WithAnnotations(c.config.Annotations),
+			operator.WithManagingOwner(p),
+		),
+	)
+}
+
+// newExposure returns the exposure of the Prometheus web server or nil if
+// it isn't defined.
+func (c *Operator) newExposure(p *monitoringv1.Prometheus) *operator.Exposure {
+	if p.Spec.Exposure == nil {
+		return nil
+	}
+
+	return operator.NewExposure(*p.Spec.Exposure, operator.ExposureConfig{
+		Name:        prompkg.PrefixedName(p),
+		PodSelector: makeSelectorLabels(p.Name),
+		PortName:    cmp.Or(p.Spec.PortName, prompkg.DefaultPortName),
+		Port:        9090,
+		RoutePrefix: p.Spec.WebRoutePrefix(),
+		WebTLS:      p.Spec.PrometheusURIScheme() == "https",
+		Sharded:     true,
+		Overlays:    p.Spec.Overlays,
+	})
+}
+
 func makeSelectorLabels(name string) map[string]string {
 	return map[string]string{
 		operator.ManagedByLabelKey:           operator.ManagedByLabelValue,
@@ -1704,6 +2495,27 @@ func makeSelectorLabels(name string) map[string]string {
 }
 
 func validateAlertmanagerEndpoints(p *monitoringv1.Prometheus, am monitoringv1.AlertmanagerEndpoints) error {
+	var sources int
+	for _, defined := range []bool{
+		am.Name != "",
+		len(am.StaticConfigs) > 0 || len(am.DNSSDConfigs) > 0,
+		am.AlertmanagerRef != nil,
+	} {
+		if defined {
+			sources++
+		}
+	}
+
+	if sources != 1 {
+		return errors.New("exactly one of \"name\", \"staticConfigs\"/\"dnsSDConfigs\" or \"alertmanagerRef\" must be defined")
+	}
+
+	for i, dc := range am.DNSSDConfigs {
+		if dc.Type != nil && *dc.Type != monitoringv1.AlertmanagerDNSRecordTypeSRV && dc.Port == nil {
+			return fmt.Errorf("dnsSDConfigs[%d]: port is required for %s records", i, *dc.Type)
+		}
+	}
+
 	var nonNilFields []string
 
 	//nolint:staticcheck // Ignore SA1019 this field is marked as deprecated.
@@ -1742,6 +2554,23 @@ func validateAlertmanagerEndpoints(p *monitoringv1.Prometheus, am monitoringv1.A
 	return nil
 }
 
+// resolveAlertmanagerReference returns the endpoints derived from the
+// Alertmanager object referenced by am.
+func (c *Operator) resolveAlertmanagerReference(p *monitoringv1.Prometheus, am monitoringv1.AlertmanagerEndpoints) (monitoringv1.AlertmanagerEndpoints, error) {
+	ns := ptr.Deref(am.AlertmanagerRef.Namespace, p.Namespace)
+
+	alertmanager, err := operator.GetObjectFromKey[*monitoringv1.Alertmanager](c.amInfs, ns+"/"+am.AlertmanagerRef.Name)
+	if err != nil {
+		return am, err
+	}
+
+	if alertmanager == nil {
+		return am, fmt.Errorf("alertmanager %s/%s not found", ns, am.AlertmanagerRef.Name)
+	}
+
+	return alertmanagerpkg.EndpointsForReference(am, alertmanager, p.Namespace)
+}
+
 func addAlertmanagerEndpointsToStore(ctx context.Context, store *assets.StoreBuilder, namespace string, ams []monitoringv1.AlertmanagerEndpoints) error {
 	for i, am := range ams {
 		if err := store.AddBasicAuth(ctx, namespace, am.BasicAuth); err != nil {
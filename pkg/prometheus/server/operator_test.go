@@ -27,10 +27,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
 )
@@ -200,16 +203,41 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 				},
 			},
 
+			equal: false,
+		},
+		{
+			name: "different overlays",
+			a: monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: "v1.7.2",
+						Overlays: []monitoringv1.Overlay{
+							{
+								Target: monitoringv1.OverlayTarget{Kind: monitoringv1.StatefulSetOverlayTargetKind},
+								Patch:  `[{"op": "add", "path": "/metadata/labels/foo", "value": "bar"}]`,
+							},
+						},
+					},
+				},
+			},
+			b: monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+						Version: "v1.7.2",
+					},
+				},
+			},
+
 			equal: false,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			c := prompkg.Config{}
 
-			p1Hash, err := createSSetInputHash(tc.a, c, []string{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{})
+			p1Hash, err := createSSetInputHash(tc.a, "", c, []string{}, &operator.ShardedSecret{}, nil, appsv1.StatefulSetSpec{})
 			require.NoError(t, err)
 
-			p2Hash, err := createSSetInputHash(tc.b, c, []string{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{})
+			p2Hash, err := createSSetInputHash(tc.b, "", c, []string{}, &operator.ShardedSecret{}, nil, appsv1.StatefulSetSpec{})
 			require.NoError(t, err)
 
 			if !tc.equal {
@@ -219,7 +247,7 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 
 			require.Equal(t, p1Hash, p2Hash, "expected two Prometheus CRDs to produce the same hash but got different hash")
 
-			p2Hash, err = createSSetInputHash(tc.a, c, []string{}, &operator.ShardedSecret{}, appsv1.StatefulSetSpec{Replicas: new(int32(2))})
+			p2Hash, err = createSSetInputHash(tc.a, "", c, []string{}, &operator.ShardedSecret{}, nil, appsv1.StatefulSetSpec{Replicas: new(int32(2))})
 			require.NoError(t, err)
 
 			require.NotEqual(t, p1Hash, p2Hash, "expected same Prometheus CRDs with different statefulset specs to produce different hashes but got equal hash")
@@ -227,6 +255,52 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 	}
 }
 
+func TestStatefulSetInputHashWithWorkloadDefaults(t *testing.T) {
+	newDefaults := func(priorityClassName string) *monitoringv1alpha1.PrometheusOperatorDefaults {
+		return &monitoringv1alpha1.PrometheusOperatorDefaults{
+			Spec: monitoringv1alpha1.PrometheusOperatorDefaultsSpec{
+				Prometheus: &monitoringv1alpha1.PrometheusDefaults{
+					WorkloadDefaults: monitoringv1alpha1.WorkloadDefaults{
+						PriorityClassName: ptr.To(priorityClassName),
+					},
+				},
+			},
+		}
+	}
+
+	hash := func(p *monitoringv1.Prometheus, d *monitoringv1alpha1.PrometheusOperatorDefaults) string {
+		t.Helper()
+
+		p = p.DeepCopy()
+		defaulted := operator.ApplyPrometheusDefaults(p, d)
+		defaultedValues, err := operator.DefaultedPrometheusValues(&p.Spec.CommonPrometheusFields, defaulted)
+		require.NoError(t, err)
+
+		// The generation and the StatefulSet spec are identical: only the
+		// defaulted values can change the hash.
+		h, err := createSSetInputHash(*p, defaultedValues, prompkg.Config{}, []string{}, &operator.ShardedSecret{}, nil, appsv1.StatefulSetSpec{})
+		require.NoError(t, err)
+
+		return h
+	}
+
+	p := &monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Version: "v1.7.2",
+			},
+		},
+	}
+
+	// The effective values are part of the hash.
+	require.NotEqual(t, hash(p, nil), hash(p, newDefaults("monitoring")))
+	require.NotEqual(t, hash(p, newDefaults("monitoring")), hash(p, newDefaults("critical")))
+
+	// The default values don't override the explicit fields.
+	p.Spec.PriorityClassName = "monitoring"
+	require.Equal(t, hash(p, nil), hash(p, newDefaults("critical")))
+}
+
 func TestCreateThanosConfigSecret(t *testing.T) {
 	version := "v0.24.0"
 	ctx := context.Background()
@@ -508,3 +582,133 @@ func TestGracePeriodForPrometheusStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAlertmanagerEndpoints(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		am   monitoringv1.AlertmanagerEndpoints
+		err  bool
+	}{
+		{
+			name: "service",
+			am: monitoringv1.AlertmanagerEndpoints{
+				Name: "alertmanager",
+				Port: intstr.FromString("web"),
+			},
+		},
+		{
+			name: "static and dns",
+			am: monitoringv1.AlertmanagerEndpoints{
+				StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{{Targets: []string{"alertmanager:9093"}}},
+				DNSSDConfigs:  []monitoringv1.AlertmanagerDNSSDConfig{{Names: []string{"_web._tcp.alertmanager"}}},
+			},
+		},
+		{
+			name: "dns with A records",
+			am: monitoringv1.AlertmanagerEndpoints{
+				DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{{
+					Names: []string{"alertmanager"},
+					Type:  ptr.To(monitoringv1.AlertmanagerDNSRecordTypeA),
+					Port:  ptr.To(int32(9093)),
+				}},
+			},
+		},
+		{
+			name: "alertmanager reference",
+			am: monitoringv1.AlertmanagerEndpoints{
+				AlertmanagerRef: &monitoringv1.AlertmanagerReference{Name: "main"},
+			},
+		},
+		{
+			name: "service and alertmanager reference",
+			am: monitoringv1.AlertmanagerEndpoints{
+				Name:            "alertmanager",
+				Port:            intstr.FromString("web"),
+				AlertmanagerRef: &monitoringv1.AlertmanagerReference{Name: "main"},
+			},
+			err: true,
+		},
+		{
+			name: "no discovery",
+			am:   monitoringv1.AlertmanagerEndpoints{},
+			err:  true,
+		},
+		{
+			name: "service and static",
+			am: monitoringv1.AlertmanagerEndpoints{
+				Name:          "alertmanager",
+				Port:          intstr.FromString("web"),
+				StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{{Targets: []string{"alertmanager:9093"}}},
+			},
+			err: true,
+		},
+		{
+			name: "dns with A records without port",
+			am: monitoringv1.AlertmanagerEndpoints{
+				DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{{
+					Names: []string{"alertmanager"},
+					Type:  ptr.To(monitoringv1.AlertmanagerDNSRecordTypeA),
+				}},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAlertmanagerEndpoints(&monitoringv1.Prometheus{}, tc.am)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOverlays(t *testing.T) {
+	// Two Prometheus resources in the same namespace share the governing
+	// service, the overlays of one resource mustn't be applied to it.
+	prometheuses := []*monitoringv1.Prometheus{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "test"},
+			Spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Overlays: []monitoringv1.Overlay{
+						{
+							Target: monitoringv1.OverlayTarget{Kind: monitoringv1.ServiceOverlayTargetKind},
+							Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+							Patch:  `{"metadata": {"annotations": {"foo": "bar"}}}`,
+						},
+						{
+							Target: monitoringv1.OverlayTarget{Kind: monitoringv1.SecretOverlayTargetKind},
+							Type:   ptr.To(monitoringv1.StrategicMergeOverlayPatchType),
+							Patch:  `{"metadata": {"annotations": {"foo": "bar"}}}`,
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "test"},
+		},
+	}
+
+	o := &Operator{kclient: fake.NewClientset()}
+	for _, p := range prometheuses {
+		require.NoError(t, o.createOrUpdateGoverningService(context.Background(), p))
+
+		svc, err := o.kclient.CoreV1().Services("test").Get(context.Background(), governingServiceName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, svc.Annotations, "foo")
+
+		require.NoError(t, o.createOrUpdateThanosConfigSecret(context.Background(), p))
+
+		s, err := o.kclient.CoreV1().Secrets("test").Get(context.Background(), thanosPrometheusHTTPClientConfigSecretName(p), metav1.GetOptions{})
+		require.NoError(t, err)
+		if len(p.Spec.Overlays) > 0 {
+			require.Equal(t, "bar", s.Annotations["foo"])
+		} else {
+			require.NotContains(t, s.Annotations, "foo")
+		}
+	}
+}
@@ -18,19 +18,23 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 
-	"github.com/prometheus/prometheus/promql/parser"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespacelabeler"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	"github.com/prometheus-operator/prometheus-operator/pkg/selectionreport"
 )
 
-func (c *Operator) selectPrometheusRules(p *monitoringv1.Prometheus, logger *slog.Logger) (operator.PrometheusRuleSelection, error) {
+func (c *Operator) selectPrometheusRules(p *monitoringv1.Prometheus, quotas operator.MonitoringQuotas, logger *slog.Logger) (operator.PrometheusRuleSelection, error) {
 	namespaces, err := operator.SelectNamespacesFromCache(p, p.Spec.RuleNamespaceSelector, c.nsMonInf)
 	var rules operator.PrometheusRuleSelection
 	if err != nil {
@@ -53,22 +57,9 @@ func (c *Operator) selectPrometheusRules(p *monitoringv1.Prometheus, logger *slo
 	var (
 		nsLabeler     = namespacelabeler.New(p.Spec.EnforcedNamespaceLabel, excludedFromEnforcement, true)
 		promVersion   = operator.StringValOrDefault(p.GetCommonPrometheusFields().Version, operator.DefaultPrometheusVersion)
-		parserOptions parser.Options
+		parserOptions = prompkg.ParserOptions(p)
 	)
 
-	for _, f := range p.GetCommonPrometheusFields().EnableFeatures {
-		switch f {
-		case "promql-experimental-functions":
-			parserOptions.EnableExperimentalFunctions = true
-		case "promql-duration-expr":
-			parserOptions.ExperimentalDurationExpr = true
-		case "promql-extended-range-selectors":
-			parserOptions.EnableExtendedRangeSelectors = true
-		case "promql-binop-fill-modifiers":
-			parserOptions.EnableBinopFillModifiers = true
-		}
-	}
-
 	// Select and filter PrometheusRule resources.
 	promRuleSelector, err := operator.NewPrometheusRuleSelector(
 		operator.PrometheusFormat,
@@ -79,6 +70,7 @@ func (c *Operator) selectPrometheusRules(p *monitoringv1.Prometheus, logger *slo
 		c.newEventRecorder(p),
 		logger,
 		parserOptions,
+		operator.WithMonitoringQuotas(quotas),
 	)
 	if err != nil {
 		return rules, fmt.Errorf("initializing PrometheusRules failed: %w", err)
@@ -96,25 +88,147 @@ func (c *Operator) selectPrometheusRules(p *monitoringv1.Prometheus, logger *slo
 	return rules, nil
 }
 
-func (c *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, p *monitoringv1.Prometheus, rules operator.PrometheusRuleSelection, logger *slog.Logger) ([]string, error) {
+// reportPrometheusRules adds the candidate PrometheusRule objects to the
+// selection report.
+func (c *Operator) reportPrometheusRules(p *monitoringv1.Prometheus, resources *selectedConfigResources) error {
+	namespaces, err := operator.SelectNamespacesFromCache(p, p.Spec.RuleNamespaceSelector, c.nsMonInf)
+	if err != nil {
+		return err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleSelector)
+	if err != nil {
+		return err
+	}
+
+	if err := prompkg.AddSelectionCandidates(
+		resources.report,
+		monitoringv1.PrometheusRuleKind,
+		namespaces,
+		selector,
+		c.ruleInfs.ListAllByNamespace,
+		resources.rules.Selected(),
+	); err != nil {
+		return err
+	}
+
+	prompkg.AddSelectedResources(
+		resources.report,
+		monitoringv1.PrometheusRuleKind,
+		resources.rules.Selected(),
+		func(rule *monitoringv1.PrometheusRule, res *selectionreport.Resource) {
+			if resources.ruleShards == nil {
+				res.Shards = prompkg.ShardsForResource(p, nil, rule)
+				return
+			}
+
+			res.Shards = resources.ruleShards[fmt.Sprintf("%s/%s", rule.Namespace, rule.Name)]
+		},
+	)
+
+	return nil
+}
+
+// createOrUpdateRuleConfigMaps synchronizes the rule ConfigMaps of the
+// Prometheus object. It returns the names of the rule volumes and, when the
+// shards don't evaluate the same rules, the shards evaluating each
+// PrometheusRule object.
+func (c *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, p *monitoringv1.Prometheus, rules operator.PrometheusRuleSelection, logger *slog.Logger) ([]string, map[string][]int32, error) {
+	var (
+		cmClient = c.kclient.CoreV1().ConfigMaps(p.Namespace)
+		cmOpts   = []operator.ObjectOption{
+			operator.WithAnnotations(c.config.Annotations),
+			operator.WithLabels(c.config.Labels),
+			operator.WithManagingOwner(p),
+		}
+	)
 
 	// Update the corresponding ConfigMap resources.
 	prs := operator.NewPrometheusRuleSyncer(
 		logger,
-		fmt.Sprintf("prometheus-%s", p.Name),
-		c.kclient.CoreV1().ConfigMaps(p.Namespace),
+		rulesConfigMapPrefix(p),
+		cmClient,
 		labels.Set{prompkg.LabelPrometheusName: p.Name},
-		[]operator.ObjectOption{
-			operator.WithAnnotations(c.config.Annotations),
-			operator.WithLabels(c.config.Labels),
-			operator.WithManagingOwner(p),
-		},
+		cmOpts,
 	)
 
-	configMapNames, err := prs.Sync(ctx, rules.RuleFiles())
+	a := prompkg.NewRuleGroupAssigner(p, logger)
+	if a == nil {
+		configMapNames, err := prs.Sync(ctx, rules.RuleFiles())
+		if err != nil {
+			return nil, nil, fmt.Errorf("synchronizing PrometheusRules failed: %w", err)
+		}
+
+		return prs.AppendConfigMapNames(configMapNames, 3), nil, nil
+	}
+
+	ruleFiles, ruleShards, err := rules.RuleFilesForShards(a.Shards(), a.Assign)
+	if err != nil {
+		return nil, nil, fmt.Errorf("distributing PrometheusRules across shards failed: %w", err)
+	}
+
+	var (
+		desired = sets.New[string]()
+		n       int
+	)
+	for shard := range a.Shards() {
+		shardPrs := operator.NewPrometheusRuleSyncer(
+			logger.With("shard", shard),
+			shardRulesConfigMapPrefix(p, shard),
+			cmClient,
+			labels.Set{
+				prompkg.LabelPrometheusName: p.Name,
+				prompkg.ShardLabelName:      strconv.Itoa(int(shard)),
+			},
+			cmOpts,
+		)
+
+		configMapNames, err := shardPrs.Sync(ctx, ruleFiles[shard])
+		if err != nil {
+			return nil, nil, fmt.Errorf("synchronizing PrometheusRules for shard %d failed: %w", shard, err)
+		}
+
+		desired.Insert(configMapNames...)
+		n = max(n, len(configMapNames))
+	}
+
+	// Delete the ConfigMaps which aren't used anymore (e.g. the rule evaluation
+	// mode changed or the number of shards decreased).
+	cmList, err := cmClient.List(ctx, metav1.ListOptions{LabelSelector: labels.Set{prompkg.LabelPrometheusName: p.Name}.String()})
 	if err != nil {
-		return nil, fmt.Errorf("synchronizing PrometheusRules failed: %w", err)
+		return nil, nil, err
+	}
+
+	for _, cm := range cmList.Items {
+		if desired.Has(cm.Name) || !strings.HasPrefix(cm.Name, rulesConfigMapPrefix(p)+"-") || !strings.Contains(cm.Name, "-rulefiles-") {
+			continue
+		}
+
+		logger.Debug("deleting unused ConfigMap for PrometheusRule", "configmap", cm.Name)
+		if err := cmClient.Delete(ctx, cm.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to delete unused ConfigMap %q: %w", cm.Name, err)
+		}
+	}
+
+	// The rule volumes have the same names for all shards which means that
+	// the Prometheus configuration is identical for all shards.
+	return prs.AppendConfigMapNames(nil, max(n, 3)), ruleShards, nil
+}
+
+func rulesConfigMapPrefix(p *monitoringv1.Prometheus) string {
+	return fmt.Sprintf("prometheus-%s", p.Name)
+}
+
+func shardRulesConfigMapPrefix(p *monitoringv1.Prometheus, shard int32) string {
+	return fmt.Sprintf("prometheus-%s-shard-%d", p.Name, shard)
+}
+
+// ruleConfigMapForShard returns the name of the ConfigMap backing the rule
+// volume for the given shard.
+func ruleConfigMapForShard(p *monitoringv1.Prometheus, volumeName string, shard int32) string {
+	if !prompkg.PerShardRuleEvaluation(p) {
+		return volumeName
 	}
 
-	return prs.AppendConfigMapNames(configMapNames, 3), nil
+	return shardRulesConfigMapPrefix(p, shard) + strings.TrimPrefix(volumeName, rulesConfigMapPrefix(p))
 }
@@ -19,6 +19,7 @@ import (
 	"maps"
 	"path"
 	"path/filepath"
+	"slices"
 
 	"github.com/blang/semver/v4"
 	appsv1 "k8s.io/api/apps/v1"
@@ -34,7 +35,7 @@ import (
 
 const (
 	prometheusMode                       = "server"
-	governingServiceName                 = "prometheus-operated"
+	governingServiceName                 = prompkg.ServerGoverningServiceName
 	thanosSupportedVersionHTTPClientFlag = "0.24.0"
 
 	// Minimum Prometheus and Thanos versions supporting coordinated (delayed)
@@ -49,6 +50,8 @@ const (
 	// --storage.tsdb.delay-compact-file.path to only compact blocks that have
 	// already been uploaded.
 	thanosShipperMetaFileName = "thanos.shipper.json"
+
+	scrapeConfigFilesVolumeName = "scrape-config-files"
 )
 
 func makeStatefulSet(
@@ -60,6 +63,7 @@ func makeStatefulSet(
 	inputHash string,
 	shard int32,
 	tlsSecrets *operator.ShardedSecret,
+	scrapeConfigFiles *operator.ShardedSecret,
 ) (*appsv1.StatefulSet, error) {
 	cpf := p.GetCommonPrometheusFields()
 	objMeta := p.GetObjectMeta()
@@ -73,7 +77,7 @@ func makeStatefulSet(
 	// We need to re-set the common fields because cpf is only a copy of the original object.
 	// We set some defaults if some fields are not present, and we want those fields set in the original Prometheus object before building the StatefulSetSpec.
 	p.SetCommonPrometheusFields(cpf)
-	spec, err := makeStatefulSetSpec(p, config, cg, shard, ruleConfigMapNames, tlsSecrets)
+	spec, err := makeStatefulSetSpec(p, config, cg, shard, ruleConfigMapNames, tlsSecrets, scrapeConfigFiles)
 	if err != nil {
 		return nil, fmt.Errorf("make StatefulSet spec: %w", err)
 	}
@@ -157,6 +161,7 @@ func makeStatefulSetSpec(
 	shard int32,
 	ruleConfigMapNames []string,
 	tlsSecrets *operator.ShardedSecret,
+	scrapeConfigFiles *operator.ShardedSecret,
 ) (*appsv1.StatefulSetSpec, error) {
 	cpf := p.GetCommonPrometheusFields()
 
@@ -176,15 +181,46 @@ func makeStatefulSetSpec(
 
 	promArgs := buildServerArgs(cg, p)
 
+	if p.Spec.Exposure != nil {
+		// The external URL derived from the exposure takes precedence over
+		// spec.externalUrl.
+		externalURL := monitoringv1.Argument{
+			Name:  "web.external-url",
+			Value: operator.ExposureExternalURL(*p.Spec.Exposure, prompkg.ExpectedStatefulSetShardNames(p)[shard], true, cpf.WebRoutePrefix()),
+		}
+
+		i := slices.IndexFunc(promArgs, func(arg monitoringv1.Argument) bool { return arg.Name == externalURL.Name })
+		if i >= 0 {
+			promArgs[i] = externalURL
+		} else {
+			promArgs = append(promArgs, externalURL)
+		}
+	}
+
 	volumes, promVolumeMounts, err := prompkg.BuildCommonVolumes(p, tlsSecrets, true)
 	if err != nil {
 		return nil, err
 	}
 
-	volumes, promVolumeMounts = appendServerVolumes(p, volumes, promVolumeMounts, ruleConfigMapNames)
+	volumes, promVolumeMounts = appendServerVolumes(p, volumes, promVolumeMounts, ruleConfigMapNames, shard)
 
 	configReloaderVolumeMounts := prompkg.CreateConfigReloaderVolumeMounts()
 
+	var watchedDirectories []string
+
+	if scrapeConfigFiles != nil {
+		volumes = append(volumes, scrapeConfigFiles.Volume(scrapeConfigFilesVolumeName))
+
+		scrapeConfigFilesMount := corev1.VolumeMount{
+			Name:      scrapeConfigFilesVolumeName,
+			MountPath: prompkg.ScrapeConfigFilesDir,
+			ReadOnly:  true,
+		}
+		promVolumeMounts = append(promVolumeMounts, scrapeConfigFilesMount)
+		configReloaderVolumeMounts = append(configReloaderVolumeMounts, scrapeConfigFilesMount)
+		watchedDirectories = append(watchedDirectories, prompkg.ScrapeConfigFilesDir)
+	}
+
 	var configReloaderWebConfigFile string
 
 	// Mount web config and web TLS credentials as volumes.
@@ -281,8 +317,6 @@ func makeStatefulSetSpec(
 		promArgs = append(promArgs, monitoringv1.Argument{Name: "no-storage.tsdb.allow-overlapping-compaction"})
 	}
 
-	var watchedDirectories []string
-
 	if len(ruleConfigMapNames) != 0 {
 		for _, name := range ruleConfigMapNames {
 			mountPath := prompkg.RulesDir + "/" + name
@@ -302,6 +336,9 @@ func makeStatefulSetSpec(
 	if topologyZone != "" {
 		reloaderOpts = append(reloaderOpts, operator.InzoneShard(new(cg.InzoneShardForShard(shard))))
 	}
+	if cg.PerShardConfigurationFiles() {
+		reloaderOpts = append(reloaderOpts, operator.ConfigFile(path.Join(prompkg.ConfDir, cg.ConfigFilenameForShard(shard))))
+	}
 	operatorInitContainers = append(operatorInitContainers,
 		prompkg.BuildConfigReloader(
 			p,
@@ -329,6 +366,16 @@ func makeStatefulSetSpec(
 		envVars = append(envVars, corev1.EnvVar{Name: "GOGC", Value: fmt.Sprintf("%d", *p.Spec.Runtime.GoGC)})
 	}
 
+	// The external URL of the PerReplica exposure references the pod name.
+	if operator.ExposurePerReplica(p.Spec.Exposure) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: operator.PodNameEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+			},
+		})
+	}
+
 	operatorContainers := append([]corev1.Container{
 		{
 			Name:                     "prometheus",
@@ -424,6 +471,7 @@ func buildServerArgs(cg *prompkg.ConfigGenerator, p *monitoringv1.Prometheus) []
 		promArgs               = cg.BuildCommonPrometheusArgs()
 		retentionTimeFlagName  = "storage.tsdb.retention.time"
 		retentionTimeFlagValue = string(p.Spec.Retention)
+		retentionSize          = prompkg.RetentionSize(p.Spec)
 	)
 
 	if cg.WithMaximumVersion("2.7.0").IsCompatible() {
@@ -432,7 +480,7 @@ func buildServerArgs(cg *prompkg.ConfigGenerator, p *monitoringv1.Prometheus) []
 			retentionTimeFlagValue = prompkg.DefaultRetention
 		}
 	} else {
-		retentionTimeFlagValue = string(prompkg.RetentionTimeOrDefault(p.Spec.Retention, p.Spec.RetentionSize))
+		retentionTimeFlagValue = string(prompkg.RetentionTimeOrDefault(p.Spec.Retention, retentionSize))
 	}
 
 	// Starting with Prometheus v3.11.0, retention settings are populated in the configuration file.
@@ -440,8 +488,8 @@ func buildServerArgs(cg *prompkg.ConfigGenerator, p *monitoringv1.Prometheus) []
 		promArgs = append(promArgs, monitoringv1.Argument{Name: retentionTimeFlagName, Value: retentionTimeFlagValue})
 	}
 
-	if p.Spec.RetentionSize != "" && cg.Version().LT(semver.MustParse("3.11.0")) {
-		retentionSizeFlag := monitoringv1.Argument{Name: "storage.tsdb.retention.size", Value: string(p.Spec.RetentionSize)}
+	if retentionSize != "" && cg.Version().LT(semver.MustParse("3.11.0")) {
+		retentionSizeFlag := monitoringv1.Argument{Name: "storage.tsdb.retention.size", Value: string(retentionSize)}
 		promArgs = cg.WithMinimumVersion("2.7.0").AppendCommandlineArgument(promArgs, retentionSizeFlag)
 	}
 
@@ -500,7 +548,7 @@ func buildServerArgs(cg *prompkg.ConfigGenerator, p *monitoringv1.Prometheus) []
 }
 
 // appendServerVolumes returns a set of volumes to be mounted on the statefulset spec that are specific to Prometheus Server.
-func appendServerVolumes(p *monitoringv1.Prometheus, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount, ruleConfigMapNames []string) ([]corev1.Volume, []corev1.VolumeMount) {
+func appendServerVolumes(p *monitoringv1.Prometheus, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount, ruleConfigMapNames []string, shard int32) ([]corev1.Volume, []corev1.VolumeMount) {
 	// not mount 2 emptyDir volumes at the same mountpath
 	if volume, ok := queryLogFileVolume(p.Spec.QueryLogFile); ok && p.Spec.ScrapeFailureLogFile == nil {
 		volumes = append(volumes, volume)
@@ -512,7 +560,7 @@ func appendServerVolumes(p *monitoringv1.Prometheus, volumes []corev1.Volume, vo
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: name,
+						Name: ruleConfigMapForShard(p, name, shard),
 					},
 					Optional: new(true),
 				},
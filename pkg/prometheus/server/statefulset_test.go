@@ -63,7 +63,8 @@ func makeStatefulSetFromPrometheus(p monitoringv1.Prometheus) (*appsv1.StatefulS
 		nil,
 		"abc",
 		0,
-		&operator.ShardedSecret{})
+		&operator.ShardedSecret{},
+		nil)
 }
 
 func TestStatefulSetLabelingAndAnnotations(t *testing.T) {
@@ -419,6 +420,7 @@ func TestStatefulSetVolumeInitial(t *testing.T) {
 				Namespace: "test",
 			},
 		},
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -430,13 +432,85 @@ func TestStatefulSetVolumeInitial(t *testing.T) {
 		[]string{"rules-configmap-one"},
 		"",
 		0,
-		shardedSecret)
+		shardedSecret,
+		nil)
 	require.NoError(t, err)
 
 	require.Equalf(t, expected.Spec.Template.Spec.Volumes, sset.Spec.Template.Spec.Volumes, "expected volumes to match \n%s", pretty.Compare(expected.Spec.Template.Spec.Volumes, sset.Spec.Template.Spec.Volumes))
 	require.Equalf(t, expected.Spec.Template.Spec.Containers[0].VolumeMounts, sset.Spec.Template.Spec.Containers[0].VolumeMounts, "expected volume mounts to match \n%s", pretty.Compare(expected.Spec.Template.Spec.Containers[0].VolumeMounts, sset.Spec.Template.Spec.Containers[0].VolumeMounts))
 }
 
+func TestStatefulSetShardConfigurationRollout(t *testing.T) {
+	p := monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Shards:               ptr.To(int32(2)),
+				ShardRolloutStrategy: &monitoringv1.ShardRolloutStrategy{},
+			},
+		},
+	}
+
+	cg, err := prompkg.NewConfigGenerator(prompkg.NewLogger(), &p, prompkg.WithShardConfigurationRollout())
+	require.NoError(t, err)
+
+	sset, err := makeStatefulSet("test", &p, defaultTestConfig, cg, nil, "", 1, &operator.ShardedSecret{}, nil)
+	require.NoError(t, err)
+
+	for _, c := range sset.Spec.Template.Spec.Containers {
+		if c.Name == "config-reloader" {
+			require.Contains(t, c.Args, "--config-file=/etc/prometheus/config/prometheus-shard-1.yaml.gz")
+		}
+	}
+}
+
+func TestStatefulSetScrapeConfigFiles(t *testing.T) {
+	p := monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			ScrapeConfigRendering: ptr.To(monitoringv1.PerResourceScrapeConfigRendering),
+		},
+	}
+
+	cg, err := prompkg.NewConfigGenerator(prompkg.NewLogger(), &p)
+	require.NoError(t, err)
+
+	scrapeConfigFiles, err := operator.ReconcileShardedSecret(
+		context.Background(),
+		map[string][]byte{"servicemonitor_test_foo.yaml": []byte("scrape_configs: []")},
+		fake.NewClientset(),
+		prompkg.NewScrapeConfigFilesSecret(&p, defaultTestConfig, 0),
+		nil,
+	)
+	require.NoError(t, err)
+
+	sset, err := makeStatefulSet("test", &p, defaultTestConfig, cg, nil, "", 0, &operator.ShardedSecret{}, scrapeConfigFiles)
+	require.NoError(t, err)
+
+	expectedMount := corev1.VolumeMount{
+		Name:      "scrape-config-files",
+		MountPath: "/etc/prometheus/scrape_configs",
+		ReadOnly:  true,
+	}
+
+	require.Contains(t, sset.Spec.Template.Spec.Volumes, scrapeConfigFiles.Volume("scrape-config-files"))
+	for _, c := range sset.Spec.Template.Spec.Containers {
+		switch c.Name {
+		case "prometheus":
+			require.Contains(t, c.VolumeMounts, expectedMount)
+		case "config-reloader":
+			require.Contains(t, c.VolumeMounts, expectedMount)
+			require.Contains(t, c.Args, "--watched-dir=/etc/prometheus/scrape_configs")
+		}
+	}
+}
+
 func TestAdditionalConfigMap(t *testing.T) {
 	sset, err := makeStatefulSetFromPrometheus(monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{
@@ -826,7 +900,8 @@ func TestPrometheusDefaultBaseImageFlag(t *testing.T) {
 		nil,
 		"",
 		0,
-		&operator.ShardedSecret{})
+		&operator.ShardedSecret{},
+		nil)
 	require.NoError(t, err)
 
 	image := sset.Spec.Template.Spec.Containers[0].Image
@@ -868,7 +943,8 @@ func TestThanosDefaultBaseImageFlag(t *testing.T) {
 		nil,
 		"",
 		0,
-		&operator.ShardedSecret{})
+		&operator.ShardedSecret{},
+		nil)
 	require.NoError(t, err)
 
 	image := sset.Spec.Template.Spec.Containers[2].Image
@@ -1450,6 +1526,35 @@ func TestRetentionAndRetentionSize(t *testing.T) {
 	}
 }
 
+func TestRetentionSizePercentage(t *testing.T) {
+	sset, err := makeStatefulSetFromPrometheus(monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Version: "v3.10.0",
+				Storage: &monitoringv1.StorageSpec{
+					VolumeClaimTemplate: monitoringv1.EmbeddedPersistentVolumeClaim{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("10Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			RetentionSizePercentage: ptr.To(int32(80)),
+		},
+	})
+	require.NoError(t, err)
+
+	promArgs := sset.Spec.Template.Spec.Containers[0].Args
+	require.Contains(t, promArgs, "--storage.tsdb.retention.size=8192MB")
+	for _, arg := range promArgs {
+		require.False(t, strings.HasPrefix(arg, "--storage.tsdb.retention.time"), "unexpected argument %q", arg)
+	}
+}
+
 func TestReplicasConfigurationWithSharding(t *testing.T) {
 	testConfig := prompkg.Config{
 		ReloaderConfig:             defaultTestConfig.ReloaderConfig,
@@ -1479,7 +1584,8 @@ func TestReplicasConfigurationWithSharding(t *testing.T) {
 		nil,
 		"",
 		1,
-		&operator.ShardedSecret{})
+		&operator.ShardedSecret{},
+		nil)
 	require.NoError(t, err)
 
 	require.Equal(t, int32(2), *sset.Spec.Replicas, "Unexpected replicas configuration.")
@@ -1497,6 +1603,91 @@ func TestReplicasConfigurationWithSharding(t *testing.T) {
 	require.True(t, found, "Shard.")
 }
 
+func TestConfigFilePerShard(t *testing.T) {
+	p := monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Shards: ptr.To(int32(3)),
+				ShardingStrategy: &monitoringv1.ShardingStrategy{
+					Mode: ptr.To(monitoringv1.NamespaceShardingStrategyMode),
+				},
+			},
+		},
+	}
+
+	cg, err := prompkg.NewConfigGenerator(prompkg.NewLogger(), &p)
+	require.NoError(t, err)
+
+	sset, err := makeStatefulSet(
+		"test",
+		&p,
+		defaultTestConfig,
+		cg,
+		nil,
+		"",
+		2,
+		&operator.ShardedSecret{},
+		nil)
+	require.NoError(t, err)
+
+	for _, containers := range [][]corev1.Container{sset.Spec.Template.Spec.InitContainers, sset.Spec.Template.Spec.Containers} {
+		var found bool
+		for _, c := range containers {
+			if c.Name != "config-reloader" && c.Name != "init-config-reloader" {
+				continue
+			}
+
+			require.Contains(t, c.Args, "--config-file=/etc/prometheus/config/prometheus-shard-2.yaml.gz")
+			found = true
+		}
+		require.True(t, found)
+	}
+}
+
+func TestRuleConfigMapsPerShard(t *testing.T) {
+	p := monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Shards: ptr.To(int32(2)),
+			},
+			RuleEvaluation: &monitoringv1.RuleEvaluation{
+				Mode: ptr.To(monitoringv1.HashGroupsRuleEvaluationMode),
+			},
+		},
+	}
+
+	cg, err := prompkg.NewConfigGenerator(prompkg.NewLogger(), &p)
+	require.NoError(t, err)
+
+	sset, err := makeStatefulSet(
+		"test",
+		&p,
+		defaultTestConfig,
+		cg,
+		[]string{"prometheus-test-rulefiles-0"},
+		"",
+		1,
+		&operator.ShardedSecret{},
+		nil)
+	require.NoError(t, err)
+
+	var found bool
+	for _, v := range sset.Spec.Template.Spec.Volumes {
+		if v.Name != "prometheus-test-rulefiles-0" {
+			continue
+		}
+
+		// The volume name (and mount path) is the same for all shards but
+		// the ConfigMap is specific to the shard.
+		require.Equal(t, "prometheus-test-shard-1-rulefiles-0", v.ConfigMap.Name)
+		found = true
+	}
+	require.True(t, found)
+}
+
 func TestSidecarResources(t *testing.T) {
 	operator.TestSidecarsResources(t, func(reloaderConfig operator.ContainerConfig) *appsv1.StatefulSet {
 		testConfig := prompkg.Config{
@@ -1520,7 +1711,8 @@ func TestSidecarResources(t *testing.T) {
 			nil,
 			"",
 			0,
-			&operator.ShardedSecret{})
+			&operator.ShardedSecret{},
+			nil)
 		require.NoError(t, err)
 		return sset
 	})
@@ -1970,7 +2162,8 @@ func TestConfigReloader(t *testing.T) {
 		nil,
 		"",
 		int32(expectedShardNum),
-		&operator.ShardedSecret{})
+		&operator.ShardedSecret{},
+		nil)
 	require.NoError(t, err)
 
 	expectedArgsConfigReloader := []string{
@@ -2030,7 +2223,8 @@ func TestConfigReloaderWithSignal(t *testing.T) {
 		nil,
 		"",
 		int32(expectedShardNum),
-		&operator.ShardedSecret{})
+		&operator.ShardedSecret{},
+		nil)
 	require.NoError(t, err)
 
 	expectedArgsConfigReloader := []string{
@@ -3668,6 +3862,7 @@ func TestConfigReloaderTopologyZoneEnvVar(t *testing.T) {
 				"",
 				tc.shardIndex,
 				&operator.ShardedSecret{},
+				nil,
 			)
 			require.NoError(t, err)
 
@@ -0,0 +1,693 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8s"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// storageMigrationLabelKey is the label identifying the persistent
+	// volumes and the temporary resources created by the storage migration.
+	storageMigrationLabelKey = "operator.prometheus.io/storage-migration"
+	// storageMigrationClaimAnnotationKey is the annotation of a migrated
+	// persistent volume referencing the claim (<namespace>/<name>) which
+	// should be bound to the volume.
+	storageMigrationClaimAnnotationKey = "operator.prometheus.io/storage-migration-claim"
+	// storageMigrationReclaimPolicyAnnotationKey is the annotation of a
+	// migrated persistent volume storing the original reclaim policy.
+	storageMigrationReclaimPolicyAnnotationKey = "operator.prometheus.io/storage-migration-reclaim-policy"
+
+	storageMigrationSuffix = "-migration"
+
+	// storageMigrationRequeueInterval is the interval between 2
+	// reconciliations while a storage migration is in progress (the
+	// operator doesn't watch jobs, pods and persistent volume claims).
+	storageMigrationRequeueInterval = 10 * time.Second
+
+	// defaultStorageMigrationTimeout is the maximum duration of the job
+	// copying the data when spec.storageMigration.timeout isn't defined.
+	defaultStorageMigrationTimeout = 6 * time.Hour
+
+	storageMigrationSourceDir = "/source"
+	storageMigrationTargetDir = "/target"
+)
+
+func storageMigrationEnabled(p *monitoringv1.Prometheus) bool {
+	return p.Spec.StorageMigration != nil && p.Spec.StorageMigration.Policy == monitoringv1.CopyStorageMigrationPolicy
+}
+
+// storageMigrationTimeout returns the maximum duration of the job copying
+// the data of a replica.
+func storageMigrationTimeout(sm *monitoringv1.StorageMigration) (time.Duration, error) {
+	if sm.Timeout == nil {
+		return defaultStorageMigrationTimeout, nil
+	}
+
+	d, err := model.ParseDuration(string(*sm.Timeout))
+	if err != nil {
+		return 0, fmt.Errorf("invalid storage migration timeout %q: %w", *sm.Timeout, err)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid storage migration timeout %q: must be greater than 0", *sm.Timeout)
+	}
+
+	return time.Duration(d), nil
+}
+
+// migrateStorage migrates the persistent volume claims of the statefulset to
+// the storage class of the desired volume claim templates, one replica at a
+// time. For each replica, the steps are:
+// 1. Delete the statefulset without deleting the pods once the other
+// replicas are ready.
+// 2. Delete the pod of the replica.
+// 3. Create a temporary claim using the new storage class and copy the data
+// with a job.
+// 4. Retain the volumes and delete the claims.
+// 5. Bind the new volume to a new claim with the name of the original claim.
+// 6. Let the caller re-create the statefulset which adopts the pods and
+// starts the pod of the migrated replica.
+//
+// Every step is idempotent and the state is derived from the cluster's
+// resources. It returns true while the migration is in progress: the caller
+// shouldn't create nor update the statefulset in this case.
+// existing is nil if the statefulset doesn't exist.
+func (c *Operator) migrateStorage(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, existing, desired *appsv1.StatefulSet) (bool, error) {
+	if !storageMigrationEnabled(p) || len(desired.Spec.VolumeClaimTemplates) == 0 {
+		return false, nil
+	}
+
+	timeout, err := storageMigrationTimeout(p.Spec.StorageMigration)
+	if err != nil {
+		return false, err
+	}
+
+	pvs, err := c.listMigratedVolumes(ctx, p.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	pvcClient := c.kclient.CoreV1().PersistentVolumeClaims(p.Namespace)
+	for ordinal := range ptr.Deref(desired.Spec.Replicas, 1) {
+		for _, tmpl := range desired.Spec.VolumeClaimTemplates {
+			storageClassName := ptr.Deref(tmpl.Spec.StorageClassName, "")
+			if storageClassName == "" {
+				continue
+			}
+
+			claimName := fmt.Sprintf("%s-%s-%d", tmpl.Name, desired.Name, ordinal)
+			pvc, err := pvcClient.Get(ctx, claimName, metav1.GetOptions{})
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					return false, fmt.Errorf("failed to get persistent volume claim %s: %w", claimName, err)
+				}
+				pvc = nil
+			}
+
+			pv := pvs[claimName]
+			switch {
+			case pvc == nil && pv == nil:
+				// The statefulset controller will create the claim.
+				continue
+
+			case pvc != nil && ptr.Deref(pvc.Spec.StorageClassName, "") == storageClassName:
+				if pv != nil && pvc.Status.Phase == corev1.ClaimBound {
+					if err := c.finishVolumeMigration(ctx, pv); err != nil {
+						return false, err
+					}
+				}
+				continue
+			}
+
+			logger := logger.With("ordinal", ordinal, "claim", claimName)
+
+			if existing != nil {
+				return true, c.prepareReplicaMigration(ctx, logger, p.Namespace, existing, ordinal)
+			}
+
+			marked, stopped, err := c.stopReplica(ctx, logger, p.Namespace, fmt.Sprintf("%s-%d", desired.Name, ordinal))
+			if err != nil {
+				return true, err
+			}
+
+			if !marked {
+				// The statefulset needs to be re-created first to restart the
+				// replicas already migrated.
+				return false, nil
+			}
+
+			if !stopped {
+				return true, nil
+			}
+
+			if pvc == nil {
+				return true, c.bindMigratedVolume(ctx, logger, p, desired, tmpl, claimName, pv)
+			}
+
+			return true, c.copyClaim(ctx, logger, p, desired, tmpl, pvc, timeout)
+		}
+	}
+
+	return false, nil
+}
+
+// listMigratedVolumes returns the migrated persistent volumes waiting to be
+// bound (or bound recently) to claims of the namespace, indexed by claim
+// name.
+func (c *Operator) listMigratedVolumes(ctx context.Context, namespace string) (map[string]*corev1.PersistentVolume, error) {
+	list, err := c.kclient.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{LabelSelector: storageMigrationLabelKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %w", err)
+	}
+
+	pvs := make(map[string]*corev1.PersistentVolume, len(list.Items))
+	for i := range list.Items {
+		ns, name, found := strings.Cut(list.Items[i].Annotations[storageMigrationClaimAnnotationKey], "/")
+		if !found || ns != namespace {
+			continue
+		}
+
+		pvs[name] = &list.Items[i]
+	}
+
+	return pvs, nil
+}
+
+// prepareReplicaMigration marks the pod of the replica for the storage
+// migration and deletes the statefulset without deleting the pods once the
+// other replicas are ready.
+func (c *Operator) prepareReplicaMigration(ctx context.Context, logger *slog.Logger, namespace string, sset *appsv1.StatefulSet, ordinal int32) error {
+	if c.rr.DeletionInProgress(sset) {
+		return nil
+	}
+
+	podClient := c.kclient.CoreV1().Pods(namespace)
+
+	// Wait for the other replicas to be ready to keep serving.
+	for i := range ptr.Deref(sset.Spec.Replicas, 1) {
+		if i == ordinal {
+			continue
+		}
+
+		pod, err := podClient.Get(ctx, fmt.Sprintf("%s-%d", sset.Name, i), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !(*operator.Pod)(pod).Ready() {
+			logger.Debug("waiting for the other replicas to be ready before migrating the storage", "pod", pod.Name)
+			return nil
+		}
+	}
+
+	podName := fmt.Sprintf("%s-%d", sset.Name, ordinal)
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{storageMigrationLabelKey: "true"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := podClient.Patch(ctx, podName, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: k8s.PrometheusOperatorFieldManager}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to patch pod %s: %w", podName, err)
+	}
+
+	logger.Info("deleting StatefulSet to migrate the storage")
+	c.metrics.StsDeleteCreateCounter().Inc()
+	return k8s.OrphanDeleteStatefulSet(ctx, c.kclient.AppsV1().StatefulSets(namespace), sset.Name)
+}
+
+// stopReplica deletes the pod of the replica if it has been marked for the
+// storage migration. The first value is false if the pod exists but isn't
+// marked. The second value is true once the pod is gone.
+func (c *Operator) stopReplica(ctx context.Context, logger *slog.Logger, namespace string, podName string) (bool, bool, error) {
+	podClient := c.kclient.CoreV1().Pods(namespace)
+
+	pod, err := podClient.Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, true, nil
+		}
+		return false, false, err
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return true, false, nil
+	}
+
+	if _, found := pod.Labels[storageMigrationLabelKey]; !found {
+		return false, false, nil
+	}
+
+	logger.Info("deleting pod to migrate the storage", "pod", podName)
+	if err := podClient.Delete(ctx, podName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return false, false, fmt.Errorf("failed to delete pod %s: %w", podName, err)
+	}
+
+	return true, false, nil
+}
+
+// copyClaim copies the data of the claim to a temporary claim using the new
+// storage class. Once the copy is done, the new volume is retained and
+// annotated with the name of the claim before deleting the claims.
+// The job copying the data fails if it doesn't complete within the timeout.
+func (c *Operator) copyClaim(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, sset *appsv1.StatefulSet, tmpl corev1.PersistentVolumeClaim, pvc *corev1.PersistentVolumeClaim, timeout time.Duration) error {
+	pvcClient := c.kclient.CoreV1().PersistentVolumeClaims(p.Namespace)
+	if pvc.Spec.VolumeName == "" {
+		// The claim isn't bound: there's no data to copy.
+		logger.Info("deleting unbound persistent volume claim to migrate the storage")
+		return pvcClient.Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+	}
+
+	target, err := pvcClient.Get(ctx, pvc.Name+storageMigrationSuffix, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		target = makeStorageMigrationClaim(p, sset, tmpl, pvc.Name+storageMigrationSuffix)
+		target.Labels[storageMigrationLabelKey] = "true"
+		if capacity, found := pvc.Status.Capacity[corev1.ResourceStorage]; found {
+			if request := target.Spec.Resources.Requests[corev1.ResourceStorage]; capacity.Cmp(request) > 0 {
+				target.Spec.Resources.Requests[corev1.ResourceStorage] = capacity
+			}
+		}
+
+		logger.Info("creating persistent volume claim to migrate the storage", "target", target.Name)
+		_, err = pvcClient.Create(ctx, target, metav1.CreateOptions{})
+		return err
+	}
+
+	jobName, err := k8s.ResourceNamer{}.UniqueDNS1123Label(pvc.Name + storageMigrationSuffix)
+	if err != nil {
+		return err
+	}
+
+	jobClient := c.kclient.BatchV1().Jobs(p.Namespace)
+	job, err := jobClient.Get(ctx, jobName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		logger.Info("creating job to copy the data", "job", jobName)
+		_, err = jobClient.Create(ctx, makeStorageMigrationJob(p, sset, jobName, pvc.Name, target.Name, timeout), metav1.CreateOptions{})
+		return err
+	}
+
+	switch {
+	case jobCondition(job, batchv1.JobFailed) != nil:
+		return fmt.Errorf("job %s copying the data of persistent volume claim %s failed: %s", jobName, pvc.Name, jobFailure(job))
+	case jobCondition(job, batchv1.JobComplete) == nil:
+		return nil
+	}
+
+	if target.Spec.VolumeName == "" {
+		return fmt.Errorf("persistent volume claim %s isn't bound", target.Name)
+	}
+
+	// Retain the new volume to bind it to the claim of the replica.
+	pv, err := c.kclient.CoreV1().PersistentVolumes().Get(ctx, target.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if _, found := pv.Labels[storageMigrationLabelKey]; !found {
+		if err := c.patchPersistentVolume(ctx, pv.Name, map[string]any{
+			"metadata": map[string]any{
+				"labels": map[string]any{storageMigrationLabelKey: "true"},
+				"annotations": map[string]any{
+					storageMigrationClaimAnnotationKey:         p.Namespace + "/" + pvc.Name,
+					storageMigrationReclaimPolicyAnnotationKey: string(pv.Spec.PersistentVolumeReclaimPolicy),
+				},
+			},
+			"spec": map[string]any{
+				"persistentVolumeReclaimPolicy": corev1.PersistentVolumeReclaimRetain,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Retain the previous volume as a backup.
+	if err := c.patchPersistentVolume(ctx, pvc.Spec.VolumeName, map[string]any{
+		"spec": map[string]any{
+			"persistentVolumeReclaimPolicy": corev1.PersistentVolumeReclaimRetain,
+		},
+	}); err != nil {
+		return err
+	}
+
+	logger.Info("data copied, deleting the persistent volume claims", "previous_volume", pvc.Spec.VolumeName, "volume", pv.Name)
+	if err := jobClient.Delete(ctx, jobName, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	for _, name := range []string{target.Name, pvc.Name} {
+		if err := pvcClient.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindMigratedVolume creates the claim of the replica bound to the migrated
+// volume.
+func (c *Operator) bindMigratedVolume(ctx context.Context, logger *slog.Logger, p *monitoringv1.Prometheus, sset *appsv1.StatefulSet, tmpl corev1.PersistentVolumeClaim, claimName string, pv *corev1.PersistentVolume) error {
+	pvcClient := c.kclient.CoreV1().PersistentVolumeClaims(p.Namespace)
+
+	// Wait for the temporary claim to be deleted.
+	_, err := pvcClient.Get(ctx, claimName+storageMigrationSuffix, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.Name != claimName {
+		if err := c.patchPersistentVolume(ctx, pv.Name, map[string]any{
+			"spec": map[string]any{
+				"claimRef": nil,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	pvc := makeStorageMigrationClaim(p, sset, tmpl, claimName)
+	pvc.Spec.VolumeName = pv.Name
+
+	logger.Info("binding the migrated persistent volume", "volume", pv.Name)
+	_, err = pvcClient.Create(ctx, pvc, metav1.CreateOptions{})
+	return err
+}
+
+// finishVolumeMigration restores the reclaim policy of the migrated volume
+// once it is bound to the claim of the replica.
+func (c *Operator) finishVolumeMigration(ctx context.Context, pv *corev1.PersistentVolume) error {
+	spec := map[string]any{}
+	if policy := pv.Annotations[storageMigrationReclaimPolicyAnnotationKey]; policy != "" {
+		spec["persistentVolumeReclaimPolicy"] = policy
+	}
+
+	return c.patchPersistentVolume(ctx, pv.Name, map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{storageMigrationLabelKey: nil},
+			"annotations": map[string]any{
+				storageMigrationClaimAnnotationKey:         nil,
+				storageMigrationReclaimPolicyAnnotationKey: nil,
+			},
+		},
+		"spec": spec,
+	})
+}
+
+func (c *Operator) patchPersistentVolume(ctx context.Context, name string, patch map[string]any) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.kclient.CoreV1().PersistentVolumes().Patch(
+		ctx,
+		name,
+		types.MergePatchType,
+		data,
+		metav1.PatchOptions{FieldManager: k8s.PrometheusOperatorFieldManager},
+	); err != nil {
+		return fmt.Errorf("failed to patch persistent volume %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// makeStorageMigrationClaim returns a claim created from the volume claim
+// template of the statefulset.
+func makeStorageMigrationClaim(p *monitoringv1.Prometheus, sset *appsv1.StatefulSet, tmpl corev1.PersistentVolumeClaim, name string) *corev1.PersistentVolumeClaim {
+	labels := maps.Clone(tmpl.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if sset.Spec.Selector != nil {
+		maps.Copy(labels, sset.Spec.Selector.MatchLabels)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   p.Namespace,
+			Labels:      labels,
+			Annotations: maps.Clone(tmpl.Annotations),
+		},
+		Spec: *tmpl.Spec.DeepCopy(),
+	}
+
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+
+	return pvc
+}
+
+// makeStorageMigrationJob returns the job copying the data from the source
+// claim to the target claim.
+func makeStorageMigrationJob(p *monitoringv1.Prometheus, sset *appsv1.StatefulSet, name, source, target string, timeout time.Duration) *batchv1.Job {
+	podSpec := sset.Spec.Template.Spec
+
+	image := ptr.Deref(p.Spec.StorageMigration.Image, "")
+	if image == "" {
+		for _, container := range podSpec.Containers {
+			if container.Name == "prometheus" {
+				image = container.Image
+				break
+			}
+		}
+	}
+
+	labels := map[string]string{storageMigrationLabelKey: "true"}
+	maps.Copy(labels, makeSelectorLabels(p.Name))
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: p.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(3)),
+			// The pod may never be scheduled (e.g. when the new volume is
+			// provisioned in another zone than the previous volume).
+			ActiveDeadlineSeconds: ptr.To(int64(timeout.Seconds())),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					SecurityContext:  podSpec.SecurityContext,
+					NodeSelector:     podSpec.NodeSelector,
+					Tolerations:      podSpec.Tolerations,
+					ImagePullSecrets: podSpec.ImagePullSecrets,
+					Containers: []corev1.Container{
+						{
+							Name:    "copy",
+							Image:   image,
+							Command: []string{"cp", "-a", storageMigrationSourceDir + "/.", storageMigrationTargetDir + "/"},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "source", MountPath: storageMigrationSourceDir, ReadOnly: true},
+								{Name: "target", MountPath: storageMigrationTargetDir},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: ptr.To(false),
+								ReadOnlyRootFilesystem:   ptr.To(true),
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "source",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: source, ReadOnly: true},
+							},
+						},
+						{
+							Name: "target",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: target},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	operator.UpdateObject(
+		job,
+		operator.WithLabels(labels),
+		operator.WithManagingOwner(p),
+	)
+
+	return job
+}
+
+// jobCondition returns the condition of the given type if its status is
+// true, nil otherwise.
+func jobCondition(job *batchv1.Job, t batchv1.JobConditionType) *batchv1.JobCondition {
+	for i, cond := range job.Status.Conditions {
+		if cond.Type == t && cond.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// jobFailure returns a message describing why the job failed.
+func jobFailure(job *batchv1.Job) string {
+	reason := "unknown reason"
+	if cond := jobCondition(job, batchv1.JobFailed); cond != nil && cond.Reason != "" {
+		reason = cond.Reason
+		if cond.Message != "" {
+			reason += ": " + cond.Message
+		}
+	}
+
+	return reason + " (delete the job to retry)"
+}
+
+// storageMigratedCondition returns the StorageMigrated condition reporting
+// how many persistent volume claims use the storage class of the volume
+// claim template. It returns nil if the storage migration isn't enabled.
+func (c *Operator) storageMigratedCondition(ctx context.Context, p *monitoringv1.Prometheus) *monitoringv1.Condition {
+	if !storageMigrationEnabled(p) || p.Spec.Storage == nil || p.Spec.Storage.EmptyDir != nil || p.Spec.Storage.Ephemeral != nil {
+		return nil
+	}
+
+	storageClassName := ptr.Deref(p.Spec.Storage.VolumeClaimTemplate.Spec.StorageClassName, "")
+	if storageClassName == "" {
+		return nil
+	}
+
+	condition := &monitoringv1.Condition{
+		Type:   monitoringv1.StorageMigrated,
+		Status: monitoringv1.ConditionTrue,
+		LastTransitionTime: metav1.Time{
+			Time: time.Now().UTC(),
+		},
+		ObservedGeneration: p.Generation,
+	}
+
+	unknown := func(err error) *monitoringv1.Condition {
+		condition.Status = monitoringv1.ConditionUnknown
+		condition.Reason = "ListFailed"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	pvs, err := c.listMigratedVolumes(ctx, p.Namespace)
+	if err != nil {
+		return unknown(err)
+	}
+
+	tmplName := p.Spec.Storage.VolumeClaimTemplate.Name
+	if tmplName == "" {
+		tmplName = prompkg.VolumeName(p)
+	}
+
+	var total, migrated int
+	for _, ssetName := range prompkg.ExpectedStatefulSetShardNames(p) {
+		for ordinal := range *prompkg.ReplicasNumberPtr(p) {
+			claimName := fmt.Sprintf("%s-%s-%d", tmplName, ssetName, ordinal)
+			_, pending := pvs[claimName]
+
+			pvc, err := c.kclient.CoreV1().PersistentVolumeClaims(p.Namespace).Get(ctx, claimName, metav1.GetOptions{})
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					return unknown(err)
+				}
+
+				if pending {
+					// The claim is being re-created.
+					total++
+				}
+				continue
+			}
+
+			total++
+			if ptr.Deref(pvc.Spec.StorageClassName, "") == storageClassName && !pending {
+				migrated++
+			}
+		}
+	}
+
+	if migrated < total {
+		condition.Status = monitoringv1.ConditionFalse
+		condition.Reason = "MigrationInProgress"
+		condition.Message = fmt.Sprintf("%d/%d persistent volume claims migrated to storage class %q", migrated, total, storageClassName)
+	}
+
+	jobLabels := map[string]string{storageMigrationLabelKey: "true"}
+	maps.Copy(jobLabels, makeSelectorLabels(p.Name))
+	jobs, err := c.kclient.BatchV1().Jobs(p.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(jobLabels).String(),
+	})
+	if err != nil {
+		return unknown(err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if jobCondition(job, batchv1.JobFailed) == nil {
+			continue
+		}
+
+		condition.Status = monitoringv1.ConditionFalse
+		condition.Reason = "MigrationFailed"
+		condition.Message = fmt.Sprintf("%d/%d persistent volume claims migrated to storage class %q, job %s failed: %s", migrated, total, storageClassName, job.Name, jobFailure(job))
+		break
+	}
+
+	return condition
+}
@@ -0,0 +1,339 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+func newStorageMigrationStatefulSet(storageClass string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-test",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To(int32(2)),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: makeSelectorLabels("test"),
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "prometheus", Image: "quay.io/prometheus/prometheus"},
+					},
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "prometheus-test-db"},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: ptr.To(storageClass),
+						AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("10Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newStorageMigrationPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    makeSelectorLabels("test"),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func newStorageMigrationClaim(name, storageClass, volume string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    makeSelectorLabels("test"),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To(storageClass),
+			VolumeName:       volume,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10Gi"),
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			},
+		},
+	}
+}
+
+func newStorageMigrationVolume(name, claim string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			ClaimRef: &corev1.ObjectReference{
+				Namespace: "default",
+				Name:      claim,
+			},
+		},
+	}
+}
+
+func TestMigrateStorage(t *testing.T) {
+	ctx := context.Background()
+
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{
+			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+				Replicas: ptr.To(int32(2)),
+				Storage: &monitoringv1.StorageSpec{
+					VolumeClaimTemplate: monitoringv1.EmbeddedPersistentVolumeClaim{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							StorageClassName: ptr.To("new"),
+						},
+					},
+				},
+			},
+			StorageMigration: &monitoringv1.StorageMigration{
+				Policy: monitoringv1.CopyStorageMigrationPolicy,
+			},
+		},
+	}
+
+	const (
+		claim0 = "prometheus-test-db-prometheus-test-0"
+		claim1 = "prometheus-test-db-prometheus-test-1"
+	)
+
+	kclient := fake.NewClientset(
+		newStorageMigrationStatefulSet("old"),
+		newStorageMigrationPod("prometheus-test-0"),
+		newStorageMigrationPod("prometheus-test-1"),
+		newStorageMigrationClaim(claim0, "old", "pv-old-0"),
+		newStorageMigrationClaim(claim1, "old", "pv-old-1"),
+		newStorageMigrationVolume("pv-old-0", claim0),
+		newStorageMigrationVolume("pv-old-1", claim1),
+	)
+
+	c := &Operator{
+		kclient: kclient,
+		rr:      &operator.ResourceReconciler{},
+		metrics: operator.NewMetrics(prometheus.NewPedanticRegistry()),
+	}
+
+	desired := newStorageMigrationStatefulSet("new")
+	logger := slog.New(slog.DiscardHandler)
+
+	migrate := func(t *testing.T) bool {
+		t.Helper()
+
+		existing, err := kclient.AppsV1().StatefulSets("default").Get(ctx, "prometheus-test", metav1.GetOptions{})
+		if err != nil {
+			existing = nil
+		}
+
+		migrating, err := c.migrateStorage(ctx, logger, p, existing, desired)
+		require.NoError(t, err)
+
+		return migrating
+	}
+
+	// The pod is marked and the statefulset is deleted.
+	require.True(t, migrate(t))
+	pod, err := kclient.CoreV1().Pods("default").Get(ctx, "prometheus-test-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, pod.Labels, storageMigrationLabelKey)
+	_, err = kclient.AppsV1().StatefulSets("default").Get(ctx, "prometheus-test", metav1.GetOptions{})
+	require.Error(t, err)
+
+	// The pod is deleted.
+	require.True(t, migrate(t))
+	_, err = kclient.CoreV1().Pods("default").Get(ctx, "prometheus-test-0", metav1.GetOptions{})
+	require.Error(t, err)
+
+	// The target claim is created.
+	require.True(t, migrate(t))
+	target, err := kclient.CoreV1().PersistentVolumeClaims("default").Get(ctx, claim0+storageMigrationSuffix, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "new", *target.Spec.StorageClassName)
+
+	// The copy job is created.
+	require.True(t, migrate(t))
+	jobs, err := kclient.BatchV1().Jobs("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	job := &jobs.Items[0]
+	require.Equal(t, "quay.io/prometheus/prometheus", job.Spec.Template.Spec.Containers[0].Image)
+	require.Equal(t, claim0, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	require.Equal(t, claim0+storageMigrationSuffix, job.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+	require.Equal(t, int64(6*60*60), *job.Spec.ActiveDeadlineSeconds)
+
+	// Nothing happens until the job completes.
+	require.True(t, migrate(t))
+
+	// The failure of the job is reported until the job is deleted.
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"}}
+	job, err = kclient.BatchV1().Jobs("default").UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	existing, err := kclient.AppsV1().StatefulSets("default").Get(ctx, "prometheus-test", metav1.GetOptions{})
+	if err != nil {
+		existing = nil
+	}
+	migrating, err := c.migrateStorage(ctx, logger, p, existing, desired)
+	require.True(t, migrating)
+	require.ErrorContains(t, err, "DeadlineExceeded: Job was active longer than specified deadline (delete the job to retry)")
+
+	cond := c.storageMigratedCondition(ctx, p)
+	require.NotNil(t, cond)
+	require.Equal(t, monitoringv1.ConditionFalse, cond.Status)
+	require.Equal(t, "MigrationFailed", cond.Reason)
+	require.Contains(t, cond.Message, "job "+job.Name+" failed: DeadlineExceeded")
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	_, err = kclient.BatchV1().Jobs("default").UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	target.Spec.VolumeName = "pv-new-0"
+	_, err = kclient.CoreV1().PersistentVolumeClaims("default").Update(ctx, target, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = kclient.CoreV1().PersistentVolumes().Create(ctx, newStorageMigrationVolume("pv-new-0", target.Name), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// The volumes are retained and the claims are deleted.
+	require.True(t, migrate(t))
+	pv, err := kclient.CoreV1().PersistentVolumes().Get(ctx, "pv-new-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, corev1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	require.Equal(t, "default/"+claim0, pv.Annotations[storageMigrationClaimAnnotationKey])
+	pv, err = kclient.CoreV1().PersistentVolumes().Get(ctx, "pv-old-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, corev1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	for _, name := range []string{claim0, claim0 + storageMigrationSuffix} {
+		_, err = kclient.CoreV1().PersistentVolumeClaims("default").Get(ctx, name, metav1.GetOptions{})
+		require.Error(t, err, name)
+	}
+
+	// The claim is re-created and bound to the new volume.
+	require.True(t, migrate(t))
+	pvc, err := kclient.CoreV1().PersistentVolumeClaims("default").Get(ctx, claim0, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "pv-new-0", pvc.Spec.VolumeName)
+	require.Equal(t, "new", *pvc.Spec.StorageClassName)
+	pv, err = kclient.CoreV1().PersistentVolumes().Get(ctx, "pv-new-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, pv.Spec.ClaimRef)
+
+	cond = c.storageMigratedCondition(ctx, p)
+	require.NotNil(t, cond)
+	require.Equal(t, monitoringv1.ConditionFalse, cond.Status)
+	require.Equal(t, `0/2 persistent volume claims migrated to storage class "new"`, cond.Message)
+
+	pvc.Status.Phase = corev1.ClaimBound
+	_, err = kclient.CoreV1().PersistentVolumeClaims("default").UpdateStatus(ctx, pvc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// The migration of the first replica is done: the statefulset needs to
+	// be re-created before migrating the second replica.
+	require.False(t, migrate(t))
+	pv, err = kclient.CoreV1().PersistentVolumes().Get(ctx, "pv-new-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, corev1.PersistentVolumeReclaimDelete, pv.Spec.PersistentVolumeReclaimPolicy)
+	require.NotContains(t, pv.Labels, storageMigrationLabelKey)
+
+	cond = c.storageMigratedCondition(ctx, p)
+	require.NotNil(t, cond)
+	require.Equal(t, monitoringv1.ConditionFalse, cond.Status)
+	require.Equal(t, `1/2 persistent volume claims migrated to storage class "new"`, cond.Message)
+
+	// The second replica isn't migrated until the first one is ready.
+	_, err = kclient.AppsV1().StatefulSets("default").Create(ctx, desired, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.True(t, migrate(t))
+	_, err = kclient.AppsV1().StatefulSets("default").Get(ctx, "prometheus-test", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = kclient.CoreV1().Pods("default").Create(ctx, newStorageMigrationPod("prometheus-test-0"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.True(t, migrate(t))
+	_, err = kclient.AppsV1().StatefulSets("default").Get(ctx, "prometheus-test", metav1.GetOptions{})
+	require.Error(t, err)
+	pod, err = kclient.CoreV1().Pods("default").Get(ctx, "prometheus-test-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, pod.Labels, storageMigrationLabelKey)
+}
+
+func TestMigrateStorageDisabled(t *testing.T) {
+	for _, sm := range []*monitoringv1.StorageMigration{
+		nil,
+		{Policy: monitoringv1.NoneStorageMigrationPolicy},
+	} {
+		t.Run(fmt.Sprintf("%v", sm), func(t *testing.T) {
+			kclient := fake.NewClientset(
+				newStorageMigrationClaim("prometheus-test-db-prometheus-test-0", "old", "pv-old-0"),
+			)
+			c := &Operator{kclient: kclient}
+
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: monitoringv1.PrometheusSpec{
+					StorageMigration: sm,
+				},
+			}
+
+			migrating, err := c.migrateStorage(context.Background(), slog.New(slog.DiscardHandler), p, nil, newStorageMigrationStatefulSet("new"))
+			require.NoError(t, err)
+			require.False(t, migrating)
+			require.Nil(t, c.storageMigratedCondition(context.Background(), p))
+		})
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+const (
+	// defaultShardingLabelName is the default label identifying the shard
+	// key of the monitoring resources with the Label sharding mode.
+	defaultShardingLabelName = "operator.prometheus.io/shard"
+
+	namespaceMetaLabel = "__meta_kubernetes_namespace"
+)
+
+// PerShardConfiguration returns true when the sharding strategy assigns the
+// monitoring resources to shards. In this case, each shard has its own
+// configuration file.
+func PerShardConfiguration(p monitoringv1.PrometheusInterface) bool {
+	ss := p.GetCommonPrometheusFields().ShardingStrategy
+	if ss == nil || ss.Mode == nil {
+		return false
+	}
+
+	switch *ss.Mode {
+	case monitoringv1.NamespaceShardingStrategyMode, monitoringv1.LabelShardingStrategyMode:
+		return true
+	}
+
+	return false
+}
+
+// ConfigFilenameForShard returns the name of the configuration file used by
+// the given shard.
+func ConfigFilenameForShard(p monitoringv1.PrometheusInterface, shard int32) string {
+	if !PerShardConfiguration(p) {
+		return ConfigFilename
+	}
+
+	return perShardConfigFilename(shard)
+}
+
+func perShardConfigFilename(shard int32) string {
+	return fmt.Sprintf("prometheus-shard-%d.yaml.gz", shard)
+}
+
+// ShardConfigurationRollout returns true when the configuration changes are
+// rolled out shard by shard.
+func (cg *ConfigGenerator) ShardConfigurationRollout() bool {
+	return cg.shardConfigurationRollout
+}
+
+// PerShardConfigurationFiles returns true when each shard reads its own
+// configuration file.
+func (cg *ConfigGenerator) PerShardConfigurationFiles() bool {
+	return cg.shardConfigurationRollout || PerShardConfiguration(cg.prom)
+}
+
+// ConfigFilenameForShard returns the name of the configuration file used by
+// the given shard.
+func (cg *ConfigGenerator) ConfigFilenameForShard(shard int32) string {
+	if !cg.PerShardConfigurationFiles() {
+		return ConfigFilename
+	}
+
+	return perShardConfigFilename(shard)
+}
+
+// ShardAssigner assigns namespaces and monitoring resources to shards when
+// the sharding strategy mode is Namespace or Label.
+//
+// The assignment is deterministic: a key which isn't pinned explicitly is
+// assigned to the shard computed by the hashmod relabeling action of
+// Prometheus which means that the operator and the Prometheus relabeling
+// rules agree on the shard of a namespace.
+type ShardAssigner struct {
+	shards    int32
+	mode      monitoringv1.ShardingStrategyMode
+	labelName string
+	// pinned keys (namespaces or label values) to shards.
+	assignments map[string]int32
+}
+
+// NewShardAssigner returns a ShardAssigner for the Prometheus object.
+// It returns nil when the sharding strategy doesn't assign resources to
+// shards.
+func NewShardAssigner(p monitoringv1.PrometheusInterface, logger *slog.Logger) *ShardAssigner {
+	if !PerShardConfiguration(p) {
+		return nil
+	}
+
+	var (
+		ss = p.GetCommonPrometheusFields().ShardingStrategy
+		a  = &ShardAssigner{
+			shards:      ShardsNumber(p),
+			mode:        *ss.Mode,
+			labelName:   defaultShardingLabelName,
+			assignments: map[string]int32{},
+		}
+		assignments []monitoringv1.ShardAssignment
+	)
+
+	switch a.mode {
+	case monitoringv1.NamespaceShardingStrategyMode:
+		if ss.Namespace != nil {
+			assignments = ss.Namespace.Assignments
+		}
+	case monitoringv1.LabelShardingStrategyMode:
+		if ss.Label != nil {
+			a.labelName = ptr.Deref(ss.Label.LabelName, defaultShardingLabelName)
+			assignments = ss.Label.Assignments
+		}
+	}
+
+	for _, sa := range assignments {
+		if sa.Shard >= a.shards {
+			logger.Warn("ignoring shard assignment because the shard is out of range", "value", sa.Value, "shard", sa.Shard, "shards", a.shards)
+			continue
+		}
+
+		a.assignments[sa.Value] = sa.Shard
+	}
+
+	return a
+}
+
+// Shards returns the number of shards.
+func (a *ShardAssigner) Shards() int32 {
+	return a.shards
+}
+
+// ShardForNamespace returns the shard of the namespace.
+func (a *ShardAssigner) ShardForNamespace(namespace string) int32 {
+	if a.mode == monitoringv1.NamespaceShardingStrategyMode {
+		if shard, found := a.assignments[namespace]; found {
+			return shard
+		}
+	}
+
+	return hashmod(namespace, a.shards)
+}
+
+// ShardFor returns the shard of the monitoring resource.
+func (a *ShardAssigner) ShardFor(o metav1.Object) int32 {
+	if a.mode == monitoringv1.LabelShardingStrategyMode {
+		if v, found := o.GetLabels()[a.labelName]; found && v != "" {
+			if shard, found := a.assignments[v]; found {
+				return shard
+			}
+
+			return hashmod(v, a.shards)
+		}
+	}
+
+	return a.ShardForNamespace(o.GetNamespace())
+}
+
+// pinnedNamespaces returns the namespaces pinned to the given shard.
+func (a *ShardAssigner) pinnedNamespaces(shard int32) []string {
+	if a.mode != monitoringv1.NamespaceShardingStrategyMode {
+		return nil
+	}
+
+	var namespaces []string
+	for ns, s := range a.assignments {
+		if s == shard {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	return namespaces
+}
+
+// hashmod returns the same value as the hashmod relabeling action of
+// Prometheus for the given value and modulus.
+func hashmod(value string, modulus int32) int32 {
+	sum := md5.Sum([]byte(value))
+	return int32(binary.BigEndian.Uint64(sum[8:]) % uint64(modulus))
+}
+
+// filterForShard returns the resources assigned to the shard.
+func filterForShard[T metav1.Object](a *ShardAssigner, resources map[string]T, shard int32) map[string]T {
+	filtered := make(map[string]T)
+	for k, res := range resources {
+		if a.ShardFor(res) == shard {
+			filtered[k] = res
+		}
+	}
+
+	return filtered
+}
+
+// addNamespacesForShard adds the namespaces of the resources assigned to the
+// shard.
+func addNamespacesForShard[T metav1.Object](a *ShardAssigner, resources map[string]T, shard int32, namespaces sets.Set[string]) {
+	for _, res := range resources {
+		if a.ShardFor(res) == shard {
+			namespaces.Insert(res.GetNamespace())
+		}
+	}
+}
+
+// appendNamespaceShardingRelabelings appends the relabeling rules which
+// distribute the targets across shards based on their namespace. The
+// pinned namespaces are applied after the hashmod action.
+func (cg *ConfigGenerator) appendNamespaceShardingRelabelings(relabelings []yaml.MapSlice, shards int32, a *ShardAssigner) []yaml.MapSlice {
+	relabelings = cg.appendInRangeShardRelabelings(relabelings, shards)
+
+	relabelings = append(relabelings,
+		yaml.MapSlice{
+			{Key: "source_labels", Value: []string{namespaceMetaLabel, hashLabelNameForSharding}},
+			{Key: "target_label", Value: hashLabelNameForSharding},
+			{Key: "regex", Value: "(.+);"},
+			{Key: "replacement", Value: "$1"},
+			{Key: "action", Value: "replace"},
+		}, yaml.MapSlice{
+			{Key: "source_labels", Value: []string{hashLabelNameForSharding}},
+			{Key: "target_label", Value: hashLabelNameForSharding},
+			{Key: "modulus", Value: shards},
+			{Key: "action", Value: "hashmod"},
+		},
+	)
+
+	for shard := range shards {
+		namespaces := a.pinnedNamespaces(shard)
+		if len(namespaces) == 0 {
+			continue
+		}
+
+		slices.Sort(namespaces)
+		for i := range namespaces {
+			namespaces[i] = regexp.QuoteMeta(namespaces[i])
+		}
+
+		relabelings = append(relabelings, yaml.MapSlice{
+			{Key: "source_labels", Value: []string{namespaceMetaLabel}},
+			{Key: "target_label", Value: hashLabelNameForSharding},
+			{Key: "regex", Value: fmt.Sprintf("(%s)", strings.Join(namespaces, "|"))},
+			{Key: "replacement", Value: strconv.Itoa(int(shard))},
+			{Key: "action", Value: "replace"},
+		})
+	}
+
+	return append(relabelings, yaml.MapSlice{
+		{Key: "source_labels", Value: []string{hashLabelNameForSharding, hashLabelNameForDisablingSharding}},
+		{Key: "regex", Value: fmt.Sprintf("$(%s);|.+;.+", operator.ShardEnvVar)},
+		{Key: "action", Value: "keep"},
+	})
+}
+
+// ShardNamespaces records the namespaces assigned to the shards of the
+// Prometheus objects which use per-shard configurations.
+//
+// It is safe for concurrent use.
+type ShardNamespaces struct {
+	mtx sync.RWMutex
+	// namespaces are indexed by Prometheus key (<namespace>/<name>) and by
+	// shard.
+	namespaces map[string][][]string
+}
+
+// NewShardNamespaces returns an empty ShardNamespaces.
+func NewShardNamespaces() *ShardNamespaces {
+	return &ShardNamespaces{
+		namespaces: map[string][][]string{},
+	}
+}
+
+// Set records the namespaces of each shard for the Prometheus object
+// identified by key.
+func (sn *ShardNamespaces) Set(key string, namespaces [][]string) {
+	sn.mtx.Lock()
+	defer sn.mtx.Unlock()
+
+	sn.namespaces[key] = namespaces
+}
+
+// Delete removes the namespaces of the Prometheus object identified by key.
+func (sn *ShardNamespaces) Delete(key string) {
+	sn.mtx.Lock()
+	defer sn.mtx.Unlock()
+
+	delete(sn.namespaces, key)
+}
+
+// UpdateStatus sets the namespaces of the shard statuses for the Prometheus
+// object identified by key.
+func (sn *ShardNamespaces) UpdateStatus(key string, status *monitoringv1.PrometheusStatus) {
+	sn.mtx.RLock()
+	defer sn.mtx.RUnlock()
+
+	namespaces, found := sn.namespaces[key]
+	if !found {
+		return
+	}
+
+	for i := range status.ShardStatuses {
+		shard, err := strconv.Atoi(status.ShardStatuses[i].ShardID)
+		if err != nil || shard >= len(namespaces) {
+			continue
+		}
+
+		status.ShardStatuses[i].Namespaces = namespaces[shard]
+	}
+}
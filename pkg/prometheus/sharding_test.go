@@ -0,0 +1,233 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	"gotest.tools/v3/golden"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+func TestHashmodMatchesPrometheus(t *testing.T) {
+	for _, modulus := range []int32{1, 2, 3, 7} {
+		for _, ns := range []string{"default", "monitoring", "team-a", "team-b", "kube-system"} {
+			lb := labels.NewBuilder(labels.FromStrings("namespace", ns))
+			require.True(t, relabel.ProcessBuilder(lb, &relabel.Config{
+				SourceLabels: model.LabelNames{"namespace"},
+				Separator:    ";",
+				Modulus:      uint64(modulus),
+				TargetLabel:  "shard",
+				Action:       relabel.HashMod,
+			}))
+
+			require.Equal(t, lb.Get("shard"), strconv.Itoa(int(hashmod(ns, modulus))), "namespace %q, modulus %d", ns, modulus)
+		}
+	}
+}
+
+func TestShardAssigner(t *testing.T) {
+	newObject := func(ns string, labels map[string]string) metav1.Object {
+		return &metav1.ObjectMeta{Namespace: ns, Name: "test", Labels: labels}
+	}
+
+	t.Run("address mode", func(t *testing.T) {
+		p := defaultPrometheus()
+		p.Spec.Shards = ptr.To(int32(2))
+		require.Nil(t, NewShardAssigner(p, slog.New(slog.DiscardHandler)))
+		require.False(t, PerShardConfiguration(p))
+		require.Equal(t, ConfigFilename, ConfigFilenameForShard(p, 1))
+	})
+
+	t.Run("namespace mode", func(t *testing.T) {
+		p := defaultPrometheus()
+		p.Spec.Shards = ptr.To(int32(3))
+		p.Spec.ShardingStrategy = &monitoringv1.ShardingStrategy{
+			Mode: ptr.To(monitoringv1.NamespaceShardingStrategyMode),
+			Namespace: &monitoringv1.NamespaceShardingStrategy{
+				Assignments: []monitoringv1.ShardAssignment{
+					{Value: "team-a", Shard: 2},
+					// Out of range.
+					{Value: "team-b", Shard: 3},
+				},
+			},
+		}
+
+		a := NewShardAssigner(p, slog.New(slog.DiscardHandler))
+		require.NotNil(t, a)
+		require.True(t, PerShardConfiguration(p))
+		require.Equal(t, "prometheus-shard-1.yaml.gz", ConfigFilenameForShard(p, 1))
+
+		require.Equal(t, int32(2), a.ShardForNamespace("team-a"))
+		require.Equal(t, hashmod("team-b", 3), a.ShardForNamespace("team-b"))
+		require.Equal(t, []string{"team-a"}, a.pinnedNamespaces(2))
+
+		// Labels are ignored.
+		require.Equal(t, int32(2), a.ShardFor(newObject("team-a", map[string]string{defaultShardingLabelName: "team-b"})))
+	})
+
+	t.Run("label mode", func(t *testing.T) {
+		p := defaultPrometheus()
+		p.Spec.Shards = ptr.To(int32(3))
+		p.Spec.ShardingStrategy = &monitoringv1.ShardingStrategy{
+			Mode: ptr.To(monitoringv1.LabelShardingStrategyMode),
+			Label: &monitoringv1.LabelShardingStrategy{
+				LabelName: ptr.To("team"),
+				Assignments: []monitoringv1.ShardAssignment{
+					{Value: "frontend", Shard: 1},
+					// Namespaces can't be pinned in label mode.
+					{Value: "team-a", Shard: 2},
+				},
+			},
+		}
+
+		a := NewShardAssigner(p, slog.New(slog.DiscardHandler))
+		require.NotNil(t, a)
+
+		require.Equal(t, int32(1), a.ShardFor(newObject("team-a", map[string]string{"team": "frontend"})))
+		require.Equal(t, hashmod("backend", 3), a.ShardFor(newObject("team-a", map[string]string{"team": "backend"})))
+		require.Equal(t, hashmod("team-a", 3), a.ShardFor(newObject("team-a", nil)))
+		require.Empty(t, a.pinnedNamespaces(2))
+	})
+}
+
+func TestGenerateServerConfigurationPerShard(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.Shards = ptr.To(int32(2))
+	p.Spec.AnnotationDiscovery = &monitoringv1.AnnotationDiscovery{
+		Roles:             []monitoringv1.AnnotationDiscoveryRole{monitoringv1.PodAnnotationDiscoveryRole},
+		NamespaceSelector: monitoringv1.NamespaceSelector{Any: true},
+	}
+	p.Spec.ShardingStrategy = &monitoringv1.ShardingStrategy{
+		Mode: ptr.To(monitoringv1.NamespaceShardingStrategyMode),
+		Namespace: &monitoringv1.NamespaceShardingStrategy{
+			Assignments: []monitoringv1.ShardAssignment{
+				{Value: "team-a", Shard: 1},
+			},
+		},
+	}
+
+	sMons := map[string]*monitoringv1.ServiceMonitor{}
+	for _, ns := range []string{"team-a", "team-b", "team-c"} {
+		sMon := defaultServiceMonitor()
+		sMon.Namespace = ns
+		sMons[fmt.Sprintf("%s/%s", ns, sMon.Name)] = sMon
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	confs, namespaces, err := cg.GenerateServerConfigurationPerShard(
+		p,
+		sMons,
+		nil,
+		nil,
+		nil,
+		&assets.StoreBuilder{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, confs, 2)
+
+	expected := [][]string{{}, {}}
+	for _, ns := range []string{"team-b", "team-c"} {
+		shard := hashmod(ns, 2)
+		expected[shard] = append(expected[shard], ns)
+	}
+	expected[1] = append([]string{"team-a"}, expected[1]...)
+	require.Equal(t, expected, namespaces)
+
+	for shard, conf := range confs {
+		golden.Assert(t, string(conf), fmt.Sprintf("PerShardConfigurationShard%d.golden", shard))
+	}
+}
+
+func TestGenerateServerConfigurationPerShardWithScrapeConfigFiles(t *testing.T) {
+	p := defaultPrometheus()
+	p.Spec.Shards = ptr.To(int32(2))
+	p.Spec.ScrapeConfigRendering = ptr.To(monitoringv1.PerResourceScrapeConfigRendering)
+	p.Spec.ShardingStrategy = &monitoringv1.ShardingStrategy{
+		Mode: ptr.To(monitoringv1.LabelShardingStrategyMode),
+		Label: &monitoringv1.LabelShardingStrategy{
+			Assignments: []monitoringv1.ShardAssignment{
+				{Value: "frontend", Shard: 0},
+				{Value: "backend", Shard: 1},
+			},
+		},
+	}
+
+	sMons := map[string]*monitoringv1.ServiceMonitor{}
+	for _, team := range []string{"frontend", "backend"} {
+		sMon := defaultServiceMonitor()
+		sMon.Name = team
+		sMon.Labels[defaultShardingLabelName] = team
+		sMons[fmt.Sprintf("%s/%s", sMon.Namespace, sMon.Name)] = sMon
+	}
+
+	cg := mustNewConfigGenerator(t, p)
+	confs, namespaces, err := cg.GenerateServerConfigurationPerShard(
+		p,
+		sMons,
+		nil,
+		nil,
+		nil,
+		&assets.StoreBuilder{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"default"}, {"default"}}, namespaces)
+
+	for shard, conf := range confs {
+		golden.Assert(t, string(conf), fmt.Sprintf("PerShardConfigurationScrapeConfigFilesShard%d.golden", shard))
+	}
+}
+
+func TestShardNamespacesUpdateStatus(t *testing.T) {
+	sn := NewShardNamespaces()
+	sn.Set("default/test", [][]string{{"team-a"}, {"team-b", "team-c"}})
+
+	status := &monitoringv1.PrometheusStatus{
+		ShardStatuses: []monitoringv1.ShardStatus{
+			{ShardID: "0"},
+			{ShardID: "1"},
+			{ShardID: "2"},
+		},
+	}
+	sn.UpdateStatus("default/test", status)
+
+	require.Equal(t, []string{"team-a"}, status.ShardStatuses[0].Namespaces)
+	require.Equal(t, []string{"team-b", "team-c"}, status.ShardStatuses[1].Namespaces)
+	require.Nil(t, status.ShardStatuses[2].Namespaces)
+
+	sn.Delete("default/test")
+	status.ShardStatuses[0].Namespaces = nil
+	sn.UpdateStatus("default/test", status)
+	require.Nil(t, status.ShardStatuses[0].Namespaces)
+}
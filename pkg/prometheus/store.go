@@ -47,6 +47,10 @@ func addRemoteWritesToStore(ctx context.Context, store *assets.StoreBuilder, nam
 		return err
 	}
 
+	if err := store.AddGoogleIAM(ctx, namespace, rw.GoogleIAM); err != nil {
+		return err
+	}
+
 	if err := store.AddProxyConfig(ctx, namespace, rw.ProxyConfig); err != nil {
 		return err
 	}
@@ -124,3 +128,17 @@ func addProxyConfigToStore(ctx context.Context, pc monitoringv1.ProxyConfig, sto
 
 	return store.AddProxyConfig(ctx, namespace, pc)
 }
+
+// AddOperatorServiceDiscoveryToStore adds the TLS assets used to connect to
+// the operator's HTTP service discovery endpoint to the store.
+func AddOperatorServiceDiscoveryToStore(ctx context.Context, store *assets.StoreBuilder, namespace string, osd *monitoringv1.OperatorServiceDiscovery) error {
+	if osd == nil {
+		return nil
+	}
+
+	if err := store.AddSafeTLSConfig(ctx, namespace, osd.TLSConfig); err != nil {
+		return fmt.Errorf("operator service discovery: %w", err)
+	}
+
+	return nil
+}
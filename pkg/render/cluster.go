@@ -0,0 +1,100 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// LoadFromCluster reads the objects used by the controllers (custom
+// resources, Secrets, ConfigMaps and Namespaces) from the Kubernetes API.
+func LoadFromCluster(ctx context.Context, clients *operator.Clients) ([]runtime.Object, error) {
+	var (
+		kclient = clients.Kubernetes
+		mclient = clients.Monitoring
+		opts    = metav1.ListOptions{}
+		all     = metav1.NamespaceAll
+	)
+
+	lists := []struct {
+		kind   string
+		listFn func() (runtime.Object, error)
+	}{
+		{"Namespace", func() (runtime.Object, error) { return kclient.CoreV1().Namespaces().List(ctx, opts) }},
+		{"Secret", func() (runtime.Object, error) { return kclient.CoreV1().Secrets(all).List(ctx, opts) }},
+		{"ConfigMap", func() (runtime.Object, error) { return kclient.CoreV1().ConfigMaps(all).List(ctx, opts) }},
+		{"Prometheus", func() (runtime.Object, error) { return mclient.MonitoringV1().Prometheuses(all).List(ctx, opts) }},
+		{"PrometheusAgent", func() (runtime.Object, error) {
+			return mclient.MonitoringV1alpha1().PrometheusAgents(all).List(ctx, opts)
+		}},
+		{"Alertmanager", func() (runtime.Object, error) { return mclient.MonitoringV1().Alertmanagers(all).List(ctx, opts) }},
+		{"ThanosRuler", func() (runtime.Object, error) { return mclient.MonitoringV1().ThanosRulers(all).List(ctx, opts) }},
+		{"ServiceMonitor", func() (runtime.Object, error) { return mclient.MonitoringV1().ServiceMonitors(all).List(ctx, opts) }},
+		{"PodMonitor", func() (runtime.Object, error) { return mclient.MonitoringV1().PodMonitors(all).List(ctx, opts) }},
+		{"Probe", func() (runtime.Object, error) { return mclient.MonitoringV1().Probes(all).List(ctx, opts) }},
+		{"PrometheusRule", func() (runtime.Object, error) { return mclient.MonitoringV1().PrometheusRules(all).List(ctx, opts) }},
+		{"ScrapeConfig", func() (runtime.Object, error) { return mclient.MonitoringV1alpha1().ScrapeConfigs(all).List(ctx, opts) }},
+		{"AlertmanagerConfig", func() (runtime.Object, error) {
+			return mclient.MonitoringV1alpha1().AlertmanagerConfigs(all).List(ctx, opts)
+		}},
+		{"MonitoringQuota", func() (runtime.Object, error) {
+			return mclient.MonitoringV1alpha1().MonitoringQuotas(all).List(ctx, opts)
+		}},
+		{"PrometheusOperatorDefaults", func() (runtime.Object, error) {
+			return mclient.MonitoringV1alpha1().PrometheusOperatorDefaults().List(ctx, opts)
+		}},
+	}
+
+	var objects []runtime.Object
+	for _, l := range lists {
+		list, err := l.listFn()
+		if err != nil {
+			// The CRD may not be installed.
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to list %s objects: %w", l.kind, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range items {
+			m, err := meta.Accessor(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			// The objects are loaded into in-memory clients.
+			m.SetResourceVersion("")
+			m.SetManagedFields(nil)
+
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
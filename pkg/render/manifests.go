@@ -0,0 +1,217 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	monitoringscheme "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/scheme"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(monitoringscheme.AddToScheme(scheme))
+}
+
+// LoadManifests reads the Kubernetes objects from the YAML and JSON files
+// found in dir and its sub-directories. The objects of unsupported kinds are
+// skipped.
+func LoadManifests(logger *slog.Logger, dir string) ([]runtime.Object, error) {
+	var objects []runtime.Object
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		objs, err := loadFile(logger, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		objects = append(objects, objs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func loadFile(logger *slog.Logger, path string) ([]runtime.Object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		objects []runtime.Object
+		decoder = utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+		deser   = codecs.UniversalDeserializer()
+	)
+	for {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || bytes.Equal(raw.Raw, []byte("null")) {
+			continue
+		}
+
+		obj, gvk, err := deser.Decode(raw.Raw, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) {
+				logger.Warn("skipping object of unsupported kind", "file", path, "err", err)
+				continue
+			}
+
+			return nil, err
+		}
+
+		if _, ok := obj.(metav1.Object); !ok {
+			logger.Warn("skipping object without metadata", "file", path, "kind", gvk.Kind)
+			continue
+		}
+
+		// Like the API server, merge the stringData field into data.
+		if s, ok := obj.(*corev1.Secret); ok && len(s.StringData) > 0 {
+			if s.Data == nil {
+				s.Data = make(map[string][]byte, len(s.StringData))
+			}
+			for k, v := range s.StringData {
+				s.Data[k] = []byte(v)
+			}
+			s.StringData = nil
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// WriteObjects writes the objects to dir. Each object is written to
+// `<namespace>/<kind>-<name>.yaml`.
+//
+// The data of the Secrets is also written to plain files in the
+// `<namespace>/secret-<name>/` directory. Gzipped data (e.g. the Prometheus
+// configuration) is decompressed.
+func WriteObjects(dir string, objects []runtime.Object) error {
+	for _, obj := range objects {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+
+		prefix := fmt.Sprintf("%s-%s", strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind), m.GetName())
+		nsDir := filepath.Join(dir, m.GetNamespace())
+		if err := os.MkdirAll(nsDir, 0o755); err != nil {
+			return err
+		}
+
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s/%s: %w", m.GetNamespace(), prefix, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(nsDir, prefix+".yaml"), b, 0o644); err != nil {
+			return err
+		}
+
+		s, ok := obj.(*corev1.Secret)
+		if !ok {
+			continue
+		}
+
+		if err := writeSecretData(filepath.Join(nsDir, prefix), s); err != nil {
+			return fmt.Errorf("failed to write the data of %s/%s: %w", m.GetNamespace(), prefix, err)
+		}
+	}
+
+	return nil
+}
+
+func writeSecretData(dir string, s *corev1.Secret) error {
+	if len(s.Data) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for k, v := range s.Data {
+		if name, found := strings.CutSuffix(k, ".gz"); found {
+			r, err := gzip.NewReader(bytes.NewReader(v))
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+
+			v, err = io.ReadAll(r)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+			k = name
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, k), v, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
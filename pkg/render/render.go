@@ -0,0 +1,436 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render generates the Kubernetes resources managed by the operator
+// from a set of manifests without connecting to a Kubernetes cluster.
+//
+// The controllers run against in-memory clients pre-populated with the
+// manifests and the objects created by the controllers are returned.
+package render
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	alertmanagercontroller "github.com/prometheus-operator/prometheus-operator/pkg/alertmanager"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	prometheusagentcontroller "github.com/prometheus-operator/prometheus-operator/pkg/prometheus/agent"
+	prometheuscontroller "github.com/prometheus-operator/prometheus-operator/pkg/prometheus/server"
+	thanoscontroller "github.com/prometheus-operator/prometheus-operator/pkg/thanos"
+)
+
+// DefaultKubernetesVersion is the Kubernetes version assumed when the
+// configuration doesn't specify one.
+var DefaultKubernetesVersion = semver.MustParse("1.33.0")
+
+// Option customizes the rendering.
+type Option func(*options)
+
+type options struct {
+	workloadDefaults string
+}
+
+// WithWorkloadDefaults tells that the controllers merge the default values
+// of the PrometheusOperatorDefaults object with the given name (if it is
+// part of the objects) into the workloads.
+func WithWorkloadDefaults(name string) Option {
+	return func(o *options) {
+		o.workloadDefaults = name
+	}
+}
+
+// syncer is implemented by the workload controllers.
+type syncer interface {
+	StartInformers(context.Context) error
+	Sync(context.Context, string) error
+}
+
+// Render runs the controllers against the given objects and returns the
+// resources (StatefulSets, DaemonSets, Services, Secrets and ConfigMaps)
+// that they generate.
+//
+// The namespaces configuration must be finalized before calling Render.
+func Render(ctx context.Context, logger *slog.Logger, cfg operator.Config, objects []runtime.Object, opts ...Option) ([]runtime.Object, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if cfg.KubernetesVersion.EQ(semver.Version{}) {
+		cfg.KubernetesVersion = DefaultKubernetesVersion
+	}
+	cfg.EventRecorderFactory = operator.NewEventRecorderFactory(false)
+
+	var (
+		kubeObjects       []runtime.Object
+		metadataObjects   []runtime.Object
+		monitoringObjects []runtime.Object
+		inputs            = sets.New[string]()
+		namespaces        = sets.New[string]()
+		declared          = sets.New[string]()
+		workloads         = map[string][]string{}
+	)
+
+	for _, obj := range objects {
+		obj = obj.DeepCopyObject()
+
+		gvk, err := objectKind(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if ns, ok := obj.(*corev1.Namespace); ok {
+			declared.Insert(ns.Name)
+			kubeObjects = append(kubeObjects, obj)
+			continue
+		}
+
+		// The PrometheusOperatorDefaults objects are cluster-scoped.
+		if _, ok := obj.(*monitoringv1alpha1.PrometheusOperatorDefaults); ok {
+			inputs.Insert(objectKey(gvk.Kind, m))
+			monitoringObjects = append(monitoringObjects, obj)
+			continue
+		}
+
+		if m.GetNamespace() == "" {
+			m.SetNamespace(metav1.NamespaceDefault)
+		}
+
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			metadataObjects = append(metadataObjects, partialObjectMetadata(gvk, &o.ObjectMeta))
+		case *corev1.ConfigMap:
+			metadataObjects = append(metadataObjects, partialObjectMetadata(gvk, &o.ObjectMeta))
+		case *monitoringv1.Prometheus, *monitoringv1alpha1.PrometheusAgent, *monitoringv1.Alertmanager, *monitoringv1.ThanosRuler:
+			workloads[gvk.Kind] = append(workloads[gvk.Kind], fmt.Sprintf("%s/%s", m.GetNamespace(), m.GetName()))
+		}
+
+		namespaces.Insert(m.GetNamespace())
+		inputs.Insert(objectKey(gvk.Kind, m))
+
+		if gvk.Group == monitoringv1.SchemeGroupVersion.Group {
+			monitoringObjects = append(monitoringObjects, obj)
+			continue
+		}
+
+		kubeObjects = append(kubeObjects, obj)
+	}
+
+	// The namespaces which aren't part of the manifests are created so that
+	// the namespace selectors work as expected.
+	for _, ns := range sets.List(namespaces.Difference(declared)) {
+		kubeObjects = append(kubeObjects, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   ns,
+				Labels: map[string]string{corev1.LabelMetadataName: ns},
+			},
+		})
+	}
+
+	kclient := kubefake.NewClientset(kubeObjects...)
+	// The controllers check their permissions before managing some
+	// resources.
+	kclient.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		ssar := action.(clienttesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		ssar.Status.Allowed = true
+		return true, ssar, nil
+	})
+
+	mdScheme := metadatafake.NewTestScheme()
+	if err := metav1.AddMetaToScheme(mdScheme); err != nil {
+		return nil, err
+	}
+
+	// The controllers list the Gateway API resources generated for the
+	// exposure of the workloads.
+	dclient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			operator.GatewayAPIGroupVersion.WithResource("httproutes"):         "HTTPRouteList",
+			operator.GatewayAPIGroupVersion.WithResource("backendtlspolicies"): "BackendTLSPolicyList",
+		},
+	)
+
+	mclient := monitoringfake.NewClientset(monitoringObjects...)
+	// The object tracker stores the objects under the resource name guessed
+	// from the kind ("prometheusoperatordefaultses") which differs from the
+	// PrometheusOperatorDefaults resource name.
+	defaultsGVR, _ := meta.UnsafeGuessKindToResource(monitoringv1alpha1.SchemeGroupVersion.WithKind(monitoringv1alpha1.PrometheusOperatorDefaultsKind))
+	mclient.PrependReactor("list", monitoringv1alpha1.PrometheusOperatorDefaultsName, func(clienttesting.Action) (bool, runtime.Object, error) {
+		obj, err := mclient.Tracker().List(defaultsGVR, monitoringv1alpha1.SchemeGroupVersion.WithKind(monitoringv1alpha1.PrometheusOperatorDefaultsKind), "")
+		return true, obj, err
+	})
+	mclient.PrependWatchReactor(monitoringv1alpha1.PrometheusOperatorDefaultsName, func(clienttesting.Action) (bool, watch.Interface, error) {
+		w, err := mclient.Tracker().Watch(defaultsGVR, "")
+		return true, w, err
+	})
+
+	clients := &operator.Clients{
+		Kubernetes: &fakeClientset{Clientset: kclient},
+		Dynamic:    dclient,
+		Metadata:   metadatafake.NewSimpleMetadataClient(mdScheme, metadataObjects...),
+		Monitoring: mclient,
+	}
+
+	controllers, err := newControllers(ctx, logger, cfg, clients, workloads, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, kind := range []string{
+		monitoringv1.PrometheusesKind,
+		monitoringv1alpha1.PrometheusAgentsKind,
+		monitoringv1.AlertmanagersKind,
+		monitoringv1.ThanosRulerKind,
+	} {
+		c, found := controllers[kind]
+		if !found {
+			continue
+		}
+
+		if err := c.StartInformers(ctx); err != nil {
+			return nil, fmt.Errorf("failed to start the %s informers: %w", kind, err)
+		}
+
+		for _, key := range workloads[kind] {
+			if err := c.Sync(ctx, key); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", kind, key, err))
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return collect(ctx, kclient, inputs)
+}
+
+func newControllers(ctx context.Context, logger *slog.Logger, cfg operator.Config, clients *operator.Clients, workloads map[string][]string, o options) (map[string]syncer, error) {
+	var (
+		controllers       = map[string]syncer{}
+		r                 = prometheus.NewRegistry()
+		promOptions       = []prometheuscontroller.ControllerOption{prometheuscontroller.WithScrapeConfig(), prometheuscontroller.WithMonitoringQuota()}
+		promAgentOptions  = []prometheusagentcontroller.ControllerOption{prometheusagentcontroller.WithScrapeConfig(), prometheusagentcontroller.WithMonitoringQuota()}
+		amOptions         []alertmanagercontroller.ControllerOption
+		thanosOptions     = []thanoscontroller.ControllerOption{thanoscontroller.WithMonitoringQuota()}
+		endpointSlice     = cfg.KubernetesVersion.GTE(semver.MustParse("1.21.0"))
+		podTopologyLabels = cfg.KubernetesVersion.GTE(semver.MustParse("1.35.0"))
+	)
+
+	if endpointSlice {
+		promOptions = append(promOptions, prometheuscontroller.WithEndpointSlice())
+		promAgentOptions = append(promAgentOptions, prometheusagentcontroller.WithEndpointSlice())
+	}
+
+	if podTopologyLabels {
+		promOptions = append(promOptions, prometheuscontroller.WithPodTopologyLabels())
+		promAgentOptions = append(promAgentOptions, prometheusagentcontroller.WithPodTopologyLabels())
+	}
+
+	if o.workloadDefaults != "" {
+		promOptions = append(promOptions, prometheuscontroller.WithWorkloadDefaults(o.workloadDefaults))
+		promAgentOptions = append(promAgentOptions, prometheusagentcontroller.WithWorkloadDefaults(o.workloadDefaults))
+		amOptions = append(amOptions, alertmanagercontroller.WithWorkloadDefaults(o.workloadDefaults))
+		thanosOptions = append(thanosOptions, thanoscontroller.WithWorkloadDefaults(o.workloadDefaults))
+	}
+
+	if len(workloads[monitoringv1.PrometheusesKind]) > 0 {
+		c, err := prometheuscontroller.NewForClients(ctx, clients, cfg, logger, r, promOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating prometheus controller failed: %w", err)
+		}
+		controllers[monitoringv1.PrometheusesKind] = c
+	}
+
+	if len(workloads[monitoringv1alpha1.PrometheusAgentsKind]) > 0 {
+		c, err := prometheusagentcontroller.NewForClients(ctx, clients, cfg, logger, r, promAgentOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating prometheus-agent controller failed: %w", err)
+		}
+		controllers[monitoringv1alpha1.PrometheusAgentsKind] = c
+	}
+
+	if len(workloads[monitoringv1.AlertmanagersKind]) > 0 {
+		c, err := alertmanagercontroller.NewForClients(ctx, clients, cfg, logger, r, amOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating alertmanager controller failed: %w", err)
+		}
+		controllers[monitoringv1.AlertmanagersKind] = c
+	}
+
+	if len(workloads[monitoringv1.ThanosRulerKind]) > 0 {
+		c, err := thanoscontroller.NewForClients(ctx, clients, cfg, logger, r, thanosOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating thanos controller failed: %w", err)
+		}
+		controllers[monitoringv1.ThanosRulerKind] = c
+	}
+
+	return controllers, nil
+}
+
+// collect returns the objects created by the controllers, sorted by kind,
+// namespace and name.
+func collect(ctx context.Context, kclient *kubefake.Clientset, inputs sets.Set[string]) ([]runtime.Object, error) {
+	var objects []runtime.Object
+
+	ssets, err := kclient.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range ssets.Items {
+		objects = append(objects, &ssets.Items[i])
+	}
+
+	dsets, err := kclient.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range dsets.Items {
+		objects = append(objects, &dsets.Items[i])
+	}
+
+	svcs, err := kclient.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range svcs.Items {
+		objects = append(objects, &svcs.Items[i])
+	}
+
+	secrets, err := kclient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		objects = append(objects, &secrets.Items[i])
+	}
+
+	cms, err := kclient.CoreV1().ConfigMaps(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range cms.Items {
+		objects = append(objects, &cms.Items[i])
+	}
+
+	var ret []runtime.Object
+	for _, obj := range objects {
+		gvk, err := objectKind(obj)
+		if err != nil {
+			return nil, err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if inputs.Has(objectKey(gvk.Kind, m)) {
+			continue
+		}
+
+		m.SetResourceVersion("")
+		m.SetUID("")
+		m.SetCreationTimestamp(metav1.Time{})
+		m.SetManagedFields(nil)
+
+		ret = append(ret, obj)
+	}
+
+	slices.SortStableFunc(ret, func(a, b runtime.Object) int {
+		ma, _ := meta.Accessor(a)
+		mb, _ := meta.Accessor(b)
+		return cmp.Or(
+			cmp.Compare(a.GetObjectKind().GroupVersionKind().Kind, b.GetObjectKind().GroupVersionKind().Kind),
+			cmp.Compare(ma.GetNamespace(), mb.GetNamespace()),
+			cmp.Compare(ma.GetName(), mb.GetName()),
+		)
+	})
+
+	return ret, nil
+}
+
+// fakeClientset wraps the fake clientset to inform the reflectors that the
+// CoreV1 client doesn't support the watch-list semantics (the informers built
+// on top of the typed CoreV1 client don't know about the fake clientset).
+type fakeClientset struct {
+	*kubefake.Clientset
+}
+
+func (c *fakeClientset) CoreV1() typedcorev1.CoreV1Interface {
+	return &fakeCoreV1{CoreV1Interface: c.Clientset.CoreV1()}
+}
+
+type fakeCoreV1 struct {
+	typedcorev1.CoreV1Interface
+}
+
+func (*fakeCoreV1) IsWatchListSemanticsUnSupported() bool { return true }
+
+func objectKey(kind string, m metav1.Object) string {
+	return fmt.Sprintf("%s/%s/%s", kind, m.GetNamespace(), m.GetName())
+}
+
+func objectKind(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+
+	return gvks[0], nil
+}
+
+// partialObjectMetadata returns the object's metadata as seen by the
+// metadata informers.
+func partialObjectMetadata(gvk schema.GroupVersionKind, m *metav1.ObjectMeta) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind},
+		ObjectMeta: *m.DeepCopy(),
+	}
+}
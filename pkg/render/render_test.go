@@ -0,0 +1,278 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringfake "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+const manifests = `
+apiVersion: monitoring.coreos.com/v1
+kind: Prometheus
+metadata:
+  name: main
+  namespace: monitoring
+spec:
+  serviceMonitorSelector:
+    matchLabels:
+      team: frontend
+  serviceMonitorNamespaceSelector: {}
+  enableRemoteWriteReceiver: true
+  alerting:
+    alertmanagers:
+    - alertmanagerRef:
+        name: main
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: frontend
+  namespace: apps
+  labels:
+    team: frontend
+spec:
+  selector:
+    matchLabels:
+      app: frontend
+  endpoints:
+  - port: web
+---
+apiVersion: monitoring.coreos.com/v1
+kind: Alertmanager
+metadata:
+  name: main
+  namespace: monitoring
+spec:
+  replicas: 2
+  routePrefix: /alertmanager
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ThanosRuler
+metadata:
+  name: main
+  namespace: monitoring
+spec:
+  queryEndpointRefs:
+  - name: main
+  remoteWrite:
+  - prometheusRef:
+      name: main
+  alertmanagerRefs:
+  - name: main
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: unrelated
+  namespace: monitoring
+stringData:
+  foo: bar
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: unknown
+`
+
+func TestRender(t *testing.T) {
+	var (
+		logger = slog.New(slog.DiscardHandler)
+		in     = t.TempDir()
+		out    = t.TempDir()
+	)
+
+	require.NoError(t, os.WriteFile(filepath.Join(in, "manifests.yaml"), []byte(manifests), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(in, "README.md"), []byte("ignored"), 0o600))
+
+	objects, err := LoadManifests(logger, in)
+	require.NoError(t, err)
+	require.Len(t, objects, 5)
+
+	cfg := operator.DefaultConfig("10m", "50Mi")
+	cfg.ReloaderConfig.Image = operator.DefaultPrometheusConfigReloaderImage
+	cfg.AlertmanagerDefaultBaseImage = operator.DefaultAlertmanagerBaseImage
+	cfg.PrometheusDefaultBaseImage = operator.DefaultPrometheusBaseImage
+	cfg.ThanosDefaultBaseImage = operator.DefaultThanosBaseImage
+	require.NoError(t, cfg.Namespaces.Finalize())
+
+	rendered, err := Render(context.Background(), logger, cfg, objects)
+	require.NoError(t, err)
+
+	var got []string
+	for _, obj := range rendered {
+		m, err := meta.Accessor(obj)
+		require.NoError(t, err)
+		require.Empty(t, m.GetResourceVersion())
+
+		got = append(got, obj.GetObjectKind().GroupVersionKind().Kind+"/"+m.GetNamespace()+"/"+m.GetName())
+	}
+
+	for _, obj := range objects {
+		if s, ok := obj.(*corev1.Secret); ok {
+			require.Equal(t, map[string][]byte{"foo": []byte("bar")}, s.Data)
+		}
+	}
+
+	// The input objects aren't returned.
+	require.NotContains(t, got, "Secret/monitoring/unrelated")
+	for _, exp := range []string{
+		"StatefulSet/monitoring/prometheus-main",
+		"StatefulSet/monitoring/alertmanager-main",
+		"Service/monitoring/prometheus-operated",
+		"Service/monitoring/alertmanager-operated",
+		"Secret/monitoring/prometheus-main",
+		"Secret/monitoring/alertmanager-main-generated",
+	} {
+		require.Contains(t, got, exp)
+	}
+
+	require.NoError(t, WriteObjects(out, rendered))
+	require.FileExists(t, filepath.Join(out, "monitoring", "statefulset-prometheus-main.yaml"))
+
+	// The Prometheus configuration is decompressed.
+	b, err := os.ReadFile(filepath.Join(out, "monitoring", "secret-prometheus-main", "prometheus.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "job_name: serviceMonitor/apps/frontend/0")
+
+	// The Alertmanager reference is resolved from the Alertmanager spec.
+	for _, exp := range []string{
+		"scheme: http",
+		"path_prefix: /alertmanager",
+		"__meta_kubernetes_pod_label_alertmanager",
+	} {
+		require.Contains(t, string(b), exp)
+	}
+
+	b, err = os.ReadFile(filepath.Join(out, "monitoring", "secret-thanos-ruler-main-config", "alertmanagers.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "- alertmanager-main-1.alertmanager-operated.monitoring.svc:9093")
+	require.Contains(t, string(b), "path_prefix: /alertmanager")
+
+	// The Prometheus references are resolved from the Prometheus spec.
+	b, err = os.ReadFile(filepath.Join(out, "monitoring", "secret-thanos-ruler-main-config", "query.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "- prometheus-main-0.prometheus-operated.monitoring.svc:9090")
+
+	b, err = os.ReadFile(filepath.Join(out, "monitoring", "secret-thanos-ruler-main-config", "remote-write.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "url: http://prometheus-main-0.prometheus-operated.monitoring.svc:9090/api/v1/write")
+}
+
+func TestLoadFromCluster(t *testing.T) {
+	clients := &operator.Clients{
+		Kubernetes: kubefake.NewClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-main", Namespace: "monitoring"}},
+		),
+		Monitoring: monitoringfake.NewClientset(
+			&monitoringv1.Prometheus{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring", ResourceVersion: "1"}},
+			&monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+		),
+	}
+
+	objects, err := LoadFromCluster(context.Background(), clients)
+	require.NoError(t, err)
+	require.Len(t, objects, 4)
+
+	for _, obj := range objects {
+		m, err := meta.Accessor(obj)
+		require.NoError(t, err)
+		require.Empty(t, m.GetResourceVersion())
+	}
+}
+
+func TestRenderWithWorkloadDefaults(t *testing.T) {
+	const defaults = `
+apiVersion: monitoring.coreos.com/v1alpha1
+kind: PrometheusOperatorDefaults
+metadata:
+  name: cluster
+spec:
+  prometheus:
+    priorityClassName: monitoring
+  alertmanager:
+    priorityClassName: alerting
+`
+	var (
+		logger = slog.New(slog.DiscardHandler)
+		in     = t.TempDir()
+	)
+
+	require.NoError(t, os.WriteFile(filepath.Join(in, "manifests.yaml"), []byte(manifests+"---"+defaults), 0o600))
+
+	objects, err := LoadManifests(logger, in)
+	require.NoError(t, err)
+
+	cfg := operator.DefaultConfig("10m", "50Mi")
+	cfg.ReloaderConfig.Image = operator.DefaultPrometheusConfigReloaderImage
+	cfg.AlertmanagerDefaultBaseImage = operator.DefaultAlertmanagerBaseImage
+	cfg.PrometheusDefaultBaseImage = operator.DefaultPrometheusBaseImage
+	cfg.ThanosDefaultBaseImage = operator.DefaultThanosBaseImage
+	require.NoError(t, cfg.Namespaces.Finalize())
+
+	for _, tc := range []struct {
+		name       string
+		opts       []Option
+		prometheus string
+		am         string
+	}{
+		{
+			name: "without defaults",
+		},
+		{
+			name:       "with defaults",
+			opts:       []Option{WithWorkloadDefaults("cluster")},
+			prometheus: "monitoring",
+			am:         "alerting",
+		},
+		{
+			name: "with unknown defaults",
+			opts: []Option{WithWorkloadDefaults("unknown")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := Render(context.Background(), logger, cfg, objects, tc.opts...)
+			require.NoError(t, err)
+
+			got := map[string]string{}
+			for _, obj := range rendered {
+				sts, ok := obj.(*appsv1.StatefulSet)
+				if !ok {
+					continue
+				}
+
+				got[sts.Name] = sts.Spec.Template.Spec.PriorityClassName
+			}
+
+			require.Equal(t, tc.prometheus, got["prometheus-main"])
+			require.Equal(t, tc.am, got["alertmanager-main"])
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selectionreport implements the HTTP endpoint reporting how the
+// configuration resources (ServiceMonitor, PodMonitor, ...) are selected by
+// the Prometheus and PrometheusAgent objects.
+package selectionreport
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const pathPrefix = "/selection"
+
+// ResourceStatus is the outcome of the selection for a candidate resource.
+type ResourceStatus string
+
+const (
+	// NotSelectedResourceStatus means that the namespace selector or the
+	// label selector doesn't match the resource.
+	NotSelectedResourceStatus ResourceStatus = "NotSelected"
+	// AcceptedResourceStatus means that the resource is selected and valid.
+	AcceptedResourceStatus ResourceStatus = "Accepted"
+	// RejectedResourceStatus means that the resource is selected but the
+	// operator rejected it.
+	RejectedResourceStatus ResourceStatus = "Rejected"
+)
+
+// Selector describes the selectors of the Prometheus or PrometheusAgent
+// object for a kind of configuration resources.
+type Selector struct {
+	Kind string `json:"kind"`
+	// Namespaces are the namespaces matched by the namespace selector.
+	Namespaces []string `json:"namespaces"`
+	// LabelSelector is the label selector applied to the resources of the
+	// matched namespaces.
+	LabelSelector string `json:"labelSelector"`
+}
+
+// Resource describes the selection of a candidate configuration resource.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// NamespaceSelectorMatched is true when the namespace of the resource is
+	// matched by the namespace selector.
+	NamespaceSelectorMatched bool `json:"namespaceSelectorMatched"`
+	// LabelSelectorMatched is true when the labels of the resource are
+	// matched by the label selector.
+	LabelSelectorMatched bool `json:"labelSelectorMatched"`
+
+	Status ResourceStatus `json:"status"`
+	// Reason and Message explain why the resource has been rejected.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// The following fields are only set for accepted resources.
+
+	// ScrapeClass is the name of the scrape class applied to the resource
+	// (if any).
+	ScrapeClass string `json:"scrapeClass,omitempty"`
+	// Shards are the shards handling the resource.
+	Shards []int32 `json:"shards,omitempty"`
+	// JobNames are the names of the scrape jobs generated for the resource.
+	JobNames []string `json:"jobNames,omitempty"`
+}
+
+// Defaults describes the default values merged into the spec of the
+// Prometheus or PrometheusAgent object.
+type Defaults struct {
+	// Name is the name of the PrometheusOperatorDefaults object.
+	Name string `json:"name"`
+	// Generation is the generation of the PrometheusOperatorDefaults object.
+	Generation int64 `json:"generation"`
+	// Fields are the spec fields which have been set from the defaults.
+	Fields []string `json:"fields"`
+}
+
+// Report describes the selection of the configuration resources by a
+// Prometheus or PrometheusAgent object.
+type Report struct {
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Generation int64     `json:"generation"`
+	Shards     int32     `json:"shards"`
+	Timestamp  time.Time `json:"timestamp"`
+
+	// Defaults is nil when no PrometheusOperatorDefaults object applies.
+	Defaults *Defaults `json:"defaults,omitempty"`
+
+	// Selectors describe how the candidate resources are selected.
+	Selectors []Selector `json:"selectors"`
+
+	// Resources are all the candidate resources, whether they've been
+	// selected or not.
+	Resources []Resource `json:"resources"`
+}
+
+// NewReport returns an empty report for the given Prometheus or
+// PrometheusAgent object.
+func NewReport(kind, namespace, name string, generation int64, shards int32) *Report {
+	return &Report{
+		Kind:       kind,
+		Namespace:  namespace,
+		Name:       name,
+		Generation: generation,
+		Shards:     shards,
+		Selectors:  []Selector{},
+		Resources:  []Resource{},
+	}
+}
+
+// AddSelector appends the selectors of a kind of resources to the report.
+func (r *Report) AddSelector(sel Selector) {
+	if r == nil {
+		return
+	}
+
+	r.Selectors = append(r.Selectors, sel)
+}
+
+// Add appends a candidate resource to the report.
+func (r *Report) Add(res Resource) {
+	if r == nil {
+		return
+	}
+
+	r.Resources = append(r.Resources, res)
+}
+
+// SetDefaults records the default values merged into the spec of the
+// Prometheus or PrometheusAgent object.
+func (r *Report) SetDefaults(d *Defaults) {
+	if r == nil {
+		return
+	}
+
+	r.Defaults = d
+}
+
+// Key returns the key identifying the Prometheus or PrometheusAgent object
+// in the registry.
+func Key(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), namespace, name)
+}
+
+// Path returns the URL path serving the report of the Prometheus or
+// PrometheusAgent object.
+func Path(kind, namespace, name string) string {
+	return pathPrefix + "/" + Key(kind, namespace, name)
+}
+
+// Registry holds the latest selection report of each Prometheus and
+// PrometheusAgent object.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	logger *slog.Logger
+
+	mtx     sync.RWMutex
+	reports map[string][]byte
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry(logger *slog.Logger) *Registry {
+	return &Registry{
+		logger:  logger,
+		reports: map[string][]byte{},
+	}
+}
+
+// Set replaces the report of the Prometheus or PrometheusAgent object.
+func (r *Registry) Set(report *Report) {
+	if r == nil || report == nil {
+		return
+	}
+
+	slices.SortFunc(report.Selectors, func(a, b Selector) int {
+		return cmp.Compare(a.Kind, b.Kind)
+	})
+	slices.SortFunc(report.Resources, func(a, b Resource) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	report.Timestamp = time.Now().UTC()
+
+	key := Key(report.Kind, report.Namespace, report.Name)
+	b, err := json.Marshal(report)
+	if err != nil {
+		r.logger.Warn("failed to marshal the selection report", "key", key, "err", err)
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.reports[key] = b
+}
+
+// Delete removes the report of the Prometheus or PrometheusAgent object.
+func (r *Registry) Delete(kind, namespace, name string) {
+	if r == nil {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	delete(r.reports, Key(kind, namespace, name))
+}
+
+// Register adds the selection report handler to the mux.
+func (r *Registry) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+pathPrefix+"/{kind}/{namespace}/{name}", r.serveReport)
+}
+
+func (r *Registry) serveReport(w http.ResponseWriter, req *http.Request) {
+	key := Key(req.PathValue("kind"), req.PathValue("namespace"), req.PathValue("name"))
+
+	r.mtx.RLock()
+	b, found := r.reports[key]
+	r.mtx.RUnlock()
+
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		r.logger.Warn("failed to write the selection report", "key", key, "err", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectionreport
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	require.Equal(t, "/selection/prometheusagent/monitoring/main", Path("PrometheusAgent", "monitoring", "main"))
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(slog.New(slog.DiscardHandler))
+	mux := http.NewServeMux()
+	r.Register(mux)
+
+	report := NewReport("Prometheus", "monitoring", "main", 3, 2)
+	report.AddSelector(Selector{
+		Kind:          "ServiceMonitor",
+		Namespaces:    []string{"default"},
+		LabelSelector: "group=a",
+	})
+	report.AddSelector(Selector{
+		Kind:       "PodMonitor",
+		Namespaces: []string{"default"},
+	})
+	report.Add(Resource{
+		Kind:                     "ServiceMonitor",
+		Namespace:                "default",
+		Name:                     "b",
+		NamespaceSelectorMatched: true,
+		Status:                   NotSelectedResourceStatus,
+	})
+	report.Add(Resource{
+		Kind:      "ServiceMonitor",
+		Namespace: "default",
+		Name:      "a",
+		Status:    AcceptedResourceStatus,
+		Shards:    []int32{0},
+		JobNames:  []string{"serviceMonitor/default/a/0"},
+	})
+	report.Add(Resource{
+		Kind:      "PodMonitor",
+		Namespace: "default",
+		Name:      "c",
+		Status:    RejectedResourceStatus,
+		Reason:    "InvalidConfiguration",
+		Message:   "invalid",
+	})
+	report.SetDefaults(&Defaults{
+		Name:       "cluster",
+		Generation: 2,
+		Fields:     []string{"resources", "tolerations"},
+	})
+	r.Set(report)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path("Prometheus", "monitoring", "main"), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var got Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Equal(t, "Prometheus", got.Kind)
+	require.Equal(t, int64(3), got.Generation)
+	require.Equal(t, int32(2), got.Shards)
+	require.False(t, got.Timestamp.IsZero())
+	require.Equal(t, &Defaults{Name: "cluster", Generation: 2, Fields: []string{"resources", "tolerations"}}, got.Defaults)
+
+	// The selectors are sorted by kind.
+	require.Len(t, got.Selectors, 2)
+	require.Equal(t, "PodMonitor", got.Selectors[0].Kind)
+	require.Equal(t, "group=a", got.Selectors[1].LabelSelector)
+
+	// The resources are sorted by kind, namespace and name.
+	require.Len(t, got.Resources, 3)
+	require.Equal(t, "c", got.Resources[0].Name)
+	require.Equal(t, RejectedResourceStatus, got.Resources[0].Status)
+	require.Equal(t, "a", got.Resources[1].Name)
+	require.Equal(t, []string{"serviceMonitor/default/a/0"}, got.Resources[1].JobNames)
+	require.Equal(t, "b", got.Resources[2].Name)
+	require.Equal(t, NotSelectedResourceStatus, got.Resources[2].Status)
+	require.True(t, got.Resources[2].NamespaceSelectorMatched)
+	require.False(t, got.Resources[2].LabelSelectorMatched)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path("PrometheusAgent", "monitoring", "main"), nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	r.Delete("Prometheus", "monitoring", "main")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path("Prometheus", "monitoring", "main"), nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
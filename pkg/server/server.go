@@ -68,24 +68,31 @@ func DefaultConfig(listenAddress string, enableTLS bool) Config {
 }
 
 func RegisterFlags(fs *flag.FlagSet, c *Config) {
-	fs.StringVar(&c.ListenAddress, "web.listen-address", c.ListenAddress, "Address on which to expose metrics and web interface.")
+	RegisterFlagsWithPrefix(fs, c, "web", "web server", "Address on which to expose metrics and web interface.")
+}
+
+// RegisterFlagsWithPrefix registers the flags configuring a server. All flag
+// names start with "<prefix>." and name identifies the server in the flag
+// descriptions.
+func RegisterFlagsWithPrefix(fs *flag.FlagSet, c *Config, prefix, name, listenAddressUsage string) {
+	fs.StringVar(&c.ListenAddress, prefix+".listen-address", c.ListenAddress, listenAddressUsage)
 
-	fs.BoolVar(&c.EnableHTTP2, "web.enable-http2", c.EnableHTTP2, "Enable HTTP2 connections.")
+	fs.BoolVar(&c.EnableHTTP2, prefix+".enable-http2", c.EnableHTTP2, "Enable HTTP2 connections.")
 
-	fs.BoolVar(&c.TLSConfig.Enabled, "web.enable-tls", c.TLSConfig.Enabled, "Enable TLS for the web server.")
-	fs.StringVar(&c.TLSConfig.CertFile, "web.cert-file", c.TLSConfig.CertFile, "Certificate file to be used for the web server.")
-	fs.StringVar(&c.TLSConfig.KeyFile, "web.key-file", c.TLSConfig.KeyFile, "Private key matching the cert file to be used for the web server.")
-	fs.StringVar(&c.TLSConfig.ClientCAFile, "web.client-ca-file", c.TLSConfig.ClientCAFile, "Client CA certificate file to be used for the web server.")
+	fs.BoolVar(&c.TLSConfig.Enabled, prefix+".enable-tls", c.TLSConfig.Enabled, "Enable TLS for the "+name+".")
+	fs.StringVar(&c.TLSConfig.CertFile, prefix+".cert-file", c.TLSConfig.CertFile, "Certificate file to be used for the "+name+".")
+	fs.StringVar(&c.TLSConfig.KeyFile, prefix+".key-file", c.TLSConfig.KeyFile, "Private key matching the cert file to be used for the "+name+".")
+	fs.StringVar(&c.TLSConfig.ClientCAFile, prefix+".client-ca-file", c.TLSConfig.ClientCAFile, "Client CA certificate file to be used for the "+name+".")
 
-	fs.DurationVar(&c.TLSConfig.ReloadInterval, "web.tls-reload-interval", c.TLSConfig.ReloadInterval, "The interval at which to watch for TLS certificate changes, by default set to 1 minute. (default 1m0s).")
+	fs.DurationVar(&c.TLSConfig.ReloadInterval, prefix+".tls-reload-interval", c.TLSConfig.ReloadInterval, "The interval at which to watch for TLS certificate changes, by default set to 1 minute. (default 1m0s).")
 
-	fs.StringVar(&c.TLSConfig.MinVersion, "web.tls-min-version", c.TLSConfig.MinVersion,
+	fs.StringVar(&c.TLSConfig.MinVersion, prefix+".tls-min-version", c.TLSConfig.MinVersion,
 		"Minimum TLS version supported. Value must match version names from https://golang.org/pkg/crypto/tls/#pkg-constants.")
-	fs.Var(&c.TLSConfig.CipherSuites, "web.tls-cipher-suites", "Comma-separated list of cipher suites for the server."+
+	fs.Var(&c.TLSConfig.CipherSuites, prefix+".tls-cipher-suites", "Comma-separated list of cipher suites for the server."+
 		" Values are from tls package constants (https://golang.org/pkg/crypto/tls/#pkg-constants)."+
 		"If omitted, the default Go cipher suites will be used. "+
 		"Note that TLS 1.3 ciphersuites are not configurable.")
-	fs.Var(&c.TLSConfig.Curves, "web.tls-curves", "Comma-separated list of TLS curves for the server. Supported values: "+strings.Join(slices.Sorted(maps.Keys(supportedCurves)), ", ")+".")
+	fs.Var(&c.TLSConfig.Curves, prefix+".tls-curves", "Comma-separated list of TLS curves for the server. Supported values: "+strings.Join(slices.Sorted(maps.Keys(supportedCurves)), ", ")+".")
 }
 
 var supportedCurves = map[string]tls.CurveID{}
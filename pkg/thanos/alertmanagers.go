@@ -0,0 +1,406 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+	"k8s.io/utils/ptr"
+
+	alertmanagerpkg "github.com/prometheus-operator/prometheus-operator/pkg/alertmanager"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// generateAlertmanagersConfig returns the Thanos Ruler configuration of the
+// Alertmanagers referenced by alertmanagerRefs and of the
+// alertmanagerEndpoints. It returns nil if the configuration isn't generated
+// by the operator.
+//
+// See https://thanos.io/tip/components/rule.md/#alertmanager
+func (o *Operator) generateAlertmanagersConfig(ctx context.Context, store *assets.StoreBuilder, tr *monitoringv1.ThanosRuler) ([]byte, error) {
+	if tr.Spec.AlertManagersConfig != nil || (len(tr.Spec.AlertmanagerRefs) == 0 && len(tr.Spec.AlertmanagerEndpoints) == 0) {
+		return nil, nil
+	}
+
+	amConfigs := make([]yaml.MapSlice, 0, len(tr.Spec.AlertmanagerRefs)+len(tr.Spec.AlertmanagerEndpoints))
+	for i, ref := range tr.Spec.AlertmanagerRefs {
+		ns := ptr.Deref(ref.Namespace, tr.Namespace)
+
+		am, err := operator.GetObjectFromKey[*monitoringv1.Alertmanager](o.amInfs, ns+"/"+ref.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if am == nil {
+			return nil, fmt.Errorf("alertmanagerRefs[%d]: alertmanager %s/%s not found", i, ns, ref.Name)
+		}
+
+		ep, err := alertmanagerpkg.EndpointsForReference(monitoringv1.AlertmanagerEndpoints{}, am, tr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("alertmanagerRefs[%d]: %w", i, err)
+		}
+
+		amConfig := yaml.MapSlice{}
+		if ep.TLSConfig != nil {
+			if err := store.AddTLSConfig(ctx, tr.Namespace, ep.TLSConfig); err != nil {
+				return nil, fmt.Errorf("alertmanagerRefs[%d]: %w", i, err)
+			}
+
+			amConfig = append(amConfig, yaml.MapItem{
+				Key: "http_config",
+				Value: yaml.MapSlice{
+					{
+						Key: "tls_config",
+						Value: yaml.MapSlice{
+							{Key: "ca_file", Value: path.Join(tlsAssetsDir, store.ForNamespace(tr.Namespace).TLSAsset(ep.TLSConfig.CA))},
+							{Key: "server_name", Value: *ep.TLSConfig.ServerName},
+						},
+					},
+				},
+			})
+		}
+
+		amConfig = append(amConfig,
+			yaml.MapItem{Key: "static_configs", Value: alertmanagerpkg.PodAddresses(am)},
+			yaml.MapItem{Key: "scheme", Value: ep.Scheme.String()},
+			yaml.MapItem{Key: "path_prefix", Value: *ep.PathPrefix},
+			yaml.MapItem{Key: "api_version", Value: strings.ToLower(string(*ep.APIVersion))},
+		)
+
+		amConfigs = append(amConfigs, amConfig)
+	}
+
+	for i, ep := range tr.Spec.AlertmanagerEndpoints {
+		amConfig, err := generateAlertmanagerEndpointsConfig(ctx, store, tr.Namespace, ep)
+		if err != nil {
+			return nil, fmt.Errorf("alertmanagerEndpoints[%d]: %w", i, err)
+		}
+
+		amConfigs = append(amConfigs, amConfig)
+	}
+
+	return yaml.Marshal(yaml.MapSlice{{Key: "alertmanagers", Value: amConfigs}})
+}
+
+// generateAlertmanagerEndpointsConfig returns the Thanos Ruler configuration
+// of Alertmanager endpoints which aren't managed by the operator.
+func generateAlertmanagerEndpointsConfig(ctx context.Context, store *assets.StoreBuilder, namespace string, ep monitoringv1.ThanosRulerAlertmanagerEndpoints) (yaml.MapSlice, error) {
+	var httpConfig yaml.MapSlice
+
+	if ep.BasicAuth != nil {
+		if err := store.AddBasicAuth(ctx, namespace, ep.BasicAuth); err != nil {
+			return nil, err
+		}
+
+		username, err := store.ForNamespace(namespace).GetSecretKey(ep.BasicAuth.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		password, err := store.ForNamespace(namespace).GetSecretKey(ep.BasicAuth.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		httpConfig = append(httpConfig, yaml.MapItem{
+			Key: "basic_auth",
+			Value: yaml.MapSlice{
+				{Key: "username", Value: string(username)},
+				{Key: "password", Value: string(password)},
+			},
+		})
+	}
+
+	if ep.TLSConfig != nil {
+		if err := store.AddSafeTLSConfig(ctx, namespace, ep.TLSConfig); err != nil {
+			return nil, err
+		}
+
+		httpConfig = append(httpConfig, yaml.MapItem{
+			Key:   "tls_config",
+			Value: generateTLSConfig(store.ForNamespace(namespace), ep.TLSConfig),
+		})
+	}
+
+	amConfig := yaml.MapSlice{}
+	if len(httpConfig) > 0 {
+		amConfig = append(amConfig, yaml.MapItem{Key: "http_config", Value: httpConfig})
+	}
+
+	amConfig = append(amConfig, yaml.MapItem{Key: "static_configs", Value: alertmanagerAddresses(ep)})
+
+	if ep.Scheme != nil {
+		amConfig = append(amConfig, yaml.MapItem{Key: "scheme", Value: ep.Scheme.String()})
+	}
+
+	if ep.PathPrefix != nil {
+		amConfig = append(amConfig, yaml.MapItem{Key: "path_prefix", Value: *ep.PathPrefix})
+	}
+
+	if ep.Timeout != nil {
+		amConfig = append(amConfig, yaml.MapItem{Key: "timeout", Value: string(*ep.Timeout)})
+	}
+
+	if ep.APIVersion != nil {
+		amConfig = append(amConfig, yaml.MapItem{Key: "api_version", Value: strings.ToLower(string(*ep.APIVersion))})
+	}
+
+	return amConfig, nil
+}
+
+// generateTLSConfig returns the TLS configuration of an HTTP client of Thanos
+// Ruler. The minimum and maximum TLS versions aren't supported by Thanos.
+func generateTLSConfig(store assets.StoreGetter, tlsConfig *monitoringv1.SafeTLSConfig) yaml.MapSlice {
+	cfg := yaml.MapSlice{}
+
+	if tlsConfig.CA.Secret != nil || tlsConfig.CA.ConfigMap != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "ca_file", Value: path.Join(tlsAssetsDir, store.TLSAsset(tlsConfig.CA))})
+	}
+
+	if tlsConfig.Cert.Secret != nil || tlsConfig.Cert.ConfigMap != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "cert_file", Value: path.Join(tlsAssetsDir, store.TLSAsset(tlsConfig.Cert))})
+	}
+
+	if tlsConfig.KeySecret != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "key_file", Value: path.Join(tlsAssetsDir, store.TLSAsset(tlsConfig.KeySecret))})
+	}
+
+	if ptr.Deref(tlsConfig.ServerName, "") != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "server_name", Value: *tlsConfig.ServerName})
+	}
+
+	if tlsConfig.InsecureSkipVerify != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "insecure_skip_verify", Value: *tlsConfig.InsecureSkipVerify})
+	}
+
+	return cfg
+}
+
+// alertmanagerAddresses returns the addresses of the static_configs field.
+// The DNS names are resolved by Thanos Ruler: the SRV records with the
+// "dnssrv+" prefix and the A/AAAA records with the "dns+" prefix.
+func alertmanagerAddresses(ep monitoringv1.ThanosRulerAlertmanagerEndpoints) []string {
+	var addrs []string
+
+	for _, sc := range ep.StaticConfigs {
+		addrs = append(addrs, sc.Targets...)
+	}
+
+	for _, dc := range ep.DNSSDConfigs {
+		for _, name := range dc.Names {
+			if ptr.Deref(dc.Type, monitoringv1.AlertmanagerDNSRecordTypeSRV) == monitoringv1.AlertmanagerDNSRecordTypeSRV {
+				addrs = append(addrs, "dnssrv+"+name)
+				continue
+			}
+
+			addrs = append(addrs, "dns+"+net.JoinHostPort(name, strconv.Itoa(int(ptr.Deref(dc.Port, 0)))))
+		}
+	}
+
+	return addrs
+}
+
+// dnsSchemePrefixes are the prefixes of the Alertmanager addresses which are
+// resolved with DNS lookups by Thanos Ruler.
+var dnsSchemePrefixes = []string{"dns+", "dnssrv+", "dnssrvnoa+"}
+
+// validateAlertmanagers checks the Alertmanager endpoints defined by the
+// alertmanagersConfig, alertmanagerEndpoints or alertmanagersUrl fields
+// (whichever applies).
+//
+// The alertmanagersConfig secret is passed as-is to Thanos Ruler: an invalid
+// configuration is only logged since the operator may not know all the fields
+// supported by the Thanos version.
+func validateAlertmanagers(ctx context.Context, logger *slog.Logger, store *assets.StoreBuilder, tr *monitoringv1.ThanosRuler) error {
+	switch {
+	case tr.Spec.AlertManagersConfig != nil:
+		amConfig, err := store.GetSecretKey(ctx, tr.Namespace, *tr.Spec.AlertManagersConfig)
+		if err != nil {
+			return fmt.Errorf("alertmanagersConfig: %w", err)
+		}
+
+		if err := validateAlertmanagersConfig([]byte(amConfig)); err != nil {
+			logger.Warn("the alertmanagersConfig secret may be invalid", "err", err)
+		}
+
+		return nil
+	case len(tr.Spec.AlertmanagerRefs) > 0 || len(tr.Spec.AlertmanagerEndpoints) > 0:
+		for i, ep := range tr.Spec.AlertmanagerEndpoints {
+			if err := validateAlertmanagerEndpoints(ep); err != nil {
+				return fmt.Errorf("alertmanagerEndpoints[%d]: %w", i, err)
+			}
+		}
+
+		return nil
+	}
+
+	for i, u := range tr.Spec.AlertManagersURL {
+		if err := validateAlertmanagerURL(u); err != nil {
+			return fmt.Errorf("alertmanagersUrl[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateAlertmanagerEndpoints checks the fields of the Alertmanager
+// endpoints which can't be translated to the Thanos Ruler configuration.
+func validateAlertmanagerEndpoints(ep monitoringv1.ThanosRulerAlertmanagerEndpoints) error {
+	if len(ep.StaticConfigs) == 0 && len(ep.DNSSDConfigs) == 0 {
+		return errors.New("one of \"staticConfigs\" or \"dnsSDConfigs\" must be defined")
+	}
+
+	for i, sc := range ep.StaticConfigs {
+		for j, addr := range sc.Targets {
+			if err := validateAlertmanagerAddress(addr); err != nil {
+				return fmt.Errorf("staticConfigs[%d]: targets[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	for i, dc := range ep.DNSSDConfigs {
+		if dc.RefreshInterval != nil {
+			return fmt.Errorf("dnsSDConfigs[%d]: refreshInterval isn't supported by Thanos Ruler", i)
+		}
+
+		if dc.Type != nil && *dc.Type != monitoringv1.AlertmanagerDNSRecordTypeSRV && dc.Port == nil {
+			return fmt.Errorf("dnsSDConfigs[%d]: port is required for %s records", i, *dc.Type)
+		}
+	}
+
+	if ep.TLSConfig != nil && (ep.TLSConfig.MinVersion != nil || ep.TLSConfig.MaxVersion != nil) {
+		return errors.New("tlsConfig: minVersion and maxVersion aren't supported by Thanos Ruler")
+	}
+
+	return nil
+}
+
+// thanosAlertmanagersConfig mirrors the Alertmanager configuration file of
+// Thanos Ruler.
+type thanosAlertmanagersConfig struct {
+	Alertmanagers []struct {
+		HTTPConfig    yaml.MapSlice `yaml:"http_config,omitempty"`
+		StaticConfigs []string      `yaml:"static_configs,omitempty"`
+		FileSDConfigs []any         `yaml:"file_sd_configs,omitempty"`
+		Scheme        string        `yaml:"scheme,omitempty"`
+		PathPrefix    string        `yaml:"path_prefix,omitempty"`
+		Timeout       string        `yaml:"timeout,omitempty"`
+		APIVersion    string        `yaml:"api_version,omitempty"`
+	} `yaml:"alertmanagers"`
+}
+
+// validateAlertmanagersConfig checks the Alertmanager configuration file of
+// Thanos Ruler.
+//
+// See https://thanos.io/tip/components/rule.md/#alertmanager
+func validateAlertmanagersConfig(b []byte) error {
+	var cfg thanosAlertmanagersConfig
+	if err := yaml.UnmarshalStrict(b, &cfg); err != nil {
+		return fmt.Errorf("failed to parse the configuration: %w", err)
+	}
+
+	if len(cfg.Alertmanagers) == 0 {
+		return errors.New("no alertmanagers defined")
+	}
+
+	for i, am := range cfg.Alertmanagers {
+		if len(am.StaticConfigs) == 0 && len(am.FileSDConfigs) == 0 {
+			return fmt.Errorf("alertmanagers[%d]: one of \"static_configs\" or \"file_sd_configs\" must be defined", i)
+		}
+
+		for j, addr := range am.StaticConfigs {
+			if err := validateAlertmanagerAddress(addr); err != nil {
+				return fmt.Errorf("alertmanagers[%d]: static_configs[%d]: %w", i, j, err)
+			}
+		}
+
+		if am.Scheme != "" && am.Scheme != "http" && am.Scheme != "https" {
+			return fmt.Errorf("alertmanagers[%d]: invalid scheme %q", i, am.Scheme)
+		}
+
+		if am.APIVersion != "" && am.APIVersion != "v1" && am.APIVersion != "v2" {
+			return fmt.Errorf("alertmanagers[%d]: invalid api_version %q", i, am.APIVersion)
+		}
+
+		if am.Timeout != "" {
+			if _, err := model.ParseDuration(am.Timeout); err != nil {
+				return fmt.Errorf("alertmanagers[%d]: invalid timeout: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAlertmanagerAddress checks an address of the static_configs field
+// (e.g. "alertmanager:9093" or "dnssrv+_web._tcp.alertmanager").
+func validateAlertmanagerAddress(addr string) error {
+	for _, prefix := range dnsSchemePrefixes {
+		if after, found := strings.CutPrefix(addr, prefix); found {
+			addr = after
+			break
+		}
+	}
+
+	u, err := url.Parse("http://" + addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	if u.Host == "" || u.Host != addr {
+		return fmt.Errorf("invalid address %q: expected <host>[:<port>]", addr)
+	}
+
+	return nil
+}
+
+// validateAlertmanagerURL checks a value of the alertmanagersUrl field (e.g.
+// "http://alertmanager:9093" or "dnssrv+https://_web._tcp.alertmanager").
+func validateAlertmanagerURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", s, err)
+	}
+
+	scheme := u.Scheme
+	for _, prefix := range dnsSchemePrefixes {
+		if after, found := strings.CutPrefix(scheme, prefix); found {
+			scheme = after
+			break
+		}
+	}
+
+	if scheme != "http" && scheme != "https" {
+		return fmt.Errorf("invalid URL %q: unsupported scheme %q", s, u.Scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL %q: missing host", s)
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@
 package thanos
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
@@ -28,6 +29,7 @@ import (
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -39,6 +41,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	monitoringv1ac "github.com/prometheus-operator/prometheus-operator/pkg/client/applyconfiguration/monitoring/v1"
 	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
@@ -55,6 +58,8 @@ const (
 	applicationNameLabelValue = "thanos-ruler"
 	controllerName            = "thanos-controller"
 	rwConfigFile              = "remote-write.yaml"
+	alertmanagersConfigFile   = "alertmanagers.yaml"
+	queryConfigFile           = "query.yaml"
 
 	noSelectedResourcesMessage = "No PrometheusRule have been selected."
 )
@@ -73,12 +78,21 @@ type Operator struct {
 	accessor *operator.Accessor
 
 	controllerID string
+	driftPolicy  operator.DriftPolicy
 	repairPolicy operator.RepairPolicy
 
 	thanosRulerInfs *informers.ForResource
 	cmapInfs        *informers.ForResource
 	ruleInfs        *informers.ForResource
 	ssetInfs        *informers.ForResource
+	quotaInfs       *informers.ForResource
+	amInfs          *informers.ForResource
+	promInfs        *informers.ForResource
+
+	// defaultsInfs is nil when no PrometheusOperatorDefaults object is
+	// configured.
+	defaultsInfs     *informers.ForResource
+	workloadDefaults string
 
 	rr *operator.ResourceReconciler
 
@@ -89,6 +103,8 @@ type Operator struct {
 	reconciliations     *operator.ReconciliationTracker
 	canReadStorageClass bool
 
+	monitoringQuotaSupported bool
+
 	newEventRecorder operator.NewEventRecorderFunc
 
 	config Config
@@ -119,6 +135,23 @@ func WithStorageClassValidation() ControllerOption {
 	}
 }
 
+// WithMonitoringQuota tells that the controller enforces the MonitoringQuota
+// objects.
+func WithMonitoringQuota() ControllerOption {
+	return func(o *Operator) {
+		o.monitoringQuotaSupported = true
+	}
+}
+
+// WithWorkloadDefaults tells that the controller merges the default values
+// from the PrometheusOperatorDefaults object with the given name into the
+// ThanosRuler objects.
+func WithWorkloadDefaults(name string) ControllerOption {
+	return func(o *Operator) {
+		o.workloadDefaults = name
+	}
+}
+
 // WithConfigResourceStatus tells that the controller can manage the status of
 // configuration resources.
 func WithConfigResourceStatus() ControllerOption {
@@ -129,27 +162,26 @@ func WithConfigResourceStatus() ControllerOption {
 
 // New creates a new controller.
 func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
-	logger = logger.With("component", controllerName)
-
-	client, err := kubernetes.NewForConfig(restConfig)
+	clients, err := operator.NewClients(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("instantiating kubernetes client failed: %w", err)
+		return nil, err
 	}
 
-	dclient, err := dynamic.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating dynamic client failed: %w", err)
-	}
+	return NewForClients(ctx, clients, c, logger, r, options...)
+}
 
-	mdClient, err := metadata.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating metadata client failed: %w", err)
-	}
+// NewForClients creates a new controller which interacts with the
+// Kubernetes API using the given clients.
+func NewForClients(ctx context.Context, clients *operator.Clients, c operator.Config, logger *slog.Logger, r prometheus.Registerer, options ...ControllerOption) (*Operator, error) {
+	logger = logger.With("component", controllerName)
 
-	mclient, err := monitoringclient.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("instantiating monitoring client failed: %w", err)
-	}
+	var (
+		client   = clients.Kubernetes
+		dclient  = clients.Dynamic
+		mdClient = clients.Metadata
+		mclient  = clients.Monitoring
+		err      error
+	)
 
 	// All the metrics exposed by the controller get the controller="thanos" label.
 	r = prometheus.WrapRegistererWith(prometheus.Labels{"controller": "thanos"}, r)
@@ -165,6 +197,7 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		newEventRecorder: c.EventRecorderFactory(client, controllerName),
 		reconciliations:  &operator.ReconciliationTracker{},
 		controllerID:     c.ControllerID,
+		driftPolicy:      c.DriftPolicy,
 		repairPolicy:     c.RepairPolicy,
 		config: Config{
 			ReloaderConfig:         c.ReloaderConfig,
@@ -245,6 +278,61 @@ func New(ctx context.Context, restConfig *rest.Config, c operator.Config, logger
 		return nil, fmt.Errorf("error creating prometheusrule informers: %w", err)
 	}
 
+	if o.monitoringQuotaSupported {
+		o.quotaInfs, err = informers.NewInformersForResource(
+			informers.NewMonitoringInformerFactories(
+				c.Namespaces.AllowList,
+				c.Namespaces.DenyList,
+				mclient,
+				resyncPeriod,
+				nil,
+			),
+			monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.MonitoringQuotaName),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating monitoringquota informers: %w", err)
+		}
+	}
+
+	if o.workloadDefaults != "" {
+		o.defaultsInfs, err = operator.NewWorkloadDefaultsInformers(mclient, o.workloadDefaults, resyncPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("error creating prometheusoperatordefaults informers: %w", err)
+		}
+	}
+
+	// The Alertmanager objects are watched to resolve the references from
+	// the alerting configuration.
+	o.amInfs, err = informers.NewInformersForResource(
+		informers.NewMonitoringInformerFactories(
+			c.Namespaces.AlertmanagerAllowList,
+			c.Namespaces.DenyList,
+			mclient,
+			resyncPeriod,
+			nil,
+		),
+		monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.AlertmanagerName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating alertmanager informers: %w", err)
+	}
+
+	o.promInfs, err = informers.NewInformersForResource(
+		informers.NewMonitoringInformerFactories(
+			c.Namespaces.PrometheusAllowList,
+			c.Namespaces.DenyList,
+			mclient,
+			resyncPeriod,
+			func(options *metav1.ListOptions) {
+				options.LabelSelector = c.PromSelector.String()
+			},
+		),
+		monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.PrometheusName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating prometheus informers: %w", err)
+	}
+
 	o.ssetInfs, err = informers.NewInformersForResource(
 		informers.NewKubeInformerFactories(
 			c.Namespaces.ThanosRulerAllowList,
@@ -310,7 +398,16 @@ func (o *Operator) waitForCacheSync(ctx context.Context) error {
 		{"ConfigMap", o.cmapInfs},
 		{"PrometheusRule", o.ruleInfs},
 		{"StatefulSet", o.ssetInfs},
+		{"MonitoringQuota", o.quotaInfs},
+		{"PrometheusOperatorDefaults", o.defaultsInfs},
+		{"Alertmanager", o.amInfs},
+		{"Prometheus", o.promInfs},
 	} {
+		// Skipping informers that were not started.
+		if infs.informersForResource == nil {
+			continue
+		}
+
 		for _, inf := range infs.informersForResource.GetInformers() {
 			if !operator.WaitForNamedCacheSync(ctx, "thanos", o.logger.With("informer", infs.name), inf.Informer()) {
 				return fmt.Errorf("failed to sync cache for %s informer", infs.name)
@@ -362,6 +459,56 @@ func (o *Operator) addHandlers() {
 		),
 	))
 
+	if o.quotaInfs != nil {
+		o.quotaInfs.AddEventHandler(operator.NewEventHandler(
+			o.logger,
+			o.accessor,
+			o.metrics,
+			monitoringv1alpha1.MonitoringQuotasKind,
+			o.enqueueForRulesNamespace,
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
+	if o.defaultsInfs != nil {
+		o.defaultsInfs.AddEventHandler(operator.NewEventHandler(
+			o.logger,
+			o.accessor,
+			o.metrics,
+			monitoringv1alpha1.PrometheusOperatorDefaultsKind,
+			o.enqueueAll,
+			operator.WithFilter(operator.GenerationChanged),
+		))
+	}
+
+	o.amInfs.AddEventHandler(operator.NewEventHandler(
+		o.logger,
+		o.accessor,
+		o.metrics,
+		monitoringv1.AlertmanagersKind,
+		o.enqueueForAlertmanagerNamespace,
+		operator.WithFilter(
+			operator.AnyFilter(
+				operator.GenerationChanged,
+				operator.LabelsChanged,
+			),
+		),
+	))
+
+	o.promInfs.AddEventHandler(operator.NewEventHandler(
+		o.logger,
+		o.accessor,
+		o.metrics,
+		monitoringv1.PrometheusesKind,
+		o.enqueueForPrometheusNamespace,
+		operator.WithFilter(
+			operator.AnyFilter(
+				operator.GenerationChanged,
+				operator.LabelsChanged,
+			),
+		),
+	))
+
 	// The controller needs to watch the namespaces in which the rules live
 	// because a label change on a namespace may trigger a configuration
 	// change.
@@ -372,20 +519,35 @@ func (o *Operator) addHandlers() {
 	})
 }
 
-// Run the controller.
-func (o *Operator) Run(ctx context.Context) error {
-	go o.rr.Run(ctx)
-	defer o.rr.Stop()
-
+// StartInformers starts the informers of the controller and waits for
+// their caches to be synced.
+func (o *Operator) StartInformers(ctx context.Context) error {
 	go o.thanosRulerInfs.Start(ctx.Done())
 	go o.cmapInfs.Start(ctx.Done())
 	go o.ruleInfs.Start(ctx.Done())
+	if o.monitoringQuotaSupported {
+		go o.quotaInfs.Start(ctx.Done())
+	}
+	if o.defaultsInfs != nil {
+		go o.defaultsInfs.Start(ctx.Done())
+	}
+	go o.amInfs.Start(ctx.Done())
+	go o.promInfs.Start(ctx.Done())
 	go o.nsRuleInf.Run(ctx.Done())
 	if o.nsRuleInf != o.nsThanosRulerInf {
 		go o.nsThanosRulerInf.Run(ctx.Done())
 	}
 	go o.ssetInfs.Start(ctx.Done())
-	if err := o.waitForCacheSync(ctx); err != nil {
+
+	return o.waitForCacheSync(ctx)
+}
+
+// Run the controller.
+func (o *Operator) Run(ctx context.Context) error {
+	go o.rr.Run(ctx)
+	defer o.rr.Stop()
+
+	if err := o.StartInformers(ctx); err != nil {
 		return err
 	}
 
@@ -520,6 +682,25 @@ func (o *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		return closure, nil
 	}
 
+	ctx = operator.WithDriftReporting(ctx, logger, o.metrics, o.newEventRecorder(tr), o.driftPolicy)
+
+	if err := k8s.ValidateOverlays(tr.Spec.Overlays); err != nil {
+		return closure, err
+	}
+
+	defaults, err := operator.GetWorkloadDefaults(o.defaultsInfs, o.workloadDefaults)
+	if err != nil {
+		return closure, err
+	}
+	defaulted := operator.ApplyThanosRulerDefaults(tr, defaults)
+	if len(defaulted) > 0 {
+		logger.Debug("default values applied", "fields", defaulted)
+	}
+	defaultedValues, err := operator.DefaultedWorkloadValues(tr.Spec.Resources, tr.Spec.SecurityContext, tr.Spec.Tolerations, tr.Spec.PriorityClassName, defaulted)
+	if err != nil {
+		return closure, err
+	}
+
 	o.recordDeprecatedFields(key, logger, tr)
 
 	if err := operator.CheckStorageClass(ctx, o.canReadStorageClass, o.kclient, tr.Spec.Storage); err != nil {
@@ -548,11 +729,25 @@ func (o *Operator) sync(ctx context.Context, key string) (func(context.Context)
 
 	assetStore := assets.NewStoreBuilder(o.kclient.CoreV1(), o.kclient.CoreV1())
 
-	if err := o.createOrUpdateRulerConfigSecret(ctx, assetStore, tr); err != nil {
+	if err := validateAlertmanagers(ctx, logger, assetStore, tr); err != nil {
+		return closure, fmt.Errorf("invalid alertmanagers configuration: %w", err)
+	}
+
+	amConfig, err := o.generateAlertmanagersConfig(ctx, assetStore, tr)
+	if err != nil {
+		return closure, fmt.Errorf("failed to generate the alertmanagers configuration: %w", err)
+	}
+
+	queryConfig, err := o.generateQueryConfig(ctx, assetStore, tr)
+	if err != nil {
+		return closure, fmt.Errorf("failed to generate the query configuration: %w", err)
+	}
+
+	if err := o.createOrUpdateRulerConfigSecret(ctx, assetStore, tr, amConfig, queryConfig); err != nil {
 		return closure, fmt.Errorf("failed to synchronize ruler config secret: %w", err)
 	}
 
-	tlsAssets, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), o.kclient, newTLSAssetSecret(tr, o.config))
+	tlsAssets, err := operator.ReconcileShardedSecret(ctx, assetStore.TLSAssets(), o.kclient, newTLSAssetSecret(tr, o.config), tr.Spec.Overlays)
 	if err != nil {
 		return closure, fmt.Errorf("failed to reconcile the TLS secrets: %w", err)
 	}
@@ -568,7 +763,9 @@ func (o *Operator) sync(ctx context.Context, key string) (func(context.Context)
 			return closure, err
 		}
 	} else {
-		// Create governing service if it doesn't exist.
+		// Create governing service if it doesn't exist. The service is shared
+		// by all the ThanosRuler resources of the namespace, the overlays
+		// don't apply to it.
 		if _, err = k8s.CreateOrUpdateService(ctx, svcClient, makeStatefulSetService(tr, o.config)); err != nil {
 			return closure, fmt.Errorf("synchronizing governing service failed: %w", err)
 		}
@@ -590,7 +787,7 @@ func (o *Operator) sync(ctx context.Context, key string) (func(context.Context)
 		return closure, nil
 	}
 
-	newSSetInputHash, err := createSSetInputHash(*tr, o.config, tlsAssets, ruleConfigMapNames, existingStatefulSet.Spec)
+	newSSetInputHash, err := createSSetInputHash(*tr, defaultedValues, o.config, tlsAssets, ruleConfigMapNames, amConfig, queryConfig, existingStatefulSet.Spec)
 	if err != nil {
 		return closure, err
 	}
@@ -602,6 +799,33 @@ func (o *Operator) sync(ctx context.Context, key string) (func(context.Context)
 
 	operator.SanitizeSTS(sset)
 
+	if err := k8s.ApplyOverlays(tr.Spec.Overlays, sset); err != nil {
+		return closure, err
+	}
+
+	var pdbs []*policyv1.PodDisruptionBudget
+	if tr.Spec.PodDisruptionBudget != nil {
+		pdbs = append(pdbs, operator.MakePodDisruptionBudget(*tr.Spec.PodDisruptionBudget, sset))
+	}
+
+	if err := operator.ReconcilePodDisruptionBudgets(ctx, o.kclient, tr, labels.SelectorFromSet(sset.Spec.Selector.MatchLabels), pdbs); err != nil {
+		return closure, err
+	}
+
+	if err := o.reconcileNetworkPolicy(ctx, logger, tr, sset); err != nil {
+		return closure, err
+	}
+
+	if err := o.reconcileExposure(ctx, logger, tr, sset); err != nil {
+		return closure, fmt.Errorf("failed to reconcile the exposure: %w", err)
+	}
+
+	if operator.UpdateStorageResizedCondition(ctx, o.kclient, o.reconciliations, key, []*appsv1.StatefulSet{sset}, tr.Generation) {
+		logger.Debug("persistent volume claims resize in progress")
+		o.rr.EnqueueForReconciliationAfter(tr, operator.StorageResizeRequeueInterval)
+		o.rr.EnqueueForStatus(tr)
+	}
+
 	ssetClient := o.kclient.AppsV1().StatefulSets(tr.Namespace)
 	if shouldCreate {
 		logger.Debug("creating statefulset")
@@ -618,6 +842,20 @@ func (o *Operator) sync(ctx context.Context, key string) (func(context.Context)
 	}
 
 	logger.Debug("new hash differs from the existing value", "new", newSSetInputHash, "existing", existingStatefulSet.Annotations[operator.InputHashAnnotationKey])
+	expand, err := operator.ExpandPersistentVolumeClaims(ctx, o.kclient, o.canReadStorageClass, existingStatefulSet, sset)
+	if err != nil {
+		return closure, err
+	}
+
+	if expand {
+		o.metrics.StsDeleteCreateCounter().Inc()
+		logger.Info("recreating StatefulSet because the volume claim templates have been expanded")
+		if err := k8s.OrphanDeleteStatefulSet(ctx, ssetClient, sset.Name); err != nil {
+			return closure, fmt.Errorf("failed to delete statefulset: %w", err)
+		}
+		return closure, nil
+	}
+
 	if err = k8s.ForceUpdateStatefulSet(ctx, ssetClient, sset, func(reason string) {
 		o.metrics.StsDeleteCreateCounter().Inc()
 		logger.Info("recreating StatefulSet because the update operation wasn't possible", "reason", reason)
@@ -735,7 +973,11 @@ func (o *Operator) UpdateStatus(ctx context.Context, key string) error {
 	}
 
 	reconciledCondition := o.reconciliations.GetCondition(key, tr.Generation)
-	tr.Status.Conditions = operator.UpdateConditions(tr.Status.Conditions, availableCondition, reconciledCondition)
+	conditions := []monitoringv1.Condition{availableCondition, reconciledCondition}
+	if cond := o.reconciliations.GetStorageResizedCondition(key); cond != nil {
+		conditions = append(conditions, *cond)
+	}
+	tr.Status.Conditions = operator.UpdateConditions(tr.Status.Conditions, conditions...)
 	tr.Status.Paused = tr.Spec.Paused
 
 	if _, err = o.mclient.MonitoringV1().ThanosRulers(tr.Namespace).ApplyStatus(ctx, applyConfigurationFromThanosRuler(tr), metav1.ApplyOptions{FieldManager: k8s.PrometheusOperatorFieldManager, Force: true}); err != nil {
@@ -745,7 +987,7 @@ func (o *Operator) UpdateStatus(ctx context.Context, key string) error {
 	return nil
 }
 
-func createSSetInputHash(tr monitoringv1.ThanosRuler, c Config, tlsAssets *operator.ShardedSecret, ruleConfigMapNames []string, ss appsv1.StatefulSetSpec) (string, error) {
+func createSSetInputHash(tr monitoringv1.ThanosRuler, defaultedValues string, c Config, tlsAssets *operator.ShardedSecret, ruleConfigMapNames []string, amConfig, queryConfig []byte, ss appsv1.StatefulSetSpec) (string, error) {
 
 	// The controller should ignore any changes to RevisionHistoryLimit field because
 	// it may be modified by external actors.
@@ -753,21 +995,31 @@ func createSSetInputHash(tr monitoringv1.ThanosRuler, c Config, tlsAssets *opera
 	ss.RevisionHistoryLimit = nil
 
 	hash, err := hashstructure.Hash(struct {
-		ThanosRulerLabels      map[string]string
-		ThanosRulerAnnotations map[string]string
-		ThanosRulerGeneration  int64
-		Config                 Config
-		StatefulSetSpec        appsv1.StatefulSetSpec
-		RuleConfigMaps         []string `hash:"set"`
-		ShardedSecret          *operator.ShardedSecret
+		ThanosRulerLabels          map[string]string
+		ThanosRulerAnnotations     map[string]string
+		ThanosRulerGeneration      int64
+		ThanosRulerOverlays        []monitoringv1.Overlay
+		ThanosRulerDefaultedValues string
+		Config                     Config
+		StatefulSetSpec            appsv1.StatefulSetSpec
+		RuleConfigMaps             []string `hash:"set"`
+		ShardedSecret              *operator.ShardedSecret
+		// Thanos Ruler doesn't reload the Alertmanagers and query
+		// configurations.
+		AlertmanagersConfig []byte
+		QueryConfig         []byte
 	}{
-		ThanosRulerLabels:      tr.Labels,
-		ThanosRulerAnnotations: tr.Annotations,
-		ThanosRulerGeneration:  tr.Generation,
-		Config:                 c,
-		StatefulSetSpec:        ss,
-		RuleConfigMaps:         ruleConfigMapNames,
-		ShardedSecret:          tlsAssets,
+		ThanosRulerLabels:          tr.Labels,
+		ThanosRulerAnnotations:     tr.Annotations,
+		ThanosRulerGeneration:      tr.Generation,
+		ThanosRulerOverlays:        tr.Spec.Overlays,
+		ThanosRulerDefaultedValues: defaultedValues,
+		Config:                     c,
+		StatefulSetSpec:            ss,
+		RuleConfigMaps:             ruleConfigMapNames,
+		ShardedSecret:              tlsAssets,
+		AlertmanagersConfig:        amConfig,
+		QueryConfig:                queryConfig,
 	},
 		nil,
 	)
@@ -837,6 +1089,62 @@ func (o *Operator) enqueueForNamespace(store cache.Store, nsName string) {
 	}
 }
 
+// enqueueAll enqueues all the ThanosRuler objects.
+func (o *Operator) enqueueAll(string) {
+	err := o.thanosRulerInfs.ListAll(labels.Everything(), func(obj any) {
+		o.rr.EnqueueForReconciliation(obj.(*monitoringv1.ThanosRuler))
+	})
+	if err != nil {
+		o.logger.Error("listing all ThanosRuler instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+// enqueueForAlertmanagerNamespace enqueues the ThanosRuler objects referencing
+// Alertmanager objects in the given namespace.
+func (o *Operator) enqueueForAlertmanagerNamespace(nsName string) {
+	err := o.thanosRulerInfs.ListAll(labels.Everything(), func(obj any) {
+		tr := obj.(*monitoringv1.ThanosRuler)
+		for _, ref := range tr.Spec.AlertmanagerRefs {
+			if ptr.Deref(ref.Namespace, tr.Namespace) == nsName {
+				o.rr.EnqueueForReconciliation(tr)
+				return
+			}
+		}
+	})
+	if err != nil {
+		o.logger.Error("listing all ThanosRuler instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+// enqueueForPrometheusNamespace enqueues the ThanosRuler objects referencing
+// Prometheus objects in the given namespace.
+func (o *Operator) enqueueForPrometheusNamespace(nsName string) {
+	err := o.thanosRulerInfs.ListAll(labels.Everything(), func(obj any) {
+		tr := obj.(*monitoringv1.ThanosRuler)
+
+		refs := make([]*monitoringv1.PrometheusReference, 0, len(tr.Spec.QueryEndpointRefs)+len(tr.Spec.RemoteWrite))
+		for i := range tr.Spec.QueryEndpointRefs {
+			refs = append(refs, &tr.Spec.QueryEndpointRefs[i])
+		}
+		for _, rw := range tr.Spec.RemoteWrite {
+			refs = append(refs, rw.PrometheusRef)
+		}
+
+		if prompkg.ReferencesNamespace(tr.Namespace, nsName, refs...) {
+			o.rr.EnqueueForReconciliation(tr)
+		}
+	})
+	if err != nil {
+		o.logger.Error("listing all ThanosRuler instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
 func (o *Operator) createOrUpdateWebConfigSecret(ctx context.Context, tr *monitoringv1.ThanosRuler) error {
 	var fields monitoringv1.WebConfigFileFields
 	if tr.Spec.Web != nil {
@@ -860,7 +1168,7 @@ func (o *Operator) createOrUpdateWebConfigSecret(ctx context.Context, tr *monito
 		operator.WithManagingOwner(tr),
 	)
 
-	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, o.kclient.CoreV1().Secrets(tr.Namespace), s); err != nil {
+	if err := webConfig.CreateOrUpdateWebConfigSecret(ctx, o.kclient.CoreV1().Secrets(tr.Namespace), s, tr.Spec.Overlays); err != nil {
 		return fmt.Errorf("failed to update the web config secret: %w", err)
 	}
 
@@ -907,6 +1215,96 @@ func newTLSAssetSecret(tr *monitoringv1.ThanosRuler, config Config) *corev1.Secr
 	return s
 }
 
+// reconcileNetworkPolicy creates or updates the NetworkPolicy of the
+// ThanosRuler pods when it is defined and deletes it otherwise.
+func (o *Operator) reconcileNetworkPolicy(ctx context.Context, logger *slog.Logger, tr *monitoringv1.ThanosRuler, sset *appsv1.StatefulSet) error {
+	name := prefixedName(tr.Name)
+	if tr.Spec.NetworkPolicy == nil {
+		return operator.ReconcileNetworkPolicy(ctx, o.kclient, tr.Namespace, name, nil)
+	}
+
+	np := operator.NewNetworkPolicyBuilder(tr.Namespace, makeSelectorLabels(tr.Name))
+	np.AddPodSpec(&sset.Spec.Template.Spec)
+	np.AddAddresses(tr.Spec.QueryEndpoints...)
+	np.AddAddresses(tr.Spec.AlertManagersURL...)
+
+	for _, ref := range tr.Spec.QueryEndpointRefs {
+		np.AddNamespaces(ptr.Deref(ref.Namespace, tr.Namespace))
+	}
+
+	for _, ref := range tr.Spec.AlertmanagerRefs {
+		np.AddNamespaces(ptr.Deref(ref.Namespace, tr.Namespace))
+	}
+
+	for _, ep := range tr.Spec.AlertmanagerEndpoints {
+		for _, sc := range ep.StaticConfigs {
+			np.AddAddresses(sc.Targets...)
+		}
+
+		for _, sd := range ep.DNSSDConfigs {
+			np.AddAddresses(sd.Names...)
+		}
+	}
+
+	for _, rw := range tr.Spec.RemoteWrite {
+		if rw.PrometheusRef != nil {
+			np.AddNamespaces(ptr.Deref(rw.PrometheusRef.Namespace, tr.Namespace))
+			continue
+		}
+
+		np.AddAddresses(string(rw.URL))
+	}
+
+	return operator.ReconcileNetworkPolicy(
+		ctx,
+		o.kclient,
+		tr.Namespace,
+		name,
+		np.Build(
+			ctx,
+			logger,
+			o.kclient,
+			*tr.Spec.NetworkPolicy,
+			operator.WithName(name),
+			operator.WithNamespace(tr.Namespace),
+			operator.WithLabels(o.config.Labels),
+			operator.WithAnnotations(o.config.Annotations),
+			operator.WithManagingOwner(tr),
+		),
+	)
+}
+
+// reconcileExposure creates or updates the objects exposing the ThanosRuler
+// web server when the exposure is defined and deletes them otherwise.
+func (o *Operator) reconcileExposure(ctx context.Context, logger *slog.Logger, tr *monitoringv1.ThanosRuler, sset *appsv1.StatefulSet) error {
+	var exposure *operator.Exposure
+	if tr.Spec.Exposure != nil {
+		exposure = operator.NewExposure(*tr.Spec.Exposure, operator.ExposureConfig{
+			Name:        prefixedName(tr.Name),
+			PodSelector: makeSelectorLabels(tr.Name),
+			PortName:    cmp.Or(tr.Spec.PortName, defaultPortName),
+			Port:        10902,
+			RoutePrefix: tr.Spec.RoutePrefix,
+			WebTLS:      tr.Spec.Web != nil && tr.Spec.Web.TLSConfig != nil,
+			Overlays:    tr.Spec.Overlays,
+		})
+		exposure.AddStatefulSet(sset)
+	}
+
+	return operator.ReconcileExposure(
+		ctx,
+		logger,
+		o.kclient,
+		o.dclient,
+		tr,
+		prefixedName(tr.Name),
+		exposure,
+		operator.WithLabels(o.config.Labels),
+		operator.WithAnnotations(o.config.Annotations),
+		operator.WithManagingOwner(tr),
+	)
+}
+
 // In cases where an existing selector label is modified, or a new one is added, new sts cannot match existing pods.
 // We should try to avoid removing such immutable fields whenever possible since doing
 // so forces us to enter the 'recreate cycle' and can potentially lead to downtime.
@@ -930,7 +1328,7 @@ func labelSelectorForStatefulSets() string {
 	)
 }
 
-func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *assets.StoreBuilder, tr *monitoringv1.ThanosRuler) error {
+func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *assets.StoreBuilder, tr *monitoringv1.ThanosRuler, amConfig, queryConfig []byte) error {
 	sClient := o.kclient.CoreV1().Secrets(tr.GetNamespace())
 
 	s := &corev1.Secret{
@@ -971,7 +1369,15 @@ func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *a
 		}
 	}
 
-	for i, rw := range tr.Spec.RemoteWrite {
+	remoteWrite, err := prompkg.ResolveRemoteWriteReferences(o.promInfs, tr.Namespace, tr.Spec.RemoteWrite)
+	if err != nil {
+		return err
+	}
+
+	for i := range remoteWrite {
+		// The remote-write configuration may be updated in-place below.
+		rw := remoteWrite[i].DeepCopy()
+
 		// Thanos does not support azureAD.workloadIdentity in any version
 		if rw.AzureAD != nil && rw.AzureAD.WorkloadIdentity != nil {
 			reset := resetFieldFn("none")
@@ -1008,6 +1414,7 @@ func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *a
 		if version.LT(semver.MustParse("0.37.0")) {
 			reset := resetFieldFn("0.37.0")
 			reset("messageVersion", &rw.MessageVersion) // requires >= 2.54.0
+			reset("googleIAM", &rw.GoogleIAM)           // requires >= 2.55.0
 		}
 
 		// Thanos v0.36.0 is equivalent to Prometheus v2.52.2.
@@ -1056,6 +1463,8 @@ func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *a
 		}
 
 		// Thanos v0.24.0 is equivalent to Prometheus v2.32.0.
+
+		remoteWrite[i] = *rw
 	}
 
 	cg, err := prompkg.NewConfigGenerator(o.logger, nil, prompkg.WithoutVersionCheck())
@@ -1063,14 +1472,14 @@ func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *a
 		return err
 	}
 
-	err = cg.AddRemoteWriteToStore(ctx, store, tr.Namespace, tr.Spec.RemoteWrite)
+	err = cg.AddRemoteWriteToStore(ctx, store, tr.Namespace, remoteWrite)
 	if err != nil {
 		return err
 	}
 
 	rwConfig, err := yaml.Marshal(
 		yaml.MapSlice{
-			cg.GenerateRemoteWriteConfig(tr.Spec.RemoteWrite, store.ForNamespace(tr.Namespace)),
+			cg.GenerateRemoteWriteConfig(remoteWrite, store.ForNamespace(tr.Namespace)),
 		},
 	)
 	if err != nil {
@@ -1078,6 +1487,18 @@ func (o *Operator) createOrUpdateRulerConfigSecret(ctx context.Context, store *a
 	}
 	s.Data[rwConfigFile] = rwConfig
 
+	if amConfig != nil {
+		s.Data[alertmanagersConfigFile] = amConfig
+	}
+
+	if queryConfig != nil {
+		s.Data[queryConfigFile] = queryConfig
+	}
+
+	if err = k8s.ApplyOverlays(tr.Spec.Overlays, s); err != nil {
+		return err
+	}
+
 	if err = k8s.CreateOrUpdateSecret(ctx, sClient, s); err != nil {
 		return err
 	}
@@ -15,14 +15,17 @@
 package thanos
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/golden"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/ptr"
 
@@ -67,6 +70,28 @@ func TestCreateOrUpdateRulerConfigSecret(t *testing.T) {
 			},
 			golden: "v0.24.0_remote_write_config.golden",
 		},
+		{
+			name:    "googleIAM with default version",
+			version: operator.DefaultThanosVersion,
+			remoteWrite: []monitoringv1.RemoteWriteSpec{
+				{
+					URL:       "http://example.com",
+					GoogleIAM: &monitoringv1.GoogleIAM{},
+				},
+			},
+			golden: "google_iam_remote_write_config.golden",
+		},
+		{
+			name:    "googleIAM with v0.36.0",
+			version: "v0.36.0",
+			remoteWrite: []monitoringv1.RemoteWriteSpec{
+				{
+					URL:       "http://example.com",
+					GoogleIAM: &monitoringv1.GoogleIAM{},
+				},
+			},
+			golden: "v0.36.0_google_iam_remote_write_config.golden",
+		},
 		{
 			name:    "sigv4 external_id not support in any thanos version",
 			version: operator.DefaultThanosVersion,
@@ -125,7 +150,7 @@ func TestCreateOrUpdateRulerConfigSecret(t *testing.T) {
 				},
 			)
 
-			err := o.createOrUpdateRulerConfigSecret(context.Background(), sb, tr)
+			err := o.createOrUpdateRulerConfigSecret(context.Background(), sb, tr, nil, nil)
 			if tc.expectErr {
 				require.Error(t, err)
 				return
@@ -138,3 +163,249 @@ func TestCreateOrUpdateRulerConfigSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAlertmanagers(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		urls          []string
+		config        string
+		refs          []monitoringv1.AlertmanagerReference
+		endpoints     []monitoringv1.ThanosRulerAlertmanagerEndpoints
+		expectErr     bool
+		expectWarning bool
+	}{
+		{
+			name: "no alertmanagers",
+		},
+		{
+			name: "valid urls",
+			urls: []string{
+				"http://alertmanager:9093",
+				"https://alertmanager.example.com/prefix",
+				"dns+http://alertmanager:9093",
+				"dnssrv+https://_web._tcp.alertmanager",
+			},
+		},
+		{
+			name:      "url without scheme",
+			urls:      []string{"alertmanager:9093"},
+			expectErr: true,
+		},
+		{
+			name:      "url with invalid scheme",
+			urls:      []string{"dns+ftp://alertmanager:9093"},
+			expectErr: true,
+		},
+		{
+			name:      "url without host",
+			urls:      []string{"http:///api"},
+			expectErr: true,
+		},
+		{
+			name: "valid endpoints",
+			endpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{
+				{
+					StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{{Targets: []string{"alertmanager:9093"}}},
+					DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{
+						{Names: []string{"_web._tcp.alertmanager"}},
+						{Names: []string{"alertmanager"}, Type: ptr.To(monitoringv1.AlertmanagerDNSRecordTypeA), Port: ptr.To(int32(9093))},
+					},
+				},
+			},
+		},
+		{
+			name:      "endpoints without discovery",
+			endpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{{}},
+			expectErr: true,
+		},
+		{
+			name: "endpoints with url in staticConfigs",
+			endpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{
+				{StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{{Targets: []string{"http://alertmanager:9093"}}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "endpoints with A record without port",
+			endpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{
+				{DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{{Names: []string{"alertmanager"}, Type: ptr.To(monitoringv1.AlertmanagerDNSRecordTypeA)}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "endpoints with refresh interval",
+			endpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{
+				{DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{{Names: []string{"alertmanager"}, RefreshInterval: ptr.To(monitoringv1.Duration("30s"))}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "endpoints with minimum TLS version",
+			endpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{
+				{
+					StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{{Targets: []string{"alertmanager:9093"}}},
+					TLSConfig:     &monitoringv1.SafeTLSConfig{MinVersion: ptr.To(monitoringv1.TLSVersion12)},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid urls ignored with alertmanagerRefs",
+			urls: []string{"alertmanager:9093"},
+			refs: []monitoringv1.AlertmanagerReference{{Name: "main"}},
+		},
+		{
+			name: "valid config",
+			config: `alertmanagers:
+- static_configs:
+  - alertmanager-0:9093
+  - dnssrv+_web._tcp.alertmanager
+  scheme: https
+  path_prefix: /prefix
+  timeout: 10s
+  api_version: v2
+- file_sd_configs:
+  - files:
+    - /etc/alertmanagers.json
+`,
+		},
+		{
+			name:   "invalid urls ignored with config",
+			urls:   []string{"alertmanager:9093"},
+			config: "alertmanagers:\n- static_configs: [alertmanager:9093]\n",
+		},
+		{
+			name:          "config with unknown field",
+			config:        "alertmanagers:\n- static_config: [alertmanager:9093]\n",
+			expectWarning: true,
+		},
+		{
+			name:          "config without alertmanagers",
+			config:        "alertmanagers: []\n",
+			expectWarning: true,
+		},
+		{
+			name:          "config without discovery",
+			config:        "alertmanagers:\n- scheme: http\n",
+			expectWarning: true,
+		},
+		{
+			name:          "config with url in static_configs",
+			config:        "alertmanagers:\n- static_configs: [http://alertmanager:9093]\n",
+			expectWarning: true,
+		},
+		{
+			name:          "config with invalid scheme",
+			config:        "alertmanagers:\n- static_configs: [alertmanager:9093]\n  scheme: ftp\n",
+			expectWarning: true,
+		},
+		{
+			name:          "config with invalid api version",
+			config:        "alertmanagers:\n- static_configs: [alertmanager:9093]\n  api_version: v3\n",
+			expectWarning: true,
+		},
+		{
+			name:          "config with invalid timeout",
+			config:        "alertmanagers:\n- static_configs: [alertmanager:9093]\n  timeout: 10\n",
+			expectWarning: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &monitoringv1.ThanosRuler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.ThanosRulerSpec{
+					AlertManagersURL:      tc.urls,
+					AlertmanagerRefs:      tc.refs,
+					AlertmanagerEndpoints: tc.endpoints,
+				},
+			}
+
+			var objects []k8sruntime.Object
+			if tc.config != "" {
+				tr.Spec.AlertManagersConfig = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "alertmanagers"},
+					Key:                  "config.yaml",
+				}
+				objects = append(objects, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "alertmanagers",
+						Namespace: "test",
+					},
+					Data: map[string][]byte{
+						"config.yaml": []byte(tc.config),
+					},
+				})
+			}
+
+			var (
+				c   = fake.NewClientset(objects...)
+				buf bytes.Buffer
+			)
+			err := validateAlertmanagers(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)), assets.NewStoreBuilder(c.CoreV1(), c.CoreV1()), tr)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectWarning, strings.Contains(buf.String(), "level=WARN"), buf.String())
+		})
+	}
+}
+
+func TestGenerateAlertmanagerEndpointsConfig(t *testing.T) {
+	tr := &monitoringv1.ThanosRuler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "test",
+		},
+		Spec: monitoringv1.ThanosRulerSpec{
+			AlertmanagerEndpoints: []monitoringv1.ThanosRulerAlertmanagerEndpoints{
+				{
+					StaticConfigs: []monitoringv1.AlertmanagerStaticConfig{
+						{Targets: []string{"alertmanager-0.example.com:9093", "alertmanager-1.example.com:9093"}},
+					},
+					Scheme:     ptr.To(monitoringv1.SchemeHTTPS),
+					PathPrefix: ptr.To("/alertmanager"),
+					APIVersion: ptr.To(monitoringv1.AlertmanagerAPIVersion2),
+					Timeout:    ptr.To(monitoringv1.Duration("10s")),
+					BasicAuth: &monitoringv1.BasicAuth{
+						Username: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "auth"}, Key: "username"},
+						Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "auth"}, Key: "password"},
+					},
+					TLSConfig: &monitoringv1.SafeTLSConfig{
+						ServerName:         ptr.To("alertmanager.example.com"),
+						InsecureSkipVerify: ptr.To(true),
+					},
+				},
+				{
+					DNSSDConfigs: []monitoringv1.AlertmanagerDNSSDConfig{
+						{Names: []string{"_web._tcp.alertmanager.example.com"}},
+						{Names: []string{"alertmanager.example.com"}, Type: ptr.To(monitoringv1.AlertmanagerDNSRecordTypeA), Port: ptr.To(int32(9093))},
+					},
+				},
+			},
+		},
+	}
+
+	sb := assets.NewTestStoreBuilder(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "auth",
+				Namespace: "test",
+			},
+			Data: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("pass"),
+			},
+		},
+	)
+
+	o := &Operator{logger: slog.Default()}
+	amConfig, err := o.generateAlertmanagersConfig(context.Background(), sb, tr)
+	require.NoError(t, err)
+	golden.Assert(t, string(amConfig), "alertmanager_endpoints_config.golden")
+}
@@ -0,0 +1,81 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+)
+
+// generateQueryConfig returns the Thanos Ruler configuration of the query
+// endpoints referenced by queryEndpointRefs. It returns nil if the
+// configuration isn't generated by the operator.
+//
+// See https://thanos.io/tip/components/rule.md/#query-api
+func (o *Operator) generateQueryConfig(ctx context.Context, store *assets.StoreBuilder, tr *monitoringv1.ThanosRuler) ([]byte, error) {
+	if tr.Spec.QueryConfig != nil || len(tr.Spec.QueryEndpointRefs) == 0 {
+		return nil, nil
+	}
+
+	queryConfigs := make([]yaml.MapSlice, 0, len(tr.Spec.QueryEndpointRefs))
+	for i, ref := range tr.Spec.QueryEndpointRefs {
+		p, err := prompkg.GetReferencedPrometheus(o.promInfs, tr.Namespace, ref)
+		if err != nil {
+			return nil, fmt.Errorf("queryEndpointRefs[%d]: %w", i, err)
+		}
+
+		ep, err := prompkg.EndpointForReference(p, tr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("queryEndpointRefs[%d]: %w", i, err)
+		}
+
+		queryConfig := yaml.MapSlice{}
+		if ep.TLSConfig != nil {
+			if err := store.AddSafeTLSConfig(ctx, tr.Namespace, ep.TLSConfig); err != nil {
+				return nil, fmt.Errorf("queryEndpointRefs[%d]: %w", i, err)
+			}
+
+			queryConfig = append(queryConfig, yaml.MapItem{
+				Key: "http_config",
+				Value: yaml.MapSlice{
+					{
+						Key: "tls_config",
+						Value: yaml.MapSlice{
+							{Key: "ca_file", Value: path.Join(tlsAssetsDir, store.ForNamespace(tr.Namespace).TLSAsset(ep.TLSConfig.CA))},
+							{Key: "server_name", Value: *ep.TLSConfig.ServerName},
+						},
+					},
+				},
+			})
+		}
+
+		queryConfig = append(queryConfig,
+			yaml.MapItem{Key: "static_configs", Value: ep.Addresses},
+			yaml.MapItem{Key: "scheme", Value: ep.Scheme},
+			yaml.MapItem{Key: "path_prefix", Value: ep.PathPrefix},
+		)
+
+		queryConfigs = append(queryConfigs, queryConfig)
+	}
+
+	return yaml.Marshal(queryConfigs)
+}
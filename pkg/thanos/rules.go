@@ -57,6 +57,11 @@ func (o *Operator) selectPrometheusRules(t *monitoringv1.ThanosRuler, logger *sl
 		thanosVersion = operator.StringValOrDefault(ptr.Deref(t.Spec.Version, ""), operator.DefaultThanosVersion)
 	)
 
+	quotas, err := operator.ListMonitoringQuotas(o.quotaInfs)
+	if err != nil {
+		return rules, err
+	}
+
 	promRuleSelector, err := operator.NewPrometheusRuleSelector(
 		operator.ThanosFormat,
 		thanosVersion,
@@ -66,6 +71,7 @@ func (o *Operator) selectPrometheusRules(t *monitoringv1.ThanosRuler, logger *sl
 		o.newEventRecorder(t),
 		logger,
 		parser.Options{},
+		operator.WithMonitoringQuotas(quotas),
 	)
 	if err != nil {
 		return rules, fmt.Errorf("initializing PrometheusRules failed: %w", err)
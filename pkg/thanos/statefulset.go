@@ -138,7 +138,7 @@ func makeStatefulSet(tr *monitoringv1.ThanosRuler, config Config, ruleConfigMapN
 }
 
 func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfigMapNames []string, tlsSecrets *operator.ShardedSecret) (*appsv1.StatefulSetSpec, error) {
-	if tr.Spec.QueryConfig == nil && len(tr.Spec.QueryEndpoints) < 1 {
+	if tr.Spec.QueryConfig == nil && len(tr.Spec.QueryEndpointRefs) < 1 && len(tr.Spec.QueryEndpoints) < 1 {
 		return nil, errors.New(tr.GetName() + ": thanos ruler requires query config or at least one query endpoint to be specified")
 	}
 
@@ -258,6 +258,19 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 	if tr.Spec.QueryConfig != nil {
 		trVolumes, trVolumeMounts, fullPath = mountSecretKey(trVolumes, trVolumeMounts, tr.Spec.QueryConfig, "query-config")
 		trCLIArgs = append(trCLIArgs, monitoringv1.Argument{Name: "query.config-file", Value: fullPath})
+	} else if len(tr.Spec.QueryEndpointRefs) > 0 {
+		trVolumes, trVolumeMounts, fullPath = mountSecretKey(
+			trVolumes,
+			trVolumeMounts,
+			&corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: rulerConfigSecretName(tr.Name),
+				},
+				Key: queryConfigFile,
+			},
+			"query-endpoints-config",
+		)
+		trCLIArgs = append(trCLIArgs, monitoringv1.Argument{Name: "query.config-file", Value: fullPath})
 	} else if len(tr.Spec.QueryEndpoints) > 0 {
 		for _, endpoint := range tr.Spec.QueryEndpoints {
 			trCLIArgs = append(trCLIArgs, monitoringv1.Argument{Name: "query", Value: endpoint})
@@ -267,6 +280,19 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 	if tr.Spec.AlertManagersConfig != nil {
 		trVolumes, trVolumeMounts, fullPath = mountSecretKey(trVolumes, trVolumeMounts, tr.Spec.AlertManagersConfig, "alertmanager-config")
 		trCLIArgs = append(trCLIArgs, monitoringv1.Argument{Name: "alertmanagers.config-file", Value: fullPath})
+	} else if len(tr.Spec.AlertmanagerRefs) > 0 || len(tr.Spec.AlertmanagerEndpoints) > 0 {
+		trVolumes, trVolumeMounts, fullPath = mountSecretKey(
+			trVolumes,
+			trVolumeMounts,
+			&corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: rulerConfigSecretName(tr.Name),
+				},
+				Key: alertmanagersConfigFile,
+			},
+			"alertmanagers-config",
+		)
+		trCLIArgs = append(trCLIArgs, monitoringv1.Argument{Name: "alertmanagers.config-file", Value: fullPath})
 	} else if len(tr.Spec.AlertManagersURL) > 0 {
 		for _, url := range tr.Spec.AlertManagersURL {
 			trCLIArgs = append(trCLIArgs, monitoringv1.Argument{Name: "alertmanagers.url", Value: url})
@@ -0,0 +1,109 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradecheck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/alertmanager/matcher/parse"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"gopkg.in/yaml.v2"
+)
+
+const alertmanagerConfigFileCompressed = "alertmanager.yaml.gz"
+
+// alertmanagerMatchers holds the matchers of the Alertmanager configuration.
+type alertmanagerMatchers struct {
+	Route        *routeMatchers `yaml:"route"`
+	InhibitRules []struct {
+		SourceMatchers []string `yaml:"source_matchers"`
+		TargetMatchers []string `yaml:"target_matchers"`
+	} `yaml:"inhibit_rules"`
+}
+
+type routeMatchers struct {
+	Matchers []string         `yaml:"matchers"`
+	Routes   []*routeMatchers `yaml:"routes"`
+}
+
+func (r *routeMatchers) walk(fn func(string)) {
+	if r == nil {
+		return
+	}
+
+	for _, m := range r.Matchers {
+		fn(m)
+	}
+
+	for _, child := range r.Routes {
+		child.walk(fn)
+	}
+}
+
+// matchersFromCompressedConfig returns the matchers of the gzipped
+// Alertmanager configuration.
+func matchersFromCompressedConfig(b []byte) ([]string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg alertmanagerMatchers
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	var matchers []string
+	cfg.Route.walk(func(m string) { matchers = append(matchers, m) })
+	for _, ir := range cfg.InhibitRules {
+		matchers = append(matchers, ir.SourceMatchers...)
+		matchers = append(matchers, ir.TargetMatchers...)
+	}
+
+	return matchers, nil
+}
+
+// checkMatcher returns a non-empty string when the input isn't parsed the
+// same way by the classic and the UTF-8 parsers of Alertmanager.
+//
+// In fallback mode (default), Alertmanager logs a warning and uses the
+// classic parser for these inputs. They break only when the UTF-8 strict mode
+// is enabled with the `utf8-strict-mode` feature flag.
+func checkMatcher(input string) string {
+	classic, classicErr := labels.ParseMatchers(input)
+	utf8, utf8Err := parse.Matchers(input)
+
+	switch {
+	case classicErr != nil:
+		// The input is already invalid.
+		return ""
+	case utf8Err != nil:
+		return fmt.Sprintf("warning: matcher %q is incompatible with the UTF-8 parser (%v). Alertmanager falls back to the classic parser in the default mode but rejects the matcher in UTF-8 strict mode", input, utf8Err)
+	}
+
+	if labels.Matchers(classic).String() != utf8.String() {
+		return fmt.Sprintf("warning: matcher %q is parsed as %s by the classic parser but as %s by the UTF-8 parser. Alertmanager uses the classic parser in the default mode but the UTF-8 parser in UTF-8 strict mode", input, labels.Matchers(classic), utf8)
+	}
+
+	return ""
+}
@@ -0,0 +1,154 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradecheck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// versionAttrs are the log attributes which depend on the version of the
+// component and which aren't taken into account to compare warnings.
+var versionAttrs = []string{"current_version", "version", "error", "err"}
+
+// warning is a warning logged by the controllers.
+type warning struct {
+	msg   string
+	attrs []slog.Attr
+}
+
+// key identifies the warning independently of the component's version.
+func (w warning) key() string {
+	var sb strings.Builder
+	sb.WriteString(w.msg)
+	for _, a := range w.attrs {
+		if slices.Contains(versionAttrs, a.Key) {
+			continue
+		}
+		fmt.Fprintf(&sb, " %s=%s", a.Key, a.Value)
+	}
+
+	return sb.String()
+}
+
+// workloadKinds maps the names of the controllers to the kinds of workload.
+var workloadKinds = map[string]string{
+	"prometheus-controller":      "Prometheus",
+	"prometheusagent-controller": "PrometheusAgent",
+	"alertmanager-controller":    "Alertmanager",
+	"thanos-controller":          "ThanosRuler",
+}
+
+// object returns the workload (<kind>/<namespace>/<name>) which logged the
+// warning, if known.
+func (w warning) object() string {
+	var kind, key string
+	for _, a := range w.attrs {
+		switch a.Key {
+		case "component":
+			kind = workloadKinds[a.Value.String()]
+		case "key":
+			key = a.Value.String()
+		}
+	}
+
+	if kind == "" || key == "" {
+		return ""
+	}
+
+	return kind + "/" + key
+}
+
+func (w warning) String() string {
+	var sb strings.Builder
+	sb.WriteString(w.msg)
+	for _, a := range w.attrs {
+		if a.Key == "component" || a.Key == "key" {
+			continue
+		}
+		fmt.Fprintf(&sb, " %s=%q", a.Key, a.Value.String())
+	}
+
+	return sb.String()
+}
+
+// recorder is a slog.Handler recording the warnings.
+type recorder struct {
+	attrs []slog.Attr
+
+	mtx      *sync.Mutex
+	warnings *[]warning
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		mtx:      &sync.Mutex{},
+		warnings: &[]warning{},
+	}
+}
+
+func (r *recorder) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn
+}
+
+func (r *recorder) Handle(_ context.Context, rec slog.Record) error {
+	w := warning{
+		msg:   rec.Message,
+		attrs: slices.Clone(r.attrs),
+	}
+	rec.Attrs(func(a slog.Attr) bool {
+		w.attrs = append(w.attrs, a)
+		return true
+	})
+	slices.SortStableFunc(w.attrs, func(a, b slog.Attr) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	*r.warnings = append(*r.warnings, w)
+
+	return nil
+}
+
+func (r *recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recorder{
+		attrs:    append(slices.Clone(r.attrs), attrs...),
+		mtx:      r.mtx,
+		warnings: r.warnings,
+	}
+}
+
+// WithGroup doesn't support groups since the controllers don't use them.
+func (r *recorder) WithGroup(string) slog.Handler {
+	return r
+}
+
+// Warnings returns the recorded warnings indexed by key.
+func (r *recorder) Warnings() map[string]warning {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	ret := make(map[string]warning, len(*r.warnings))
+	for _, w := range *r.warnings {
+		ret[w.key()] = w
+	}
+
+	return ret
+}
@@ -0,0 +1,420 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upgradecheck reports the impact of upgrading the Prometheus and
+// Alertmanager versions on the resources managed by the operator.
+//
+// The resources are rendered offline twice (with the current and the target
+// versions) and the differences are reported: fields which are dropped,
+// resources which are rejected, rules affected by the metric name validation
+// scheme and Alertmanager matchers parsed differently in UTF-8 mode.
+package upgradecheck
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	prompkg "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	"github.com/prometheus-operator/prometheus-operator/pkg/render"
+)
+
+// Category is the category of a finding.
+type Category string
+
+const (
+	// DroppedFieldCategory means that a field is ignored by the operator with
+	// the target version.
+	DroppedFieldCategory Category = "DroppedField"
+	// RejectedResourceCategory means that a resource is rejected by the
+	// operator with the target version.
+	RejectedResourceCategory Category = "RejectedResource"
+	// NameValidationCategory means that a PrometheusRule is affected by the
+	// change of the metric name validation scheme.
+	NameValidationCategory Category = "NameValidation"
+	// MatcherParsingCategory means that an Alertmanager matcher isn't parsed
+	// the same way in UTF-8 mode. Alertmanager runs in fallback mode by
+	// default: it keeps the classic parsing and logs a warning.
+	MatcherParsingCategory Category = "MatcherParsing"
+)
+
+// utf8MatchersVersion is the first Alertmanager version parsing the matchers
+// with the UTF-8 parser (in fallback mode by default).
+var utf8MatchersVersion = semver.MustParse("0.27.0")
+
+// Finding describes an issue introduced by the version upgrade.
+type Finding struct {
+	Category Category `json:"category"`
+	// Object identifies the workload or the resource (<kind>/<namespace>/<name>).
+	Object  string `json:"object,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Object == "" {
+		return fmt.Sprintf("[%s] %s", f.Category, f.Message)
+	}
+
+	return fmt.Sprintf("[%s] %s: %s", f.Category, f.Object, f.Message)
+}
+
+// Options defines the target versions.
+type Options struct {
+	// PrometheusVersion is the target version for the Prometheus and
+	// PrometheusAgent objects. If empty, the versions are unchanged.
+	PrometheusVersion string
+	// AlertmanagerVersion is the target version for the Alertmanager
+	// objects. If empty, the versions are unchanged.
+	AlertmanagerVersion string
+	// WorkloadDefaults is the name of the PrometheusOperatorDefaults object
+	// merged into the workloads. If empty, no defaults apply.
+	WorkloadDefaults string
+}
+
+// Check returns the findings for upgrading the objects to the target
+// versions.
+//
+// The namespaces configuration must be finalized before calling Check.
+func Check(ctx context.Context, cfg operator.Config, objects []runtime.Object, opts Options) ([]Finding, error) {
+	for _, v := range []string{opts.PrometheusVersion, opts.AlertmanagerVersion} {
+		if v == "" {
+			continue
+		}
+
+		if _, err := semver.ParseTolerant(v); err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", v, err)
+		}
+	}
+
+	var renderOpts []render.Option
+	if opts.WorkloadDefaults != "" {
+		renderOpts = append(renderOpts, render.WithWorkloadDefaults(opts.WorkloadDefaults))
+	}
+
+	currentRecorder := newRecorder()
+	if _, err := render.Render(ctx, slog.New(currentRecorder), cfg, objects, renderOpts...); err != nil {
+		return nil, fmt.Errorf("failed to render the resources with the current versions: %w", err)
+	}
+
+	targetObjects := withTargetVersions(objects, opts)
+	targetRecorder := newRecorder()
+	rendered, err := render.Render(ctx, slog.New(targetRecorder), cfg, targetObjects, renderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the resources with the target versions: %w", err)
+	}
+
+	findings := diffWarnings(currentRecorder.Warnings(), targetRecorder.Warnings())
+
+	nvFindings, err := checkNameValidation(objects, targetObjects)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, nvFindings...)
+
+	mFindings, err := checkMatchers(objects, targetObjects, rendered)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, mFindings...)
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(a.Category, b.Category),
+			cmp.Compare(a.Object, b.Object),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+
+	return findings, nil
+}
+
+// withTargetVersions returns a copy of the objects with the versions of the
+// workloads replaced by the target versions.
+func withTargetVersions(objects []runtime.Object, opts Options) []runtime.Object {
+	ret := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		obj = obj.DeepCopyObject()
+
+		switch o := obj.(type) {
+		case *monitoringv1.Prometheus:
+			if opts.PrometheusVersion != "" {
+				o.Spec.Version = opts.PrometheusVersion
+			}
+		case *monitoringv1alpha1.PrometheusAgent:
+			if opts.PrometheusVersion != "" {
+				o.Spec.Version = opts.PrometheusVersion
+			}
+		case *monitoringv1.Alertmanager:
+			if opts.AlertmanagerVersion != "" {
+				o.Spec.Version = opts.AlertmanagerVersion
+			}
+		}
+
+		ret = append(ret, obj)
+	}
+
+	return ret
+}
+
+// diffWarnings returns the warnings logged with the target versions but not
+// with the current versions.
+func diffWarnings(current, target map[string]warning) []Finding {
+	var findings []Finding
+	for k, w := range target {
+		if _, found := current[k]; found {
+			continue
+		}
+
+		category := DroppedFieldCategory
+		if strings.HasPrefix(w.msg, "skipping ") {
+			category = RejectedResourceCategory
+		}
+
+		findings = append(findings, Finding{
+			Category: category,
+			Object:   w.object(),
+			Message:  w.String(),
+		})
+	}
+
+	return findings
+}
+
+// checkNameValidation reports the PrometheusRules which aren't validated the
+// same way with the current and target metric name validation schemes. Only
+// the PrometheusRules selected by each Prometheus object are checked.
+func checkNameValidation(current, target []runtime.Object) ([]Finding, error) {
+	var (
+		rules      []*monitoringv1.PrometheusRule
+		namespaces = map[string]labels.Set{}
+	)
+	for _, obj := range current {
+		switch o := obj.(type) {
+		case *monitoringv1.PrometheusRule:
+			rules = append(rules, o)
+		case *corev1.Namespace:
+			namespaces[o.Name] = labels.Set(o.Labels)
+		}
+	}
+
+	var findings []Finding
+	for i, obj := range current {
+		p, ok := obj.(*monitoringv1.Prometheus)
+		if !ok {
+			continue
+		}
+
+		currentScheme, err := validationScheme(p)
+		if err != nil {
+			return nil, err
+		}
+
+		targetScheme, err := validationScheme(target[i].(*monitoringv1.Prometheus))
+		if err != nil {
+			return nil, err
+		}
+
+		if currentScheme == targetScheme {
+			continue
+		}
+
+		selected, err := selectRules(p, rules, namespaces)
+		if err != nil {
+			return nil, err
+		}
+
+		parserOptions := prompkg.ParserOptions(p)
+		for _, r := range selected {
+			currentErrs := operator.ValidateRule(*r.Spec.DeepCopy(), currentScheme, parserOptions)
+			targetErrs := operator.ValidateRule(*r.Spec.DeepCopy(), targetScheme, parserOptions)
+
+			var msg string
+			switch {
+			case len(currentErrs) == 0 && len(targetErrs) > 0:
+				msg = fmt.Sprintf("rejected by Prometheus %s/%s with the %s name validation scheme: %v", p.Namespace, p.Name, targetScheme, targetErrs[0])
+			case len(currentErrs) > 0 && len(targetErrs) == 0:
+				msg = fmt.Sprintf("accepted by Prometheus %s/%s with the %s name validation scheme (currently rejected: %v)", p.Namespace, p.Name, targetScheme, currentErrs[0])
+			default:
+				continue
+			}
+
+			findings = append(findings, Finding{
+				Category: NameValidationCategory,
+				Object:   fmt.Sprintf("%s/%s/%s", monitoringv1.PrometheusRuleKind, namespaceOrDefault(r.Namespace), r.Name),
+				Message:  msg,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// selectRules returns the PrometheusRules matched by the rule selector and
+// the rule namespace selector of the Prometheus object.
+//
+// The namespaces which aren't declared are assumed to have only the
+// `kubernetes.io/metadata.name` label.
+func selectRules(p *monitoringv1.Prometheus, rules []*monitoringv1.PrometheusRule, namespaces map[string]labels.Set) ([]*monitoringv1.PrometheusRule, error) {
+	if p.Spec.RuleSelector == nil {
+		return nil, nil
+	}
+
+	ruleSelector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleSelector)
+	if err != nil {
+		return nil, fmt.Errorf("Prometheus %s/%s: invalid rule selector: %w", p.Namespace, p.Name, err)
+	}
+
+	var nsSelector labels.Selector
+	if p.Spec.RuleNamespaceSelector != nil {
+		nsSelector, err = metav1.LabelSelectorAsSelector(p.Spec.RuleNamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("Prometheus %s/%s: invalid rule namespace selector: %w", p.Namespace, p.Name, err)
+		}
+	}
+
+	var selected []*monitoringv1.PrometheusRule
+	for _, r := range rules {
+		ns := namespaceOrDefault(r.Namespace)
+
+		if nsSelector == nil {
+			// Only the namespace of the Prometheus object is selected.
+			if ns != namespaceOrDefault(p.Namespace) {
+				continue
+			}
+		} else {
+			nsLabels, found := namespaces[ns]
+			if !found {
+				nsLabels = labels.Set{corev1.LabelMetadataName: ns}
+			}
+
+			if !nsSelector.Matches(nsLabels) {
+				continue
+			}
+		}
+
+		if !ruleSelector.Matches(labels.Set(r.Labels)) {
+			continue
+		}
+
+		selected = append(selected, r)
+	}
+
+	return selected, nil
+}
+
+func namespaceOrDefault(ns string) string {
+	if ns == "" {
+		return metav1.NamespaceDefault
+	}
+
+	return ns
+}
+
+func validationScheme(p *monitoringv1.Prometheus) (model.ValidationScheme, error) {
+	version, err := semver.ParseTolerant(operator.StringValOrDefault(p.Spec.Version, operator.DefaultPrometheusVersion))
+	if err != nil {
+		return model.UnsetValidation, fmt.Errorf("Prometheus %s/%s: failed to parse the version: %w", p.Namespace, p.Name, err)
+	}
+
+	return operator.ValidationSchemeForPrometheus(version), nil
+}
+
+// checkMatchers reports the matchers of the generated Alertmanager
+// configurations which aren't parsed the same way in UTF-8 mode. Only the
+// Alertmanager objects upgraded from a version parsing the matchers with the
+// classic parser are checked.
+func checkMatchers(current, target []runtime.Object, rendered []runtime.Object) ([]Finding, error) {
+	upgraded := map[string]bool{}
+	for i, obj := range target {
+		am, ok := obj.(*monitoringv1.Alertmanager)
+		if !ok {
+			continue
+		}
+
+		currentVersion, err := alertmanagerVersion(current[i].(*monitoringv1.Alertmanager))
+		if err != nil {
+			return nil, err
+		}
+
+		targetVersion, err := alertmanagerVersion(am)
+		if err != nil {
+			return nil, err
+		}
+
+		upgraded[fmt.Sprintf("%s/%s", namespaceOrDefault(am.Namespace), am.Name)] = currentVersion.LT(utf8MatchersVersion) && targetVersion.GTE(utf8MatchersVersion)
+	}
+
+	var findings []Finding
+	for _, obj := range rendered {
+		s, ok := obj.(*corev1.Secret)
+		if !ok {
+			continue
+		}
+
+		b, found := s.Data[alertmanagerConfigFileCompressed]
+		if !found {
+			continue
+		}
+
+		var name string
+		for _, ref := range s.OwnerReferences {
+			if ref.Kind == monitoringv1.AlertmanagersKind {
+				name = ref.Name
+			}
+		}
+
+		if !upgraded[fmt.Sprintf("%s/%s", s.Namespace, name)] {
+			continue
+		}
+
+		matchers, err := matchersFromCompressedConfig(b)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s: failed to parse the Alertmanager configuration: %w", s.Namespace, s.Name, err)
+		}
+
+		for _, m := range matchers {
+			if msg := checkMatcher(m); msg != "" {
+				findings = append(findings, Finding{
+					Category: MatcherParsingCategory,
+					Object:   fmt.Sprintf("%s/%s/%s", monitoringv1.AlertmanagersKind, s.Namespace, name),
+					Message:  msg,
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func alertmanagerVersion(am *monitoringv1.Alertmanager) (semver.Version, error) {
+	version, err := semver.ParseTolerant(operator.StringValOrDefault(am.Spec.Version, operator.DefaultAlertmanagerVersion))
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("Alertmanager %s/%s: failed to parse the version: %w", am.Namespace, am.Name, err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,240 @@
+// Copyright The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradecheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+const alertmanagerConfig = `
+route:
+  receiver: "null"
+  routes:
+  - receiver: "null"
+    matchers:
+    - severity="critical"
+    - team=sre ops
+receivers:
+- name: "null"
+inhibit_rules:
+- source_matchers:
+  - alertname=Watchdog
+  target_matchers:
+  - a=b=c
+`
+
+func TestCheckMatcher(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		ok    bool
+	}{
+		{input: `severity="critical"`, ok: true},
+		{input: `{severity=~"critical|warning"}`, ok: true},
+		{input: `severity=critical`, ok: true},
+		{input: `team=sre ops`},
+		{input: `a=b=c`},
+		{input: `foo=bar}`},
+		// Invalid for both parsers.
+		{input: `=bar`, ok: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			if tc.ok {
+				require.Empty(t, checkMatcher(tc.input))
+				return
+			}
+
+			require.NotEmpty(t, checkMatcher(tc.input))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cfg := operator.DefaultConfig("10m", "50Mi")
+	cfg.ReloaderConfig.Image = operator.DefaultPrometheusConfigReloaderImage
+	cfg.AlertmanagerDefaultBaseImage = operator.DefaultAlertmanagerBaseImage
+	cfg.PrometheusDefaultBaseImage = operator.DefaultPrometheusBaseImage
+	require.NoError(t, cfg.Namespaces.Finalize())
+
+	objects := []runtime.Object{
+		&monitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+			Spec: monitoringv1.PrometheusSpec{
+				CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
+					Version:                         "v2.55.0",
+					ServiceMonitorSelector:          &metav1.LabelSelector{},
+					ServiceMonitorNamespaceSelector: &metav1.LabelSelector{},
+				},
+				RuleSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "prometheus",
+						Operator: metav1.LabelSelectorOpDoesNotExist,
+					}},
+				},
+				RuleNamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "system",
+						Operator: metav1.LabelSelectorOpDoesNotExist,
+					}},
+				},
+			},
+		},
+		&monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: monitoringv1.ServiceMonitorSpec{
+				Endpoints: []monitoringv1.Endpoint{{
+					Port: "web",
+					RelabelConfigs: []monitoringv1.RelabelConfig{{
+						Action:      "replace",
+						TargetLabel: "service.name",
+					}},
+				}},
+			},
+		},
+		&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "utf8", Namespace: "default"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{
+					Name: "utf8",
+					Rules: []monitoringv1.Rule{{
+						Record: "service.requests:rate5m",
+						Expr:   intstr.FromString(`rate(requests_total[5m])`),
+					}},
+				}},
+			},
+		},
+		&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{
+					Name: "legacy",
+					Rules: []monitoringv1.Rule{{
+						Record: "requests:rate5m",
+						Expr:   intstr.FromString(`rate(requests_total[5m])`),
+					}},
+				}},
+			},
+		},
+		// Not selected by the Prometheus object (rule selector).
+		&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "unselected",
+				Namespace: "default",
+				Labels:    map[string]string{"prometheus": "other"},
+			},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{
+					Name: "unselected",
+					Rules: []monitoringv1.Rule{{
+						Record: "service.errors:rate5m",
+						Expr:   intstr.FromString(`rate(errors_total[5m])`),
+					}},
+				}},
+			},
+		},
+		// Not selected by the Prometheus object (rule namespace selector).
+		&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "unselected", Namespace: "kube-system"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{
+					Name: "unselected",
+					Rules: []monitoringv1.Rule{{
+						Record: "service.errors:rate5m",
+						Expr:   intstr.FromString(`rate(errors_total[5m])`),
+					}},
+				}},
+			},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "kube-system",
+				Labels: map[string]string{"system": "true"},
+			},
+		},
+		&monitoringv1.Alertmanager{
+			ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+			Spec: monitoringv1.AlertmanagerSpec{
+				Version: "v0.26.0",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-main", Namespace: "monitoring"},
+			Data: map[string][]byte{
+				"alertmanager.yaml": []byte(alertmanagerConfig),
+			},
+		},
+	}
+
+	// No change.
+	findings, err := Check(context.Background(), cfg, objects, Options{})
+	require.NoError(t, err)
+	require.Empty(t, findings)
+
+	findings, err = Check(context.Background(), cfg, objects, Options{
+		PrometheusVersion:   "v3.5.0",
+		AlertmanagerVersion: "v0.28.0",
+	})
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	require.Contains(t, got, `[MatcherParsing] Alertmanager/monitoring/main: warning: matcher "team=sre ops" is incompatible with the UTF-8 parser (9:12: unexpected ops: expected a comma or close brace). Alertmanager falls back to the classic parser in the default mode but rejects the matcher in UTF-8 strict mode`)
+	require.Contains(t, got, `[MatcherParsing] Alertmanager/monitoring/main: warning: matcher "a=b=c" is incompatible with the UTF-8 parser (3:4: unexpected =: expected a comma or close brace). Alertmanager falls back to the classic parser in the default mode but rejects the matcher in UTF-8 strict mode`)
+	require.Contains(t, got, `[NameValidation] PrometheusRule/default/utf8: accepted by Prometheus monitoring/main with the utf8 name validation scheme (currently rejected: 5:13: group "utf8", rule 1, "service.requests:rate5m": invalid recording rule name: service.requests:rate5m)`)
+	require.Len(t, findings, 3)
+
+	// Downgrading Prometheus rejects the ServiceMonitor and the
+	// PrometheusRule which are only valid in UTF-8 mode.
+	objects[0].(*monitoringv1.Prometheus).Spec.Version = "v3.5.0"
+	findings, err = Check(context.Background(), cfg, objects, Options{
+		PrometheusVersion: "v2.55.0",
+	})
+	require.NoError(t, err)
+
+	got = got[:0]
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	require.Equal(t, []string{
+		`[NameValidation] PrometheusRule/default/utf8: rejected by Prometheus monitoring/main with the legacy name validation scheme: 5:13: group "utf8", rule 1, "service.requests:rate5m": invalid recording rule name: service.requests:rate5m`,
+		`[RejectedResource] Prometheus/monitoring/main: skipping object error="endpoints[0]: relabelConfigs: [0]: \"service.name\" is invalid 'target_label' for replace action" kind="ServiceMonitor" object="default/app"`,
+		`[RejectedResource] Prometheus/monitoring/main: skipping prometheusrule error="invalid rule" namespace="default" prometheusrule="utf8"`,
+	}, got)
+
+	// Upgrading Alertmanager from a version already parsing the matchers in
+	// fallback mode reports nothing.
+	objects[0].(*monitoringv1.Prometheus).Spec.Version = "v2.55.0"
+	for _, obj := range objects {
+		if am, ok := obj.(*monitoringv1.Alertmanager); ok {
+			am.Spec.Version = "v0.27.0"
+		}
+	}
+	findings, err = Check(context.Background(), cfg, objects, Options{
+		AlertmanagerVersion: "v0.28.0",
+	})
+	require.NoError(t, err)
+	require.Empty(t, findings)
+}
@@ -95,10 +95,11 @@ func (c Config) GetMountParameters() (monitoringv1.Argument, []corev1.Volume, []
 }
 
 // CreateOrUpdateWebConfigSecret create or update a Kubernetes secret with the
-// data for the web config file.
+// data for the web config file. The overlays are applied to the secret before
+// it is written.
 // The format of the web config file is available in the official prometheus documentation:
 // https://prometheus.io/docs/prometheus/latest/configuration/https/#https-and-authentication
-func (c Config) CreateOrUpdateWebConfigSecret(ctx context.Context, secretClient typedcorev1.SecretInterface, s *corev1.Secret) error {
+func (c Config) CreateOrUpdateWebConfigSecret(ctx context.Context, secretClient typedcorev1.SecretInterface, s *corev1.Secret, overlays []monitoringv1.Overlay) error {
 	data, err := c.generateConfigFileContents()
 	if err != nil {
 		return err
@@ -109,6 +110,10 @@ func (c Config) CreateOrUpdateWebConfigSecret(ctx context.Context, secretClient
 		configFile: data,
 	}
 
+	if err := k8s.ApplyOverlays(overlays, s); err != nil {
+		return err
+	}
+
 	return k8s.CreateOrUpdateSecret(ctx, secretClient, s)
 }
 
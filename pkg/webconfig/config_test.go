@@ -186,9 +186,9 @@ func TestCreateOrUpdateWebConfigSecret(t *testing.T) {
 
 			var (
 				s            = corev1.Secret{}
-				secretClient = fake.NewSimpleClientset().CoreV1().Secrets("default")
+				secretClient = fake.NewClientset().CoreV1().Secrets("default")
 			)
-			err = config.CreateOrUpdateWebConfigSecret(context.Background(), secretClient, &s)
+			err = config.CreateOrUpdateWebConfigSecret(context.Background(), secretClient, &s, nil)
 			require.NoError(t, err)
 
 			secret, err := secretClient.Get(context.Background(), secretName, metav1.GetOptions{})